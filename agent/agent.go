@@ -0,0 +1,301 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package agent implements the containerlab agent serving the labs of a Linux lab server
+// to the containerlab clients running on the hosts which can't run the labs, e.g. macOS or Windows laptops.
+//
+// The agent exposes the gRPC API running the containerlab commands sent by the clients, streaming their output back,
+// and proxying the TCP connections of the clients, e.g. SSH or console sessions, to the lab nodes.
+package agent
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/runner"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultPort is the port the agent listens on by default
+const DefaultPort = 50080
+
+// commands are the containerlab commands the clients can run on the agent,
+// the value is true for the commands changing the lab
+var commands = map[string]bool{
+	"deploy":  true,
+	"destroy": true,
+	"save":    true,
+	"inspect": false,
+	"list":    false,
+	"exec":    false,
+	"version": false,
+}
+
+// Server serves the agent API
+type Server struct {
+	// bearer token the clients authenticate with
+	Token string
+	// directory the topology files of the clients are stored in,
+	// the lab directories are created next to them
+	WorkDir string
+	// Runner runs the containerlab commands of the clients
+	Runner *runner.Runner
+}
+
+// GRPCServer returns the gRPC server of the agent API created with the options, e.g. the TLS credentials
+func (s *Server) GRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	g := grpc.NewServer(append(opts, grpc.StreamInterceptor(s.auth))...)
+	g.RegisterService(&serviceDesc, s)
+	return g
+}
+
+// auth rejects the calls without the bearer token of the agent passed in the authorization metadata
+func (s *Server) auth(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, h grpc.StreamHandler) error {
+	md, _ := metadata.FromIncomingContext(ss.Context())
+	var token string
+	if v := md.Get("authorization"); len(v) > 0 {
+		token = strings.TrimPrefix(v[0], "Bearer ")
+	}
+	if s.Token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.Token)) != 1 {
+		return status.Error(codes.Unauthenticated, "invalid or missing bearer token")
+	}
+	return h(srv, ss)
+}
+
+// run runs the containerlab command of the request and streams its output to the client,
+// the exit code of the command is sent in the last message
+func (s *Server) run(stream grpc.ServerStream) error {
+	req := new(RunRequest)
+	if err := stream.RecvMsg(req); err != nil {
+		return err
+	}
+	cmd, err := s.command(req)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	out := &streamWriter{stream: stream}
+	cmd.Stdout, cmd.Stderr = out, out
+	rc, err := s.Runner.Run(stream.Context(), cmd)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return stream.SendMsg(&RunOutput{ExitCode: &rc})
+}
+
+// command returns the command of the request, the topology of the request is stored in the work dir
+// and the command is run with the topology path of the agent host
+func (s *Server) command(req *RunRequest) (*runner.Command, error) {
+	if len(req.Args) == 0 {
+		return nil, fmt.Errorf("no command to run")
+	}
+	labOp, ok := commands[req.Args[0]]
+	if !ok {
+		allowed := make([]string, 0, len(commands))
+		for c := range commands {
+			allowed = append(allowed, c)
+		}
+		sort.Strings(allowed)
+		return nil, fmt.Errorf("command %q is not allowed, the agent runs the commands: %s", req.Args[0], strings.Join(allowed, ", "))
+	}
+	for _, a := range req.Args {
+		if topoFlag(a) {
+			return nil, fmt.Errorf("the topology file is sent with the request, flag %s is not allowed", a)
+		}
+	}
+	if err := os.MkdirAll(s.WorkDir, 0755); err != nil {
+		return nil, err
+	}
+	cmd := &runner.Command{Args: req.Args, Dir: s.WorkDir}
+	if labOp {
+		// the lab commands without a topology, e.g. destroy --all, run one at a time
+		cmd.Lab = s.WorkDir
+	}
+	if req.TopoName == "" {
+		return cmd, nil
+	}
+	// only the file name is kept, the topology files are stored in the work dir
+	name := filepath.Base(req.TopoName)
+	if name == "." || name == ".." || name == string(filepath.Separator) {
+		return nil, fmt.Errorf("invalid topology file name %q", req.TopoName)
+	}
+	p := filepath.Join(s.WorkDir, name)
+	if labOp {
+		cmd.Lab = p
+	}
+	if err := os.WriteFile(p, req.Topo, 0644); err != nil {
+		return nil, fmt.Errorf("failed to store topology file: %v", err)
+	}
+	cmd.Args = append(append([]string{}, req.Args...), "--topo", p)
+	return cmd, nil
+}
+
+// topoFlag returns true for the argument setting the topology file,
+// i.e. the --topo flag or the -t shorthand, also when it is combined with other shorthands
+func topoFlag(a string) bool {
+	if strings.HasPrefix(a, "--") {
+		return a == "--topo" || strings.HasPrefix(a, "--topo=")
+	}
+	return strings.HasPrefix(a, "-") && strings.Contains(a, "t")
+}
+
+// labNode is the container of a lab node, as reported by the inspect command in the json format
+type labNode struct {
+	Name        string `json:"name"`
+	IPv4Address string `json:"ipv4_address"`
+	IPv6Address string `json:"ipv6_address"`
+}
+
+// labNodes returns the containers of the labs running on the agent host
+func (s *Server) labNodes(stream grpc.ServerStream) ([]*labNode, error) {
+	var out, stderr bytes.Buffer
+	rc, err := s.Runner.Run(stream.Context(), &runner.Command{
+		Args:   []string{"inspect", "--all", "--format", "json"},
+		Stdout: &out,
+		Stderr: &stderr,
+	})
+	if err == nil && rc != 0 {
+		err = fmt.Errorf("inspect exited with code %d: %s", rc, strings.TrimSpace(stderr.String()))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list lab nodes: %v", err)
+	}
+	var nodes []*labNode
+	// no labs are reported in the log only
+	if len(bytes.TrimSpace(out.Bytes())) == 0 {
+		return nodes, nil
+	}
+	if err := json.Unmarshal(out.Bytes(), &nodes); err != nil {
+		return nil, fmt.Errorf("failed to decode inspect output: %v", err)
+	}
+	return nodes, nil
+}
+
+// nodeAddr returns the address the connection to addr is proxied to,
+// the host of addr is required to be the container name or a management address of a lab node
+func nodeAddr(nodes []*labNode, addr string) (string, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", fmt.Errorf("invalid address %q: %v", addr, err)
+	}
+	for _, n := range nodes {
+		ips := make([]net.IP, 0, 2)
+		for _, a := range []string{n.IPv4Address, n.IPv6Address} {
+			if ip, _, err := net.ParseCIDR(a); err == nil {
+				ips = append(ips, ip)
+			}
+		}
+		if len(ips) == 0 {
+			continue
+		}
+		if host == n.Name {
+			return net.JoinHostPort(ips[0].String(), port), nil
+		}
+		for _, ip := range ips {
+			if ip.Equal(net.ParseIP(host)) {
+				return net.JoinHostPort(ip.String(), port), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("%s is not a management address of a lab node", host)
+}
+
+// proxy connects the client stream to the lab node address of the first message
+// and copies the data between them until the node closes the connection
+func (s *Server) proxy(stream grpc.ServerStream) error {
+	req := new(ProxyData)
+	if err := stream.RecvMsg(req); err != nil {
+		return err
+	}
+	nodes, err := s.labNodes(stream)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	addr, err := nodeAddr(nodes, req.Addr)
+	if err != nil {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	backend, err := net.DialTimeout("tcp", addr, 10*time.Second)
+	if err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	defer backend.Close()
+	log.Debugf("Proxying connection to %s", addr)
+
+	go func() {
+		for {
+			m := new(ProxyData)
+			if err := stream.RecvMsg(m); err != nil {
+				// the end of the client data is passed on to the node, the connection is closed when the client went away
+				if err == io.EOF {
+					closeWrite(backend)
+				} else {
+					backend.Close()
+				}
+				return
+			}
+			if _, err := backend.Write(m.Data); err != nil {
+				return
+			}
+		}
+	}()
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := backend.Read(buf)
+		if n > 0 {
+			if err := stream.SendMsg(&ProxyData{Data: buf[:n]}); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return status.Error(codes.Unavailable, err.Error())
+		}
+	}
+}
+
+// closeWrite closes the write half of the connection or the whole connection if it can't be half closed
+func closeWrite(c net.Conn) {
+	if cw, ok := c.(interface{ CloseWrite() error }); ok {
+		_ = cw.CloseWrite()
+		return
+	}
+	_ = c.Close()
+}
+
+// streamWriter sends the command output to the client as it is produced.
+// The output is dropped once the client has gone away, so that the command keeps running rather than failing on the closed output
+type streamWriter struct {
+	mu     sync.Mutex
+	stream grpc.ServerStream
+	failed bool
+}
+
+func (w *streamWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.failed {
+		return len(p), nil
+	}
+	if err := w.stream.SendMsg(&RunOutput{Data: p}); err != nil {
+		log.Debugf("client stopped receiving the command output: %v", err)
+		w.failed = true
+	}
+	return len(p), nil
+}
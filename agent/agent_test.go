@@ -0,0 +1,254 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/runner"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewRunRequest(t *testing.T) {
+	dir := t.TempDir()
+	topo := filepath.Join(dir, "lab.clab.yml")
+	if err := os.WriteFile(topo, []byte("name: lab\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		args    []string
+		want    *RunRequest
+		wantErr bool
+	}{
+		"short-flag": {
+			args: []string{"deploy", "-t", topo, "--reconfigure"},
+			want: &RunRequest{Args: []string{"deploy", "--reconfigure"}, TopoName: "lab.clab.yml", Topo: []byte("name: lab\n")},
+		},
+		"long-flag": {
+			args: []string{"destroy", "--topo=" + topo},
+			want: &RunRequest{Args: []string{"destroy"}, TopoName: "lab.clab.yml", Topo: []byte("name: lab\n")},
+		},
+		"no-topology": {
+			args: []string{"inspect", "--all"},
+			want: &RunRequest{Args: []string{"inspect", "--all"}},
+		},
+		"missing-topology": {
+			args:    []string{"deploy", "-t", filepath.Join(dir, "missing.yml")},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewRunRequest(tc.args)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got error: %v, want error: %v", err, tc.wantErr)
+			}
+			if !cmp.Equal(got, tc.want) {
+				t.Errorf("got: %+v, want: %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCommand(t *testing.T) {
+	workDir := t.TempDir()
+	topo := filepath.Join(workDir, "lab.clab.yml")
+	tests := map[string]struct {
+		req     *RunRequest
+		want    *runner.Command
+		wantErr bool
+	}{
+		"deploy": {
+			req:  &RunRequest{Args: []string{"deploy", "--reconfigure"}, TopoName: "../lab.clab.yml", Topo: []byte("name: lab\n")},
+			want: &runner.Command{Args: []string{"deploy", "--reconfigure", "--topo", topo}, Dir: workDir, Lab: topo},
+		},
+		"destroy-all": {
+			req:  &RunRequest{Args: []string{"destroy", "--all"}},
+			want: &runner.Command{Args: []string{"destroy", "--all"}, Dir: workDir, Lab: workDir},
+		},
+		"inspect": {
+			req:  &RunRequest{Args: []string{"inspect", "--all"}},
+			want: &runner.Command{Args: []string{"inspect", "--all"}, Dir: workDir},
+		},
+		"no-command": {
+			req:     &RunRequest{},
+			wantErr: true,
+		},
+		"not-allowed-command": {
+			req:     &RunRequest{Args: []string{"tools", "veth", "create"}},
+			wantErr: true,
+		},
+		"global-flag-before-command": {
+			req:     &RunRequest{Args: []string{"--runtime", "docker", "deploy"}},
+			wantErr: true,
+		},
+		"topo-flag": {
+			req:     &RunRequest{Args: []string{"deploy", "--topo", "/etc/lab.clab.yml"}},
+			wantErr: true,
+		},
+		"topo-shorthand": {
+			req:     &RunRequest{Args: []string{"destroy", "-dt", "/etc/lab.clab.yml"}},
+			wantErr: true,
+		},
+	}
+	s := &Server{WorkDir: workDir}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := s.command(tc.req)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got error: %v, want error: %v", err, tc.wantErr)
+			}
+			if !cmp.Equal(got, tc.want) {
+				t.Errorf("got: %+v, want: %+v", got, tc.want)
+			}
+		})
+	}
+	if b, err := os.ReadFile(topo); err != nil || string(b) != "name: lab\n" {
+		t.Errorf("topology file is not stored in the work dir: %v", err)
+	}
+}
+
+func TestNodeAddr(t *testing.T) {
+	nodes := []*labNode{
+		{Name: "clab-lab-srl1", IPv4Address: "172.20.20.2/24", IPv6Address: "2001:172:20:20::2/64"},
+		{Name: "clab-lab-srl2", IPv4Address: "N/A", IPv6Address: "2001:172:20:20::3/64"},
+		{Name: "clab-lab-host", IPv4Address: "N/A", IPv6Address: "N/A"},
+	}
+	tests := map[string]struct {
+		addr    string
+		want    string
+		wantErr bool
+	}{
+		"container-name":   {addr: "clab-lab-srl1:22", want: "172.20.20.2:22"},
+		"ipv4-address":     {addr: "172.20.20.2:57400", want: "172.20.20.2:57400"},
+		"ipv6-address":     {addr: "[2001:172:20:20:0::3]:22", want: "[2001:172:20:20::3]:22"},
+		"ipv6-only-node":   {addr: "clab-lab-srl2:22", want: "[2001:172:20:20::3]:22"},
+		"node-without-ip":  {addr: "clab-lab-host:22", wantErr: true},
+		"other-host":       {addr: "10.0.0.1:22", wantErr: true},
+		"agent-host":       {addr: "localhost:22", wantErr: true},
+		"no-port":          {addr: "clab-lab-srl1", wantErr: true},
+		"unknown-hostname": {addr: "lab-server:22", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := nodeAddr(nodes, tc.addr)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got error: %v, want error: %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+// startServer starts the agent API with the containerlab binary and returns the agent URL
+func startServer(t *testing.T, bin string) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{Token: "secret", WorkDir: t.TempDir(), Runner: &runner.Runner{Binary: bin}}
+	g := s.GRPCServer()
+	go func() { _ = g.Serve(l) }()
+	t.Cleanup(g.Stop)
+	return "http://" + l.Addr().String()
+}
+
+func TestRun(t *testing.T) {
+	// echo prints the arguments the agent runs containerlab with
+	url := startServer(t, "echo")
+
+	topo := filepath.Join(t.TempDir(), "lab.clab.yml")
+	if err := os.WriteFile(topo, []byte("name: lab\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	c := &Client{URL: url, Token: "secret"}
+	rc, err := c.Run([]string{"deploy", "-t", topo}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), "deploy --topo ") || !strings.HasSuffix(out.String(), "lab.clab.yml\n") || rc != 0 {
+		t.Errorf("got rc %d, output %q, want rc 0, deploy of the stored topology", rc, out.String())
+	}
+
+	if _, err := c.Run([]string{"tools", "veth", "create"}, io.Discard); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("got error %v, want invalid argument", err)
+	}
+
+	c.Token = "wrong"
+	if _, err := c.Run([]string{"inspect"}, io.Discard); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("got error %v, want unauthenticated", err)
+	}
+}
+
+func TestRunExitCode(t *testing.T) {
+	c := &Client{URL: startServer(t, "false"), Token: "secret"}
+	rc, err := c.Run([]string{"deploy"}, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc != 1 {
+		t.Errorf("got rc %d, want 1", rc)
+	}
+}
+
+func TestProxy(t *testing.T) {
+	// the backend stands for the SSH server of a lab node
+	backend, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+	go func() {
+		conn, err := backend.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		_, _ = conn.Write([]byte("echo " + line))
+	}()
+
+	// the inspect command of the agent reports the lab node with the backend address
+	bin := filepath.Join(t.TempDir(), "containerlab")
+	script := `#!/bin/sh
+echo '[{"name": "clab-lab-n1", "ipv4_address": "127.0.0.1/8", "ipv6_address": "N/A"}]'
+`
+	if err := os.WriteFile(bin, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{URL: startServer(t, bin), Token: "secret"}
+
+	_, port, _ := net.SplitHostPort(backend.Addr().String())
+	var out bytes.Buffer
+	rw := struct {
+		io.Reader
+		io.Writer
+	}{strings.NewReader("hello\n"), &out}
+	if err := c.Proxy(net.JoinHostPort("clab-lab-n1", port), rw); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "echo hello\n" {
+		t.Errorf("got %q, want %q", out.String(), "echo hello\n")
+	}
+
+	if err := c.Proxy("10.0.0.1:22", rw); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("got error %v, want permission denied", err)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+// clab-remote is the containerlab agent client for the hosts containerlab can't run on, e.g. macOS or Windows.
+// It is the standalone build of the 'containerlab remote' command.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/srl-labs/containerlab/agent"
+)
+
+func main() {
+	agentURL := flag.String("agent", os.Getenv("CLAB_AGENT"), "URL of the containerlab agent, e.g. https://lab-server:50080")
+	insecure := flag.Bool("insecure", false, "skip the verification of the agent TLS certificate")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] <command> [command flags]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *agentURL == "" || flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	c := &agent.Client{
+		URL:      *agentURL,
+		Token:    os.Getenv("CLAB_AGENT_TOKEN"),
+		Insecure: *insecure,
+	}
+	args := flag.Args()
+	if args[0] == "proxy" {
+		if len(args) != 2 {
+			fatal(fmt.Errorf("provide the address to proxy to as host:port"))
+		}
+		if err := c.Proxy(args[1], struct {
+			io.Reader
+			io.Writer
+		}{os.Stdin, os.Stdout}); err != nil {
+			fatal(err)
+		}
+		return
+	}
+	rc, err := c.Run(args, os.Stdout)
+	if err != nil {
+		fatal(err)
+	}
+	os.Exit(rc)
+}
+
+func fatal(err error) {
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	os.Exit(1)
+}
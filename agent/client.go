@@ -0,0 +1,191 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package agent
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+)
+
+// Client drives the containerlab agent
+type Client struct {
+	// agent URL, e.g. https://lab-server:50080
+	URL string
+	// bearer token of the agent
+	Token string
+	// skip the verification of the agent TLS certificate
+	Insecure bool
+}
+
+// agentURL returns the parsed agent URL with the default agent port set when the URL has no port
+func (c *Client) agentURL() (*url.URL, error) {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported agent URL scheme %q", u.Scheme)
+	}
+	if u.Port() == "" {
+		u.Host = net.JoinHostPort(u.Hostname(), strconv.Itoa(DefaultPort))
+	}
+	return u, nil
+}
+
+// dial returns the connection to the agent, the calls made with ctx are authenticated with the token of the client.
+// The https agent URL makes the connection use TLS
+func (c *Client) dial(ctx context.Context) (*grpc.ClientConn, context.Context, error) {
+	u, err := c.agentURL()
+	if err != nil {
+		return nil, nil, err
+	}
+	creds := grpc.WithInsecure()
+	if u.Scheme == "https" {
+		creds = grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{InsecureSkipVerify: c.Insecure})) // skipcq: GSC-G402
+	}
+	conn, err := grpc.DialContext(ctx, u.Host, creds, grpc.WithDefaultCallOptions(grpc.CallContentSubtype(codec)))
+	if err != nil {
+		return nil, nil, err
+	}
+	return conn, metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+c.Token), nil
+}
+
+// Run runs the containerlab command with the args on the agent, streaming its output to out.
+// The topology file set with the --topo|-t flag of the args is sent to the agent.
+// Run returns the exit code of the command
+func (c *Client) Run(args []string, out io.Writer) (int, error) {
+	req, err := NewRunRequest(args)
+	if err != nil {
+		return 0, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	conn, ctx, err := c.dial(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	stream, err := conn.NewStream(ctx, &serviceDesc.Streams[0], runMethod)
+	if err != nil {
+		return 0, err
+	}
+	if err := stream.SendMsg(req); err != nil {
+		return 0, err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return 0, err
+	}
+	for {
+		m := new(RunOutput)
+		if err := stream.RecvMsg(m); err != nil {
+			if err == io.EOF {
+				return 0, errors.New("agent didn't report the exit code of the command")
+			}
+			return 0, err
+		}
+		if _, err := out.Write(m.Data); err != nil {
+			return 0, err
+		}
+		if m.ExitCode != nil {
+			return *m.ExitCode, nil
+		}
+	}
+}
+
+// NewRunRequest returns the run request of the command args,
+// the topology file of the --topo|-t flag is read and removed from the args
+func NewRunRequest(args []string) (*RunRequest, error) {
+	req := &RunRequest{}
+	var topo string
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case a == "-t" || a == "--topo":
+			if i+1 == len(args) {
+				return nil, fmt.Errorf("flag %s needs an argument", a)
+			}
+			topo = args[i+1]
+			i++
+		case strings.HasPrefix(a, "--topo="):
+			topo = strings.TrimPrefix(a, "--topo=")
+		default:
+			req.Args = append(req.Args, a)
+		}
+	}
+	if topo == "" {
+		return req, nil
+	}
+	b, err := os.ReadFile(topo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read topology file: %v", err)
+	}
+	req.TopoName = filepath.Base(topo)
+	req.Topo = b
+	return req, nil
+}
+
+// Proxy connects rw to the management address of a lab node running on the agent host, e.g. its SSH server,
+// and copies the data between them until the proxied connection is closed.
+// The host of addr is the container name or a management address of the node
+func (c *Client) Proxy(addr string, rw io.ReadWriter) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	conn, ctx, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	stream, err := conn.NewStream(ctx, &serviceDesc.Streams[1], proxyMethod)
+	if err != nil {
+		return err
+	}
+	if err := stream.SendMsg(&ProxyData{Addr: addr}); err != nil {
+		return err
+	}
+	// the end of the local input is passed on to the proxied connection
+	go func() {
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := rw.Read(buf)
+			if n > 0 {
+				if err := stream.SendMsg(&ProxyData{Data: buf[:n]}); err != nil {
+					return
+				}
+			}
+			if err != nil {
+				_ = stream.CloseSend()
+				return
+			}
+		}
+	}()
+	// the session ends when the agent closes the stream
+	for {
+		m := new(ProxyData)
+		if err := stream.RecvMsg(m); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if _, err := rw.Write(m.Data); err != nil {
+			return err
+		}
+	}
+}
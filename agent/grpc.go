@@ -0,0 +1,69 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package agent
+
+import (
+	"github.com/srl-labs/containerlab/api"
+	"google.golang.org/grpc"
+)
+
+const (
+	// Service is the name of the gRPC service of the agent
+	Service = "containerlab.agent.v1.Agent"
+	// runMethod streams the output of the containerlab command run by the agent
+	runMethod = "/" + Service + "/Run"
+	// proxyMethod streams the data of the TCP connection proxied to a lab node
+	proxyMethod = "/" + Service + "/Proxy"
+)
+
+// codec is the name of the codec the agent messages are marshaled with,
+// the JSON codec of the API server is used, so that the service is used without the generated protobuf code
+var codec = api.JSONCodec{}.Name()
+
+// RunRequest is the containerlab command the client asks the agent to run
+type RunRequest struct {
+	// containerlab command line arguments, e.g. ["deploy", "--reconfigure"]
+	Args []string `json:"args"`
+	// file name and content of the topology file the command is run with
+	TopoName string `json:"topo-name,omitempty"`
+	Topo     []byte `json:"topo,omitempty"`
+}
+
+// RunOutput is the message of the output stream of the command run by the agent,
+// the last message of the stream carries the exit code of the command
+type RunOutput struct {
+	Data     []byte `json:"data,omitempty"`
+	ExitCode *int   `json:"exit-code,omitempty"`
+}
+
+// ProxyData is the message of the connection proxied by the agent,
+// the first message of the client carries the address of the lab node to connect to
+type ProxyData struct {
+	Addr string `json:"addr,omitempty"`
+	Data []byte `json:"data,omitempty"`
+}
+
+// serviceDesc describes the gRPC service of the agent
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: Service,
+	HandlerType: (*interface{})(nil),
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Run",
+			ServerStreams: true,
+			Handler: func(srv interface{}, stream grpc.ServerStream) error {
+				return srv.(*Server).run(stream)
+			},
+		},
+		{
+			StreamName:    "Proxy",
+			ServerStreams: true,
+			ClientStreams: true,
+			Handler: func(srv interface{}, stream grpc.ServerStream) error {
+				return srv.(*Server).proxy(stream)
+			},
+		},
+	},
+}
@@ -0,0 +1,129 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/srl-labs/containerlab/runner"
+)
+
+// CLIBackend runs the lab operations with the containerlab commands run by the runner shared with the agent.
+// The commands run in the directory of the topology file, so the lab directory is created next to it
+type CLIBackend struct {
+	Runner *runner.Runner
+}
+
+// Deploy deploys the lab of the topology file
+func (b *CLIBackend) Deploy(ctx context.Context, topoFile string, reconfigure bool) error {
+	args := []string{"deploy", "--topo", topoFile}
+	if reconfigure {
+		args = append(args, "--reconfigure")
+	}
+	_, err := b.run(ctx, topoFile, args, true)
+	return err
+}
+
+// Destroy destroys the lab of the topology file and removes its lab directory
+func (b *CLIBackend) Destroy(ctx context.Context, topoFile string) error {
+	_, err := b.run(ctx, topoFile, []string{"destroy", "--topo", topoFile, "--cleanup"}, true)
+	return err
+}
+
+// Inspect returns the containers of the lab nodes
+func (b *CLIBackend) Inspect(ctx context.Context, topoFile string) ([]*Node, error) {
+	out, err := b.run(ctx, topoFile, []string{"inspect", "--topo", topoFile, "--format", "json"}, false)
+	if err != nil {
+		return nil, err
+	}
+	var nodes []*Node
+	// the lab without containers is reported in the log only
+	if len(bytes.TrimSpace(out)) == 0 {
+		return nodes, nil
+	}
+	if err := json.Unmarshal(out, &nodes); err != nil {
+		return nil, fmt.Errorf("failed to decode inspect output: %v", err)
+	}
+	return nodes, nil
+}
+
+// Exec executes the command on the lab nodes and returns the results sorted by node
+func (b *CLIBackend) Exec(ctx context.Context, topoFile, cmd string, nodes []string) ([]*ExecResult, error) {
+	args := []string{"exec", "--topo", topoFile, "--cmd", cmd, "--format", "json"}
+	for _, n := range nodes {
+		args = append(args, "--node", n)
+	}
+	out, err := b.run(ctx, topoFile, args, false)
+	if err != nil {
+		return nil, err
+	}
+	return execResults(out, cmd)
+}
+
+// execResults returns the results of the command cmd from the json output of the exec command
+func execResults(out []byte, cmd string) ([]*ExecResult, error) {
+	var doc map[string]map[string]*struct {
+		Stdout   interface{} `json:"stdout"`
+		Stderr   string      `json:"stderr"`
+		ExitCode int         `json:"exit_code"`
+	}
+	if err := json.Unmarshal(out, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode exec output: %v", err)
+	}
+	res := []*ExecResult{}
+	for node, cmds := range doc {
+		r := cmds[cmd]
+		if r == nil {
+			continue
+		}
+		res = append(res, &ExecResult{Node: node, Stdout: r.Stdout, Stderr: r.Stderr, ExitCode: r.ExitCode})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Node < res[j].Node })
+	return res, nil
+}
+
+// run runs the containerlab command and returns its stdout, the log of the command is written to the server log.
+// The lab operations, i.e. deploy and destroy, have their output written to the server log as well.
+// They run one at a time for the lab and are not canceled with ctx, so that a client going away
+// doesn't leave a lab partially deployed or destroyed, while the other commands run concurrently.
+// The failed commands are reported with the last line of their log
+func (b *CLIBackend) run(ctx context.Context, topoFile string, args []string, labOp bool) ([]byte, error) {
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	cmd := &runner.Command{
+		Args:   args,
+		Dir:    filepath.Dir(topoFile),
+		Stdout: stdout,
+		Stderr: io.MultiWriter(os.Stderr, stderr),
+	}
+	if labOp {
+		cmd.Lab = topoFile
+		cmd.Stdout = io.MultiWriter(os.Stderr, stdout)
+	}
+	rc, err := b.Runner.Run(ctx, cmd)
+	if err == nil && rc != 0 {
+		err = fmt.Errorf("exit status %d", rc)
+	}
+	if err != nil {
+		if line := lastLine(stderr.Bytes()); line != "" {
+			return nil, fmt.Errorf("containerlab %s failed: %v: %s", args[0], err, line)
+		}
+		return nil, fmt.Errorf("containerlab %s failed: %v", args[0], err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// lastLine returns the last non-empty line of the output
+func lastLine(out []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
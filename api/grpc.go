@@ -0,0 +1,126 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCService is the name of the gRPC service of the API server
+const GRPCService = "containerlab.v1.Labs"
+
+// JSONCodec marshals the gRPC messages of the API server to JSON, so that the service is used without the generated protobuf code.
+// The clients select it with the "json" content subtype, i.e. the application/grpc+json content type
+type JSONCodec struct{}
+
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+func (JSONCodec) Name() string { return "json" }
+
+func init() {
+	encoding.RegisterCodec(JSONCodec{})
+}
+
+// grpcCodes maps the HTTP status codes of the API errors to the gRPC codes
+var grpcCodes = map[int]codes.Code{
+	http.StatusBadRequest:          codes.InvalidArgument,
+	http.StatusUnauthorized:        codes.Unauthenticated,
+	http.StatusNotFound:            codes.NotFound,
+	http.StatusConflict:            codes.FailedPrecondition,
+	http.StatusInternalServerError: codes.Internal,
+}
+
+// grpcError converts the API error to the gRPC status error
+func grpcError(err error) error {
+	if err == nil {
+		return nil
+	}
+	e, ok := err.(*Error)
+	if !ok {
+		return status.Error(codes.Internal, err.Error())
+	}
+	c, ok := grpcCodes[e.Code]
+	if !ok {
+		c = codes.Unknown
+	}
+	return status.Error(c, e.Message)
+}
+
+// grpcMethod returns the description of the unary method of the service calling the server method
+// with the request decoded to a new value of the request type
+func grpcMethod(name string, newReq func() interface{}, call func(s *Server, ctx context.Context, req interface{}) (interface{}, error)) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: name,
+		Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+			req := newReq()
+			if err := dec(req); err != nil {
+				return nil, err
+			}
+			h := func(ctx context.Context, req interface{}) (interface{}, error) {
+				resp, err := call(srv.(*Server), ctx, req)
+				return resp, grpcError(err)
+			}
+			info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + GRPCService + "/" + name}
+			return interceptor(ctx, req, info, h)
+		},
+	}
+}
+
+// grpcServiceDesc describes the gRPC service with the methods matching the HTTP API
+var grpcServiceDesc = grpc.ServiceDesc{
+	ServiceName: GRPCService,
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{
+		grpcMethod("Deploy", func() interface{} { return new(DeployRequest) },
+			func(s *Server, ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Deploy(ctx, req.(*DeployRequest))
+			}),
+		grpcMethod("Destroy", func() interface{} { return new(LabRequest) },
+			func(s *Server, ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Destroy(ctx, req.(*LabRequest))
+			}),
+		grpcMethod("Inspect", func() interface{} { return new(LabRequest) },
+			func(s *Server, ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Inspect(ctx, req.(*LabRequest))
+			}),
+		grpcMethod("List", func() interface{} { return new(ListRequest) },
+			func(s *Server, ctx context.Context, req interface{}) (interface{}, error) {
+				return s.List(ctx, req.(*ListRequest))
+			}),
+		grpcMethod("Exec", func() interface{} { return new(ExecRequest) },
+			func(s *Server, ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Exec(ctx, req.(*ExecRequest))
+			}),
+	},
+}
+
+// newGRPCServer returns the gRPC server of the API server authenticating the calls with the bearer token
+// passed in the authorization metadata
+func newGRPCServer(s *Server) *grpc.Server {
+	auth := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, h grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		var token string
+		if v := md.Get("authorization"); len(v) > 0 {
+			token = v[0]
+		}
+		if !s.validToken(token) {
+			return nil, status.Error(codes.Unauthenticated, "invalid or missing bearer token")
+		}
+		return h(ctx, req)
+	}
+	g := grpc.NewServer(grpc.UnaryInterceptor(auth))
+	g.RegisterService(&grpcServiceDesc, s)
+	return g
+}
@@ -0,0 +1,142 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// httpPrefix is the path prefix of the HTTP API
+const httpPrefix = "/api/v1/"
+
+// maxTopologySize limits the size of the request bodies carrying the topologies
+const maxTopologySize = 1 << 20
+
+// authenticate rejects the requests without the bearer token of the server
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.validToken(r.Header.Get("Authorization")) {
+			writeError(w, errorf(http.StatusUnauthorized, "invalid or missing bearer token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleLabs lists the labs and deploys the labs of the topologies
+func (s *Server) handleLabs(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		resp, err := s.List(r.Context(), &ListRequest{})
+		writeResponse(w, http.StatusOK, resp, err)
+	case http.MethodPost:
+		req, err := deployRequest(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		lab, err := s.Deploy(r.Context(), req)
+		writeResponse(w, operationStatus(req.Wait), lab, err)
+	default:
+		writeError(w, errorf(http.StatusMethodNotAllowed, "method %s is not allowed", r.Method))
+	}
+}
+
+// handleLab inspects and destroys the lab and executes the commands on its nodes
+func (s *Server) handleLab(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, httpPrefix+"labs/"), "/")
+	name, action := path, ""
+	if i := strings.Index(path, "/"); i >= 0 {
+		name, action = path[:i], path[i+1:]
+	}
+	wait, _ := strconv.ParseBool(r.URL.Query().Get("wait"))
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		resp, err := s.Inspect(r.Context(), &LabRequest{Name: name})
+		writeResponse(w, http.StatusOK, resp, err)
+	case action == "" && r.Method == http.MethodDelete:
+		lab, err := s.Destroy(r.Context(), &LabRequest{Name: name, Wait: wait})
+		writeResponse(w, operationStatus(wait), lab, err)
+	case action == "exec" && r.Method == http.MethodPost:
+		req := new(ExecRequest)
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			writeError(w, errorf(http.StatusBadRequest, "failed to decode request: %v", err))
+			return
+		}
+		req.Name = name
+		resp, err := s.Exec(r.Context(), req)
+		writeResponse(w, http.StatusOK, resp, err)
+	case action == "" || action == "exec":
+		writeError(w, errorf(http.StatusMethodNotAllowed, "method %s is not allowed", r.Method))
+	default:
+		writeError(w, errorf(http.StatusNotFound, "unknown path %s", r.URL.Path))
+	}
+}
+
+// deployRequest returns the deploy request of the JSON body or of the YAML topology posted as is,
+// the reconfigure and wait options of the latter are set with the query parameters
+func deployRequest(r *http.Request) (*DeployRequest, error) {
+	b, err := ioutil.ReadAll(http.MaxBytesReader(nil, r.Body, maxTopologySize))
+	if err != nil {
+		return nil, errorf(http.StatusBadRequest, "failed to read request: %v", err)
+	}
+	req := new(DeployRequest)
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		if err := json.Unmarshal(b, req); err != nil {
+			return nil, errorf(http.StatusBadRequest, "failed to decode request: %v", err)
+		}
+	} else {
+		req.Topology = string(b)
+	}
+	q := r.URL.Query()
+	if v, err := strconv.ParseBool(q.Get("reconfigure")); err == nil {
+		req.Reconfigure = v
+	}
+	if v, err := strconv.ParseBool(q.Get("wait")); err == nil {
+		req.Wait = v
+	}
+	return req, nil
+}
+
+// operationStatus returns the status code of the deploy and destroy responses,
+// the operations running in the background are accepted only
+func operationStatus(wait bool) int {
+	if wait {
+		return http.StatusOK
+	}
+	return http.StatusAccepted
+}
+
+func writeResponse(w http.ResponseWriter, code int, v interface{}, err error) {
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, code, v)
+}
+
+// writeError writes the API error with its status code, the other errors are internal server errors
+func writeError(w http.ResponseWriter, err error) {
+	e, ok := err.(*Error)
+	if !ok {
+		e = errorf(http.StatusInternalServerError, "%v", err)
+	}
+	writeJSON(w, e.Code, e)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Debugf("failed to write API response: %v", err)
+	}
+}
@@ -0,0 +1,350 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package api implements the API server managing the labs of the container host over HTTP and gRPC
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+	"gopkg.in/yaml.v2"
+)
+
+// Backend runs the lab operations of the API server on the container host,
+// the labs are referenced by the paths to their topology files in the store
+type Backend interface {
+	Deploy(ctx context.Context, topoFile string, reconfigure bool) error
+	Destroy(ctx context.Context, topoFile string) error
+	Inspect(ctx context.Context, topoFile string) ([]*Node, error)
+	Exec(ctx context.Context, topoFile string, cmd string, nodes []string) ([]*ExecResult, error)
+}
+
+// DeployRequest deploys the lab of the topology, the lab name is taken from the topology
+type DeployRequest struct {
+	Topology    string `json:"topology"`
+	Reconfigure bool   `json:"reconfigure,omitempty"`
+	// Wait makes the request return after the deployment is finished
+	Wait bool `json:"wait,omitempty"`
+}
+
+// LabRequest references a lab of the API server
+type LabRequest struct {
+	Name string `json:"name"`
+	Wait bool   `json:"wait,omitempty"`
+}
+
+// ListRequest lists the labs of the API server
+type ListRequest struct{}
+
+// ListResponse is the list of the labs of the API server
+type ListResponse struct {
+	Labs []*Lab `json:"labs"`
+}
+
+// Node is the container of a lab node, as reported by the inspect command in the json format
+type Node struct {
+	Name        string   `json:"name"`
+	ContainerID string   `json:"container_id,omitempty"`
+	Image       string   `json:"image,omitempty"`
+	Kind        string   `json:"kind,omitempty"`
+	Group       string   `json:"group,omitempty"`
+	State       string   `json:"state,omitempty"`
+	Health      string   `json:"health,omitempty"`
+	IPv4Address string   `json:"ipv4_address,omitempty"`
+	IPv6Address string   `json:"ipv6_address,omitempty"`
+	Ports       []string `json:"ports,omitempty"`
+}
+
+// InspectResponse is the state of the lab and the containers of its nodes
+type InspectResponse struct {
+	Lab   *Lab    `json:"lab"`
+	Nodes []*Node `json:"nodes"`
+}
+
+// ExecRequest executes the command on the nodes of the lab, all the nodes when no nodes are given.
+// The node names may be the glob patterns
+type ExecRequest struct {
+	Name  string   `json:"name"`
+	Cmd   string   `json:"cmd"`
+	Nodes []string `json:"nodes,omitempty"`
+}
+
+// ExecResult is the result of the command executed on the node,
+// the stdout of the command is decoded when it is a JSON document
+type ExecResult struct {
+	Node     string      `json:"node"`
+	Stdout   interface{} `json:"stdout"`
+	Stderr   string      `json:"stderr"`
+	ExitCode int         `json:"exit_code"`
+}
+
+// ExecResponse is the results of the command executed on the nodes of the lab
+type ExecResponse struct {
+	Results []*ExecResult `json:"results"`
+}
+
+// Error is the error of the API request with the HTTP status code of the response
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func errorf(code int, format string, args ...interface{}) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Server manages the labs of the store with the backend,
+// the deploy and destroy operations run in the background unless the request waits for them
+type Server struct {
+	store   *Store
+	backend Backend
+	token   string
+	grpc    *grpc.Server
+	// mu serializes the changes of the lab states
+	mu sync.Mutex
+	// wg tracks the operations running in the background
+	wg sync.WaitGroup
+}
+
+// NewServer returns the API server authenticating the requests with the token.
+// The labs left deploying or destroying by the previous server are marked failed
+func NewServer(store *Store, backend Backend, token string) (*Server, error) {
+	if token == "" {
+		return nil, fmt.Errorf("API token is not set")
+	}
+	s := &Server{store: store, backend: backend, token: token}
+	labs, err := store.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range labs {
+		if !l.busy() {
+			continue
+		}
+		log.Warnf("lab %s was left %s by the previous server, marking it failed", l.Name, l.Status)
+		l.Status, l.Error, l.Updated = StatusFailed, fmt.Sprintf("%s was interrupted by the server restart", l.Status), time.Now()
+		if err := store.Save(l); err != nil {
+			return nil, err
+		}
+	}
+	s.grpc = newGRPCServer(s)
+	return s, nil
+}
+
+// Wait waits for the operations running in the background to finish
+func (s *Server) Wait() {
+	s.wg.Wait()
+}
+
+// Handler returns the handler serving the gRPC requests and the HTTP API,
+// the HTTP/2 requests are accepted without TLS as well, so that the gRPC clients can connect without TLS
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(httpPrefix+"labs", s.handleLabs)
+	mux.HandleFunc(httpPrefix+"labs/", s.handleLab)
+	api := s.authenticate(mux)
+
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+			s.grpc.ServeHTTP(w, r)
+			return
+		}
+		api.ServeHTTP(w, r)
+	})
+	return h2c.NewHandler(h, &http2.Server{})
+}
+
+// validToken returns true when the authorization header value carries the bearer token of the server
+func (s *Server) validToken(auth string) bool {
+	const prefix = "Bearer "
+	if !strings.HasPrefix(auth, prefix) {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(auth, prefix)), []byte(s.token)) == 1
+}
+
+// Deploy deploys the lab of the topology or reconfigures the deployed lab
+func (s *Server) Deploy(ctx context.Context, req *DeployRequest) (*Lab, error) {
+	var meta struct {
+		Name string `yaml:"name"`
+	}
+	if err := yaml.Unmarshal([]byte(req.Topology), &meta); err != nil {
+		return nil, errorf(http.StatusBadRequest, "failed to parse topology: %v", err)
+	}
+	if !labNameRe.MatchString(meta.Name) {
+		return nil, errorf(http.StatusBadRequest, "invalid lab name %q", meta.Name)
+	}
+
+	s.mu.Lock()
+	l, err := s.store.Get(meta.Name)
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	switch {
+	case l == nil:
+		l = &Lab{Name: meta.Name, Created: time.Now()}
+	case l.busy():
+		s.mu.Unlock()
+		return nil, errorf(http.StatusConflict, "lab %s is %s", l.Name, l.Status)
+	case l.Status == StatusRunning && !req.Reconfigure:
+		s.mu.Unlock()
+		return nil, errorf(http.StatusConflict, "lab %s is deployed already, set reconfigure to redeploy it", l.Name)
+	}
+	if l.TopoFile, err = s.store.WriteTopology(l.Name, []byte(req.Topology)); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	l.Status, l.Error, l.Updated = StatusDeploying, "", time.Now()
+	err = s.store.Save(l)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.run(ctx, l, req.Wait, func(ctx context.Context) error {
+		return s.backend.Deploy(ctx, l.TopoFile, req.Reconfigure)
+	})
+}
+
+// Destroy destroys the lab and removes it from the store
+func (s *Server) Destroy(ctx context.Context, req *LabRequest) (*Lab, error) {
+	s.mu.Lock()
+	l, err := s.lab(req.Name)
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	if l.busy() {
+		s.mu.Unlock()
+		return nil, errorf(http.StatusConflict, "lab %s is %s", l.Name, l.Status)
+	}
+	l.Status, l.Error, l.Updated = StatusDestroying, "", time.Now()
+	err = s.store.Save(l)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.run(ctx, l, req.Wait, func(ctx context.Context) error {
+		return s.backend.Destroy(ctx, l.TopoFile)
+	})
+}
+
+// Inspect returns the state of the lab and the access details of its nodes
+func (s *Server) Inspect(ctx context.Context, req *LabRequest) (*InspectResponse, error) {
+	l, err := s.lab(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	resp := &InspectResponse{Lab: l, Nodes: []*Node{}}
+	if l.Status != StatusRunning {
+		return resp, nil
+	}
+	nodes, err := s.backend.Inspect(ctx, l.TopoFile)
+	if err != nil {
+		return nil, err
+	}
+	if nodes != nil {
+		resp.Nodes = nodes
+	}
+	return resp, nil
+}
+
+// List returns the labs of the server
+func (s *Server) List(_ context.Context, _ *ListRequest) (*ListResponse, error) {
+	labs, err := s.store.List()
+	if err != nil {
+		return nil, err
+	}
+	return &ListResponse{Labs: labs}, nil
+}
+
+// Exec executes the command on the nodes of the running lab
+func (s *Server) Exec(ctx context.Context, req *ExecRequest) (*ExecResponse, error) {
+	if req.Cmd == "" {
+		return nil, errorf(http.StatusBadRequest, "command is not set")
+	}
+	l, err := s.lab(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if l.Status != StatusRunning {
+		return nil, errorf(http.StatusConflict, "lab %s is %s", l.Name, l.Status)
+	}
+	res, err := s.backend.Exec(ctx, l.TopoFile, req.Cmd, req.Nodes)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		res = []*ExecResult{}
+	}
+	return &ExecResponse{Results: res}, nil
+}
+
+// lab returns the state of the lab name, the labs missing from the store are reported with the not found error
+func (s *Server) lab(name string) (*Lab, error) {
+	l, err := s.store.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if l == nil {
+		return nil, errorf(http.StatusNotFound, "lab %q is not found", name)
+	}
+	return l, nil
+}
+
+// run runs the deploy or destroy operation of the lab and records its outcome in the store.
+// The operation runs in the background unless wait is set, it is not canceled with the request then
+func (s *Server) run(ctx context.Context, l *Lab, wait bool, op func(ctx context.Context) error) (*Lab, error) {
+	status := l.Status
+	done := func(ctx context.Context) (*Lab, error) {
+		err := op(ctx)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		res := *l
+		res.Updated = time.Now()
+		switch {
+		case err != nil:
+			log.Errorf("failed to %s lab %s: %v", strings.TrimSuffix(status, "ing"), l.Name, err)
+			res.Status, res.Error = StatusFailed, err.Error()
+		case status == StatusDestroying:
+			res.Status = StatusDestroyed
+			return &res, s.store.Delete(l.Name)
+		default:
+			res.Status = StatusRunning
+		}
+		return &res, s.store.Save(&res)
+	}
+	if wait {
+		res, err := done(ctx)
+		if err == nil && res.Status == StatusFailed {
+			return nil, errorf(http.StatusInternalServerError, "%s", res.Error)
+		}
+		return res, err
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		if _, err := done(context.Background()); err != nil {
+			log.Errorf("failed to save state of lab %s: %v", l.Name, err)
+		}
+	}()
+	res := *l
+	return &res, nil
+}
@@ -0,0 +1,269 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/runner"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const testToken = "secret"
+
+const testTopo = `name: lab1
+topology:
+  nodes:
+    n1:
+      kind: linux
+`
+
+// fakeBackend records the operations and fails the deployments of the topologies containing fail
+type fakeBackend struct {
+	ops []string
+}
+
+func (b *fakeBackend) Deploy(_ context.Context, topoFile string, reconfigure bool) error {
+	b.ops = append(b.ops, fmt.Sprintf("deploy reconfigure=%v", reconfigure))
+	if strings.Contains(topoFile, "fail") {
+		return fmt.Errorf("deploy failed")
+	}
+	return nil
+}
+
+func (b *fakeBackend) Destroy(_ context.Context, _ string) error {
+	b.ops = append(b.ops, "destroy")
+	return nil
+}
+
+func (b *fakeBackend) Inspect(_ context.Context, _ string) ([]*Node, error) {
+	return []*Node{{Name: "clab-lab1-n1", Kind: "linux", State: "running"}}, nil
+}
+
+func (b *fakeBackend) Exec(_ context.Context, _ string, cmd string, nodes []string) ([]*ExecResult, error) {
+	return []*ExecResult{{Node: "clab-lab1-n1", Stdout: cmd}}, nil
+}
+
+func newTestServer(t *testing.T, dir string) (*Server, *fakeBackend) {
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := new(fakeBackend)
+	s, err := NewServer(store, b, testToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, b
+}
+
+func doRequest(t *testing.T, h http.Handler, method, path, token, body string) (int, map[string]interface{}) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	res := map[string]interface{}{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatalf("failed to decode response %q: %v", rec.Body.String(), err)
+	}
+	return rec.Code, res
+}
+
+func TestHTTPAPI(t *testing.T) {
+	s, b := newTestServer(t, t.TempDir())
+	h := s.Handler()
+
+	if code, _ := doRequest(t, h, http.MethodGet, "/api/v1/labs", "wrong", ""); code != http.StatusUnauthorized {
+		t.Fatalf("request with wrong token: got status %d", code)
+	}
+
+	code, res := doRequest(t, h, http.MethodPost, "/api/v1/labs?wait=true", testToken, testTopo)
+	if code != http.StatusOK || res["status"] != StatusRunning {
+		t.Fatalf("deploy: got status %d, response %v", code, res)
+	}
+	code, _ = doRequest(t, h, http.MethodPost, "/api/v1/labs?wait=true", testToken, testTopo)
+	if code != http.StatusConflict {
+		t.Fatalf("deploy of deployed lab: got status %d", code)
+	}
+	code, _ = doRequest(t, h, http.MethodPost, "/api/v1/labs?wait=true&reconfigure=true", testToken, testTopo)
+	if code != http.StatusOK {
+		t.Fatalf("reconfigure: got status %d", code)
+	}
+
+	code, res = doRequest(t, h, http.MethodGet, "/api/v1/labs/lab1", testToken, "")
+	if code != http.StatusOK || len(res["nodes"].([]interface{})) != 1 {
+		t.Fatalf("inspect: got status %d, response %v", code, res)
+	}
+	code, res = doRequest(t, h, http.MethodPost, "/api/v1/labs/lab1/exec", testToken, `{"cmd": "uname"}`)
+	if code != http.StatusOK || res["results"].([]interface{})[0].(map[string]interface{})["stdout"] != "uname" {
+		t.Fatalf("exec: got status %d, response %v", code, res)
+	}
+	if code, _ = doRequest(t, h, http.MethodGet, "/api/v1/labs/lab2", testToken, ""); code != http.StatusNotFound {
+		t.Fatalf("inspect of missing lab: got status %d", code)
+	}
+
+	code, res = doRequest(t, h, http.MethodDelete, "/api/v1/labs/lab1", testToken, "")
+	if code != http.StatusAccepted || res["status"] != StatusDestroying {
+		t.Fatalf("destroy: got status %d, response %v", code, res)
+	}
+	s.Wait()
+	_, res = doRequest(t, h, http.MethodGet, "/api/v1/labs", testToken, "")
+	if labs := res["labs"].([]interface{}); len(labs) != 0 {
+		t.Fatalf("destroyed lab is listed: %v", labs)
+	}
+
+	want := []string{"deploy reconfigure=false", "deploy reconfigure=true", "destroy"}
+	if !cmp.Equal(b.ops, want) {
+		t.Errorf("backend operations diff (-want +got):\n%s", cmp.Diff(want, b.ops))
+	}
+}
+
+func TestFailedDeploy(t *testing.T) {
+	s, _ := newTestServer(t, t.TempDir())
+	lab, err := s.Deploy(context.Background(), &DeployRequest{Topology: strings.Replace(testTopo, "lab1", "fail", 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lab.Status != StatusDeploying {
+		t.Fatalf("got status %s, want %s", lab.Status, StatusDeploying)
+	}
+	s.Wait()
+	resp, err := s.Inspect(context.Background(), &LabRequest{Name: "fail"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Lab.Status != StatusFailed || resp.Lab.Error != "deploy failed" {
+		t.Errorf("got status %s, error %q", resp.Lab.Status, resp.Lab.Error)
+	}
+}
+
+func TestServerRestart(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for _, l := range []*Lab{
+		{Name: "lab1", Status: StatusRunning, Created: now},
+		{Name: "lab2", Status: StatusDeploying, Created: now},
+	} {
+		if err := store.Save(l); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s, _ := newTestServer(t, dir)
+	resp, err := s.List(context.Background(), &ListRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, l := range resp.Labs {
+		got[l.Name] = l.Status
+	}
+	want := map[string]string{"lab1": StatusRunning, "lab2": StatusFailed}
+	if !cmp.Equal(got, want) {
+		t.Errorf("diff (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestGRPCAPI(t *testing.T) {
+	s, _ := newTestServer(t, t.TempDir())
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("failed to listen: %v", err)
+	}
+	srv := &http.Server{Handler: s.Handler()}
+	go srv.Serve(l)
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, l.Addr().String(), grpc.WithInsecure(), grpc.WithBlock(),
+		grpc.WithDefaultCallOptions(grpc.CallContentSubtype(JSONCodec{}.Name())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	resp := new(ListResponse)
+	err = conn.Invoke(ctx, "/"+GRPCService+"/List", &ListRequest{}, resp)
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("call without token: got %v", err)
+	}
+
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+testToken)
+	lab := new(Lab)
+	if err := conn.Invoke(ctx, "/"+GRPCService+"/Deploy", &DeployRequest{Topology: testTopo, Wait: true}, lab); err != nil {
+		t.Fatal(err)
+	}
+	if lab.Status != StatusRunning {
+		t.Fatalf("got status %s, want %s", lab.Status, StatusRunning)
+	}
+	err = conn.Invoke(ctx, "/"+GRPCService+"/Inspect", &LabRequest{Name: "lab2"}, new(InspectResponse))
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("inspect of missing lab: got %v", err)
+	}
+	if err := conn.Invoke(ctx, "/"+GRPCService+"/List", &ListRequest{}, resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Labs) != 1 || resp.Labs[0].Name != "lab1" {
+		t.Errorf("unexpected labs %+v", resp.Labs)
+	}
+}
+
+func TestExecResults(t *testing.T) {
+	out := []byte(`{
+  "clab-lab1-n2": {"uname": {"stdout": "Linux\n", "stderr": "", "exit_code": 0}},
+  "clab-lab1-n1": {"uname": {"stdout": {"os": "Linux"}, "stderr": "warning", "exit_code": 1}}
+}`)
+	got, err := execResults(out, "uname")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*ExecResult{
+		{Node: "clab-lab1-n1", Stdout: map[string]interface{}{"os": "Linux"}, Stderr: "warning", ExitCode: 1},
+		{Node: "clab-lab1-n2", Stdout: "Linux\n"},
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("diff (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestCLIBackendErrors(t *testing.T) {
+	dir := t.TempDir()
+	// the containerlab stand-in fails with the error logged on the last line
+	bin := filepath.Join(dir, "containerlab")
+	script := `#!/bin/sh
+echo "INFO[0000] Parsing & checking topology file" >&2
+echo "Error: $1 failed on $2" >&2
+exit 1
+`
+	if err := os.WriteFile(bin, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	b := &CLIBackend{Runner: &runner.Runner{Binary: bin, GlobalArgs: []string{"--runtime", "docker"}}}
+	err := b.Deploy(context.Background(), filepath.Join(dir, "lab1.clab.yml"), false)
+	want := "containerlab deploy failed: exit status 1: Error: --runtime failed on docker"
+	if err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// statuses of the labs managed by the API server
+const (
+	StatusDeploying  = "deploying"
+	StatusRunning    = "running"
+	StatusDestroying = "destroying"
+	StatusFailed     = "failed"
+	// StatusDestroyed is reported by the destroy requests waiting for the lab to be destroyed,
+	// the destroyed labs are removed from the store
+	StatusDestroyed = "destroyed"
+)
+
+const (
+	// labsDir is the directory of the state directory with the directories of the labs
+	labsDir = "labs"
+	// topoFile is the topology file of the lab in the lab directory of the store
+	topoFile = "topology.clab.yml"
+	// stateFile is the state of the lab in the lab directory of the store
+	stateFile = "state.json"
+)
+
+// labNameRe matches the lab names usable as the directory names of the store
+var labNameRe = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// Lab is the state of a lab managed by the API server
+type Lab struct {
+	Name     string    `json:"name"`
+	Status   string    `json:"status"`
+	Error    string    `json:"error,omitempty"`
+	TopoFile string    `json:"topo_file"`
+	Created  time.Time `json:"created"`
+	Updated  time.Time `json:"updated"`
+}
+
+// busy returns true when an operation is running on the lab
+func (l *Lab) busy() bool {
+	return l.Status == StatusDeploying || l.Status == StatusDestroying
+}
+
+// Store persists the topologies and the states of the labs in the state directory,
+// each lab has a directory with its topology file, the state file and the lab directory created by the deployment
+type Store struct {
+	dir string
+}
+
+// NewStore returns the store of the labs in the directory dir, creating it if needed
+func NewStore(dir string) (*Store, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Join(dir, labsDir), 0750); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %v", err)
+	}
+	return &Store{dir: dir}, nil
+}
+
+// Dir returns the state directory of the store
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+func (s *Store) labDir(name string) string {
+	return filepath.Join(s.dir, labsDir, name)
+}
+
+// WriteTopology writes the topology of the lab name to the store and returns the path to the topology file
+func (s *Store) WriteTopology(name string, b []byte) (string, error) {
+	if !labNameRe.MatchString(name) {
+		return "", fmt.Errorf("invalid lab name %q", name)
+	}
+	if err := os.MkdirAll(s.labDir(name), 0750); err != nil {
+		return "", err
+	}
+	p := filepath.Join(s.labDir(name), topoFile)
+	return p, ioutil.WriteFile(p, b, 0640)
+}
+
+// Get returns the state of the lab name, nil is returned for the labs missing from the store
+func (s *Store) Get(name string) (*Lab, error) {
+	if !labNameRe.MatchString(name) {
+		return nil, nil
+	}
+	b, err := ioutil.ReadFile(filepath.Join(s.labDir(name), stateFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	l := new(Lab)
+	if err := json.Unmarshal(b, l); err != nil {
+		return nil, fmt.Errorf("failed to read state of lab %s: %v", name, err)
+	}
+	return l, nil
+}
+
+// List returns the states of the labs of the store sorted by name
+func (s *Store) List() ([]*Lab, error) {
+	entries, err := ioutil.ReadDir(filepath.Join(s.dir, labsDir))
+	if err != nil {
+		return nil, err
+	}
+	labs := []*Lab{}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		l, err := s.Get(e.Name())
+		if err != nil {
+			return nil, err
+		}
+		if l != nil {
+			labs = append(labs, l)
+		}
+	}
+	sort.Slice(labs, func(i, j int) bool { return labs[i].Name < labs[j].Name })
+	return labs, nil
+}
+
+// Save writes the state of the lab to the store,
+// the state file is replaced atomically so that it is never read half-written
+func (s *Store) Save(l *Lab) error {
+	b, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.labDir(l.Name), 0750); err != nil {
+		return err
+	}
+	tmp := filepath.Join(s.labDir(l.Name), stateFile+".tmp")
+	if err := ioutil.WriteFile(tmp, b, 0640); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(s.labDir(l.Name), stateFile))
+}
+
+// Delete removes the lab name with its topology and lab directory from the store
+func (s *Store) Delete(name string) error {
+	if !labNameRe.MatchString(name) {
+		return nil
+	}
+	return os.RemoveAll(s.labDir(name))
+}
@@ -0,0 +1,271 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package catalog implements the catalog of the reusable lab templates shared across a team.
+//
+// A catalog index is a directory with a sub directory per template. A template directory holds
+// the catalog.yml file describing the template and its parameters, the topology.clab.yml topology file
+// and any other files the lab uses, e.g. startup configs. The files with the .tmpl suffix are rendered
+// with the template parameters as Go templates, the suffix is stripped from the rendered file names.
+//
+// The index is read from a local directory, a git repository or an OCI artifact.
+package catalog
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// MetaFile is the file describing a template
+	MetaFile = "catalog.yml"
+	// TopoFile is the topology file of a template, optionally with the .tmpl suffix
+	TopoFile = "topology.clab.yml"
+
+	tmplSuffix = ".tmpl"
+	// parameter types
+	ParamString = "string"
+	ParamInt    = "int"
+)
+
+// Param is a parameter of a template rendered into the lab files
+type Param struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	// string or int, string by default
+	Type    string `yaml:"type,omitempty"`
+	Default string `yaml:"default,omitempty"`
+}
+
+// Template is a lab template of the catalog
+type Template struct {
+	Name        string   `yaml:"-"`
+	Description string   `yaml:"description,omitempty"`
+	Params      []*Param `yaml:"parameters,omitempty"`
+	// directory of the template files
+	dir string
+}
+
+// Index is a catalog of the lab templates
+type Index struct {
+	// directory the templates are read from
+	Dir string
+}
+
+// Open returns the index of the source, either a local directory path,
+// a git repository URL (git+https://, git@, or an URL with the .git suffix) or an OCI artifact reference (oci://).
+// The remote indexes are fetched into the cache dir
+func Open(src, cacheDir string) (*Index, error) {
+	switch {
+	case strings.HasPrefix(src, "oci://"):
+		dir := cachePath(cacheDir, src)
+		ref := strings.TrimPrefix(src, "oci://")
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, err
+		}
+		log.Infof("Pulling catalog %s", ref)
+		if err := run("", "oras", "pull", ref, "-o", dir); err != nil {
+			return nil, err
+		}
+		return &Index{Dir: dir}, nil
+	case isGitURL(src):
+		dir := cachePath(cacheDir, src)
+		url := strings.TrimPrefix(src, "git+")
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			log.Infof("Updating catalog %s", url)
+			if err := run(dir, "git", "pull", "--ff-only", "-q"); err != nil {
+				return nil, err
+			}
+			return &Index{Dir: dir}, nil
+		}
+		if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+			return nil, err
+		}
+		log.Infof("Cloning catalog %s", url)
+		if err := run("", "git", "clone", "-q", "--depth", "1", url, dir); err != nil {
+			return nil, err
+		}
+		return &Index{Dir: dir}, nil
+	}
+	if fi, err := os.Stat(src); err != nil || !fi.IsDir() {
+		return nil, fmt.Errorf("catalog index %q is not a directory", src)
+	}
+	return &Index{Dir: src}, nil
+}
+
+func isGitURL(src string) bool {
+	return strings.HasPrefix(src, "git+") || strings.HasPrefix(src, "git@") ||
+		(strings.Contains(src, "://") && strings.HasSuffix(src, ".git"))
+}
+
+// cachePath returns the directory the remote index is fetched into
+func cachePath(cacheDir, src string) string {
+	return filepath.Join(cacheDir, fmt.Sprintf("%x", sha256.Sum256([]byte(src)))[:16])
+}
+
+func run(dir, name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s %s: %v: %s", name, strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// List returns the templates of the index sorted by name
+func (i *Index) List() ([]*Template, error) {
+	entries, err := ioutil.ReadDir(i.Dir)
+	if err != nil {
+		return nil, err
+	}
+	var res []*Template
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(i.Dir, e.Name(), MetaFile)); err != nil {
+			continue
+		}
+		t, err := i.Get(e.Name())
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, t)
+	}
+	sort.Slice(res, func(a, b int) bool { return res[a].Name < res[b].Name })
+	return res, nil
+}
+
+// Get returns the template of the index by its name
+func (i *Index) Get(name string) (*Template, error) {
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return nil, fmt.Errorf("invalid template name %q", name)
+	}
+	dir := filepath.Join(i.Dir, name)
+	b, err := ioutil.ReadFile(filepath.Join(dir, MetaFile))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("template %q is not found in the catalog", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	t := &Template{Name: name, dir: dir}
+	if err := yaml.UnmarshalStrict(b, t); err != nil {
+		return nil, fmt.Errorf("failed to parse %s of template %q: %v", MetaFile, name, err)
+	}
+	for _, p := range t.Params {
+		switch p.Type {
+		case "":
+			p.Type = ParamString
+		case ParamString, ParamInt:
+		default:
+			return nil, fmt.Errorf("template %q: parameter %q has unsupported type %q", name, p.Name, p.Type)
+		}
+	}
+	return t, nil
+}
+
+// Values returns the parameter values of the template rendering,
+// the values not set are taken from the parameter defaults
+func (t *Template) Values(set map[string]string) (map[string]interface{}, error) {
+	known := map[string]struct{}{}
+	vals := map[string]interface{}{}
+	for _, p := range t.Params {
+		known[p.Name] = struct{}{}
+		v, ok := set[p.Name]
+		if !ok {
+			v = p.Default
+		}
+		if v == "" && !ok {
+			return nil, fmt.Errorf("template %q: parameter %q is not set", t.Name, p.Name)
+		}
+		if p.Type == ParamInt {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("template %q: parameter %q must be an integer, got %q", t.Name, p.Name, v)
+			}
+			vals[p.Name] = n
+			continue
+		}
+		vals[p.Name] = v
+	}
+	for name := range set {
+		if _, ok := known[name]; !ok {
+			return nil, fmt.Errorf("template %q has no parameter %q", t.Name, name)
+		}
+	}
+	return vals, nil
+}
+
+// templateFuncs are the functions available to the template files in addition to the Go template builtins
+var templateFuncs = template.FuncMap{
+	// seq returns the numbers from 1 to n, e.g. to range over the node count parameters
+	"seq": func(n int) []int {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = i + 1
+		}
+		return s
+	},
+}
+
+// Render renders the template files with the parameter values into the dst directory
+// and returns the path of the rendered topology file
+func (t *Template) Render(vals map[string]interface{}, dst string) (string, error) {
+	err := filepath.Walk(t.dir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(t.dir, p)
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			if fi.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(filepath.Join(dst, rel), 0755)
+		}
+		if rel == MetaFile {
+			return nil
+		}
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if strings.HasSuffix(rel, tmplSuffix) {
+			rel = strings.TrimSuffix(rel, tmplSuffix)
+			tpl, err := template.New(rel).Funcs(templateFuncs).Option("missingkey=error").Parse(string(b))
+			if err != nil {
+				return fmt.Errorf("template %q: %v", t.Name, err)
+			}
+			buf := new(bytes.Buffer)
+			if err := tpl.Execute(buf, vals); err != nil {
+				return fmt.Errorf("template %q: %v", t.Name, err)
+			}
+			b = buf.Bytes()
+		}
+		return ioutil.WriteFile(filepath.Join(dst, rel), b, fi.Mode().Perm())
+	})
+	if err != nil {
+		return "", err
+	}
+	topo := filepath.Join(dst, TopoFile)
+	if _, err := os.Stat(topo); err != nil {
+		return "", fmt.Errorf("template %q has no %s file", t.Name, TopoFile)
+	}
+	return topo, nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package catalog
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testMeta = `description: leaves connected to a spine
+parameters:
+  - name: image
+    description: SR Linux image
+    default: ghcr.io/nokia/srlinux:21.6.4
+  - name: leaves
+    type: int
+    default: "2"
+`
+
+const testTopo = `name: fabric{{ .leaves }}
+topology:
+  nodes:
+    spine:
+      kind: srl
+      image: {{ .image }}
+{{- range seq .leaves }}
+    leaf{{ . }}:
+      kind: srl
+      image: {{ $.image }}
+      startup-config: leaf.cfg
+{{- end }}
+`
+
+func writeTestIndex(t *testing.T) string {
+	dir := t.TempDir()
+	tdir := filepath.Join(dir, "fabric")
+	if err := os.MkdirAll(tdir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{
+		MetaFile:              testMeta,
+		TopoFile + tmplSuffix: testTopo,
+		"leaf.cfg":            "set / system name host-name leaf\n",
+	} {
+		if err := ioutil.WriteFile(filepath.Join(tdir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// directories without the catalog.yml file are not templates
+	if err := os.MkdirAll(filepath.Join(dir, "docs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestList(t *testing.T) {
+	idx, err := Open(writeTestIndex(t), t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpls, err := idx.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tmpls) != 1 || tmpls[0].Name != "fabric" || len(tmpls[0].Params) != 2 || tmpls[0].Params[0].Type != ParamString {
+		t.Fatalf("unexpected templates %+v", tmpls)
+	}
+	if _, err := idx.Get("../fabric"); err == nil {
+		t.Error("wanted invalid template name error")
+	}
+}
+
+func TestRender(t *testing.T) {
+	idx, err := Open(writeTestIndex(t), t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := idx.Get("fabric")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tmpl.Values(map[string]string{"leaves": "two"}); err == nil {
+		t.Error("wanted integer parameter error")
+	}
+	if _, err := tmpl.Values(map[string]string{"spines": "2"}); err == nil {
+		t.Error("wanted unknown parameter error")
+	}
+	vals, err := tmpl.Values(map[string]string{"leaves": "3", "image": "srl:test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(t.TempDir(), "lab")
+	topo, err := tmpl.Render(vals, dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ioutil.ReadFile(topo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `name: fabric3
+topology:
+  nodes:
+    spine:
+      kind: srl
+      image: srl:test
+    leaf1:
+      kind: srl
+      image: srl:test
+      startup-config: leaf.cfg
+    leaf2:
+      kind: srl
+      image: srl:test
+      startup-config: leaf.cfg
+    leaf3:
+      kind: srl
+      image: srl:test
+      startup-config: leaf.cfg
+`
+	if string(got) != want {
+		t.Errorf("got rendered topology:\n%s\nwant:\n%s", got, want)
+	}
+	// the other template files are copied and the metadata is not
+	if _, err := os.Stat(filepath.Join(dst, "leaf.cfg")); err != nil {
+		t.Error(err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, MetaFile)); !os.IsNotExist(err) {
+		t.Errorf("wanted %s not to be rendered, got %v", MetaFile, err)
+	}
+}
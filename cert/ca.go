@@ -0,0 +1,115 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cert
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// CA is a certificate authority backend issuing the lab certificates
+type CA interface {
+	// GenerateRootCA makes the root CA certificate available in the labCARoot directory as root-ca.pem
+	GenerateRootCA(configName, labCARoot string) error
+	// SignNodeCert issues the node certificate described by input
+	// and stores it in the <labCADir>/<node-name> directory
+	SignNodeCert(input CertInput, labCADir, labCARoot string) (*Certificates, error)
+}
+
+// labCA is the CA backend used for the lab certificates
+var labCA CA = new(LocalCA)
+
+// SetCA sets the CA backend issuing the lab certificates
+func SetCA(ca CA) {
+	labCA = ca
+}
+
+// NewCA returns the CA backend selected in the lab settings.
+// The ca config applies to the local backend only
+func NewCA(b *types.CABackendConfig, ca *types.CAConfig) (CA, error) {
+	t := b.GetType()
+	if t != "local" && ca != nil {
+		return nil, fmt.Errorf("ca settings can't be used with the %s certificate authority backend", t)
+	}
+	switch t {
+	case "local":
+		return &LocalCA{Config: ca}, nil
+	case "vault":
+		return NewVaultCA(b.Vault)
+	case "step-ca":
+		return NewStepCA(b.StepCA)
+	}
+	return nil, fmt.Errorf("unknown certificate authority backend %q, supported backends are local, vault and step-ca", t)
+}
+
+// LocalCA issues the lab certificates with cfssl.
+// The root CA is generated per lab, unless the external CA is provided in the config
+type LocalCA struct {
+	Config *types.CAConfig
+}
+
+// GenerateRootCA generates the lab root CA or installs the external CA,
+// and creates the intermediate CA when it is enabled
+func (l *LocalCA) GenerateRootCA(configName, labCARoot string) error {
+	if l.Config.IsExternal() {
+		if err := useExternalCA(labCARoot, l.Config); err != nil {
+			return err
+		}
+		return syncIntermediateCA(configName, labCARoot, l.Config.UseIntermediate())
+	}
+
+	// if either of the files doesn't exist, create root CA
+	if !utils.FileExists(filepath.Join(labCARoot, "root-ca.pem")) ||
+		!utils.FileExists(filepath.Join(labCARoot, "root-ca-key.pem")) {
+		if err := generateRootCA(configName, labCARoot); err != nil {
+			return err
+		}
+	}
+
+	return syncIntermediateCA(configName, labCARoot, l.Config.UseIntermediate())
+}
+
+// SignNodeCert generates the node certificate signed by the intermediate CA when it is used,
+// otherwise by the lab root CA
+func (*LocalCA) SignNodeCert(input CertInput, labCADir, labCARoot string) (*Certificates, error) {
+	certTpl, err := template.New("node-cert").Parse(NodeCSRTempl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Node CSR Template: %v", err)
+	}
+	caCert, caKey, _ := signingCA(labCADir, labCARoot)
+	targetPath := filepath.Join(labCADir, input.Name)
+	certs, err := GenerateCert(caCert, caKey, certTpl, input, targetPath)
+	if err != nil {
+		return nil, err
+	}
+	if certs.CertChain, err = certChain(certs.Cert, labCADir, labCARoot); err != nil {
+		return nil, fmt.Errorf("failed to read intermediate CA certificate: %v", err)
+	}
+	return certs, utils.CreateFile(filepath.Join(targetPath, input.Name+"-chain.pem"), string(certs.CertChain))
+}
+
+// writeNodeCertFiles writes the node certificate, key and certificate chain
+// issued by a remote CA backend to the <labCADir>/<node-name> directory
+func writeNodeCertFiles(certs *Certificates, labCADir, name string) error {
+	dir := filepath.Join(labCADir, name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for suffix, data := range map[string][]byte{
+		".pem":       certs.Cert,
+		"-key.pem":   certs.Key,
+		"-chain.pem": certs.CertChain,
+	} {
+		if err := utils.CreateFile(filepath.Join(dir, name+suffix), string(data)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
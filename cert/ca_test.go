@@ -0,0 +1,184 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cert
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestNewCA(t *testing.T) {
+	tests := map[string]struct {
+		backend *types.CABackendConfig
+		ca      *types.CAConfig
+		want    string
+		wantErr bool
+	}{
+		"default": {
+			want: "*cert.LocalCA",
+		},
+		"local_external": {
+			backend: &types.CABackendConfig{Type: "local"},
+			ca:      &types.CAConfig{Intermediate: true},
+			want:    "*cert.LocalCA",
+		},
+		"vault": {
+			backend: &types.CABackendConfig{Type: "vault", Vault: &types.VaultConfig{
+				Address: "https://vault:8200", Token: "s.token", Role: "clab",
+			}},
+			want: "*cert.VaultCA",
+		},
+		"vault_no_role": {
+			backend: &types.CABackendConfig{Type: "vault", Vault: &types.VaultConfig{
+				Address: "https://vault:8200", Token: "s.token",
+			}},
+			wantErr: true,
+		},
+		"vault_with_ca": {
+			backend: &types.CABackendConfig{Type: "vault", Vault: &types.VaultConfig{
+				Address: "https://vault:8200", Token: "s.token", Role: "clab",
+			}},
+			ca:      &types.CAConfig{Intermediate: true},
+			wantErr: true,
+		},
+		"step_ca_no_url": {
+			backend: &types.CABackendConfig{Type: "step-ca", StepCA: &types.StepCAConfig{}},
+			wantErr: true,
+		},
+		"unknown": {
+			backend: &types.CABackendConfig{Type: "openssl"},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewCA(tc.backend, tc.ca)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err != nil {
+				return
+			}
+			if typ := fmt.Sprintf("%T", got); typ != tc.want {
+				t.Errorf("expected %s backend, got %s", tc.want, typ)
+			}
+		})
+	}
+}
+
+func TestVaultCA(t *testing.T) {
+	var issueReq map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/lab-pki/ca/pem":
+			w.Write([]byte("-----BEGIN CERTIFICATE-----\nCA\n-----END CERTIFICATE-----\n"))
+		case "/v1/lab-pki/issue/clab":
+			json.NewDecoder(r.Body).Decode(&issueReq)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"certificate": "NODE",
+					"issuing_ca":  "ICA",
+					"ca_chain":    []string{"ICA", "CA"},
+					"private_key": "KEY",
+				},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "clab-cert")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	labCA := filepath.Join(dir, "ca")
+	labCARoot := filepath.Join(labCA, "root")
+
+	ca, err := NewVaultCA(&types.VaultConfig{Address: srv.URL + "/", Token: "s.token", Mount: "/lab-pki/", Role: "clab"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ca.GenerateRootCA("lab", labCARoot); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(labCARoot, "root-ca.pem")); err != nil {
+		t.Errorf("vault CA certificate is not written: %v", err)
+	}
+
+	certs, err := ca.SignNodeCert(CertInput{
+		Name:       "srl1",
+		CommonName: "srl1.lab.io",
+		Hosts:      []string{"srl1", "clab-lab-srl1", "172.20.20.2"},
+		Expiry:     "720h",
+	}, labCA, labCARoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantReq := map[string]string{
+		"common_name": "srl1.lab.io",
+		"alt_names":   "srl1,clab-lab-srl1",
+		"ip_sans":     "172.20.20.2",
+		"format":      "pem",
+		"ttl":         "720h",
+	}
+	if !cmp.Equal(issueReq, wantReq) {
+		t.Errorf("issue request diff (-want +got):\n%s", cmp.Diff(wantReq, issueReq))
+	}
+	if string(certs.CertChain) != "NODE\nICA\nCA" {
+		t.Errorf("unexpected certificate chain %q", certs.CertChain)
+	}
+
+	// issued certificate is stored in the lab CA directory
+	stored, err := RetrieveNodeCertData(&types.NodeConfig{ShortName: "srl1"}, labCA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(stored.Key) != "KEY\n" || string(stored.CertChain) != "NODE\nICA\nCA\n" {
+		t.Errorf("unexpected stored certificate data: %+v", stored)
+	}
+}
+
+func TestStepCACertificateArgs(t *testing.T) {
+	s := &StepCA{cfg: &types.StepCAConfig{
+		URL:          "https://ca:9000",
+		Provisioner:  "clab",
+		PasswordFile: "/etc/step/pass",
+	}}
+	got := s.certificateArgs(CertInput{
+		CommonName: "srl1.lab.io",
+		Hosts:      []string{"srl1", "172.20.20.2"},
+		Expiry:     "720h",
+	}, "root-ca.pem", "srl1-chain.pem", "srl1-key.pem")
+	want := []string{"ca", "certificate", "srl1.lab.io", "srl1-chain.pem", "srl1-key.pem",
+		"--ca-url", "https://ca:9000",
+		"--root", "root-ca.pem",
+		"--provisioner", "clab",
+		"--force",
+		"--provisioner-password-file", "/etc/step/pass",
+		"--not-after", "720h",
+		"--san", "srl1.lab.io",
+		"--san", "srl1",
+		"--san", "172.20.20.2",
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("diff (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
@@ -6,11 +6,16 @@ package cert
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"encoding/json"
+	"encoding/pem"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"text/template"
+	"time"
 
 	"github.com/cloudflare/cfssl/api/generator"
 	"github.com/cloudflare/cfssl/cli/genkey"
@@ -29,6 +34,8 @@ type Certificates struct {
 	Key  []byte
 	Csr  []byte
 	Cert []byte
+	// certificate followed by the certificates of the intermediate CAs
+	CertChain []byte
 }
 
 // CertInput struct
@@ -81,25 +88,62 @@ var rootCACSRTempl string = `{
 `
 
 var NodeCSRTempl string = `{
-    "CN": "{{.Name}}.{{.Prefix}}.io",
+    "CN": "{{.CommonName}}",
     "key": {
       "algo": "rsa",
       "size": 2048
     },
     "names": [{
-      "C": "BE",
-      "L": "Antwerp",
-      "O": "Nokia",
-      "OU": "Container lab"
+      "C": "{{.Country}}",
+      "L": "{{.Locality}}",
+      "O": "{{.Organization}}",
+      "OU": "{{.OrganizationUnit}}"
     }],
     "hosts": [
-      "{{.Name}}",
-      "{{.LongName}}",
-      "{{.Fqdn}}"
+      {{- range $i, $h := .Hosts}}{{if $i}},{{end}}
+      "{{$h}}"
+      {{- end}}
     ]
 }
 `
 
+// NodeCertInput returns the certificate input for a node.
+// Subject fields, expiry and SANs set in the node certificate config override the defaults,
+// node names and management addresses are always part of the SANs
+func NodeCertInput(n *types.NodeConfig, prefix string) CertInput {
+	c := (&types.CertificateConfig{
+		CommonName:       n.ShortName + "." + prefix + ".io",
+		Country:          "BE",
+		Locality:         "Antwerp",
+		Organization:     "Nokia",
+		OrganizationUnit: "Container lab",
+	}).Merge(n.Certificate)
+
+	var hosts []string
+	seen := map[string]struct{}{}
+	for _, h := range append([]string{n.ShortName, n.LongName, n.Fqdn, n.MgmtIPv4Address, n.MgmtIPv6Address}, c.SANs...) {
+		if _, ok := seen[h]; ok || h == "" {
+			continue
+		}
+		seen[h] = struct{}{}
+		hosts = append(hosts, h)
+	}
+
+	return CertInput{
+		Hosts:            hosts,
+		CommonName:       c.CommonName,
+		Country:          c.Country,
+		Locality:         c.Locality,
+		Organization:     c.Organization,
+		OrganizationUnit: c.OrganizationUnit,
+		Expiry:           c.Expiry,
+		Name:             n.ShortName,
+		LongName:         n.LongName,
+		Fqdn:             n.Fqdn,
+		Prefix:           prefix,
+	}
+}
+
 // GenerateRootCa function
 func GenerateRootCa(labCARoot string, csrRootJsonTpl *template.Template, input CaRootInput) (*Certificates, error) {
 	log.Info("Creating root CA")
@@ -163,6 +207,12 @@ func GenerateCert(ca, caKey string, csrJSONTpl *template.Template, input CertInp
 		Profiles: map[string]*config.SigningProfile{},
 		Default:  config.DefaultConfig(),
 	}
+	if input.Expiry != "" {
+		if policy.Default.Expiry, err = time.ParseDuration(input.Expiry); err != nil {
+			return nil, fmt.Errorf("invalid certificate expiry %q: %v", input.Expiry, err)
+		}
+		policy.Default.ExpiryString = input.Expiry
+	}
 	root := universal.Root{
 		Config: map[string]string{
 			"cert-file": ca,
@@ -196,12 +246,13 @@ func GenerateCert(ca, caKey string, csrJSONTpl *template.Template, input CertInp
 	return certs, nil
 }
 
-// RetrieveNodeCertData reads the node private key and certificate by the well known paths
-// if either of those files doesn't exist, an error is returned
+// RetrieveNodeCertData reads the node private key, certificate and certificate chain by the well known paths
+// if either of the key and certificate files doesn't exist, an error is returned
 func RetrieveNodeCertData(n *types.NodeConfig, labCADir string) (*Certificates, error) {
 	var nodeCertFilesDir = filepath.Join(labCADir, n.ShortName)
 	var nodeCertFile = filepath.Join(nodeCertFilesDir, n.ShortName+".pem")
 	var nodeKeyFile = filepath.Join(nodeCertFilesDir, n.ShortName+"-key.pem")
+	var nodeChainFile = filepath.Join(nodeCertFilesDir, n.ShortName+"-chain.pem")
 
 	var certs = &Certificates{}
 
@@ -222,22 +273,86 @@ func RetrieveNodeCertData(n *types.NodeConfig, labCADir string) (*Certificates,
 		return nil, err
 	}
 
+	// certificates signed by the root CA don't have the chain file
+	certs.CertChain = certs.Cert
+	if chain, err := utils.ReadFileContent(nodeChainFile); err == nil {
+		certs.CertChain = chain
+	}
+
 	return certs, nil
 }
 
+// Fingerprint returns the SHA-256 fingerprint of the first certificate of the PEM data
+// as the colon separated upper case hex bytes, the format of openssl x509 -fingerprint
+func Fingerprint(certPEM []byte) (string, error) {
+	b, _ := pem.Decode(certPEM)
+	if b == nil || b.Type != "CERTIFICATE" {
+		return "", errors.New("no PEM encoded certificate found")
+	}
+	sum := sha256.Sum256(b.Bytes)
+	hexBytes := make([]string, len(sum))
+	for i, v := range sum {
+		hexBytes[i] = fmt.Sprintf("%02X", v)
+	}
+	return strings.Join(hexBytes, ":"), nil
+}
+
+// NodeCerts returns the certificate and key of the node stored in the lab CA directory,
+// the certificate is issued by the lab CA backend if it doesn't exist yet
+func NodeCerts(n *types.NodeConfig, configName, labCADir, labCARoot string) (*Certificates, error) {
+	// retrieve node certificates
+	nodeCerts, err := RetrieveNodeCertData(n, labCADir)
+	if err == nil && nodeCerts != nil {
+		return nodeCerts, nil
+	}
+	// if not available on disk, create cert
+	nodeCerts, err = labCA.SignNodeCert(NodeCertInput(n, configName), labCADir, labCARoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate certificates for node %s: %v", n.ShortName, err)
+	}
+	log.Debugf("%s CSR: %s", n.ShortName, string(nodeCerts.Csr))
+	log.Debugf("%s Cert: %s", n.ShortName, string(nodeCerts.Cert))
+	log.Debugf("%s Key: %s", n.ShortName, string(nodeCerts.Key))
+	return nodeCerts, nil
+}
+
+// InstallNodeCerts writes the node certificate, key, certificate chain and the lab root CA certificate
+// to the dir directory as <node>.pem, <node>-key.pem, <node>-chain.pem and ca.pem files
+func InstallNodeCerts(n *types.NodeConfig, configName, labCADir, labCARoot, dir string) error {
+	nodeCerts, err := NodeCerts(n, configName, labCADir, labCARoot)
+	if err != nil {
+		return err
+	}
+	n.TLSCert = string(nodeCerts.CertChain)
+	n.TLSKey = string(nodeCerts.Key)
+
+	utils.CreateDirectory(dir, 0755)
+	if err := utils.CreateFile(filepath.Join(dir, n.ShortName+".pem"), string(nodeCerts.Cert)); err != nil {
+		return err
+	}
+	if err := utils.CreateFile(filepath.Join(dir, n.ShortName+"-key.pem"), string(nodeCerts.Key)); err != nil {
+		return err
+	}
+	if err := utils.CreateFile(filepath.Join(dir, n.ShortName+"-chain.pem"), string(nodeCerts.CertChain)); err != nil {
+		return err
+	}
+	return utils.CopyFile(filepath.Join(labCARoot, "root-ca.pem"), filepath.Join(dir, "ca.pem"))
+}
+
 func writeCertFiles(certs *Certificates, filesPrefix string) {
 	utils.CreateFile(filesPrefix+".pem", string(certs.Cert))
 	utils.CreateFile(filesPrefix+"-key.pem", string(certs.Key))
 	utils.CreateFile(filesPrefix+".csr", string(certs.Csr))
 }
 
-//CreateRootCA creates RootCA key/certificate if it is needed by the topology
+//CreateRootCA creates RootCA key/certificate if it is needed by the topology.
+//The root CA is provided by the lab CA backend
 func CreateRootCA(configName, labCARoot string, ns map[string]nodes.Node) error {
 	rootCANeeded := false
-	// check if srl kinds defined in topo
+	// check if srl kinds or nodes with tls enabled defined in topo
 	// for them we need to create rootCA and certs
 	for _, n := range ns {
-		if n.Config().Kind == "srl" {
+		if NeedsCert(n.Config()) {
 			rootCANeeded = true
 			break
 		}
@@ -247,28 +362,11 @@ func CreateRootCA(configName, labCARoot string, ns map[string]nodes.Node) error
 		return nil
 	}
 
-	var rootCaCertPath = filepath.Join(labCARoot, "root-ca.pem")
-	var rootCaKeyPath = filepath.Join(labCARoot, "root-ca-key.pem")
-
-	var rootCaCertExists = false
-	var rootCaKeyExists = false
-
-	_, err := os.Stat(rootCaCertPath)
-	if err == nil {
-		rootCaCertExists = true
-	}
-	_, err = os.Stat(rootCaKeyPath)
-	if err == nil {
-		rootCaKeyExists = true
-	}
-	// if both files exist skip root CA creation
-	if rootCaCertExists && rootCaKeyExists {
-		rootCANeeded = false
-	}
-	if !rootCANeeded {
-		return nil
-	}
+	return labCA.GenerateRootCA(configName, labCARoot)
+}
 
+// generateRootCA generates the lab root CA key/certificate in the labCARoot directory
+func generateRootCA(configName, labCARoot string) error {
 	tpl, err := template.New("ca-csr").Parse(rootCACSRTempl)
 	if err != nil {
 		return fmt.Errorf("failed to parse Root CA CSR Template: %v", err)
@@ -0,0 +1,192 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cert
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestNodeCertInput(t *testing.T) {
+	tests := map[string]struct {
+		node *types.NodeConfig
+		want CertInput
+	}{
+		"defaults": {
+			node: &types.NodeConfig{ShortName: "srl1", LongName: "clab-lab-srl1", Fqdn: "srl1.lab.io"},
+			want: CertInput{
+				Hosts:            []string{"srl1", "clab-lab-srl1", "srl1.lab.io"},
+				CommonName:       "srl1.lab.io",
+				Country:          "BE",
+				Locality:         "Antwerp",
+				Organization:     "Nokia",
+				OrganizationUnit: "Container lab",
+				Name:             "srl1",
+				LongName:         "clab-lab-srl1",
+				Fqdn:             "srl1.lab.io",
+				Prefix:           "lab",
+			},
+		},
+		"custom": {
+			node: &types.NodeConfig{
+				ShortName:       "srl1",
+				LongName:        "clab-lab-srl1",
+				Fqdn:            "srl1.lab.io",
+				MgmtIPv4Address: "172.20.20.2",
+				MgmtIPv6Address: "2001:172:20:20::2",
+				Certificate: &types.CertificateConfig{
+					CommonName:   "srl1.example.com",
+					Organization: "Example",
+					Expiry:       "720h",
+					SANs:         []string{"srl1.example.com", "10.0.0.1", "srl1"},
+				},
+			},
+			want: CertInput{
+				Hosts: []string{"srl1", "clab-lab-srl1", "srl1.lab.io", "172.20.20.2", "2001:172:20:20::2",
+					"srl1.example.com", "10.0.0.1"},
+				CommonName:       "srl1.example.com",
+				Country:          "BE",
+				Locality:         "Antwerp",
+				Organization:     "Example",
+				OrganizationUnit: "Container lab",
+				Expiry:           "720h",
+				Name:             "srl1",
+				LongName:         "clab-lab-srl1",
+				Fqdn:             "srl1.lab.io",
+				Prefix:           "lab",
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := NodeCertInput(tc.node, "lab")
+			if !cmp.Equal(got, tc.want) {
+				t.Errorf("diff (-want +got):\n%s", cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
+
+func TestGenerateNodeCert(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clab-cert")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	caTpl := template.Must(template.New("ca-csr").Parse(rootCACSRTempl))
+	if _, err := GenerateRootCa(dir, caTpl, CaRootInput{Prefix: "lab", NamePrefix: "root-ca"}); err != nil {
+		t.Fatal(err)
+	}
+
+	input := NodeCertInput(&types.NodeConfig{
+		ShortName:       "srl1",
+		LongName:        "clab-lab-srl1",
+		MgmtIPv4Address: "172.20.20.2",
+		Certificate:     &types.CertificateConfig{Expiry: "720h", SANs: []string{"srl1.example.com"}},
+	}, "lab")
+	tpl := template.Must(template.New("node-cert").Parse(NodeCSRTempl))
+	certs, err := GenerateCert(filepath.Join(dir, "root-ca.pem"), filepath.Join(dir, "root-ca-key.pem"),
+		tpl, input, filepath.Join(dir, "srl1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, _ := pem.Decode(certs.Cert)
+	c, err := x509.ParseCertificate(b.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantDNS := []string{"srl1", "clab-lab-srl1", "srl1.example.com"}
+	if !cmp.Equal(c.DNSNames, wantDNS) {
+		t.Errorf("DNS SANs diff (-want +got):\n%s", cmp.Diff(wantDNS, c.DNSNames))
+	}
+	if len(c.IPAddresses) != 1 || !c.IPAddresses[0].Equal(net.ParseIP("172.20.20.2")) {
+		t.Errorf("unexpected IP SANs %v", c.IPAddresses)
+	}
+	if v := c.NotAfter.Sub(c.NotBefore); v > 721*time.Hour || v < 719*time.Hour {
+		t.Errorf("unexpected certificate validity %s", v)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	dir := t.TempDir()
+	caTpl := template.Must(template.New("ca-csr").Parse(rootCACSRTempl))
+	certs, err := GenerateRootCa(dir, caTpl, CaRootInput{Prefix: "lab", NamePrefix: "root-ca"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Fingerprint(certs.Cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := pem.Decode(certs.Cert)
+	sum := sha256.Sum256(b.Bytes)
+	// 32 hex bytes separated by colons
+	want := strings.ToUpper(hex.EncodeToString(sum[:]))
+	if len(got) != 95 || strings.ReplaceAll(got, ":", "") != want {
+		t.Errorf("got fingerprint %q, want colon separated %q", got, want)
+	}
+
+	if _, err := Fingerprint(certs.Key); err == nil {
+		t.Error("wanted error for the PEM data without certificate")
+	}
+}
+
+func TestInstallNodeCerts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clab-cert")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	labCA := filepath.Join(dir, "ca")
+	labCARoot := filepath.Join(labCA, "root")
+	caTpl := template.Must(template.New("ca-csr").Parse(rootCACSRTempl))
+	if _, err := GenerateRootCa(labCARoot, caTpl, CaRootInput{Prefix: "lab", NamePrefix: "root-ca"}); err != nil {
+		t.Fatal(err)
+	}
+
+	n := &types.NodeConfig{ShortName: "ceos1", LongName: "clab-lab-ceos1", TLS: true}
+	tlsDir := filepath.Join(dir, "ceos1", "tls")
+	if err := InstallNodeCerts(n, "lab", labCA, labCARoot, tlsDir); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []string{"ceos1.pem", "ceos1-key.pem", "ca.pem"} {
+		if _, err := os.Stat(filepath.Join(tlsDir, f)); err != nil {
+			t.Errorf("file %s is not installed: %v", f, err)
+		}
+	}
+	if n.TLSCert == "" || n.TLSKey == "" {
+		t.Errorf("node TLS certificate and key are not set")
+	}
+
+	// certificate stored in the lab CA directory is reused
+	stored, err := ioutil.ReadFile(filepath.Join(labCA, "ceos1", "ceos1.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	certs, err := NodeCerts(n, "lab", labCA, labCARoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(certs.Cert) != string(stored) {
+		t.Errorf("stored node certificate is not reused")
+	}
+}
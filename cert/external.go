@@ -0,0 +1,98 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cert
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// IsPEM returns true if s holds PEM encoded data rather than a path to a file
+func IsPEM(s string) bool {
+	return strings.Contains(s, "-----BEGIN ")
+}
+
+// readPEM returns PEM data provided inline or read from the file by path s
+func readPEM(s string) ([]byte, error) {
+	if IsPEM(s) {
+		return []byte(strings.TrimSpace(s) + "\n"), nil
+	}
+	return ioutil.ReadFile(s)
+}
+
+// LoadExternalCA reads the certificate and key of an external CA
+// and verifies that the key matches the certificate and the certificate is a valid CA certificate
+func LoadExternalCA(ca *types.CAConfig) (*Certificates, error) {
+	if ca.Cert == "" || ca.Key == "" {
+		return nil, errors.New("both cert and key of the external CA must be provided")
+	}
+	var err error
+	certs := &Certificates{}
+	if certs.Cert, err = readPEM(ca.Cert); err != nil {
+		return nil, fmt.Errorf("failed to read external CA certificate: %v", err)
+	}
+	if certs.Key, err = readPEM(ca.Key); err != nil {
+		return nil, fmt.Errorf("failed to read external CA key: %v", err)
+	}
+
+	if _, err := tls.X509KeyPair(certs.Cert, certs.Key); err != nil {
+		return nil, fmt.Errorf("invalid external CA certificate/key pair: %v", err)
+	}
+	// X509KeyPair verified the first block is a certificate
+	b, _ := pem.Decode(certs.Cert)
+	c, err := x509.ParseCertificate(b.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse external CA certificate: %v", err)
+	}
+	if !c.IsCA {
+		return nil, fmt.Errorf("external certificate %q is not a CA certificate", c.Subject.CommonName)
+	}
+	if time.Now().After(c.NotAfter) {
+		return nil, fmt.Errorf("external CA certificate %q expired on %s", c.Subject.CommonName, c.NotAfter.Format(time.RFC3339))
+	}
+	return certs, nil
+}
+
+// useExternalCA puts the external CA certificate and key to the lab root CA directory
+// so that the node certificates are signed by the external CA.
+// Node certificates signed by a different CA are removed to get them re-issued
+func useExternalCA(labCARoot string, ca *types.CAConfig) error {
+	certs, err := LoadExternalCA(ca)
+	if err != nil {
+		return err
+	}
+	rootCaCertPath := filepath.Join(labCARoot, "root-ca.pem")
+
+	if cur, err := ioutil.ReadFile(rootCaCertPath); err == nil {
+		if bytes.Equal(bytes.TrimSpace(cur), bytes.TrimSpace(certs.Cert)) {
+			return nil
+		}
+		log.Infof("Lab CA differs from the external CA, node certificates will be re-issued")
+		if err := removeIssuedCerts(labCARoot); err != nil {
+			return err
+		}
+	}
+
+	log.Infof("Using external CA to sign node certificates")
+	if err := os.MkdirAll(labCARoot, 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(rootCaCertPath, certs.Cert, 0644); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(labCARoot, "root-ca-key.pem"), certs.Key, 0600)
+}
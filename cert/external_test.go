@@ -0,0 +1,66 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cert
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestLoadExternalCA(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clab-ca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tpl := template.Must(template.New("ca-csr").Parse(rootCACSRTempl))
+	ca, err := GenerateRootCa(dir, tpl, CaRootInput{Prefix: "ext", NamePrefix: "ca"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := GenerateRootCa(dir, tpl, CaRootInput{Prefix: "other", NamePrefix: "other"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		ca      *types.CAConfig
+		wantErr bool
+	}{
+		"files": {
+			ca: &types.CAConfig{Cert: filepath.Join(dir, "ca.pem"), Key: filepath.Join(dir, "ca-key.pem")},
+		},
+		"pem": {
+			ca: &types.CAConfig{Cert: string(ca.Cert), Key: string(ca.Key)},
+		},
+		"mismatched_key": {
+			ca:      &types.CAConfig{Cert: string(ca.Cert), Key: string(other.Key)},
+			wantErr: true,
+		},
+		"missing_key": {
+			ca:      &types.CAConfig{Cert: string(ca.Cert)},
+			wantErr: true,
+		},
+		"missing_file": {
+			ca:      &types.CAConfig{Cert: filepath.Join(dir, "absent.pem"), Key: filepath.Join(dir, "ca-key.pem")},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadExternalCA(tc.ca)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("got error %v, want error %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cert
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// importPrefix is the prefix of the names the node certificates are imported with to the NOS PKI
+const importPrefix = "clab"
+
+// ImportBundle holds the node certificate prepared for the import to the PKI of a NOS,
+// e.g. the trustpoint of a vrnetlab VM
+type ImportBundle struct {
+	// PEM certificate of the node
+	Cert string
+	// PEM certificate of the CA which signed the node certificate
+	CA string
+	// PEM private key of the node encrypted with KeyPassword, as the NOSes import the encrypted keys only
+	Key         string
+	KeyPassword string
+	// name of the certificate in the NOS PKI, unique per certificate serial,
+	// so that a renewed certificate is imported next to the one in use
+	Name string
+}
+
+// NewImportBundle prepares the node certificates for the import to the NOS PKI
+func NewImportBundle(nodeCerts *Certificates, labCARoot string) (*ImportBundle, error) {
+	ca, err := issuerCert(nodeCerts.CertChain, filepath.Join(labCARoot, "root-ca.pem"))
+	if err != nil {
+		return nil, err
+	}
+	res := &ImportBundle{Cert: strings.TrimSpace(string(nodeCerts.Cert)), CA: ca}
+	if res.Name, err = importName(nodeCerts.Cert); err != nil {
+		return nil, err
+	}
+	if res.Key, res.KeyPassword, err = encryptKey(nodeCerts.Key); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// issuerCert returns the certificate of the issuer of the first certificate of the chain,
+// the root CA certificate read from rootFile is the issuer of the chains without the intermediate CAs
+func issuerCert(chain []byte, rootFile string) (string, error) {
+	_, rest := pem.Decode(chain)
+	if b, _ := pem.Decode(rest); b != nil {
+		return strings.TrimSpace(string(pem.EncodeToMemory(b))), nil
+	}
+	root, err := utils.ReadFileContent(rootFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read lab root CA certificate: %v", err)
+	}
+	return strings.TrimSpace(string(root)), nil
+}
+
+// importName returns the import name of the PEM certificate made of the prefix and its serial number
+func importName(certPEM []byte) (string, error) {
+	b, _ := pem.Decode(certPEM)
+	if b == nil {
+		return "", fmt.Errorf("failed to decode node certificate")
+	}
+	c, err := x509.ParseCertificate(b.Bytes)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse node certificate: %v", err)
+	}
+	serial := hex.EncodeToString(c.SerialNumber.Bytes())
+	if len(serial) > 8 {
+		serial = serial[len(serial)-8:]
+	}
+	return importPrefix + "-" + serial, nil
+}
+
+// encryptKey encrypts the PEM private key with a random password and returns the encrypted key and the password
+func encryptKey(keyPEM []byte) (string, string, error) {
+	b, _ := pem.Decode(keyPEM)
+	if b == nil {
+		return "", "", fmt.Errorf("failed to decode node key")
+	}
+	pw := make([]byte, 12)
+	if _, err := rand.Read(pw); err != nil {
+		return "", "", err
+	}
+	password := hex.EncodeToString(pw)
+	// the NOSes import the keys in the legacy encrypted PEM format only
+	enc, err := x509.EncryptPEMBlock(rand.Reader, b.Type, b.Bytes, []byte(password), x509.PEMCipher3DES) // skipcq: GSC-G401
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encrypt node key: %v", err)
+	}
+	return strings.TrimSpace(string(pem.EncodeToMemory(enc))), password, nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cert
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestNewImportBundle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clab-cert")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	labCA := filepath.Join(dir, "ca")
+	labCARoot := filepath.Join(labCA, "root")
+	caTpl := template.Must(template.New("ca-csr").Parse(rootCACSRTempl))
+	if _, err := GenerateRootCa(labCARoot, caTpl, CaRootInput{Prefix: "lab", NamePrefix: "root-ca"}); err != nil {
+		t.Fatal(err)
+	}
+	n := &types.NodeConfig{ShortName: "csr1", LongName: "clab-lab-csr1", Kind: "vr-csr", TLS: true}
+	nodeCerts, err := NodeCerts(n, "lab", labCA, labCARoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := NewImportBundle(nodeCerts, labCARoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := ioutil.ReadFile(filepath.Join(labCARoot, "root-ca.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.CA != strings.TrimSpace(string(root)) {
+		t.Errorf("CA certificate is not the lab root CA certificate")
+	}
+	if err := verifyCert(root, []byte(b.Cert)); err != nil {
+		t.Errorf("node certificate is not signed by the lab root CA: %v", err)
+	}
+	if !strings.HasPrefix(b.Name, importPrefix+"-") || len(b.Name) > len(importPrefix)+9 {
+		t.Errorf("unexpected import name %q", b.Name)
+	}
+
+	block, _ := pem.Decode([]byte(b.Key))
+	if block == nil || !x509.IsEncryptedPEMBlock(block) {
+		t.Fatalf("key is not an encrypted PEM block")
+	}
+	der, err := x509.DecryptPEMBlock(block, []byte(b.KeyPassword))
+	if err != nil {
+		t.Fatalf("failed to decrypt key: %v", err)
+	}
+	orig, _ := pem.Decode(nodeCerts.Key)
+	if string(der) != string(orig.Bytes) {
+		t.Errorf("decrypted key doesn't match the node key")
+	}
+}
+
+func TestIssuerCert(t *testing.T) {
+	leaf := "-----BEGIN CERTIFICATE-----\nbGVhZg==\n-----END CERTIFICATE-----"
+	inter := "-----BEGIN CERTIFICATE-----\naW50ZXI=\n-----END CERTIFICATE-----"
+	root := filepath.Join(t.TempDir(), "root-ca.pem")
+	if err := ioutil.WriteFile(root, []byte("root\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		chain string
+		want  string
+	}{
+		"signed-by-root":         {chain: leaf + "\n", want: "root"},
+		"signed-by-intermediate": {chain: leaf + "\n" + inter + "\n", want: inter},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := issuerCert([]byte(tc.chain), root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("got: %q, want: %q", got, tc.want)
+			}
+		})
+	}
+}
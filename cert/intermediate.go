@@ -0,0 +1,229 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cert
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"text/template"
+	"time"
+
+	"github.com/cloudflare/cfssl/cli/genkey"
+	"github.com/cloudflare/cfssl/config"
+	"github.com/cloudflare/cfssl/csr"
+	"github.com/cloudflare/cfssl/signer"
+	"github.com/cloudflare/cfssl/signer/universal"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+const (
+	// name of the lab CA directory holding the intermediate CA
+	intermediateDir = "intermediate"
+	// default validity of the intermediate CA, shorter than the root CA validity
+	intermediateExpiry = "87600h"
+)
+
+var intermediateCACSRTempl string = `{
+    "CN": "{{.Prefix}} Intermediate CA",
+    "key": {
+       "algo": "rsa",
+       "size": 2048
+    },
+    "names": [{
+       "C": "BE",
+       "L": "Antwerp",
+       "O": "Nokia",
+       "OU": "Container lab"
+    }],
+    "ca": {
+       "expiry": "{{.Expiry}}",
+       "pathlen": 0,
+       "pathlenzero": true
+    }
+}
+`
+
+// GenerateIntermediateCa generates the intermediate CA key/certificate signed by the CA
+// passed as ca and caKey file paths and saves them to the labCAIntermediate directory.
+// Intermediate CA can only sign leaf certificates
+func GenerateIntermediateCa(ca, caKey, labCAIntermediate string, csrJSONTpl *template.Template, input CaRootInput) (*Certificates, error) {
+	log.Info("Creating intermediate CA")
+	utils.CreateDirectory(labCAIntermediate, 0755)
+	if input.Expiry == "" {
+		input.Expiry = intermediateExpiry
+	}
+	expiry, err := time.ParseDuration(input.Expiry)
+	if err != nil {
+		return nil, fmt.Errorf("invalid intermediate CA expiry %q: %v", input.Expiry, err)
+	}
+
+	csrBuff := new(bytes.Buffer)
+	if err := csrJSONTpl.Execute(csrBuff, input); err != nil {
+		return nil, err
+	}
+	req := &csr.CertificateRequest{
+		KeyRequest: csr.NewKeyRequest(),
+	}
+	if err := json.Unmarshal(csrBuff.Bytes(), req); err != nil {
+		return nil, err
+	}
+
+	gen := &csr.Generator{Validator: genkey.Validator}
+	csrBytes, key, err := gen.ProcessRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := config.DefaultConfig()
+	profile.Usage = []string{"cert sign", "crl sign"}
+	profile.Expiry = expiry
+	profile.ExpiryString = input.Expiry
+	profile.CAConstraint = config.CAConstraint{IsCA: true, MaxPathLen: 0, MaxPathLenZero: true}
+	policy := &config.Signing{
+		Profiles: map[string]*config.SigningProfile{},
+		Default:  profile,
+	}
+	root := universal.Root{
+		Config: map[string]string{
+			"cert-file": ca,
+			"key-file":  caKey,
+		},
+	}
+	s, err := universal.NewSigner(root, policy)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := s.Sign(signer.SignRequest{Request: string(csrBytes)})
+	if err != nil {
+		return nil, err
+	}
+
+	certs := &Certificates{
+		Key:  key,
+		Csr:  csrBytes,
+		Cert: cert,
+	}
+	writeCertFiles(certs, filepath.Join(labCAIntermediate, input.NamePrefix))
+	return certs, nil
+}
+
+// signingCA returns the paths to the certificate and key of the CA signing the node certificates,
+// which is the intermediate CA when it exists in the lab CA directory and the root CA otherwise
+func signingCA(labCADir, labCARoot string) (caCert, caKey string, intermediate bool) {
+	dir := filepath.Join(labCADir, intermediateDir)
+	caCert = filepath.Join(dir, "intermediate-ca.pem")
+	caKey = filepath.Join(dir, "intermediate-ca-key.pem")
+	if utils.FileExists(caCert) && utils.FileExists(caKey) {
+		return caCert, caKey, true
+	}
+	return filepath.Join(labCARoot, "root-ca.pem"), filepath.Join(labCARoot, "root-ca-key.pem"), false
+}
+
+// certChain returns the node certificate followed by the intermediate CA certificate if it is used
+func certChain(cert []byte, labCADir, labCARoot string) ([]byte, error) {
+	caCert, _, intermediate := signingCA(labCADir, labCARoot)
+	if !intermediate {
+		return cert, nil
+	}
+	ca, err := ioutil.ReadFile(caCert)
+	if err != nil {
+		return nil, err
+	}
+	chain := append(bytes.TrimSpace(cert), '\n')
+	return append(chain, bytes.TrimSpace(ca)...), nil
+}
+
+// syncIntermediateCA creates the intermediate CA when it is enabled and doesn't exist or isn't signed by the lab root CA,
+// and removes it when it is disabled. Node certificates are removed when the signing CA changes to get them re-issued
+func syncIntermediateCA(configName, labCARoot string, enabled bool) error {
+	labCADir := filepath.Dir(labCARoot)
+	dir := filepath.Join(labCADir, intermediateDir)
+	_, _, exists := signingCA(labCADir, labCARoot)
+	if exists {
+		if err := verifyIssuer(filepath.Join(dir, "intermediate-ca.pem"), filepath.Join(labCARoot, "root-ca.pem")); err != nil {
+			log.Infof("Intermediate CA is not signed by the lab root CA: %v", err)
+			exists = false
+		}
+	}
+	if enabled == exists {
+		return nil
+	}
+
+	log.Infof("Lab signing CA changed, node certificates will be re-issued")
+	if err := removeIssuedCerts(labCARoot); err != nil {
+		return err
+	}
+	if !enabled {
+		return nil
+	}
+
+	tpl, err := template.New("intermediate-ca-csr").Parse(intermediateCACSRTempl)
+	if err != nil {
+		return fmt.Errorf("failed to parse Intermediate CA CSR Template: %v", err)
+	}
+	certs, err := GenerateIntermediateCa(
+		filepath.Join(labCARoot, "root-ca.pem"),
+		filepath.Join(labCARoot, "root-ca-key.pem"),
+		dir,
+		tpl,
+		CaRootInput{Prefix: configName, NamePrefix: "intermediate-ca"},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to generate intermediate CA: %v", err)
+	}
+	log.Debugf("intermediate Cert: %s", string(certs.Cert))
+	return nil
+}
+
+// verifyIssuer verifies that the certificate by path cert is signed by the CA certificate by path ca
+func verifyIssuer(cert, ca string) error {
+	parse := func(p string) (*x509.Certificate, error) {
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		blk, _ := pem.Decode(b)
+		if blk == nil {
+			return nil, fmt.Errorf("failed to decode certificate %s", p)
+		}
+		return x509.ParseCertificate(blk.Bytes)
+	}
+	c, err := parse(cert)
+	if err != nil {
+		return err
+	}
+	parent, err := parse(ca)
+	if err != nil {
+		return err
+	}
+	return c.CheckSignatureFrom(parent)
+}
+
+// removeIssuedCerts removes the node certificates and the intermediate CA from the lab CA directory,
+// the root CA is kept
+func removeIssuedCerts(labCARoot string) error {
+	labCADir := filepath.Dir(labCARoot)
+	entries, err := ioutil.ReadDir(labCADir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		p := filepath.Join(labCADir, e.Name())
+		if p == filepath.Clean(labCARoot) {
+			continue
+		}
+		if err := os.RemoveAll(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
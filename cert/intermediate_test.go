@@ -0,0 +1,117 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cert
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestIntermediateCA(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clab-cert")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	labCA := filepath.Join(dir, "ca")
+	labCARoot := filepath.Join(labCA, "root")
+	caTpl := template.Must(template.New("ca-csr").Parse(rootCACSRTempl))
+	if _, err := GenerateRootCa(labCARoot, caTpl, CaRootInput{Prefix: "lab", NamePrefix: "root-ca"}); err != nil {
+		t.Fatal(err)
+	}
+	n := &types.NodeConfig{ShortName: "srl1", LongName: "clab-lab-srl1", Kind: "srl"}
+	if _, err := NodeCerts(n, "lab", labCA, labCARoot); err != nil {
+		t.Fatal(err)
+	}
+
+	// node certificate signed by the root CA is re-issued by the intermediate CA
+	if err := syncIntermediateCA("lab", labCARoot, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(labCA, "srl1")); !os.IsNotExist(err) {
+		t.Errorf("node certificate signed by the root CA is not removed")
+	}
+
+	ica := parseCerts(t, filepath.Join(labCA, "intermediate", "intermediate-ca.pem"))[0]
+	if !ica.IsCA || ica.MaxPathLen != 0 || !ica.MaxPathLenZero {
+		t.Errorf("intermediate CA has unexpected constraints: IsCA=%v, MaxPathLen=%d", ica.IsCA, ica.MaxPathLen)
+	}
+
+	certs, err := NodeCerts(n, "lab", labCA, labCARoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain := parseCertsPEM(t, certs.CertChain)
+	if len(chain) != 2 {
+		t.Fatalf("expected certificate chain of 2 certificates, got %d", len(chain))
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(parseCerts(t, filepath.Join(labCARoot, "root-ca.pem"))[0])
+	inter := x509.NewCertPool()
+	inter.AddCert(chain[1])
+	if _, err := chain[0].Verify(x509.VerifyOptions{Roots: roots, Intermediates: inter, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}}); err != nil {
+		t.Errorf("node certificate chain doesn't verify with the root CA: %v", err)
+	}
+	if _, err := chain[0].Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}}); err == nil {
+		t.Errorf("node certificate is signed by the root CA")
+	}
+
+	// existing intermediate CA is kept
+	if err := syncIntermediateCA("lab", labCARoot, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(labCA, "srl1")); err != nil {
+		t.Errorf("node certificate is removed with unchanged intermediate CA")
+	}
+
+	// disabled intermediate CA is removed along with the node certificates
+	if err := syncIntermediateCA("lab", labCARoot, false); err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []string{"intermediate", "srl1"} {
+		if _, err := os.Stat(filepath.Join(labCA, d)); !os.IsNotExist(err) {
+			t.Errorf("%s directory is not removed", d)
+		}
+	}
+	certs, err = NodeCerts(n, "lab", labCA, labCARoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(certs.CertChain) != string(certs.Cert) {
+		t.Errorf("certificate chain without intermediate CA should only hold the node certificate")
+	}
+}
+
+func parseCerts(t *testing.T, p string) []*x509.Certificate {
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parseCertsPEM(t, b)
+}
+
+func parseCertsPEM(t *testing.T, b []byte) []*x509.Certificate {
+	var certs []*x509.Certificate
+	for {
+		var blk *pem.Block
+		blk, b = pem.Decode(b)
+		if blk == nil {
+			return certs
+		}
+		c, err := x509.ParseCertificate(blk.Bytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		certs = append(certs, c)
+	}
+}
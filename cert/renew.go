@@ -0,0 +1,43 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cert
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// NeedsCert returns true if the node uses a certificate signed by the lab root CA
+func NeedsCert(n *types.NodeConfig) bool {
+	return n.Kind == nodes.NodeKindSRL || n.TLS
+}
+
+// RenewNodeCerts removes the node certificate stored in the lab CA directory
+// and signs a new one with the lab root CA
+func RenewNodeCerts(n *types.NodeConfig, configName, labCADir, labCARoot string) (*Certificates, error) {
+	if err := os.RemoveAll(filepath.Join(labCADir, n.ShortName)); err != nil {
+		return nil, fmt.Errorf("failed to remove certificate of node %s: %v", n.ShortName, err)
+	}
+	return NodeCerts(n, configName, labCADir, labCARoot)
+}
+
+// RenewRootCA replaces the lab root CA with the one provided by the lab CA backend,
+// the local backend generates a new root CA unless the external CA is used.
+// The intermediate CA is re-issued by the renewed root CA when it is used
+func RenewRootCA(configName, labCARoot string) error {
+	if err := os.RemoveAll(filepath.Join(filepath.Dir(labCARoot), intermediateDir)); err != nil {
+		return fmt.Errorf("failed to remove intermediate CA: %v", err)
+	}
+	for _, f := range []string{"root-ca.pem", "root-ca-key.pem", "root-ca.csr"} {
+		if err := os.Remove(filepath.Join(labCARoot, f)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove root CA file: %v", err)
+		}
+	}
+	return labCA.GenerateRootCA(configName, labCARoot)
+}
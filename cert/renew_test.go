@@ -0,0 +1,114 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cert
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestRenewNodeCerts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clab-cert")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	labCA := filepath.Join(dir, "ca")
+	labCARoot := filepath.Join(labCA, "root")
+	caTpl := template.Must(template.New("ca-csr").Parse(rootCACSRTempl))
+	if _, err := GenerateRootCa(labCARoot, caTpl, CaRootInput{Prefix: "lab", NamePrefix: "root-ca"}); err != nil {
+		t.Fatal(err)
+	}
+
+	n := &types.NodeConfig{ShortName: "srl1", LongName: "clab-lab-srl1", Kind: "srl"}
+	old, err := NodeCerts(n, "lab", labCA, labCARoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	renewed, err := RenewNodeCerts(n, "lab", labCA, labCARoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(renewed.Cert) == string(old.Cert) || string(renewed.Key) == string(old.Key) {
+		t.Errorf("node certificate is not renewed")
+	}
+	stored, err := ioutil.ReadFile(filepath.Join(labCA, "srl1", "srl1.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(string(stored)) != strings.TrimSpace(string(renewed.Cert)) {
+		t.Errorf("renewed certificate is not stored in the lab CA directory")
+	}
+
+	// root CA is replaced, node certificates are signed by the new root CA
+	oldRoot, err := ioutil.ReadFile(filepath.Join(labCARoot, "root-ca.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RenewRootCA("lab", labCARoot); err != nil {
+		t.Fatal(err)
+	}
+	newRoot, err := ioutil.ReadFile(filepath.Join(labCARoot, "root-ca.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(newRoot) == string(oldRoot) {
+		t.Errorf("root CA is not renewed")
+	}
+	renewed, err = RenewNodeCerts(n, "lab", labCA, labCARoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := verifyCert(newRoot, renewed.Cert); err != nil {
+		t.Errorf("node certificate is not signed by the renewed root CA: %v", err)
+	}
+}
+
+// verifyCert verifies that the certificate is signed by the root CA
+func verifyCert(root, cert []byte) error {
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(root) {
+		return fmt.Errorf("failed to parse root CA")
+	}
+	b, _ := pem.Decode(cert)
+	if b == nil {
+		return fmt.Errorf("failed to decode certificate")
+	}
+	c, err := x509.ParseCertificate(b.Bytes)
+	if err != nil {
+		return err
+	}
+	_, err = c.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}})
+	return err
+}
+
+func TestNeedsCert(t *testing.T) {
+	tests := map[string]struct {
+		node *types.NodeConfig
+		want bool
+	}{
+		"srl":      {node: &types.NodeConfig{Kind: "srl"}, want: true},
+		"tls":      {node: &types.NodeConfig{Kind: "ceos", TLS: true}, want: true},
+		"no_certs": {node: &types.NodeConfig{Kind: "linux"}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := NeedsCert(tc.node); got != tc.want {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cert
+
+import (
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// StepCA issues the lab certificates with the step-ca server using the step CLI
+type StepCA struct {
+	cfg *types.StepCAConfig
+	// path to the step CLI
+	step string
+}
+
+// NewStepCA returns the step-ca backend
+func NewStepCA(c *types.StepCAConfig) (*StepCA, error) {
+	switch {
+	case c == nil || c.URL == "":
+		return nil, errors.New("step-ca url is not set")
+	case c.Fingerprint == "":
+		return nil, errors.New("step-ca root certificate fingerprint is not set")
+	case c.Provisioner == "":
+		return nil, errors.New("step-ca provisioner is not set")
+	}
+	step, err := exec.LookPath("step")
+	if err != nil {
+		return nil, errors.New("step CLI is required to use step-ca certificate authority backend")
+	}
+	return &StepCA{cfg: c, step: step}, nil
+}
+
+func (s *StepCA) run(args ...string) error {
+	log.Debugf("running step %s", strings.Join(args, " "))
+	out, err := exec.Command(s.step, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("step %s failed: %v: %s", args[0]+" "+args[1], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// GenerateRootCA downloads the root certificate of the step-ca server to the labCARoot directory
+func (s *StepCA) GenerateRootCA(_, labCARoot string) error {
+	log.Infof("Retrieving root certificate from step-ca %s", s.cfg.URL)
+	if err := os.MkdirAll(labCARoot, 0755); err != nil {
+		return err
+	}
+	return s.run("ca", "root", filepath.Join(labCARoot, "root-ca.pem"),
+		"--ca-url", s.cfg.URL, "--fingerprint", s.cfg.Fingerprint, "--force")
+}
+
+// certificateArgs returns the step CLI arguments to issue the node certificate
+// to the crt and key files
+func (s *StepCA) certificateArgs(input CertInput, root, crt, key string) []string {
+	args := []string{"ca", "certificate", input.CommonName, crt, key,
+		"--ca-url", s.cfg.URL,
+		"--root", root,
+		"--provisioner", s.cfg.Provisioner,
+		"--force",
+	}
+	if s.cfg.PasswordFile != "" {
+		args = append(args, "--provisioner-password-file", s.cfg.PasswordFile)
+	}
+	if input.Expiry != "" {
+		args = append(args, "--not-after", input.Expiry)
+	}
+	// step uses the common name as the only SAN when no SANs are given
+	for _, h := range append([]string{input.CommonName}, input.Hosts...) {
+		args = append(args, "--san", h)
+	}
+	return args
+}
+
+// SignNodeCert issues the node certificate with the step-ca provisioner.
+// The subject fields other than the common name are defined by the provisioner
+func (s *StepCA) SignNodeCert(input CertInput, labCADir, labCARoot string) (*Certificates, error) {
+	dir := filepath.Join(labCADir, input.Name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	// step writes the certificate followed by the intermediate CA certificate
+	crt := filepath.Join(dir, input.Name+"-chain.pem")
+	key := filepath.Join(dir, input.Name+"-key.pem")
+	if err := s.run(s.certificateArgs(input, filepath.Join(labCARoot, "root-ca.pem"), crt, key)...); err != nil {
+		return nil, err
+	}
+
+	certs := &Certificates{}
+	var err error
+	if certs.CertChain, err = ioutil.ReadFile(crt); err != nil {
+		return nil, err
+	}
+	if certs.Key, err = ioutil.ReadFile(key); err != nil {
+		return nil, err
+	}
+	b, _ := pem.Decode(certs.CertChain)
+	if b == nil {
+		return nil, fmt.Errorf("failed to decode certificate issued by step-ca")
+	}
+	certs.Cert = pem.EncodeToMemory(b)
+	return certs, utils.CreateFile(filepath.Join(dir, input.Name+".pem"), string(certs.Cert))
+}
@@ -0,0 +1,161 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cert
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// VaultCA issues the lab certificates with the Vault PKI secrets engine
+type VaultCA struct {
+	cfg    *types.VaultConfig
+	client *http.Client
+}
+
+// vaultIssueResponse is the response of the Vault PKI issue endpoint
+type vaultIssueResponse struct {
+	Errors []string `json:"errors"`
+	Data   struct {
+		Certificate string   `json:"certificate"`
+		IssuingCA   string   `json:"issuing_ca"`
+		CAChain     []string `json:"ca_chain"`
+		PrivateKey  string   `json:"private_key"`
+	} `json:"data"`
+}
+
+// NewVaultCA returns the Vault CA backend, the address and token default to
+// VAULT_ADDR and VAULT_TOKEN env vars
+func NewVaultCA(c *types.VaultConfig) (*VaultCA, error) {
+	cfg := types.VaultConfig{}
+	if c != nil {
+		cfg = *c
+	}
+	if cfg.Address == "" {
+		cfg.Address = os.Getenv("VAULT_ADDR")
+	}
+	if cfg.Token == "" {
+		cfg.Token = os.Getenv("VAULT_TOKEN")
+	}
+	if cfg.Mount == "" {
+		cfg.Mount = "pki"
+	}
+	switch {
+	case cfg.Address == "":
+		return nil, errors.New("vault address is not set")
+	case cfg.Token == "":
+		return nil, errors.New("vault token is not set")
+	case cfg.Role == "":
+		return nil, errors.New("vault role is not set")
+	}
+	cfg.Address = strings.TrimSuffix(cfg.Address, "/")
+	cfg.Mount = strings.Trim(cfg.Mount, "/")
+	return &VaultCA{cfg: &cfg, client: &http.Client{Timeout: 30 * time.Second}}, nil
+}
+
+// do sends the request to the Vault API path and returns the response body
+func (v *VaultCA) do(method, path string, body interface{}) ([]byte, error) {
+	r := bytes.NewReader(nil)
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, v.cfg.Address+"/v1/"+v.cfg.Mount+"/"+path, r)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", v.cfg.Token)
+	if v.cfg.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.cfg.Namespace)
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("vault request %s %s failed with %s: %s", method, path, resp.Status, strings.TrimSpace(string(b)))
+	}
+	return b, nil
+}
+
+// GenerateRootCA writes the CA certificate of the Vault PKI mount to the labCARoot directory
+func (v *VaultCA) GenerateRootCA(_, labCARoot string) error {
+	log.Infof("Retrieving CA certificate from Vault PKI %s", v.cfg.Mount)
+	b, err := v.do(http.MethodGet, "ca/pem", nil)
+	if err != nil {
+		return err
+	}
+	if !IsPEM(string(b)) {
+		return fmt.Errorf("vault PKI %s has no CA certificate", v.cfg.Mount)
+	}
+	if err := os.MkdirAll(labCARoot, 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(labCARoot, "root-ca.pem"), append(bytes.TrimSpace(b), '\n'), 0644)
+}
+
+// SignNodeCert issues the node certificate with the Vault PKI role.
+// The subject fields other than the common name are defined by the role
+func (v *VaultCA) SignNodeCert(input CertInput, labCADir, _ string) (*Certificates, error) {
+	var dnsSANs, ipSANs []string
+	for _, h := range input.Hosts {
+		if net.ParseIP(h) != nil {
+			ipSANs = append(ipSANs, h)
+			continue
+		}
+		dnsSANs = append(dnsSANs, h)
+	}
+	req := map[string]string{
+		"common_name": input.CommonName,
+		"alt_names":   strings.Join(dnsSANs, ","),
+		"ip_sans":     strings.Join(ipSANs, ","),
+		"format":      "pem",
+	}
+	if input.Expiry != "" {
+		req["ttl"] = input.Expiry
+	}
+	b, err := v.do(http.MethodPost, "issue/"+v.cfg.Role, req)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(vaultIssueResponse)
+	if err := json.Unmarshal(b, resp); err != nil {
+		return nil, fmt.Errorf("failed to parse vault response: %v", err)
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("vault errors: %s", strings.Join(resp.Errors, "; "))
+	}
+
+	chain := resp.Data.CAChain
+	if len(chain) == 0 && resp.Data.IssuingCA != "" {
+		chain = []string{resp.Data.IssuingCA}
+	}
+	certs := &Certificates{
+		Cert:      []byte(resp.Data.Certificate),
+		Key:       []byte(resp.Data.PrivateKey),
+		CertChain: []byte(strings.Join(append([]string{resp.Data.Certificate}, chain...), "\n")),
+	}
+	return certs, writeNodeCertFiles(certs, labCADir, input.Name)
+}
@@ -0,0 +1,137 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/srl-labs/containerlab/types"
+)
+
+// archiveTimeFormat is the format of the timestamp in the name of the lab archive
+const archiveTimeFormat = "20060102-150405"
+
+// ArchiveLab bundles the lab directory with the node configs and certificates, the topology file
+// and the inspect output of the lab containers into the timestamped tar.gz archive.
+// The archive is written next to the lab directory labDir, it returns the path to the archive
+func (c *CLab) ArchiveLab(labDir string, containers []types.GenericContainer, now time.Time) (string, error) {
+	p := fmt.Sprintf("%s-%s.tar.gz", labDir, now.Format(archiveTimeFormat))
+	f, err := os.Create(p)
+	if err != nil {
+		return "", fmt.Errorf("failed to create lab archive: %v", err)
+	}
+	defer f.Close()
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+	root := filepath.Base(labDir)
+	if err := addDirToArchive(tw, labDir, root); err != nil {
+		return "", fmt.Errorf("failed to archive lab directory %s: %v", labDir, err)
+	}
+	if c.TopoFile != nil {
+		if err := addFileToArchive(tw, c.TopoFile.path, filepath.Join(root, c.TopoFile.fullName)); err != nil {
+			return "", fmt.Errorf("failed to archive topology file: %v", err)
+		}
+	}
+	inspect := new(bytes.Buffer)
+	if err := c.WriteAccessSummary(inspect, SummaryFormatJSON, c.AccessSummary(containers)); err != nil {
+		return "", err
+	}
+	if err := addBytesToArchive(tw, inspect.Bytes(), filepath.Join(root, "inspect.json"), now); err != nil {
+		return "", err
+	}
+
+	if err := tw.Close(); err != nil {
+		return "", err
+	}
+	if err := gw.Close(); err != nil {
+		return "", err
+	}
+	return p, f.Close()
+}
+
+// addDirToArchive adds the contents of the directory dir to the archive under the prefix,
+// the symlinks are archived as links
+func addDirToArchive(tw *tar.Writer, dir, prefix string) error {
+	return filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		var link string
+		if fi.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(p); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(fi, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(filepath.Join(prefix, rel))
+		if fi.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+		return copyFileToArchive(tw, p)
+	})
+}
+
+// addFileToArchive adds the regular file p to the archive under the name
+func addFileToArchive(tw *tar.Writer, p, name string) error {
+	fi, err := os.Stat(p)
+	if err != nil {
+		return err
+	}
+	hdr, err := tar.FileInfoHeader(fi, "")
+	if err != nil {
+		return err
+	}
+	hdr.Name = filepath.ToSlash(name)
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	return copyFileToArchive(tw, p)
+}
+
+func copyFileToArchive(tw *tar.Writer, p string) error {
+	f, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// addBytesToArchive adds the file with the contents b to the archive under the name
+func addBytesToArchive(tw *tar.Writer, b []byte, name string, mtime time.Time) error {
+	hdr := &tar.Header{
+		Name:    filepath.ToSlash(name),
+		Mode:    0644,
+		Size:    int64(len(b)),
+		ModTime: mtime,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := tw.Write(b)
+	return err
+}
@@ -0,0 +1,87 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestArchiveLab(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo1.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	labDir := filepath.Join(t.TempDir(), "clab-topo1")
+	if err := os.MkdirAll(filepath.Join(labDir, "node1", "config"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(labDir, "node1", "config", "config.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("node1", filepath.Join(labDir, "link")); err != nil {
+		t.Fatal(err)
+	}
+	containers := []types.GenericContainer{
+		{Labels: map[string]string{NodeNameLabel: "node1"}, State: "running"},
+	}
+
+	now := time.Date(2021, 6, 1, 10, 20, 30, 0, time.UTC)
+	p, err := c.ArchiveLab(labDir, containers, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := labDir + "-20210601-102030.tar.gz"; p != want {
+		t.Errorf("wanted archive %s, got %s", want, p)
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gr)
+	var got []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, hdr.Name)
+		if hdr.Typeflag == tar.TypeSymlink && hdr.Linkname != "node1" {
+			t.Errorf("wanted symlink %s to node1, got %s", hdr.Name, hdr.Linkname)
+		}
+	}
+	sort.Strings(got)
+	want := []string{
+		"clab-topo1/",
+		"clab-topo1/inspect.json",
+		"clab-topo1/link",
+		"clab-topo1/node1/",
+		"clab-topo1/node1/config/",
+		"clab-topo1/node1/config/config.json",
+		"clab-topo1/topo1.yml",
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("archive contents mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// batfishConfigCmds are the commands retrieving the running configuration
+// of the containerized NOSes in the format parsed by Batfish
+var batfishConfigCmds = map[string][]string{
+	nodes.NodeKindCEOS: {"Cli", "-p", "15", "-c", "show running-config"},
+	nodes.NodeKindCRPD: {"cli", "show", "configuration"},
+}
+
+// batfishStartupConfigKinds are the vrnetlab kinds supported by Batfish.
+// Their running configuration can't be retrieved from the container,
+// so the startup configuration of such nodes is exported
+var batfishStartupConfigKinds = map[string]struct{}{
+	nodes.NodeKindVrCSR:   {},
+	nodes.NodeKindVrN9KV:  {},
+	nodes.NodeKindVrNXOS:  {},
+	nodes.NodeKindVrPAN:   {},
+	nodes.NodeKindVrVEOS:  {},
+	nodes.NodeKindVrVMX:   {},
+	nodes.NodeKindVrVQFX:  {},
+	nodes.NodeKindVrXRV:   {},
+	nodes.NodeKindVrXRV9K: {},
+}
+
+// hostIfacesCmd lists the IPv4 addresses of the linux nodes, one address per line
+var hostIfacesCmd = []string{"ip", "-o", "-4", "addr", "show"}
+
+// eosIfaceRe matches the linux interface names of the EOS nodes, e.g. eth1 or eth1_1
+var eosIfaceRe = regexp.MustCompile(`^eth(\d+(?:_\d+)*)$`)
+
+// batfishInterface is an interface of the Batfish layer1 topology
+type batfishInterface struct {
+	Hostname      string `json:"hostname"`
+	InterfaceName string `json:"interfaceName"`
+}
+
+// batfishEdge is a link of the Batfish layer1 topology
+type batfishEdge struct {
+	Node1 batfishInterface `json:"node1"`
+	Node2 batfishInterface `json:"node2"`
+}
+
+// batfishLayer1 is the Batfish layer1 topology stored in batfish/layer1_topology.json of the snapshot
+type batfishLayer1 struct {
+	Edges []batfishEdge `json:"edges"`
+}
+
+// batfishHost is the Batfish host definition stored in the hosts directory of the snapshot
+type batfishHost struct {
+	Hostname       string                           `json:"hostname"`
+	HostInterfaces map[string]*batfishHostInterface `json:"hostInterfaces"`
+}
+
+type batfishHostInterface struct {
+	Name   string `json:"name"`
+	Prefix string `json:"prefix"`
+}
+
+// batfishIfaceName returns the interface name used in the NOS configuration for the link endpoint name
+func batfishIfaceName(kind, name string) string {
+	switch kind {
+	case nodes.NodeKindCEOS:
+		if m := eosIfaceRe.FindStringSubmatch(name); m != nil {
+			return "Ethernet" + strings.ReplaceAll(m[1], "_", "/")
+		}
+	}
+	return name
+}
+
+// batfishLayer1Topology returns the layer1 topology of the lab links between the exported nodes.
+// Each link is added in both directions
+func (c *CLab) batfishLayer1Topology(exported map[string]struct{}) *batfishLayer1 {
+	topo := &batfishLayer1{Edges: []batfishEdge{}}
+	for i := 0; i < len(c.Links); i++ {
+		l, ok := c.Links[i]
+		if !ok {
+			continue
+		}
+		_, okA := exported[l.A.Node.ShortName]
+		_, okB := exported[l.B.Node.ShortName]
+		if !okA || !okB {
+			continue
+		}
+		a := batfishInterface{Hostname: l.A.Node.ShortName, InterfaceName: batfishIfaceName(l.A.Node.Kind, l.A.EndpointName)}
+		b := batfishInterface{Hostname: l.B.Node.ShortName, InterfaceName: batfishIfaceName(l.B.Node.Kind, l.B.EndpointName)}
+		topo.Edges = append(topo.Edges, batfishEdge{Node1: a, Node2: b}, batfishEdge{Node1: b, Node2: a})
+	}
+	return topo
+}
+
+// parseHostInterfaces returns the host interfaces from the `ip -o -4 addr show` output.
+// The loopback and the management interface eth0 are skipped, as well as the secondary addresses
+func parseHostInterfaces(out []byte) map[string]*batfishHostInterface {
+	ifaces := map[string]*batfishHostInterface{}
+	s := bufio.NewScanner(bytes.NewReader(out))
+	for s.Scan() {
+		// 12: eth1@if13    inet 192.168.0.1/24 scope global eth1\       valid_lft forever preferred_lft forever
+		f := strings.Fields(s.Text())
+		if len(f) < 4 || f[2] != "inet" {
+			continue
+		}
+		name := strings.SplitN(f[1], "@", 2)[0]
+		if _, ok := ifaces[name]; ok || name == "lo" || name == "eth0" {
+			continue
+		}
+		ifaces[name] = &batfishHostInterface{Name: name, Prefix: f[3]}
+	}
+	return ifaces
+}
+
+// ExportBatfish writes the Batfish snapshot of the lab to the dir directory.
+// The snapshot consists of the node configurations in the configs directory,
+// the linux nodes definitions in the hosts directory and the layer1 topology of the lab links.
+// Nodes of the kinds not supported by Batfish are skipped
+func (c *CLab) ExportBatfish(ctx context.Context, dir string) error {
+	for _, d := range []string{"configs", "hosts", "batfish"} {
+		if err := os.MkdirAll(filepath.Join(dir, d), 0755); err != nil {
+			return err
+		}
+	}
+
+	names := make([]string, 0, len(c.Nodes))
+	for name := range c.Nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	exported := map[string]struct{}{}
+	for _, name := range names {
+		n := c.Nodes[name]
+		cfg := n.Config()
+		var err error
+		switch _, vrStartup := batfishStartupConfigKinds[cfg.Kind]; {
+		case batfishConfigCmds[cfg.Kind] != nil:
+			err = exportRunningConfig(ctx, n, batfishConfigCmds[cfg.Kind], filepath.Join(dir, "configs", name+".cfg"))
+		case cfg.Kind == nodes.NodeKindLinux:
+			err = exportHost(ctx, n, filepath.Join(dir, "hosts", name+".json"))
+		case vrStartup && cfg.StartupConfig != "":
+			log.Warnf("running configuration of %s node can't be retrieved, exporting its startup-config", name)
+			err = utils.CopyFile(cfg.StartupConfig, filepath.Join(dir, "configs", name+".cfg"))
+		default:
+			log.Warnf("node %s of kind %s can't be exported to the Batfish snapshot, skipping", name, cfg.Kind)
+			continue
+		}
+		if err != nil {
+			log.Warnf("failed to export node %s: %v", name, err)
+			continue
+		}
+		exported[name] = struct{}{}
+	}
+
+	b, err := json.MarshalIndent(c.batfishLayer1Topology(exported), "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, "batfish", "layer1_topology.json"), b, 0644)
+}
+
+// exportRunningConfig writes the output of the cmd retrieving the running configuration of the node to the dst file
+func exportRunningConfig(ctx context.Context, n nodes.Node, cmd []string, dst string) error {
+	stdout, stderr, err := n.GetRuntime().Exec(ctx, n.Config().LongName, cmd)
+	if err != nil {
+		return err
+	}
+	if len(stderr) > 0 {
+		return fmt.Errorf("%s", strings.TrimSpace(string(stderr)))
+	}
+	return ioutil.WriteFile(dst, stdout, 0644)
+}
+
+// exportHost writes the Batfish host definition of the linux node with its IPv4 addresses to the dst file
+func exportHost(ctx context.Context, n nodes.Node, dst string) error {
+	stdout, _, err := n.GetRuntime().Exec(ctx, n.Config().LongName, hostIfacesCmd)
+	if err != nil {
+		return err
+	}
+	host := &batfishHost{
+		Hostname:       n.Config().ShortName,
+		HostInterfaces: parseHostInterfaces(stdout),
+	}
+	b, err := json.MarshalIndent(host, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(dst, b, 0644)
+}
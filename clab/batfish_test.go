@@ -0,0 +1,69 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBatfishIfaceName(t *testing.T) {
+	tests := map[string]struct {
+		kind string
+		name string
+		want string
+	}{
+		"ceos":          {kind: "ceos", name: "eth1", want: "Ethernet1"},
+		"ceos-breakout": {kind: "ceos", name: "eth1_2", want: "Ethernet1/2"},
+		"ceos-mgmt":     {kind: "ceos", name: "mgmt0", want: "mgmt0"},
+		"crpd":          {kind: "crpd", name: "eth1", want: "eth1"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := batfishIfaceName(tc.kind, tc.name); got != tc.want {
+				t.Errorf("wanted %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestBatfishLayer1Topology(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo14.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// spine1 is an srl node not supported by Batfish
+	exported := map[string]struct{}{"leaf1": {}, "leaf2": {}, "client<1>": {}}
+	want := &batfishLayer1{Edges: []batfishEdge{
+		{
+			Node1: batfishInterface{Hostname: "leaf1", InterfaceName: "Ethernet2"},
+			Node2: batfishInterface{Hostname: "client<1>", InterfaceName: "eth1"},
+		},
+		{
+			Node1: batfishInterface{Hostname: "client<1>", InterfaceName: "eth1"},
+			Node2: batfishInterface{Hostname: "leaf1", InterfaceName: "Ethernet2"},
+		},
+	}}
+	if d := cmp.Diff(want, c.batfishLayer1Topology(exported)); d != "" {
+		t.Fatalf("layer1 topology mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestParseHostInterfaces(t *testing.T) {
+	out := `1: lo    inet 127.0.0.1/8 scope host lo\       valid_lft forever preferred_lft forever
+164: eth0@if165    inet 172.20.20.4/24 brd 172.20.20.255 scope global eth0\       valid_lft forever preferred_lft forever
+170: eth1@if171    inet 192.168.1.2/24 scope global eth1\       valid_lft forever preferred_lft forever
+170: eth1@if171    inet 192.168.2.2/24 scope global secondary eth1\       valid_lft forever preferred_lft forever
+172: eth2@if173    inet 10.0.0.2/30 scope global eth2\       valid_lft forever preferred_lft forever
+`
+	want := map[string]*batfishHostInterface{
+		"eth1": {Name: "eth1", Prefix: "192.168.1.2/24"},
+		"eth2": {Name: "eth2", Prefix: "10.0.0.2/30"},
+	}
+	if d := cmp.Diff(want, parseHostInterfaces([]byte(out))); d != "" {
+		t.Fatalf("host interfaces mismatch (-want +got):\n%s", d)
+	}
+}
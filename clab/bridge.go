@@ -0,0 +1,98 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"fmt"
+	"os/exec"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/vishvananda/netlink"
+)
+
+// verifyLinkVLANs checks the VLAN settings of the links attached to the bridges
+func (c *CLab) verifyLinkVLANs() error {
+	for _, l := range c.Links {
+		if l.VLAN == nil {
+			continue
+		}
+		br, err := linkVLANBridge(l)
+		if err != nil {
+			return err
+		}
+		// linux bridge applies the VLANs of its ports with VLAN filtering enabled only
+		if br.Kind == nodes.NodeKindBridge && !bridgeVLANFiltering(br) {
+			return fmt.Errorf("%s: bridge %s has VLAN filtering disabled, set `vlan-filtering: true` in the bridge settings of the node", l, br.ShortName)
+		}
+	}
+	return nil
+}
+
+// linkVLANBridge validates the VLAN settings of the link
+// and returns the bridge node the link is attached to
+func linkVLANBridge(l *types.Link) (*types.NodeConfig, error) {
+	if err := l.VLAN.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %v", l, err)
+	}
+	var bridges []*types.NodeConfig
+	for _, n := range []*types.NodeConfig{l.A.Node, l.B.Node} {
+		if n.Kind == nodes.NodeKindBridge || n.Kind == nodes.NodeKindOVS {
+			bridges = append(bridges, n)
+		}
+	}
+	if len(bridges) != 1 {
+		return nil, fmt.Errorf("%s: vlan settings need the link to have exactly one bridge or ovs-bridge endpoint", l)
+	}
+	if bridges[0].ShortName == "mgmt-net" {
+		return nil, fmt.Errorf("%s: vlan settings are not supported for the mgmt-net links", l)
+	}
+	return bridges[0], nil
+}
+
+// bridgeVLANFiltering returns true if the existing linux bridge has VLAN filtering enabled
+// or the bridge is to be created with VLAN filtering
+func bridgeVLANFiltering(n *types.NodeConfig) bool {
+	if l, err := netlink.LinkByName(n.ShortName); err == nil {
+		br, ok := l.(*netlink.Bridge)
+		return ok && br.VlanFiltering != nil && *br.VlanFiltering
+	}
+	return n.Bridge.GetCreate() && n.Bridge.VLANFiltering
+}
+
+// enableBridgeVLANFiltering enables VLAN filtering on the linux bridges created by the lab
+// which ports have the VLAN settings, as the bridge applies the VLANs of its ports with VLAN filtering enabled only
+func (c *CLab) enableBridgeVLANFiltering() {
+	for _, l := range c.Links {
+		if l.VLAN == nil {
+			continue
+		}
+		for _, n := range []*types.NodeConfig{l.A.Node, l.B.Node} {
+			if n.Kind != nodes.NodeKindBridge || !n.Bridge.GetCreate() || n.Bridge.VLANFiltering {
+				continue
+			}
+			log.Debugf("enabling VLAN filtering on bridge %s with VLAN ports", n.ShortName)
+			// the bridge settings may be shared with other nodes through the kinds and defaults
+			b := *n.Bridge
+			b.VLANFiltering = true
+			n.Bridge = &b
+		}
+	}
+}
+
+// verifyOvsInstalled checks that Open vSwitch is installed when the topology has ovs-bridge nodes
+func (c *CLab) verifyOvsInstalled() error {
+	for name, n := range c.Nodes {
+		if n.Config().Kind != nodes.NodeKindOVS {
+			continue
+		}
+		if _, err := exec.LookPath("ovs-vsctl"); err != nil {
+			return fmt.Errorf("ovs-bridge node %s needs Open vSwitch installed on the lab host, ovs-vsctl is not found: %v", name, err)
+		}
+		return nil
+	}
+	return nil
+}
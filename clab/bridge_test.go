@@ -0,0 +1,86 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestLinkVLANBridge(t *testing.T) {
+	srl := &types.NodeConfig{ShortName: "srl", Kind: "srl"}
+	br := &types.NodeConfig{ShortName: "br1", Kind: "bridge"}
+	ovs := &types.NodeConfig{ShortName: "ovs1", Kind: "ovs-bridge"}
+	mgmt := &types.NodeConfig{ShortName: "mgmt-net", Kind: "bridge"}
+	access := &types.LinkVLAN{Access: 10}
+
+	tests := map[string]struct {
+		link    *types.Link
+		want    *types.NodeConfig
+		wantErr bool
+	}{
+		"linux-bridge": {
+			link: &types.Link{A: &types.Endpoint{Node: srl}, B: &types.Endpoint{Node: br}, VLAN: access},
+			want: br,
+		},
+		"ovs-bridge-side-a": {
+			link: &types.Link{A: &types.Endpoint{Node: ovs}, B: &types.Endpoint{Node: srl}, VLAN: access},
+			want: ovs,
+		},
+		"no-bridge": {
+			link:    &types.Link{A: &types.Endpoint{Node: srl}, B: &types.Endpoint{Node: srl}, VLAN: access},
+			wantErr: true,
+		},
+		"two-bridges": {
+			link:    &types.Link{A: &types.Endpoint{Node: br}, B: &types.Endpoint{Node: ovs}, VLAN: access},
+			wantErr: true,
+		},
+		"mgmt-net": {
+			link:    &types.Link{A: &types.Endpoint{Node: srl}, B: &types.Endpoint{Node: mgmt}, VLAN: access},
+			wantErr: true,
+		},
+		"invalid-vlan": {
+			link:    &types.Link{A: &types.Endpoint{Node: srl}, B: &types.Endpoint{Node: br}, VLAN: &types.LinkVLAN{Access: 5000}},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := linkVLANBridge(tc.link)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("wanted bridge %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestEnableBridgeVLANFiltering(t *testing.T) {
+	created := &types.BridgeConfig{Create: true}
+	srl := &types.NodeConfig{ShortName: "srl", Kind: "srl"}
+	br1 := &types.NodeConfig{ShortName: "br1", Kind: "bridge", Bridge: created}
+	br2 := &types.NodeConfig{ShortName: "br2", Kind: "bridge", Bridge: created}
+	br3 := &types.NodeConfig{ShortName: "br3", Kind: "bridge"}
+	c := &CLab{Links: map[int]*types.Link{
+		0: {A: &types.Endpoint{Node: srl}, B: &types.Endpoint{Node: br1}, VLAN: &types.LinkVLAN{Access: 10}},
+		1: {A: &types.Endpoint{Node: srl}, B: &types.Endpoint{Node: br2}},
+		2: {A: &types.Endpoint{Node: srl}, B: &types.Endpoint{Node: br3}, VLAN: &types.LinkVLAN{Access: 10}},
+	}}
+	c.enableBridgeVLANFiltering()
+
+	if !br1.Bridge.VLANFiltering {
+		t.Error("expected VLAN filtering enabled on the created bridge with VLAN ports")
+	}
+	if br2.Bridge.VLANFiltering || created.VLANFiltering {
+		t.Error("expected the shared bridge settings of the bridge without VLAN ports left intact")
+	}
+	if br3.Bridge != nil {
+		t.Error("expected the settings of the existing bridge left intact")
+	}
+}
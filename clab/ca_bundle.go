@@ -0,0 +1,78 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+const (
+	// caBundleFile is the lab CA trust bundle written to the lab directory of the nodes
+	caBundleFile = "ca-bundle.pem"
+	// caBundlePath is the path the trust bundle is mounted to in the linux nodes
+	caBundlePath = "/etc/ssl/certs/clab-ca-bundle.pem"
+	// caBundleEnv is the env var set to the trust bundle path in the linux nodes
+	caBundleEnv = "CLAB_CA_BUNDLE"
+)
+
+// writeCABundles writes the lab root CA certificate as the ca-bundle.pem file to the lab directory of every node
+// and returns the names of the nodes the bundle was written for.
+// Nothing is written when the lab has no root CA
+func (c *CLab) writeCABundles() ([]string, error) {
+	rootCA := filepath.Join(c.Dir.LabCARoot, "root-ca.pem")
+	if !utils.FileExists(rootCA) {
+		return nil, nil
+	}
+	b, err := ioutil.ReadFile(rootCA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lab root CA: %v", err)
+	}
+
+	var res []string
+	for name, n := range c.Nodes {
+		cfg := n.Config()
+		switch cfg.Kind {
+		case nodes.NodeKindBridge, nodes.NodeKindOVS, nodes.NodeKindHOST:
+			continue
+		}
+		utils.CreateDirectory(cfg.LabDir, 0777)
+		if err := ioutil.WriteFile(filepath.Join(cfg.LabDir, caBundleFile), b, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write CA bundle of node %s: %v", name, err)
+		}
+		res = append(res, name)
+	}
+	return res, nil
+}
+
+// InstallCABundle writes the lab CA trust bundle to the lab directory of the nodes.
+// The bundle is mounted to the linux nodes and its path is set in the CLAB_CA_BUNDLE env var,
+// so that the clients running there can validate the certificates of the lab nodes
+func (c *CLab) InstallCABundle() error {
+	names, err := c.writeCABundles()
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		cfg := c.Nodes[name].Config()
+		if cfg.Kind != nodes.NodeKindLinux {
+			continue
+		}
+		log.Debugf("mounting lab CA bundle to %s node", name)
+		cfg.Binds = append(cfg.Binds, filepath.Join(cfg.LabDir, caBundleFile)+":"+caBundlePath+":ro")
+		if cfg.Env == nil {
+			cfg.Env = map[string]string{}
+		}
+		if _, ok := cfg.Env[caBundleEnv]; !ok {
+			cfg.Env[caBundleEnv] = caBundlePath
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestInstallCABundle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clab-ca-bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c, err := NewContainerLab(WithLabDirPath(dir), WithTopoFile("test_data/topo14.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// no bundle is installed until the lab root CA is created
+	if err := c.InstallCABundle(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(c.Nodes["leaf1"].Config().LabDir, caBundleFile)); !os.IsNotExist(err) {
+		t.Fatalf("CA bundle is written without the lab root CA")
+	}
+
+	rootCA := "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"
+	if err := os.MkdirAll(c.Dir.LabCARoot, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(c.Dir.LabCARoot, "root-ca.pem"), []byte(rootCA), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.InstallCABundle(); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, n := range c.Nodes {
+		b, err := ioutil.ReadFile(filepath.Join(n.Config().LabDir, caBundleFile))
+		if err != nil {
+			t.Fatalf("node %s: %v", name, err)
+		}
+		if string(b) != rootCA {
+			t.Errorf("node %s: wanted CA bundle %q, got %q", name, rootCA, b)
+		}
+	}
+
+	client := c.Nodes["client<1>"].Config()
+	wantBind := filepath.Join(client.LabDir, caBundleFile) + ":" + caBundlePath + ":ro"
+	if d := cmp.Diff([]string{wantBind}, client.Binds); d != "" {
+		t.Errorf("linux node binds mismatch (-want +got):\n%s", d)
+	}
+	if client.Env[caBundleEnv] != caBundlePath {
+		t.Errorf("wanted %s env var set to %s, got %q", caBundleEnv, caBundlePath, client.Env[caBundleEnv])
+	}
+	for _, name := range []string{"spine1", "leaf1"} {
+		if _, ok := c.Nodes[name].Config().Env[caBundleEnv]; ok {
+			t.Errorf("node %s: CA bundle env var is set for non-linux node", name)
+		}
+	}
+}
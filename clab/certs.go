@@ -0,0 +1,166 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/cert"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// InitCA sets the certificate authority backend issuing the lab certificates
+// as selected in the lab settings
+func (c *CLab) InitCA() error {
+	labCA, err := cert.NewCA(c.Config.Settings.GetCertificateAuthority(), c.Config.CA)
+	if err != nil {
+		return err
+	}
+	cert.SetCA(labCA)
+	return nil
+}
+
+// certNodes returns the names of the lab nodes using the lab certificates sorted by name.
+// When names are provided, only those nodes are returned
+func (c *CLab) certNodes(names []string) ([]string, error) {
+	var res []string
+	if len(names) == 0 {
+		for name, n := range c.Nodes {
+			if cert.NeedsCert(n.Config()) {
+				res = append(res, name)
+			}
+		}
+		sort.Strings(res)
+		return res, nil
+	}
+	for _, name := range names {
+		n, ok := c.Nodes[name]
+		if !ok {
+			return nil, fmt.Errorf("node %q is not found in the topology", name)
+		}
+		if !cert.NeedsCert(n.Config()) {
+			return nil, fmt.Errorf("node %q doesn't use the lab certificates", name)
+		}
+		res = append(res, name)
+	}
+	return res, nil
+}
+
+// RenewCerts re-signs the certificates of the lab nodes with the lab root CA and returns the names of the renewed nodes.
+// When renewCA is set, the lab root CA is regenerated and the certificates of all nodes are renewed.
+// Otherwise the renewal can be limited to the nodes passed in names
+func (c *CLab) RenewCerts(renewCA bool, names []string) ([]string, error) {
+	if renewCA && len(names) > 0 {
+		return nil, fmt.Errorf("certificates of all nodes are renewed with the root CA, nodes can't be selected")
+	}
+	renewed, err := c.certNodes(names)
+	if err != nil {
+		return nil, err
+	}
+	if len(renewed) == 0 {
+		return nil, fmt.Errorf("lab %s has no nodes using the lab certificates", c.Config.Name)
+	}
+
+	if err := c.InitCA(); err != nil {
+		return nil, err
+	}
+	if renewCA {
+		log.Info("Renewing lab root CA")
+		if err := cert.RenewRootCA(c.Config.Name, c.Dir.LabCARoot); err != nil {
+			return nil, err
+		}
+		// linux nodes use the renewed bundle right away, as it is bind mounted
+		if _, err := c.writeCABundles(); err != nil {
+			return nil, err
+		}
+	} else if _, err := os.Stat(filepath.Join(c.Dir.LabCARoot, "root-ca.pem")); err != nil {
+		return nil, fmt.Errorf("lab root CA is not found in %s, deploy the lab first or use --ca flag", c.Dir.LabCARoot)
+	}
+
+	for _, name := range renewed {
+		log.Infof("Renewing certificate of node %s", name)
+		if _, err := cert.RenewNodeCerts(c.Nodes[name].Config(), c.Config.Name, c.Dir.LabCA, c.Dir.LabCARoot); err != nil {
+			return nil, err
+		}
+	}
+	return renewed, nil
+}
+
+// ReloadCerts installs the renewed certificates on the running lab nodes.
+// Nodes of the kinds without support for the certificate reload are skipped with a warning
+func (c *CLab) ReloadCerts(ctx context.Context, names []string) error {
+	labels := []*types.GenericFilter{{FilterType: "label", Match: c.Config.Name, Field: "containerlab", Operator: "="}}
+	containers, err := c.ListContainers(ctx, labels)
+	if err != nil {
+		return err
+	}
+	running := map[string]struct{}{}
+	for _, ctr := range containers {
+		if ctr.State == "running" {
+			running[ctr.Labels[NodeNameLabel]] = struct{}{}
+		}
+	}
+
+	for _, name := range names {
+		n := c.Nodes[name]
+		if _, ok := running[name]; !ok {
+			log.Warnf("node %s is not running, renewed certificate will be used on the next deployment", name)
+			continue
+		}
+		r, ok := n.(nodes.CertReloader)
+		if !ok {
+			log.Warnf("node %s of kind %s doesn't support certificate reload, redeploy the node to use the renewed certificate",
+				name, n.Config().Kind)
+			continue
+		}
+		if err := r.ReloadCerts(ctx, c.Config.Name, c.Dir.LabCA, c.Dir.LabCARoot); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InstallCerts installs the node certificates on the nodes which NOS imports them after boot,
+// e.g. the vrnetlab nodes with tls enabled. The nodes are handled concurrently and each of them
+// is given its boot timeout to accept the certificate
+func (c *CLab) InstallCerts(ctx context.Context) error {
+	var mu sync.Mutex
+	var errs []string
+	wg := new(sync.WaitGroup)
+	for _, n := range c.Nodes {
+		ci, ok := n.(nodes.CertInstaller)
+		if !ok || !n.Config().TLS {
+			continue
+		}
+		wg.Add(1)
+		go func(cfg *types.NodeConfig, ci nodes.CertInstaller) {
+			defer wg.Done()
+			log.Infof("Installing TLS certificate to node %s", cfg.ShortName)
+			install := func(ctx context.Context) (bool, error) {
+				err := ci.InstallCerts(ctx, c.Config.Name, c.Dir.LabCA, c.Dir.LabCARoot)
+				return err == nil, err
+			}
+			if err := waitReady(ctx, install, waitTimeout(cfg), readyPoll); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Sprintf("%s: %v", cfg.ShortName, err))
+				mu.Unlock()
+			}
+		}(n.Config(), ci)
+	}
+	wg.Wait()
+	if len(errs) != 0 {
+		sort.Strings(errs)
+		return fmt.Errorf("failed to install certificates: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCertNodes(t *testing.T) {
+	tests := map[string]struct {
+		names   []string
+		want    []string
+		wantErr bool
+	}{
+		"all": {
+			want: []string{"spine1"},
+		},
+		"selected": {
+			names: []string{"spine1"},
+			want:  []string{"spine1"},
+		},
+		"no_certs": {
+			names:   []string{"leaf1"},
+			wantErr: true,
+		},
+		"unknown_node": {
+			names:   []string{"leaf3"},
+			wantErr: true,
+		},
+	}
+
+	c, err := NewContainerLab(WithTopoFile("test_data/topo14.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := c.certNodes(tc.names)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !cmp.Equal(got, tc.want) {
+				t.Errorf("failed at '%s', diff (-want +got):\n%s", name, cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
@@ -0,0 +1,311 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/docker/go-units"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+const (
+	CheckOK   = "ok"
+	CheckWarn = "warn"
+	CheckFail = "fail"
+
+	// inotify instances and open files a single node is expected to consume
+	inotifyInstancesPerNode = 16
+	openFilesPerNode        = 64
+)
+
+// onDemandModules are loaded by the kernel when the first link of their type is created
+var onDemandModules = map[string]struct{}{
+	"bridge": {},
+	"veth":   {},
+	"vrf":    {},
+}
+
+// meminfoFile is the file the memory statistics are read from
+var meminfoFile = "/proc/meminfo"
+
+// CheckResult is an outcome of a single host check
+type CheckResult struct {
+	Name    string
+	Status  string
+	Message string
+	// Fix is an action a user can take to fix a failed check
+	Fix string
+}
+
+// HostCheck is a function checking a container host against the needs of a lab
+type HostCheck func(c *CLab) []*CheckResult
+
+// HostChecks is the list of checks run by the CheckHost
+var HostChecks = []HostCheck{
+	checkKernelModules,
+	checkInotifyLimits,
+	checkOpenFilesLimit,
+	checkHugepages,
+	checkMemory,
+	checkKVM,
+	checkBinaries,
+}
+
+// CheckHost runs the host checks for the lab nodes
+func (c *CLab) CheckHost() []*CheckResult {
+	var res []*CheckResult
+	for _, check := range HostChecks {
+		res = append(res, check(c)...)
+	}
+	return res
+}
+
+// hasKind returns true if the lab has nodes of a kind satisfying the match function
+func (c *CLab) hasKind(match func(kind string) bool) bool {
+	for _, n := range c.Nodes {
+		if match(n.Config().Kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkKernelModules verifies that the kernel modules needed for the lab links are available,
+// the modules loaded on demand are only reported when they are not loaded yet
+func checkKernelModules(c *CLab) []*CheckResult {
+	var res []*CheckResult
+	for _, m := range c.requiredModules() {
+		r := &CheckResult{Name: "kernel module " + m, Status: CheckOK, Message: "loaded"}
+		if !moduleLoaded(m) {
+			r.Status = CheckFail
+			r.Message = "not loaded"
+			if _, ok := onDemandModules[m]; ok {
+				r.Status = CheckWarn
+				r.Message = "not loaded, the kernel loads it when the links are created"
+			}
+			r.Fix = "modprobe " + m
+		}
+		res = append(res, r)
+	}
+	return res
+}
+
+// kernelModuleLoaded returns true if the module is either loaded or built into the kernel
+func kernelModuleLoaded(name string) bool {
+	if _, err := os.Stat("/sys/module/" + name); err == nil {
+		return true
+	}
+	release, err := ioutil.ReadFile("/proc/sys/kernel/osrelease")
+	if err != nil {
+		return false
+	}
+	b, err := ioutil.ReadFile(fmt.Sprintf("/lib/modules/%s/modules.builtin", strings.TrimSpace(string(release))))
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(b), "/"+name+".ko")
+}
+
+// checkInotifyLimits verifies that the inotify limits are sufficient for the number of lab nodes
+func checkInotifyLimits(c *CLab) []*CheckResult {
+	want := inotifyInstancesPerNode * len(c.Nodes)
+	if want < 128 {
+		want = 128
+	}
+	r := &CheckResult{Name: "inotify instances", Status: CheckOK}
+	v, err := readSysctlInt("fs/inotify/max_user_instances")
+	switch {
+	case err != nil:
+		r.Status = CheckWarn
+		r.Message = fmt.Sprintf("failed to read the limit: %v", err)
+	case v < want:
+		r.Status = CheckWarn
+		r.Message = fmt.Sprintf("max_user_instances is %d, %d nodes might need %d", v, len(c.Nodes), want)
+		r.Fix = fmt.Sprintf("sysctl -w fs.inotify.max_user_instances=%d", want)
+	default:
+		r.Message = fmt.Sprintf("max_user_instances is %d", v)
+	}
+	return []*CheckResult{r}
+}
+
+// checkOpenFilesLimit verifies that the open files limit is sufficient for the number of lab nodes
+func checkOpenFilesLimit(c *CLab) []*CheckResult {
+	want := uint64(openFilesPerNode * len(c.Nodes))
+	if want < 1024 {
+		want = 1024
+	}
+	r := &CheckResult{Name: "open files limit", Status: CheckOK}
+	var rlim syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &rlim); err != nil {
+		r.Status = CheckWarn
+		r.Message = fmt.Sprintf("failed to read the limit: %v", err)
+		return []*CheckResult{r}
+	}
+	r.Message = fmt.Sprintf("%d", rlim.Cur)
+	if rlim.Cur < want {
+		r.Status = CheckWarn
+		r.Message = fmt.Sprintf("%d, %d nodes might need %d", rlim.Cur, len(c.Nodes), want)
+		r.Fix = fmt.Sprintf("ulimit -n %d", want)
+	}
+	return []*CheckResult{r}
+}
+
+// checkHugepages verifies that hugepages are allocated when nodes mount /dev/hugepages
+func checkHugepages(c *CLab) []*CheckResult {
+	var nodes []string
+	for name, n := range c.Nodes {
+		for _, b := range n.Config().Binds {
+			if strings.HasPrefix(b, "/dev/hugepages") {
+				nodes = append(nodes, name)
+				break
+			}
+		}
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+	r := &CheckResult{Name: "hugepages", Status: CheckOK}
+	meminfo, err := readMeminfo()
+	if err != nil {
+		r.Status = CheckWarn
+		r.Message = fmt.Sprintf("failed to read /proc/meminfo: %v", err)
+		return []*CheckResult{r}
+	}
+	total := meminfo["HugePages_Total"]
+	r.Message = fmt.Sprintf("%d hugepages allocated", total)
+	if total == 0 {
+		r.Status = CheckFail
+		r.Message = fmt.Sprintf("no hugepages allocated, required by nodes %s", strings.Join(nodes, ", "))
+		r.Fix = "sysctl -w vm.nr_hugepages=<number of pages>"
+	}
+	return []*CheckResult{r}
+}
+
+// checkMemory verifies that the available memory is enough for the memory declared by the nodes
+func checkMemory(c *CLab) []*CheckResult {
+	var required int64
+	for _, n := range c.Nodes {
+		v, _ := nodeMemory(n.Config())
+		required += v
+	}
+	if required == 0 {
+		return nil
+	}
+	r := &CheckResult{Name: "memory", Status: CheckOK}
+	meminfo, err := readMeminfo()
+	if err != nil {
+		r.Status = CheckWarn
+		r.Message = fmt.Sprintf("failed to read /proc/meminfo: %v", err)
+		return []*CheckResult{r}
+	}
+	// meminfo values are in kB
+	available := meminfo["MemAvailable"] * units.KiB
+	r.Message = fmt.Sprintf("nodes require %s, %s available", units.BytesSize(float64(required)), units.BytesSize(float64(available)))
+	if available < required {
+		r.Status = CheckWarn
+		r.Fix = "free up memory or reduce the memory of the nodes"
+	}
+	return []*CheckResult{r}
+}
+
+// checkKVM verifies that the KVM device is available for VM based nodes
+func checkKVM(c *CLab) []*CheckResult {
+	if !c.hasKind(nodes.IsVrKind) {
+		return nil
+	}
+	r := &CheckResult{Name: "kvm", Status: CheckOK, Message: "/dev/kvm is available"}
+	if err := c.verifyVirtSupport(); err != nil {
+		r.Status = CheckFail
+		r.Message = "CPU virtualization extensions are not available"
+		r.Fix = "enable VT-x/AMD-V in BIOS or nested virtualization for the VM"
+		return []*CheckResult{r}
+	}
+	if !utils.FileExists("/dev/kvm") {
+		r.Status = CheckFail
+		r.Message = "/dev/kvm is not present"
+		r.Fix = "modprobe kvm_intel (or kvm_amd)"
+	}
+	return []*CheckResult{r}
+}
+
+// checkBinaries verifies that the binaries used by the lab nodes are installed
+func checkBinaries(c *CLab) []*CheckResult {
+	var bins []string
+	if c.hasKind(func(k string) bool { return k == "ovs-bridge" }) {
+		bins = append(bins, "ovs-vsctl")
+	}
+
+	var res []*CheckResult
+	for _, b := range bins {
+		r := &CheckResult{Name: "binary " + b, Status: CheckOK, Message: "found"}
+		if p, err := exec.LookPath(b); err != nil {
+			r.Status = CheckFail
+			r.Message = "not found in PATH"
+			r.Fix = "install " + b
+		} else {
+			r.Message = p
+		}
+		res = append(res, r)
+	}
+	return res
+}
+
+// nodeMemory returns the memory declared by a node in bytes, 0 if the node doesn't declare it
+func nodeMemory(cfg *types.NodeConfig) (int64, error) {
+	switch {
+	case cfg.Memory != "":
+		return units.RAMInBytes(cfg.Memory)
+	case cfg.RAM != "":
+		return units.RAMInBytes(cfg.RAM)
+	// vrnetlab nodes are provided with RAM (in MB) via env vars
+	case nodes.IsVrKind(cfg.Kind) && cfg.Env["RAM"] != "":
+		v, err := strconv.ParseInt(cfg.Env["RAM"], 10, 64)
+		return v * units.MiB, err
+	}
+	return 0, nil
+}
+
+func readSysctlInt(name string) (int, error) {
+	b, err := ioutil.ReadFile(filepath.Join(sysctlDir, name))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(b)))
+}
+
+// readMeminfo returns /proc/meminfo values keyed by their names
+func readMeminfo() (map[string]int64, error) {
+	f, err := os.Open(meminfoFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	m := map[string]int64{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		v, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		m[strings.TrimSuffix(fields[0], ":")] = v
+	}
+	return m, scanner.Err()
+}
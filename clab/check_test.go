@@ -0,0 +1,167 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCheckKernelModules(t *testing.T) {
+	origLoaded := moduleLoaded
+	defer func() { moduleLoaded = origLoaded }()
+
+	c, err := NewContainerLab(WithTopoFile("test_data/topo1.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]struct {
+		loaded map[string]bool
+		// kind of node1, srl when empty
+		kind string
+		want []*CheckResult
+	}{
+		"loaded": {
+			loaded: map[string]bool{"bridge": true, "veth": true},
+			want: []*CheckResult{
+				{Name: "kernel module bridge", Status: CheckOK, Message: "loaded"},
+				{Name: "kernel module veth", Status: CheckOK, Message: "loaded"},
+			},
+		},
+		// fresh host, the modules are loaded when the links are created
+		"loaded_on_demand": {
+			loaded: map[string]bool{},
+			want: []*CheckResult{
+				{Name: "kernel module bridge", Status: CheckWarn, Message: "not loaded, the kernel loads it when the links are created", Fix: "modprobe bridge"},
+				{Name: "kernel module veth", Status: CheckWarn, Message: "not loaded, the kernel loads it when the links are created", Fix: "modprobe veth"},
+			},
+		},
+		"kind_module_not_loaded": {
+			loaded: map[string]bool{"bridge": true, "veth": true},
+			kind:   "crpd",
+			want: []*CheckResult{
+				{Name: "kernel module bridge", Status: CheckOK, Message: "loaded"},
+				{Name: "kernel module mpls_iptunnel", Status: CheckFail, Message: "not loaded", Fix: "modprobe mpls_iptunnel"},
+				{Name: "kernel module mpls_router", Status: CheckFail, Message: "not loaded", Fix: "modprobe mpls_router"},
+				{Name: "kernel module veth", Status: CheckOK, Message: "loaded"},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			moduleLoaded = func(m string) bool { return tc.loaded[m] }
+			c.Nodes["node1"].Config().Kind = "srl"
+			if tc.kind != "" {
+				c.Nodes["node1"].Config().Kind = tc.kind
+			}
+			if d := cmp.Diff(tc.want, checkKernelModules(c)); d != "" {
+				t.Errorf("check results mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestCheckInotifyLimits(t *testing.T) {
+	origSysctlDir := sysctlDir
+	defer func() { sysctlDir = origSysctlDir }()
+	sysctlDir = t.TempDir()
+	if err := os.MkdirAll(filepath.Join(sysctlDir, "fs", "inotify"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := NewContainerLab(WithTopoFile("test_data/topo1.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]struct {
+		value string
+		want  *CheckResult
+	}{
+		"sufficient": {
+			value: "8192",
+			want:  &CheckResult{Name: "inotify instances", Status: CheckOK, Message: "max_user_instances is 8192"},
+		},
+		"too_low": {
+			value: "64",
+			want: &CheckResult{
+				Name: "inotify instances", Status: CheckWarn,
+				Message: "max_user_instances is 64, 2 nodes might need 128",
+				Fix:     "sysctl -w fs.inotify.max_user_instances=128",
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := writeSysctl("fs.inotify.max_user_instances", tc.value); err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff([]*CheckResult{tc.want}, checkInotifyLimits(c)); d != "" {
+				t.Errorf("check results mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestCheckHugepagesAndMemory(t *testing.T) {
+	origMeminfo := meminfoFile
+	defer func() { meminfoFile = origMeminfo }()
+	meminfoFile = filepath.Join(t.TempDir(), "meminfo")
+
+	c, err := NewContainerLab(WithTopoFile("test_data/topo1.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the nodes that neither mount hugepages nor declare memory are not checked
+	if got := append(checkHugepages(c), checkMemory(c)...); len(got) != 0 {
+		t.Fatalf("got check results %+v for the lab without hugepages and memory", got)
+	}
+
+	node1 := c.Nodes["node1"].Config()
+	node1.Binds = append(node1.Binds, "/dev/hugepages:/dev/hugepages")
+	node1.Memory = "2GB"
+
+	tests := map[string]struct {
+		meminfo string
+		want    []*CheckResult
+	}{
+		"sufficient": {
+			meminfo: "MemTotal:       16384000 kB\nMemAvailable:    8388608 kB\nHugePages_Total:     512\n",
+			want: []*CheckResult{
+				{Name: "hugepages", Status: CheckOK, Message: "512 hugepages allocated"},
+				{Name: "memory", Status: CheckOK, Message: "nodes require 2GiB, 8GiB available"},
+			},
+		},
+		"insufficient": {
+			meminfo: "MemTotal:       2097152 kB\nMemAvailable:    1048576 kB\nHugePages_Total:       0\n",
+			want: []*CheckResult{
+				{
+					Name: "hugepages", Status: CheckFail,
+					Message: "no hugepages allocated, required by nodes node1",
+					Fix:     "sysctl -w vm.nr_hugepages=<number of pages>",
+				},
+				{
+					Name: "memory", Status: CheckWarn,
+					Message: "nodes require 2GiB, 1GiB available",
+					Fix:     "free up memory or reduce the memory of the nodes",
+				},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := ioutil.WriteFile(meminfoFile, []byte(tc.meminfo), 0644); err != nil {
+				t.Fatal(err)
+			}
+			got := append(checkHugepages(c), checkMemory(c)...)
+			if d := cmp.Diff(tc.want, got); d != "" {
+				t.Errorf("check results mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// name of the lab directory subdirectory holding the checkpoints of the nodes
+const checkpointsDir = "checkpoints"
+
+// states of the paused and the running containers, as reported by the runtimes
+const (
+	containerPaused  = "paused"
+	containerRunning = "running"
+)
+
+// CheckpointDir returns the directory the checkpoint of the node is saved to
+func (c *CLab) CheckpointDir(node string) string {
+	return filepath.Join(c.Dir.Lab, checkpointsDir, node)
+}
+
+// nodeStates returns the states of the containers of the lab nodes keyed by the node name,
+// the nodes without containers, e.g. bridges, are skipped
+func (c *CLab) nodeStates(ctx context.Context) (map[string]string, error) {
+	labels := []*types.GenericFilter{{FilterType: "label", Match: c.Config.Name, Field: ContainerlabLabel, Operator: "="}}
+	containers, err := c.ListContainers(ctx, labels)
+	if err != nil {
+		return nil, err
+	}
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("lab %s is not deployed", c.Config.Name)
+	}
+	states := map[string]string{}
+	for i := range containers {
+		name := containers[i].Labels[NodeNameLabel]
+		if _, ok := c.Nodes[name]; !ok {
+			continue
+		}
+		states[name] = containers[i].State
+	}
+	return states, nil
+}
+
+// PauseNodes freezes the containers of the running lab nodes and returns the names of the paused nodes.
+// With checkpoint set the state of the containers is saved to the lab directory with CRIU instead
+// and the containers are stopped, so that the nodes survive the host reboot and are restored with RestoreLab
+func (c *CLab) PauseNodes(ctx context.Context, checkpoint bool) ([]string, error) {
+	states, err := c.nodeStates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(states))
+	for name := range states {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var paused []string
+	for _, name := range names {
+		cfg := c.Nodes[name].Config()
+		r := c.Nodes[name].GetRuntime()
+		switch states[name] {
+		case containerRunning:
+		case containerPaused:
+			if !checkpoint {
+				log.Infof("Node %s is already paused", name)
+				continue
+			}
+			// paused containers can't be checkpointed
+			if err := r.UnpauseContainer(ctx, cfg.LongName); err != nil {
+				return paused, fmt.Errorf("failed to unpause node %s: %v", name, err)
+			}
+		default:
+			log.Warnf("Node %s is %s, it is not paused", name, states[name])
+			continue
+		}
+		if !checkpoint {
+			if err := r.PauseContainer(ctx, cfg.LongName); err != nil {
+				return paused, fmt.Errorf("failed to pause node %s: %v", name, err)
+			}
+			paused = append(paused, name)
+			continue
+		}
+		dir := c.CheckpointDir(name)
+		if err := os.RemoveAll(dir); err != nil {
+			return paused, err
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return paused, err
+		}
+		log.Infof("Checkpointing node %s to %s", name, dir)
+		if err := r.CheckpointContainer(ctx, cfg.LongName, dir); err != nil {
+			_ = os.RemoveAll(dir)
+			return paused, fmt.Errorf("failed to checkpoint node %s: %v", name, err)
+		}
+		paused = append(paused, name)
+	}
+	return paused, nil
+}
+
+// checkpointedNodes returns the names of the nodes with a checkpoint saved in the lab directory
+func checkpointedNodes(labDir string) (map[string]struct{}, error) {
+	entries, err := ioutil.ReadDir(filepath.Join(labDir, checkpointsDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	res := map[string]struct{}{}
+	for _, e := range entries {
+		if e.IsDir() {
+			res[e.Name()] = struct{}{}
+		}
+	}
+	return res, nil
+}
+
+// restorePlan returns the sorted names of the paused nodes to resume
+// and of the stopped nodes to restore from their checkpoints
+func restorePlan(states map[string]string, checkpointed map[string]struct{}) (resume, restore []string) {
+	for name, state := range states {
+		_, ok := checkpointed[name]
+		switch {
+		case state == containerPaused:
+			resume = append(resume, name)
+		case state != containerRunning && ok:
+			restore = append(restore, name)
+		}
+	}
+	sort.Strings(resume)
+	sort.Strings(restore)
+	return resume, restore
+}
+
+// RestoreLab resumes the paused nodes and restores the checkpointed nodes of the lab where they left off.
+// The links of the restored nodes are wired again, as their network namespaces are created anew,
+// and the nodes missing from the lab are deployed. It returns the names of the resumed and restored nodes
+// and the names of the added nodes
+func (c *CLab) RestoreLab(ctx context.Context) ([]string, []string, error) {
+	states, err := c.nodeStates(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	checkpointed, err := checkpointedNodes(c.Dir.Lab)
+	if err != nil {
+		return nil, nil, err
+	}
+	resume, restore := restorePlan(states, checkpointed)
+	if len(resume) == 0 && len(restore) == 0 {
+		return nil, nil, fmt.Errorf("lab %s has no paused or checkpointed nodes, pause it with 'containerlab pause' first", c.Config.Name)
+	}
+
+	for _, name := range resume {
+		log.Infof("Resuming node %s", name)
+		n := c.Nodes[name]
+		if err := n.GetRuntime().UnpauseContainer(ctx, n.Config().LongName); err != nil {
+			return nil, nil, fmt.Errorf("failed to resume node %s: %v", name, err)
+		}
+	}
+	for _, name := range restore {
+		log.Infof("Restoring node %s from its checkpoint", name)
+		n := c.Nodes[name]
+		dir := c.CheckpointDir(name)
+		if err := n.GetRuntime().RestoreContainer(ctx, n.Config().LongName, dir); err != nil {
+			return nil, nil, fmt.Errorf("failed to restore node %s: %v", name, err)
+		}
+		// the checkpoint is outdated as soon as the node runs again
+		if err := os.RemoveAll(dir); err != nil {
+			log.Warnf("failed to remove checkpoint of node %s: %v", name, err)
+		}
+	}
+
+	added, err := c.ReconcileLab(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	// the routes and the mgmt-netem qdisc of the restored nodes are gone with their network namespaces
+	for _, name := range restore {
+		cfg := c.Nodes[name].Config()
+		c.setNodeRoutes(cfg)
+		if cfg.MgmtNetem != nil && cfg.NetworkMode != "host" && !c.remoteWiring() {
+			if err := setMgmtNetem(cfg); err != nil {
+				log.Errorf("failed to apply mgmt-netem to node %s: %v", name, err)
+			}
+		}
+	}
+	return append(resume, restore...), added, nil
+}
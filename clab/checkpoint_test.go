@@ -0,0 +1,65 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCheckpointedNodes(t *testing.T) {
+	labDir, err := ioutil.TempDir("", "clab-checkpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(labDir)
+
+	got, err := checkpointedNodes(labDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("wanted no checkpointed nodes, got %v", got)
+	}
+
+	for _, node := range []string{"srl1", "sros1"} {
+		if err := os.MkdirAll(filepath.Join(labDir, checkpointsDir, node), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ioutil.WriteFile(filepath.Join(labDir, checkpointsDir, "stray"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err = checkpointedNodes(labDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]struct{}{"srl1": {}, "sros1": {}}
+	if !cmp.Equal(got, want) {
+		t.Errorf("diff (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestRestorePlan(t *testing.T) {
+	states := map[string]string{
+		"srl1":   "exited",
+		"srl2":   "paused",
+		"sros1":  "stopped",
+		"linux1": "running",
+		"linux2": "exited",
+	}
+	checkpointed := map[string]struct{}{"srl1": {}, "sros1": {}, "linux1": {}}
+	resume, restore := restorePlan(states, checkpointed)
+	if want := []string{"srl2"}; !cmp.Equal(resume, want) {
+		t.Errorf("resume: wanted %v, got %v", want, resume)
+	}
+	if want := []string{"srl1", "sros1"}; !cmp.Equal(restore, want) {
+		t.Errorf("restore: wanted %v, got %v", want, restore)
+	}
+}
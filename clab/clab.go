@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -20,6 +21,12 @@ import (
 	"github.com/srl-labs/containerlab/utils"
 )
 
+// defaultShutdownTimeout is the time given to the nodes to shut down when no shutdown timeout is set
+const defaultShutdownTimeout = 2 * time.Minute
+
+// defaultStopTimeout is the time given to the containers to exit on SIGTERM when no stop timeout is set
+const defaultStopTimeout = 10 * time.Second
+
 type CLab struct {
 	Config        *Config
 	TopoFile      *TopoFile
@@ -31,6 +38,25 @@ type CLab struct {
 	Dir           *Directory
 
 	timeout time.Duration
+	// time given to the nodes to shut down the NOS before their containers are removed
+	shutdownTimeout time.Duration
+	// path to the directory where the lab directory is created
+	labDirPath string
+	// wiring agent of the remote container host is started once
+	agentOnce sync.Once
+	agentErr  error
+	// per kind deployment concurrency limits set with the options and their limiters
+	kindConcurrency map[string]uint
+	kindLimiters    []*kindLimiter
+	// links wired by CreateLinks, the deployment slots of the nodes are released once their links are wired
+	wiredMu    sync.Mutex
+	wiredLinks map[*types.Link]bool
+	// lab hosts of a multi-host lab, nil when the lab runs on a single host
+	labHosts *labHosts
+	// file with the variables of the topology file template
+	topoVarsFile string
+	// use the local images only, the missing images are not pulled
+	skipPull bool
 }
 
 type Directory struct {
@@ -48,6 +74,13 @@ func WithTimeout(dur time.Duration) ClabOption {
 	}
 }
 
+// WithShutdownTimeout sets the time given to the nodes to shut down gracefully on destroy
+func WithShutdownTimeout(dur time.Duration) ClabOption {
+	return func(c *CLab) {
+		c.shutdownTimeout = dur
+	}
+}
+
 func WithRuntime(name string, rtconfig *runtime.RuntimeConfig) ClabOption {
 	return func(c *CLab) {
 		// define runtime name.
@@ -86,6 +119,13 @@ func WithKeepMgmtNet() ClabOption {
 	}
 }
 
+// WithLabDirPath sets the path to the directory where the lab directory is created
+func WithLabDirPath(p string) ClabOption {
+	return func(c *CLab) {
+		c.labDirPath = p
+	}
+}
+
 func WithTopoFile(file string) ClabOption {
 	return func(c *CLab) {
 		if file == "" {
@@ -158,6 +198,34 @@ func (c *CLab) GlobalRuntime() runtime.ContainerRuntime {
 // returns waitgroups for nodes with static and dynamic IPs,
 // since static nodes are scheduled first
 func (c *CLab) CreateNodes(ctx context.Context, maxWorkers uint,
+	serialNodes map[string]struct{}) (*sync.WaitGroup, *sync.WaitGroup) {
+	c.initKindLimiters()
+	// the nodes with dependencies are deployed in stages
+	if stages, err := c.deployStages(); err == nil && len(stages) > 1 {
+		return c.createNodesInStages(ctx, maxWorkers, serialNodes, stages), nil
+	}
+	if len(c.kindLimiters) == 0 {
+		return c.scheduleNodes(ctx, maxWorkers, serialNodes)
+	}
+	// the workers of the nodes waiting for a deployment slot hold up the scheduling of the other nodes,
+	// the nodes are scheduled in the background, so that the links of the created nodes are wired meanwhile
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		staticIPWg, dynIPWg := c.scheduleNodes(ctx, maxWorkers, serialNodes)
+		if staticIPWg != nil {
+			staticIPWg.Wait()
+		}
+		if dynIPWg != nil {
+			dynIPWg.Wait()
+		}
+	}()
+	return wg, nil
+}
+
+// scheduleNodes schedules the creation of the nodes with static IPs first and then of the nodes with dynamic IPs
+func (c *CLab) scheduleNodes(ctx context.Context, maxWorkers uint,
 	serialNodes map[string]struct{}) (*sync.WaitGroup, *sync.WaitGroup) {
 	staticIPNodes := make(map[string]nodes.Node)
 	dynIPNodes := make(map[string]nodes.Node)
@@ -205,24 +273,42 @@ func (c *CLab) createNodes(ctx context.Context, maxWorkers int,
 					time.Sleep(time.Duration(delay) * time.Second)
 				}
 
+				release, err := c.acquireKindSlots(ctx, node)
+				if err != nil {
+					return
+				}
 				// PreDeploy
-				err := node.PreDeploy(c.Config.Name, c.Dir.LabCA, c.Dir.LabCARoot)
+				err = node.PreDeploy(c.Config.Name, c.Dir.LabCA, c.Dir.LabCARoot)
 				if err != nil {
 					log.Errorf("failed pre-deploy phase for node %q: %v", node.Config().ShortName, err)
+					if release != nil {
+						release()
+					}
+					continue
+				}
+				err = c.syncLabDirBinds(ctx, node)
+				if err != nil {
+					log.Errorf("failed pre-deploy phase for node %q: %v", node.Config().ShortName, err)
+					if release != nil {
+						release()
+					}
 					continue
 				}
 				// Deploy
 				err = node.Deploy(ctx)
 				if err != nil {
 					log.Errorf("failed deploy phase for node %q: %v", node.Config().ShortName, err)
+					if release != nil {
+						release()
+					}
 					continue
 				}
-
 				// set deployment status of a node to created to indicate that it finished creating
 				// this status is checked during link creation to only schedule link creation if both nodes are ready
 				c.m.Lock()
 				node.Config().DeploymentStatus = "created"
 				c.m.Unlock()
+				c.releaseWhenWired(ctx, node, release)
 			case <-ctx.Done():
 				return
 			}
@@ -276,6 +362,9 @@ func (c *CLab) CreateLinks(ctx context.Context, workers uint, postdeploy bool) {
 	wg.Add(int(workers))
 	linksChan := make(chan *types.Link)
 
+	// the links of the vrnetlab nodes wait for their predecessors to be wired
+	preds := c.vrLinkPredecessors()
+
 	log.Debug("creating links...")
 	// wire the links between the nodes based on cabling plan
 	for i := uint(0); i < workers; i++ {
@@ -289,9 +378,10 @@ func (c *CLab) CreateLinks(ctx context.Context, workers uint, postdeploy bool) {
 						return
 					}
 					log.Debugf("Link worker %d received link: %+v", i, link)
-					if err := c.CreateVirtualWiring(link); err != nil {
+					if err := c.wireLink(ctx, link); err != nil {
 						log.Error(err)
 					}
+					c.markLinkWired(link)
 				case <-ctx.Done():
 					return
 				}
@@ -311,7 +401,8 @@ func (c *CLab) CreateLinks(ctx context.Context, workers uint, postdeploy bool) {
 		}
 		for k, link := range linksCopy {
 			c.m.Lock()
-			if link.A.Node.DeploymentStatus == "created" && link.B.Node.DeploymentStatus == "created" {
+			if link.A.Node.DeploymentStatus == "created" && link.B.Node.DeploymentStatus == "created" &&
+				c.predecessorsWired(link, preds) {
 				linksChan <- link
 				delete(linksCopy, k)
 			}
@@ -325,7 +416,45 @@ func (c *CLab) CreateLinks(ctx context.Context, workers uint, postdeploy bool) {
 	wg.Wait()
 }
 
+// predecessorsWired returns true when the links to be wired before the link are wired
+func (c *CLab) predecessorsWired(l *types.Link, preds map[*types.Link][]*types.Link) bool {
+	for _, p := range preds[l] {
+		if !c.linkWired(p) {
+			return false
+		}
+	}
+	return true
+}
+
+// DeleteNodes deletes the nodes of deleteCandidates. With the graceful shutdown requested
+// the nodes are shut down and deleted stage by stage in the reverse dependency order,
+// so that a node is stopped before the nodes it depends on
 func (c *CLab) DeleteNodes(ctx context.Context, workers uint, deleteCandidates map[string]nodes.Node, serialNodes map[string]struct{}) {
+	stages := [][]string{nil}
+	for name := range deleteCandidates {
+		stages[0] = append(stages[0], name)
+	}
+	if c.gracefulShutdown(deleteCandidates) {
+		if s, err := c.destroyStages(deleteCandidates); err == nil {
+			stages = s
+		} else {
+			log.Warnf("failed to order nodes by their dependencies, deleting them at once: %v", err)
+		}
+	}
+	for i, stage := range stages {
+		if len(stages) > 1 {
+			log.Infof("Destroying stage %d: %s", i+1, strings.Join(stage, ", "))
+		}
+		stageNodes := make(map[string]nodes.Node, len(stage))
+		for _, name := range stage {
+			stageNodes[name] = deleteCandidates[name]
+		}
+		c.deleteNodes(ctx, workers, stageNodes, serialNodes)
+	}
+}
+
+// deleteNodes shuts down and deletes the nodes concurrently, the serial nodes are deleted one by one
+func (c *CLab) deleteNodes(ctx context.Context, workers uint, deleteCandidates map[string]nodes.Node, serialNodes map[string]struct{}) {
 
 	wg := new(sync.WaitGroup)
 
@@ -341,6 +470,9 @@ func (c *CLab) DeleteNodes(ctx context.Context, workers uint, deleteCandidates m
 					log.Debugf("Worker %d terminating...", i)
 					return
 				}
+				if err := c.shutdownNode(ctx, n); err != nil {
+					log.Warnf("could not shut down node %q: %v", n.Config().ShortName, err)
+				}
 				err := n.Delete(ctx)
 				if err != nil {
 					log.Errorf("could not remove container %q: %v", n.Config().LongName, err)
@@ -364,7 +496,7 @@ func (c *CLab) DeleteNodes(ctx context.Context, workers uint, deleteCandidates m
 	}
 
 	// send nodes to workers
-	for _, n := range c.Nodes {
+	for _, n := range deleteCandidates {
 		if _, ok := serialNodes[n.Config().LongName]; ok {
 			serialChan <- n
 			continue
@@ -380,6 +512,55 @@ func (c *CLab) DeleteNodes(ctx context.Context, workers uint, deleteCandidates m
 
 }
 
+// gracefulShutdown returns true when the graceful shutdown is requested for the runtime of any of the nodes
+func (*CLab) gracefulShutdown(ns map[string]nodes.Node) bool {
+	for _, n := range ns {
+		if n.GetRuntime().Config().GracefulShutdown {
+			return true
+		}
+	}
+	return false
+}
+
+// shutdownNode gracefully stops the node within its stop timeout. The nodes without the stop timeout are given
+// the shutdown timeout when they implement nodes.Shutdowner, and the default container stop timeout otherwise.
+// The NOS of the node implementing nodes.Shutdowner is shut down first, e.g. the VM of a vrnetlab node is powered down,
+// then the container is sent SIGTERM and is killed if it hasn't exited by the end of the timeout.
+// Nodes are shut down only when the graceful shutdown is requested for their runtime
+func (c *CLab) shutdownNode(ctx context.Context, n nodes.Node) error {
+	cfg := n.Config()
+	if _, ok := noMgmtKinds[cfg.Kind]; ok || !n.GetRuntime().Config().GracefulShutdown {
+		return nil
+	}
+	s, isShutdowner := n.(nodes.Shutdowner)
+	timeout := cfg.StopTimeout
+	switch {
+	case timeout > 0:
+	case !isShutdowner:
+		timeout = defaultStopTimeout
+	case c.shutdownTimeout > 0:
+		timeout = c.shutdownTimeout
+	default:
+		timeout = defaultShutdownTimeout
+	}
+	deadline := time.Now().Add(timeout)
+	if isShutdowner {
+		sctx, cancel := context.WithDeadline(ctx, deadline)
+		defer cancel()
+		log.Infof("Shutting down node %s", cfg.ShortName)
+		if err := s.Shutdown(sctx); err != nil {
+			log.Warnf("could not shut down the NOS of node %q: %v", cfg.ShortName, err)
+		}
+	}
+	// the container is given the rest of the timeout to stop
+	rest := time.Until(deadline).Truncate(time.Second)
+	if rest < 0 {
+		rest = 0
+	}
+	log.Debugf("Stopping container %s within %s", cfg.LongName, rest)
+	return n.GetRuntime().GracefulStopContainer(ctx, cfg.LongName, rest)
+}
+
 func (c *CLab) ListContainers(ctx context.Context, labels []*types.GenericFilter) ([]types.GenericContainer, error) {
 	var containers []types.GenericContainer
 
@@ -394,6 +575,12 @@ func (c *CLab) ListContainers(ctx context.Context, labels []*types.GenericFilter
 }
 
 func (c *CLab) GetNodeRuntime(query string) (runtime.ContainerRuntime, error) {
+	// the container names rendered with the container name template don't follow the default naming
+	for _, n := range c.Nodes {
+		if n.Config().LongName == query {
+			return n.GetRuntime(), nil
+		}
+	}
 	shortName, err := getShortName(c.Config.Name, query)
 	if err != nil {
 		return nil, err
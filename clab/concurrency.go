@@ -0,0 +1,184 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// kindLimiter limits the number of the nodes of the kinds matching its pattern deployed at once
+type kindLimiter struct {
+	pattern string
+	slots   chan struct{}
+}
+
+// WithKindConcurrency sets the per kind deployment concurrency limits,
+// the limits override the ones set for the same kind patterns in the topology settings
+func WithKindConcurrency(limits map[string]uint) ClabOption {
+	return func(c *CLab) {
+		c.kindConcurrency = limits
+	}
+}
+
+// ParseKindConcurrency parses the kind concurrency limits in the format of <kind-pattern>=<limit>
+func ParseKindConcurrency(limits []string) (map[string]uint, error) {
+	res := make(map[string]uint, len(limits))
+	for _, l := range limits {
+		i := strings.LastIndex(l, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("malformed concurrency limit %q, use <kind-pattern>=<limit>", l)
+		}
+		n, err := strconv.ParseUint(l[i+1:], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("malformed concurrency limit %q, use <kind-pattern>=<limit>", l)
+		}
+		res[l[:i]] = uint(n)
+	}
+	return res, nil
+}
+
+// kindConcurrencyLimits returns the per kind concurrency limits of the topology settings and the options
+func (c *CLab) kindConcurrencyLimits() map[string]uint {
+	limits := map[string]uint{}
+	for p, n := range c.Config.Settings.GetConcurrency() {
+		limits[p] = n
+	}
+	for p, n := range c.kindConcurrency {
+		limits[p] = n
+	}
+	return limits
+}
+
+// verifyKindConcurrency verifies the kind patterns and the values of the concurrency limits
+func (c *CLab) verifyKindConcurrency() error {
+	for p, n := range c.kindConcurrencyLimits() {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid concurrency kind pattern %q: %v", p, err)
+		}
+		if n == 0 {
+			return fmt.Errorf("concurrency limit of %q must be greater than 0", p)
+		}
+	}
+	return nil
+}
+
+// initKindLimiters creates the limiters of the concurrency limits, sorted by their patterns,
+// so that the slots of the limiters matching a node are always taken in the same order
+func (c *CLab) initKindLimiters() {
+	limits := c.kindConcurrencyLimits()
+	c.kindLimiters = make([]*kindLimiter, 0, len(limits))
+	for p, n := range limits {
+		if n == 0 {
+			continue
+		}
+		c.kindLimiters = append(c.kindLimiters, &kindLimiter{pattern: p, slots: make(chan struct{}, n)})
+	}
+	sort.Slice(c.kindLimiters, func(i, j int) bool { return c.kindLimiters[i].pattern < c.kindLimiters[j].pattern })
+}
+
+// acquireKindSlots blocks until the node gets a slot of every limiter matching its kind
+// and returns the function releasing the slots, nil if no limiter matches the node kind
+func (c *CLab) acquireKindSlots(ctx context.Context, n nodes.Node) (func(), error) {
+	var taken []*kindLimiter
+	release := func() {
+		for _, l := range taken {
+			<-l.slots
+		}
+	}
+	for _, l := range c.kindLimiters {
+		if ok, _ := path.Match(l.pattern, n.Config().Kind); !ok {
+			continue
+		}
+		select {
+		case l.slots <- struct{}{}:
+			taken = append(taken, l)
+		default:
+			log.Infof("Node %s is waiting for a deployment slot of %q kinds", n.Config().ShortName, l.pattern)
+			select {
+			case l.slots <- struct{}{}:
+				taken = append(taken, l)
+			case <-ctx.Done():
+				release()
+				return nil, ctx.Err()
+			}
+		}
+	}
+	if len(taken) == 0 {
+		return nil, nil
+	}
+	return release, nil
+}
+
+// linkPollInterval is the interval the wiring of the links of a node holding a deployment slot is checked at
+const linkPollInterval = 100 * time.Millisecond
+
+// releaseWhenWired releases the deployment slots of the created node once its links to the created nodes are wired,
+// the links to the nodes created later are wired once those are created.
+// The slots are not held until the node is ready, as the vrnetlab nodes wait for their links before they boot the VM
+// and the peers waiting for a slot would never get their links wired
+func (c *CLab) releaseWhenWired(ctx context.Context, n nodes.Node, release func()) {
+	if release == nil {
+		return
+	}
+	go func() {
+		defer release()
+		t := time.NewTicker(linkPollInterval)
+		defer t.Stop()
+		for !c.nodeLinksWired(n.Config()) {
+			select {
+			case <-t.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// nodeLinksWired returns true when the links of the node to the created nodes are wired
+func (c *CLab) nodeLinksWired(cfg *types.NodeConfig) bool {
+	c.m.RLock()
+	defer c.m.RUnlock()
+	for _, l := range c.Links {
+		peer := l.B.Node
+		switch cfg {
+		case l.A.Node:
+		case l.B.Node:
+			peer = l.A.Node
+		default:
+			continue
+		}
+		if peer.DeploymentStatus == "created" && !c.linkWired(l) {
+			return false
+		}
+	}
+	return true
+}
+
+// markLinkWired records the link as wired, whether its wiring succeeded or not
+func (c *CLab) markLinkWired(l *types.Link) {
+	c.wiredMu.Lock()
+	defer c.wiredMu.Unlock()
+	if c.wiredLinks == nil {
+		c.wiredLinks = map[*types.Link]bool{}
+	}
+	c.wiredLinks[l] = true
+}
+
+// linkWired returns true when the link is wired
+func (c *CLab) linkWired(l *types.Link) bool {
+	c.wiredMu.Lock()
+	defer c.wiredMu.Unlock()
+	return c.wiredLinks[l]
+}
@@ -0,0 +1,128 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/nodes"
+)
+
+func TestParseKindConcurrency(t *testing.T) {
+	got, err := ParseKindConcurrency([]string{"vr-*=4", "vr-xrv9k=1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]uint{"vr-*": 4, "vr-xrv9k": 1}
+	if !cmp.Equal(got, want) {
+		t.Errorf("diff (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	for _, l := range []string{"vr-*", "=4", "vr-*=four"} {
+		if _, err := ParseKindConcurrency([]string{l}); err == nil {
+			t.Errorf("%q: wanted error", l)
+		}
+	}
+}
+
+func TestAcquireKindSlots(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo1.yml"), WithKindConcurrency(map[string]uint{"sr*": 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.verifyKindConcurrency(); err != nil {
+		t.Fatal(err)
+	}
+	c.initKindLimiters()
+
+	ctx := context.Background()
+	release, err := c.acquireKindSlots(ctx, c.Nodes["node1"])
+	if err != nil || release == nil {
+		t.Fatalf("wanted a slot, got %v", err)
+	}
+
+	// the single slot of srl nodes is taken, node2 waits until it is released
+	tctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.acquireKindSlots(tctx, c.Nodes["node2"]); err == nil {
+		t.Fatal("wanted node2 to wait for the slot")
+	}
+	release()
+	release, err = c.acquireKindSlots(ctx, c.Nodes["node2"])
+	if err != nil || release == nil {
+		t.Fatalf("wanted a slot, got %v", err)
+	}
+	release()
+
+	c.kindConcurrency = map[string]uint{"[": 1}
+	if err := c.verifyKindConcurrency(); err == nil {
+		t.Error("wanted invalid pattern error")
+	}
+}
+
+// fakeVrNode is a vrnetlab node which VM boots once its links are wired
+type fakeVrNode struct {
+	nodes.Node
+	c *CLab
+}
+
+func (*fakeVrNode) PreDeploy(_, _, _ string) error { return nil }
+
+func (*fakeVrNode) Deploy(context.Context) error { return nil }
+
+func (n *fakeVrNode) Ready(context.Context) (bool, error) {
+	for _, l := range n.c.Links {
+		if (l.A.Node == n.Config() || l.B.Node == n.Config()) && !n.c.linkWired(l) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func TestCreateNodesKindConcurrency(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo35.yml"), WithKindConcurrency(map[string]uint{"vr-*": 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, n := range c.Nodes {
+		c.Nodes[name] = &fakeVrNode{Node: n, c: c}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// the links are wired once both their nodes are created, as CreateLinks does
+	go func() {
+		for ctx.Err() == nil {
+			for _, l := range c.Links {
+				c.m.RLock()
+				created := l.A.Node.DeploymentStatus == "created" && l.B.Node.DeploymentStatus == "created"
+				c.m.RUnlock()
+				if created {
+					c.markLinkWired(l)
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	// the single slot of vr nodes is released once vr1 or vr2 is created, before its VM can boot
+	staticIPWg, dynIPWg := c.CreateNodes(ctx, 1, nil)
+	for _, wg := range []*sync.WaitGroup{staticIPWg, dynIPWg} {
+		if wg != nil {
+			wg.Wait()
+		}
+	}
+	if ctx.Err() != nil {
+		t.Fatal("nodes were not created before the timeout")
+	}
+	for name, n := range c.Nodes {
+		if n.Config().DeploymentStatus != "created" {
+			t.Errorf("node %s was not created", name)
+		}
+	}
+}
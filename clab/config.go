@@ -14,9 +14,11 @@ import (
 	"sort"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/mitchellh/go-homedir"
 	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/cert"
 	"github.com/srl-labs/containerlab/nodes"
 	clabRuntimes "github.com/srl-labs/containerlab/runtime"
 	"github.com/srl-labs/containerlab/types"
@@ -35,6 +37,9 @@ const (
 	hostNSPath = "__host"
 	// veth link mtu
 	defaultVethLinkMTU = 9500
+	// range of the MTU values of the veth links
+	minLinkMTU = 68
+	maxLinkMTU = 65535
 	// containerlab's reserved OUI
 	clabOUI = "aa:c1:ab"
 
@@ -45,6 +50,7 @@ const (
 	NodeTypeLabel     = "clab-node-type"
 	NodeGroupLabel    = "clab-node-group"
 	NodeLabDirLabel   = "clab-node-lab-dir"
+	LabDirLabel       = "clab-lab-dir"
 	TopoFileLabel     = "clab-topo-file"
 )
 
@@ -58,12 +64,14 @@ var kinds = []string{
 	"vr-n9kv",
 	"vr-sros",
 	"vr-vmx",
+	"vr-vqfx",
 	"vr-xrv",
 	"vr-xrv9k",
 	"vr-veos",
 	"vr-pan",
 	"vr-csr",
 	"vr-ros",
+	"generic_vm",
 	"linux",
 	"bridge",
 	"ovs-bridge",
@@ -74,11 +82,17 @@ var kinds = []string{
 
 // Config defines lab configuration as it is provided in the YAML file
 type Config struct {
-	Name       string          `json:"name,omitempty"`
-	Prefix     *string         `json:"prefix,omitempty"`
-	Mgmt       *types.MgmtNet  `json:"mgmt,omitempty"`
-	Topology   *types.Topology `json:"topology,omitempty"`
-	ConfigPath string          `yaml:"config_path,omitempty"`
+	Name       string                  `yaml:"name,omitempty" json:"name,omitempty"`
+	Prefix     *string                 `yaml:"prefix,omitempty" json:"prefix,omitempty"`
+	Mgmt       *types.MgmtNet          `yaml:"mgmt,omitempty" json:"mgmt,omitempty"`
+	Topology   *types.Topology         `yaml:"topology,omitempty" json:"topology,omitempty"`
+	ConfigPath string                  `yaml:"config_path,omitempty"`
+	Quota      *types.QuotaConfig      `yaml:"quota,omitempty" json:"quota,omitempty"`
+	CA         *types.CAConfig         `yaml:"ca,omitempty" json:"ca,omitempty"`
+	JumpHost   *types.JumpHostConfig   `yaml:"jump-host,omitempty" json:"jump-host,omitempty"`
+	NTP        *types.NTPConfig        `yaml:"ntp,omitempty" json:"ntp,omitempty"`
+	Settings   *types.Settings         `yaml:"settings,omitempty" json:"settings,omitempty"`
+	Controller *types.ControllerConfig `yaml:"controller,omitempty" json:"controller,omitempty"`
 }
 
 // ParseTopology parses the lab topology
@@ -86,8 +100,20 @@ func (c *CLab) parseTopology() error {
 	log.Infof("Parsing & checking topology file: %s", c.TopoFile.fullName)
 	log.Debugf("Lab name: %s", c.Config.Name)
 
-	if c.Config.ConfigPath == "" {
+	var err error
+	switch {
+	// lab directory path set with a flag takes precedence over the topology setting
+	case c.labDirPath != "":
+		if c.Config.ConfigPath, err = resolvePath(c.labDirPath); err != nil {
+			return err
+		}
+	case c.Config.ConfigPath == "":
 		c.Config.ConfigPath, _ = filepath.Abs(os.Getenv("PWD"))
+	// relative config_path is resolved against the topology file directory, same as the lab-dir of the nodes
+	default:
+		if c.Config.ConfigPath, err = c.resolveTopoPath(c.Config.ConfigPath); err != nil {
+			return err
+		}
 	}
 	if c.Config.Prefix == nil {
 		c.Config.Prefix = new(string)
@@ -105,6 +131,48 @@ func (c *CLab) parseTopology() error {
 	c.Dir.LabCARoot = filepath.Join(c.Dir.LabCA, "root")
 	c.Dir.LabGraph = filepath.Join(c.Dir.Lab, "graph")
 
+	// external CA files are relative to the topology file
+	if ca := c.Config.CA; ca != nil {
+		for _, p := range []*string{&ca.Cert, &ca.Key} {
+			if *p == "" || cert.IsPEM(*p) {
+				continue
+			}
+			if *p, err = c.resolveTopoPath(*p); err != nil {
+				return err
+			}
+		}
+	}
+
+	if b := c.Config.Settings.GetCertificateAuthority(); b != nil && b.StepCA != nil && b.StepCA.PasswordFile != "" {
+		if b.StepCA.PasswordFile, err = c.resolveTopoPath(b.StepCA.PasswordFile); err != nil {
+			return err
+		}
+	}
+
+	// cosign key is either a file relative to the topology file or a KMS URI
+	if v := c.Config.Settings.GetImageVerification(); v != nil && v.CosignKey != "" && !strings.Contains(v.CosignKey, "://") {
+		if v.CosignKey, err = c.resolveTopoPath(v.CosignKey); err != nil {
+			return err
+		}
+	}
+
+	if err := c.loadPlugins(); err != nil {
+		return err
+	}
+
+	if err := c.expandTopology(); err != nil {
+		return err
+	}
+	c.Config.Topology.ApplyGroups()
+
+	if err := c.addJumpHost(); err != nil {
+		return err
+	}
+
+	if err := c.addNTPServer(); err != nil {
+		return err
+	}
+
 	// initialize Nodes and Links variable
 	c.Nodes = make(map[string]nodes.Node)
 	c.Links = make(map[int]*types.Link)
@@ -116,6 +184,11 @@ func (c *CLab) parseTopology() error {
 	}
 	sort.Strings(nodeNames)
 
+	// the nodes deployed on the other lab hosts of a multi-host lab are skipped
+	if err := c.initLabHosts(); err != nil {
+		return err
+	}
+
 	// collect node runtimes in a map[NodeName] -> RuntimeName
 	var nodeRuntimes = make(map[string]string)
 
@@ -137,29 +210,38 @@ func (c *CLab) parseTopology() error {
 	}
 
 	// initialize any extra runtimes
-	for _, r := range nodeRuntimes {
+	for _, nodeName := range nodeNames {
+		r := nodeRuntimes[nodeName]
 		// this is the case for already init'ed runtimes
-		if _, ok := c.Runtimes[r]; ok {
+		if _, ok := c.Runtimes[r]; ok || r == c.globalRuntime || c.isRemoteNode(nodeName) {
 			continue
 		}
 
-		if rInit, ok := clabRuntimes.ContainerRuntimes[r]; ok {
-
-			newRuntime := rInit()
-			defaultConfig := c.Runtimes[c.globalRuntime].Config()
-			err := newRuntime.Init(
-				clabRuntimes.WithConfig(&defaultConfig),
-			)
-			if err != nil {
-				return fmt.Errorf("failed to init the container runtime: %s", err)
-			}
-
-			c.Runtimes[r] = newRuntime
+		rInit, ok := clabRuntimes.ContainerRuntimes[r]
+		if !ok {
+			return fmt.Errorf("unknown container runtime %q of node %q", r, nodeName)
+		}
+		newRuntime := rInit()
+		var defaultConfig clabRuntimes.RuntimeConfig
+		if gr, ok := c.Runtimes[c.globalRuntime]; ok {
+			defaultConfig = gr.Config()
 		}
+		// extra runtimes attach the nodes to the same management network
+		err := newRuntime.Init(
+			clabRuntimes.WithConfig(&defaultConfig),
+			clabRuntimes.WithMgmtNet(c.Config.Mgmt),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to init the container runtime: %s", err)
+		}
+
+		c.Runtimes[r] = newRuntime
 	}
 
-	var err error
 	for idx, nodeName := range nodeNames {
+		if c.isRemoteNode(nodeName) {
+			continue
+		}
 		err = c.NewNode(nodeName, nodeRuntimes[nodeName], c.Config.Topology.Nodes[nodeName], idx)
 		if err != nil {
 			return err
@@ -167,8 +249,19 @@ func (c *CLab) parseTopology() error {
 	}
 	for i, l := range c.Config.Topology.Links {
 		// i represents the endpoint integer and l provide the link struct
-		c.Links[i] = c.NewLink(l)
+		link, err := c.newStitchedLink(i, l)
+		if err != nil {
+			return err
+		}
+		// the links between the nodes of the other lab hosts are skipped, the links are kept numbered contiguously
+		if link != nil {
+			c.Links[len(c.Links)] = link
+		}
+	}
+	if c.labHosts != nil {
+		c.dropRemoteDependencies()
 	}
+	c.enableBridgeVLANFiltering()
 
 	// set any containerlab defaults after we've parsed the input
 	c.setDefaults()
@@ -196,6 +289,7 @@ func (c *CLab) NewNode(nodeName, nodeRuntime string, nodeDef *types.NodeDefiniti
 		log.Errorf("failed to initialize node %q: %v", nodeCfg.ShortName, err)
 		return fmt.Errorf("failed to initialize node %q: %v", nodeCfg.ShortName, err)
 	}
+	nodes.MountLicense(n.Config())
 
 	n.Config().Labels = utils.MergeStringMaps(n.Config().Labels, map[string]string{
 		ContainerlabLabel: c.Config.Name,
@@ -203,8 +297,10 @@ func (c *CLab) NewNode(nodeName, nodeRuntime string, nodeDef *types.NodeDefiniti
 		NodeKindLabel:     n.Config().Kind,
 		NodeTypeLabel:     n.Config().NodeType,
 		NodeGroupLabel:    n.Config().Group,
-		NodeLabDirLabel:   n.Config().LabDir,
-		TopoFileLabel:     c.TopoFile.path,
+		// the paths in the labels are the host paths, as seen by the container runtime
+		NodeLabDirLabel: utils.HostPath(n.Config().LabDir),
+		LabDirLabel:     utils.HostPath(c.Dir.Lab),
+		TopoFileLabel:   utils.HostPath(c.TopoFile.path),
 	})
 	c.Nodes[nodeName] = n
 
@@ -212,14 +308,18 @@ func (c *CLab) NewNode(nodeName, nodeRuntime string, nodeDef *types.NodeDefiniti
 }
 
 func (c *CLab) createNodeCfg(nodeName string, nodeDef *types.NodeDefinition, idx int) (*types.NodeConfig, error) {
-	longName := strings.Join([]string{c.Config.Name, nodeName}, "-")
-	if c.Config.Prefix != nil && *c.Config.Prefix != "" {
-		longName = strings.Join([]string{*c.Config.Prefix, longName}, "-")
+	longName, err := c.longName(nodeName)
+	if err != nil {
+		return nil, err
+	}
+	env, err := c.nodeEnv(nodeName)
+	if err != nil {
+		return nil, err
 	}
 	nodeCfg := &types.NodeConfig{
 		ShortName:       nodeName,
 		LongName:        longName,
-		Fqdn:            strings.Join([]string{nodeName, c.Config.Name, ".io"}, "."),
+		Fqdn:            strings.Join([]string{nodeName, c.Config.Name, "io"}, "."),
 		LabDir:          filepath.Join(c.Dir.Lab, nodeName),
 		Index:           idx,
 		Group:           c.Config.Topology.GetNodeGroup(nodeName),
@@ -231,11 +331,23 @@ func (c *CLab) createNodeCfg(nodeName string, nodeDef *types.NodeDefinition, idx
 		Entrypoint:      c.Config.Topology.GetNodeEntrypoint(nodeName),
 		Cmd:             c.Config.Topology.GetNodeCmd(nodeName),
 		Exec:            c.Config.Topology.GetNodeExec(nodeName),
-		Env:             c.Config.Topology.GetNodeEnv(nodeName),
+		Env:             env,
 		NetworkMode:     strings.ToLower(c.Config.Topology.GetNodeNetworkMode(nodeName)),
 		MgmtIPv4Address: nodeDef.GetMgmtIPv4(),
 		MgmtIPv6Address: nodeDef.GetMgmtIPv6(),
+		Interfaces:      nodeDef.GetInterfaces(),
+		PeerHosts:       c.Config.Topology.GetNodePeerHosts(nodeName),
+		Certificate:     c.Config.Topology.GetNodeCertificate(nodeName),
+		TLS:             c.Config.Topology.GetNodeTLS(nodeName),
+		Timezone:        c.Config.Topology.GetNodeTimezone(nodeName),
+		OnReady:         c.Config.Topology.GetNodeOnReady(nodeName),
+		OnExit:          c.Config.Topology.GetNodeOnExit(nodeName),
+		Hooks:           c.Config.Topology.GetNodeHooks(nodeName),
+		VM:              c.Config.Topology.GetNodeVM(nodeName),
+		DNS:             c.Config.Topology.GetNodeDNS(nodeName),
 		Publish:         c.Config.Topology.GetNodePublish(nodeName),
+		Import:          c.Config.Topology.GetNodeImport(nodeName),
+		DNSAliases:      []string{nodeName, strings.Join([]string{nodeName, c.Config.Name}, ".")},
 		Sysctls:         make(map[string]string),
 		Endpoints:       make([]*types.Endpoint, 0),
 		Sandbox:         c.Config.Topology.GetNodeSandbox(nodeName),
@@ -243,6 +355,8 @@ func (c *CLab) createNodeCfg(nodeName string, nodeDef *types.NodeDefinition, idx
 		Runtime:         c.Config.Topology.GetNodeRuntime(nodeName),
 		CPU:             c.Config.Topology.GetNodeCPU(nodeName),
 		RAM:             c.Config.Topology.GetNodeRAM(nodeName),
+		Memory:          c.Config.Topology.GetNodeMemory(nodeName),
+		CPUSet:          c.Config.Topology.GetNodeCPUSet(nodeName),
 		StartupDelay:    c.Config.Topology.GetNodeStartupDelay(nodeName),
 
 		// Extras
@@ -250,7 +364,6 @@ func (c *CLab) createNodeCfg(nodeName string, nodeDef *types.NodeDefinition, idx
 	}
 
 	log.Debugf("node config: %+v", nodeCfg)
-	var err error
 	// initialize config
 	nodeCfg.StartupConfig, err = c.Config.Topology.GetNodeStartupConfig(nodeCfg.ShortName)
 	if err != nil {
@@ -262,22 +375,136 @@ func (c *CLab) createNodeCfg(nodeName string, nodeDef *types.NodeDefinition, idx
 	// initialize license field
 	nodeCfg.License, err = c.Config.Topology.GetNodeLicense(nodeCfg.ShortName)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("node %q: license file is not readable: %v", nodeCfg.ShortName, err)
 	}
+	// the node image built from a Dockerfile replaces the image of the node
+	if b := c.Config.Topology.GetNodeBuild(nodeName); b != nil {
+		if nodeCfg.Build, nodeCfg.Image, err = c.resolveBuild(b); err != nil {
+			return nil, fmt.Errorf("node %q: %v", nodeName, err)
+		}
+	}
+	// lab directory of a node can be moved out of the lab directory
+	if d := nodeDef.GetLabDir(); d != "" {
+		if nodeCfg.LabDir, err = c.resolveTopoPath(d); err != nil {
+			return nil, err
+		}
+	}
+
 	// initialize bind mounts
 	binds := c.Config.Topology.GetNodeBinds(nodeName)
 	err = resolveBindPaths(binds, nodeCfg.LabDir)
 	if err != nil {
 		return nil, err
 	}
+	for _, b := range binds {
+		if err := types.ValidateBind(b); err != nil {
+			return nil, fmt.Errorf("node %q: %v", nodeName, err)
+		}
+	}
+	for _, t := range c.Config.Topology.GetNodeTmpfs(nodeName) {
+		p, opts, err := types.ParseTmpfs(t)
+		if err != nil {
+			return nil, fmt.Errorf("node %q: %v", nodeName, err)
+		}
+		if nodeCfg.Tmpfs == nil {
+			nodeCfg.Tmpfs = map[string]string{}
+		}
+		if _, ok := nodeCfg.Tmpfs[p]; ok {
+			return nil, fmt.Errorf("node %q: tmpfs %s is mounted more than once", nodeName, p)
+		}
+		nodeCfg.Tmpfs[p] = opts
+	}
+	// the sysctls of the topology take precedence over the ones set by the node kinds
+	for k, v := range c.Config.Topology.GetNodeSysctls(nodeName) {
+		nodeCfg.Sysctls[k] = v
+	}
+	if err := types.ValidateSysctls(nodeCfg.Sysctls); err != nil {
+		return nil, fmt.Errorf("node %q: %v", nodeName, err)
+	}
+	if nodeCfg.CapAdd, err = types.NormalizeCapabilities(c.Config.Topology.GetNodeCapAdd(nodeName)); err != nil {
+		return nil, fmt.Errorf("node %q: cap-add: %v", nodeName, err)
+	}
+	if nodeCfg.CapDrop, err = types.NormalizeCapabilities(c.Config.Topology.GetNodeCapDrop(nodeName)); err != nil {
+		return nil, fmt.Errorf("node %q: cap-drop: %v", nodeName, err)
+	}
 	nodeCfg.Binds = binds
+	nodeCfg.Bridge = c.Config.Topology.GetNodeBridge(nodeName)
+	nodeCfg.Credentials = c.Config.Topology.GetNodeCredentials(nodeName)
+	nodeCfg.WaitFor = c.Config.Topology.GetNodeWaitFor(nodeName)
+	nodeCfg.DependsOn = c.Config.Topology.GetNodeDependsOn(nodeName)
+	if st := c.Config.Topology.GetNodeStopTimeout(nodeName); st != "" {
+		d, err := time.ParseDuration(st)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("node %q: invalid stop-timeout %q, expected a positive duration, e.g. 3m", nodeName, st)
+		}
+		nodeCfg.StopTimeout = d
+	}
+	nodeCfg.MgmtNetem = c.Config.Topology.GetNodeMgmtNetem(nodeName)
+	if n := nodeCfg.MgmtNetem; n != nil {
+		if err := n.Validate(); err != nil {
+			return nil, fmt.Errorf("node %q: %v", nodeName, err)
+		}
+	}
+	nodeCfg.StaticRoutes = c.Config.Topology.GetNodeStaticRoutes(nodeName)
+	for _, r := range nodeCfg.StaticRoutes {
+		if err := r.Validate(); err != nil {
+			return nil, fmt.Errorf("node %q: %v", nodeName, err)
+		}
+	}
+	nodeCfg.DefaultGateway = c.Config.Topology.GetNodeDefaultGateway(nodeName)
+	if err := types.ValidateDefaultGateway(nodeCfg.DefaultGateway); err != nil {
+		return nil, fmt.Errorf("node %q: %v", nodeName, err)
+	}
+	if _, _, err := nodeCfg.ResourceLimits(); err != nil {
+		return nil, fmt.Errorf("node %q: %v", nodeName, err)
+	}
+	nodeCfg.InterfaceProfile = c.Config.Topology.GetNodeInterfaceProfile(nodeName)
+	if nodeCfg.InterfaceProfile == nil {
+		nodeCfg.InterfaceProfile = nodes.DefaultInterfaceProfiles[nodeCfg.Kind]
+	}
+	if p := nodeCfg.InterfaceProfile; p != nil {
+		if err := p.Validate(); err != nil {
+			return nil, fmt.Errorf("node %q: %v", nodeName, err)
+		}
+	}
+	persist, err := parsePersist(c.Config.Topology.GetNodePersist(nodeName), nodeCfg.LabDir, c.persistVolPrefix())
+	if err != nil {
+		return nil, fmt.Errorf("node %q: %v", nodeName, err)
+	}
+	if len(persist) > 0 {
+		// binds may be shared with other nodes of the kind, so persist binds are added to a copy
+		nodeCfg.Binds = append(append([]string{}, binds...), persistBinds(persist)...)
+	}
+	if err := setTimezone(nodeCfg); err != nil {
+		return nil, err
+	}
+	if err := setDNS(nodeCfg); err != nil {
+		return nil, err
+	}
 	nodeCfg.PortSet, nodeCfg.PortBindings, err = c.Config.Topology.GetNodePorts(nodeName)
 	if err != nil {
 		return nil, err
 	}
+	if err := applyHostPublish(nodeCfg); err != nil {
+		return nil, err
+	}
 	nodeCfg.Labels = c.Config.Topology.GetNodeLabels(nodeCfg.ShortName)
 
 	nodeCfg.Config = c.Config.Topology.GetNodeConfigDispatcher(nodeCfg.ShortName)
+	// config templates may be shared with other nodes of the kind, so the resolved paths are set to a copy
+	if tmpls := nodeCfg.Config.GetTemplates(); len(tmpls) != 0 {
+		nodeCfg.Config.Templates = make([]string, len(tmpls))
+		for i, p := range tmpls {
+			if nodeCfg.Config.Templates[i], err = c.resolveTopoPath(p); err != nil {
+				return nil, fmt.Errorf("node %q: %v", nodeName, err)
+			}
+		}
+	}
+	if nodeCfg.Config.HasPush() {
+		if _, err := nodes.PushTemplate(nodeCfg); err != nil {
+			return nil, fmt.Errorf("node %q: %v", nodeName, err)
+		}
+	}
 
 	return nodeCfg, nil
 }
@@ -288,13 +515,28 @@ func (c *CLab) NewLink(l *types.LinkConfig) *types.Link {
 		log.Fatalf("endpoint %q has wrong syntax, unexpected number of items", l.Endpoints)
 	}
 
-	return &types.Link{
+	link := &types.Link{
 		A:      c.NewEndpoint(l.Endpoints[0]),
 		B:      c.NewEndpoint(l.Endpoints[1]),
 		MTU:    defaultVethLinkMTU,
 		Labels: l.Labels,
 		Vars:   l.Vars,
+		VLAN:   l.VLAN,
+		Type:   l.Type,
+		Mode:   l.Mode,
 	}
+	// link MTU takes precedence over the lab-wide setting
+	switch {
+	case l.MTU != 0:
+		link.MTU = l.MTU
+	case c.Config.Settings.GetLinkMTU() != 0:
+		link.MTU = c.Config.Settings.GetLinkMTU()
+	}
+	if l.Netem.IsSet() {
+		netem := l.Netem
+		link.Netem = &netem
+	}
+	return link
 }
 
 // NewEndpoint initializes a new endpoint object
@@ -357,15 +599,57 @@ func (c *CLab) NewEndpoint(e string) *types.Endpoint {
 // CheckTopologyDefinition runs topology checks and returns any errors found
 func (c *CLab) CheckTopologyDefinition(ctx context.Context) error {
 	var err error
+	if err = c.verifyImportedNodes(); err != nil {
+		return err
+	}
+	if err = c.verifyContainerNames(); err != nil {
+		return err
+	}
+	if err = c.verifyHooks(); err != nil {
+		return err
+	}
+	if err = c.verifyLicenses(); err != nil {
+		return err
+	}
 	if err = c.verifyBridgesExist(); err != nil {
 		return err
 	}
+	if err = c.verifyLinkVLANs(); err != nil {
+		return err
+	}
+	if err = c.verifyLinkNetem(); err != nil {
+		return err
+	}
+	if err = c.verifyLinkMTU(); err != nil {
+		return err
+	}
+	if err = c.verifyLinkTypes(); err != nil {
+		return err
+	}
 	if err = c.verifyLinks(); err != nil {
 		return err
 	}
+	if err = c.verifyVrEndpoints(); err != nil {
+		return err
+	}
+	if err = c.verifyVMs(); err != nil {
+		return err
+	}
+	if err = c.verifyPublish(); err != nil {
+		return err
+	}
+	if err = c.verifyDependencies(); err != nil {
+		return err
+	}
+	if err = c.verifyKindConcurrency(); err != nil {
+		return err
+	}
 	if err = c.verifyRootNetnsInterfaceUniqueness(); err != nil {
 		return err
 	}
+	if err = c.verifyMgmtAddresses(); err != nil {
+		return err
+	}
 	if err = c.VerifyContainersUniqueness(ctx); err != nil {
 		return err
 	}
@@ -375,15 +659,28 @@ func (c *CLab) CheckTopologyDefinition(ctx context.Context) error {
 	if err = c.verifyHostIfaces(); err != nil {
 		return err
 	}
+	if err = c.verifyController(); err != nil {
+		return err
+	}
+	if err = c.verifyLabDirVisible(); err != nil {
+		return err
+	}
 	return c.VerifyImages(ctx)
 }
 
 // VerifyBridgeExists verifies if every node of kind=bridge/ovs-bridge exists on the lab host
+// or is to be created by containerlab
 func (c *CLab) verifyBridgesExist() error {
+	if err := c.verifyOvsInstalled(); err != nil {
+		return err
+	}
 	for name, node := range c.Nodes {
 		if node.Config().Kind == nodes.NodeKindBridge || node.Config().Kind == nodes.NodeKindOVS {
+			if node.Config().Bridge.GetCreate() {
+				continue
+			}
 			if _, err := netlink.LinkByName(name); err != nil {
-				return fmt.Errorf("bridge %s is referenced in the endpoints section but was not found in the default network namespace", name)
+				return fmt.Errorf("bridge %s is referenced in the endpoints section but was not found in the default network namespace, set `bridge: {create: true}` on the node to have it created", name)
 			}
 		}
 	}
@@ -399,6 +696,10 @@ func (c *CLab) verifyLinks() error {
 			if err := checkEndpoint(e); err != nil {
 				return err
 			}
+			// host NIC is shared by the macvlan and ipvlan links
+			if (lc.Type == linkTypeMacvlan || lc.Type == linkTypeIPvlan) && strings.HasPrefix(e, "host:") {
+				continue
+			}
 			if _, ok := endpoints[e]; ok {
 				dups = append(dups, e)
 			}
@@ -415,6 +716,10 @@ func (c *CLab) verifyLinks() error {
 // either pullable or is available in the local image store
 func (c *CLab) VerifyImages(ctx context.Context) error {
 
+	if err := c.buildImages(ctx); err != nil {
+		return err
+	}
+
 	images := make(map[string]string)
 
 	for _, node := range c.Nodes {
@@ -423,18 +728,28 @@ func (c *CLab) VerifyImages(ctx context.Context) error {
 			if imageName == "" {
 				return fmt.Errorf("missing required image for node %q", node.Config().ShortName)
 			}
+			// the images built from the Dockerfiles are not pulled
+			if node.Config().Build != nil && imageName == node.Config().Image {
+				continue
+			}
 			images[imageName] = node.GetRuntime().GetName()
 		}
 
 	}
 
-	for image, runtimeName := range images {
-		err := c.Runtimes[runtimeName].PullImageIfRequired(ctx, image)
-		if err != nil {
-			return err
+	pinned, err := c.verifyImagesTrust(ctx, images)
+	if err != nil {
+		return err
+	}
+	if len(pinned) != 0 {
+		c.pinVerifiedImages(pinned)
+		for image, p := range pinned {
+			images[p] = images[image]
+			delete(images, image)
 		}
 	}
-	return nil
+
+	return c.pullImages(ctx, images)
 }
 
 // VerifyContainersUniqueness ensures that nodes defined in the topology do not have names of the existing containers
@@ -476,25 +791,27 @@ func (c *CLab) VerifyContainersUniqueness(ctx context.Context) error {
 	return nil
 }
 
-// verifyHostIfaces ensures that host interfaces referenced in the topology
-// do not exist already in the root namespace
+// verifyHostIfaces ensures that existing host interfaces referenced in the topology
+// can be attached to the nodes
 // and ensure that nodes that are configured with host networking mode do not have any interfaces defined
 func (c *CLab) verifyHostIfaces() error {
 	for _, l := range c.Links {
-		if l.A.Node.ShortName == "host" {
-			if nl, _ := netlink.LinkByName(l.A.EndpointName); nl != nil {
-				return fmt.Errorf("host interface %s referenced in topology already exists", l.A.EndpointName)
+		if host, _ := hostEndpoints(l); host != nil && isSubIfLink(l) {
+			if _, err := netlink.LinkByName(host.EndpointName); err != nil {
+				return fmt.Errorf("%s: host interface %s of the %s link doesn't exist", l, host.EndpointName, l.Type)
+			}
+		}
+		// existing host interfaces are attached to the nodes
+		if nic, peer := hostNICEndpoints(l); nic != nil {
+			nl, _ := netlink.LinkByName(nic.EndpointName)
+			if err := verifyHostNIC(nl, peer); err != nil {
+				return err
 			}
 		}
 		if l.A.Node.NetworkMode == "host" {
 			return fmt.Errorf("node '%s' is defined with host network mode, it can't have any links. Remove '%s' node links from the topology definition",
 				l.A.Node.ShortName, l.A.Node.ShortName)
 		}
-		if l.B.Node.ShortName == "host" {
-			if nl, _ := netlink.LinkByName(l.B.EndpointName); nl != nil {
-				return fmt.Errorf("host interface %s referenced in topology already exists", l.B.EndpointName)
-			}
-		}
 		if l.B.Node.NetworkMode == "host" {
 			return fmt.Errorf("node '%s' is defined with host network mode, it can't have any links. Remove '%s' node links from the topology definition",
 				l.B.Node.ShortName, l.B.Node.ShortName)
@@ -507,7 +824,13 @@ func (c *CLab) verifyHostIfaces() error {
 // are uniquely defined in the topology file
 func (c *CLab) verifyRootNetnsInterfaceUniqueness() error {
 	rootNsIfaces := map[string]struct{}{}
+	// host NICs of the macvlan and ipvlan links, shared by their sub-interfaces
+	subIfParents := map[string]struct{}{}
 	for _, l := range c.Links {
+		if host, _ := hostEndpoints(l); host != nil && isSubIfLink(l) {
+			subIfParents[host.EndpointName] = struct{}{}
+			continue
+		}
 		endpoints := [2]*types.Endpoint{l.A, l.B}
 		for _, e := range endpoints {
 			if e.Node.Kind == nodes.NodeKindBridge || e.Node.Kind == nodes.NodeKindOVS || e.Node.Kind == nodes.NodeKindHOST {
@@ -519,6 +842,11 @@ func (c *CLab) verifyRootNetnsInterfaceUniqueness() error {
 			}
 		}
 	}
+	for name := range subIfParents {
+		if _, ok := rootNsIfaces[name]; ok {
+			return fmt.Errorf("host interface %s is the parent of the macvlan or ipvlan links and can't be used by other links", name)
+		}
+	}
 	return nil
 }
 
@@ -589,6 +917,47 @@ func resolvePath(p string) (string, error) {
 	return p, nil
 }
 
+// verifyLinkMTU checks that the MTU of the links is within the range accepted by the veth interfaces
+func (c *CLab) verifyLinkMTU() error {
+	for _, l := range c.Links {
+		if l.MTU < minLinkMTU || l.MTU > maxLinkMTU {
+			return fmt.Errorf("%s: invalid MTU %d, expected a value between %d and %d", l, l.MTU, minLinkMTU, maxLinkMTU)
+		}
+	}
+	return nil
+}
+
+// verifyLabDirVisible checks that the lab directory is visible to the container runtime
+// when containerlab runs inside a container with the host root set,
+// the node files created in the lab directory are mounted by the runtime using the host paths
+func (c *CLab) verifyLabDirVisible() error {
+	if utils.IsHostVisible(c.Dir.Lab) {
+		return nil
+	}
+	return fmt.Errorf("lab directory %s is outside of the host root %s, set the lab directory path under the host root with --lab-dir-path", c.Dir.Lab, utils.HostRoot())
+}
+
+// LabDirFromLabels returns the local path of the lab directory of a container using its labels,
+// containers created before the lab dir label was introduced have it derived from the node lab dir
+func LabDirFromLabels(labels map[string]string) string {
+	if d := labels[LabDirLabel]; d != "" {
+		return utils.LocalPath(d)
+	}
+	if d := labels[NodeLabDirLabel]; d != "" {
+		return utils.LocalPath(filepath.Dir(d))
+	}
+	return ""
+}
+
+// resolveTopoPath resolves a path set in the topology file,
+// relative paths are resolved against the topology file dir
+func (c *CLab) resolveTopoPath(p string) (string, error) {
+	if p != "" && p[0] != '~' && !filepath.IsAbs(p) {
+		p = filepath.Join(filepath.Dir(c.TopoFile.path), p)
+	}
+	return resolvePath(p)
+}
+
 // resolveBindPaths resolves the host paths in a bind string, such as /hostpath:/remotepath(:options) string
 // it allows host path to have `~` and returns absolute path for a relative path
 // if the host path doesn't exist, the error will be returned
@@ -618,6 +987,9 @@ func resolveBindPaths(binds []string, nodedir string) error {
 				return fmt.Errorf("failed to verify bind path: %v", err)
 			}
 		}
+		if !utils.IsHostVisible(hp) {
+			log.Warnf("bind path %s is outside of the host root %s and is not visible to the container runtime", hp, utils.HostRoot())
+		}
 		elems[0] = hp
 		binds[i] = strings.Join(elems, ":")
 	}
@@ -0,0 +1,101 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	jT "github.com/kellerza/template"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// configTemplateData is the data the config templates of a node are rendered with
+type configTemplateData struct {
+	Node *types.NodeConfig
+	Vars map[string]interface{}
+}
+
+// configPayloads returns the config snippets of the node followed by its rendered config templates
+func configPayloads(cfg *types.NodeConfig) ([]string, error) {
+	payloads := append([]string{}, cfg.Config.GetSnippets()...)
+	for _, p := range cfg.Config.GetTemplates() {
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config template: %v", err)
+		}
+		t, err := template.New(filepath.Base(p)).Funcs(jT.Funcs).Parse(string(b))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse config template %s: %v", p, err)
+		}
+		var buf strings.Builder
+		if err := t.Execute(&buf, configTemplateData{Node: cfg, Vars: cfg.Config.GetVars()}); err != nil {
+			return nil, fmt.Errorf("failed to render config template %s: %v", p, err)
+		}
+		payloads = append(payloads, buf.String())
+	}
+	return payloads, nil
+}
+
+// PushConfigs pushes the config snippets and templates set in the topology to the nodes with the names,
+// all the nodes with the config to push are configured when names is empty.
+// The nodes are configured concurrently, every node is given its boot time to accept the config
+func (c *CLab) PushConfigs(ctx context.Context, names []string) error {
+	if len(names) == 0 {
+		for name, n := range c.Nodes {
+			if n.Config().Config.HasPush() {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	var mu sync.Mutex
+	var errs []string
+	wg := new(sync.WaitGroup)
+	for _, name := range names {
+		n, ok := c.Nodes[name]
+		if !ok {
+			return fmt.Errorf("node %q is not found in the topology", name)
+		}
+		cfg := n.Config()
+		payloads, err := configPayloads(cfg)
+		if err != nil {
+			return fmt.Errorf("node %q: %v", name, err)
+		}
+		if len(payloads) == 0 {
+			log.Warnf("node %s has no config to push", name)
+			continue
+		}
+		wg.Add(1)
+		go func(cfg *types.NodeConfig) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, waitTimeout(cfg))
+			defer cancel()
+			log.Infof("Pushing config to node %s", cfg.ShortName)
+			if err := nodes.PushConfig(ctx, cfg, payloads, readyPoll); err != nil {
+				mu.Lock()
+				errs = append(errs, err.Error())
+				mu.Unlock()
+				return
+			}
+			log.Infof("Config pushed to node %s", cfg.ShortName)
+		}(cfg)
+	}
+	wg.Wait()
+	if len(errs) != 0 {
+		sort.Strings(errs)
+		return fmt.Errorf("failed to push config: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
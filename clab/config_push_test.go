@@ -0,0 +1,53 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestConfigPayloads(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo29.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string][]string{
+		"srl1": {
+			`{"interface": [{"name": "ethernet-1/1", "admin-state": "enable"}]}`,
+			`{"system": {"name": {"host-name": "srl1"}}, "network-instance": [{"name": "default", "protocols": {"bgp": {"autonomous-system": 65001}}}]}` + "\n",
+		},
+		"srl2": {
+			`{"system": {"name": {"host-name": "srl2"}}, "network-instance": [{"name": "default", "protocols": {"bgp": {"autonomous-system": 65002}}}]}` + "\n",
+		},
+		"sros1":  {},
+		"client": {},
+	}
+	for name, want := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := configPayloads(c.Nodes[name].Config())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff(want, got); d != "" {
+				t.Errorf("payloads mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestConfigPushUnsupportedKind(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo29.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	def := c.Config.Topology.Nodes["client"]
+	def.Config = &types.ConfigDispatcher{Snippets: []string{"{}"}}
+	if _, err := c.createNodeCfg("client", def, 0); err == nil {
+		t.Errorf("expected an error for the config push to the kind without a push transport")
+	}
+}
@@ -5,10 +5,12 @@
 package clab
 
 import (
+	"net"
 	"os"
 	"path"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"strings"
 	"testing"
 
@@ -379,6 +381,7 @@ func TestLabelsInit(t *testing.T) {
 				NodeTypeLabel:     "ixr6",
 				NodeGroupLabel:    "",
 				NodeLabDirLabel:   "./clab-topo1/node1",
+				LabDirLabel:       "./clab-topo1",
 				TopoFileLabel:     "./test_data/topo1.yml",
 			},
 		},
@@ -392,6 +395,7 @@ func TestLabelsInit(t *testing.T) {
 				NodeTypeLabel:     "ixrd2",
 				NodeGroupLabel:    "",
 				NodeLabDirLabel:   "./clab-topo1/node2",
+				LabDirLabel:       "./clab-topo1",
 				TopoFileLabel:     "./test_data/topo1.yml",
 				"node-label":      "value",
 			},
@@ -406,6 +410,7 @@ func TestLabelsInit(t *testing.T) {
 				NodeTypeLabel:     "ixrd2",
 				NodeGroupLabel:    "",
 				NodeLabDirLabel:   "./clab-topo2/node1",
+				LabDirLabel:       "./clab-topo2",
 				TopoFileLabel:     "./test_data/topo2.yml",
 				"kind-label":      "value",
 			},
@@ -420,6 +425,7 @@ func TestLabelsInit(t *testing.T) {
 				NodeTypeLabel:     "ixrd2",
 				NodeGroupLabel:    "",
 				NodeLabDirLabel:   "./clab-topo3/node2",
+				LabDirLabel:       "./clab-topo3",
 				TopoFileLabel:     "./test_data/topo3.yml",
 				"default-label":   "value",
 			},
@@ -437,6 +443,7 @@ func TestLabelsInit(t *testing.T) {
 			}
 
 			tc.want[NodeLabDirLabel], _ = resolvePath(tc.want[NodeLabDirLabel])
+			tc.want[LabDirLabel], _ = resolvePath(tc.want[LabDirLabel])
 			tc.want[TopoFileLabel], _ = resolvePath(tc.want[TopoFileLabel])
 
 			labels := c.Nodes[tc.node].Config().Labels
@@ -448,6 +455,65 @@ func TestLabelsInit(t *testing.T) {
 	}
 }
 
+func TestLabDirInit(t *testing.T) {
+	tests := map[string]struct {
+		got        string
+		labDirPath string
+		want       map[string]string
+	}{
+		"config_path_from_topo": {
+			got: "test_data/topo10.yml",
+			want: map[string]string{
+				"lab":   "test_data/labs/clab-topo10",
+				"node1": "test_data/labs/clab-topo10/node1",
+				"node2": "node2-dir",
+			},
+		},
+		"lab_dir_path_flag": {
+			got:        "test_data/topo10.yml",
+			labDirPath: "/tmp/labs",
+			want: map[string]string{
+				"lab":   "/tmp/labs/clab-topo10",
+				"node1": "/tmp/labs/clab-topo10/node1",
+				"node2": "node2-dir",
+			},
+		},
+		"default_to_pwd": {
+			got: "test_data/topo1.yml",
+			want: map[string]string{
+				"lab":   "./clab-topo1",
+				"node1": "./clab-topo1/node1",
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := []ClabOption{
+				WithTopoFile(tc.got),
+				WithLabDirPath(tc.labDirPath),
+			}
+			c, err := NewContainerLab(opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for k, v := range tc.want {
+				tc.want[k], _ = resolvePath(v)
+			}
+			got := map[string]string{"lab": c.Dir.Lab}
+			for k := range tc.want {
+				if n, ok := c.Nodes[k]; ok {
+					got[k] = n.Config().LabDir
+				}
+			}
+			if !cmp.Equal(got, tc.want) {
+				t.Errorf("got: %+v, want: %+v", got, tc.want)
+			}
+		})
+	}
+}
+
 func TestVerifyRootNetnsInterfaceUniqueness(t *testing.T) {
 
 	opts := []ClabOption{
@@ -465,3 +531,306 @@ func TestVerifyRootNetnsInterfaceUniqueness(t *testing.T) {
 	t.Logf("error: %v", err)
 
 }
+
+func TestUnknownNodeRuntime(t *testing.T) {
+	opts := []ClabOption{
+		WithTopoFile("test_data/topo17.yml"),
+	}
+	_, err := NewContainerLab(opts...)
+	if err == nil {
+		t.Fatalf("expected unknown runtime error")
+	}
+	t.Logf("error: %v", err)
+}
+
+func TestInterfaceProfileInit(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo19.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	arpIgnore, forwardingOff, forwardingOn := 1, false, true
+
+	tests := map[string]*types.InterfaceProfile{
+		"node1": {MTU: 1500, ARPIgnore: &arpIgnore},
+		"node2": {Forwarding: &forwardingOff},
+		"crpd1": {Forwarding: &forwardingOn},
+	}
+	for name, want := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := c.Nodes[name].Config().InterfaceProfile
+			if !cmp.Equal(got, want) {
+				t.Errorf("got: %+v, want: %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestDNSAliasesInit(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo1.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"node1", "node1.topo1"}
+	got := c.Nodes["node1"].Config().DNSAliases
+	if !cmp.Equal(got, want) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+}
+
+func TestLinkNetemInit(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo23.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.verifyLinkNetem(); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]*types.Netem{
+		"r1:eth1": {Delay: 50, Jitter: 5, Loss: 0.5, Rate: 10000},
+		"r1:eth2": nil,
+	}
+	for _, l := range c.Links {
+		if got := l.Netem; !cmp.Equal(got, want[endpointName(l.A)]) {
+			t.Errorf("%s: got netem %+v, want %+v", l, got, want[endpointName(l.A)])
+		}
+	}
+}
+
+func TestLinkMTUInit(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo24.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int{
+		"r1:eth1": 9212,
+		"r1:eth2": 1500,
+	}
+	for _, l := range c.Links {
+		if l.MTU != want[endpointName(l.A)] {
+			t.Errorf("%s: got MTU %d, want %d", l, l.MTU, want[endpointName(l.A)])
+		}
+	}
+	if err := c.verifyLinkMTU(); err != nil {
+		t.Fatal(err)
+	}
+	// links with no MTU get the default MTU when the lab-wide MTU is not set
+	c.Config.Settings = nil
+	l := c.NewLink(&types.LinkConfig{Endpoints: []string{"r1:eth3", "r2:eth3"}})
+	if l.MTU != defaultVethLinkMTU {
+		t.Errorf("got MTU %d, want %d", l.MTU, defaultVethLinkMTU)
+	}
+	c.Links[0].MTU = 65536
+	if err := c.verifyLinkMTU(); err == nil {
+		t.Error("expected error for the out of range MTU")
+	}
+}
+
+func TestLabHostsStitching(t *testing.T) {
+	os.Setenv(labHostEnv, "server1")
+	defer os.Unsetenv(labHostEnv)
+	c, err := NewContainerLab(WithTopoFile("test_data/topo25.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var nodeNames []string
+	for n := range c.Nodes {
+		nodeNames = append(nodeNames, n)
+	}
+	sort.Strings(nodeNames)
+	if d := cmp.Diff([]string{"r1", "r2"}, nodeNames); d != "" {
+		t.Errorf("nodes mismatch (-want +got):\n%s", d)
+	}
+	if deps := c.Nodes["r2"].Config().DependsOn; len(deps) != 0 {
+		t.Errorf("expected the dependency on the remote node to be dropped, got %v", deps)
+	}
+
+	type stitch struct {
+		A, B   string
+		Stitch *types.VxlanStitch
+	}
+	want := []stitch{
+		{A: "r1:eth1", B: "r2:eth1"},
+		{
+			A: "r1:eth2", B: "host:vs-5002",
+			Stitch: &types.VxlanStitch{Host: "server2", Remote: net.ParseIP("192.0.2.2"), VNI: 5002, Port: defaultVxlanPort, Name: "vx-5002", HostIf: "vs-5002"},
+		},
+		{
+			A: "host:vs-5003", B: "r2:eth2",
+			Stitch: &types.VxlanStitch{Host: "server2", Remote: net.ParseIP("192.0.2.2"), VNI: 5003, Port: defaultVxlanPort, Name: "vx-5003", HostIf: "vs-5003"},
+		},
+	}
+	var got []stitch
+	for i := 0; i < len(c.Links); i++ {
+		l := c.Links[i]
+		got = append(got, stitch{A: endpointName(l.A), B: endpointName(l.B), Stitch: l.Stitch})
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("links mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestLabHostsErrors(t *testing.T) {
+	tests := map[string]struct {
+		hosts   map[string]*types.LabHost
+		vxlan   *types.VxlanSettings
+		nodes   map[string]*types.NodeDefinition
+		labHost string
+	}{
+		"invalid_address": {
+			hosts:   map[string]*types.LabHost{"server1": {Address: "server1.lab"}},
+			nodes:   map[string]*types.NodeDefinition{"r1": {LabHost: "server1"}},
+			labHost: "server1",
+		},
+		"node_without_lab_host": {
+			hosts:   map[string]*types.LabHost{"server1": {Address: "192.0.2.1"}},
+			nodes:   map[string]*types.NodeDefinition{"r1": {}},
+			labHost: "server1",
+		},
+		"undefined_lab_host": {
+			hosts:   map[string]*types.LabHost{"server1": {Address: "192.0.2.1"}},
+			nodes:   map[string]*types.NodeDefinition{"r1": {LabHost: "server2"}},
+			labHost: "server1",
+		},
+		"undefined_local_lab_host": {
+			hosts:   map[string]*types.LabHost{"server1": {Address: "192.0.2.1"}},
+			nodes:   map[string]*types.NodeDefinition{"r1": {LabHost: "server1"}},
+			labHost: "server3",
+		},
+		"invalid_port": {
+			hosts:   map[string]*types.LabHost{"server1": {Address: "192.0.2.1"}},
+			vxlan:   &types.VxlanSettings{Port: 70000},
+			nodes:   map[string]*types.NodeDefinition{"r1": {LabHost: "server1"}},
+			labHost: "server1",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			os.Setenv(labHostEnv, tc.labHost)
+			defer os.Unsetenv(labHostEnv)
+			c := &CLab{Config: &Config{
+				Settings: &types.Settings{LabHosts: tc.hosts, Vxlan: tc.vxlan},
+				Topology: &types.Topology{Nodes: tc.nodes},
+			}}
+			if err := c.initLabHosts(); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestLinkTypes(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo26.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.verifyLinks(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.verifyLinkTypes(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.verifyRootNetnsInterfaceUniqueness(); err != nil {
+		t.Fatal(err)
+	}
+	var subIfs []string
+	for _, l := range c.Links {
+		if isSubIfLink(l) {
+			subIfs = append(subIfs, endpointName(l.A)+" "+l.Type+"/"+l.Mode)
+		}
+	}
+	sort.Strings(subIfs)
+	want := []string{"r1:eth1 macvlan/", "r1:eth2 ipvlan/l3", "r2:eth1 macvlan/vepa"}
+	if d := cmp.Diff(want, subIfs); d != "" {
+		t.Errorf("sub-interface links mismatch (-want +got):\n%s", d)
+	}
+
+	tests := map[string][]types.LinkConfig{
+		"unknown-type": {
+			{Endpoints: []string{"r1:eth1", "host:eno2"}, Type: "vxlan"},
+		},
+		"unknown-mode": {
+			{Endpoints: []string{"r1:eth1", "host:eno2"}, Type: "ipvlan", Mode: "vepa"},
+		},
+		"veth-mode": {
+			{Endpoints: []string{"r1:eth1", "r2:eth1"}, Mode: "bridge"},
+		},
+		"no-host-endpoint": {
+			{Endpoints: []string{"r1:eth1", "r2:eth1"}, Type: "macvlan"},
+		},
+		"bridge-peer": {
+			{Endpoints: []string{"mgmt-net:r1-eth1", "host:eno2"}, Type: "macvlan"},
+		},
+		"vlan": {
+			{Endpoints: []string{"r1:eth1", "host:eno2"}, Type: "macvlan", VLAN: &types.LinkVLAN{Access: 10}},
+		},
+		"parent-used-by-veth": {
+			{Endpoints: []string{"r1:eth1", "host:eno2"}, Type: "macvlan"},
+			{Endpoints: []string{"r2:eth1", "host:eno2"}},
+		},
+	}
+	for name, links := range tests {
+		t.Run(name, func(t *testing.T) {
+			c.Links = map[int]*types.Link{}
+			for i := range links {
+				c.Links[i] = c.NewLink(&links[i])
+			}
+			err := c.verifyLinkTypes()
+			if err == nil {
+				err = c.verifyRootNetnsInterfaceUniqueness()
+			}
+			if err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestVerifyMgmtAddresses(t *testing.T) {
+	tests := map[string]struct {
+		node       string
+		ipv4, ipv6 string
+		netMode    string
+		wantErr    bool
+	}{
+		"valid": {
+			node: "n3", ipv4: "172.100.100.13", ipv6: "2001:172:100:100::13",
+		},
+		"duplicate": {
+			node: "n3", ipv4: "172.100.100.11", wantErr: true,
+		},
+		"duplicate-ipv6": {
+			node: "n3", ipv6: "2001:172:100:100:0::11", wantErr: true,
+		},
+		"outside-subnet": {
+			node: "n3", ipv4: "172.20.20.13", wantErr: true,
+		},
+		"ipv6-as-ipv4": {
+			node: "n3", ipv4: "2001:172:100:100::13", wantErr: true,
+		},
+		"invalid": {
+			node: "n3", ipv4: "172.100.100.300", wantErr: true,
+		},
+		"network-mode": {
+			node: "n3", ipv4: "172.100.100.13", netMode: "host", wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewContainerLab(WithTopoFile("test_data/topo28.yml"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			// mgmt-ipv4 and mgmt-ipv6 are the aliases of mgmt_ipv4 and mgmt_ipv6
+			n1 := c.Nodes["n1"].Config()
+			if n1.MgmtIPv4Address != "172.100.100.11" || n1.MgmtIPv6Address != "2001:172:100:100::11" {
+				t.Fatalf("unexpected n1 management addresses %s %s", n1.MgmtIPv4Address, n1.MgmtIPv6Address)
+			}
+			cfg := c.Nodes[tc.node].Config()
+			cfg.MgmtIPv4Address, cfg.MgmtIPv6Address, cfg.NetworkMode = tc.ipv4, tc.ipv6, tc.netMode
+			err = c.verifyMgmtAddresses()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("wanted error %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ConsoleLogFile is the name of the file in the node directory the console output is logged to
+const ConsoleLogFile = "console.log"
+
+// ConsoleEscape is the key closing the serial console session, Ctrl-]
+const ConsoleEscape = 0x1d
+
+// qemuCmdlinesCmd prints the command lines of the processes running in the container
+var qemuCmdlinesCmd = []string{"sh", "-c", "cat /proc/[0-9]*/cmdline"}
+
+// ConsoleLogPath returns the path of the file the console output of the node is logged to
+func (c *CLab) ConsoleLogPath(node string) string {
+	n, ok := c.Nodes[node]
+	if !ok {
+		return ""
+	}
+	return filepath.Join(n.Config().LabDir, ConsoleLogFile)
+}
+
+// SerialConsolePort returns the telnet port of the serial console of the VM based node.
+// The port is discovered from the -serial option of the qemu processes started by the vrnetlab launcher,
+// the lowest port is returned for the nodes running several VMs. The default port of the kind is returned
+// if the port is not discovered, and 0 if the node has no serial console
+func (c *CLab) SerialConsolePort(ctx context.Context, node string) (int, error) {
+	n, ok := c.Nodes[node]
+	if !ok {
+		return 0, fmt.Errorf("node %q is not defined in the topology", node)
+	}
+	ka, ok := nodeAccess(n.Config())
+	if !ok || ka.telnet == 0 {
+		return 0, nil
+	}
+	stdout, _, err := n.GetRuntime().Exec(ctx, n.Config().LongName, qemuCmdlinesCmd)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read qemu command line of node %s: %v", node, err)
+	}
+	if ports := serialPorts(stdout); len(ports) > 0 {
+		return ports[0], nil
+	}
+	return ka.telnet, nil
+}
+
+// serialPorts returns the sorted telnet and tcp ports of the -serial options
+// found in the NUL separated process command lines
+func serialPorts(cmdlines []byte) []int {
+	var ports []int
+	args := bytes.Split(cmdlines, []byte{0})
+	for i := 0; i+1 < len(args); i++ {
+		if string(args[i]) != "-serial" {
+			continue
+		}
+		// e.g. telnet:0.0.0.0:5000,server,nowait
+		dev := strings.SplitN(string(args[i+1]), ",", 2)[0]
+		if !strings.HasPrefix(dev, "telnet:") && !strings.HasPrefix(dev, "tcp:") {
+			continue
+		}
+		p, err := strconv.Atoi(dev[strings.LastIndex(dev, ":")+1:])
+		if err != nil || p <= 0 {
+			continue
+		}
+		ports = append(ports, p)
+	}
+	sort.Ints(ports)
+	return ports
+}
+
+// telnet commands and options used by the serial console client
+const (
+	telnetSE   = 240
+	telnetSB   = 250
+	telnetWILL = 251
+	telnetWONT = 252
+	telnetDO   = 253
+	telnetDONT = 254
+	telnetIAC  = 255
+
+	telnetEcho = 1
+	telnetSGA  = 3
+)
+
+// telnetReader reads the data of the telnet stream r with the telnet commands stripped.
+// The option negotiations are answered to w, the server echo and the character mode are accepted
+type telnetReader struct {
+	r *bufio.Reader
+	w io.Writer
+}
+
+func newTelnetReader(r io.Reader, w io.Writer) *telnetReader {
+	return &telnetReader{r: bufio.NewReader(r), w: w}
+}
+
+func (t *telnetReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		// the data read so far is returned instead of blocking on the stream
+		if n > 0 && t.r.Buffered() == 0 {
+			break
+		}
+		b, err := t.r.ReadByte()
+		if err != nil {
+			return n, err
+		}
+		if b != telnetIAC {
+			p[n] = b
+			n++
+			continue
+		}
+		cmd, err := t.r.ReadByte()
+		if err != nil {
+			return n, err
+		}
+		switch cmd {
+		case telnetIAC:
+			p[n] = telnetIAC
+			n++
+		case telnetWILL, telnetWONT, telnetDO, telnetDONT:
+			opt, err := t.r.ReadByte()
+			if err != nil {
+				return n, err
+			}
+			if err := t.negotiate(cmd, opt); err != nil {
+				return n, err
+			}
+		case telnetSB:
+			// subnegotiations are skipped up to IAC SE
+			for prev := byte(0); ; {
+				c, err := t.r.ReadByte()
+				if err != nil {
+					return n, err
+				}
+				if prev == telnetIAC && c == telnetSE {
+					break
+				}
+				prev = c
+			}
+		}
+	}
+	return n, nil
+}
+
+// negotiate answers the option negotiation of the server
+func (t *telnetReader) negotiate(cmd, opt byte) error {
+	var reply byte
+	switch cmd {
+	case telnetWILL:
+		reply = telnetDONT
+		if opt == telnetEcho || opt == telnetSGA {
+			reply = telnetDO
+		}
+	case telnetDO:
+		reply = telnetWONT
+		if opt == telnetSGA {
+			reply = telnetWILL
+		}
+	default:
+		return nil
+	}
+	_, err := t.w.Write([]byte{telnetIAC, reply, opt})
+	return err
+}
+
+// telnetEscape escapes the IAC bytes of the data sent to the telnet stream
+func telnetEscape(p []byte) []byte {
+	return bytes.ReplaceAll(p, []byte{telnetIAC}, []byte{telnetIAC, telnetIAC})
+}
+
+// ConsoleSession connects in and out to the serial console telnet stream conn until the stream is closed
+// or the ConsoleEscape key is read from in. The console output is copied to log as well
+func ConsoleSession(conn io.ReadWriter, in io.Reader, out, log io.Writer) error {
+	errCh := make(chan error, 2)
+	go func() {
+		_, err := io.Copy(io.MultiWriter(out, log), newTelnetReader(conn, conn))
+		errCh <- err
+	}()
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, err := in.Read(buf)
+			if i := bytes.IndexByte(buf[:n], ConsoleEscape); i >= 0 {
+				_, err = conn.Write(telnetEscape(buf[:i]))
+				errCh <- err
+				return
+			}
+			if n > 0 {
+				if _, werr := conn.Write(telnetEscape(buf[:n])); werr != nil {
+					errCh <- werr
+					return
+				}
+			}
+			if err != nil {
+				errCh <- err
+				return
+			}
+		}
+	}()
+	err := <-errCh
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
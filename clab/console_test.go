@@ -0,0 +1,96 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSerialPorts(t *testing.T) {
+	tests := map[string]struct {
+		cmdlines string
+		want     []int
+	}{
+		"single_vm": {
+			cmdlines: "python3\x00/launch.py\x00--trace\x00" +
+				"qemu-system-x86_64\x00-m\x004096\x00-serial\x00telnet:0.0.0.0:5000,server,nowait\x00-drive\x00if=ide,file=/sros.qcow2\x00",
+			want: []int{5000},
+		},
+		"several_vms": {
+			cmdlines: "qemu-system-x86_64\x00-serial\x00telnet:0.0.0.0:5001,server,nowait\x00" +
+				"qemu-system-x86_64\x00-serial\x00tcp:127.0.0.1:5000,server,nowait\x00",
+			want: []int{5000, 5001},
+		},
+		"no_serial_port": {
+			cmdlines: "qemu-system-x86_64\x00-serial\x00stdio\x00-nographic\x00",
+			want:     nil,
+		},
+		"no_qemu": {
+			cmdlines: "sh\x00-c\x00sleep infinity\x00",
+			want:     nil,
+		},
+		"trailing_serial_option": {
+			cmdlines: "qemu-system-x86_64\x00-serial",
+			want:     nil,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := serialPorts([]byte(tc.cmdlines))
+			if d := cmp.Diff(tc.want, got); d != "" {
+				t.Fatalf("serial ports mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestTelnetReader(t *testing.T) {
+	tests := map[string]struct {
+		stream    []byte
+		wantData  string
+		wantReply []byte
+	}{
+		"plain_data": {
+			stream:   []byte("login: "),
+			wantData: "login: ",
+		},
+		"accepted_options": {
+			stream:    []byte{telnetIAC, telnetWILL, telnetEcho, telnetIAC, telnetWILL, telnetSGA, 'o', 'k'},
+			wantData:  "ok",
+			wantReply: []byte{telnetIAC, telnetDO, telnetEcho, telnetIAC, telnetDO, telnetSGA},
+		},
+		"refused_options": {
+			stream:    []byte{telnetIAC, telnetWILL, 24, telnetIAC, telnetDO, 31, telnetIAC, telnetDO, telnetSGA, telnetIAC, telnetWONT, telnetEcho},
+			wantReply: []byte{telnetIAC, telnetDONT, 24, telnetIAC, telnetWONT, 31, telnetIAC, telnetWILL, telnetSGA},
+		},
+		"subnegotiation_skipped": {
+			stream:   []byte{'a', telnetIAC, telnetSB, 24, 1, telnetIAC, telnetSE, 'b'},
+			wantData: "ab",
+		},
+		"escaped_iac": {
+			stream:   []byte{'a', telnetIAC, telnetIAC, 'b'},
+			wantData: "a\xffb",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var reply bytes.Buffer
+			got, err := ioutil.ReadAll(newTelnetReader(bytes.NewReader(tc.stream), &reply))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff(tc.wantData, string(got)); d != "" {
+				t.Errorf("data mismatch (-want +got):\n%s", d)
+			}
+			if d := cmp.Diff(tc.wantReply, reply.Bytes()); d != "" {
+				t.Errorf("negotiation reply mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
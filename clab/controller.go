@@ -0,0 +1,205 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// controller API types
+const (
+	// ControllerHTTP posts the node details to the controller API address
+	ControllerHTTP = "http"
+	// ControllerGNMIC adds the nodes as targets to the gnmic API
+	ControllerGNMIC = "gnmic"
+)
+
+// ControllerNode holds the details of a lab node registered with the controller
+type ControllerNode struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	// role of the node is the node group
+	Role     string `json:"role,omitempty"`
+	MgmtIPv4 string `json:"mgmt_ipv4,omitempty"`
+	MgmtIPv6 string `json:"mgmt_ipv6,omitempty"`
+	// gNMI server address of the node
+	GNMI     string            `json:"gnmi,omitempty"`
+	Username string            `json:"username,omitempty"`
+	Password string            `json:"password,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+}
+
+// gnmicTarget is the target definition of the gnmic API
+type gnmicTarget struct {
+	Name       string   `json:"name"`
+	Address    string   `json:"address"`
+	Username   string   `json:"username,omitempty"`
+	Password   string   `json:"password,omitempty"`
+	SkipVerify bool     `json:"skip-verify"`
+	Tags       []string `json:"tags,omitempty"`
+}
+
+// verifyController verifies the controller settings of the lab
+func (c *CLab) verifyController() error {
+	ctrl := c.Config.Controller
+	if ctrl == nil {
+		return nil
+	}
+	switch ctrl.Type {
+	case "", ControllerHTTP, ControllerGNMIC:
+	default:
+		return fmt.Errorf("unknown controller type %q, supported types are %s and %s", ctrl.Type, ControllerHTTP, ControllerGNMIC)
+	}
+	if ctrl.Address == "" {
+		return fmt.Errorf("controller address is not set")
+	}
+	return nil
+}
+
+// controllerNodes returns the lab nodes registered with the controller sorted by name.
+// Nodes are filtered by the controller groups, when set
+func (c *CLab) controllerNodes() []*ControllerNode {
+	groups := map[string]struct{}{}
+	for _, g := range c.Config.Controller.Groups {
+		groups[g] = struct{}{}
+	}
+
+	res := make([]*ControllerNode, 0, len(c.Nodes))
+	for _, n := range c.Nodes {
+		cfg := n.Config()
+		if _, ok := groups[cfg.Group]; len(groups) > 0 && !ok {
+			continue
+		}
+		switch cfg.Kind {
+		case nodes.NodeKindBridge, nodes.NodeKindOVS, nodes.NodeKindHOST:
+			continue
+		}
+		cn := &ControllerNode{
+			Name:     cfg.ShortName,
+			Kind:     cfg.Kind,
+			Role:     cfg.Group,
+			MgmtIPv4: cfg.MgmtIPv4Address,
+			MgmtIPv6: cfg.MgmtIPv6Address,
+			Labels:   cfg.Labels,
+		}
+		if creds := nodes.SaveCredentials(cfg); creds.Username != "" {
+			cn.Username, cn.Password = creds.Username, creds.Password
+		}
+		if ka, ok := kindsAccess[cfg.Kind]; ok && ka.gnmi != 0 {
+			host := cfg.MgmtIPv4Address
+			if host == "" {
+				host = cfg.MgmtIPv6Address
+			}
+			if host != "" {
+				cn.GNMI = net.JoinHostPort(host, strconv.Itoa(ka.gnmi))
+			}
+		}
+		res = append(res, cn)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
+// OnboardNodes registers the deployed lab nodes with the controller set in the topology.
+// With the http type the node details are posted to the controller address,
+// with the gnmic type the nodes with a gNMI server are added as the targets of the gnmic API.
+// All nodes are attempted, the errors are returned combined
+func (c *CLab) OnboardNodes(ctx context.Context) error {
+	ctrl := c.Config.Controller
+	if ctrl == nil {
+		return nil
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	if ctrl.SkipVerify {
+		client.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}} // skipcq: GSC-G402
+	}
+
+	var errs []string
+	for _, n := range c.controllerNodes() {
+		var url string
+		var body interface{} = n
+		switch ctrl.Type {
+		case ControllerGNMIC:
+			if n.GNMI == "" {
+				log.Debugf("node %s has no gNMI server, skipping controller onboarding", n.Name)
+				continue
+			}
+			url = strings.TrimSuffix(ctrl.Address, "/") + "/api/v1/config/targets"
+			body = gnmicTargetOf(n)
+		default:
+			url = ctrl.Address
+		}
+		log.Infof("Onboarding node %s to controller %s", n.Name, ctrl.Address)
+		if err := postController(ctx, client, ctrl, url, body); err != nil {
+			errs = append(errs, fmt.Sprintf("node %s: %v", n.Name, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to onboard nodes to controller: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// gnmicTargetOf returns the gnmic target of the node, the node role is set as the target tag
+func gnmicTargetOf(n *ControllerNode) *gnmicTarget {
+	t := &gnmicTarget{
+		Name:     n.Name,
+		Address:  n.GNMI,
+		Username: n.Username,
+		Password: n.Password,
+		// lab nodes use self-signed or lab CA certificates
+		SkipVerify: true,
+	}
+	if n.Role != "" {
+		t.Tags = []string{n.Role}
+	}
+	return t
+}
+
+// postController posts the JSON encoded body to the controller API url
+func postController(ctx context.Context, client *http.Client, ctrl *types.ControllerConfig, url string, body interface{}) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	switch {
+	case ctrl.Token != "":
+		req.Header.Set("Authorization", "Bearer "+ctrl.Token)
+	case ctrl.Username != "":
+		req.SetBasicAuth(ctrl.Username, ctrl.Password)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("controller responded with %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// controllerServer records the request bodies posted to the test controller API
+type controllerServer struct {
+	m      sync.Mutex
+	paths  []string
+	bodies []map[string]interface{}
+	auth   []string
+}
+
+func (s *controllerServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body := map[string]interface{}{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.paths = append(s.paths, r.URL.Path)
+	s.bodies = append(s.bodies, body)
+	s.auth = append(s.auth, r.Header.Get("Authorization"))
+}
+
+func TestOnboardNodes(t *testing.T) {
+	tests := map[string]struct {
+		ctrl      *types.ControllerConfig
+		wantPaths []string
+		wantNames []string
+		wantAuth  string
+		// fields of the first posted body
+		wantFirst map[string]interface{}
+	}{
+		"gnmic": {
+			ctrl:      &types.ControllerConfig{Type: ControllerGNMIC},
+			wantPaths: []string{"/api/v1/config/targets", "/api/v1/config/targets", "/api/v1/config/targets"},
+			wantNames: []string{"leaf1", "leaf2", "spine1"},
+			wantFirst: map[string]interface{}{
+				"name":        "leaf1",
+				"address":     "172.20.20.21:6030",
+				"skip-verify": true,
+				"tags":        []interface{}{"leaf"},
+			},
+		},
+		"http-groups": {
+			ctrl:      &types.ControllerConfig{Type: ControllerHTTP, Groups: []string{"spine"}, Token: "secret"},
+			wantPaths: []string{"/nodes"},
+			wantNames: []string{"spine1"},
+			wantAuth:  "Bearer secret",
+			wantFirst: map[string]interface{}{
+				"name":      "spine1",
+				"kind":      "srl",
+				"role":      "spine",
+				"mgmt_ipv4": "172.20.20.11",
+				"gnmi":      "172.20.20.11:57400",
+				"username":  "admin",
+				"password":  "admin",
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv := &controllerServer{}
+			ts := httptest.NewServer(srv)
+			defer ts.Close()
+
+			c, err := NewContainerLab(WithTopoFile("test_data/topo16.yml"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.Config.Controller = tc.ctrl
+			c.Config.Controller.Address = ts.URL
+			if tc.ctrl.Type == ControllerHTTP {
+				c.Config.Controller.Address = ts.URL + "/nodes"
+			}
+			if err := c.OnboardNodes(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			if d := cmp.Diff(tc.wantPaths, srv.paths); d != "" {
+				t.Errorf("paths mismatch (-want +got):\n%s", d)
+			}
+			var names []string
+			for _, b := range srv.bodies {
+				names = append(names, b["name"].(string))
+			}
+			if d := cmp.Diff(tc.wantNames, names); d != "" {
+				t.Errorf("onboarded nodes mismatch (-want +got):\n%s", d)
+			}
+			if srv.auth[0] != tc.wantAuth {
+				t.Errorf("wanted authorization %q, got %q", tc.wantAuth, srv.auth[0])
+			}
+			for k, v := range tc.wantFirst {
+				if d := cmp.Diff(v, srv.bodies[0][k]); d != "" {
+					t.Errorf("field %s mismatch (-want +got):\n%s", k, d)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyController(t *testing.T) {
+	tests := map[string]struct {
+		ctrl    *types.ControllerConfig
+		wantErr bool
+	}{
+		"no-controller": {},
+		"default-type": {
+			ctrl: &types.ControllerConfig{Address: "http://controller"},
+		},
+		"unknown-type": {
+			ctrl:    &types.ControllerConfig{Type: "netbox", Address: "http://controller"},
+			wantErr: true,
+		},
+		"no-address": {
+			ctrl:    &types.ControllerConfig{Type: ControllerGNMIC},
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &CLab{Config: &Config{Controller: tc.ctrl}}
+			if err := c.verifyController(); (err != nil) != tc.wantErr {
+				t.Fatalf("wanted error %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
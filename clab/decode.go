@@ -0,0 +1,86 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// TopoDecoder converts the topology file contents written in a format other than YAML to YAML.
+// The path is the topology file path, data is the file contents with the topology template rendered
+type TopoDecoder func(path string, data []byte) ([]byte, error)
+
+// TopoDecoders maps the topology file extensions to the decoders of the file formats,
+// the files with other extensions are read as YAML
+var TopoDecoders = map[string]TopoDecoder{
+	".json": decodeJSONTopology,
+	".cue":  decodeCUETopology,
+}
+
+// decodeTopology converts the topology file contents to YAML with the decoder registered for the file extension
+func decodeTopology(path string, data []byte) ([]byte, error) {
+	dec, ok := TopoDecoders[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return data, nil
+	}
+	return dec(path, data)
+}
+
+// decodeJSONTopology checks the JSON topology syntax and returns the document in the YAML compatible form.
+// JSON is a subset of YAML, only the tabs and the escaped slashes YAML parser doesn't accept are replaced,
+// so that the line and column numbers of the JSON document are kept
+func decodeJSONTopology(_ string, data []byte) ([]byte, error) {
+	if err := json.Unmarshal(data, new(interface{})); err != nil {
+		if serr, ok := err.(*json.SyntaxError); ok {
+			line := bytes.Count(data[:serr.Offset], []byte("\n")) + 1
+			return nil, fmt.Errorf("failed to decode JSON topology: line %d: %v", line, err)
+		}
+		return nil, fmt.Errorf("failed to decode JSON topology: %v", err)
+	}
+	res := make([]byte, 0, len(data))
+	inString := false
+	for i := 0; i < len(data); i++ {
+		b := data[i]
+		switch {
+		case inString && b == '\\' && i+1 < len(data):
+			i++
+			if data[i] == '/' {
+				res = append(res, '/')
+				continue
+			}
+			res = append(res, b, data[i])
+			continue
+		case b == '"':
+			inString = !inString
+		case !inString && b == '\t':
+			b = ' '
+		}
+		res = append(res, b)
+	}
+	return res, nil
+}
+
+// decodeCUETopology exports the CUE topology to YAML with the cue tool.
+// The imports of the CUE file are resolved relative to the topology file directory
+func decodeCUETopology(path string, data []byte) ([]byte, error) {
+	if !commandExists("cue") {
+		return nil, fmt.Errorf("cue is required to read the CUE topology files, see https://cuelang.org/docs/install/")
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("cue", "export", "--out", "yaml", "cue:", "-")
+	cmd.Dir = filepath.Dir(path)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to export CUE topology: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+)
+
+func TestDecodeJSONTopology(t *testing.T) {
+	tests := map[string]struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		"tabs": {
+			in:   "{\n\t\"name\": \"lab\"\n}",
+			want: "{\n \"name\": \"lab\"\n}",
+		},
+		"escaped-slash": {
+			in:   `{"cmd": "echo http:\/\/host \\/ \"\t\""}`,
+			want: `{"cmd": "echo http://host \\/ \"\t\""}`,
+		},
+		"syntax-error": {
+			in:      "{\n\"name\": \"lab\",\n}",
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := decodeJSONTopology("topo.json", []byte(tc.in))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("wanted error %v, got %v", tc.wantErr, err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("wanted %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestDecodeTopologyYAML(t *testing.T) {
+	in := []byte("name: lab\n")
+	got, err := decodeTopology("topo.clab.yml", in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(in) {
+		t.Errorf("wanted %q, got %q", in, got)
+	}
+}
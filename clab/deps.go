@@ -0,0 +1,302 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/go-units"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes"
+)
+
+const (
+	// HostDepModule is a kernel module loaded on the container host
+	HostDepModule = "module"
+	// HostDepSysctl is a kernel parameter raised on the container host
+	HostDepSysctl = "sysctl"
+
+	// inotify watches and system wide open files a single node is expected to consume
+	inotifyWatchesPerNode = 8192
+	fileMaxPerNode        = 8192
+)
+
+// KindKernelModules holds the kernel modules the nodes of a kind need on top of the bridge and veth modules
+var KindKernelModules = map[string][]string{
+	nodes.NodeKindOVS:   {"openvswitch"},
+	nodes.NodeKindSonic: {"vrf"},
+	nodes.NodeKindCVX:   {"vrf"},
+	nodes.NodeKindCRPD:  {"mpls_router", "mpls_iptunnel"},
+}
+
+// hostDepsDir is the directory the host changes made for the labs are recorded in, so that they can be undone
+var hostDepsDir = "/var/lib/containerlab/install-deps"
+
+// sysctlDir is the directory the kernel parameters are read and set through
+var sysctlDir = "/proc/sys"
+
+// moduleLoaded and modprobe check and change the state of the kernel modules
+var (
+	moduleLoaded = kernelModuleLoaded
+	modprobe     = func(args ...string) error {
+		if out, err := exec.Command("modprobe", args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("modprobe %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+)
+
+// HostChange is a change of the container host state needed by the lab nodes
+type HostChange struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	// state before and after the change, the module state is either "loaded" or "not loaded"
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+func (h *HostChange) String() string {
+	return fmt.Sprintf("%s %s: %s -> %s", h.Type, h.Name, h.From, h.To)
+}
+
+// requiredModules returns the kernel modules needed by the lab nodes
+func (c *CLab) requiredModules() []string {
+	set := map[string]struct{}{"bridge": {}, "veth": {}}
+	for _, n := range c.Nodes {
+		for _, m := range KindKernelModules[n.Config().Kind] {
+			set[m] = struct{}{}
+		}
+	}
+	mods := make([]string, 0, len(set))
+	for m := range set {
+		mods = append(mods, m)
+	}
+	sort.Strings(mods)
+	return mods
+}
+
+// requiredSysctls returns the minimal values of the kernel parameters needed by the lab nodes
+func (c *CLab) requiredSysctls() (map[string]int64, error) {
+	n := int64(len(c.Nodes))
+	req := map[string]int64{
+		"fs.inotify.max_user_instances": max64(inotifyInstancesPerNode*n, 128),
+		"fs.inotify.max_user_watches":   max64(inotifyWatchesPerNode*n, 65536),
+		"fs.file-max":                   max64(fileMaxPerNode*n, 65536),
+	}
+	pages, err := c.requiredHugepages()
+	if err != nil {
+		return nil, err
+	}
+	if pages > 0 {
+		req["vm.nr_hugepages"] = pages
+	}
+	return req, nil
+}
+
+// requiredHugepages returns the number of hugepages backing the memory of the nodes mounting /dev/hugepages
+func (c *CLab) requiredHugepages() (int64, error) {
+	var mem int64
+	for name, n := range c.Nodes {
+		cfg := n.Config()
+		for _, b := range cfg.Binds {
+			if !strings.HasPrefix(b, "/dev/hugepages") {
+				continue
+			}
+			m, err := nodeMemory(cfg)
+			if err != nil {
+				return 0, fmt.Errorf("failed to parse memory of node %q: %v", name, err)
+			}
+			if m == 0 {
+				log.Warnf("node %s mounts hugepages, but doesn't declare its memory, its hugepages are not allocated", name)
+			}
+			mem += m
+			break
+		}
+	}
+	if mem == 0 {
+		return 0, nil
+	}
+	meminfo, err := readMeminfo()
+	if err != nil {
+		return 0, err
+	}
+	size := meminfo["Hugepagesize"] * units.KiB
+	if size == 0 {
+		return 0, fmt.Errorf("hugepages are not supported by the kernel")
+	}
+	return (mem + size - 1) / size, nil
+}
+
+// HostDeps returns the changes of the container host needed by the lab nodes,
+// the modules which are not loaded and the kernel parameters lower than needed.
+// The parameters set higher than needed are never lowered
+func (c *CLab) HostDeps() ([]*HostChange, error) {
+	var changes []*HostChange
+	for _, m := range c.requiredModules() {
+		if !moduleLoaded(m) {
+			changes = append(changes, &HostChange{Type: HostDepModule, Name: m, From: "not loaded", To: "loaded"})
+		}
+	}
+	req, err := c.requiredSysctls()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(req))
+	for name := range req {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		v, err := readSysctl(name)
+		if err != nil {
+			return nil, err
+		}
+		if cur, err := strconv.ParseInt(v, 10, 64); err == nil && cur >= req[name] {
+			continue
+		}
+		changes = append(changes, &HostChange{Type: HostDepSysctl, Name: name, From: v, To: strconv.FormatInt(req[name], 10)})
+	}
+	return changes, nil
+}
+
+// InstallDeps applies the host changes and records them, so that they can be undone with UndoDeps.
+// The changes applied before a failure are recorded and returned along with the error
+func (c *CLab) InstallDeps(changes []*HostChange) ([]*HostChange, error) {
+	var applied []*HostChange
+	var err error
+	for _, h := range changes {
+		if err = applyHostChange(h, false); err != nil {
+			break
+		}
+		log.Infof("Changed %s", h)
+		applied = append(applied, h)
+	}
+	if rerr := c.recordHostChanges(applied); rerr != nil && err == nil {
+		err = rerr
+	}
+	return applied, err
+}
+
+// UndoDeps reverts the host changes recorded for the lab and returns the reverted changes
+func (c *CLab) UndoDeps() ([]*HostChange, error) {
+	recorded, err := c.recordedHostChanges()
+	if err != nil {
+		return nil, err
+	}
+	var reverted []*HostChange
+	// the changes are reverted in the reverse order
+	for i := len(recorded) - 1; i >= 0; i-- {
+		h := recorded[i]
+		if err := applyHostChange(h, true); err != nil {
+			log.Warnf("failed to revert %s: %v", h, err)
+			continue
+		}
+		log.Infof("Reverted %s", h)
+		reverted = append(reverted, h)
+	}
+	if err := os.Remove(c.hostDepsFile()); err != nil && !os.IsNotExist(err) {
+		return reverted, err
+	}
+	return reverted, nil
+}
+
+// applyHostChange applies the change or reverts it if undo is set
+func applyHostChange(h *HostChange, undo bool) error {
+	switch h.Type {
+	case HostDepModule:
+		if undo {
+			return modprobe("-r", h.Name)
+		}
+		return modprobe(h.Name)
+	case HostDepSysctl:
+		v := h.To
+		if undo {
+			v = h.From
+		}
+		return writeSysctl(h.Name, v)
+	}
+	return fmt.Errorf("unknown host change type %q", h.Type)
+}
+
+// hostDepsFile returns the path of the file recording the host changes made for the lab
+func (c *CLab) hostDepsFile() string {
+	return filepath.Join(hostDepsDir, c.Config.Name+".json")
+}
+
+// recordedHostChanges returns the host changes recorded for the lab
+func (c *CLab) recordedHostChanges() ([]*HostChange, error) {
+	b, err := ioutil.ReadFile(c.hostDepsFile())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var changes []*HostChange
+	if err := json.Unmarshal(b, &changes); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", c.hostDepsFile(), err)
+	}
+	return changes, nil
+}
+
+// recordHostChanges adds the changes to the ones recorded for the lab.
+// A change of an already recorded module or parameter keeps its original state recorded
+func (c *CLab) recordHostChanges(changes []*HostChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	recorded, err := c.recordedHostChanges()
+	if err != nil {
+		return err
+	}
+	idx := map[string]*HostChange{}
+	for _, h := range recorded {
+		idx[h.Type+"/"+h.Name] = h
+	}
+	for _, h := range changes {
+		if r, ok := idx[h.Type+"/"+h.Name]; ok {
+			r.To = h.To
+			continue
+		}
+		recorded = append(recorded, h)
+	}
+	b, err := json.MarshalIndent(recorded, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(hostDepsDir, 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.hostDepsFile(), b, 0644)
+}
+
+// readSysctl returns the value of the kernel parameter, e.g. fs.file-max
+func readSysctl(name string) (string, error) {
+	b, err := ioutil.ReadFile(filepath.Join(sysctlDir, strings.ReplaceAll(name, ".", "/")))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// writeSysctl sets the value of the kernel parameter
+func writeSysctl(name, value string) error {
+	return ioutil.WriteFile(filepath.Join(sysctlDir, strings.ReplaceAll(name, ".", "/")), []byte(value), 0644)
+}
+
+func max64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
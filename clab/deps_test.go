@@ -0,0 +1,82 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestInstallDeps(t *testing.T) {
+	origSysctlDir, origDepsDir, origLoaded, origModprobe := sysctlDir, hostDepsDir, moduleLoaded, modprobe
+	defer func() {
+		sysctlDir, hostDepsDir, moduleLoaded, modprobe = origSysctlDir, origDepsDir, origLoaded, origModprobe
+	}()
+	// fake kernel state: veth is not loaded, the inotify instances limit is too low
+	sysctlDir = t.TempDir()
+	hostDepsDir = t.TempDir()
+	if err := os.MkdirAll(filepath.Join(sysctlDir, "fs", "inotify"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, v := range map[string]string{
+		"fs.inotify.max_user_instances": "64",
+		"fs.inotify.max_user_watches":   "524288",
+		"fs.file-max":                   "9223372036854775807",
+	} {
+		if err := writeSysctl(name, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	loaded := map[string]bool{"bridge": true}
+	moduleLoaded = func(m string) bool { return loaded[m] }
+	modprobe = func(args ...string) error {
+		if args[0] == "-r" {
+			delete(loaded, args[1])
+		} else {
+			loaded[args[0]] = true
+		}
+		return nil
+	}
+
+	c, err := NewContainerLab(WithTopoFile("test_data/topo1.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err := c.HostDeps()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*HostChange{
+		{Type: HostDepModule, Name: "veth", From: "not loaded", To: "loaded"},
+		{Type: HostDepSysctl, Name: "fs.inotify.max_user_instances", From: "64", To: "128"},
+	}
+	if !cmp.Equal(changes, want) {
+		t.Fatalf("diff (-want +got):\n%s", cmp.Diff(want, changes))
+	}
+
+	if _, err := c.InstallDeps(changes); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := readSysctl("fs.inotify.max_user_instances"); v != "128" || !loaded["veth"] {
+		t.Fatalf("changes are not applied, max_user_instances %q, modules %v", v, loaded)
+	}
+	if changes, _ := c.HostDeps(); len(changes) != 0 {
+		t.Errorf("wanted no changes after install, got %v", changes)
+	}
+
+	reverted, err := c.UndoDeps()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reverted) != 2 {
+		t.Errorf("wanted 2 reverted changes, got %v", reverted)
+	}
+	if v, _ := readSysctl("fs.inotify.max_user_instances"); v != "64" || loaded["veth"] {
+		t.Errorf("changes are not reverted, max_user_instances %q, modules %v", v, loaded)
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// setDNS validates the DNS settings of the node. The vrnetlab nodes get the settings
+// in the DNS_SERVERS, DNS_SEARCH and DNS_OPTIONS env vars read by launch.py to configure the VM,
+// unless these env vars are set with env
+func setDNS(cfg *types.NodeConfig) error {
+	if cfg.DNS == nil {
+		return nil
+	}
+	if err := cfg.DNS.Validate(); err != nil {
+		return fmt.Errorf("node %q: %v", cfg.ShortName, err)
+	}
+	if _, ok := noMgmtKinds[cfg.Kind]; ok {
+		return fmt.Errorf("node %q: dns is not supported by kind %s", cfg.ShortName, cfg.Kind)
+	}
+	if cfg.NetworkMode != "" {
+		return fmt.Errorf("node %q: dns can't be set with network-mode %s", cfg.ShortName, cfg.NetworkMode)
+	}
+	if !nodes.IsVrKind(cfg.Kind) {
+		return nil
+	}
+	if cfg.Env == nil {
+		cfg.Env = map[string]string{}
+	}
+	for env, v := range map[string][]string{
+		"DNS_SERVERS": cfg.DNS.Servers,
+		"DNS_SEARCH":  cfg.DNS.Search,
+		"DNS_OPTIONS": cfg.DNS.Options,
+	} {
+		if _, ok := cfg.Env[env]; !ok && len(v) != 0 {
+			cfg.Env[env] = strings.Join(v, ",")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestSetDNS(t *testing.T) {
+	dns := &types.DNSConfig{
+		Servers: []string{"10.0.0.53", "2001:db8::53"},
+		Search:  []string{"corp.example.com", "example.com"},
+	}
+	tests := map[string]struct {
+		cfg     *types.NodeConfig
+		wantEnv map[string]string
+		wantErr bool
+	}{
+		"linux": {
+			cfg: &types.NodeConfig{ShortName: "n1", Kind: "linux", DNS: dns},
+		},
+		"vr": {
+			cfg: &types.NodeConfig{ShortName: "n1", Kind: "vr-sros", DNS: dns},
+			wantEnv: map[string]string{
+				"DNS_SERVERS": "10.0.0.53,2001:db8::53",
+				"DNS_SEARCH":  "corp.example.com,example.com",
+			},
+		},
+		"vr_env_set": {
+			cfg: &types.NodeConfig{ShortName: "n1", Kind: "vr-sros", DNS: dns, Env: map[string]string{"DNS_SERVERS": "1.1.1.1"}},
+			wantEnv: map[string]string{
+				"DNS_SERVERS": "1.1.1.1",
+				"DNS_SEARCH":  "corp.example.com,example.com",
+			},
+		},
+		"invalid_server": {
+			cfg:     &types.NodeConfig{ShortName: "n1", Kind: "linux", DNS: &types.DNSConfig{Servers: []string{"dns.example.com"}}},
+			wantErr: true,
+		},
+		"network_mode": {
+			cfg:     &types.NodeConfig{ShortName: "n1", Kind: "linux", NetworkMode: "host", DNS: dns},
+			wantErr: true,
+		},
+		"bridge": {
+			cfg:     &types.NodeConfig{ShortName: "n1", Kind: "bridge", DNS: dns},
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := setDNS(tc.cfg)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if d := cmp.Diff(tc.wantEnv, tc.cfg.Env); !tc.wantErr && d != "" {
+				t.Errorf("env mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestDNSResolvConf(t *testing.T) {
+	dns := &types.DNSConfig{
+		Servers: []string{"10.0.0.53", "10.0.1.53"},
+		Search:  []string{"corp.example.com"},
+		Options: []string{"ndots:2", "timeout:1"},
+	}
+	want := "nameserver 10.0.0.53\nnameserver 10.0.1.53\nsearch corp.example.com\noptions ndots:2 timeout:1\n"
+	if got := string(dns.ResolvConf()); got != want {
+		t.Errorf("expected resolv.conf %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// env templates use their own delimiters, so that they are not rendered with the topology file template
+const (
+	envTemplateLeft  = "[["
+	envTemplateRight = "]]"
+)
+
+// envTemplateNode is the node of the env templates data
+type envTemplateNode struct {
+	Name     string
+	LongName string
+	Kind     string
+	Fqdn     string
+	MgmtIPv4 string
+	MgmtIPv6 string
+}
+
+// envTemplateData is the data the env values of the nodes are rendered with
+type envTemplateData struct {
+	// lab name
+	Lab string
+	// node the env is rendered for
+	Node *envTemplateNode
+	// lab nodes by their names
+	Nodes map[string]*envTemplateNode
+}
+
+// nodeEnv returns the env of the node. The env files of the defaults, the kind and the node are read in that order,
+// the later files override the variables of the earlier ones, and the env set in the topology overrides the files
+func (c *CLab) nodeEnv(name string) (map[string]string, error) {
+	var env map[string]string
+	for _, f := range c.Config.Topology.GetNodeEnvFiles(name) {
+		p, err := c.resolveTopoPath(f)
+		if err != nil {
+			return nil, err
+		}
+		fh, err := os.Open(p)
+		if err != nil {
+			return nil, fmt.Errorf("node %q: failed to read env file: %v", name, err)
+		}
+		fenv, err := utils.ParseEnvFile(fh)
+		fh.Close()
+		if err != nil {
+			return nil, fmt.Errorf("node %q: failed to parse env file %s: %v", name, f, err)
+		}
+		env = utils.MergeStringMaps(env, fenv)
+	}
+	return utils.MergeStringMaps(env, c.Config.Topology.GetNodeEnv(name)), nil
+}
+
+// RenderEnvTemplates renders the env values of the nodes which are the templates referencing the lab nodes,
+// e.g. [[ .Nodes.srv1.MgmtIPv4 ]]. The management addresses are the ones known before the nodes are created,
+// i.e. set in the topology or allocated by IPAM
+func (c *CLab) RenderEnvTemplates() error {
+	data := &envTemplateData{Lab: c.Config.Name, Nodes: map[string]*envTemplateNode{}}
+	for name, n := range c.Nodes {
+		cfg := n.Config()
+		data.Nodes[name] = &envTemplateNode{
+			Name:     cfg.ShortName,
+			LongName: cfg.LongName,
+			Kind:     cfg.Kind,
+			Fqdn:     cfg.Fqdn,
+			MgmtIPv4: cfg.MgmtIPv4Address,
+			MgmtIPv6: cfg.MgmtIPv6Address,
+		}
+	}
+	for _, name := range c.nodeNames() {
+		cfg := c.Nodes[name].Config()
+		keys := make([]string, 0, len(cfg.Env))
+		for k, v := range cfg.Env {
+			if strings.Contains(v, envTemplateLeft) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		data.Node = data.Nodes[name]
+		for _, k := range keys {
+			v, err := renderEnvValue(cfg.Env[k], data)
+			if err != nil {
+				return fmt.Errorf("node %q: env %s: %v", name, k, err)
+			}
+			cfg.Env[k] = v
+		}
+	}
+	return nil
+}
+
+// renderEnvValue renders the env value template with the data
+func renderEnvValue(v string, data *envTemplateData) (string, error) {
+	t, err := template.New("env").
+		Delims(envTemplateLeft, envTemplateRight).
+		Option("missingkey=error").
+		Parse(v)
+	if err != nil {
+		return "", err
+	}
+	var b bytes.Buffer
+	if err := t.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
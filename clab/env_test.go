@@ -0,0 +1,44 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+)
+
+func TestRenderEnvValue(t *testing.T) {
+	data := &envTemplateData{
+		Lab: "lab1",
+		Nodes: map[string]*envTemplateNode{
+			"srv1":    {Name: "srv1", Kind: "linux", MgmtIPv4: "172.20.20.10"},
+			"client1": {Name: "client1", Kind: "linux"},
+		},
+	}
+	data.Node = data.Nodes["client1"]
+	tests := map[string]struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		"plain":            {in: "value", want: "value"},
+		"peer-address":     {in: "http://[[ .Nodes.srv1.MgmtIPv4 ]]:8080", want: "http://172.20.20.10:8080"},
+		"node-name":        {in: "[[ .Lab ]]-[[ .Node.Name ]]", want: "lab1-client1"},
+		"go-template":      {in: "{{ .Lab }}", want: "{{ .Lab }}"},
+		"unknown-node":     {in: "[[ .Nodes.srv2.MgmtIPv4 ]]", wantErr: true},
+		"unknown-field":    {in: "[[ .Node.Image ]]", wantErr: true},
+		"invalid-template": {in: "[[ .Lab", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := renderEnvValue(tc.in, data)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("renderEnvValue() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("renderEnvValue() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
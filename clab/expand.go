@@ -0,0 +1,144 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/srl-labs/containerlab/types"
+	"gopkg.in/yaml.v2"
+)
+
+// rangeRe matches the range expression in node names and link endpoints, e.g. `[1-16]` or `[1,3,5-7]`
+var rangeRe = regexp.MustCompile(`\[([0-9][0-9,\-]*)\]`)
+
+// expandRange returns the values of a range expression such as `1-4`, `01-16` or `1,3,5-7`.
+// Values are zero padded when the range start has leading zeros
+func expandRange(r string) ([]string, error) {
+	var res []string
+	for _, part := range strings.Split(r, ",") {
+		bounds := strings.SplitN(part, "-", 2)
+		start, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid range %q", r)
+		}
+		end := start
+		if len(bounds) == 2 {
+			if end, err = strconv.Atoi(bounds[1]); err != nil {
+				return nil, fmt.Errorf("invalid range %q", r)
+			}
+		}
+		if end < start {
+			return nil, fmt.Errorf("invalid range %q: %d is less than %d", r, end, start)
+		}
+		format := "%d"
+		if len(bounds[0]) > 1 && bounds[0][0] == '0' {
+			format = fmt.Sprintf("%%0%dd", len(bounds[0]))
+		}
+		for i := start; i <= end; i++ {
+			res = append(res, fmt.Sprintf(format, i))
+		}
+	}
+	return res, nil
+}
+
+// expandPattern expands the range expressions in s, e.g. `leaf[1-2]:e1-[1-2]`
+// is expanded to leaf1:e1-1, leaf1:e1-2, leaf2:e1-1, leaf2:e1-2.
+// The last range varies the fastest
+func expandPattern(s string) ([]string, error) {
+	loc := rangeRe.FindStringSubmatchIndex(s)
+	if loc == nil {
+		return []string{s}, nil
+	}
+	values, err := expandRange(s[loc[2]:loc[3]])
+	if err != nil {
+		return nil, err
+	}
+	rest, err := expandPattern(s[loc[1]:])
+	if err != nil {
+		return nil, err
+	}
+	res := make([]string, 0, len(values)*len(rest))
+	for _, v := range values {
+		for _, r := range rest {
+			res = append(res, s[:loc[0]]+v+r)
+		}
+	}
+	return res, nil
+}
+
+// expandTopology expands the node ranges and link patterns of the topology.
+// A node named `leaf[1-4]` is replaced with the nodes leaf1..leaf4 sharing its definition.
+// Link endpoints with range expressions are expanded in order and paired with each other,
+// so both endpoints of a link pattern must expand to the same number of endpoints
+func (c *CLab) expandTopology() error {
+	topo := c.Config.Topology
+	for name, def := range topo.Nodes {
+		if !rangeRe.MatchString(name) {
+			continue
+		}
+		if def.GetMgmtIPv4() != "" || def.GetMgmtIPv6() != "" {
+			return fmt.Errorf("node range %q can't set management addresses", name)
+		}
+		names, err := expandPattern(name)
+		if err != nil {
+			return fmt.Errorf("failed to expand node range %q: %v", name, err)
+		}
+		delete(topo.Nodes, name)
+		for _, n := range names {
+			if _, ok := topo.Nodes[n]; ok {
+				return fmt.Errorf("node %q of the node range %q is already defined", n, name)
+			}
+			if topo.Nodes[n], err = copyNodeDefinition(def); err != nil {
+				return err
+			}
+		}
+	}
+
+	links := make([]*types.LinkConfig, 0, len(topo.Links))
+	for _, l := range topo.Links {
+		if len(l.Endpoints) != 2 || !rangeRe.MatchString(strings.Join(l.Endpoints, " ")) {
+			links = append(links, l)
+			continue
+		}
+		a, err := expandPattern(l.Endpoints[0])
+		if err != nil {
+			return fmt.Errorf("failed to expand link endpoint %q: %v", l.Endpoints[0], err)
+		}
+		b, err := expandPattern(l.Endpoints[1])
+		if err != nil {
+			return fmt.Errorf("failed to expand link endpoint %q: %v", l.Endpoints[1], err)
+		}
+		if len(a) != len(b) {
+			return fmt.Errorf("link endpoints %q expand to %d and %d endpoints, the number must match",
+				l.Endpoints, len(a), len(b))
+		}
+		for i := range a {
+			links = append(links, &types.LinkConfig{
+				Endpoints: []string{a[i], b[i]},
+				Labels:    l.Labels,
+				Vars:      l.Vars,
+			})
+		}
+	}
+	topo.Links = links
+	return nil
+}
+
+// copyNodeDefinition returns a deep copy of the node definition
+func copyNodeDefinition(def *types.NodeDefinition) (*types.NodeDefinition, error) {
+	res := new(types.NodeDefinition)
+	if def == nil {
+		return res, nil
+	}
+	b, err := yaml.Marshal(def)
+	if err != nil {
+		return nil, err
+	}
+	return res, yaml.Unmarshal(b, res)
+}
@@ -0,0 +1,105 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExpandPattern(t *testing.T) {
+	tests := map[string]struct {
+		got     string
+		want    []string
+		wantErr bool
+	}{
+		"no_range": {
+			got:  "leaf1",
+			want: []string{"leaf1"},
+		},
+		"range": {
+			got:  "leaf[1-3]",
+			want: []string{"leaf1", "leaf2", "leaf3"},
+		},
+		"zero_padded": {
+			got:  "leaf[08-10]",
+			want: []string{"leaf08", "leaf09", "leaf10"},
+		},
+		"list": {
+			got:  "leaf[1,3,5-6]",
+			want: []string{"leaf1", "leaf3", "leaf5", "leaf6"},
+		},
+		"multiple_ranges": {
+			got:  "leaf[1-2]:e1-[1-2]",
+			want: []string{"leaf1:e1-1", "leaf1:e1-2", "leaf2:e1-1", "leaf2:e1-2"},
+		},
+		"reversed_range": {
+			got:     "leaf[3-1]",
+			wantErr: true,
+		},
+		"invalid_range": {
+			got:     "leaf[1-]",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := expandPattern(tc.got)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !cmp.Equal(got, tc.want) {
+				t.Errorf("failed at '%s', diff (-want +got):\n%s", name, cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
+
+func TestExpandTopology(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo15.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for n := range c.Nodes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	wantNodes := []string{"leaf01", "leaf02", "leaf03", "leaf04", "spine1", "spine2"}
+	if !cmp.Equal(names, wantNodes) {
+		t.Errorf("nodes diff (-want +got):\n%s", cmp.Diff(wantNodes, names))
+	}
+	leaf := c.Nodes["leaf03"].Config()
+	if leaf.Group != "leaf" || leaf.Env["ROLE"] != "leaf" {
+		t.Errorf("node range definition is not applied to leaf03: group=%q, env=%v", leaf.Group, leaf.Env)
+	}
+
+	var links [][]string
+	for i := 0; i < len(c.Links); i++ {
+		l := c.Links[i]
+		links = append(links, []string{
+			l.A.Node.ShortName + ":" + l.A.EndpointName,
+			l.B.Node.ShortName + ":" + l.B.EndpointName,
+		})
+	}
+	wantLinks := [][]string{
+		{"spine1:eth1", "leaf01:eth1"},
+		{"spine1:eth2", "leaf02:eth1"},
+		{"spine1:eth3", "leaf03:eth1"},
+		{"spine1:eth4", "leaf04:eth1"},
+		{"spine2:eth1", "leaf01:eth2"},
+		{"spine2:eth2", "leaf02:eth2"},
+		{"spine2:eth3", "leaf03:eth2"},
+		{"spine2:eth4", "leaf04:eth2"},
+		{"leaf01:eth3", "leaf02:eth3"},
+	}
+	if !cmp.Equal(links, wantLinks) {
+		t.Errorf("links diff (-want +got):\n%s", cmp.Diff(wantLinks, links))
+	}
+}
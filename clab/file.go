@@ -21,6 +21,7 @@ type TopoFile struct {
 	path     string // topo file path
 	fullName string // file name with extension
 	name     string // file name without extension
+	rendered []byte // topology with the template and the env vars expanded
 }
 
 // GetTopology parses the topology file into c.Conf structure
@@ -32,6 +33,16 @@ func (c *CLab) GetTopology(topo string) error {
 	}
 	log.Debug(fmt.Sprintf("Topology file contents:\n%s\n", yamlFile))
 
+	vars, err := readTopoVars(c.topoVarsFile)
+	if err != nil {
+		return err
+	}
+	if yamlFile, err = renderTopology(topo, yamlFile, vars); err != nil {
+		return err
+	}
+	if yamlFile, err = decodeTopology(topo, yamlFile); err != nil {
+		return err
+	}
 	yamlFile = []byte(os.ExpandEnv(string(yamlFile)))
 	err = yaml.UnmarshalStrict(yamlFile, c.Config)
 	if err != nil {
@@ -50,6 +61,12 @@ func (c *CLab) GetTopology(topo string) error {
 		path:     topoAbsPath,
 		fullName: file,
 		name:     strings.TrimSuffix(file, path.Ext(file)),
+		rendered: yamlFile,
 	}
 	return nil
 }
+
+// RenderedTopology returns the topology with the template and the env vars expanded
+func (c *CLab) RenderedTopology() []byte {
+	return c.TopoFile.rendered
+}
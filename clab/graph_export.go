@@ -0,0 +1,303 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// graph export formats
+	GraphFormatDrawio  = "drawio"
+	GraphFormatMermaid = "mermaid"
+	GraphFormatDot     = "dot"
+
+	// node states of the graph exports
+	graphStateRunning     = "running"
+	graphStateStopped     = "stopped"
+	graphStateNotDeployed = "not-deployed"
+)
+
+// graphStateColors are the fill colors of the nodes by their state
+var graphStateColors = map[string]string{
+	graphStateRunning:     "#2da44e",
+	graphStateStopped:     "#cf222e",
+	graphStateNotDeployed: "#afb8c1",
+}
+
+// graphIconColors are the fill colors of the node icons when the node states are unknown
+var graphIconColors = map[string]string{
+	"router":   "#1f6feb",
+	"switch":   "#2da44e",
+	"firewall": "#cf222e",
+	"bridge":   "#8250df",
+	"host":     "#57606a",
+}
+
+// drawioShapes maps the node icons to the draw.io shapes
+var drawioShapes = map[string]string{
+	"router":   "shape=mxgraph.cisco.routers.router",
+	"switch":   "shape=mxgraph.cisco.switches.layer_3_switch",
+	"firewall": "shape=mxgraph.cisco.security.firewall",
+	"bridge":   "shape=mxgraph.cisco.switches.workgroup_switch",
+	"host":     "shape=mxgraph.cisco.computers_and_peripherals.pc",
+}
+
+// GraphExportFormat returns the graph export format matching the file extension
+func GraphExportFormat(p string) (string, error) {
+	switch strings.ToLower(filepath.Ext(p)) {
+	case ".drawio":
+		return GraphFormatDrawio, nil
+	case ".mmd", ".mermaid":
+		return GraphFormatMermaid, nil
+	case ".dot", ".gv":
+		return GraphFormatDot, nil
+	}
+	if f, err := GraphImageFormat(p); err == nil {
+		return f, nil
+	}
+	return "", fmt.Errorf("unsupported graph export extension %q, use .svg, .png, .drawio, .mmd or .dot", filepath.Ext(p))
+}
+
+// graphNodeState returns the state of the node name for the graph exports.
+// The states map holds the container states of the deployed nodes, nil when the states are unknown
+func graphNodeState(states map[string]string, name string) string {
+	if states == nil {
+		return ""
+	}
+	switch s, ok := states[name]; {
+	case !ok:
+		return graphStateNotDeployed
+	case s == "running":
+		return graphStateRunning
+	}
+	return graphStateStopped
+}
+
+// graphNodeColor returns the fill color of the node, by its state when the states are known
+func graphNodeColor(n *graphNode, states map[string]string) string {
+	if s := graphNodeState(states, n.name); s != "" {
+		return graphStateColors[s]
+	}
+	return graphIconColors[n.icon]
+}
+
+// ExportGraph writes the lab topology graph to the file in the format matching its extension.
+// The drawio, mermaid and dot exports color the nodes by their container state in states,
+// the nodes are colored by their kind when states is nil
+func (c *CLab) ExportGraph(p string, states map[string]string) error {
+	format, err := GraphExportFormat(p)
+	if err != nil {
+		return err
+	}
+	var b bytes.Buffer
+	switch format {
+	case GraphFormatSVG, GraphFormatPNG:
+		return c.ExportGraphImage(p)
+	case GraphFormatDrawio:
+		err = c.WriteGraphDrawio(&b, states)
+	case GraphFormatMermaid:
+		err = c.WriteGraphMermaid(&b, states)
+	case GraphFormatDot:
+		err = c.WriteGraphDot(&b, states)
+	}
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(p, b.Bytes(), 0644)
+}
+
+// graphNodesByName returns the nodes of the graph layout rows by their names along with the sorted names
+func graphNodesByName(rows [][]*graphNode) (map[string]*graphNode, []string) {
+	pos := map[string]*graphNode{}
+	var names []string
+	for _, row := range rows {
+		for _, n := range row {
+			pos[n.name] = n
+			names = append(names, n.name)
+		}
+	}
+	sort.Strings(names)
+	return pos, names
+}
+
+// WriteGraphDrawio renders the lab topology as a draw.io diagram with the kind specific shapes
+// placed with the group-based layout of the graph image
+func (c *CLab) WriteGraphDrawio(w io.Writer, states map[string]string) error {
+	rows, _, width, height := c.graphLayout()
+	pos, _ := graphNodesByName(rows)
+	esc := html.EscapeString
+
+	var b bytes.Buffer
+	b.WriteString(`<mxfile host="containerlab">` + "\n")
+	fmt.Fprintf(&b, `  <diagram id="%s" name="%s">`+"\n", esc(c.Config.Name), esc(c.Config.Name))
+	fmt.Fprintf(&b, `    <mxGraphModel dx="%d" dy="%d" grid="1" gridSize="10" page="1" pageWidth="%d" pageHeight="%d">`+"\n",
+		width, height, width, height)
+	b.WriteString("      <root>\n")
+	b.WriteString(`        <mxCell id="0"/>` + "\n")
+	b.WriteString(`        <mxCell id="1" parent="0"/>` + "\n")
+
+	for _, row := range rows {
+		for _, n := range row {
+			style := fmt.Sprintf("%s;html=1;fillColor=%s;strokeColor=#ffffff;verticalLabelPosition=bottom;verticalAlign=top;align=center;",
+				drawioShapes[n.icon], graphNodeColor(n, states))
+			fmt.Fprintf(&b, `        <mxCell id="node-%s" value="%s" style="%s" vertex="1" parent="1">`+"\n",
+				esc(n.name), esc(n.name), style)
+			fmt.Fprintf(&b, `          <mxGeometry x="%d" y="%d" width="%d" height="%d" as="geometry"/>`+"\n",
+				n.x-graphIconSize/2, n.y-graphIconSize/2, graphIconSize, graphIconSize)
+			b.WriteString("        </mxCell>\n")
+		}
+	}
+
+	for i := 0; i < len(c.Links); i++ {
+		l, ok := c.Links[i]
+		if !ok || pos[l.A.Node.ShortName] == nil || pos[l.B.Node.ShortName] == nil {
+			continue
+		}
+		fmt.Fprintf(&b, `        <mxCell id="link-%d" style="endArrow=none;html=1;strokeColor=#6e7781;strokeWidth=2;" edge="1" parent="1" source="node-%s" target="node-%s">`+"\n",
+			i, esc(l.A.Node.ShortName), esc(l.B.Node.ShortName))
+		b.WriteString(`          <mxGeometry relative="1" as="geometry"/>` + "\n")
+		b.WriteString("        </mxCell>\n")
+		// the interface names are placed as the labels at the link ends
+		for j, e := range []string{l.A.EndpointName, l.B.EndpointName} {
+			x := "-0.7"
+			if j == 1 {
+				x = "0.7"
+			}
+			fmt.Fprintf(&b, `        <mxCell id="link-%d-%d" value="%s" style="edgeLabel;html=1;align=center;verticalAlign=middle;resizable=0;points=[];fontSize=10;fontColor=#0550ae;" vertex="1" connectable="0" parent="link-%d">`+"\n",
+				i, j, esc(e), i)
+			fmt.Fprintf(&b, `          <mxGeometry x="%s" relative="1" as="geometry">`+"\n", x)
+			b.WriteString(`            <mxPoint as="offset"/>` + "\n")
+			b.WriteString("          </mxGeometry>\n")
+			b.WriteString("        </mxCell>\n")
+		}
+	}
+
+	b.WriteString("      </root>\n")
+	b.WriteString("    </mxGraphModel>\n")
+	b.WriteString("  </diagram>\n")
+	b.WriteString("</mxfile>\n")
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// mermaidIDRe matches the characters not allowed in the mermaid node ids
+var mermaidIDRe = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// mermaidLabel escapes the characters of the mermaid labels
+var mermaidLabel = strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace
+
+// WriteGraphMermaid renders the lab topology as a mermaid flowchart,
+// the node groups are rendered as subgraphs
+func (c *CLab) WriteGraphMermaid(w io.Writer, states map[string]string) error {
+	rows, groups, _, _ := c.graphLayout()
+	pos, names := graphNodesByName(rows)
+	ids := make(map[string]string, len(names))
+	used := map[string]bool{}
+	for i, name := range names {
+		id := mermaidIDRe.ReplaceAllString(name, "_")
+		if used[id] {
+			id = fmt.Sprintf("%s_%d", id, i)
+		}
+		used[id] = true
+		ids[name] = id
+	}
+
+	var b bytes.Buffer
+	b.WriteString("graph TD\n")
+	for i, row := range rows {
+		indent := "  "
+		if groups[i] != "" {
+			fmt.Fprintf(&b, "  subgraph %s[\"%s\"]\n", "group_"+mermaidIDRe.ReplaceAllString(groups[i], "_"), mermaidLabel(groups[i]))
+			indent = "    "
+		}
+		for _, n := range row {
+			fmt.Fprintf(&b, "%s%s[\"%s<br/><small>%s</small>\"]\n", indent, ids[n.name], mermaidLabel(n.name), mermaidLabel(n.kind))
+		}
+		if groups[i] != "" {
+			b.WriteString("  end\n")
+		}
+	}
+	for i := 0; i < len(c.Links); i++ {
+		l, ok := c.Links[i]
+		if !ok || pos[l.A.Node.ShortName] == nil || pos[l.B.Node.ShortName] == nil {
+			continue
+		}
+		fmt.Fprintf(&b, "  %s ---|\"%s - %s\"| %s\n", ids[l.A.Node.ShortName],
+			mermaidLabel(l.A.EndpointName), mermaidLabel(l.B.EndpointName), ids[l.B.Node.ShortName])
+	}
+
+	if states != nil {
+		byState := map[string][]string{}
+		for _, name := range names {
+			s := graphNodeState(states, name)
+			byState[s] = append(byState[s], ids[name])
+		}
+		for _, s := range []string{graphStateRunning, graphStateStopped, graphStateNotDeployed} {
+			if len(byState[s]) == 0 {
+				continue
+			}
+			class := strings.ReplaceAll(s, "-", "_")
+			fmt.Fprintf(&b, "  classDef %s fill:%s,stroke:%s,color:#fff\n", class, graphStateColors[s], graphStateColors[s])
+			fmt.Fprintf(&b, "  class %s %s\n", strings.Join(byState[s], ","), class)
+		}
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// dotEscape escapes the characters of the dot quoted strings
+var dotEscape = strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace
+
+// dotQuote returns the string quoted as the dot ID
+func dotQuote(s string) string {
+	return `"` + dotEscape(s) + `"`
+}
+
+// WriteGraphDot renders the lab topology in the graphviz dot language,
+// the node groups are rendered as clusters and the links are labelled with the interface names
+func (c *CLab) WriteGraphDot(w io.Writer, states map[string]string) error {
+	rows, groups, _, _ := c.graphLayout()
+	pos, _ := graphNodesByName(rows)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "graph %s {\n", dotQuote(c.Config.Name))
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fontcolor=white, fontname=Helvetica];\n")
+	b.WriteString("  edge [fontsize=10, fontname=Helvetica];\n")
+	for i, row := range rows {
+		indent := "  "
+		if groups[i] != "" {
+			fmt.Fprintf(&b, "  subgraph %s {\n", dotQuote("cluster_"+groups[i]))
+			fmt.Fprintf(&b, "    label=%s;\n", dotQuote(groups[i]))
+			indent = "    "
+		}
+		for _, n := range row {
+			fmt.Fprintf(&b, "%s%s [label=%s, fillcolor=%s];\n", indent, dotQuote(n.name),
+				`"`+dotEscape(n.name)+`\n`+dotEscape(n.kind)+`"`, dotQuote(graphNodeColor(n, states)))
+		}
+		if groups[i] != "" {
+			b.WriteString("  }\n")
+		}
+	}
+	for i := 0; i < len(c.Links); i++ {
+		l, ok := c.Links[i]
+		if !ok || pos[l.A.Node.ShortName] == nil || pos[l.B.Node.ShortName] == nil {
+			continue
+		}
+		fmt.Fprintf(&b, "  %s -- %s [taillabel=%s, headlabel=%s];\n", dotQuote(l.A.Node.ShortName), dotQuote(l.B.Node.ShortName),
+			dotQuote(l.A.EndpointName), dotQuote(l.B.EndpointName))
+	}
+	b.WriteString("}\n")
+	_, err := w.Write(b.Bytes())
+	return err
+}
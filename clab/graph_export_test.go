@@ -0,0 +1,130 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestGraphExportFormat(t *testing.T) {
+	tests := map[string]struct {
+		path    string
+		want    string
+		wantErr bool
+	}{
+		"drawio":      {path: "lab.drawio", want: GraphFormatDrawio},
+		"mermaid":     {path: "docs/lab.mmd", want: GraphFormatMermaid},
+		"dot":         {path: "lab.DOT", want: GraphFormatDot},
+		"svg":         {path: "lab.svg", want: GraphFormatSVG},
+		"unsupported": {path: "lab.vsdx", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := GraphExportFormat(tc.path)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+// graphStates are the states of the topo14 nodes, client1 is not deployed
+var graphStates = map[string]string{
+	"spine1": "running",
+	"leaf1":  "running",
+	"leaf2":  "exited",
+}
+
+func TestWriteGraphDrawio(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo14.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := c.WriteGraphDrawio(&b, graphStates); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	// the diagram must be a well-formed XML document
+	var doc struct{}
+	if err := xml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("invalid drawio xml: %v", err)
+	}
+	for _, want := range []string{
+		`id="node-spine1" value="spine1" style="shape=mxgraph.cisco.routers.router;html=1;fillColor=#2da44e;`,
+		`id="node-leaf2" value="leaf2" style="shape=mxgraph.cisco.switches.layer_3_switch;html=1;fillColor=#cf222e;`,
+		`id="node-client&lt;1&gt;" value="client&lt;1&gt;" style="shape=mxgraph.cisco.computers_and_peripherals.pc;html=1;fillColor=#afb8c1;`,
+		`edge="1" parent="1" source="node-spine1" target="node-leaf1"`,
+		`value="e1-2"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("drawio diagram doesn't contain %s", want)
+		}
+	}
+	if n := strings.Count(out, `edge="1"`); n != 3 {
+		t.Errorf("expected 3 links, got %d", n)
+	}
+}
+
+func TestWriteGraphMermaid(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo14.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := c.WriteGraphMermaid(&b, graphStates); err != nil {
+		t.Fatal(err)
+	}
+	want := `graph TD
+  subgraph group_leaf["leaf"]
+    leaf1["leaf1<br/><small>ceos</small>"]
+    leaf2["leaf2<br/><small>ceos</small>"]
+  end
+  subgraph group_spine["spine"]
+    spine1["spine1<br/><small>srl</small>"]
+  end
+  client_1_["client#lt;1#gt;<br/><small>linux</small>"]
+  spine1 ---|"e1-1 - eth1"| leaf1
+  spine1 ---|"e1-2 - eth1"| leaf2
+  leaf1 ---|"eth2 - eth1"| client_1_
+  classDef running fill:#2da44e,stroke:#2da44e,color:#fff
+  class leaf1,spine1 running
+  classDef stopped fill:#cf222e,stroke:#cf222e,color:#fff
+  class leaf2 stopped
+  classDef not_deployed fill:#afb8c1,stroke:#afb8c1,color:#fff
+  class client_1_ not_deployed
+`
+	if got := b.String(); got != want {
+		t.Errorf("unexpected mermaid graph, got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteGraphDot(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo14.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := c.WriteGraphDot(&b, nil); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		`graph "topo14" {`,
+		`subgraph "cluster_spine" {`,
+		`"spine1" [label="spine1\nsrl", fillcolor="#1f6feb"];`,
+		`"client<1>" [label="client<1>\nlinux", fillcolor="#57606a"];`,
+		`"spine1" -- "leaf2" [taillabel="e1-2", headlabel="eth1"];`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("dot graph doesn't contain %s", want)
+		}
+	}
+}
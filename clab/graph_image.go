@@ -0,0 +1,233 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/srl-labs/containerlab/nodes"
+)
+
+const (
+	// graph image formats
+	GraphFormatSVG = "svg"
+	GraphFormatPNG = "png"
+
+	// sizes of the graph image elements in pixels
+	graphMargin     = 40
+	graphGroupLabel = 120
+	graphNodeWidth  = 160
+	graphRowHeight  = 160
+	graphIconSize   = 48
+)
+
+// kindIcons maps the kinds to the icons drawn for the nodes,
+// kinds without an entry are drawn with the host icon
+var kindIcons = map[string]string{
+	nodes.NodeKindSRL:     "router",
+	nodes.NodeKindCRPD:    "router",
+	nodes.NodeKindVrSROS:  "router",
+	nodes.NodeKindVrVMX:   "router",
+	nodes.NodeKindVrXRV:   "router",
+	nodes.NodeKindVrXRV9K: "router",
+	nodes.NodeKindVrCSR:   "router",
+	nodes.NodeKindVrROS:   "router",
+	nodes.NodeKindCEOS:    "switch",
+	nodes.NodeKindCVX:     "switch",
+	nodes.NodeKindSonic:   "switch",
+	nodes.NodeKindVrVEOS:  "switch",
+	nodes.NodeKindVrN9KV:  "switch",
+	nodes.NodeKindVrNXOS:  "switch",
+	nodes.NodeKindVrVQFX:  "switch",
+	nodes.NodeKindVrFTOSV: "switch",
+	nodes.NodeKindVrPAN:   "firewall",
+	nodes.NodeKindBridge:  "bridge",
+	nodes.NodeKindOVS:     "bridge",
+}
+
+// graphIconDefs holds the SVG symbols of the node icons
+const graphIconDefs = `<defs>
+<symbol id="router" viewBox="0 0 48 48"><circle cx="24" cy="24" r="22" fill="#1f6feb"/>` +
+	`<path d="M24 6v14M18 12l6-6 6 6M24 42V28M18 36l6 6 6-6M6 24h14M12 18l-6 6 6 6M42 24H28M36 18l6 6-6 6" stroke="#fff" stroke-width="3" fill="none"/></symbol>
+<symbol id="switch" viewBox="0 0 48 48"><rect x="2" y="8" width="44" height="32" rx="4" fill="#2da44e"/>` +
+	`<path d="M10 18h26M30 13l6 5-6 5M38 30H12M18 25l-6 5 6 5" stroke="#fff" stroke-width="3" fill="none"/></symbol>
+<symbol id="firewall" viewBox="0 0 48 48"><rect x="2" y="6" width="44" height="36" rx="2" fill="#cf222e"/>` +
+	`<path d="M2 18h44M2 30h44M16 6v12M32 6v12M24 18v12M10 30v12M38 30v12" stroke="#fff" stroke-width="2" fill="none"/></symbol>
+<symbol id="bridge" viewBox="0 0 48 48"><polygon points="12,4 36,4 46,24 36,44 12,44 2,24" fill="#8250df"/>` +
+	`<path d="M10 24h28" stroke="#fff" stroke-width="3"/></symbol>
+<symbol id="host" viewBox="0 0 48 48"><rect x="4" y="6" width="40" height="28" rx="2" fill="#57606a"/>` +
+	`<rect x="8" y="10" width="32" height="20" fill="#fff"/><path d="M16 42h16M24 34v8" stroke="#57606a" stroke-width="3"/></symbol>
+</defs>
+`
+
+// graphNode is a node placed on the graph image
+type graphNode struct {
+	name, kind, icon string
+	// center of the node icon
+	x, y int
+}
+
+// graphLayout places the lab nodes on the image: every group is a row,
+// rows are sorted by the group name with the nodes without a group in the last row
+func (c *CLab) graphLayout() (rows [][]*graphNode, groups []string, width, height int) {
+	byGroup := map[string][]*graphNode{}
+	for _, n := range c.Nodes {
+		cfg := n.Config()
+		icon, ok := kindIcons[cfg.Kind]
+		if !ok {
+			icon = "host"
+		}
+		g := strings.TrimSpace(cfg.Group)
+		byGroup[g] = append(byGroup[g], &graphNode{name: cfg.ShortName, kind: cfg.Kind, icon: icon})
+	}
+	for g := range byGroup {
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		// nodes without a group go last
+		if groups[i] == "" || groups[j] == "" {
+			return groups[j] == ""
+		}
+		return groups[i] < groups[j]
+	})
+
+	maxRow := 0
+	for _, g := range groups {
+		if len(byGroup[g]) > maxRow {
+			maxRow = len(byGroup[g])
+		}
+	}
+	width = 2*graphMargin + graphGroupLabel + maxRow*graphNodeWidth
+	height = 2*graphMargin + len(groups)*graphRowHeight
+
+	for i, g := range groups {
+		row := byGroup[g]
+		sort.Slice(row, func(i, j int) bool { return row[i].name < row[j].name })
+		// rows are centered horizontally
+		offset := graphMargin + graphGroupLabel + (maxRow-len(row))*graphNodeWidth/2
+		for j, n := range row {
+			n.x = offset + j*graphNodeWidth + graphNodeWidth/2
+			n.y = graphMargin + i*graphRowHeight + graphRowHeight/2 - 16
+		}
+		rows = append(rows, row)
+	}
+	return rows, groups, width, height
+}
+
+// WriteGraphSVG renders the lab topology as an SVG image with kind icons and group-based layout
+func (c *CLab) WriteGraphSVG(w io.Writer) error {
+	rows, groups, width, height := c.graphLayout()
+	pos := map[string]*graphNode{}
+	for _, row := range rows {
+		for _, n := range row {
+			pos[n.name] = n
+		}
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Helvetica, Arial, sans-serif">`+"\n",
+		width, height, width, height)
+	b.WriteString(graphIconDefs)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="#fff"/>`+"\n")
+	fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="16" font-weight="bold">%s</text>`+"\n",
+		graphMargin/2, graphMargin/2+8, html.EscapeString(c.Config.Name))
+
+	for i, g := range groups {
+		if g == "" {
+			continue
+		}
+		y := graphMargin + i*graphRowHeight
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" rx="8" fill="#f6f8fa" stroke="#d0d7de"/>`+"\n",
+			graphMargin/2, y+4, width-graphMargin, graphRowHeight-8)
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="13" fill="#57606a">%s</text>`+"\n",
+			graphMargin, y+graphRowHeight/2, html.EscapeString(g))
+	}
+
+	// links are drawn in the order of their definition, under the nodes
+	for i := 0; i < len(c.Links); i++ {
+		l, ok := c.Links[i]
+		if !ok {
+			continue
+		}
+		a, z := pos[l.A.Node.ShortName], pos[l.B.Node.ShortName]
+		if a == nil || z == nil {
+			continue
+		}
+		fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#6e7781" stroke-width="2"/>`+"\n", a.x, a.y, z.x, z.y)
+		writeEndpointLabel(&b, a, z, l.A.EndpointName)
+		writeEndpointLabel(&b, z, a, l.B.EndpointName)
+	}
+
+	for _, row := range rows {
+		for _, n := range row {
+			fmt.Fprintf(&b, `<use xlink:href="#%s" x="%d" y="%d" width="%d" height="%d"/>`+"\n",
+				n.icon, n.x-graphIconSize/2, n.y-graphIconSize/2, graphIconSize, graphIconSize)
+			fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="13" text-anchor="middle">%s</text>`+"\n",
+				n.x, n.y+graphIconSize/2+16, html.EscapeString(n.name))
+			fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="11" text-anchor="middle" fill="#57606a">%s</text>`+"\n",
+				n.x, n.y+graphIconSize/2+30, html.EscapeString(n.kind))
+		}
+	}
+	b.WriteString("</svg>\n")
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// writeEndpointLabel writes the interface name next to the node n on the link towards the peer node
+func writeEndpointLabel(b *bytes.Buffer, n, peer *graphNode, ifName string) {
+	// label is placed at 40% of the distance between the icon edge and the middle of the link
+	x := n.x + (peer.x-n.x)*3/10
+	y := n.y + (peer.y-n.y)*3/10
+	fmt.Fprintf(b, `<text x="%d" y="%d" font-size="10" text-anchor="middle" fill="#0550ae" stroke="#fff" stroke-width="3" paint-order="stroke">%s</text>`+"\n",
+		x, y, html.EscapeString(ifName))
+}
+
+// GraphImageFormat returns the image format matching the file extension
+func GraphImageFormat(p string) (string, error) {
+	switch strings.ToLower(filepath.Ext(p)) {
+	case ".svg":
+		return GraphFormatSVG, nil
+	case ".png":
+		return GraphFormatPNG, nil
+	}
+	return "", fmt.Errorf("unsupported graph image extension %q, use .svg or .png", filepath.Ext(p))
+}
+
+// ExportGraphImage renders the lab topology to the SVG or PNG image file.
+// PNG images are converted from SVG with rsvg-convert
+func (c *CLab) ExportGraphImage(p string) error {
+	format, err := GraphImageFormat(p)
+	if err != nil {
+		return err
+	}
+	var svg bytes.Buffer
+	if err := c.WriteGraphSVG(&svg); err != nil {
+		return err
+	}
+	if format == GraphFormatSVG {
+		return ioutil.WriteFile(p, svg.Bytes(), 0644)
+	}
+
+	if !commandExists("rsvg-convert") {
+		return fmt.Errorf("rsvg-convert is required to export the graph to PNG, install librsvg or use the .svg extension")
+	}
+	cmd := exec.Command("rsvg-convert", "--format", "png", "--output", p)
+	cmd.Stdin = &svg
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to convert the graph to PNG: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteGraphSVG(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo14.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := c.WriteGraphSVG(&b); err != nil {
+		t.Fatal(err)
+	}
+	svg := b.String()
+
+	for _, want := range []string{
+		`<use xlink:href="#router"`,
+		`<use xlink:href="#switch"`,
+		`<use xlink:href="#host"`,
+		`>spine1</text>`,
+		`>leaf2</text>`,
+		`>e1-2</text>`,
+		// group labels and escaped node names
+		`>spine</text>`,
+		`>leaf</text>`,
+		`>client&lt;1&gt;</text>`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("svg image doesn't contain %s", want)
+		}
+	}
+	if n := strings.Count(svg, "<line "); n != 3 {
+		t.Errorf("expected 3 links, got %d", n)
+	}
+
+	rows, groups, _, _ := c.graphLayout()
+	wantGroups := []string{"leaf", "spine", ""}
+	if strings.Join(groups, ",") != strings.Join(wantGroups, ",") {
+		t.Errorf("expected groups %q, got %q", wantGroups, groups)
+	}
+	if rows[0][0].name != "leaf1" || rows[0][1].name != "leaf2" || rows[0][0].y != rows[0][1].y {
+		t.Errorf("leaf nodes are not placed in the same row")
+	}
+}
+
+func TestGraphImageFormat(t *testing.T) {
+	tests := map[string]struct {
+		path    string
+		want    string
+		wantErr bool
+	}{
+		"svg":         {path: "lab.svg", want: GraphFormatSVG},
+		"png":         {path: "/tmp/lab.PNG", want: GraphFormatPNG},
+		"unsupported": {path: "lab.jpg", wantErr: true},
+		"no_ext":      {path: "lab", wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := GraphImageFormat(tc.path)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
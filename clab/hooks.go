@@ -0,0 +1,212 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/shlex"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// lifecycle points the hooks of the nodes are run at
+const (
+	HookPreDeploy  = "pre-deploy"
+	HookPostDeploy = "post-deploy"
+	HookPreDestroy = "pre-destroy"
+)
+
+// failure policies of the hooks
+const (
+	hookFailureWarn = "warn"
+	hookFailureFail = "fail"
+)
+
+// stageHooks returns the hooks of the lifecycle point
+func stageHooks(hooks *types.Hooks, stage string) []*types.Hook {
+	if hooks == nil {
+		return nil
+	}
+	switch stage {
+	case HookPreDeploy:
+		return hooks.PreDeploy
+	case HookPostDeploy:
+		return hooks.PostDeploy
+	case HookPreDestroy:
+		return hooks.PreDestroy
+	}
+	return nil
+}
+
+// verifyHooks checks the commands, the timeouts and the failure policies of the hooks of the nodes.
+// The pre-deploy hooks run before the node container exists, thus they must run on the container host
+func (c *CLab) verifyHooks() error {
+	for _, name := range c.nodeNames() {
+		cfg := c.Nodes[name].Config()
+		for _, stage := range []string{HookPreDeploy, HookPostDeploy, HookPreDestroy} {
+			for i, h := range stageHooks(cfg.Hooks, stage) {
+				if err := verifyHook(cfg, stage, h); err != nil {
+					return fmt.Errorf("node %q: %s hook %d: %v", name, stage, i+1, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func verifyHook(cfg *types.NodeConfig, stage string, h *types.Hook) error {
+	if h == nil || strings.TrimSpace(h.Command) == "" {
+		return fmt.Errorf("command is not set")
+	}
+	if h.Timeout != "" {
+		d, err := time.ParseDuration(h.Timeout)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid timeout %q", h.Timeout)
+		}
+	}
+	switch h.OnFailure {
+	case "", hookFailureWarn, hookFailureFail:
+	default:
+		return fmt.Errorf("unknown on-failure policy %q, use one of [%s, %s]", h.OnFailure, hookFailureWarn, hookFailureFail)
+	}
+	if h.Host {
+		return nil
+	}
+	if stage == HookPreDeploy {
+		return fmt.Errorf("pre-deploy hooks run before the node is created and must set host: true")
+	}
+	if _, ok := noMgmtKinds[cfg.Kind]; ok {
+		return fmt.Errorf("nodes of kind %s have no container to run the command in, set host: true", cfg.Kind)
+	}
+	if _, err := shlex.Split(h.Command); err != nil {
+		return fmt.Errorf("invalid command %q: %v", h.Command, err)
+	}
+	return nil
+}
+
+// RunHooks runs the hooks of the lifecycle point of the nodes. The nodes run their hooks concurrently,
+// the hooks of a node are run one by one in the order they are defined.
+// The post-deploy and pre-destroy hooks are run for the nodes with the running containers only.
+// The error is returned when a hook with the fail policy fails, the failures of the other hooks are logged
+func (c *CLab) RunHooks(ctx context.Context, stage string, containers []types.GenericContainer) error {
+	running := map[string]*types.GenericContainer{}
+	for i := range containers {
+		if containers[i].State == "running" {
+			running[containers[i].Labels[NodeNameLabel]] = &containers[i]
+		}
+	}
+
+	var (
+		mu   sync.Mutex
+		errs []string
+	)
+	wg := &sync.WaitGroup{}
+	for _, name := range c.nodeNames() {
+		n := c.Nodes[name]
+		hooks := stageHooks(n.Config().Hooks, stage)
+		if len(hooks) == 0 {
+			continue
+		}
+		ctr := running[name]
+		if stage != HookPreDeploy {
+			if ctr == nil {
+				log.Warnf("skipping %s hooks of node %s, its container is not running", stage, name)
+				continue
+			}
+			keepMgmtAddresses(n.Config(), ctr)
+		}
+		wg.Add(1)
+		go func(n nodes.Node, ctr *types.GenericContainer, hooks []*types.Hook) {
+			defer wg.Done()
+			if err := c.runNodeHooks(ctx, n, ctr, stage, hooks); err != nil {
+				mu.Lock()
+				errs = append(errs, err.Error())
+				mu.Unlock()
+			}
+		}(n, ctr, hooks)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return fmt.Errorf("%s hooks failed: %s", stage, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// runNodeHooks runs the hooks of the node and stops at the first failed hook with the fail policy
+func (c *CLab) runNodeHooks(ctx context.Context, n nodes.Node, ctr *types.GenericContainer, stage string, hooks []*types.Hook) error {
+	name := n.Config().ShortName
+	for _, h := range hooks {
+		log.Infof("Running %s hook of node %s: %s", stage, name, h.Command)
+		out, err := c.runHook(ctx, n, ctr, stage, h)
+		if len(out) > 0 {
+			log.Infof("%s hook of node %s output:\n%s", stage, name, strings.TrimRight(string(out), "\n"))
+		}
+		if err == nil {
+			continue
+		}
+		if h.OnFailure == hookFailureFail {
+			return fmt.Errorf("node %s: %q: %v", name, h.Command, err)
+		}
+		log.Errorf("%s hook of node %s failed: %q: %v", stage, name, h.Command, err)
+	}
+	return nil
+}
+
+// runHook runs the hook command with sh in the directory of the topology file on the container host
+// or with the runtime exec inside the node container, and returns the output of the command.
+// The host commands get the node described with the CLAB_* env vars
+func (c *CLab) runHook(ctx context.Context, n nodes.Node, ctr *types.GenericContainer, stage string, h *types.Hook) ([]byte, error) {
+	timeout := hookTimeout
+	if h.Timeout != "" {
+		timeout, _ = time.ParseDuration(h.Timeout)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cfg := n.Config()
+	id, container := cfg.LongName, cfg.LongName
+	if ctr != nil {
+		id = ctr.ID
+		if len(ctr.Names) > 0 {
+			container = strings.TrimLeft(ctr.Names[0], "/")
+		}
+	}
+
+	if h.Host {
+		cmd := exec.CommandContext(ctx, "sh", "-c", h.Command)
+		if c.TopoFile != nil && c.TopoFile.path != "" {
+			cmd.Dir = filepath.Dir(c.TopoFile.path)
+		}
+		ev := &NodeEvent{Lab: c.Config.Name, Node: cfg.ShortName, Container: container, State: stage}
+		cmd.Env = append(os.Environ(), hookEnv(cfg, ev)...)
+		return cmd.CombinedOutput()
+	}
+
+	args, err := shlex.Split(h.Command)
+	if err != nil {
+		return nil, err
+	}
+	stdout, stderr, rc, err := n.GetRuntime().ExecWithExitCode(ctx, id, args)
+	out := append(stdout, stderr...)
+	if err != nil {
+		return out, err
+	}
+	if rc != 0 {
+		return out, fmt.Errorf("exit code %d", rc)
+	}
+	return out, nil
+}
@@ -0,0 +1,40 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestVerifyHook(t *testing.T) {
+	tests := map[string]struct {
+		kind    string
+		stage   string
+		hook    *types.Hook
+		wantErr bool
+	}{
+		"exec":               {kind: "linux", stage: HookPostDeploy, hook: &types.Hook{Command: "sysctl -w net.ipv4.ip_forward=1"}},
+		"host":               {kind: "linux", stage: HookPreDeploy, hook: &types.Hook{Command: "./prepare.sh", Host: true}},
+		"timeout-policy":     {kind: "linux", stage: HookPreDestroy, hook: &types.Hook{Command: "poweroff", Timeout: "30s", OnFailure: "fail"}},
+		"no-command":         {kind: "linux", stage: HookPostDeploy, hook: &types.Hook{Command: " "}, wantErr: true},
+		"invalid-timeout":    {kind: "linux", stage: HookPostDeploy, hook: &types.Hook{Command: "ls", Timeout: "10"}, wantErr: true},
+		"negative-timeout":   {kind: "linux", stage: HookPostDeploy, hook: &types.Hook{Command: "ls", Timeout: "-1s"}, wantErr: true},
+		"unknown-policy":     {kind: "linux", stage: HookPostDeploy, hook: &types.Hook{Command: "ls", OnFailure: "ignore"}, wantErr: true},
+		"pre-deploy-exec":    {kind: "linux", stage: HookPreDeploy, hook: &types.Hook{Command: "ls"}, wantErr: true},
+		"no-container-exec":  {kind: "bridge", stage: HookPostDeploy, hook: &types.Hook{Command: "ls"}, wantErr: true},
+		"no-container-host":  {kind: "bridge", stage: HookPostDeploy, hook: &types.Hook{Command: "ip link", Host: true}},
+		"unterminated-quote": {kind: "linux", stage: HookPostDeploy, hook: &types.Hook{Command: "echo 'a"}, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := verifyHook(&types.NodeConfig{Kind: tc.kind}, tc.stage, tc.hook)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("verifyHook() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/vishvananda/netlink"
+)
+
+// hostNICAliasPrefix prefixes the original name of a host NIC moved to the node netns in the NIC alias,
+// so that the NIC is found and renamed back when it returns to the host netns
+const hostNICAliasPrefix = "clab-host-nic:"
+
+// hostEndpoints returns the host endpoint of the link between the host and a node
+// along with the endpoint of the node
+func hostEndpoints(l *types.Link) (host, peer *types.Endpoint) {
+	for _, p := range [][2]*types.Endpoint{{l.A, l.B}, {l.B, l.A}} {
+		if p[0].Node.Kind == nodes.NodeKindHOST && p[1].Node.Kind != nodes.NodeKindHOST {
+			return p[0], p[1]
+		}
+	}
+	return nil, nil
+}
+
+// hostNICEndpoints returns the host endpoint of the link referencing an existing host interface
+// along with the endpoint of the node the interface is attached to.
+// The links with the host endpoints not existing in the host netns are wired with veth pairs
+func hostNICEndpoints(l *types.Link) (nic, peer *types.Endpoint) {
+	if l.Stitch != nil || isSubIfLink(l) {
+		return nil, nil
+	}
+	nic, peer = hostEndpoints(l)
+	if nic == nil {
+		return nil, nil
+	}
+	if _, err := netlink.LinkByName(nic.EndpointName); err != nil {
+		return nil, nil
+	}
+	return nic, peer
+}
+
+// verifyHostNIC checks that the existing host interface can be attached to the node
+func verifyHostNIC(nic netlink.Link, peer *types.Endpoint) error {
+	name := nic.Attrs().Name
+	switch {
+	case nic.Type() == "veth" || nic.Type() == "bridge" || nic.Type() == "openvswitch":
+		return fmt.Errorf("host interface %s referenced in topology already exists", name)
+	case peer.Node.Kind == nodes.NodeKindOVS:
+		return fmt.Errorf("host interface %s can't be attached to ovs-bridge %s, attach it to the ovs bridge with ovs-vsctl", name, peer.Node.ShortName)
+	}
+	addrs, err := netlink.AddrList(nic, netlink.FAMILY_ALL)
+	if err != nil {
+		return err
+	}
+	for _, a := range addrs {
+		if a.IP.IsGlobalUnicast() {
+			return fmt.Errorf("host interface %s has address %s, attaching it to node %s would disrupt the host connectivity", name, a.IP, peer.Node.ShortName)
+		}
+	}
+	return nil
+}
+
+// attachHostNIC attaches the existing host interface of the link to the node.
+// The interface is moved to the node netns and renamed to the node endpoint name,
+// or attached to the linux bridge when the node is a bridge
+func (c *CLab) attachHostNIC(l *types.Link, nic, peer *types.Endpoint) error {
+	log.Infof("Attaching host interface %s to %s:%s", nic.EndpointName, peer.Node.ShortName, peer.EndpointName)
+	link, err := netlink.LinkByName(nic.EndpointName)
+	if err != nil {
+		return fmt.Errorf("failed to lookup host interface %q: %v", nic.EndpointName, err)
+	}
+	if err := verifyHostNIC(link, peer); err != nil {
+		return err
+	}
+	if peer.Node.Kind == nodes.NodeKindBridge {
+		br := peer.Node.ShortName
+		if br == "mgmt-net" {
+			br = c.Config.Mgmt.Bridge
+		}
+		ep := &vEthEndpoint{Link: link, LinkName: nic.EndpointName, Bridge: br, VLAN: l.VLAN, Netem: l.Netem}
+		return ep.toBridge()
+	}
+	if err := netlink.LinkSetAlias(link, hostNICAliasPrefix+nic.EndpointName); err != nil {
+		return fmt.Errorf("failed to set alias of host interface %s: %v", nic.EndpointName, err)
+	}
+	// the interface is renamed in the node netns and has to be down to be renamed
+	if err := netlink.LinkSetDown(link); err != nil {
+		return fmt.Errorf("failed to set host interface %s down: %v", nic.EndpointName, err)
+	}
+	ep := &vEthEndpoint{
+		Link:     link,
+		LinkName: peer.EndpointName,
+		NSName:   peer.Node.LongName,
+		NSPath:   peer.Node.NSPath,
+		Profile:  peer.Node.InterfaceProfile,
+		Netem:    l.Netem,
+	}
+	return ep.toNS()
+}
+
+// DetachHostNICs returns the host interfaces attached to the nodes back to the host netns
+// under their original names and detaches the host interfaces attached to the linux bridges.
+// The interfaces are detached before the nodes are deleted, as the kernel moves the physical
+// interfaces of a deleted netns to the host netns keeping the names they got in the node netns
+func (c *CLab) DetachHostNICs(ctx context.Context) {
+	for _, l := range c.Links {
+		if l.Stitch != nil || isSubIfLink(l) {
+			continue
+		}
+		nic, peer := hostEndpoints(l)
+		if nic == nil {
+			continue
+		}
+		if err := c.detachHostNIC(ctx, nic, peer); err != nil {
+			log.Warnf("failed to detach host interface %s from %s: %v", nic.EndpointName, peer.Node.ShortName, err)
+		}
+	}
+	restoreHostNICNames()
+}
+
+func (c *CLab) detachHostNIC(ctx context.Context, nic, peer *types.Endpoint) error {
+	if peer.Node.Kind == nodes.NodeKindBridge {
+		link, err := netlink.LinkByName(nic.EndpointName)
+		// the host endpoint was a veth deleted along with the lab
+		if err != nil || link.Type() == "veth" || link.Attrs().MasterIndex == 0 {
+			return nil
+		}
+		log.Infof("Detaching host interface %s from bridge %s", nic.EndpointName, peer.Node.ShortName)
+		return netlink.LinkSetNoMaster(link)
+	}
+	n, ok := c.Nodes[peer.Node.ShortName]
+	if !ok {
+		return nil
+	}
+	nsPath, err := n.GetRuntime().GetNSPath(ctx, peer.Node.LongName)
+	if err != nil {
+		// the node container doesn't run
+		return nil
+	}
+	hostNS, err := ns.GetCurrentNS()
+	if err != nil {
+		return err
+	}
+	defer hostNS.Close()
+	nodeNS, err := ns.GetNS(nsPath)
+	if err != nil {
+		return err
+	}
+	defer nodeNS.Close()
+	return nodeNS.Do(func(_ ns.NetNS) error {
+		link, err := netlink.LinkByName(peer.EndpointName)
+		if err != nil || link.Attrs().Alias != hostNICAliasPrefix+nic.EndpointName {
+			// the endpoint is a veth
+			return nil
+		}
+		log.Infof("Returning host interface %s from %s to the host", nic.EndpointName, peer.Node.ShortName)
+		if err := netlink.LinkSetDown(link); err != nil {
+			return err
+		}
+		if err := netlink.LinkSetName(link, nic.EndpointName); err != nil {
+			return err
+		}
+		return netlink.LinkSetNsFd(link, int(hostNS.Fd()))
+	})
+}
+
+// restoreHostNICNames renames the host interfaces returned to the host netns to their original names
+// and clears their aliases. The interfaces of the nodes which netns was deleted before they were detached
+// are returned by the kernel under the names they had in the node netns
+func restoreHostNICNames() {
+	links, err := netlink.LinkList()
+	if err != nil {
+		log.Warnf("failed to list host interfaces: %v", err)
+		return
+	}
+	for _, link := range links {
+		alias := link.Attrs().Alias
+		if !strings.HasPrefix(alias, hostNICAliasPrefix) {
+			continue
+		}
+		name := strings.TrimPrefix(alias, hostNICAliasPrefix)
+		if link.Attrs().Name != name {
+			log.Infof("Renaming host interface %s back to %s", link.Attrs().Name, name)
+			if err := netlink.LinkSetDown(link); err != nil {
+				log.Warnf("failed to set host interface %s down: %v", link.Attrs().Name, err)
+				continue
+			}
+			if err := netlink.LinkSetName(link, name); err != nil {
+				log.Warnf("failed to rename host interface %s to %s: %v", link.Attrs().Name, name, err)
+				continue
+			}
+		}
+		if err := netlink.LinkSetAlias(link, ""); err != nil {
+			log.Warnf("failed to clear alias of host interface %s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+
+	"github.com/srl-labs/containerlab/types"
+	"github.com/vishvananda/netlink"
+)
+
+func TestVerifyHostNIC(t *testing.T) {
+	lo, err := netlink.LinkByName("lo")
+	if err != nil {
+		t.Skipf("loopback interface is not available: %v", err)
+	}
+	srl := &types.Endpoint{Node: &types.NodeConfig{ShortName: "srl", Kind: "srl"}, EndpointName: "e1-1"}
+	br := &types.Endpoint{Node: &types.NodeConfig{ShortName: "br1", Kind: "bridge"}, EndpointName: "br1-eth1"}
+	ovs := &types.Endpoint{Node: &types.NodeConfig{ShortName: "ovs1", Kind: "ovs-bridge"}, EndpointName: "ovs1-eth1"}
+
+	tests := map[string]struct {
+		nic     netlink.Link
+		peer    *types.Endpoint
+		wantErr bool
+	}{
+		"loopback-addresses": {
+			nic:  lo,
+			peer: srl,
+		},
+		"linux-bridge": {
+			nic:  lo,
+			peer: br,
+		},
+		"ovs-bridge": {
+			nic:     lo,
+			peer:    ovs,
+			wantErr: true,
+		},
+		"veth": {
+			nic:     &netlink.Veth{LinkAttrs: netlink.LinkAttrs{Name: "srl_e1-1"}},
+			peer:    srl,
+			wantErr: true,
+		},
+		"bridge": {
+			nic:     &netlink.Bridge{LinkAttrs: netlink.LinkAttrs{Name: "br0"}},
+			peer:    srl,
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := verifyHostNIC(tc.nic, tc.peer)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got error %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -20,7 +20,8 @@ const (
 )
 
 func AppendHostsFileEntries(containers []types.GenericContainer, labname string) error {
-	filename := clabHostsFilename
+	// the entries are added to the hosts file of the container host
+	filename := utils.LocalPath(clabHostsFilename)
 	if labname == "" {
 		return fmt.Errorf("missing lab name")
 	}
@@ -41,7 +42,7 @@ func AppendHostsFileEntries(containers []types.GenericContainer, labname string)
 	}
 	var f *os.File
 
-	f, err = os.OpenFile(clabHostsFilename, os.O_APPEND|os.O_WRONLY, os.ModeAppend)
+	f, err = os.OpenFile(filename, os.O_APPEND|os.O_WRONLY, os.ModeAppend)
 	if err != nil {
 		return err
 	}
@@ -87,7 +88,8 @@ func DeleteEntriesFromHostsFile(labname string) error {
 	if labname == "" {
 		return errors.New("missing containerlab name")
 	}
-	f, err := os.OpenFile(clabHostsFilename, os.O_RDWR, 0644) // skipcq: GSC-G302
+	filename := utils.LocalPath(clabHostsFilename)
+	f, err := os.OpenFile(filename, os.O_RDWR, 0644) // skipcq: GSC-G302
 	if err != nil {
 		return err
 	}
@@ -117,7 +119,7 @@ func DeleteEntriesFromHostsFile(labname string) error {
 	if skiplines {
 		// if skiplines is not false, we did not find the end
 		// so we should not mess with /etc/hosts
-		return fmt.Errorf("issue cleaning up %s file. Please do so manually", clabHostsFilename)
+		return fmt.Errorf("issue cleaning up %s file. Please do so manually", filename)
 	}
 	err = f.Truncate(0)
 	if err != nil {
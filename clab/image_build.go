@@ -0,0 +1,144 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// builtImageRepo is the repository of the node images built from the Dockerfiles
+const builtImageRepo = "clab-build"
+
+// imageNameInvalidChars matches the characters not allowed in the image repository names
+var imageNameInvalidChars = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// resolveBuild returns the build config of the node with the build context resolved against the topology file
+// and the name of the image built from it. The image is tagged with the hash of the build context, the Dockerfile
+// and the build args, so that the image is rebuilt only when any of them changes
+func (c *CLab) resolveBuild(b *types.BuildConfig) (*types.BuildConfig, string, error) {
+	if b.Context == "" {
+		return nil, "", fmt.Errorf("build context is not set")
+	}
+	res := *b
+	var err error
+	if res.Context, err = c.resolveTopoPath(b.Context); err != nil {
+		return nil, "", err
+	}
+	if _, err := os.Stat(filepath.Join(res.Context, res.GetDockerfile())); err != nil {
+		return nil, "", fmt.Errorf("failed to read Dockerfile of build context %s: %v", b.Context, err)
+	}
+	hash, err := buildHash(&res)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read build context %s: %v", b.Context, err)
+	}
+	return &res, builtImageName(res.Context, hash), nil
+}
+
+// builtImageName returns the name of the image built from the context directory with the build hash
+func builtImageName(context, hash string) string {
+	name := imageNameInvalidChars.ReplaceAllString(strings.ToLower(filepath.Base(context)), "-")
+	name = strings.Trim(name, "._-")
+	if name == "" {
+		name = "image"
+	}
+	return fmt.Sprintf("%s/%s:%s", builtImageRepo, name, hash[:12])
+}
+
+// buildHash returns the hex sha256 of the Dockerfile path, the build args and the paths, modes and contents
+// of the files of the build context
+func buildHash(b *types.BuildConfig) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "dockerfile %s\n", filepath.ToSlash(filepath.Clean(b.GetDockerfile())))
+	keys := make([]string, 0, len(b.Args))
+	for k := range b.Args {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(h, "arg %s=%s\n", k, b.Args[k])
+	}
+	err := filepath.Walk(b.Context, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(b.Context, path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "file %s %s\n", filepath.ToSlash(rel), info.Mode())
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(h, "link %s\n", link)
+		case info.Mode().IsRegular():
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			if _, err := io.Copy(h, f); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// buildImages builds the images of the nodes declaring the Dockerfile the node image is built from,
+// the images built before from the same build context are reused
+func (c *CLab) buildImages(ctx context.Context) error {
+	names := make([]string, 0, len(c.Nodes))
+	for name, n := range c.Nodes {
+		if n.Config().Build != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	built := map[string]struct{}{}
+	for _, name := range names {
+		cfg := c.Nodes[name].Config()
+		if _, ok := built[cfg.Image]; ok {
+			continue
+		}
+		built[cfg.Image] = struct{}{}
+		r := c.Nodes[name].GetRuntime()
+		exists, err := r.ImageExists(ctx, cfg.Image)
+		if err != nil {
+			return fmt.Errorf("failed to look up image %s: %v", cfg.Image, err)
+		}
+		if exists {
+			log.Infof("Using image %s of node %s built before from %s", cfg.Image, name, cfg.Build.Context)
+			continue
+		}
+		log.Infof("Building image %s of node %s from %s", cfg.Image, name, cfg.Build.Context)
+		start := time.Now()
+		if err := r.BuildImage(ctx, cfg.Image, cfg.Build); err != nil {
+			return fmt.Errorf("failed to build image of node %s: %v", name, err)
+		}
+		log.Infof("Built image %s in %s", cfg.Image, time.Since(start).Round(time.Second))
+	}
+	return nil
+}
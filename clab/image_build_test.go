@@ -0,0 +1,66 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestBuiltImageName(t *testing.T) {
+	hash := "0123456789abcdef0123456789abcdef"
+	tests := map[string]struct {
+		context string
+		want    string
+	}{
+		"plain":   {context: "/labs/images/mytool", want: "clab-build/mytool:0123456789ab"},
+		"invalid": {context: "/labs/images/My Tool!", want: "clab-build/my-tool:0123456789ab"},
+		"empty":   {context: "/labs/images/__", want: "clab-build/image:0123456789ab"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := builtImageName(tc.context, hash); got != tc.want {
+				t.Errorf("builtImageName() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBuildHash(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("Dockerfile", "FROM alpine:3\nCOPY run.sh /\n")
+	write("run.sh", "echo 1\n")
+
+	hash := func(b *types.BuildConfig) string {
+		h, err := buildHash(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+	base := hash(&types.BuildConfig{Context: dir})
+	if h := hash(&types.BuildConfig{Context: dir}); h != base {
+		t.Errorf("hash of the unchanged context changed: %s != %s", h, base)
+	}
+	if h := hash(&types.BuildConfig{Context: dir, Dockerfile: "./Dockerfile"}); h != base {
+		t.Errorf("hash of the same Dockerfile path changed: %s != %s", h, base)
+	}
+	withArgs := hash(&types.BuildConfig{Context: dir, Args: map[string]string{"VERSION": "1"}})
+	if withArgs == base {
+		t.Error("hash didn't change with the build args")
+	}
+	write("run.sh", "echo 2\n")
+	if h := hash(&types.BuildConfig{Context: dir}); h == base {
+		t.Error("hash didn't change with the context file contents")
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/go-units"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// dockerHub is the registry of the images without a registry host
+const dockerHub = "docker.io"
+
+// pullProgressInterval is the interval the pull progress of an image is logged with
+const pullProgressInterval = 5 * time.Second
+
+// WithSkipPull makes the deployment use the local images only, the missing images fail the deployment before
+// any node is created
+func WithSkipPull() ClabOption {
+	return func(c *CLab) {
+		c.skipPull = true
+	}
+}
+
+// pullImages makes the node images available in the image stores of their runtimes before any node is created.
+// The missing images are pulled one by one with the registry mirror and credentials of the image-pull settings,
+// with the skip-pull option the missing images are reported instead
+func (c *CLab) pullImages(ctx context.Context, images map[string]string) error {
+	refs := make([]string, 0, len(images))
+	for image := range images {
+		refs = append(refs, image)
+	}
+	sort.Strings(refs)
+
+	var missing []string
+	for _, image := range refs {
+		exists, err := c.Runtimes[images[image]].ImageExists(ctx, image)
+		if err != nil {
+			return fmt.Errorf("failed to look up image %s: %v", image, err)
+		}
+		if !exists {
+			missing = append(missing, image)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	if c.skipPull {
+		return fmt.Errorf("images %s are not present locally, pull them or deploy without --skip-pull flag",
+			strings.Join(missing, ", "))
+	}
+
+	cfg := c.Config.Settings.GetImagePull()
+	dockerAuths := dockerConfigAuths(dockerConfigPath())
+	for i, image := range missing {
+		from := mirrorImage(image, cfg)
+		opts := &types.PullOptions{
+			Auth:     registryAuth(imageRegistry(from), cfg, dockerAuths),
+			Progress: pullProgress(image),
+		}
+		if from != image {
+			opts.Source = from
+		}
+
+		log.Infof("Pulling image %s (%d/%d)", from, i+1, len(missing))
+		start := time.Now()
+		if err := c.Runtimes[images[image]].PullImage(ctx, image, opts); err != nil {
+			return fmt.Errorf("failed to pull image %s: %v", from, err)
+		}
+		log.Infof("Pulled image %s in %s", image, time.Since(start).Round(time.Second))
+	}
+	return nil
+}
+
+// pullProgress returns the progress func logging the downloaded share of the image layers every pullProgressInterval
+func pullProgress(image string) func(current, total int64) {
+	var mu sync.Mutex
+	last := time.Now()
+	return func(current, total int64) {
+		mu.Lock()
+		defer mu.Unlock()
+		if total <= 0 || time.Since(last) < pullProgressInterval {
+			return
+		}
+		last = time.Now()
+		log.Infof("Pulling image %s: %d%% of %s", image, current*100/total, units.HumanSize(float64(total)))
+	}
+}
+
+// imageRegistry returns the registry host of the image reference, docker.io for the Docker Hub images
+func imageRegistry(image string) string {
+	i := strings.Index(image, "/")
+	if i < 0 {
+		return dockerHub
+	}
+	host := image[:i]
+	if !strings.ContainsAny(host, ".:") && host != "localhost" {
+		return dockerHub
+	}
+	if host == "index.docker.io" || host == "registry-1.docker.io" {
+		return dockerHub
+	}
+	return host
+}
+
+// mirrorImage returns the reference of the Docker Hub image in the registry mirror of the image pull settings,
+// the images of the other registries are pulled from their registries
+func mirrorImage(image string, cfg *types.ImagePullConfig) string {
+	if cfg == nil || cfg.Mirror == "" || imageRegistry(image) != dockerHub {
+		return image
+	}
+	canonical := utils.GetCanonicalImageName(image)
+	return strings.TrimSuffix(cfg.Mirror, "/") + "/" + strings.TrimPrefix(canonical, dockerHub+"/")
+}
+
+// registryAuth returns the credentials of the registry set in the image pull settings,
+// the credentials stored by docker login are used for the registries not set in the settings
+func registryAuth(registry string, cfg *types.ImagePullConfig, dockerAuths map[string]*types.RegistryAuth) *types.RegistryAuth {
+	if cfg != nil {
+		if a, ok := cfg.Registries[registry]; ok {
+			return a
+		}
+	}
+	return dockerAuths[registry]
+}
+
+// dockerConfigPath returns the path to the docker client config file holding the docker login credentials
+func dockerConfigPath() string {
+	if d := os.Getenv("DOCKER_CONFIG"); d != "" {
+		return filepath.Join(d, "config.json")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".docker", "config.json")
+}
+
+// dockerConfigAuths returns the registry credentials stored in the docker client config file by their registry host,
+// the credentials kept by the credential helpers are not supported
+func dockerConfigAuths(path string) map[string]*types.RegistryAuth {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var cfg struct {
+		Auths map[string]struct {
+			Auth string `json:"auth"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		log.Debugf("failed to parse docker config %s: %v", path, err)
+		return nil
+	}
+	res := map[string]*types.RegistryAuth{}
+	for server, a := range cfg.Auths {
+		creds, err := base64.StdEncoding.DecodeString(a.Auth)
+		if err != nil {
+			continue
+		}
+		userPass := strings.SplitN(string(creds), ":", 2)
+		if len(userPass) != 2 {
+			continue
+		}
+		// the servers are stored as hosts or URLs, e.g. https://index.docker.io/v1/
+		host := strings.TrimPrefix(strings.TrimPrefix(server, "https://"), "http://")
+		if i := strings.Index(host, "/"); i >= 0 {
+			host = host[:i]
+		}
+		res[imageRegistry(host+"/image")] = &types.RegistryAuth{Username: userPass[0], Password: userPass[1]}
+	}
+	return res
+}
@@ -0,0 +1,87 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestMirrorImage(t *testing.T) {
+	cfg := &types.ImagePullConfig{Mirror: "mirror.example.com:5000/"}
+	tests := map[string]struct {
+		image string
+		cfg   *types.ImagePullConfig
+		want  string
+	}{
+		"official":       {image: "alpine", cfg: cfg, want: "mirror.example.com:5000/library/alpine:latest"},
+		"user":           {image: "frrouting/frr:v7.5.1", cfg: cfg, want: "mirror.example.com:5000/frrouting/frr:v7.5.1"},
+		"explicit-hub":   {image: "docker.io/library/alpine:3", cfg: cfg, want: "mirror.example.com:5000/library/alpine:3"},
+		"other-registry": {image: "ghcr.io/nokia/srlinux:21.6.1", cfg: cfg, want: "ghcr.io/nokia/srlinux:21.6.1"},
+		"local-registry": {image: "localhost/vrnetlab/vr-sros:21.2.R1", cfg: cfg, want: "localhost/vrnetlab/vr-sros:21.2.R1"},
+		"no-mirror":      {image: "alpine", want: "alpine"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := mirrorImage(tc.image, tc.cfg); got != tc.want {
+				t.Errorf("mirrorImage() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDockerConfigAuths(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	// lab:secret and ci:token:with:colons
+	cfg := `{"auths": {
+		"https://index.docker.io/v1/": {"auth": "bGFiOnNlY3JldA=="},
+		"registry.example.com:5000": {"auth": "Y2k6dG9rZW46d2l0aDpjb2xvbnM="},
+		"ghcr.io": {}
+	}}`
+	if err := ioutil.WriteFile(path, []byte(cfg), 0600); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]*types.RegistryAuth{
+		"docker.io":                 {Username: "lab", Password: "secret"},
+		"registry.example.com:5000": {Username: "ci", Password: "token:with:colons"},
+	}
+	if d := cmp.Diff(want, dockerConfigAuths(path)); d != "" {
+		t.Errorf("dockerConfigAuths() mismatch (-want +got):\n%s", d)
+	}
+	if got := dockerConfigAuths(filepath.Join(t.TempDir(), "missing.json")); got != nil {
+		t.Errorf("dockerConfigAuths() of a missing file = %v, want nil", got)
+	}
+}
+
+func TestRegistryAuth(t *testing.T) {
+	cfg := &types.ImagePullConfig{Registries: map[string]*types.RegistryAuth{
+		"registry.example.com": {Username: "topo"},
+	}}
+	dockerAuths := map[string]*types.RegistryAuth{
+		"registry.example.com": {Username: "docker"},
+		"docker.io":            {Username: "hub"},
+	}
+	tests := map[string]struct {
+		registry string
+		cfg      *types.ImagePullConfig
+		want     *types.RegistryAuth
+	}{
+		"topology":     {registry: "registry.example.com", cfg: cfg, want: &types.RegistryAuth{Username: "topo"}},
+		"docker-login": {registry: "docker.io", cfg: cfg, want: &types.RegistryAuth{Username: "hub"}},
+		"no-settings":  {registry: "registry.example.com", want: &types.RegistryAuth{Username: "docker"}},
+		"anonymous":    {registry: "ghcr.io", cfg: cfg},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if d := cmp.Diff(tc.want, registryAuth(tc.registry, tc.cfg, dockerAuths)); d != "" {
+				t.Errorf("registryAuth() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// digestRe matches the images pinned by the sha256 digest
+var digestRe = regexp.MustCompile(`@sha256:[a-f0-9]{64}$`)
+
+// verifyImagesTrust checks the node images against the image-verification settings
+// before the images are pulled and the nodes are created.
+// The images with verified signatures are returned mapped to the references pinned by the verified digest,
+// so that the nodes run the verified image rather than whatever the tag points to in the local image store
+func (c *CLab) verifyImagesTrust(ctx context.Context, images map[string]string) (map[string]string, error) {
+	v := c.Config.Settings.GetImageVerification()
+	if v == nil {
+		return nil, nil
+	}
+	if v.RequireDigest {
+		nodeImages := make(map[string][]string)
+		for name, n := range c.Nodes {
+			for _, image := range n.GetImages() {
+				// the images built from the Dockerfiles are not pulled and have no digest to pin
+				if n.Config().Build != nil && image == n.Config().Image {
+					continue
+				}
+				nodeImages[name] = append(nodeImages[name], image)
+			}
+		}
+		if unpinned := unpinnedImages(nodeImages); len(unpinned) != 0 {
+			return nil, fmt.Errorf("images are required to be pinned by digest (image@sha256:<digest>), offending nodes: %s",
+				strings.Join(unpinned, ", "))
+		}
+	}
+	if v.CosignKey == "" {
+		return nil, nil
+	}
+	if _, err := exec.LookPath("cosign"); err != nil {
+		return nil, errors.New("cosign binary is required to verify image signatures, see https://docs.sigstore.dev/cosign/installation")
+	}
+	refs := make([]string, 0, len(images))
+	for image := range images {
+		refs = append(refs, image)
+	}
+	sort.Strings(refs)
+	pinned := make(map[string]string, len(refs))
+	for _, image := range refs {
+		log.Infof("Verifying signature of image %s", image)
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, "cosign", "verify", "--key", v.CosignKey, image)
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("signature verification of image %s failed: %v\n%s", image, err, stderr.Bytes())
+		}
+		digest, err := cosignDigest(out)
+		if err != nil {
+			return nil, fmt.Errorf("signature verification of image %s failed: %v", image, err)
+		}
+		pinned[image] = pinImage(image, digest)
+		log.Debugf("image %s is pinned to the verified digest: %s", image, pinned[image])
+	}
+	return pinned, nil
+}
+
+// pinVerifiedImages replaces the images of the nodes with the references pinned by the verified digests
+func (c *CLab) pinVerifiedImages(pinned map[string]string) {
+	for _, n := range c.Nodes {
+		cfg := n.Config()
+		for _, image := range []*string{&cfg.Image, &cfg.Kernel, &cfg.Sandbox} {
+			if p, ok := pinned[*image]; ok {
+				*image = p
+			}
+		}
+	}
+}
+
+// cosignDigest returns the manifest digest of the image from the json output of cosign verify.
+// Every verified signature carries the digest, the signatures of the image must agree on it
+func cosignDigest(out []byte) (string, error) {
+	var sigs []struct {
+		Critical struct {
+			Image struct {
+				Digest string `json:"docker-manifest-digest"`
+			} `json:"image"`
+		} `json:"critical"`
+	}
+	if err := json.Unmarshal(out, &sigs); err != nil {
+		return "", fmt.Errorf("failed to parse cosign output: %v", err)
+	}
+	var digest string
+	for _, s := range sigs {
+		d := s.Critical.Image.Digest
+		if !digestRe.MatchString("@" + d) {
+			return "", fmt.Errorf("cosign output has invalid image digest %q", d)
+		}
+		if digest != "" && d != digest {
+			return "", fmt.Errorf("signatures of the image have different digests %s and %s", digest, d)
+		}
+		digest = d
+	}
+	if digest == "" {
+		return "", errors.New("cosign output has no verified signatures")
+	}
+	return digest, nil
+}
+
+// pinImage returns the image reference pinned by the digest, the tag of the image is dropped.
+// The image already pinned by digest is returned as is, as cosign verifies the digest it is pinned by
+func pinImage(image, digest string) string {
+	if strings.Contains(image, "@") {
+		return image
+	}
+	// the tag follows the last colon after the last slash, the colon before it separates the registry port
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image + "@" + digest
+}
+
+// unpinnedImages returns the sorted "node: image" entries of the images not pinned by digest
+func unpinnedImages(nodeImages map[string][]string) []string {
+	var unpinned []string
+	for node, images := range nodeImages {
+		for _, image := range images {
+			if !digestRe.MatchString(image) {
+				unpinned = append(unpinned, fmt.Sprintf("%s: %s", node, image))
+			}
+		}
+	}
+	sort.Strings(unpinned)
+	return unpinned
+}
@@ -0,0 +1,181 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUnpinnedImages(t *testing.T) {
+	digest := "@sha256:" + "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	tests := map[string]struct {
+		nodeImages map[string][]string
+		want       []string
+	}{
+		"all_pinned": {
+			nodeImages: map[string][]string{
+				"srl1": {"ghcr.io/nokia/srlinux" + digest},
+				"srl2": {"ghcr.io/nokia/srlinux:21.6.4" + digest},
+			},
+		},
+		"tag_only": {
+			nodeImages: map[string][]string{
+				"srl1":   {"ghcr.io/nokia/srlinux:21.6.4"},
+				"srl2":   {"ghcr.io/nokia/srlinux" + digest},
+				"alpine": {"alpine"},
+			},
+			want: []string{"alpine: alpine", "srl1: ghcr.io/nokia/srlinux:21.6.4"},
+		},
+		"short_digest": {
+			nodeImages: map[string][]string{
+				"srl1": {"ghcr.io/nokia/srlinux@sha256:0123"},
+			},
+			want: []string{"srl1: ghcr.io/nokia/srlinux@sha256:0123"},
+		},
+		"kata_sandbox_and_kernel": {
+			nodeImages: map[string][]string{
+				"vm": {"alpine" + digest, "kernel:5.10"},
+			},
+			want: []string{"vm: kernel:5.10"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := unpinnedImages(tc.nodeImages)
+			if d := cmp.Diff(tc.want, got); d != "" {
+				t.Fatalf("unpinned images mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestCosignDigest(t *testing.T) {
+	d1 := "sha256:" + "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	d2 := "sha256:" + "fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210"
+	sig := func(d string) string {
+		return `{"critical":{"identity":{"docker-reference":"ghcr.io/nokia/srlinux"},"image":{"docker-manifest-digest":"` +
+			d + `"},"type":"cosign container image signature"},"optional":null}`
+	}
+	tests := map[string]struct {
+		out     string
+		want    string
+		wantErr bool
+	}{
+		"single":          {out: "[" + sig(d1) + "]", want: d1},
+		"same_digests":    {out: "[" + sig(d1) + "," + sig(d1) + "]", want: d1},
+		"different":       {out: "[" + sig(d1) + "," + sig(d2) + "]", wantErr: true},
+		"no_signatures":   {out: "[]", wantErr: true},
+		"invalid_digest":  {out: "[" + sig("sha256:0123") + "]", wantErr: true},
+		"not_json_output": {out: "Verification for ghcr.io/nokia/srlinux --", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := cosignDigest([]byte(tc.out))
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got digest %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPinImage(t *testing.T) {
+	digest := "sha256:" + "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	tests := map[string]struct {
+		image string
+		want  string
+	}{
+		"name":           {image: "alpine", want: "alpine@" + digest},
+		"tag":            {image: "ghcr.io/nokia/srlinux:21.6.4", want: "ghcr.io/nokia/srlinux@" + digest},
+		"registry_port":  {image: "registry:5000/srlinux", want: "registry:5000/srlinux@" + digest},
+		"port_and_tag":   {image: "registry:5000/srlinux:21.6.4", want: "registry:5000/srlinux@" + digest},
+		"already_pinned": {image: "ghcr.io/nokia/srlinux@" + digest, want: "ghcr.io/nokia/srlinux@" + digest},
+		"tag_and_pinned": {image: "ghcr.io/nokia/srlinux:21.6.4@" + digest, want: "ghcr.io/nokia/srlinux:21.6.4@" + digest},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := pinImage(tc.image, digest); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestVerifyImagesTrustRequireDigest(t *testing.T) {
+	digest := "@sha256:" + "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	tests := map[string]struct {
+		nodes   string
+		wantErr string
+	}{
+		"pinned_and_built": {
+			nodes: `
+    host1:
+      kind: linux
+      image: alpine` + digest + `
+    helper:
+      kind: linux
+      build:
+        context: helper
+`,
+		},
+		"unpinned": {
+			nodes: `
+    host1:
+      kind: linux
+      image: alpine:3
+    helper:
+      kind: linux
+      build:
+        context: helper
+`,
+			wantErr: "images are required to be pinned by digest (image@sha256:<digest>), offending nodes: host1: alpine:3",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.MkdirAll(filepath.Join(dir, "helper"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, "helper", "Dockerfile"), []byte("FROM alpine\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			topo := "name: verify\nsettings:\n  image-verification:\n    require-digest: true\ntopology:\n  nodes:" + tc.nodes
+			topoFile := filepath.Join(dir, "verify.clab.yml")
+			if err := os.WriteFile(topoFile, []byte(topo), 0644); err != nil {
+				t.Fatal(err)
+			}
+			c, err := NewContainerLab(WithTopoFile(topoFile))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = c.verifyImagesTrust(context.Background(), nil)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.wantErr {
+				t.Fatalf("got error %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,356 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+	"github.com/vishvananda/netlink"
+)
+
+// importFile is the file of the lab directory listing the containers imported into the lab
+const importFile = "imported.json"
+
+// ImportedContainer is a running container imported into the lab as a lab node
+type ImportedContainer struct {
+	// name of the lab node
+	Node string `json:"node"`
+	// name and id of the container
+	Container string `json:"container"`
+	ID        string `json:"id"`
+}
+
+// importPath returns the path to the file listing the containers imported into the lab
+func (c *CLab) importPath() string {
+	return filepath.Join(c.Dir.Lab, importFile)
+}
+
+// verifyImportedNodes checks that the topology has no imported nodes,
+// the imported nodes are added to the lab with the import command instead of being deployed
+func (c *CLab) verifyImportedNodes() error {
+	for _, name := range c.nodeNames() {
+		if c.Nodes[name].Config().Import != nil {
+			return fmt.Errorf("node %q is imported from a running container, use the import command to add it to the lab", name)
+		}
+	}
+	return nil
+}
+
+// verifyImport checks that the topology can be imported: all the container nodes are imported
+// and each of them matches the container by its name or labels
+func (c *CLab) verifyImport() error {
+	imported := 0
+	for _, name := range c.nodeNames() {
+		cfg := c.Nodes[name].Config()
+		if _, ok := noMgmtKinds[cfg.Kind]; ok {
+			continue
+		}
+		if cfg.Import == nil {
+			return fmt.Errorf("node %q is not imported, only the containers set with the import node setting can be added to the lab", name)
+		}
+		if cfg.Import.Container == "" && len(cfg.Import.Labels) == 0 {
+			return fmt.Errorf("node %q: import must set the container name or labels", name)
+		}
+		imported++
+	}
+	if imported == 0 {
+		return fmt.Errorf("topology %s has no imported nodes", c.TopoFile.fullName)
+	}
+	return nil
+}
+
+// nodeNames returns the names of the lab nodes in alphabetical order
+func (c *CLab) nodeNames() []string {
+	names := make([]string, 0, len(c.Nodes))
+	for name := range c.Nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// selectImportContainer returns the running container the node is imported from out of the listed containers.
+// The container must match the name and all the labels of the import config and must not be a node of another lab
+func selectImportContainer(cfg *types.NodeConfig, ctrs []types.GenericContainer) (*types.GenericContainer, error) {
+	var res []*types.GenericContainer
+	for i := range ctrs {
+		ctr := &ctrs[i]
+		if cfg.Import.Container != "" && containerName(ctr) != cfg.Import.Container {
+			continue
+		}
+		matched := true
+		for k, v := range cfg.Import.Labels {
+			if lv, ok := ctr.Labels[k]; !ok || lv != v {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			res = append(res, ctr)
+		}
+	}
+	switch len(res) {
+	case 0:
+		return nil, fmt.Errorf("node %q: no container matches the import settings", cfg.ShortName)
+	case 1:
+	default:
+		names := make([]string, 0, len(res))
+		for _, ctr := range res {
+			names = append(names, containerName(ctr))
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("node %q: import settings match %d containers: %s", cfg.ShortName, len(res), strings.Join(names, ", "))
+	}
+	ctr := res[0]
+	if ctr.State != "running" {
+		return nil, fmt.Errorf("node %q: container %s is not running", cfg.ShortName, containerName(ctr))
+	}
+	if lab := ctr.Labels[ContainerlabLabel]; lab != "" {
+		return nil, fmt.Errorf("node %q: container %s is a node of lab %s", cfg.ShortName, containerName(ctr), lab)
+	}
+	return ctr, nil
+}
+
+// containerName returns the name of the container without the leading slash
+func containerName(ctr *types.GenericContainer) string {
+	if len(ctr.Names) == 0 {
+		return ctr.ShortID
+	}
+	return strings.TrimPrefix(ctr.Names[0], "/")
+}
+
+// findImportContainer looks up the running container the node is imported from
+func (c *CLab) findImportContainer(ctx context.Context, cfg *types.NodeConfig) (*types.GenericContainer, error) {
+	var filters []*types.GenericFilter
+	if cfg.Import.Container != "" {
+		filters = append(filters, &types.GenericFilter{FilterType: "name", Match: cfg.Import.Container})
+	}
+	for k, v := range cfg.Import.Labels {
+		filters = append(filters, &types.GenericFilter{FilterType: "label", Field: k, Operator: "=", Match: v})
+	}
+	ctrs, err := c.Nodes[cfg.ShortName].GetRuntime().ListContainers(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("node %q: could not list containers: %v", cfg.ShortName, err)
+	}
+	return selectImportContainer(cfg, ctrs)
+}
+
+// Import adopts the running containers of the imported nodes into the lab. The containers are connected
+// to the management network and the links of the topology are wired into their network namespaces.
+// The imported containers are recorded in the lab directory, so that inspect and destroy treat them as the lab nodes
+func (c *CLab) Import(ctx context.Context, workers uint) ([]*ImportedContainer, error) {
+	for _, check := range []func() error{
+		c.verifyImport, c.verifyBridgesExist, c.verifyLinkVLANs, c.verifyLinkNetem,
+		c.verifyLinkMTU, c.verifyLinkTypes, c.verifyLinks, c.verifyMgmtAddresses,
+	} {
+		if err := check(); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := os.Stat(c.importPath()); err == nil {
+		return nil, fmt.Errorf("lab %s has imported containers already, destroy the lab before importing again", c.Config.Name)
+	}
+
+	imported := make([]*ImportedContainer, 0, len(c.Nodes))
+	ctrs := map[string]*types.GenericContainer{}
+	for _, name := range c.nodeNames() {
+		cfg := c.Nodes[name].Config()
+		if cfg.Import == nil {
+			continue
+		}
+		ctr, err := c.findImportContainer(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		ctrs[name] = ctr
+		imported = append(imported, &ImportedContainer{Node: name, Container: containerName(ctr), ID: ctr.ID})
+	}
+
+	log.Info("Creating lab directory: ", c.Dir.Lab)
+	utils.CreateDirectory(c.Dir.Lab, 0755)
+	if err := c.GlobalRuntime().CreateNet(ctx); err != nil {
+		return nil, err
+	}
+
+	for _, ic := range imported {
+		n := c.Nodes[ic.Node]
+		cfg := n.Config()
+		log.Infof("Importing container %s as node %s", ic.Container, ic.Node)
+		if err := n.GetRuntime().ConnectMgmtNet(ctx, ic.ID, cfg); err != nil {
+			return nil, fmt.Errorf("node %q: failed to connect container %s to the management network: %v", ic.Node, ic.Container, err)
+		}
+		// the node is known under the name of its container
+		cfg.LongName = ic.Container
+		cfg.ContainerID = ctrs[ic.Node].ShortID
+		nspath, err := n.GetRuntime().GetNSPath(ctx, ic.Container)
+		if err != nil {
+			return nil, fmt.Errorf("node %q: failed to get netns of container %s: %v", ic.Node, ic.Container, err)
+		}
+		cfg.NSPath = nspath
+		if err := utils.LinkContainerNS(nspath, ic.Container); err != nil {
+			return nil, err
+		}
+		cfg.DeploymentStatus = "created"
+	}
+	for _, name := range c.nodeNames() {
+		n := c.Nodes[name]
+		if n.Config().Import != nil {
+			continue
+		}
+		if err := n.Deploy(ctx); err != nil {
+			return nil, fmt.Errorf("failed deploy phase for node %q: %v", name, err)
+		}
+		n.Config().DeploymentStatus = "created"
+	}
+
+	if err := c.writeImported(imported); err != nil {
+		return nil, err
+	}
+	if workers == 0 || workers > uint(len(c.Links)) {
+		workers = uint(len(c.Links))
+	}
+	c.CreateLinks(ctx, workers, false)
+	return imported, nil
+}
+
+// writeImported writes the list of the imported containers to the lab directory
+func (c *CLab) writeImported(imported []*ImportedContainer) error {
+	b, err := json.MarshalIndent(imported, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.importPath(), b, 0644)
+}
+
+// readImported reads the list of the imported containers from the lab directory,
+// it returns no containers when the lab has none imported
+func (c *CLab) readImported() ([]*ImportedContainer, error) {
+	b, err := ioutil.ReadFile(c.importPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var res []*ImportedContainer
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, fmt.Errorf("failed to read imported containers from %s: %v", c.importPath(), err)
+	}
+	return res, nil
+}
+
+// ListImportedContainers lists the running containers imported into the lab.
+// The containers don't carry the lab labels, so the labels of the lab nodes are set to the returned containers
+func (c *CLab) ListImportedContainers(ctx context.Context) ([]types.GenericContainer, error) {
+	imported, err := c.readImported()
+	if err != nil || len(imported) == 0 {
+		return nil, err
+	}
+	var res []types.GenericContainer
+	for _, ic := range imported {
+		filters := []*types.GenericFilter{{FilterType: "name", Match: ic.Container}}
+		ctrs, err := c.GlobalRuntime().ListContainers(ctx, filters)
+		if err != nil {
+			return nil, fmt.Errorf("could not list containers: %v", err)
+		}
+		for _, ctr := range ctrs {
+			if ctr.ID != ic.ID {
+				continue
+			}
+			kind := ""
+			if n, ok := c.Nodes[ic.Node]; ok {
+				kind = n.Config().Kind
+			}
+			ctr.Labels = utils.MergeStringMaps(ctr.Labels, map[string]string{
+				ContainerlabLabel: c.Config.Name,
+				NodeNameLabel:     ic.Node,
+				NodeKindLabel:     kind,
+				LabDirLabel:       utils.HostPath(c.Dir.Lab),
+				TopoFileLabel:     utils.HostPath(c.TopoFile.path),
+			})
+			res = append(res, ctr)
+		}
+	}
+	return res, nil
+}
+
+// ReleaseImported releases the containers imported into the lab: the interfaces of their links are removed
+// and the containers are disconnected from the management network. The imported nodes are removed
+// from the lab nodes, so that the containers are kept running when the lab nodes are deleted
+func (c *CLab) ReleaseImported(ctx context.Context) error {
+	imported, err := c.readImported()
+	if err != nil || len(imported) == 0 {
+		return err
+	}
+	for _, ic := range imported {
+		log.Infof("Releasing imported container %s of node %s", ic.Container, ic.Node)
+		if nspath, err := c.GlobalRuntime().GetNSPath(ctx, ic.Container); err != nil {
+			log.Warnf("failed to get netns of container %s: %v", ic.Container, err)
+		} else if err := deleteNodeLinks(nspath, c.nodeEndpoints(ic.Node)); err != nil {
+			log.Warnf("failed to remove links of container %s: %v", ic.Container, err)
+		}
+		if err := c.GlobalRuntime().DisconnectMgmtNet(ctx, ic.ID); err != nil {
+			log.Warnf("failed to disconnect container %s from the management network: %v", ic.Container, err)
+		}
+		if err := utils.DeleteNetnsSymlink(ic.Container); err != nil {
+			log.Warnf("failed to delete netns symlink of container %s: %v", ic.Container, err)
+		}
+		delete(c.Nodes, ic.Node)
+	}
+	return os.Remove(c.importPath())
+}
+
+// nodeEndpoints returns the names of the link interfaces of the node
+func (c *CLab) nodeEndpoints(node string) []string {
+	var res []string
+	for _, l := range c.Links {
+		for _, ep := range []*types.Endpoint{l.A, l.B} {
+			if ep.Node.ShortName == node {
+				res = append(res, ep.EndpointName)
+			}
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// deleteNodeLinks removes the interfaces from the network namespace, removing a veth removes its peer as well
+func deleteNodeLinks(nspath string, ifaces []string) error {
+	if len(ifaces) == 0 {
+		return nil
+	}
+	netns, err := ns.GetNS(nspath)
+	if err != nil {
+		return err
+	}
+	defer netns.Close()
+	return netns.Do(func(_ ns.NetNS) error {
+		for _, name := range ifaces {
+			link, err := netlink.LinkByName(name)
+			if err != nil {
+				if _, ok := err.(netlink.LinkNotFoundError); ok {
+					continue
+				}
+				return err
+			}
+			if err := netlink.LinkDel(link); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,87 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestVerifyImport(t *testing.T) {
+	tests := map[string]struct {
+		topo    string
+		wantErr bool
+	}{
+		"imported-nodes-and-bridge": {topo: "test_data/topo30.yml"},
+		"deployed-nodes":            {topo: "test_data/topo14.yml", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewContainerLab(WithTopoFile(tc.topo))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := c.verifyImport(); (err != nil) != tc.wantErr {
+				t.Errorf("verifyImport() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			// the topology with the imported nodes can't be deployed
+			if err := c.verifyImportedNodes(); (err == nil) != tc.wantErr {
+				t.Errorf("verifyImportedNodes() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestSelectImportContainer(t *testing.T) {
+	ctrs := []types.GenericContainer{
+		{Names: []string{"/trex1"}, ID: "a1", State: "running", Labels: map[string]string{"app": "trex", "instance": "1"}},
+		{Names: []string{"/trex10"}, ID: "a2", State: "running", Labels: map[string]string{"app": "trex", "instance": "2"}},
+		{Names: []string{"/trex3"}, ID: "a3", State: "exited", Labels: map[string]string{"app": "trex", "instance": "3"}},
+		{Names: []string{"/lab1-n1"}, ID: "a4", State: "running", Labels: map[string]string{"containerlab": "lab1"}},
+	}
+	tests := map[string]struct {
+		imp     *types.ImportConfig
+		wantID  string
+		wantErr bool
+	}{
+		"exact-name": {
+			imp:    &types.ImportConfig{Container: "trex1"},
+			wantID: "a1",
+		},
+		"labels": {
+			imp:    &types.ImportConfig{Labels: map[string]string{"app": "trex", "instance": "2"}},
+			wantID: "a2",
+		},
+		"name-and-labels": {
+			imp:     &types.ImportConfig{Container: "trex1", Labels: map[string]string{"instance": "2"}},
+			wantErr: true,
+		},
+		"many-matches": {
+			imp:     &types.ImportConfig{Labels: map[string]string{"app": "trex"}},
+			wantErr: true,
+		},
+		"not-running": {
+			imp:     &types.ImportConfig{Container: "trex3"},
+			wantErr: true,
+		},
+		"lab-node": {
+			imp:     &types.ImportConfig{Container: "lab1-n1"},
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &types.NodeConfig{ShortName: "tgen", Import: tc.imp}
+			ctr, err := selectImportContainer(cfg, ctrs)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("selectImportContainer() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if err == nil && ctr.ID != tc.wantID {
+				t.Errorf("selectImportContainer() = %s, want %s", ctr.ID, tc.wantID)
+			}
+		})
+	}
+}
@@ -9,86 +9,151 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"text/template"
 
+	"github.com/srl-labs/containerlab/nodes"
 	"github.com/srl-labs/containerlab/types"
 )
 
+// ansibleInventoryFile is the name of the ansible inventory file in the lab directory
+const ansibleInventoryFile = "ansible-inventory.yml"
+
 // GenerateInventories generate various inventory files and writes it to a lab location
 func (c *CLab) GenerateInventories() error {
-	ansibleInvFPath := filepath.Join(c.Dir.Lab, "ansible-inventory.yml")
+	ansibleInvFPath := filepath.Join(c.Dir.Lab, ansibleInventoryFile)
 	f, err := os.Create(ansibleInvFPath)
 	if err != nil {
 		return err
 	}
+	defer f.Close()
 	return c.generateAnsibleInventory(f)
 }
 
-// generateAnsibleInventory generates and writes ansible inventory file to w
+// generateAnsibleInventory generates and writes ansible inventory file of the lab nodes to w
 func (c *CLab) generateAnsibleInventory(w io.Writer) error {
+	cfgs := make([]*types.NodeConfig, 0, len(c.Nodes))
+	for _, n := range c.Nodes {
+		cfgs = append(cfgs, n.Config())
+	}
+	return writeAnsibleInventory(w, cfgs)
+}
+
+// WriteAnsibleInventory writes the ansible inventory of the lab containers to w.
+// The containers of the topology nodes get the credentials of the nodes,
+// the other containers, e.g. of the labs inspected by name, get the default credentials of their kind
+func (c *CLab) WriteAnsibleInventory(w io.Writer, containers []types.GenericContainer) error {
+	cfgs := make([]*types.NodeConfig, 0, len(containers))
+	for _, cont := range containers {
+		if len(cont.Names) == 0 {
+			continue
+		}
+		name := cont.Labels[NodeNameLabel]
+		cfg := &types.NodeConfig{ShortName: name, Kind: cont.Labels[NodeKindLabel], Labels: cont.Labels}
+		if n, ok := c.Nodes[name]; ok && cont.Labels[ContainerlabLabel] == c.Config.Name {
+			nodeCfg := *n.Config()
+			cfg = &nodeCfg
+		}
+		cfg.LongName = strings.TrimLeft(cont.Names[0], "/")
+		if cont.NetworkSettings != nil && cont.NetworkSettings.Set {
+			cfg.MgmtIPv4Address, cfg.MgmtIPv6Address = cont.NetworkSettings.IPv4addr, cont.NetworkSettings.IPv6addr
+		}
+		cfgs = append(cfgs, cfg)
+	}
+	return writeAnsibleInventory(w, cfgs)
+}
+
+// ansibleHost is a host of the ansible inventory
+type ansibleHost struct {
+	Name, Address  string
+	User, Password string
+}
 
+// ansibleKind is the group of the ansible inventory with the hosts of a kind
+// and the connection variables of the kind
+type ansibleKind struct {
+	NetworkOS, Connection string
+	Hosts                 []*ansibleHost
+}
+
+// writeAnsibleInventory writes the ansible inventory of the nodes to w.
+// The nodes are grouped by their kind and by the ansible-group label,
+// the nodes without the management address, e.g. bridges, are skipped
+func writeAnsibleInventory(w io.Writer, cfgs []*types.NodeConfig) error {
 	invT :=
 		`all:
   children:
-{{- range $kind, $nodes := .Nodes}}
+{{- range $kind, $group := .Kinds}}
     {{$kind}}:
+{{- if $group.NetworkOS}}
+      vars:
+        ansible_network_os: {{$group.NetworkOS}}
+        ansible_connection: {{$group.Connection}}
+{{- end}}
       hosts:
-{{- range $nodes}}
-        {{.LongName}}:
-          ansible_host: {{.MgmtIPv4Address}}
+{{- range $group.Hosts}}
+        {{.Name}}:
+          ansible_host: {{.Address}}
+{{- if .User}}
+          ansible_user: {{printf "%q" .User}}
+          ansible_password: {{printf "%q" .Password}}
+{{- end}}
 {{- end}}
 {{- end}}
-{{- range $name, $nodes := .Groups}}
+{{- range $name, $hosts := .Groups}}
     {{$name}}:
       hosts:
-      {{- range $nodes}}
-        {{.LongName}}:
-          ansible_host: {{.MgmtIPv4Address}}
+      {{- range $hosts}}
+        {{.Name}}:
+          ansible_host: {{.Address}}
       {{- end}}
 {{- end}}
 `
 
 	type inv struct {
 		// clab nodes aggregated by their kind
-		Nodes map[string][]*types.NodeConfig
+		Kinds map[string]*ansibleKind
 		// clab nodes aggregated by user-defined groups
-		Groups map[string][]*types.NodeConfig
+		Groups map[string][]*ansibleHost
 	}
 
 	i := inv{
-		Nodes:  make(map[string][]*types.NodeConfig),
-		Groups: make(map[string][]*types.NodeConfig),
-	}
-
-	for _, n := range c.Nodes {
-		i.Nodes[n.Config().Kind] = append(i.Nodes[n.Config().Kind], n.Config())
-		if n.Config().Labels["ansible-group"] != "" {
-			i.Groups[n.Config().Labels["ansible-group"]] = append(i.Groups[n.Config().Labels["ansible-group"]], n.Config())
-		}
+		Kinds:  make(map[string]*ansibleKind),
+		Groups: make(map[string][]*ansibleHost),
 	}
 
 	// sort nodes by name as they are not sorted originally
-	for _, nodes := range i.Nodes {
-		sort.Slice(nodes, func(i, j int) bool {
-			return nodes[i].ShortName < nodes[j].ShortName
-		})
-	}
-
-	// sort nodes-per-group by name as they are not sorted originally
-	for _, nodes := range i.Groups {
-		sort.Slice(nodes, func(i, j int) bool {
-			return nodes[i].ShortName < nodes[j].ShortName
-		})
+	sort.Slice(cfgs, func(i, j int) bool {
+		return cfgs[i].LongName < cfgs[j].LongName
+	})
+	for _, cfg := range cfgs {
+		if _, ok := noMgmtKinds[cfg.Kind]; ok {
+			continue
+		}
+		h := &ansibleHost{Name: cfg.LongName, Address: cfg.MgmtIPv4Address}
+		if h.Address == "" {
+			h.Address = cfg.MgmtIPv6Address
+		}
+		if creds := nodes.SaveCredentials(cfg); creds.Username != "" {
+			h.User, h.Password = creds.Username, creds.Password
+		}
+		k, ok := i.Kinds[cfg.Kind]
+		if !ok {
+			k = &ansibleKind{}
+			if conn, ok := nodes.AnsibleConnections[cfg.Kind]; ok {
+				k.NetworkOS, k.Connection = conn[0], conn[1]
+			}
+			i.Kinds[cfg.Kind] = k
+		}
+		k.Hosts = append(k.Hosts, h)
+		if g := cfg.Labels["ansible-group"]; g != "" {
+			i.Groups[g] = append(i.Groups[g], h)
+		}
 	}
 
 	t, err := template.New("ansible").Parse(invT)
 	if err != nil {
 		return err
 	}
-	err = t.Execute(w, i)
-	if err != nil {
-		return err
-	}
-	return err
-
+	return t.Execute(w, i)
 }
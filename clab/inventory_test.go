@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
 )
 
 func TestGenerateAnsibleInventory(t *testing.T) {
@@ -21,11 +22,18 @@ func TestGenerateAnsibleInventory(t *testing.T) {
 			want: `all:
   children:
     srl:
+      vars:
+        ansible_network_os: nokia.srlinux.srlinux
+        ansible_connection: ansible.netcommon.httpapi
       hosts:
         clab-topo1-node1:
           ansible_host: 172.100.100.11
+          ansible_user: "admin"
+          ansible_password: "admin"
         clab-topo1-node2:
           ansible_host: 172.100.100.12
+          ansible_user: "admin"
+          ansible_password: "admin"
 `,
 		},
 		"case2": {
@@ -33,13 +41,22 @@ func TestGenerateAnsibleInventory(t *testing.T) {
 			want: `all:
   children:
     srl:
+      vars:
+        ansible_network_os: nokia.srlinux.srlinux
+        ansible_connection: ansible.netcommon.httpapi
       hosts:
         clab-topo8_ansible_groups-node1:
           ansible_host: 172.100.100.11
+          ansible_user: "admin"
+          ansible_password: "admin"
         clab-topo8_ansible_groups-node2:
           ansible_host: 172.100.100.12
+          ansible_user: "admin"
+          ansible_password: "admin"
         clab-topo8_ansible_groups-node3:
           ansible_host: 172.100.100.13
+          ansible_user: "admin"
+          ansible_password: "admin"
     extra_group:
       hosts:
         clab-topo8_ansible_groups-node2:
@@ -76,3 +93,64 @@ func TestGenerateAnsibleInventory(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteAnsibleInventory(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo1.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	containers := []types.GenericContainer{
+		{
+			Names:           []string{"/clab-topo1-node1"},
+			Labels:          map[string]string{ContainerlabLabel: "topo1", NodeNameLabel: "node1", NodeKindLabel: "srl"},
+			NetworkSettings: &types.GenericMgmtIPs{Set: true, IPv4addr: "172.20.20.2"},
+		},
+		// a container of another lab inspected with --all
+		{
+			Names:           []string{"/clab-other-vmx1"},
+			Labels:          map[string]string{ContainerlabLabel: "other", NodeNameLabel: "vmx1", NodeKindLabel: "vr-vmx", "ansible-group": "pe"},
+			NetworkSettings: &types.GenericMgmtIPs{Set: true, IPv6addr: "2001:172:20:20::3"},
+		},
+		{
+			Names:           []string{"/clab-other-client"},
+			Labels:          map[string]string{ContainerlabLabel: "other", NodeNameLabel: "client", NodeKindLabel: "linux"},
+			NetworkSettings: &types.GenericMgmtIPs{Set: true, IPv4addr: "172.20.20.4"},
+		},
+	}
+	want := `all:
+  children:
+    linux:
+      hosts:
+        clab-other-client:
+          ansible_host: 172.20.20.4
+    srl:
+      vars:
+        ansible_network_os: nokia.srlinux.srlinux
+        ansible_connection: ansible.netcommon.httpapi
+      hosts:
+        clab-topo1-node1:
+          ansible_host: 172.20.20.2
+          ansible_user: "admin"
+          ansible_password: "admin"
+    vr-vmx:
+      vars:
+        ansible_network_os: junipernetworks.junos.junos
+        ansible_connection: ansible.netcommon.netconf
+      hosts:
+        clab-other-vmx1:
+          ansible_host: 2001:172:20:20::3
+          ansible_user: "admin"
+          ansible_password: "admin@123"
+    pe:
+      hosts:
+        clab-other-vmx1:
+          ansible_host: 2001:172:20:20::3
+`
+	var s strings.Builder
+	if err := c.WriteAnsibleInventory(&s, containers); err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(want, s.String()); d != "" {
+		t.Errorf("inventory mismatch (-want +got):\n%s", d)
+	}
+}
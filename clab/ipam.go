@@ -0,0 +1,140 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/ipam"
+	"github.com/srl-labs/containerlab/nodes"
+)
+
+// kinds that are not attached to the management network
+var noMgmtKinds = map[string]struct{}{
+	"bridge":     {},
+	"ovs-bridge": {},
+	"host":       {},
+}
+
+// newIPAM returns the IPAM configured for the lab management network
+func (c *CLab) newIPAM() (ipam.IPAM, error) {
+	cfg := c.Config.Mgmt.IPAM
+	if cfg != nil && cfg.File != "" {
+		p, err := c.resolveTopoPath(cfg.File)
+		if err != nil {
+			return nil, err
+		}
+		cfg.File = p
+	}
+	return ipam.New(cfg, c.Config.Mgmt)
+}
+
+// managedByIPAM returns true if the node's management addresses are to be allocated by IPAM
+func managedByIPAM(n nodes.Node) bool {
+	cfg := n.Config()
+	if _, ok := noMgmtKinds[cfg.Kind]; ok {
+		return false
+	}
+	// nodes sharing netns with the host or other containers are not attached to the management network
+	if cfg.NetworkMode != "" {
+		return false
+	}
+	// addresses set in the topology take precedence over IPAM
+	return cfg.MgmtIPv4Address == "" && cfg.MgmtIPv6Address == ""
+}
+
+// AllocateMgmtAddresses sets the management addresses of the nodes using the configured IPAM
+func (c *CLab) AllocateMgmtAddresses(ctx context.Context) error {
+	i, err := c.newIPAM()
+	if err != nil {
+		return err
+	}
+	for _, n := range c.Nodes {
+		if !managedByIPAM(n) {
+			continue
+		}
+		cfg := n.Config()
+		v4, v6, err := i.Allocate(ctx, c.Config.Name, cfg)
+		if err != nil {
+			return fmt.Errorf("failed to allocate management addresses for node %q: %v", cfg.ShortName, err)
+		}
+		if v4 != "" || v6 != "" {
+			log.Debugf("node %q was allocated management addresses: IPv4=%q IPv6=%q", cfg.ShortName, v4, v6)
+		}
+		cfg.MgmtIPv4Address = v4
+		cfg.MgmtIPv6Address = v6
+	}
+	return nil
+}
+
+// ReleaseMgmtAddresses releases the management addresses allocated by the configured IPAM
+func (c *CLab) ReleaseMgmtAddresses(ctx context.Context) error {
+	i, err := c.newIPAM()
+	if err != nil {
+		return err
+	}
+	for _, n := range c.Nodes {
+		if !managedByIPAM(n) {
+			continue
+		}
+		if err := i.Release(ctx, c.Config.Name, n.Config()); err != nil {
+			log.Warnf("failed to release management addresses of node %q: %v", n.Config().ShortName, err)
+		}
+	}
+	return nil
+}
+
+// verifyMgmtAddresses checks that the static management addresses of the nodes are valid addresses
+// of the management network subnets and are not assigned to several nodes.
+// The default subnets of a custom network are not checked, as the network might exist with other subnets
+func (c *CLab) verifyMgmtAddresses() error {
+	mgmt := c.Config.Mgmt
+	defaultSubnets := mgmt.Network != dockerNetName && mgmt.IPv4Subnet == dockerNetIPv4Addr && mgmt.IPv6Subnet == dockerNetIPv6Addr
+	var subnets [2]*net.IPNet
+	for i, s := range []string{mgmt.IPv4Subnet, mgmt.IPv6Subnet} {
+		if s == "" || defaultSubnets {
+			continue
+		}
+		if _, n, err := net.ParseCIDR(s); err == nil {
+			subnets[i] = n
+		}
+	}
+	names := make([]string, 0, len(c.Nodes))
+	for name := range c.Nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	owners := map[string]string{}
+	for _, name := range names {
+		cfg := c.Nodes[name].Config()
+		for i, a := range []struct{ family, addr string }{
+			{"ipv4", cfg.MgmtIPv4Address},
+			{"ipv6", cfg.MgmtIPv6Address},
+		} {
+			if a.addr == "" {
+				continue
+			}
+			ip := net.ParseIP(a.addr)
+			if ip == nil || (ip.To4() != nil) != (a.family == "ipv4") {
+				return fmt.Errorf("node %q: mgmt_%s %q is not a valid %s address", name, a.family, a.addr, a.family)
+			}
+			if cfg.NetworkMode != "" {
+				return fmt.Errorf("node %q: mgmt_%s can't be set with the network-mode %q", name, a.family, cfg.NetworkMode)
+			}
+			if subnets[i] != nil && !subnets[i].Contains(ip) {
+				return fmt.Errorf("node %q: mgmt_%s %s is outside of the management network subnet %s", name, a.family, a.addr, subnets[i])
+			}
+			if owner, ok := owners[ip.String()]; ok {
+				return fmt.Errorf("nodes %q and %q have the same management address %s", owner, name, a.addr)
+			}
+			owners[ip.String()] = name
+		}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+)
+
+const (
+	// JumpHostName is the name of the jump host node
+	JumpHostName         = "jumphost"
+	defaultJumpHostImage = "linuxserver/openssh-server:latest"
+	defaultJumpHostUser  = "clab"
+	defaultJumpHostPort  = 2222
+	// port the ssh server of the jump host image listens on
+	jumpHostSSHPort = 2222
+)
+
+// addJumpHost adds the jump host node to the topology if the jump host is enabled
+func (c *CLab) addJumpHost() error {
+	jh := c.Config.JumpHost
+	if jh == nil {
+		return nil
+	}
+	if _, ok := c.Config.Topology.Nodes[JumpHostName]; ok {
+		return fmt.Errorf("node name %q is reserved for the jump host", JumpHostName)
+	}
+	if jh.Image == "" {
+		jh.Image = defaultJumpHostImage
+	}
+	if jh.User == "" {
+		jh.User = defaultJumpHostUser
+	}
+	if jh.Port == 0 {
+		jh.Port = defaultJumpHostPort
+	}
+	if c.Config.Topology.Nodes == nil {
+		c.Config.Topology.Nodes = map[string]*types.NodeDefinition{}
+	}
+	c.Config.Topology.Nodes[JumpHostName] = &types.NodeDefinition{
+		Kind:  nodes.NodeKindLinux,
+		Image: jh.Image,
+		Ports: []string{fmt.Sprintf("%d:%d/tcp", jh.Port, jumpHostSSHPort)},
+		Env: map[string]string{
+			"USER_NAME":       jh.User,
+			"PASSWORD_ACCESS": "false",
+			"SUDO_ACCESS":     "false",
+		},
+	}
+	return nil
+}
+
+// ProvisionJumpHost provisions the jump host with the public keys of the users
+func (c *CLab) ProvisionJumpHost() error {
+	jh := c.Config.JumpHost
+	if jh == nil {
+		return nil
+	}
+	keys, err := jumpHostKeys(jh.Keys)
+	if err != nil {
+		return err
+	}
+	c.Nodes[JumpHostName].Config().Env["PUBLIC_KEY"] = keys
+	return nil
+}
+
+// jumpHostKeys returns the content of the public key files,
+// ~/.ssh/*.pub files are used when no files are set
+func jumpHostKeys(files []string) (string, error) {
+	if len(files) == 0 {
+		p, err := homedir.Expand("~/.ssh/*.pub")
+		if err != nil {
+			return "", err
+		}
+		if files, err = filepath.Glob(p); err != nil {
+			return "", err
+		}
+		if len(files) == 0 {
+			return "", fmt.Errorf("no public keys found in ~/.ssh, set the jump host keys")
+		}
+	}
+	keys := make([]string, 0, len(files))
+	for _, f := range files {
+		p, err := resolvePath(f)
+		if err != nil {
+			return "", err
+		}
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			return "", fmt.Errorf("failed to read jump host key: %v", err)
+		}
+		keys = append(keys, strings.TrimSpace(string(b)))
+	}
+	return strings.Join(keys, "\n"), nil
+}
+
+// JumpHostSSHConfig returns the ssh_config snippet with the jump host entry
+// and the entries of the lab nodes reachable through the jump host with ProxyJump.
+// host is the address of the container host the jump host port is published on
+func (c *CLab) JumpHostSSHConfig(host string) string {
+	jh := c.Config.JumpHost
+	if jh == nil {
+		return ""
+	}
+	jumpName := c.Nodes[JumpHostName].Config().LongName
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Host %s\n", jumpName)
+	fmt.Fprintf(&b, "    HostName %s\n", host)
+	fmt.Fprintf(&b, "    Port %d\n", jh.Port)
+	fmt.Fprintf(&b, "    User %s\n", jh.User)
+
+	names := make([]string, 0, len(c.Nodes))
+	for name := range c.Nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		cfg := c.Nodes[name].Config()
+		if name == JumpHostName || cfg.MgmtIPv4Address == "" && cfg.MgmtIPv6Address == "" {
+			continue
+		}
+		addr := cfg.MgmtIPv4Address
+		if addr == "" {
+			addr = cfg.MgmtIPv6Address
+		}
+		fmt.Fprintf(&b, "\nHost %s\n", cfg.LongName)
+		fmt.Fprintf(&b, "    HostName %s\n", addr)
+		if creds := nodes.SaveCredentials(cfg); creds.Username != "" {
+			fmt.Fprintf(&b, "    User %s\n", creds.Username)
+		}
+		fmt.Fprintf(&b, "    ProxyJump %s\n", jumpName)
+	}
+	return b.String()
+}
+
+// WriteJumpHostSSHConfig writes the jump host ssh_config snippet to the jump host directory
+// and returns the path of the written file along with the snippet
+func (c *CLab) WriteJumpHostSSHConfig() (string, string, error) {
+	host, err := os.Hostname()
+	if err != nil {
+		return "", "", err
+	}
+	cfg := c.JumpHostSSHConfig(host)
+	dir := c.Nodes[JumpHostName].Config().LabDir
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", "", err
+	}
+	p := filepath.Join(dir, "ssh_config")
+	return p, cfg, ioutil.WriteFile(p, []byte(cfg), 0644)
+}
@@ -0,0 +1,92 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJumpHostInit(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo12.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n, ok := c.Nodes[JumpHostName]
+	if !ok {
+		t.Fatalf("jump host node is not created")
+	}
+	cfg := n.Config()
+	if cfg.Kind != "linux" || cfg.Image != defaultJumpHostImage {
+		t.Errorf("unexpected jump host kind/image: %s/%s", cfg.Kind, cfg.Image)
+	}
+	if cfg.Env["USER_NAME"] != "student" || cfg.Env["PASSWORD_ACCESS"] != "false" {
+		t.Errorf("unexpected jump host env: %v", cfg.Env)
+	}
+	if len(cfg.PortBindings) != 1 {
+		t.Errorf("expected a single published port, got %v", cfg.PortBindings)
+	}
+}
+
+func TestJumpHostSSHConfig(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo12.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `Host clab-topo12-jumphost
+    HostName lab-server
+    Port 2022
+    User student
+
+Host clab-topo12-node1
+    HostName 172.100.100.11
+    User admin
+    ProxyJump clab-topo12-jumphost
+
+Host clab-topo12-node2
+    HostName 2001:db8::12
+    ProxyJump clab-topo12-jumphost
+`
+	got := c.JumpHostSSHConfig("lab-server")
+	if !cmp.Equal(got, want) {
+		t.Errorf("diff (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestJumpHostKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clab-keys")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	k1 := filepath.Join(dir, "id_rsa.pub")
+	k2 := filepath.Join(dir, "id_ed25519.pub")
+	if err := ioutil.WriteFile(k1, []byte("ssh-rsa AAAA user1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(k2, []byte("ssh-ed25519 AAAA user2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := jumpHostKeys([]string{k1, k2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "ssh-rsa AAAA user1\nssh-ed25519 AAAA user2"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	if _, err := jumpHostKeys([]string{filepath.Join(dir, "absent.pub")}); err == nil {
+		t.Errorf("expected an error for a missing key file")
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/docker/go-units"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/cert"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// LabSummaryFile is the name of the lab directory file the lab summary is written to
+const LabSummaryFile = "lab-summary.json"
+
+// LabSummary is the summary of the deployed lab nodes, their resources and links
+type LabSummary struct {
+	Name  string         `json:"name"`
+	Nodes []*NodeSummary `json:"nodes"`
+	Links []*LinkSummary `json:"links"`
+	// CPUs and memory reserved by the lab nodes
+	CPU    float64 `json:"cpu,omitempty"`
+	Memory string  `json:"memory,omitempty"`
+}
+
+// NodeSummary holds the management addresses, the image and the resources of a lab node
+type NodeSummary struct {
+	Name        string `json:"name"`
+	Kind        string `json:"kind"`
+	State       string `json:"state,omitempty"`
+	MgmtIPv4    string `json:"mgmt_ipv4,omitempty"`
+	MgmtIPv6    string `json:"mgmt_ipv6,omitempty"`
+	Image       string `json:"image,omitempty"`
+	ImageDigest string `json:"image_digest,omitempty"`
+	// CPUs, memory and the host CPUs reserved for the node
+	CPU    string `json:"cpu,omitempty"`
+	Memory string `json:"memory,omitempty"`
+	CPUSet string `json:"cpu_set,omitempty"`
+	// SHA-256 fingerprint of the node TLS certificate
+	CertFingerprint string `json:"cert_fingerprint,omitempty"`
+}
+
+// LinkSummary is a lab link between the node:interface endpoints
+type LinkSummary struct {
+	A   string `json:"a"`
+	B   string `json:"b"`
+	MTU int    `json:"mtu,omitempty"`
+}
+
+// LabSummary returns the summary of the lab nodes sorted by node name and of the lab links.
+// The state and the management addresses of the nodes are taken from the containers, the image digests from the runtime,
+// the resources from the topology and the certificate fingerprints from the lab CA directory
+func (c *CLab) LabSummary(ctx context.Context, containers []types.GenericContainer) (*LabSummary, error) {
+	return c.labSummary(containers, c.imageDigests(ctx, containers))
+}
+
+// imageDigests returns the registry digests of the images of the lab containers keyed by the node name,
+// the digests are looked up once per image
+func (c *CLab) imageDigests(ctx context.Context, containers []types.GenericContainer) map[string]string {
+	digests := map[string]string{}
+	byImage := map[string]string{}
+	for _, ctr := range containers {
+		name := ctr.Labels[NodeNameLabel]
+		n, ok := c.Nodes[name]
+		if !ok {
+			continue
+		}
+		key := n.GetRuntime().GetName() + "/" + ctr.Image + "@" + ctr.ImageID
+		d, ok := byImage[key]
+		if !ok {
+			var err error
+			if d, err = n.GetRuntime().ImageDigest(ctx, ctr.Image, ctr.ImageID); err != nil {
+				log.Debugf("failed to look up digest of image %s of node %s: %v", ctr.Image, name, err)
+			}
+			byImage[key] = d
+		}
+		digests[name] = d
+	}
+	return digests
+}
+
+// labSummary returns the lab summary with the image digests of the nodes keyed by the node name
+func (c *CLab) labSummary(containers []types.GenericContainer, digests map[string]string) (*LabSummary, error) {
+	ctrs := map[string]types.GenericContainer{}
+	for _, ctr := range containers {
+		ctrs[ctr.Labels[NodeNameLabel]] = ctr
+	}
+
+	s := &LabSummary{Name: c.Config.Name, Nodes: []*NodeSummary{}, Links: []*LinkSummary{}}
+	for name, n := range c.Nodes {
+		cfg := n.Config()
+		ctr := ctrs[name]
+		ns := &NodeSummary{
+			Name:        cfg.LongName,
+			Kind:        cfg.Kind,
+			State:       ctr.State,
+			MgmtIPv4:    cfg.MgmtIPv4Address,
+			MgmtIPv6:    cfg.MgmtIPv6Address,
+			Image:       cfg.Image,
+			ImageDigest: digests[name],
+			CPU:         cfg.CPU,
+			CPUSet:      cfg.CPUSet,
+		}
+		if ctr.NetworkSettings != nil && ctr.NetworkSettings.Set {
+			ns.MgmtIPv4, ns.MgmtIPv6 = ctr.NetworkSettings.IPv4addr, ctr.NetworkSettings.IPv6addr
+		}
+		if ctr.Image != "" {
+			ns.Image = ctr.Image
+		}
+		m, err := nodeMemory(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse memory of node %q: %v", name, err)
+		}
+		if m > 0 {
+			ns.Memory = units.BytesSize(float64(m))
+		}
+		// the nodes without the certificate have no node directory in the lab CA directory
+		if certs, err := cert.RetrieveNodeCertData(cfg, c.Dir.LabCA); err == nil && certs != nil {
+			if ns.CertFingerprint, err = cert.Fingerprint(certs.Cert); err != nil {
+				return nil, fmt.Errorf("failed to read certificate of node %q: %v", name, err)
+			}
+		}
+		s.Nodes = append(s.Nodes, ns)
+	}
+	sort.Slice(s.Nodes, func(i, j int) bool {
+		return s.Nodes[i].Name < s.Nodes[j].Name
+	})
+
+	for _, l := range c.Links {
+		s.Links = append(s.Links, &LinkSummary{A: endpointName(l.A), B: endpointName(l.B), MTU: l.MTU})
+	}
+	sort.Slice(s.Links, func(i, j int) bool {
+		if s.Links[i].A == s.Links[j].A {
+			return s.Links[i].B < s.Links[j].B
+		}
+		return s.Links[i].A < s.Links[j].A
+	})
+
+	r, err := c.declaredResources()
+	if err != nil {
+		return nil, err
+	}
+	s.CPU = r.cpu
+	if r.memory > 0 {
+		s.Memory = units.BytesSize(float64(r.memory))
+	}
+	return s, nil
+}
+
+// WriteLabSummary writes the lab summary to w in the json format
+func WriteLabSummary(w io.Writer, s *LabSummary) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// SaveLabSummary writes the lab summary to the lab directory and returns the path of the written file
+func (c *CLab) SaveLabSummary(s *LabSummary) (string, error) {
+	p := filepath.Join(c.Dir.Lab, LabSummaryFile)
+	f, err := os.Create(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return p, WriteLabSummary(f, s)
+}
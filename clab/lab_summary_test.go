@@ -0,0 +1,76 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestLabSummary(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo33.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// no node certificates in the lab CA directory
+	c.Dir.LabCA = t.TempDir()
+
+	containers := []types.GenericContainer{
+		{
+			Image:   "alpine:3",
+			ImageID: "sha256:0123",
+			State:   "running",
+			Labels:  map[string]string{NodeNameLabel: "node1"},
+			NetworkSettings: &types.GenericMgmtIPs{
+				Set: true, IPv4addr: "172.20.20.2", IPv6addr: "2001:172:20:20::2",
+			},
+		},
+		{
+			Image:   "alpine:3",
+			ImageID: "sha256:0123",
+			State:   "exited",
+			Labels:  map[string]string{NodeNameLabel: "node2"},
+		},
+	}
+
+	want := &LabSummary{
+		Name: "topo33",
+		Nodes: []*NodeSummary{
+			{
+				Name: "clab-topo33-node1", Kind: "linux", State: "running",
+				MgmtIPv4: "172.20.20.2", MgmtIPv6: "2001:172:20:20::2",
+				Image: "alpine:3", ImageDigest: "sha256:4567",
+				CPU: "2.5", Memory: "1GiB", CPUSet: "0-3",
+			},
+			{
+				Name: "clab-topo33-node2", Kind: "linux", State: "exited",
+				Image: "alpine:3", ImageDigest: "sha256:4567",
+				CPU: "1", Memory: "512MiB",
+			},
+			// node3 container is missing, the node is summarized from the topology
+			{
+				Name: "clab-topo33-node3", Kind: "linux",
+				Image: "alpine:edge",
+				CPU:   "1", Memory: "512MiB",
+			},
+		},
+		Links: []*LinkSummary{
+			{A: "node1:eth2", B: "node3:eth1", MTU: 9500},
+			{A: "node2:eth1", B: "node1:eth1", MTU: 9000},
+		},
+		CPU:    4.5,
+		Memory: "2GiB",
+	}
+	digests := map[string]string{"node1": "sha256:4567", "node2": "sha256:4567"}
+	got, err := c.labSummary(containers, digests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("lab summary mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"sort"
+	"time"
+
+	"github.com/srl-labs/containerlab/types"
+)
+
+// LabInfo is a deployed lab discovered by the labels of its containers
+type LabInfo struct {
+	Name     string    `json:"name"`
+	TopoFile string    `json:"topo_file"`
+	LabDir   string    `json:"lab_dir,omitempty"`
+	Nodes    int       `json:"nodes"`
+	Running  int       `json:"running"`
+	Created  time.Time `json:"created"`
+}
+
+// DiscoverLabs groups the containerlab containers by their lab, the labs are sorted by name.
+// The lab is created when its oldest container was created
+func DiscoverLabs(containers []types.GenericContainer) []*LabInfo {
+	labs := map[string]*LabInfo{}
+	for _, ctr := range containers {
+		name := ctr.Labels[ContainerlabLabel]
+		if name == "" {
+			continue
+		}
+		l, ok := labs[name]
+		if !ok {
+			l = &LabInfo{Name: name, TopoFile: ctr.Labels[TopoFileLabel], LabDir: LabDirFromLabels(ctr.Labels)}
+			labs[name] = l
+		}
+		l.Nodes++
+		if ctr.State == "running" {
+			l.Running++
+		}
+		if !ctr.Created.IsZero() && (l.Created.IsZero() || ctr.Created.Before(l.Created)) {
+			l.Created = ctr.Created
+		}
+	}
+	res := make([]*LabInfo, 0, len(labs))
+	for _, l := range labs {
+		res = append(res, l)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
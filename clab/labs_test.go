@@ -0,0 +1,37 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestDiscoverLabs(t *testing.T) {
+	t0 := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	labels := func(lab string) map[string]string {
+		return map[string]string{
+			ContainerlabLabel: lab,
+			TopoFileLabel:     "/labs/" + lab + ".clab.yml",
+			LabDirLabel:       "/labs/clab-" + lab,
+		}
+	}
+	ctrs := []types.GenericContainer{
+		{Names: []string{"/clab-b-n1"}, State: "running", Labels: labels("b"), Created: t0.Add(time.Minute)},
+		{Names: []string{"/clab-a-n1"}, State: "running", Labels: labels("a"), Created: t0.Add(2 * time.Minute)},
+		{Names: []string{"/clab-a-n2"}, State: "exited", Labels: labels("a"), Created: t0},
+		{Names: []string{"/other"}, State: "running", Labels: map[string]string{}},
+	}
+	want := []*LabInfo{
+		{Name: "a", TopoFile: "/labs/a.clab.yml", LabDir: "/labs/clab-a", Nodes: 2, Running: 1, Created: t0},
+		{Name: "b", TopoFile: "/labs/b.clab.yml", LabDir: "/labs/clab-b", Nodes: 1, Running: 1, Created: t0.Add(time.Minute)},
+	}
+	if d := cmp.Diff(want, DiscoverLabs(ctrs)); d != "" {
+		t.Errorf("labs mismatch (-want +got):\n%s", d)
+	}
+}
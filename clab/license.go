@@ -0,0 +1,70 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// verifyLicenses checks that the license files of the nodes are readable and supported by their kinds,
+// so that a missing license fails the deployment before any node is created
+func (c *CLab) verifyLicenses() error {
+	names := make([]string, 0, len(c.Nodes))
+	for name := range c.Nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := nodes.CheckLicense(c.Nodes[name].Config()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CopyLicenses copies the license files of the nodes which kinds install the license after the VM has booted,
+// the nodes are licensed concurrently and each of them is given its boot timeout
+func (c *CLab) CopyLicenses(ctx context.Context) error {
+	var mu sync.Mutex
+	var errs []string
+	wg := new(sync.WaitGroup)
+	for _, n := range c.Nodes {
+		cfg := n.Config()
+		if cfg.License == "" {
+			continue
+		}
+		if d, err := nodes.LicenseDeliveryOf(cfg.Kind); err != nil || d.Mode != nodes.LicenseSCP {
+			continue
+		}
+		wg.Add(1)
+		go func(cfg *types.NodeConfig) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, waitTimeout(cfg))
+			defer cancel()
+			log.Infof("Copying license to node %s", cfg.ShortName)
+			if err := nodes.CopyLicense(ctx, cfg, readyPoll); err != nil {
+				mu.Lock()
+				errs = append(errs, err.Error())
+				mu.Unlock()
+				return
+			}
+			log.Infof("License copied to node %s", cfg.ShortName)
+		}(cfg)
+	}
+	wg.Wait()
+	if len(errs) != 0 {
+		sort.Strings(errs)
+		return fmt.Errorf("failed to copy license: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
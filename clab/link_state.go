@@ -0,0 +1,89 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/vishvananda/netlink"
+)
+
+// LinkFlap is the schedule of the interface flaps
+type LinkFlap struct {
+	// time the interface stays down
+	Down time.Duration
+	// interval between the starts of the flaps
+	Interval time.Duration
+	// number of flaps, the interface flaps until canceled when 0
+	Count int
+}
+
+// Validate checks the flap schedule for errors
+func (f *LinkFlap) Validate() error {
+	if f.Down <= 0 {
+		return fmt.Errorf("flap down time must be positive, got %s", f.Down)
+	}
+	if f.Interval <= f.Down {
+		return fmt.Errorf("flap interval %s must be longer than the down time %s", f.Interval, f.Down)
+	}
+	if f.Count < 0 {
+		return fmt.Errorf("flap count must not be negative, got %d", f.Count)
+	}
+	return nil
+}
+
+// SetInterfaceState sets the administrative state of the interface in the network namespace of nsPath to up or down.
+// The peer of a veth interface brought down loses its carrier, so both ends of the link see it down
+func SetInterfaceState(nsPath, iface, state string) error {
+	var f func(netlink.Link) error
+	switch state {
+	case types.InterfaceStateUp:
+		f = netlink.LinkSetUp
+	case types.InterfaceStateDown:
+		f = netlink.LinkSetDown
+	default:
+		return fmt.Errorf("invalid interface state %q, expected %s or %s", state, types.InterfaceStateUp, types.InterfaceStateDown)
+	}
+	return doInterface(nsPath, iface, f)
+}
+
+// FlapInterface brings the interface in the network namespace of nsPath down for the down time of the flap
+// every flap interval, until the flaps count is reached or ctx is canceled. The interface is left up
+func FlapInterface(ctx context.Context, nsPath, iface string, f *LinkFlap) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+	ticker := time.NewTicker(f.Interval)
+	defer ticker.Stop()
+	for i := 1; f.Count == 0 || i <= f.Count; i++ {
+		if i > 1 {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+		if err := SetInterfaceState(nsPath, iface, types.InterfaceStateDown); err != nil {
+			return err
+		}
+		log.Infof("Flap %d: interface %s is down for %s", i, iface, f.Down)
+		select {
+		case <-ctx.Done():
+		case <-time.After(f.Down):
+		}
+		if err := SetInterfaceState(nsPath, iface, types.InterfaceStateUp); err != nil {
+			return err
+		}
+		log.Infof("Flap %d: interface %s is up", i, iface)
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLinkFlapValidate(t *testing.T) {
+	tests := map[string]struct {
+		flap    *LinkFlap
+		wantErr bool
+	}{
+		"valid":              {flap: &LinkFlap{Down: 5 * time.Second, Interval: 30 * time.Second}},
+		"valid-count":        {flap: &LinkFlap{Down: time.Second, Interval: 2 * time.Second, Count: 3}},
+		"no-down-time":       {flap: &LinkFlap{Interval: 30 * time.Second}, wantErr: true},
+		"interval-too-short": {flap: &LinkFlap{Down: 5 * time.Second, Interval: 5 * time.Second}, wantErr: true},
+		"negative-count":     {flap: &LinkFlap{Down: time.Second, Interval: 2 * time.Second, Count: -1}, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := tc.flap.Validate(); (err != nil) != tc.wantErr {
+				t.Errorf("wanted error %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSetInterfaceStateInvalid(t *testing.T) {
+	if err := SetInterfaceState("/proc/1/ns/net", "eth1", "flapping"); err == nil {
+		t.Error("wanted error for invalid interface state")
+	}
+}
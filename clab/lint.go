@@ -0,0 +1,386 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/nodes/srl"
+	"github.com/srl-labs/containerlab/types"
+	"gopkg.in/yaml.v2"
+)
+
+// lint finding severities, from the most to the least severe
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+	LintInfo    = "info"
+)
+
+// lintSeverities ranks the severities, the higher rank the more severe a finding is
+var lintSeverities = map[string]int{
+	LintInfo:    0,
+	LintWarning: 1,
+	LintError:   2,
+}
+
+// credentialEnvRe matches the names of the env vars holding credentials
+var credentialEnvRe = regexp.MustCompile(`(?i)(passw|secret|token)`)
+
+// LintFinding is a single issue found in the topology by a lint rule
+type LintFinding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Node     string `json:"node,omitempty"`
+	Message  string `json:"message"`
+}
+
+// lintTopo is the topology checked by the lint rules
+type lintTopo struct {
+	// topology with the node ranges expanded and the env vars substituted
+	topo *types.Topology
+	// topology as written in the file, without the env vars substituted
+	raw *types.Topology
+}
+
+// LintRule is a function checking the topology for a class of issues
+type LintRule func(l *lintTopo) []*LintFinding
+
+// LintRules is the list of rules run by the LintTopology
+var LintRules = []LintRule{
+	lintKinds,
+	lintEndpoints,
+	lintUnlinkedNodes,
+	lintImageTags,
+	lintCredentials,
+	lintNodeFiles,
+	lintKindConstraints,
+}
+
+// kindLintRules holds the kind specific rules run for each node of the kind
+var kindLintRules = map[string]func(t *types.Topology, name string) []*LintFinding{
+	nodes.NodeKindSRL:    lintSRL,
+	nodes.NodeKindVrSROS: lintSROS,
+	nodes.NodeKindCVX:    lintCVX,
+}
+
+// cvxPortRe matches the Cumulus Linux front panel port names, e.g. swp1 or breakout port swp1s0
+var cvxPortRe = regexp.MustCompile(`^swp\d+(s\d+)?$`)
+
+// LintTopology runs the lint rules against the topology file and returns the findings
+// sorted by severity, rule and node. An error is returned if the file can't be read.
+// The options set the lab settings the topology file is read with, e.g. the topology template vars
+func LintTopology(file string, opts ...ClabOption) ([]*LintFinding, error) {
+	c, err := NewContainerLab(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.GetTopology(file); err != nil {
+		return nil, fmt.Errorf("failed to read topology file: %v", err)
+	}
+	var res []*LintFinding
+	if err := c.expandTopology(); err != nil {
+		res = append(res, &LintFinding{Rule: "node-ranges", Severity: LintError, Message: err.Error()})
+	}
+	c.Config.Topology.ApplyGroups()
+
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	vars, err := readTopoVars(c.topoVarsFile)
+	if err != nil {
+		return nil, err
+	}
+	if b, err = renderTopology(file, b, vars); err != nil {
+		return nil, err
+	}
+	if b, err = decodeTopology(file, b); err != nil {
+		return nil, err
+	}
+	raw := &Config{Topology: types.NewTopology()}
+	// the fields set with env vars may fail to decode, the rest of the topology is decoded regardless
+	if err := yaml.Unmarshal(b, raw); err != nil {
+		if _, ok := err.(*yaml.TypeError); !ok {
+			return nil, fmt.Errorf("failed to read topology file: %v", err)
+		}
+	}
+
+	l := &lintTopo{topo: c.Config.Topology, raw: raw.Topology}
+	for _, rule := range LintRules {
+		res = append(res, rule(l)...)
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Severity != res[j].Severity {
+			return lintSeverities[res[i].Severity] > lintSeverities[res[j].Severity]
+		}
+		if res[i].Rule != res[j].Rule {
+			return res[i].Rule < res[j].Rule
+		}
+		return res[i].Node < res[j].Node
+	})
+	return res, nil
+}
+
+// LintFailed returns true if any of the findings is at least as severe as the severity
+func LintFailed(findings []*LintFinding, severity string) bool {
+	for _, f := range findings {
+		if lintSeverities[f.Severity] >= lintSeverities[severity] {
+			return true
+		}
+	}
+	return false
+}
+
+// IsLintSeverity returns true if s is a valid lint severity
+func IsLintSeverity(s string) bool {
+	_, ok := lintSeverities[s]
+	return ok
+}
+
+// sortedNodeNames returns the names of the topology nodes in alphabetical order
+func sortedNodeNames(t *types.Topology) []string {
+	names := make([]string, 0, len(t.Nodes))
+	for name := range t.Nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lintKinds reports the nodes with missing or unknown kinds and the unused kinds of the kinds section
+func lintKinds(l *lintTopo) []*LintFinding {
+	var res []*LintFinding
+	used := map[string]struct{}{}
+	for _, name := range sortedNodeNames(l.topo) {
+		kind := l.topo.GetNodeKind(name)
+		used[kind] = struct{}{}
+		switch {
+		case kind == "":
+			res = append(res, &LintFinding{Rule: "node-kind", Severity: LintError, Node: name,
+				Message: "node has no kind set"})
+		case !isSupportedKind(kind):
+			res = append(res, &LintFinding{Rule: "node-kind", Severity: LintError, Node: name,
+				Message: fmt.Sprintf("kind %q is not supported", kind)})
+		}
+	}
+	defined := make([]string, 0, len(l.topo.GetKinds()))
+	for kind := range l.topo.GetKinds() {
+		defined = append(defined, kind)
+	}
+	sort.Strings(defined)
+	for _, kind := range defined {
+		if _, ok := used[kind]; !ok {
+			res = append(res, &LintFinding{Rule: "unused-kind", Severity: LintWarning,
+				Message: fmt.Sprintf("kind %q is defined in the kinds section, but no node uses it", kind)})
+		}
+	}
+	return res
+}
+
+func isSupportedKind(kind string) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// lintEndpoints reports the malformed, duplicate and dangling link endpoints
+func lintEndpoints(l *lintTopo) []*LintFinding {
+	var res []*LintFinding
+	seen := map[string]struct{}{}
+	for _, lc := range l.topo.Links {
+		if len(lc.Endpoints) != 2 {
+			res = append(res, &LintFinding{Rule: "link-endpoints", Severity: LintError,
+				Message: fmt.Sprintf("link %q must have exactly 2 endpoints", lc.Endpoints)})
+		}
+		for _, e := range lc.Endpoints {
+			if err := checkEndpoint(e); err != nil {
+				res = append(res, &LintFinding{Rule: "link-endpoints", Severity: LintError, Message: err.Error()})
+				continue
+			}
+			if _, ok := seen[e]; ok {
+				res = append(res, &LintFinding{Rule: "duplicate-endpoint", Severity: LintError,
+					Node: strings.Split(e, ":")[0], Message: fmt.Sprintf("endpoint %q is used by more than one link", e)})
+			}
+			seen[e] = struct{}{}
+			split := strings.Split(e, ":")
+			if len(split[1]) > 15 {
+				res = append(res, &LintFinding{Rule: "link-endpoints", Severity: LintError, Node: split[0],
+					Message: fmt.Sprintf("interface name %q exceeds maximum length of 15 characters", split[1])})
+			}
+			if split[0] == "host" || split[0] == "mgmt-net" {
+				continue
+			}
+			if _, ok := l.topo.Nodes[split[0]]; !ok {
+				res = append(res, &LintFinding{Rule: "link-endpoints", Severity: LintError, Node: split[0],
+					Message: fmt.Sprintf("endpoint %q references a node not defined in the nodes section", e)})
+			}
+		}
+	}
+	return res
+}
+
+// lintUnlinkedNodes reports the nodes with no links
+func lintUnlinkedNodes(l *lintTopo) []*LintFinding {
+	linked := map[string]struct{}{}
+	for _, lc := range l.topo.Links {
+		for _, e := range lc.Endpoints {
+			linked[strings.Split(e, ":")[0]] = struct{}{}
+		}
+	}
+	var res []*LintFinding
+	for _, name := range sortedNodeNames(l.topo) {
+		if _, ok := linked[name]; !ok {
+			res = append(res, &LintFinding{Rule: "unlinked-node", Severity: LintInfo, Node: name,
+				Message: "node has no links"})
+		}
+	}
+	return res
+}
+
+// lintImageTags reports the images without an explicit tag or digest,
+// such images resolve to the latest tag which changes over time
+func lintImageTags(l *lintTopo) []*LintFinding {
+	var res []*LintFinding
+	for _, name := range sortedNodeNames(l.topo) {
+		switch l.topo.GetNodeKind(name) {
+		// these kinds don't run containers
+		case nodes.NodeKindBridge, nodes.NodeKindOVS, nodes.NodeKindHOST:
+			continue
+		}
+		image := l.topo.GetNodeImage(name)
+		if image == "" {
+			res = append(res, &LintFinding{Rule: "image-tag", Severity: LintError, Node: name,
+				Message: "node has no image set"})
+			continue
+		}
+		// images pinned with a digest have a fixed content
+		if strings.Contains(image, "@") {
+			continue
+		}
+		if tag := imageTag(image); tag == "" || tag == "latest" {
+			res = append(res, &LintFinding{Rule: "image-tag", Severity: LintWarning, Node: name,
+				Message: fmt.Sprintf("image %q has no explicit tag, the latest tag is used", image)})
+		}
+	}
+	return res
+}
+
+// imageTag returns the tag of the image reference, the registry port is not mistaken for a tag
+func imageTag(image string) string {
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i+1:], "/") {
+		return ""
+	}
+	return image[i+1:]
+}
+
+// lintCredentials reports the credentials set in plain text in the env vars,
+// credentials should be passed in from the environment, e.g. PASSWORD: ${NODE_PASSWORD}
+func lintCredentials(l *lintTopo) []*LintFinding {
+	var res []*LintFinding
+	check := func(where, node string, env map[string]string) {
+		keys := make([]string, 0, len(env))
+		for k := range env {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if !credentialEnvRe.MatchString(k) || env[k] == "" || strings.Contains(env[k], "$") {
+				continue
+			}
+			res = append(res, &LintFinding{Rule: "plaintext-credentials", Severity: LintWarning, Node: node,
+				Message: fmt.Sprintf("env var %s of %s is set in plain text, pass it in from the environment instead", k, where)})
+		}
+	}
+	check("the defaults", "", l.raw.GetDefaults().GetEnv())
+	kindNames := make([]string, 0, len(l.raw.GetKinds()))
+	for k := range l.raw.GetKinds() {
+		kindNames = append(kindNames, k)
+	}
+	sort.Strings(kindNames)
+	for _, k := range kindNames {
+		check(fmt.Sprintf("kind %s", k), "", l.raw.GetKind(k).GetEnv())
+	}
+	for _, name := range sortedNodeNames(l.raw) {
+		check("the node", name, l.raw.Nodes[name].GetEnv())
+	}
+	return res
+}
+
+// lintNodeFiles reports the missing files referenced by the nodes
+// and the invalid persist and interface-profile settings
+func lintNodeFiles(l *lintTopo) []*LintFinding {
+	var res []*LintFinding
+	for _, name := range sortedNodeNames(l.topo) {
+		if _, err := l.topo.GetNodeStartupConfig(name); err != nil {
+			res = append(res, &LintFinding{Rule: "node-files", Severity: LintError, Node: name,
+				Message: fmt.Sprintf("startup-config: %v", err)})
+		}
+		if _, err := l.topo.GetNodeLicense(name); err != nil {
+			res = append(res, &LintFinding{Rule: "node-files", Severity: LintError, Node: name,
+				Message: fmt.Sprintf("license: %v", err)})
+		}
+		if _, err := parsePersist(l.topo.GetNodePersist(name), "", ""); err != nil {
+			res = append(res, &LintFinding{Rule: "node-settings", Severity: LintError, Node: name, Message: err.Error()})
+		}
+		if p := l.topo.GetNodeInterfaceProfile(name); p != nil {
+			if err := p.Validate(); err != nil {
+				res = append(res, &LintFinding{Rule: "node-settings", Severity: LintError, Node: name, Message: err.Error()})
+			}
+		}
+	}
+	return res
+}
+
+// lintKindConstraints runs the kind specific rules
+func lintKindConstraints(l *lintTopo) []*LintFinding {
+	var res []*LintFinding
+	for _, name := range sortedNodeNames(l.topo) {
+		if rule, ok := kindLintRules[l.topo.GetNodeKind(name)]; ok {
+			res = append(res, rule(l.topo, name)...)
+		}
+	}
+	return res
+}
+
+func lintSRL(t *types.Topology, name string) []*LintFinding {
+	if typ := t.GetNodeType(name); typ != "" && !srl.IsValidType(typ) {
+		return []*LintFinding{{Rule: "kind-constraints", Severity: LintError, Node: name,
+			Message: fmt.Sprintf("srl node type %q doesn't exist", typ)}}
+	}
+	return nil
+}
+
+func lintSROS(t *types.Topology, name string) []*LintFinding {
+	if lic, err := t.GetNodeLicense(name); err == nil && lic == "" {
+		return []*LintFinding{{Rule: "kind-constraints", Severity: LintWarning, Node: name,
+			Message: "vr-sros node has no license set, SR OS runs with limited capabilities without a license"}}
+	}
+	return nil
+}
+
+func lintCVX(t *types.Topology, name string) []*LintFinding {
+	var res []*LintFinding
+	for _, l := range t.Links {
+		for _, e := range l.Endpoints {
+			split := strings.Split(e, ":")
+			if len(split) != 2 || split[0] != name || cvxPortRe.MatchString(split[1]) {
+				continue
+			}
+			res = append(res, &LintFinding{Rule: "kind-constraints", Severity: LintWarning, Node: name,
+				Message: fmt.Sprintf("cvx interface %q is not a front panel port, Cumulus Linux manages swpN interfaces as switch ports", split[1])})
+		}
+	}
+	return res
+}
@@ -0,0 +1,60 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLintTopology(t *testing.T) {
+	got, err := LintTopology("test_data/topo20.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*LintFinding{
+		{Rule: "duplicate-endpoint", Severity: LintError, Node: "srl1", Message: `endpoint "srl1:e1-1" is used by more than one link`},
+		{Rule: "kind-constraints", Severity: LintError, Node: "srl1", Message: `srl node type "ixr99" doesn't exist`},
+		{Rule: "link-endpoints", Severity: LintError, Node: "ghost", Message: `endpoint "ghost:eth1" references a node not defined in the nodes section`},
+		{Rule: "node-kind", Severity: LintError, Node: "junk", Message: `kind "junos" is not supported`},
+		{Rule: "image-tag", Severity: LintWarning, Node: "linux1", Message: `image "alpine" has no explicit tag, the latest tag is used`},
+		{Rule: "image-tag", Severity: LintWarning, Node: "linux2", Message: `image "registry.example.com:5000/alpine" has no explicit tag, the latest tag is used`},
+		{Rule: "kind-constraints", Severity: LintWarning, Node: "cvx", Message: `cvx interface "eth1" is not a front panel port, Cumulus Linux manages swpN interfaces as switch ports`},
+		{Rule: "kind-constraints", Severity: LintWarning, Node: "sros", Message: "vr-sros node has no license set, SR OS runs with limited capabilities without a license"},
+		{Rule: "plaintext-credentials", Severity: LintWarning, Node: "srl2", Message: "env var ADMIN_PASSWORD of the node is set in plain text, pass it in from the environment instead"},
+		{Rule: "unused-kind", Severity: LintWarning, Message: `kind "ceos" is defined in the kinds section, but no node uses it`},
+		{Rule: "unlinked-node", Severity: LintInfo, Node: "junk", Message: "node has no links"},
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("diff (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestLintFailed(t *testing.T) {
+	findings := []*LintFinding{{Severity: LintWarning}}
+	for severity, want := range map[string]bool{
+		LintError:   false,
+		LintWarning: true,
+		LintInfo:    true,
+	} {
+		if got := LintFailed(findings, severity); got != want {
+			t.Errorf("severity %s: wanted %v, got %v", severity, want, got)
+		}
+	}
+}
+
+func TestImageTag(t *testing.T) {
+	for image, want := range map[string]string{
+		"alpine":                             "",
+		"alpine:3":                           "3",
+		"registry.example.com:5000/alpine":   "",
+		"registry.example.com:5000/alpine:3": "3",
+	} {
+		if got := imageTag(image); got != want {
+			t.Errorf("image %s: wanted %q, got %q", image, want, got)
+		}
+	}
+}
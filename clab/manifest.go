@@ -0,0 +1,170 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+const (
+	// manifestFileName is the name of the lab manifest file created in the lab directory
+	manifestFileName = "manifest.json"
+)
+
+// Manifest is a machine-readable description of a deployed lab
+type Manifest struct {
+	Name     string          `json:"name"`
+	TopoFile string          `json:"topology_file"`
+	LabDir   string          `json:"lab_dir"`
+	Mgmt     *types.MgmtNet  `json:"mgmt"`
+	CA       *ManifestCA     `json:"ca,omitempty"`
+	Nodes    []*ManifestNode `json:"nodes"`
+	Links    []*ManifestLink `json:"links"`
+}
+
+// ManifestCA holds the paths to the lab root CA artifacts
+type ManifestCA struct {
+	Cert string `json:"cert"`
+	Key  string `json:"key"`
+}
+
+// ManifestNode describes a lab node in the manifest
+type ManifestNode struct {
+	Name        string                      `json:"name"`
+	LongName    string                      `json:"long_name"`
+	Kind        string                      `json:"kind"`
+	Type        string                      `json:"type,omitempty"`
+	Group       string                      `json:"group,omitempty"`
+	Image       string                      `json:"image,omitempty"`
+	LabDir      string                      `json:"lab_dir"`
+	MgmtIPv4    string                      `json:"mgmt_ipv4,omitempty"`
+	MgmtIPv6    string                      `json:"mgmt_ipv6,omitempty"`
+	Credentials *ManifestCredentials        `json:"credentials,omitempty"`
+	Ports       []*types.GenericPortBinding `json:"ports,omitempty"`
+	TLS         *ManifestCA                 `json:"tls,omitempty"`
+}
+
+// ManifestCredentials tells where the credentials of a node come from
+// the password is not included in the manifest
+type ManifestCredentials struct {
+	Username string `json:"username"`
+	Source   string `json:"source"`
+}
+
+// ManifestLink describes a lab link in the manifest
+type ManifestLink struct {
+	A string `json:"a"`
+	B string `json:"b"`
+}
+
+// ManifestPath returns the path to the lab manifest file
+func (c *CLab) ManifestPath() string {
+	return filepath.Join(c.Dir.Lab, manifestFileName)
+}
+
+// GenerateManifest writes the lab manifest to the lab directory
+func (c *CLab) GenerateManifest() error {
+	f, err := os.Create(c.ManifestPath())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return c.writeManifest(f)
+}
+
+// writeManifest builds the lab manifest and writes it to w
+func (c *CLab) writeManifest(w io.Writer) error {
+	m := &Manifest{
+		Name:     c.Config.Name,
+		TopoFile: c.TopoFile.path,
+		LabDir:   c.Dir.Lab,
+		Mgmt:     c.Config.Mgmt,
+		Nodes:    make([]*ManifestNode, 0, len(c.Nodes)),
+		Links:    make([]*ManifestLink, 0, len(c.Links)),
+	}
+
+	caCert := filepath.Join(c.Dir.LabCARoot, "root-ca.pem")
+	if utils.FileExists(caCert) {
+		m.CA = &ManifestCA{
+			Cert: caCert,
+			Key:  filepath.Join(c.Dir.LabCARoot, "root-ca-key.pem"),
+		}
+	}
+
+	for _, n := range c.Nodes {
+		cfg := n.Config()
+		mn := &ManifestNode{
+			Name:     cfg.ShortName,
+			LongName: cfg.LongName,
+			Kind:     cfg.Kind,
+			Type:     cfg.NodeType,
+			Group:    cfg.Group,
+			Image:    cfg.Image,
+			LabDir:   cfg.LabDir,
+			MgmtIPv4: cfg.MgmtIPv4Address,
+			MgmtIPv6: cfg.MgmtIPv6Address,
+		}
+
+		if creds, source := nodes.NodeCredentials(cfg); creds.Username != "" {
+			mn.Credentials = &ManifestCredentials{
+				Username: creds.Username,
+				Source:   source,
+			}
+		}
+
+		for port, bindings := range cfg.PortBindings {
+			for _, b := range bindings {
+				hostPort, _ := strconv.Atoi(b.HostPort)
+				mn.Ports = append(mn.Ports, &types.GenericPortBinding{
+					HostIP:        b.HostIP,
+					HostPort:      hostPort,
+					ContainerPort: port.Int(),
+					Protocol:      port.Proto(),
+				})
+			}
+		}
+		sort.Slice(mn.Ports, func(i, j int) bool {
+			return mn.Ports[i].String() < mn.Ports[j].String()
+		})
+
+		nodeCert := filepath.Join(c.Dir.LabCA, cfg.ShortName, cfg.ShortName+".pem")
+		if utils.FileExists(nodeCert) {
+			mn.TLS = &ManifestCA{
+				Cert: nodeCert,
+				Key:  filepath.Join(c.Dir.LabCA, cfg.ShortName, cfg.ShortName+"-key.pem"),
+			}
+		}
+
+		m.Nodes = append(m.Nodes, mn)
+	}
+	sort.Slice(m.Nodes, func(i, j int) bool {
+		return m.Nodes[i].Name < m.Nodes[j].Name
+	})
+
+	// links are kept in the order of their definition in the topology file
+	for i := 0; i < len(c.Links); i++ {
+		l, ok := c.Links[i]
+		if !ok {
+			continue
+		}
+		m.Links = append(m.Links, &ManifestLink{
+			A: l.A.Node.ShortName + ":" + l.A.EndpointName,
+			B: l.B.Node.ShortName + ":" + l.B.EndpointName,
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(m)
+}
@@ -0,0 +1,108 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestGenerateManifest(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo34.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Dir.Lab = t.TempDir()
+	c.Dir.LabCA = filepath.Join(c.Dir.Lab, "ca")
+	c.Dir.LabCARoot = filepath.Join(c.Dir.LabCA, "root")
+	// root CA and the certificate of srl1 only
+	for _, f := range []string{
+		filepath.Join(c.Dir.LabCARoot, "root-ca.pem"),
+		filepath.Join(c.Dir.LabCA, "srl1", "srl1.pem"),
+	} {
+		if err := os.MkdirAll(filepath.Dir(f), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(f, []byte("cert"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := c.GenerateManifest(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(c.Dir.Lab, "manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := new(Manifest)
+	if err := json.Unmarshal(b, got); err != nil {
+		t.Fatalf("failed to decode the manifest: %v", err)
+	}
+
+	want := &Manifest{
+		Name:     "topo34",
+		TopoFile: c.TopoFile.path,
+		LabDir:   c.Dir.Lab,
+		CA: &ManifestCA{
+			Cert: filepath.Join(c.Dir.LabCARoot, "root-ca.pem"),
+			Key:  filepath.Join(c.Dir.LabCARoot, "root-ca-key.pem"),
+		},
+		Nodes: []*ManifestNode{
+			{
+				Name:     "host1",
+				LongName: "clab-topo34-host1",
+				Kind:     "linux",
+				Group:    "hosts",
+				Image:    "alpine:3",
+				LabDir:   c.Nodes["host1"].Config().LabDir,
+				Credentials: &ManifestCredentials{
+					Username: "ops",
+					Source:   "topology",
+				},
+			},
+			{
+				Name:     "srl1",
+				LongName: "clab-topo34-srl1",
+				Kind:     "srl",
+				Type:     "ixrd2",
+				Image:    c.Nodes["srl1"].Config().Image,
+				LabDir:   c.Nodes["srl1"].Config().LabDir,
+				MgmtIPv4: "172.20.20.3",
+				Credentials: &ManifestCredentials{
+					Username: "admin",
+					Source:   "kind-default",
+				},
+				// sorted by their docker notation
+				Ports: []*types.GenericPortBinding{
+					{HostIP: "127.0.0.1", HostPort: 5161, ContainerPort: 161, Protocol: "udp"},
+					{HostPort: 8080, ContainerPort: 80, Protocol: "tcp"},
+				},
+				TLS: &ManifestCA{
+					Cert: filepath.Join(c.Dir.LabCA, "srl1", "srl1.pem"),
+					Key:  filepath.Join(c.Dir.LabCA, "srl1", "srl1-key.pem"),
+				},
+			},
+		},
+		// links are in the order of the topology file
+		Links: []*ManifestLink{
+			{A: "srl1:e1-1", B: "host1:eth1"},
+			{A: "srl1:e1-2", B: "host1:eth2"},
+		},
+	}
+	if got.Mgmt == nil || got.Mgmt.Network != c.Config.Mgmt.Network {
+		t.Errorf("got mgmt %+v, want the network %q", got.Mgmt, c.Config.Mgmt.Network)
+	}
+	got.Mgmt = nil
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("manifest mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// LabMetrics exports the health of the lab nodes as Prometheus metrics: the up/down state, the restart counts
+// and the CPU and memory usage of the node containers, along with the node counts of the labs
+type LabMetrics struct {
+	c *CLab
+	// lab name, all labs of the host when empty
+	lab string
+
+	mu sync.Mutex
+	// states are the last observed states of the node containers keyed by the container name
+	states map[string]string
+	// restarts counts the starts of the node containers following their exit keyed by the container name
+	restarts map[string]int
+}
+
+// nodeSample is the state of a lab node the metrics are exported of
+type nodeSample struct {
+	lab, node, kind string
+	up              bool
+	restarts        int
+	// resource usage of the running nodes, nil when the runtime failed to report it
+	stats *types.ContainerStats
+}
+
+// metricFamily is a metric with its samples written in the Prometheus text format
+type metricFamily struct {
+	name, help, typ string
+	samples         []metricSample
+}
+
+type metricSample struct {
+	// label names and values pairs
+	labels []string
+	value  float64
+}
+
+// NewLabMetrics returns the metrics of the lab, all labs of the host are exported when lab is empty.
+// The restart counts are kept from the events passed to Observe and start at zero when the metrics are created
+func NewLabMetrics(c *CLab, lab string) *LabMetrics {
+	return &LabMetrics{c: c, lab: lab, states: map[string]string{}, restarts: map[string]int{}}
+}
+
+func (m *LabMetrics) filters() []*types.GenericFilter {
+	if m.lab == "" {
+		return []*types.GenericFilter{{FilterType: "label", Field: ContainerlabLabel, Operator: "exists"}}
+	}
+	return []*types.GenericFilter{{FilterType: "label", Match: m.lab, Field: ContainerlabLabel, Operator: "="}}
+}
+
+// Run observes the node state changes reported by the container runtime until ctx is canceled,
+// it is used when the node events are not passed to Observe by the watch of the lab
+func (m *LabMetrics) Run(ctx context.Context) error {
+	events, errs := m.c.GlobalRuntime().Events(ctx, m.filters())
+	for ev := range events {
+		m.Observe(&NodeEvent{Container: ev.ContainerName, State: ev.State})
+	}
+	if err := <-errs; err != nil && ctx.Err() == nil {
+		return err
+	}
+	return nil
+}
+
+// Observe counts the restart of the node when its container is running again after it exited
+func (m *LabMetrics) Observe(ev *NodeEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch ev.State {
+	case types.EventRunning:
+		if m.states[ev.Container] == types.EventExited {
+			m.restarts[ev.Container]++
+		}
+	case types.EventExited:
+	case types.EventRemoved:
+		delete(m.states, ev.Container)
+		return
+	default:
+		return
+	}
+	m.states[ev.Container] = ev.State
+}
+
+// ServeHTTP writes the current metrics of the lab nodes in the Prometheus text format
+func (m *LabMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	samples, err := m.collect(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	buf := new(bytes.Buffer)
+	if err := writeMetrics(buf, metricFamilies(samples)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write(buf.Bytes())
+}
+
+// collect lists the node containers and queries the resource usage of the running ones in parallel
+func (m *LabMetrics) collect(ctx context.Context) ([]*nodeSample, error) {
+	containers, err := m.c.ListContainers(ctx, m.filters())
+	if err != nil {
+		return nil, err
+	}
+	samples := make([]*nodeSample, len(containers))
+	var wg sync.WaitGroup
+	m.mu.Lock()
+	for i, ctr := range containers {
+		s := &nodeSample{
+			lab:  ctr.Labels[ContainerlabLabel],
+			node: ctr.Labels[NodeNameLabel],
+			kind: ctr.Labels[NodeKindLabel],
+			up:   strings.EqualFold(ctr.State, "running"),
+		}
+		if len(ctr.Names) > 0 {
+			s.restarts = m.restarts[strings.TrimPrefix(ctr.Names[0], "/")]
+		}
+		samples[i] = s
+		if !s.up {
+			continue
+		}
+		wg.Add(1)
+		go func(s *nodeSample, id string) {
+			defer wg.Done()
+			st, err := m.c.GlobalRuntime().ContainerStats(ctx, id)
+			if err != nil {
+				log.Debugf("failed to get resource usage of node %s of lab %s: %v", s.node, s.lab, err)
+				return
+			}
+			s.stats = st
+		}(s, ctr.ID)
+	}
+	m.mu.Unlock()
+	wg.Wait()
+	return samples, nil
+}
+
+// metricFamilies returns the metrics of the node samples sorted by lab and node name
+func metricFamilies(samples []*nodeSample) []*metricFamily {
+	sort.Slice(samples, func(i, j int) bool {
+		if samples[i].lab != samples[j].lab {
+			return samples[i].lab < samples[j].lab
+		}
+		return samples[i].node < samples[j].node
+	})
+	up := &metricFamily{name: "clab_node_up", help: "Whether the node container is running.", typ: "gauge"}
+	restarts := &metricFamily{name: "clab_node_restarts_total", help: "Number of starts of the node container following its exit.", typ: "counter"}
+	cpu := &metricFamily{name: "clab_node_cpu_seconds_total", help: "CPU time consumed by the node container in seconds.", typ: "counter"}
+	mem := &metricFamily{name: "clab_node_memory_usage_bytes", help: "Memory used by the node container in bytes.", typ: "gauge"}
+	memLimit := &metricFamily{name: "clab_node_memory_limit_bytes", help: "Memory limit of the node container in bytes.", typ: "gauge"}
+	labNodes := &metricFamily{name: "clab_lab_nodes", help: "Number of the node containers of the lab.", typ: "gauge"}
+	labUp := &metricFamily{name: "clab_lab_nodes_up", help: "Number of the running node containers of the lab.", typ: "gauge"}
+
+	var labs []string
+	nodeCount, upCount := map[string]int{}, map[string]int{}
+	for _, s := range samples {
+		if _, ok := nodeCount[s.lab]; !ok {
+			labs = append(labs, s.lab)
+		}
+		nodeCount[s.lab]++
+		labels := []string{"lab", s.lab, "node", s.node, "kind", s.kind}
+		v := 0.0
+		if s.up {
+			v = 1
+			upCount[s.lab]++
+		}
+		up.samples = append(up.samples, metricSample{labels, v})
+		restarts.samples = append(restarts.samples, metricSample{labels, float64(s.restarts)})
+		if s.stats == nil {
+			continue
+		}
+		cpu.samples = append(cpu.samples, metricSample{labels, s.stats.CPUUsage.Seconds()})
+		mem.samples = append(mem.samples, metricSample{labels, float64(s.stats.MemoryUsage)})
+		if s.stats.MemoryLimit > 0 {
+			memLimit.samples = append(memLimit.samples, metricSample{labels, float64(s.stats.MemoryLimit)})
+		}
+	}
+	for _, l := range labs {
+		labNodes.samples = append(labNodes.samples, metricSample{[]string{"lab", l}, float64(nodeCount[l])})
+		labUp.samples = append(labUp.samples, metricSample{[]string{"lab", l}, float64(upCount[l])})
+	}
+	return []*metricFamily{up, restarts, cpu, mem, memLimit, labNodes, labUp}
+}
+
+// writeMetrics writes the metric families in the Prometheus text exposition format
+func writeMetrics(w io.Writer, families []*metricFamily) error {
+	for _, f := range families {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.typ); err != nil {
+			return err
+		}
+		for _, s := range f.samples {
+			pairs := make([]string, 0, len(s.labels)/2)
+			for i := 0; i+1 < len(s.labels); i += 2 {
+				pairs = append(pairs, fmt.Sprintf("%s=%q", s.labels[i], s.labels[i+1]))
+			}
+			if _, err := fmt.Fprintf(w, "%s{%s} %s\n", f.name, strings.Join(pairs, ","),
+				strconv.FormatFloat(s.value, 'g', -1, 64)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	samples := []*nodeSample{
+		{lab: "lab2", node: "r1", kind: "vr-sros"},
+		{lab: "lab1", node: "srl2", kind: "srl", up: true, restarts: 2},
+		{lab: "lab1", node: "srl1", kind: "srl", up: true, stats: &types.ContainerStats{
+			CPUUsage:    1500 * time.Millisecond,
+			MemoryUsage: 1 << 30,
+		}},
+	}
+	want := `# HELP clab_node_up Whether the node container is running.
+# TYPE clab_node_up gauge
+clab_node_up{lab="lab1",node="srl1",kind="srl"} 1
+clab_node_up{lab="lab1",node="srl2",kind="srl"} 1
+clab_node_up{lab="lab2",node="r1",kind="vr-sros"} 0
+# HELP clab_node_restarts_total Number of starts of the node container following its exit.
+# TYPE clab_node_restarts_total counter
+clab_node_restarts_total{lab="lab1",node="srl1",kind="srl"} 0
+clab_node_restarts_total{lab="lab1",node="srl2",kind="srl"} 2
+clab_node_restarts_total{lab="lab2",node="r1",kind="vr-sros"} 0
+# HELP clab_node_cpu_seconds_total CPU time consumed by the node container in seconds.
+# TYPE clab_node_cpu_seconds_total counter
+clab_node_cpu_seconds_total{lab="lab1",node="srl1",kind="srl"} 1.5
+# HELP clab_node_memory_usage_bytes Memory used by the node container in bytes.
+# TYPE clab_node_memory_usage_bytes gauge
+clab_node_memory_usage_bytes{lab="lab1",node="srl1",kind="srl"} 1.073741824e+09
+# HELP clab_node_memory_limit_bytes Memory limit of the node container in bytes.
+# TYPE clab_node_memory_limit_bytes gauge
+# HELP clab_lab_nodes Number of the node containers of the lab.
+# TYPE clab_lab_nodes gauge
+clab_lab_nodes{lab="lab1"} 2
+clab_lab_nodes{lab="lab2"} 1
+# HELP clab_lab_nodes_up Number of the running node containers of the lab.
+# TYPE clab_lab_nodes_up gauge
+clab_lab_nodes_up{lab="lab1"} 2
+clab_lab_nodes_up{lab="lab2"} 0
+`
+	buf := new(bytes.Buffer)
+	if err := writeMetrics(buf, metricFamilies(samples)); err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(want, buf.String()); d != "" {
+		t.Errorf("writeMetrics() mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestLabMetricsObserve(t *testing.T) {
+	m := NewLabMetrics(nil, "lab1")
+	for _, st := range []string{
+		types.EventRunning, types.EventExited, types.EventRunning, types.EventOOM,
+		types.EventExited, types.EventRunning, types.EventRemoved, types.EventRunning,
+	} {
+		m.Observe(&NodeEvent{Container: "clab-lab1-srl1", State: st})
+	}
+	if got := m.restarts["clab-lab1-srl1"]; got != 2 {
+		t.Errorf("got %d restarts, want 2", got)
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// containerNameRe matches the valid container names
+var containerNameRe = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// containerNameData is the data of the container name template
+type containerNameData struct {
+	// lab prefix, clab by default
+	Prefix string
+	// lab name
+	Lab string
+	// node name
+	Node string
+}
+
+// longName returns the container name of the node. The name is rendered with the container name template
+// of the lab settings, without the template the name is the node name prefixed with the lab name and the lab prefix
+func (c *CLab) longName(node string) (string, error) {
+	var prefix string
+	if c.Config.Prefix != nil {
+		prefix = *c.Config.Prefix
+	}
+	tmpl := c.Config.Settings.GetContainerName()
+	if tmpl == "" {
+		name := strings.Join([]string{c.Config.Name, node}, "-")
+		if prefix != "" {
+			name = strings.Join([]string{prefix, name}, "-")
+		}
+		return name, nil
+	}
+	t, err := template.New("container-name").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid container name template %q: %v", tmpl, err)
+	}
+	var b bytes.Buffer
+	if err := t.Execute(&b, &containerNameData{Prefix: prefix, Lab: c.Config.Name, Node: node}); err != nil {
+		return "", fmt.Errorf("invalid container name template %q: %v", tmpl, err)
+	}
+	name := strings.TrimSpace(b.String())
+	if !containerNameRe.MatchString(name) {
+		return "", fmt.Errorf("node %q: container name %q rendered with template %q is not a valid container name", node, name, tmpl)
+	}
+	return name, nil
+}
+
+// verifyContainerNames checks that the container names of the nodes are unique,
+// the container name template may render the same name for several nodes
+func (c *CLab) verifyContainerNames() error {
+	names := map[string]string{}
+	for _, node := range c.nodeNames() {
+		cfg := c.Nodes[node].Config()
+		if _, ok := noMgmtKinds[cfg.Kind]; ok {
+			continue
+		}
+		if other, ok := names[cfg.LongName]; ok {
+			return fmt.Errorf("nodes %q and %q have the same container name %q", other, node, cfg.LongName)
+		}
+		names[cfg.LongName] = node
+	}
+	return nil
+}
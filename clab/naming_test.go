@@ -0,0 +1,42 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestLongName(t *testing.T) {
+	prefix := func(s string) *string { return &s }
+	tests := map[string]struct {
+		prefix  *string
+		tmpl    string
+		want    string
+		wantErr bool
+	}{
+		"default":          {prefix: prefix("clab"), want: "clab-lab1-n1"},
+		"custom-prefix":    {prefix: prefix("c"), want: "c-lab1-n1"},
+		"no-prefix":        {prefix: prefix(""), want: "lab1-n1"},
+		"bare-node-name":   {prefix: prefix("clab"), tmpl: "{{ .Node }}", want: "n1"},
+		"template":         {prefix: prefix("c"), tmpl: "{{ .Lab }}_{{ .Node }}.{{ .Prefix }}", want: "lab1_n1.c"},
+		"invalid-name":     {prefix: prefix("clab"), tmpl: "{{ .Lab }}/{{ .Node }}", wantErr: true},
+		"unknown-field":    {prefix: prefix("clab"), tmpl: "{{ .Host }}-{{ .Node }}", wantErr: true},
+		"invalid-template": {prefix: prefix("clab"), tmpl: "{{ .Node", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &CLab{Config: &Config{Name: "lab1", Prefix: tc.prefix, Settings: &types.Settings{ContainerName: tc.tmpl}}}
+			got, err := c.longName("n1")
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("longName() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("longName() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
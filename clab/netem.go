@@ -0,0 +1,125 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/vishvananda/netlink"
+)
+
+// mgmtIfName is the name of the management interface of the nodes
+const mgmtIfName = "eth0"
+
+// SetMgmtNetem applies the mgmt-netem impairments of the lab nodes to their management interfaces
+func (c *CLab) SetMgmtNetem() {
+	for _, n := range c.Nodes {
+		cfg := n.Config()
+		if cfg.MgmtNetem == nil {
+			continue
+		}
+		switch {
+		case cfg.NetworkMode == "host":
+			log.Warnf("node %s uses the host network, mgmt-netem is not applied", cfg.ShortName)
+			continue
+		case c.remoteWiring():
+			log.Warnf("node %s runs on a remote host, mgmt-netem is not applied", cfg.ShortName)
+			continue
+		}
+		if err := setMgmtNetem(cfg); err != nil {
+			log.Errorf("failed to apply mgmt-netem to node %s: %v", cfg.ShortName, err)
+			continue
+		}
+		log.Infof("Applied mgmt-netem %+v to node %s", *cfg.MgmtNetem, cfg.ShortName)
+	}
+}
+
+// setMgmtNetem sets the netem qdisc on the management interface inside the node netns
+func setMgmtNetem(cfg *types.NodeConfig) error {
+	return SetInterfaceNetem(cfg.NSPath, mgmtIfName, cfg.MgmtNetem)
+}
+
+// InterfaceNetem holds the impairments of an interface
+type InterfaceNetem struct {
+	Interface string `json:"interface"`
+	// nil if the interface has no impairments
+	*types.Netem
+}
+
+// SetInterfaceNetem applies the impairments to the interface in the network namespace of nsPath,
+// replacing the impairments set before
+func SetInterfaceNetem(nsPath, iface string, n *types.Netem) error {
+	return doInterface(nsPath, iface, func(l netlink.Link) error {
+		return types.ApplyNetem(l, n)
+	})
+}
+
+// ResetInterfaceNetem removes the impairments from the interface in the network namespace of nsPath
+func ResetInterfaceNetem(nsPath, iface string) error {
+	return doInterface(nsPath, iface, types.ResetNetem)
+}
+
+// InterfacesNetem returns the impairments of the interfaces in the network namespace of nsPath
+// sorted by the interface name, the loopback interface is skipped
+func InterfacesNetem(nsPath string) ([]InterfaceNetem, error) {
+	netNS, err := ns.GetNS(nsPath)
+	if err != nil {
+		return nil, err
+	}
+	defer netNS.Close()
+	var res []InterfaceNetem
+	err = netNS.Do(func(_ ns.NetNS) error {
+		links, err := netlink.LinkList()
+		if err != nil {
+			return err
+		}
+		for _, l := range links {
+			if l.Attrs().Name == "lo" {
+				continue
+			}
+			n, err := types.LinkNetem(l)
+			if err != nil {
+				return fmt.Errorf("failed to get qdiscs of %s: %v", l.Attrs().Name, err)
+			}
+			res = append(res, InterfaceNetem{Interface: l.Attrs().Name, Netem: n})
+		}
+		return nil
+	})
+	sort.Slice(res, func(i, j int) bool { return res[i].Interface < res[j].Interface })
+	return res, err
+}
+
+// doInterface calls f with the interface iface in the network namespace of nsPath
+func doInterface(nsPath, iface string, f func(netlink.Link) error) error {
+	netNS, err := ns.GetNS(nsPath)
+	if err != nil {
+		return err
+	}
+	defer netNS.Close()
+	return netNS.Do(func(_ ns.NetNS) error {
+		link, err := netlink.LinkByName(iface)
+		if err != nil {
+			return fmt.Errorf("failed to lookup %s: %v", iface, err)
+		}
+		return f(link)
+	})
+}
+
+// verifyLinkNetem validates the impairments of the lab links
+func (c *CLab) verifyLinkNetem() error {
+	for _, l := range c.Links {
+		if l.Netem == nil {
+			continue
+		}
+		if err := l.Netem.Validate(); err != nil {
+			return fmt.Errorf("%s: %v", l, err)
+		}
+	}
+	return nil
+}
@@ -24,6 +24,12 @@ type vEthEndpoint struct {
 	NSPath    string // netns path
 	Bridge    string // bridge name a veth is destined to be connected to
 	OvsBridge string // ovs-bridge name a veth is destined to be connected to
+	// interface profile of the node the veth is placed to
+	Profile *types.InterfaceProfile
+	// VLANs of the bridge port
+	VLAN *types.LinkVLAN
+	// impairments of the traffic sent out of the veth
+	Netem *types.Netem
 }
 
 // CreateVirtualWiring creates the virtual topology between the containers
@@ -37,12 +43,16 @@ func (c *CLab) CreateVirtualWiring(l *types.Link) (err error) {
 		LinkName: l.A.EndpointName,
 		NSName:   l.A.Node.LongName,
 		NSPath:   l.A.Node.NSPath,
+		Profile:  l.A.Node.InterfaceProfile,
+		Netem:    l.Netem,
 	}
 	// veth side B
 	vB := vEthEndpoint{
 		LinkName: l.B.EndpointName,
 		NSName:   l.B.Node.LongName,
 		NSPath:   l.B.Node.NSPath,
+		Profile:  l.B.Node.InterfaceProfile,
+		Netem:    l.Netem,
 	}
 
 	// get random names for veth sides as they will be created in root netns first
@@ -60,6 +70,7 @@ func (c *CLab) CreateVirtualWiring(l *types.Link) (err error) {
 		} else {
 			vA.Bridge = c.Config.Mgmt.Bridge
 		}
+		vA.VLAN = l.VLAN
 		// veth endpoint destined to connect to the bridge in the host netns
 		// will not have a random name
 		ARndmName = l.A.EndpointName
@@ -69,12 +80,15 @@ func (c *CLab) CreateVirtualWiring(l *types.Link) (err error) {
 		} else {
 			vB.Bridge = c.Config.Mgmt.Bridge
 		}
+		vB.VLAN = l.VLAN
 		BRndmName = l.B.EndpointName
 	case l.A.Node.Kind == "ovs-bridge":
 		vA.OvsBridge = l.A.Node.ShortName
+		vA.VLAN = l.VLAN
 		ARndmName = l.A.EndpointName
 	case l.B.Node.Kind == "ovs-bridge":
 		vB.OvsBridge = l.B.Node.ShortName
+		vB.VLAN = l.VLAN
 		BRndmName = l.B.EndpointName
 	// for host connections random names shouldn't be used
 	case l.A.Node.Kind == "host":
@@ -155,6 +169,9 @@ func (veth *vEthEndpoint) setVethLink() error {
 	// host endpoints have a special NSPath value
 	// the host portion of veth doesn't need to be additionally processed
 	if veth.NSPath == hostNSPath {
+		if err := types.ApplyNetem(veth.Link, veth.Netem); err != nil {
+			return err
+		}
 		if err := netlink.LinkSetUp(veth.Link); err != nil {
 			return fmt.Errorf("failed to set %q up: %v",
 				veth.LinkName, err)
@@ -181,6 +198,19 @@ func (veth *vEthEndpoint) toNS() error {
 			return fmt.Errorf(
 				"failed to rename link: %v", err)
 		}
+		if err = types.ApplyInterfaceProfile(veth.Link, veth.Profile); err != nil {
+			return err
+		}
+		if veth.Netem != nil {
+			// the link index may change when the link is moved to the netns
+			l, err := netlink.LinkByName(veth.LinkName)
+			if err != nil {
+				return fmt.Errorf("failed to lookup %q: %v", veth.LinkName, err)
+			}
+			if err := types.ApplyNetem(l, veth.Netem); err != nil {
+				return err
+			}
+		}
 
 		if err = netlink.LinkSetUp(veth.Link); err != nil {
 			return fmt.Errorf("failed to set %q up: %v",
@@ -209,6 +239,15 @@ func (veth *vEthEndpoint) toBridge() error {
 			return fmt.Errorf("failed to connect %q to bridge %v: %v", veth.LinkName, veth.Bridge, err)
 		}
 
+		if veth.VLAN != nil {
+			if err := setBridgePortVLANs(veth.Link, veth.VLAN); err != nil {
+				return fmt.Errorf("failed to set VLANs of %q port of bridge %v: %v", veth.LinkName, veth.Bridge, err)
+			}
+		}
+		if err := types.ApplyNetem(veth.Link, veth.Netem); err != nil {
+			return err
+		}
+
 		if err = netlink.LinkSetUp(veth.Link); err != nil {
 			return fmt.Errorf("failed to set %q up: %v", veth.LinkName, err)
 		}
@@ -217,6 +256,24 @@ func (veth *vEthEndpoint) toBridge() error {
 	return err
 }
 
+// setBridgePortVLANs sets the VLANs of the port of the VLAN filtering linux bridge
+func setBridgePortVLANs(link netlink.Link, v *types.LinkVLAN) error {
+	if v.Access != 0 {
+		// access VLAN replaces the default VLAN 1 as the untagged VLAN of the port,
+		// the deletion error is ignored as the bridge may have no default VLAN
+		_ = netlink.BridgeVlanDel(link, 1, true, true, false, true)
+		if err := netlink.BridgeVlanAdd(link, uint16(v.Access), true, true, false, true); err != nil {
+			return err
+		}
+	}
+	for _, vid := range v.Trunk {
+		if err := netlink.BridgeVlanAdd(link, uint16(vid), false, false, false, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // DeleteNetnsSymlinks deletes the symlink file created for each container netns
 func (c *CLab) DeleteNetnsSymlinks() (err error) {
 	for _, node := range c.Nodes {
@@ -0,0 +1,159 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/cert"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// ParseLinks parses the links in the `nodeA:ifaceA<->nodeB:ifaceB` notation
+func ParseLinks(links []string) ([]*types.LinkConfig, error) {
+	res := make([]*types.LinkConfig, 0, len(links))
+	for _, l := range links {
+		eps := strings.Split(l, "<->")
+		if len(eps) != 2 {
+			return nil, fmt.Errorf("link %q has wrong syntax, expected nodeA:ifaceA<->nodeB:ifaceB", l)
+		}
+		for i, e := range eps {
+			eps[i] = strings.TrimSpace(e)
+			if parts := strings.Split(eps[i], ":"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				return nil, fmt.Errorf("endpoint %q of link %q has wrong syntax, expected node:iface", e, l)
+			}
+		}
+		res = append(res, &types.LinkConfig{Endpoints: eps})
+	}
+	return res, nil
+}
+
+// cloneNode adds the node name to the topology with the definition of the like node.
+// The management addresses and host ports of the like node are not cloned.
+// Links must connect the new node with the nodes of the topology over unused interfaces
+func (c *CLab) cloneNode(name, like string, links []*types.LinkConfig) error {
+	def, ok := c.Config.Topology.Nodes[like]
+	if !ok {
+		return fmt.Errorf("node %q is not found in the topology", like)
+	}
+	if _, ok := c.Config.Topology.Nodes[name]; ok {
+		return fmt.Errorf("node %q already exists in the topology", name)
+	}
+	if rangeRe.MatchString(name) {
+		return fmt.Errorf("node name %q can't be a range", name)
+	}
+
+	used := map[string]struct{}{}
+	for _, lc := range c.Config.Topology.Links {
+		for _, e := range lc.Endpoints {
+			used[e] = struct{}{}
+		}
+	}
+	for _, l := range links {
+		newNode := false
+		for _, e := range l.Endpoints {
+			n := strings.SplitN(e, ":", 2)[0]
+			switch _, ok := c.Config.Topology.Nodes[n]; {
+			case n == name:
+				newNode = true
+			case !ok && n != "host":
+				return fmt.Errorf("node %q of link %q is not found in the topology", n, l.Endpoints)
+			}
+			if _, ok := used[e]; ok {
+				return fmt.Errorf("endpoint %q is already used by another link", e)
+			}
+			used[e] = struct{}{}
+		}
+		if !newNode {
+			return fmt.Errorf("link %q doesn't connect the node %q", l.Endpoints, name)
+		}
+	}
+
+	clone, err := copyNodeDefinition(def)
+	if err != nil {
+		return err
+	}
+	clone.MgmtIPv4 = ""
+	clone.MgmtIPv6 = ""
+	clone.Ports = nil
+
+	// the clone runs on the runtime of the like node
+	rt := c.globalRuntime
+	if r := c.Nodes[like].GetRuntime(); r != nil {
+		rt = r.GetName()
+	}
+	c.Config.Topology.Nodes[name] = clone
+	if err := c.NewNode(name, rt, clone, len(c.Nodes)); err != nil {
+		return err
+	}
+	for _, l := range links {
+		c.Config.Topology.Links = append(c.Config.Topology.Links, l)
+		c.Links[len(c.Links)] = c.NewLink(l)
+	}
+	return nil
+}
+
+// AddNode deploys the node name cloned from the like node to the running lab,
+// and wires the links of the new node. The other nodes of the lab are not touched
+func (c *CLab) AddNode(ctx context.Context, name, like string, links []*types.LinkConfig) error {
+	first := len(c.Links)
+	if err := c.cloneNode(name, like, links); err != nil {
+		return err
+	}
+	n := c.Nodes[name]
+	cfg := n.Config()
+
+	if cert.NeedsCert(cfg) {
+		if err := c.InitCA(); err != nil {
+			return err
+		}
+		if err := cert.CreateRootCA(c.Config.Name, c.Dir.LabCARoot, c.Nodes); err != nil {
+			return err
+		}
+	}
+	if err := c.InstallCABundle(); err != nil {
+		return err
+	}
+	if err := c.CreatePersistDirs(); err != nil {
+		return err
+	}
+
+	log.Infof("Adding node %s cloned from %s", name, like)
+	if err := n.PreDeploy(c.Config.Name, c.Dir.LabCA, c.Dir.LabCARoot); err != nil {
+		return fmt.Errorf("failed pre-deploy phase for node %q: %v", name, err)
+	}
+	if err := c.syncLabDirBinds(ctx, n); err != nil {
+		return fmt.Errorf("failed pre-deploy phase for node %q: %v", name, err)
+	}
+	if err := n.Deploy(ctx); err != nil {
+		return fmt.Errorf("failed deploy phase for node %q: %v", name, err)
+	}
+
+	for i := first; i < len(c.Links); i++ {
+		l := c.Links[i]
+		for _, ep := range []*types.Endpoint{l.A, l.B} {
+			if ep.Node.NSPath != "" || ep.Node.Kind == "bridge" || ep.Node.Kind == "ovs-bridge" {
+				continue
+			}
+			// peers of the new node are running already
+			var err error
+			if ep.Node.NSPath, err = c.Nodes[ep.Node.ShortName].GetRuntime().GetNSPath(ctx, ep.Node.LongName); err != nil {
+				return fmt.Errorf("failed to get netns of node %q: %v", ep.Node.ShortName, err)
+			}
+		}
+		if err := c.wireLink(ctx, l); err != nil {
+			return err
+		}
+	}
+	if err := c.RemoveWiringAgent(ctx); err != nil {
+		log.Warnf("failed to remove wiring agent: %v", err)
+	}
+	c.setNodeRoutes(cfg)
+
+	return n.PostDeploy(ctx, c.Nodes)
+}
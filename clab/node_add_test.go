@@ -0,0 +1,125 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestParseLinks(t *testing.T) {
+	tests := map[string]struct {
+		links   []string
+		want    []*types.LinkConfig
+		wantErr bool
+	}{
+		"valid": {
+			links: []string{"leaf5:e1-49<->spine1:e1-5", "leaf5:e1-50 <-> spine2:e1-5"},
+			want: []*types.LinkConfig{
+				{Endpoints: []string{"leaf5:e1-49", "spine1:e1-5"}},
+				{Endpoints: []string{"leaf5:e1-50", "spine2:e1-5"}},
+			},
+		},
+		"no-separator": {
+			links:   []string{"leaf5:e1-49,spine1:e1-5"},
+			wantErr: true,
+		},
+		"no-iface": {
+			links:   []string{"leaf5<->spine1:e1-5"},
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseLinks(tc.links)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("wanted error %v, got %v", tc.wantErr, err)
+			}
+			if d := cmp.Diff(tc.want, got); !tc.wantErr && d != "" {
+				t.Fatalf("links mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestCloneNode(t *testing.T) {
+	tests := map[string]struct {
+		name    string
+		like    string
+		links   []string
+		wantErr bool
+	}{
+		"clone": {
+			name:  "leaf3",
+			like:  "leaf1",
+			links: []string{"leaf3:eth1<->spine1:e1-3"},
+		},
+		"unknown-like": {
+			name:    "leaf3",
+			like:    "leaf9",
+			wantErr: true,
+		},
+		"existing-name": {
+			name:    "leaf2",
+			like:    "leaf1",
+			wantErr: true,
+		},
+		"used-endpoint": {
+			name:    "leaf3",
+			like:    "leaf1",
+			links:   []string{"leaf3:eth1<->spine1:e1-1"},
+			wantErr: true,
+		},
+		"unrelated-link": {
+			name:    "leaf3",
+			like:    "leaf1",
+			links:   []string{"leaf2:eth5<->spine1:e1-3"},
+			wantErr: true,
+		},
+		"unknown-peer": {
+			name:    "leaf3",
+			like:    "leaf1",
+			links:   []string{"leaf3:eth1<->spine9:e1-3"},
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewContainerLab(WithTopoFile("test_data/topo14.yml"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			links, err := ParseLinks(tc.links)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = c.cloneNode(tc.name, tc.like, links)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("wanted error %v, got %v", tc.wantErr, err)
+			}
+			if tc.wantErr {
+				return
+			}
+
+			n, ok := c.Nodes[tc.name]
+			if !ok {
+				t.Fatalf("node %s is not added", tc.name)
+			}
+			like := c.Nodes[tc.like].Config()
+			if n.Config().Kind != like.Kind || n.Config().Group != like.Group {
+				t.Errorf("wanted kind %s group %s, got kind %s group %s", like.Kind, like.Group, n.Config().Kind, n.Config().Group)
+			}
+			if n.Config().LongName != "clab-topo14-"+tc.name {
+				t.Errorf("unexpected long name %s", n.Config().LongName)
+			}
+			l := c.Links[len(c.Links)-1]
+			if l.A.Node != n.Config() || l.B.Node.ShortName != "spine1" || l.B.EndpointName != "e1-3" {
+				t.Errorf("unexpected link %s", l)
+			}
+		})
+	}
+}
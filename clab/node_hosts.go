@@ -0,0 +1,91 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes"
+)
+
+// labHostsFile is the hosts file with the entries of the lab nodes in the lab directory
+const labHostsFile = "hosts"
+
+// updateHostsScript replaces the block of the lab entries in /etc/hosts of a container with the block passed in $1,
+// the block is delimited by the lines passed in $2 and $3.
+// /etc/hosts is rewritten in place as it is usually bind mounted by the container runtime
+const updateHostsScript = `h=$(awk -v s="$2" -v e="$3" '$0==s{skip=1;next} $0==e{skip=0;next} !skip' /etc/hosts) && printf '%s\n%s' "$h" "$1" > /etc/hosts`
+
+// nodeHostsEntries returns the block of /etc/hosts entries mapping the long and short names of the lab nodes
+// to their management addresses, the IPv4 entries go first
+func (c *CLab) nodeHostsEntries() []byte {
+	names := make([]string, 0, len(c.Nodes))
+	for name := range c.Nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	entries := new(bytes.Buffer)
+	v6entries := new(bytes.Buffer)
+	fmt.Fprintf(entries, clabHostEntryPrefix+"\n", c.Config.Name)
+	for _, name := range names {
+		cfg := c.Nodes[name].Config()
+		if _, ok := noMgmtKinds[cfg.Kind]; ok || cfg.NetworkMode != "" {
+			continue
+		}
+		if cfg.MgmtIPv4Address != "" {
+			fmt.Fprintf(entries, "%s\t%s %s\n", cfg.MgmtIPv4Address, cfg.LongName, cfg.ShortName)
+		}
+		if cfg.MgmtIPv6Address != "" {
+			fmt.Fprintf(v6entries, "%s\t%s %s\n", cfg.MgmtIPv6Address, cfg.LongName, cfg.ShortName)
+		}
+	}
+	entries.Write(v6entries.Bytes())
+	fmt.Fprintf(entries, clabHostEntryPostfix+"\n", c.Config.Name)
+	return entries.Bytes()
+}
+
+// PopulateNodeHosts adds the entries of the lab nodes to /etc/hosts of the linux nodes,
+// so that the nodes reach each other by name. The entries added by the previous runs are replaced.
+// The entries are also written to the hosts file in the lab directory for the nodes of the other kinds, e.g. VMs.
+// Must be called after the management addresses of the nodes are known
+func (c *CLab) PopulateNodeHosts(ctx context.Context) error {
+	entries := c.nodeHostsEntries()
+	p := filepath.Join(c.Dir.Lab, labHostsFile)
+	if err := ioutil.WriteFile(p, entries, 0644); err != nil {
+		return fmt.Errorf("failed to write lab hosts file: %v", err)
+	}
+
+	wg := new(sync.WaitGroup)
+	for _, n := range c.Nodes {
+		cfg := n.Config()
+		if cfg.Kind != nodes.NodeKindLinux || cfg.NetworkMode != "" {
+			continue
+		}
+		wg.Add(1)
+		go func(n nodes.Node) {
+			defer wg.Done()
+			cfg := n.Config()
+			cmd := []string{"sh", "-c", updateHostsScript, "sh", string(entries),
+				fmt.Sprintf(clabHostEntryPrefix, c.Config.Name), fmt.Sprintf(clabHostEntryPostfix, c.Config.Name)}
+			_, stderr, err := n.GetRuntime().Exec(ctx, cfg.LongName, cmd)
+			if err == nil && len(stderr) != 0 {
+				err = fmt.Errorf("%s", bytes.TrimSpace(stderr))
+			}
+			if err != nil {
+				log.Warnf("failed to add lab hosts entries to node %s: %v", cfg.ShortName, err)
+			}
+		}(n)
+	}
+	wg.Wait()
+	return nil
+}
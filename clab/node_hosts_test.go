@@ -0,0 +1,34 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNodeHostsEntries(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo11.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n1 := c.Nodes["node1"].Config()
+	n1.MgmtIPv4Address, n1.MgmtIPv6Address = "172.20.20.2", "2001:172:20:20::2"
+	n2 := c.Nodes["node2"].Config()
+	n2.MgmtIPv4Address = "172.20.20.3"
+	// the nodes sharing a network namespace are not reachable by their own address
+	n3 := c.Nodes["node3"].Config()
+	n3.MgmtIPv4Address, n3.NetworkMode = "172.20.20.4", "host"
+
+	want := "###### CLAB-topo11-START ######\n" +
+		"172.20.20.2\tclab-topo11-node1 node1\n" +
+		"172.20.20.3\tclab-topo11-node2 node2\n" +
+		"2001:172:20:20::2\tclab-topo11-node1 node1\n" +
+		"###### CLAB-topo11-END ######\n"
+	if d := cmp.Diff(want, string(c.nodeHostsEntries())); d != "" {
+		t.Errorf("hosts entries mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// RedeployNode destroys and recreates the container of the node name, e.g. a node which VM crashed,
+// without saving its configuration. The links of the node are rewired into the netns of its peers,
+// the pre-deploy and post-deploy phases of the node kind are run again and the node is waited on to become ready
+func (c *CLab) RedeployNode(ctx context.Context, name string) error {
+	n, ok := c.Nodes[name]
+	if !ok {
+		return fmt.Errorf("node %q is not found in the topology", name)
+	}
+	cfg := n.Config()
+	if _, ok := noMgmtKinds[cfg.Kind]; ok {
+		return fmt.Errorf("node %q of kind %s has no container to redeploy", name, cfg.Kind)
+	}
+	labels := []*types.GenericFilter{
+		{FilterType: "label", Match: c.Config.Name, Field: ContainerlabLabel, Operator: "="},
+		{FilterType: "label", Match: name, Field: NodeNameLabel, Operator: "="},
+	}
+	containers, err := n.GetRuntime().ListContainers(ctx, labels)
+	if err != nil {
+		return err
+	}
+	// the container of the node might have been removed already
+	var cont *types.GenericContainer
+	if len(containers) > 0 {
+		cont = &containers[0]
+	}
+	pinned, err := c.verifyImagesTrust(ctx, map[string]string{cfg.Image: n.GetRuntime().GetName()})
+	if err != nil {
+		return err
+	}
+	if p, ok := pinned[cfg.Image]; ok {
+		cfg.Image = p
+	}
+	if err := n.GetRuntime().PullImageIfRequired(ctx, cfg.Image); err != nil {
+		return err
+	}
+	log.Infof("Redeploying node %s", name)
+	return c.recreateNode(ctx, n, cont)
+}
+
+// recreateNode replaces the container cont of the node with a new container, rewires the links of the node
+// and waits for the node to become ready. The new container keeps the management addresses of the replaced one
+func (c *CLab) recreateNode(ctx context.Context, n nodes.Node, cont *types.GenericContainer) error {
+	cfg := n.Config()
+	name := cfg.ShortName
+	if cont != nil {
+		keepMgmtAddresses(cfg, cont)
+		if err := n.Delete(ctx); err != nil {
+			return fmt.Errorf("failed to delete node %q: %v", name, err)
+		}
+	}
+	cfg.NSPath = ""
+	if err := n.PreDeploy(c.Config.Name, c.Dir.LabCA, c.Dir.LabCARoot); err != nil {
+		return fmt.Errorf("failed pre-deploy phase for node %q: %v", name, err)
+	}
+	if err := c.syncLabDirBinds(ctx, n); err != nil {
+		return fmt.Errorf("failed pre-deploy phase for node %q: %v", name, err)
+	}
+	if err := n.Deploy(ctx); err != nil {
+		return fmt.Errorf("failed deploy phase for node %q: %v", name, err)
+	}
+
+	var err error
+	for _, l := range c.nodeLinks(name) {
+		for _, ep := range []*types.Endpoint{l.A, l.B} {
+			if ep.Node.NSPath != "" || ep.Node.Kind == "bridge" || ep.Node.Kind == "ovs-bridge" {
+				continue
+			}
+			if ep.Node.NSPath, err = c.Nodes[ep.Node.ShortName].GetRuntime().GetNSPath(ctx, ep.Node.LongName); err != nil {
+				return fmt.Errorf("failed to get netns of node %q: %v", ep.Node.ShortName, err)
+			}
+		}
+		if err := c.wireLink(ctx, l); err != nil {
+			return err
+		}
+	}
+	if err := c.RemoveWiringAgent(ctx); err != nil {
+		log.Warnf("failed to remove wiring agent: %v", err)
+	}
+	if cfg.MgmtNetem != nil && !c.remoteWiring() {
+		if err := setMgmtNetem(cfg); err != nil {
+			log.Errorf("failed to apply mgmt-netem to node %s: %v", name, err)
+		}
+	}
+
+	c.setNodeRoutes(cfg)
+
+	if err := n.PostDeploy(ctx, c.Nodes); err != nil {
+		return fmt.Errorf("failed post-deploy phase for node %q: %v", name, err)
+	}
+	return WaitForNode(ctx, n, true)
+}
@@ -0,0 +1,105 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// nodeLinks returns the links of the node name,
+// the links of a vrnetlab node are sorted by the index of its interfaces to be wired in the topology order
+func (c *CLab) nodeLinks(name string) []*types.Link {
+	var res []*types.Link
+	idx := map[*types.Link]int{}
+	for i := 0; i < len(c.Links); i++ {
+		l := c.Links[i]
+		switch name {
+		case l.A.Node.ShortName:
+			idx[l], _ = vrIfIndex(l.A.EndpointName)
+		case l.B.Node.ShortName:
+			idx[l], _ = vrIfIndex(l.B.EndpointName)
+		default:
+			continue
+		}
+		res = append(res, l)
+	}
+	if n, ok := c.Nodes[name]; ok && nodes.IsVrKind(n.Config().Kind) {
+		sort.SliceStable(res, func(i, j int) bool { return idx[res[i]] < idx[res[j]] })
+	}
+	return res
+}
+
+// keepMgmtAddresses sets the management addresses of the node config to the addresses of the running container,
+// so that the replaced container is reachable on the same addresses
+func keepMgmtAddresses(cfg *types.NodeConfig, cont *types.GenericContainer) {
+	if cfg.NetworkMode != "" || cont.NetworkSettings == nil {
+		return
+	}
+	if cfg.MgmtIPv4Address == "" && cont.NetworkSettings.IPv4addr != "" {
+		cfg.MgmtIPv4Address = cont.NetworkSettings.IPv4addr
+		cfg.MgmtIPv4PrefixLength = cont.NetworkSettings.IPv4pLen
+	}
+	if cfg.MgmtIPv6Address == "" && cont.NetworkSettings.IPv6addr != "" {
+		cfg.MgmtIPv6Address = cont.NetworkSettings.IPv6addr
+		cfg.MgmtIPv6PrefixLength = cont.NetworkSettings.IPv6pLen
+	}
+}
+
+// UpgradeNode replaces the container of the running node name with a container of the image.
+// The running config of the node is saved first, the replaced node starts with the saved config
+// for the kinds keeping their config in the lab directory and with its startup-config otherwise.
+// The links of the node are rewired and the node is waited on to become ready
+func (c *CLab) UpgradeNode(ctx context.Context, name, image string) error {
+	n, ok := c.Nodes[name]
+	if !ok {
+		return fmt.Errorf("node %q is not found in the topology", name)
+	}
+	cfg := n.Config()
+	if _, ok := noMgmtKinds[cfg.Kind]; ok {
+		return fmt.Errorf("node %q of kind %s has no image to upgrade", name, cfg.Kind)
+	}
+	if image == cfg.Image {
+		log.Warnf("node %s is replaced with the same image %s", name, image)
+	}
+	r := n.GetRuntime()
+
+	labels := []*types.GenericFilter{
+		{FilterType: "label", Match: c.Config.Name, Field: ContainerlabLabel, Operator: "="},
+		{FilterType: "label", Match: name, Field: NodeNameLabel, Operator: "="},
+	}
+	containers, err := r.ListContainers(ctx, labels)
+	if err != nil {
+		return err
+	}
+	if len(containers) == 0 || containers[0].State != "running" {
+		return fmt.Errorf("node %q is not running", name)
+	}
+
+	pinned, err := c.verifyImagesTrust(ctx, map[string]string{image: r.GetName()})
+	if err != nil {
+		return err
+	}
+	if p, ok := pinned[image]; ok {
+		image = p
+	}
+	if err := r.PullImageIfRequired(ctx, image); err != nil {
+		return err
+	}
+
+	log.Infof("Saving configuration of node %s", name)
+	if err := n.SaveConfig(ctx); err != nil {
+		return fmt.Errorf("failed to save configuration of node %q: %v", name, err)
+	}
+
+	log.Infof("Replacing node %s image %s with %s", name, cfg.Image, image)
+	cfg.Image = image
+	return c.recreateNode(ctx, n, &containers[0])
+}
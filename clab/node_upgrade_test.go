@@ -0,0 +1,81 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestNodeLinks(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo14.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string][]string{
+		"spine1": {"spine1:e1-1", "spine1:e1-2"},
+		"leaf1":  {"leaf1:eth1", "leaf1:eth2"},
+		"leaf2":  {"leaf2:eth1"},
+	}
+	for name, want := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			for _, l := range c.nodeLinks(name) {
+				for _, ep := range []*types.Endpoint{l.A, l.B} {
+					if ep.Node.ShortName == name {
+						got = append(got, endpointName(ep))
+					}
+				}
+			}
+			if d := cmp.Diff(want, got); d != "" {
+				t.Errorf("diff (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestKeepMgmtAddresses(t *testing.T) {
+	cont := &types.GenericContainer{
+		NetworkSettings: &types.GenericMgmtIPs{
+			IPv4addr: "172.20.20.5",
+			IPv4pLen: 24,
+			IPv6addr: "2001:172:20:20::5",
+			IPv6pLen: 64,
+		},
+	}
+	tests := map[string]struct {
+		cfg  *types.NodeConfig
+		want *types.NodeConfig
+	}{
+		"dynamic": {
+			cfg: &types.NodeConfig{},
+			want: &types.NodeConfig{
+				MgmtIPv4Address: "172.20.20.5", MgmtIPv4PrefixLength: 24,
+				MgmtIPv6Address: "2001:172:20:20::5", MgmtIPv6PrefixLength: 64,
+			},
+		},
+		"static_ipv4": {
+			cfg: &types.NodeConfig{MgmtIPv4Address: "172.20.20.100"},
+			want: &types.NodeConfig{
+				MgmtIPv4Address: "172.20.20.100",
+				MgmtIPv6Address: "2001:172:20:20::5", MgmtIPv6PrefixLength: 64,
+			},
+		},
+		"network_mode": {
+			cfg:  &types.NodeConfig{NetworkMode: "host"},
+			want: &types.NodeConfig{NetworkMode: "host"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			keepMgmtAddresses(tc.cfg, cont)
+			if d := cmp.Diff(tc.want, tc.cfg); d != "" {
+				t.Errorf("diff (-want +got):\n%s", d)
+			}
+		})
+	}
+}
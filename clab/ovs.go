@@ -6,9 +6,11 @@ package clab
 
 import (
 	"fmt"
+	"os/exec"
 
 	"github.com/containernetworking/plugins/pkg/ns"
 	"github.com/digitalocean/go-openvswitch/ovs"
+	"github.com/srl-labs/containerlab/types"
 	"github.com/vishvananda/netlink"
 )
 
@@ -34,6 +36,15 @@ func (veth *vEthEndpoint) toOvsBridge() error {
 			return err
 		}
 
+		if veth.VLAN != nil {
+			if out, err := exec.Command("ovs-vsctl", veth.VLAN.OVSPortArgs(veth.LinkName)...).CombinedOutput(); err != nil {
+				return fmt.Errorf("failed to set VLANs of %q port of ovs bridge %q: %v: %s", veth.LinkName, veth.OvsBridge, err, out)
+			}
+		}
+		if err := types.ApplyNetem(veth.Link, veth.Netem); err != nil {
+			return err
+		}
+
 		if err = netlink.LinkSetUp(veth.Link); err != nil {
 			return fmt.Errorf("failed to set %q up: %v", veth.LinkName, err)
 		}
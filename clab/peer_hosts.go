@@ -0,0 +1,64 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"net"
+	"regexp"
+
+	"github.com/srl-labs/containerlab/types"
+)
+
+// chars not allowed in host names
+var hostNameRe = regexp.MustCompile(`[^a-zA-Z0-9-]`)
+
+// PeerHosts returns /etc/hosts entries in the `name:ip` format for the link peers of a node.
+// Peer addresses are taken from the interfaces configuration of the peer nodes.
+// Every address of a peer interface is named `<peer>-<interface>`,
+// the first address of each family is also reachable with the peer name.
+func (c *CLab) PeerHosts(node *types.NodeConfig) []string {
+	var hosts []string
+	// peers which name was already used, per address family
+	named := map[string]struct{}{}
+
+	// links are processed in the order of their definition in the topology file
+	for i := 0; i < len(c.Links); i++ {
+		l, ok := c.Links[i]
+		if !ok {
+			continue
+		}
+		var peer *types.Endpoint
+		switch node {
+		case l.A.Node:
+			peer = l.B
+		case l.B.Node:
+			peer = l.A
+		default:
+			continue
+		}
+		ifCfg, ok := peer.Node.Interfaces[peer.EndpointName]
+		if !ok || ifCfg == nil {
+			continue
+		}
+		ifName := peer.Node.ShortName + "-" + hostNameRe.ReplaceAllString(peer.EndpointName, "-")
+		for _, a := range append(ifCfg.IPv4, ifCfg.IPv6...) {
+			ip, _, err := net.ParseCIDR(a)
+			if err != nil {
+				continue
+			}
+			hosts = append(hosts, ifName+":"+ip.String())
+
+			family := peer.Node.ShortName + "/4"
+			if ip.To4() == nil {
+				family = peer.Node.ShortName + "/6"
+			}
+			if _, ok := named[family]; !ok {
+				named[family] = struct{}{}
+				hosts = append(hosts, peer.Node.ShortName+":"+ip.String())
+			}
+		}
+	}
+	return hosts
+}
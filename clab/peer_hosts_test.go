@@ -0,0 +1,63 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPeerHosts(t *testing.T) {
+	tests := map[string]struct {
+		got  string
+		node string
+		want []string
+	}{
+		"two_families": {
+			got:  "test_data/topo11.yml",
+			node: "node2",
+			want: []string{
+				"node1-eth1:192.168.1.1",
+				"node1:192.168.1.1",
+				"node1-eth1:2001:db8:1::1",
+				"node1:2001:db8:1::1",
+			},
+		},
+		"multiple_links": {
+			got:  "test_data/topo11.yml",
+			node: "node3",
+			want: []string{
+				"node1-eth2:192.168.2.1",
+				"node1:192.168.2.1",
+			},
+		},
+		"peers_without_addresses": {
+			got:  "test_data/topo11.yml",
+			node: "node1",
+			want: []string{
+				"node2-eth1:192.168.1.2",
+				"node2:192.168.1.2",
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewContainerLab(WithTopoFile(tc.got))
+			if err != nil {
+				t.Fatal(err)
+			}
+			n := c.Nodes[tc.node].Config()
+			if !n.PeerHosts {
+				t.Fatalf("peer-hosts is not set for node %s", tc.node)
+			}
+			got := c.PeerHosts(n)
+			if !cmp.Equal(got, tc.want) {
+				t.Errorf("failed at '%s', expected\n%v, got\n%+v", name, tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// persistDir is the subdirectory of the node directory holding the persisted container paths
+const persistDir = "persist"
+
+// volumeNameRe matches the valid names of the container volumes
+var volumeNameRe = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// persistMount is a container path retained across redeploys
+type persistMount struct {
+	// host directory or volume name
+	src string
+	// path in the container
+	dst string
+	// src is a named volume
+	volume bool
+}
+
+// parsePersist parses the persist entries of the node with the nodeDir directory.
+// A path is persisted in the persist subdirectory of the node directory,
+// e.g. /var/lib/data is kept in <nodeDir>/persist/var/lib/data.
+// A path prefixed with a volume name, e.g. data:/var/lib/data, is persisted in the named volume,
+// the volume name is prefixed with volPrefix so that the labs don't share the volumes, e.g. clab-lab1-data
+func parsePersist(persist []string, nodeDir, volPrefix string) ([]*persistMount, error) {
+	res := make([]*persistMount, 0, len(persist))
+	dsts := map[string]struct{}{}
+	for _, p := range persist {
+		m := &persistMount{dst: p}
+		if i := strings.Index(p, ":"); i >= 0 {
+			m.src, m.dst, m.volume = p[:i], p[i+1:], true
+			if !volumeNameRe.MatchString(m.src) {
+				return nil, fmt.Errorf("persist entry %q has invalid volume name %q", p, m.src)
+			}
+			if volPrefix != "" {
+				m.src = volPrefix + "-" + m.src
+			}
+		}
+		if !path.IsAbs(m.dst) {
+			return nil, fmt.Errorf("persist entry %q must have an absolute container path", p)
+		}
+		m.dst = path.Clean(m.dst)
+		if m.dst == "/" {
+			return nil, fmt.Errorf("persist entry %q can't persist the container root", p)
+		}
+		if _, ok := dsts[m.dst]; ok {
+			return nil, fmt.Errorf("container path %q is persisted more than once", m.dst)
+		}
+		dsts[m.dst] = struct{}{}
+		if !m.volume {
+			m.src = filepath.Join(nodeDir, persistDir, filepath.FromSlash(m.dst))
+		}
+		res = append(res, m)
+	}
+	return res, nil
+}
+
+// persistBinds returns the bind mounts of the persisted paths
+func persistBinds(mounts []*persistMount) []string {
+	binds := make([]string, 0, len(mounts))
+	for _, m := range mounts {
+		binds = append(binds, m.src+":"+m.dst)
+	}
+	return binds
+}
+
+// CreatePersistDirs creates the host directories of the persisted paths of the nodes.
+// Existing directories are kept with their content, so that the persisted data survives redeploys
+func (c *CLab) CreatePersistDirs() error {
+	for name, n := range c.Nodes {
+		mounts, err := parsePersist(c.Config.Topology.GetNodePersist(name), n.Config().LabDir, c.persistVolPrefix())
+		if err != nil {
+			return err
+		}
+		for _, m := range mounts {
+			if m.volume {
+				continue
+			}
+			log.Debugf("creating persist directory %s of node %s", m.src, name)
+			if err := os.MkdirAll(m.src, 0755); err != nil {
+				return fmt.Errorf("failed to create persist directory of node %s: %v", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// DeletePersistVolumes removes the named volumes of the persisted paths of the nodes,
+// the persist directories are removed along with the node directories
+func (c *CLab) DeletePersistVolumes(ctx context.Context) {
+	deleted := map[string]struct{}{}
+	for name, n := range c.Nodes {
+		mounts, err := parsePersist(c.Config.Topology.GetNodePersist(name), n.Config().LabDir, c.persistVolPrefix())
+		if err != nil {
+			log.Errorf("failed to parse persist entries of node %s: %v", name, err)
+			continue
+		}
+		for _, m := range mounts {
+			if _, ok := deleted[m.src]; !m.volume || ok {
+				continue
+			}
+			deleted[m.src] = struct{}{}
+			log.Debugf("removing persist volume %s of node %s", m.src, name)
+			if err := n.GetRuntime().DeleteVolume(ctx, m.src); err != nil {
+				log.Errorf("failed to remove persist volume %s of node %s: %v", m.src, name, err)
+			}
+		}
+	}
+}
+
+// persistVolPrefix returns the prefix of the names of the persist volumes of the lab, the lab directory name
+func (c *CLab) persistVolPrefix() string {
+	return filepath.Base(c.Dir.Lab)
+}
@@ -0,0 +1,74 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPersistBinds(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clab-persist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c, err := NewContainerLab(WithLabDirPath(dir), WithTopoFile("test_data/topo18.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	labDir := filepath.Join(dir, "clab-topo18")
+	lic, _ := filepath.Abs("test_data/node1.lic")
+
+	tests := map[string][]string{
+		"node1": {filepath.Join(labDir, "node1", "persist", "var", "lib", "data") + ":/var/lib/data"},
+		"node2": {
+			lic + ":/node1.lic",
+			filepath.Join(labDir, "node2", "persist", "root") + ":/root",
+			"clab-topo18-db:/var/lib/db",
+		},
+	}
+	for name, want := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := c.Nodes[name].Config().Binds
+			if !cmp.Equal(got, want) {
+				t.Errorf("got: %v, want: %v", got, want)
+			}
+		})
+	}
+
+	if err := c.CreatePersistDirs(); err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []string{
+		filepath.Join(labDir, "node1", "persist", "var", "lib", "data"),
+		filepath.Join(labDir, "node2", "persist", "root"),
+	} {
+		if fi, err := os.Stat(d); err != nil || !fi.IsDir() {
+			t.Errorf("persist directory %s is not created", d)
+		}
+	}
+}
+
+func TestParsePersistErrors(t *testing.T) {
+	tests := map[string][]string{
+		"relative-path":   {"var/lib/data"},
+		"root":            {"/"},
+		"invalid-volume":  {"./data:/var/lib/data"},
+		"duplicate-paths": {"/var/lib/data", "data:/var/lib/data/"},
+	}
+	for name, persist := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := parsePersist(persist, "/tmp/node1", "clab-lab1"); err == nil {
+				t.Errorf("expected an error for %q", persist)
+			}
+		})
+	}
+}
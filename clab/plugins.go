@@ -0,0 +1,41 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes/plugin"
+)
+
+// pluginsDirEnv is the env var setting the directory of the kind plugins for all labs
+const pluginsDirEnv = "CLAB_PLUGINS_DIR"
+
+// loadPlugins registers the kinds of the plugins found in the plugins directory of the lab settings,
+// the directory set with the CLAB_PLUGINS_DIR env var and the default plugins directory
+func (c *CLab) loadPlugins() error {
+	var dirs []string
+	if d := c.Config.Settings.GetPluginsDir(); d != "" {
+		d, err := c.resolveTopoPath(d)
+		if err != nil {
+			return err
+		}
+		dirs = append(dirs, d)
+	}
+	if d := os.Getenv(pluginsDirEnv); d != "" {
+		dirs = append(dirs, d)
+	}
+	dirs = append(dirs, plugin.DefaultDir)
+
+	kinds, err := plugin.Load(dirs...)
+	if err != nil {
+		return err
+	}
+	if len(kinds) != 0 {
+		log.Debugf("kinds provided by plugins: %v", kinds)
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	netnsDir = "/run/netns"
+	// tmpVethPrefix is the prefix of the random names veth interfaces get
+	// while they are in the root netns during the links creation
+	tmpVethPrefix = "clab-"
+)
+
+// deployLockFile is locked by the lab deployments for reading and by prune for writing,
+// so that prune doesn't remove the resources of the labs being deployed
+var deployLockFile = "/run/containerlab.lock"
+
+// ErrDeployInProgress is returned by PruneLock when a lab is being deployed
+var ErrDeployInProgress = errors.New("a lab deployment is in progress")
+
+// transitionalStates are the states of the containers being created, restarted or removed
+var transitionalStates = map[string]struct{}{
+	"created":    {},
+	"restarting": {},
+	"removing":   {},
+}
+
+// labDirArtifacts is a set of files and dirs containerlab creates in a lab directory
+// before any node is deployed. Lab directories containing nothing but these are
+// considered leftovers of the failed deployments
+var labDirArtifacts = map[string]struct{}{
+	"ca":                    {},
+	"ansible-inventory.yml": {},
+}
+
+// ActiveLabs returns the names of the labs having at least one container that is running, paused
+// or in a transitional state
+func ActiveLabs(containers []types.GenericContainer) map[string]struct{} {
+	active := map[string]struct{}{}
+	for _, ctr := range containers {
+		lab := ctr.Labels[ContainerlabLabel]
+		if lab == "" {
+			continue
+		}
+		if _, ok := transitionalStates[ctr.State]; ok || ctr.State == "running" || ctr.State == "paused" {
+			active[lab] = struct{}{}
+		}
+	}
+	return active
+}
+
+// IsOrphanedContainer returns true if the containerlab container doesn't belong to a functional lab anymore,
+// that is the container is dead or its topology file or lab directory were removed.
+// The containers without the lab labels, the containers in transitional states
+// and the containers of the active labs are never orphaned
+func IsOrphanedContainer(ctr types.GenericContainer, activeLabs map[string]struct{}) bool {
+	lab := ctr.Labels[ContainerlabLabel]
+	topo := ctr.Labels[TopoFileLabel]
+	if lab == "" || topo == "" {
+		return false
+	}
+	if _, ok := transitionalStates[ctr.State]; ok {
+		return false
+	}
+	if _, ok := activeLabs[lab]; ok {
+		return false
+	}
+	if ctr.State == "dead" {
+		return true
+	}
+	if _, err := os.Stat(topo); os.IsNotExist(err) {
+		return true
+	}
+	if dir := LabDirFromLabels(ctr.Labels); dir != "" {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			return true
+		}
+	}
+	return false
+}
+
+// DeployLock takes the shared deploy lock, the deployments of several labs can hold it at the same time.
+// The returned function releases the lock
+func DeployLock() (func(), error) {
+	return lockDeployFile(syscall.LOCK_SH)
+}
+
+// PruneLock takes the exclusive deploy lock without waiting for it,
+// ErrDeployInProgress is returned when the lock is held by a lab deployment
+func PruneLock() (func(), error) {
+	release, err := lockDeployFile(syscall.LOCK_EX | syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return nil, ErrDeployInProgress
+	}
+	return release, err
+}
+
+func lockDeployFile(how int) (func(), error) {
+	f, err := os.OpenFile(utils.LocalPath(deployLockFile), os.O_RDONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), how); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return func() {
+		syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+	}, nil
+}
+
+// DanglingVeths returns veth interfaces left in the root netns by interrupted links creation.
+// The interfaces with the temporary names exist while the links are being created,
+// thus the caller must hold the PruneLock
+func DanglingVeths() ([]netlink.Link, error) {
+	ls, err := netlink.LinkList()
+	if err != nil {
+		return nil, err
+	}
+	var dangling []netlink.Link
+	for _, l := range ls {
+		name := l.Attrs().Name
+		// temporary names are made of the prefix and 8 random chars
+		if l.Type() == "veth" && strings.HasPrefix(name, tmpVethPrefix) && len(name) == len(tmpVethPrefix)+8 {
+			dangling = append(dangling, l)
+		}
+	}
+	return dangling, nil
+}
+
+// StaleNetnsSymlinks returns the paths of the netns symlinks pointing to the namespaces of removed containers
+func StaleNetnsSymlinks() ([]string, error) {
+	dir := utils.LocalPath(netnsDir)
+	entries, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var stale []string
+	for _, e := range entries {
+		// named namespaces created with iproute2 are bind mounts,
+		// containerlab creates symlinks to /proc/<pid>/ns/net
+		if e.Mode()&os.ModeSymlink == 0 {
+			continue
+		}
+		p := filepath.Join(dir, e.Name())
+		// the symlinks point to the host paths
+		target, err := os.Readlink(p)
+		if err != nil {
+			continue
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(netnsDir, target)
+		}
+		if _, err := os.Stat(utils.LocalPath(target)); os.IsNotExist(err) {
+			stale = append(stale, p)
+		}
+	}
+	return stale, nil
+}
+
+// StaleLabDirs returns the lab directories found in dir that are not used by any of the running labs
+// and contain only the artifacts created before the nodes deployment
+func StaleLabDirs(dir string, inUse map[string]struct{}) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "clab-*"))
+	if err != nil {
+		return nil, err
+	}
+	var stale []string
+	for _, m := range matches {
+		absPath, err := filepath.Abs(m)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := inUse[absPath]; ok {
+			continue
+		}
+		fi, err := os.Stat(absPath)
+		if err != nil || !fi.IsDir() {
+			continue
+		}
+		entries, err := ioutil.ReadDir(absPath)
+		if err != nil {
+			return nil, err
+		}
+		empty := true
+		for _, e := range entries {
+			if _, ok := labDirArtifacts[e.Name()]; !ok {
+				empty = false
+				break
+			}
+		}
+		if empty {
+			stale = append(stale, absPath)
+		}
+	}
+	return stale, nil
+}
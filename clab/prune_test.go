@@ -0,0 +1,112 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestIsOrphanedContainer(t *testing.T) {
+	dir := t.TempDir()
+	topo := filepath.Join(dir, "lab.clab.yml")
+	if err := ioutil.WriteFile(topo, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	labels := func(lab, topo string) map[string]string {
+		return map[string]string{ContainerlabLabel: lab, TopoFileLabel: topo, LabDirLabel: dir}
+	}
+	containers := []types.GenericContainer{
+		{State: "running", Labels: labels("lab1", topo)},
+		{State: "exited", Labels: labels("lab1", topo)},
+		{State: "created", Labels: labels("lab2", topo)},
+		{State: "dead", Labels: labels("lab3", topo)},
+		{State: "exited", Labels: labels("lab4", filepath.Join(dir, "removed.clab.yml"))},
+	}
+	if d := cmp.Diff(map[string]struct{}{"lab1": {}, "lab2": {}}, ActiveLabs(containers)); d != "" {
+		t.Errorf("active labs mismatch (-want +got):\n%s", d)
+	}
+
+	tests := map[string]struct {
+		ctr  types.GenericContainer
+		want bool
+	}{
+		"exited_in_running_lab": {
+			ctr: containers[1],
+		},
+		"dead_in_running_lab": {
+			ctr: types.GenericContainer{State: "dead", Labels: labels("lab1", topo)},
+		},
+		"being_created": {
+			ctr: containers[2],
+		},
+		"being_removed": {
+			ctr: types.GenericContainer{State: "removing", Labels: labels("lab5", filepath.Join(dir, "removed.clab.yml"))},
+		},
+		"dead": {
+			ctr:  containers[3],
+			want: true,
+		},
+		"topology_file_removed": {
+			ctr:  containers[4],
+			want: true,
+		},
+		"lab_dir_removed": {
+			ctr: types.GenericContainer{State: "exited", Labels: map[string]string{
+				ContainerlabLabel: "lab6", TopoFileLabel: topo, LabDirLabel: filepath.Join(dir, "clab-lab6"),
+			}},
+			want: true,
+		},
+		"stopped_lab": {
+			ctr: types.GenericContainer{State: "exited", Labels: labels("lab7", topo)},
+		},
+		"no_lab_labels": {
+			ctr: types.GenericContainer{State: "dead", Labels: map[string]string{ContainerlabLabel: "lab8"}},
+		},
+	}
+	active := ActiveLabs(containers)
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := IsOrphanedContainer(tc.ctr, active); got != tc.want {
+				t.Errorf("got orphaned %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPruneLock(t *testing.T) {
+	lockFile := deployLockFile
+	defer func() { deployLockFile = lockFile }()
+	deployLockFile = filepath.Join(t.TempDir(), "containerlab.lock")
+
+	// several labs are deployed at the same time
+	release1, err := DeployLock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	release2, err := DeployLock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := PruneLock(); !errors.Is(err, ErrDeployInProgress) {
+		t.Fatalf("got error %v, want %v", err, ErrDeployInProgress)
+	}
+	release1()
+	if _, err := PruneLock(); !errors.Is(err, ErrDeployInProgress) {
+		t.Fatalf("got error %v, want %v", err, ErrDeployInProgress)
+	}
+	release2()
+
+	release, err := PruneLock()
+	if err != nil {
+		t.Fatalf("failed to take the prune lock: %v", err)
+	}
+	release()
+}
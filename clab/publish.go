@@ -0,0 +1,145 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/go-connections/nat"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// PublishHost is the publish type exposing the node port as the host port mapping of the container runtime,
+// the other publish types are published with the mysocketio tunnels
+const PublishHost = "host"
+
+// accessSchemes are the URL schemes of the access URLs of the well-known container ports
+var accessSchemes = map[int]string{
+	22:  "ssh",
+	23:  "telnet",
+	80:  "http",
+	443: "https",
+	830: "netconf",
+}
+
+// parseHostPublish parses the host publish entry in the host/<port>[/<host-port>] format
+// and returns the container port and the host port, 0 when the host port is picked by the container runtime
+func parseHostPublish(s string) (port, hostPort int, err error) {
+	split := strings.Split(s, "/")
+	if len(split) < 2 || len(split) > 3 || split[0] != PublishHost {
+		return 0, 0, fmt.Errorf("wrong host publish entry %q, should be host/<port>[/<host-port>], i.e. host/22 or host/57400/57401", s)
+	}
+	if port, err = strconv.Atoi(split[1]); err != nil || port < 1 || port > 65535 {
+		return 0, 0, fmt.Errorf("incorrect port number %q in publish entry %q", split[1], s)
+	}
+	if len(split) == 3 {
+		if hostPort, err = strconv.Atoi(split[2]); err != nil || hostPort < 1 || hostPort > 65535 {
+			return 0, 0, fmt.Errorf("incorrect host port number %q in publish entry %q", split[2], s)
+		}
+	}
+	return port, hostPort, nil
+}
+
+// applyHostPublish adds the host publish entries of the node to its port bindings
+// and keeps the entries published with the tunnels in the publish list of the node
+func applyHostPublish(cfg *types.NodeConfig) error {
+	var tunnels []string
+	for _, p := range cfg.Publish {
+		if !strings.HasPrefix(p, PublishHost+"/") {
+			tunnels = append(tunnels, p)
+			continue
+		}
+		port, hostPort, err := parseHostPublish(p)
+		if err != nil {
+			return fmt.Errorf("node %q: %v", cfg.ShortName, err)
+		}
+		if cfg.PortSet == nil {
+			cfg.PortSet = nat.PortSet{}
+		}
+		if cfg.PortBindings == nil {
+			cfg.PortBindings = nat.PortMap{}
+		}
+		np := nat.Port(fmt.Sprintf("%d/tcp", port))
+		cfg.PortSet[np] = struct{}{}
+		b := nat.PortBinding{}
+		if hostPort != 0 {
+			b.HostPort = strconv.Itoa(hostPort)
+		}
+		cfg.PortBindings[np] = append(cfg.PortBindings[np], b)
+	}
+	cfg.Publish = tunnels
+	return nil
+}
+
+// verifyPublish warns about the ports to be published with the tunnels in the lab without a mysocketio node
+func (c *CLab) verifyPublish() error {
+	var tunneled []string
+	for _, name := range c.nodeNames() {
+		cfg := c.Nodes[name].Config()
+		if cfg.Kind == nodes.NodeKindMySocketIO {
+			return nil
+		}
+		if len(cfg.Publish) > 0 {
+			tunneled = append(tunneled, name)
+		}
+	}
+	if len(tunneled) > 0 {
+		log.Warnf("nodes %s publish ports with the tunnels, but the lab has no mysocketio node, use the host/<port> publish entries to publish the ports on the container host",
+			strings.Join(tunneled, ", "))
+	}
+	return nil
+}
+
+// PublishAddress returns the address the ports published on all the addresses of the container host are reached with,
+// the name of the remote container host or the name of this host
+func PublishAddress(r runtime.ContainerRuntime) string {
+	if r != nil && runtime.IsRemoteHost(r.Config().Host) {
+		if u, err := url.Parse(r.Config().Host); err == nil && u.Hostname() != "" {
+			return u.Hostname()
+		}
+	}
+	if h, err := os.Hostname(); err == nil && h != "" {
+		return h
+	}
+	return "localhost"
+}
+
+// AccessURLs returns the URLs the ports published by the container on the host are reached with, e.g. ssh://lab-server:32768.
+// The ports published on all the host addresses are reached with the address addr
+func AccessURLs(ctr types.GenericContainer, addr string) []string {
+	urls := make([]string, 0, len(ctr.Ports))
+	seen := map[string]struct{}{}
+	for _, p := range ctr.Ports {
+		if p.HostPort == 0 {
+			continue
+		}
+		host := p.HostIP
+		if host == "" || host == "0.0.0.0" || host == "::" {
+			host = addr
+		}
+		scheme := p.Protocol
+		if s, ok := accessSchemes[p.ContainerPort]; ok && p.Protocol == "tcp" {
+			scheme = s
+		}
+		u := scheme + "://" + net.JoinHostPort(host, strconv.Itoa(p.HostPort))
+		// docker reports the bindings of all the IPv4 and IPv6 addresses separately
+		if _, ok := seen[u]; ok {
+			continue
+		}
+		seen[u] = struct{}{}
+		urls = append(urls, u)
+	}
+	sort.Strings(urls)
+	return urls
+}
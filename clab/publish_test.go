@@ -0,0 +1,57 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestApplyHostPublish(t *testing.T) {
+	cfg := &types.NodeConfig{
+		ShortName: "r1",
+		Publish:   []string{"host/22", "tls/57400/user@domain.com", "host/57400/57401"},
+	}
+	if err := applyHostPublish(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff([]string{"tls/57400/user@domain.com"}, cfg.Publish); d != "" {
+		t.Errorf("publish diff (-want +got):\n%s", d)
+	}
+	want := nat.PortMap{
+		"22/tcp":    {{}},
+		"57400/tcp": {{HostPort: "57401"}},
+	}
+	if d := cmp.Diff(want, cfg.PortBindings); d != "" {
+		t.Errorf("port bindings diff (-want +got):\n%s", d)
+	}
+	if len(cfg.PortSet) != 2 {
+		t.Errorf("got port set %v, want 2 ports", cfg.PortSet)
+	}
+
+	for _, p := range []string{"host/ssh", "host/22/0", "host/22/23/24", "host/70000"} {
+		if err := applyHostPublish(&types.NodeConfig{Publish: []string{p}}); err == nil {
+			t.Errorf("expected an error for publish entry %q", p)
+		}
+	}
+}
+
+func TestAccessURLs(t *testing.T) {
+	ctr := types.GenericContainer{
+		Ports: []*types.GenericPortBinding{
+			{HostIP: "0.0.0.0", HostPort: 32768, ContainerPort: 22, Protocol: "tcp"},
+			{HostIP: "::", HostPort: 32768, ContainerPort: 22, Protocol: "tcp"},
+			{HostIP: "10.0.0.1", HostPort: 57401, ContainerPort: 57400, Protocol: "tcp"},
+			{HostIP: "0.0.0.0", HostPort: 1161, ContainerPort: 161, Protocol: "udp"},
+		},
+	}
+	want := []string{"ssh://lab-server:32768", "tcp://10.0.0.1:57401", "udp://lab-server:1161"}
+	if d := cmp.Diff(want, AccessURLs(ctr, "lab-server")); d != "" {
+		t.Errorf("diff (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/go-units"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// labResources holds the resources declared by the lab nodes
+type labResources struct {
+	cpu    float64
+	memory int64
+	// nodes that don't declare cpu or memory and therefore are not accounted
+	noCPU, noMemory []string
+}
+
+// declaredResources sums up the resources declared by the lab nodes
+func (c *CLab) declaredResources() (*labResources, error) {
+	r := new(labResources)
+	for name, n := range c.Nodes {
+		cfg := n.Config()
+		// bridges and host nodes are not containers and don't consume resources
+		if _, ok := noMgmtKinds[cfg.Kind]; ok {
+			continue
+		}
+
+		m, err := nodeMemory(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse memory of node %q: %v", name, err)
+		}
+		if m == 0 {
+			r.noMemory = append(r.noMemory, name)
+		}
+		r.memory += m
+
+		cpu, err := nodeCPU(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse cpu of node %q: %v", name, err)
+		}
+		if cpu == 0 {
+			r.noCPU = append(r.noCPU, name)
+		}
+		r.cpu += cpu
+	}
+	sort.Strings(r.noCPU)
+	sort.Strings(r.noMemory)
+	return r, nil
+}
+
+// nodeCPU returns the number of CPUs declared by a node, 0 if the node doesn't declare it
+func nodeCPU(cfg *types.NodeConfig) (float64, error) {
+	if cfg.CPU == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(cfg.CPU, 64)
+}
+
+// parseQuota returns the cpu and memory limits set in the quota config,
+// zero values mean the resource is not limited
+func parseQuota(q *types.QuotaConfig) (cpu float64, memory int64, err error) {
+	if q.CPU != "" {
+		if cpu, err = strconv.ParseFloat(q.CPU, 64); err != nil || cpu <= 0 {
+			return 0, 0, fmt.Errorf("invalid quota cpu value %q", q.CPU)
+		}
+	}
+	if q.Memory != "" {
+		if memory, err = units.RAMInBytes(q.Memory); err != nil || memory <= 0 {
+			return 0, 0, fmt.Errorf("invalid quota memory value %q", q.Memory)
+		}
+	}
+	return cpu, memory, nil
+}
+
+// CheckQuota verifies that the resources declared by the lab nodes fit into the lab quota
+// and the capacity of the container host
+func (c *CLab) CheckQuota() error {
+	if c.Config.Quota == nil {
+		return nil
+	}
+	cpuQuota, memQuota, err := parseQuota(c.Config.Quota)
+	if err != nil {
+		return err
+	}
+	r, err := c.declaredResources()
+	if err != nil {
+		return err
+	}
+	return checkQuota(r, cpuQuota, memQuota, float64(runtime.NumCPU()), int64(sysMemory("total")))
+}
+
+func checkQuota(r *labResources, cpuQuota float64, memQuota int64, hostCPU float64, hostMem int64) error {
+	var errs []string
+	if cpuQuota > 0 {
+		if len(r.noCPU) > 0 {
+			log.Warnf("nodes %s do not declare cpu and are not accounted in the lab cpu quota", strings.Join(r.noCPU, ", "))
+		}
+		if r.cpu > cpuQuota {
+			errs = append(errs, fmt.Sprintf("nodes declare %g cpu, lab quota is %g", r.cpu, cpuQuota))
+		}
+		if hostCPU > 0 && cpuQuota > hostCPU {
+			log.Warnf("lab cpu quota %g exceeds %g cpu of the container host", cpuQuota, hostCPU)
+		}
+	}
+	if memQuota > 0 {
+		if len(r.noMemory) > 0 {
+			log.Warnf("nodes %s do not declare memory and are not accounted in the lab memory quota", strings.Join(r.noMemory, ", "))
+		}
+		if r.memory > memQuota {
+			errs = append(errs, fmt.Sprintf("nodes declare %s of memory, lab quota is %s",
+				units.BytesSize(float64(r.memory)), units.BytesSize(float64(memQuota))))
+		}
+		if hostMem > 0 && memQuota > hostMem {
+			log.Warnf("lab memory quota %s exceeds %s of the container host memory",
+				units.BytesSize(float64(memQuota)), units.BytesSize(float64(hostMem)))
+		}
+	}
+	// declared memory can't be provided by the host regardless of the quota
+	if hostMem > 0 && r.memory > hostMem {
+		errs = append(errs, fmt.Sprintf("nodes declare %s of memory, container host has %s",
+			units.BytesSize(float64(r.memory)), units.BytesSize(float64(hostMem))))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("lab quota exceeded: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+
+	"github.com/docker/go-units"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestParseQuota(t *testing.T) {
+	tests := map[string]struct {
+		quota   *types.QuotaConfig
+		wantCPU float64
+		wantMem int64
+		wantErr bool
+	}{
+		"cpu_and_memory": {
+			quota:   &types.QuotaConfig{CPU: "2.5", Memory: "4GB"},
+			wantCPU: 2.5,
+			wantMem: 4 * units.GiB,
+		},
+		"memory_only": {
+			quota:   &types.QuotaConfig{Memory: "512mb"},
+			wantMem: 512 * units.MiB,
+		},
+		"invalid_cpu": {
+			quota:   &types.QuotaConfig{CPU: "two"},
+			wantErr: true,
+		},
+		"negative_cpu": {
+			quota:   &types.QuotaConfig{CPU: "-1"},
+			wantErr: true,
+		},
+		"invalid_memory": {
+			quota:   &types.QuotaConfig{Memory: "lots"},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cpu, mem, err := parseQuota(tc.quota)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tc.wantErr)
+			}
+			if cpu != tc.wantCPU || mem != tc.wantMem {
+				t.Errorf("got cpu %g memory %d, want cpu %g memory %d", cpu, mem, tc.wantCPU, tc.wantMem)
+			}
+		})
+	}
+}
+
+func TestCheckQuota(t *testing.T) {
+	tests := map[string]struct {
+		res      *labResources
+		cpuQuota float64
+		memQuota int64
+		hostMem  int64
+		wantErr  bool
+	}{
+		"within_quota": {
+			res:      &labResources{cpu: 4, memory: 8 * units.GiB},
+			cpuQuota: 4,
+			memQuota: 8 * units.GiB,
+			hostMem:  16 * units.GiB,
+		},
+		"cpu_exceeded": {
+			res:      &labResources{cpu: 6},
+			cpuQuota: 4,
+			wantErr:  true,
+		},
+		"memory_exceeded": {
+			res:      &labResources{memory: 10 * units.GiB},
+			memQuota: 8 * units.GiB,
+			wantErr:  true,
+		},
+		"host_memory_exceeded": {
+			res:      &labResources{memory: 10 * units.GiB},
+			cpuQuota: 4,
+			hostMem:  8 * units.GiB,
+			wantErr:  true,
+		},
+		"no_limits": {
+			res: &labResources{cpu: 100, memory: 100 * units.GiB},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := checkQuota(tc.res, tc.cpuQuota, tc.memQuota, 8, tc.hostMem)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("got error %v, want error %v", err, tc.wantErr)
+			}
+		})
+	}
+}
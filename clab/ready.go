@@ -0,0 +1,111 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// readyPoll is the interval of checking whether a node is ready
+const readyPoll = 5 * time.Second
+
+// portProbeTimeout bounds a single TCP port probe
+const portProbeTimeout = 3 * time.Second
+
+// WaitForNode blocks until the node is ready to be used.
+// The nodes with the wait-for settings are waited on always, the nodes with the readiness probe,
+// e.g. the vrnetlab nodes booting a VM, are waited on when all is set.
+// A node is ready when its readiness probe reports so and the wait-for port accepts connections
+func WaitForNode(ctx context.Context, n nodes.Node, all bool) error {
+	cfg := n.Config()
+	rc, hasProbe := n.(nodes.ReadyChecker)
+	if cfg.WaitFor == nil && !(all && hasProbe) {
+		return nil
+	}
+	probe := func(ctx context.Context) (bool, error) {
+		if hasProbe {
+			if ok, err := rc.Ready(ctx); !ok || err != nil {
+				return false, err
+			}
+		}
+		if p := cfg.WaitFor.GetPort(); p != 0 {
+			return probePort(ctx, net.JoinHostPort(probeHost(cfg), strconv.Itoa(p))), nil
+		}
+		return true, nil
+	}
+
+	timeout := waitTimeout(cfg)
+	log.Infof("Waiting for %s to become ready (timeout %s)", cfg.ShortName, timeout)
+	start := time.Now()
+	if err := waitReady(ctx, probe, timeout, readyPoll); err != nil {
+		return fmt.Errorf("node %s is not ready: %v", cfg.ShortName, err)
+	}
+	log.Infof("Node %s is ready after %s", cfg.ShortName, time.Since(start).Round(time.Second))
+	return nil
+}
+
+// waitTimeout returns the time the node is given to become ready,
+// the boot timeout of the vrnetlab kinds is used unless the wait-for timeout is set
+func waitTimeout(cfg *types.NodeConfig) time.Duration {
+	if t := cfg.WaitFor.GetTimeout(); t > 0 {
+		return time.Duration(t) * time.Second
+	}
+	return nodes.VrBootTimeout(cfg.Kind)
+}
+
+// waitReady polls the probe until it reports ready or the timeout expires,
+// probe errors are retried, as the node services may fail while starting
+func waitReady(ctx context.Context, probe func(context.Context) (bool, error), timeout, poll time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	var lastErr error
+	for {
+		ok, err := probe(ctx)
+		if ok && err == nil {
+			return nil
+		}
+		lastErr = err
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return fmt.Errorf("%v, last probe error: %v", ctx.Err(), lastErr)
+			}
+			return ctx.Err()
+		case <-time.After(poll):
+		}
+	}
+}
+
+// probeHost returns the host the wait-for port of the node is probed on
+func probeHost(cfg *types.NodeConfig) string {
+	switch {
+	case cfg.MgmtIPv4Address != "":
+		return cfg.MgmtIPv4Address
+	case cfg.MgmtIPv6Address != "":
+		return cfg.MgmtIPv6Address
+	case cfg.NetworkMode == "host":
+		return "localhost"
+	}
+	return cfg.LongName
+}
+
+// probePort returns true if the TCP port at addr accepts connections
+func probePort(ctx context.Context, addr string) bool {
+	d := net.Dialer{Timeout: portProbeTimeout}
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
@@ -0,0 +1,99 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestWaitReady(t *testing.T) {
+	tests := map[string]struct {
+		// results of the consecutive probes, the last one repeats
+		results []bool
+		errs    []error
+		wantErr bool
+	}{
+		"ready-at-once": {
+			results: []bool{true},
+			errs:    []error{nil},
+		},
+		"ready-after-errors": {
+			results: []bool{false, false, true},
+			errs:    []error{errors.New("exec failed"), nil, nil},
+		},
+		"never-ready": {
+			results: []bool{false},
+			errs:    []error{nil},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			i := 0
+			probe := func(context.Context) (bool, error) {
+				j := i
+				if j >= len(tc.results) {
+					j = len(tc.results) - 1
+				}
+				i++
+				return tc.results[j], tc.errs[j]
+			}
+			err := waitReady(context.Background(), probe, 100*time.Millisecond, time.Millisecond)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("got error: %v, want error: %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestProbePort(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	if !probePort(context.Background(), addr) {
+		t.Errorf("port %s accepting connections is not reported as open", addr)
+	}
+	l.Close()
+	if probePort(context.Background(), addr) {
+		t.Errorf("closed port %s is reported as open", addr)
+	}
+}
+
+func TestWaitTimeout(t *testing.T) {
+	tests := map[string]struct {
+		cfg  *types.NodeConfig
+		want time.Duration
+	}{
+		"wait-for-timeout": {
+			cfg:  &types.NodeConfig{Kind: "vr-sros", WaitFor: &types.WaitFor{Timeout: 90}},
+			want: 90 * time.Second,
+		},
+		"kind-boot-timeout": {
+			cfg:  &types.NodeConfig{Kind: "vr-xrv9k", WaitFor: &types.WaitFor{Port: 22}},
+			want: 30 * time.Minute,
+		},
+		"default": {
+			cfg:  &types.NodeConfig{Kind: "linux"},
+			want: 10 * time.Minute,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := waitTimeout(tc.cfg); got != tc.want {
+				t.Errorf("got: %s, want: %s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/cert"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/vishvananda/netlink"
+)
+
+// reconcilePlan returns the sorted names of the topology nodes which are not deployed
+// and the links connecting at least one of these nodes
+func (c *CLab) reconcilePlan(deployed map[string]struct{}) ([]string, []*types.Link) {
+	var missing []string
+	for name := range c.Nodes {
+		if _, ok := deployed[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+
+	var links []*types.Link
+	for i := 0; i < len(c.Links); i++ {
+		l := c.Links[i]
+		_, okA := deployed[l.A.Node.ShortName]
+		_, okB := deployed[l.B.Node.ShortName]
+		if !okA || !okB {
+			links = append(links, l)
+		}
+	}
+	return missing, links
+}
+
+// deployedNodes returns the names of the topology nodes deployed on the lab host,
+// i.e. the nodes with the lab containers, the host and the existing bridges.
+// The deployed containers of the nodes removed from the topology are reported and left running
+func (c *CLab) deployedNodes(ctx context.Context) (map[string]struct{}, error) {
+	labels := []*types.GenericFilter{{FilterType: "label", Match: c.Config.Name, Field: ContainerlabLabel, Operator: "="}}
+	containers, err := c.ListContainers(ctx, labels)
+	if err != nil {
+		return nil, err
+	}
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("lab %s is not deployed, deploy it without the --reconcile flag", c.Config.Name)
+	}
+	deployed := map[string]struct{}{}
+	for i := range containers {
+		name := containers[i].Labels[NodeNameLabel]
+		n, ok := c.Nodes[name]
+		if !ok {
+			if name != "" {
+				log.Warnf("node %s is deployed but not defined in the topology, it is left running", name)
+			}
+			continue
+		}
+		deployed[name] = struct{}{}
+		keepMgmtAddresses(n.Config(), &containers[i])
+	}
+	for name, n := range c.Nodes {
+		switch n.Config().Kind {
+		case "host":
+			deployed[name] = struct{}{}
+		case nodes.NodeKindBridge, nodes.NodeKindOVS:
+			if _, err := netlink.LinkByName(name); err == nil {
+				deployed[name] = struct{}{}
+			}
+		}
+	}
+	return deployed, nil
+}
+
+// linkWired returns true when the interfaces of the link endpoints exist,
+// the endpoints of the container nodes are looked up in the node netns
+func linkWired(l *types.Link) (bool, error) {
+	for _, ep := range []*types.Endpoint{l.A, l.B} {
+		if _, ok := noMgmtKinds[ep.Node.Kind]; ok {
+			if _, err := netlink.LinkByName(ep.EndpointName); err != nil {
+				return false, nil
+			}
+			continue
+		}
+		nodeNS, err := ns.GetNS(ep.Node.NSPath)
+		if err != nil {
+			return false, err
+		}
+		err = nodeNS.Do(func(_ ns.NetNS) error {
+			_, err := netlink.LinkByName(ep.EndpointName)
+			return err
+		})
+		nodeNS.Close()
+		if err != nil {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// ReconcileLab deploys the nodes of the topology which are missing from the running lab
+// and wires their links and the links added between the deployed nodes.
+// The deployed nodes and their links are left untouched, it returns the names of the added nodes
+func (c *CLab) ReconcileLab(ctx context.Context) ([]string, error) {
+	for _, check := range []func() error{
+		c.verifyLinkVLANs, c.verifyLinkNetem, c.verifyLinkMTU,
+		c.verifyLinkTypes, c.verifyLinks, c.verifyVrEndpoints,
+	} {
+		if err := check(); err != nil {
+			return nil, err
+		}
+	}
+	deployed, err := c.deployedNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	missing, links := c.reconcilePlan(deployed)
+
+	// the netns of the deployed nodes are needed to wire the new links
+	for name := range deployed {
+		n := c.Nodes[name]
+		if _, ok := noMgmtKinds[n.Config().Kind]; ok {
+			continue
+		}
+		if n.Config().NSPath, err = n.GetRuntime().GetNSPath(ctx, n.Config().LongName); err != nil {
+			return nil, fmt.Errorf("failed to get netns of node %q: %v", name, err)
+		}
+	}
+	planned := map[*types.Link]struct{}{}
+	for _, l := range links {
+		planned[l] = struct{}{}
+	}
+	for i := 0; i < len(c.Links); i++ {
+		l := c.Links[i]
+		if _, ok := planned[l]; ok || c.remoteWiring() {
+			continue
+		}
+		wired, err := linkWired(l)
+		if err != nil {
+			return nil, err
+		}
+		if !wired {
+			links = append(links, l)
+		}
+	}
+	if len(missing) == 0 && len(links) == 0 {
+		log.Infof("Lab %s is up to date with the topology", c.Config.Name)
+		return nil, nil
+	}
+	if err := c.VerifyImages(ctx); err != nil {
+		return nil, err
+	}
+
+	for _, name := range missing {
+		if cert.NeedsCert(c.Nodes[name].Config()) {
+			if err := c.InitCA(); err != nil {
+				return nil, err
+			}
+			if err := cert.CreateRootCA(c.Config.Name, c.Dir.LabCARoot, c.Nodes); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	if err := c.InstallCABundle(); err != nil {
+		return nil, err
+	}
+	if err := c.CreatePersistDirs(); err != nil {
+		return nil, err
+	}
+
+	for _, name := range missing {
+		n := c.Nodes[name]
+		log.Infof("Adding node %s to lab %s", name, c.Config.Name)
+		if err := n.PreDeploy(c.Config.Name, c.Dir.LabCA, c.Dir.LabCARoot); err != nil {
+			return nil, fmt.Errorf("failed pre-deploy phase for node %q: %v", name, err)
+		}
+		if err := c.syncLabDirBinds(ctx, n); err != nil {
+			return nil, fmt.Errorf("failed pre-deploy phase for node %q: %v", name, err)
+		}
+		if err := n.Deploy(ctx); err != nil {
+			return nil, fmt.Errorf("failed deploy phase for node %q: %v", name, err)
+		}
+	}
+
+	for _, l := range links {
+		for _, ep := range []*types.Endpoint{l.A, l.B} {
+			if _, ok := noMgmtKinds[ep.Node.Kind]; ok || ep.Node.NSPath != "" {
+				continue
+			}
+			if ep.Node.NSPath, err = c.Nodes[ep.Node.ShortName].GetRuntime().GetNSPath(ctx, ep.Node.LongName); err != nil {
+				return nil, fmt.Errorf("failed to get netns of node %q: %v", ep.Node.ShortName, err)
+			}
+		}
+		if err := c.wireLink(ctx, l); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.RemoveWiringAgent(ctx); err != nil {
+		log.Warnf("failed to remove wiring agent: %v", err)
+	}
+
+	for _, name := range missing {
+		n := c.Nodes[name]
+		c.setNodeRoutes(n.Config())
+		if err := n.PostDeploy(ctx, c.Nodes); err != nil {
+			log.Errorf("failed to run postdeploy task for node %s: %v", name, err)
+		}
+	}
+	return missing, nil
+}
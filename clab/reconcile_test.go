@@ -0,0 +1,56 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReconcilePlan(t *testing.T) {
+	tests := map[string]struct {
+		deployed    []string
+		wantMissing []string
+		wantLinks   []string
+	}{
+		"up-to-date": {
+			deployed: []string{"spine1", "leaf1", "leaf2", "client<1>"},
+		},
+		"new-leaf": {
+			deployed:    []string{"spine1", "leaf1", "client<1>"},
+			wantMissing: []string{"leaf2"},
+			wantLinks:   []string{"spine1:e1-2 <-> leaf2:eth1"},
+		},
+		"new-leaf-and-client": {
+			deployed:    []string{"spine1", "leaf2"},
+			wantMissing: []string{"client<1>", "leaf1"},
+			wantLinks:   []string{"spine1:e1-1 <-> leaf1:eth1", "leaf1:eth2 <-> client<1>:eth1"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewContainerLab(WithTopoFile("test_data/topo14.yml"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			deployed := map[string]struct{}{}
+			for _, n := range tc.deployed {
+				deployed[n] = struct{}{}
+			}
+			missing, links := c.reconcilePlan(deployed)
+			if d := cmp.Diff(tc.wantMissing, missing); d != "" {
+				t.Errorf("missing nodes mismatch (-want +got):\n%s", d)
+			}
+			var got []string
+			for _, l := range links {
+				got = append(got, l.A.Node.ShortName+":"+l.A.EndpointName+" <-> "+l.B.Node.ShortName+":"+l.B.EndpointName)
+			}
+			if d := cmp.Diff(tc.wantLinks, got); d != "" {
+				t.Errorf("links mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
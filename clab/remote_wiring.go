@@ -0,0 +1,292 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+)
+
+const (
+	// wiringAgentName is the name of the container wiring the links on a remote container host
+	wiringAgentName = "wiring-agent"
+	// wiringAgentImage provides the iproute2 and ethtool tools to the wiring agent
+	wiringAgentImage = "ghcr.io/hellt/network-multitool"
+	// agentHostRoot is the path the root of the remote container host is mounted to in the wiring agent
+	agentHostRoot = "/host"
+)
+
+// remoteWiring returns true when the lab runs on a remote container host,
+// in that case the links can't be wired with netlink from this host
+func (c *CLab) remoteWiring() bool {
+	r := c.GlobalRuntime()
+	return r != nil && runtime.IsRemoteHost(r.Config().Host)
+}
+
+// labDirBinds returns the source paths of the binds of the node mounting a path of the lab directory
+// or of the node directory, e.g. the configs of srl and ceos or the TLS certificates
+func labDirBinds(cfg *types.NodeConfig, labDir string) []string {
+	var res []string
+	for _, b := range cfg.Binds {
+		src := strings.SplitN(b, ":", 2)[0]
+		if !filepath.IsAbs(src) {
+			// named volume
+			continue
+		}
+		for _, d := range []string{labDir, cfg.LabDir} {
+			if d != "" && (src == d || strings.HasPrefix(src, d+string(filepath.Separator))) {
+				res = append(res, src)
+				break
+			}
+		}
+	}
+	return res
+}
+
+// syncLabDirBinds copies the files the node mounts from the lab directory or the node directory
+// to the same paths on the remote container host, so that the node can be created there.
+// The lab directory is created on the local host, the files are written by the wiring agent
+// mounting the root of the remote host. Nothing is done when the lab runs on the local host
+func (c *CLab) syncLabDirBinds(ctx context.Context, n nodes.Node) error {
+	if !c.remoteWiring() {
+		return nil
+	}
+	srcs := labDirBinds(n.Config(), c.Dir.Lab)
+	if len(srcs) == 0 {
+		return nil
+	}
+	if err := c.startWiringAgent(ctx); err != nil {
+		return fmt.Errorf("failed to start wiring agent: %v", err)
+	}
+	log.Debugf("Copying %s of node %s to the remote host", strings.Join(srcs, ", "), n.Config().ShortName)
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(tarPaths(pw, srcs))
+	}()
+	err := c.GlobalRuntime().CopyToContainer(ctx, c.wiringAgentLongName(), agentHostRoot, pr)
+	// unblocks the archive writer when the copy failed before reading the whole archive
+	pr.CloseWithError(err)
+	if err != nil {
+		return fmt.Errorf("failed to copy files of node %q to the remote host: %v", n.Config().ShortName, err)
+	}
+	return nil
+}
+
+// tarPaths writes the tar archive of the files and directories to w,
+// the entries are named after their absolute paths without the leading slash
+func tarPaths(w io.Writer, paths []string) error {
+	tw := tar.NewWriter(w)
+	for _, p := range paths {
+		err := filepath.Walk(p, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			link := ""
+			if info.Mode()&os.ModeSymlink != 0 {
+				if link, err = os.Readlink(path); err != nil {
+					return err
+				}
+			}
+			hdr, err := tar.FileInfoHeader(info, link)
+			if err != nil {
+				return err
+			}
+			hdr.Name = strings.TrimPrefix(filepath.ToSlash(path), "/")
+			if info.IsDir() {
+				hdr.Name += "/"
+			}
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			if !info.Mode().IsRegular() {
+				return nil
+			}
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			_, err = io.Copy(tw, f)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+// wireLink wires the link on the container host of the lab
+func (c *CLab) wireLink(ctx context.Context, l *types.Link) error {
+	switch {
+	case l.Stitch != nil && c.remoteWiring():
+		return fmt.Errorf("%s: links stitched to lab host %s can't be wired on a remote container host", l, l.Stitch.Host)
+	case l.Stitch != nil:
+		return c.createVxlanStitch(l)
+	case isSubIfLink(l) && c.remoteWiring():
+		return fmt.Errorf("%s: %s links can't be wired on a remote container host", l, l.Type)
+	case isSubIfLink(l):
+		return c.createSubIfLink(l)
+	case !c.remoteWiring():
+		if nic, peer := hostNICEndpoints(l); nic != nil {
+			return c.attachHostNIC(l, nic, peer)
+		}
+		return c.CreateVirtualWiring(l)
+	}
+	return c.createRemoteWiring(ctx, l)
+}
+
+func (c *CLab) wiringAgentLongName() string {
+	return fmt.Sprintf("clab-%s-%s", c.Config.Name, wiringAgentName)
+}
+
+// startWiringAgent starts the wiring agent container on the remote container host once per lab.
+// The agent shares the network and pid namespaces with the host to reach the netns of the lab containers
+// and mounts the root of the host to copy the files of the lab directory to the host
+func (c *CLab) startWiringAgent(ctx context.Context) error {
+	c.agentOnce.Do(func() {
+		r := c.GlobalRuntime()
+		if c.agentErr = r.PullImageIfRequired(ctx, wiringAgentImage); c.agentErr != nil {
+			return
+		}
+		log.Infof("Starting wiring agent on the remote container host")
+		_, c.agentErr = r.CreateContainer(ctx, &types.NodeConfig{
+			ShortName:   wiringAgentName,
+			LongName:    c.wiringAgentLongName(),
+			Image:       wiringAgentImage,
+			Entrypoint:  "tail -f /dev/null",
+			NetworkMode: "host",
+			PidMode:     "host",
+			Binds:       []string{"/var/run/netns:/var/run/netns", "/:" + agentHostRoot},
+		})
+	})
+	return c.agentErr
+}
+
+// RemoveWiringAgent removes the wiring agent container if it was started
+func (c *CLab) RemoveWiringAgent(ctx context.Context) error {
+	started := true
+	// marks the agent as done, so that it is not started after the removal
+	c.agentOnce.Do(func() { started = false })
+	if !started || c.agentErr != nil {
+		return nil
+	}
+	return c.GlobalRuntime().DeleteContainer(ctx, c.wiringAgentLongName())
+}
+
+// createRemoteWiring wires the link on the remote container host with the commands run by the wiring agent
+func (c *CLab) createRemoteWiring(ctx context.Context, l *types.Link) error {
+	log.Infof("Creating virtual wire on remote host: %s:%s <--> %s:%s", l.A.Node.ShortName, l.A.EndpointName, l.B.Node.ShortName, l.B.EndpointName)
+	script, err := remoteWiringScript(l, c.Config.Mgmt.Bridge)
+	if err != nil {
+		return err
+	}
+	if err := c.startWiringAgent(ctx); err != nil {
+		return fmt.Errorf("failed to start wiring agent: %v", err)
+	}
+	_, stderr, rc, err := c.GlobalRuntime().ExecWithExitCode(ctx, c.wiringAgentLongName(), []string{"sh", "-c", script})
+	if err != nil {
+		return err
+	}
+	if rc != 0 {
+		return fmt.Errorf("failed to wire %s:%s <--> %s:%s: %s", l.A.Node.ShortName, l.A.EndpointName,
+			l.B.Node.ShortName, l.B.EndpointName, strings.TrimSpace(string(stderr)))
+	}
+	return nil
+}
+
+// remoteWiringScript returns the shell script creating the veth pair of the link in the host netns
+// and moving its sides to the link endpoints, the same way CreateVirtualWiring does with netlink
+func remoteWiringScript(l *types.Link, mgmtBridge string) (string, error) {
+	aName, bName := "clab-"+genIfName(), "clab-"+genIfName()
+	// endpoints in the host netns keep their names
+	if hostNSEndpoint(l.A) {
+		aName = l.A.EndpointName
+	}
+	if hostNSEndpoint(l.B) {
+		bName = l.B.EndpointName
+	}
+	cmds := []string{
+		"mkdir -p /var/run/netns",
+		fmt.Sprintf("ip link add %s address %s mtu %d type veth peer name %s address %s mtu %d",
+			aName, l.A.MAC, l.MTU, bName, l.B.MAC, l.MTU),
+		fmt.Sprintf("ethtool -K %s tx off", aName),
+		fmt.Sprintf("ethtool -K %s tx off", bName),
+	}
+	for _, e := range []struct {
+		ep   *types.Endpoint
+		name string
+	}{{l.A, aName}, {l.B, bName}} {
+		epCmds, err := remoteEndpointCmds(e.ep, e.name, mgmtBridge)
+		if err != nil {
+			return "", err
+		}
+		cmds = append(cmds, epCmds...)
+		for _, tc := range l.Netem.TCCommands(e.ep.EndpointName) {
+			if !hostNSEndpoint(e.ep) {
+				tc = fmt.Sprintf("ip netns exec %s %s", e.ep.Node.LongName, tc)
+			}
+			cmds = append(cmds, tc)
+		}
+	}
+	return strings.Join(cmds, " && "), nil
+}
+
+// hostNSEndpoint returns true for the endpoints staying in the host netns
+func hostNSEndpoint(e *types.Endpoint) bool {
+	switch e.Node.Kind {
+	case "bridge", "ovs-bridge", "host":
+		return true
+	}
+	return false
+}
+
+// remoteEndpointCmds returns the commands attaching the veth side named name to the endpoint
+func remoteEndpointCmds(e *types.Endpoint, name, mgmtBridge string) ([]string, error) {
+	switch e.Node.Kind {
+	case "bridge":
+		br := e.Node.ShortName
+		if br == "mgmt-net" {
+			br = mgmtBridge
+		}
+		return []string{fmt.Sprintf("ip link set %s master %s up", name, br)}, nil
+	case "ovs-bridge":
+		return nil, fmt.Errorf("ovs-bridge %s endpoints are not supported on remote container hosts", e.Node.ShortName)
+	case "host":
+		return []string{fmt.Sprintf("ip link set %s up", name)}, nil
+	}
+	ns := e.Node.LongName
+	cmds := []string{
+		fmt.Sprintf("ln -sfn %s /var/run/netns/%s", e.Node.NSPath, ns),
+		fmt.Sprintf("ip link set %s netns %s", name, ns),
+		fmt.Sprintf("ip -n %s link set %s name %s", ns, name, e.EndpointName),
+	}
+	if p := e.Node.InterfaceProfile; p != nil {
+		if p.MTU != 0 {
+			cmds = append(cmds, fmt.Sprintf("ip -n %s link set %s mtu %d", ns, e.EndpointName, p.MTU))
+		}
+		sysctls := p.Sysctls(e.EndpointName)
+		keys := make([]string, 0, len(sysctls))
+		for k := range sysctls {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			cmds = append(cmds, fmt.Sprintf("ip netns exec %s sh -c 'echo %s > /proc/sys/%s'", ns, sysctls[k], k))
+		}
+	}
+	return append(cmds, fmt.Sprintf("ip -n %s link set %s up", ns, e.EndpointName)), nil
+}
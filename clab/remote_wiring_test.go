@@ -0,0 +1,173 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestRemoteWiringScript(t *testing.T) {
+	l := &types.Link{
+		A: &types.Endpoint{
+			Node:         &types.NodeConfig{ShortName: "mgmt-net", Kind: "bridge"},
+			EndpointName: "srl1-e1-1",
+			MAC:          "aa:c1:ab:00:00:01",
+		},
+		B: &types.Endpoint{
+			Node:         &types.NodeConfig{ShortName: "srl1", LongName: "clab-lab-srl1", Kind: "srl", NSPath: "/proc/42/ns/net"},
+			EndpointName: "e1-1",
+			MAC:          "aa:c1:ab:00:00:02",
+		},
+		MTU: 9500,
+	}
+	script, err := remoteWiringScript(l, "br-clab")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"ip link add srl1-e1-1 address aa:c1:ab:00:00:01 mtu 9500 type veth peer name clab-",
+		"ip link set srl1-e1-1 master br-clab up",
+		"ln -sfn /proc/42/ns/net /var/run/netns/clab-lab-srl1",
+		"ip -n clab-lab-srl1 link set e1-1 up",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script %q doesn't contain %q", script, want)
+		}
+	}
+
+	l.Netem = &types.Netem{Delay: 20, Rate: 1000}
+	if script, err = remoteWiringScript(l, "br-clab"); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"tc qdisc replace dev srl1-e1-1 root handle 1: netem delay 20ms",
+		"ip netns exec clab-lab-srl1 tc qdisc replace dev e1-1 root handle 1: netem delay 20ms",
+		"ip netns exec clab-lab-srl1 tc qdisc replace dev e1-1 parent 1:1 handle 10: tbf rate 1000kbit",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script %q doesn't contain %q", script, want)
+		}
+	}
+
+	l.A.Node = &types.NodeConfig{ShortName: "ovs1", Kind: "ovs-bridge"}
+	if _, err := remoteWiringScript(l, "br-clab"); err == nil {
+		t.Errorf("expected an error for ovs-bridge endpoint")
+	}
+}
+
+func TestLabDirBinds(t *testing.T) {
+	labDir := "/root/clab-lab"
+	tests := map[string]struct {
+		cfg  *types.NodeConfig
+		want []string
+	}{
+		"no-binds": {
+			cfg: &types.NodeConfig{LabDir: labDir + "/n1"},
+		},
+		"host-paths-and-volumes": {
+			cfg: &types.NodeConfig{
+				LabDir: labDir + "/n1",
+				Binds:  []string{"/opt/data:/data", "db:/var/lib/db", "/root/clab-lab-2/n1:/n1"},
+			},
+		},
+		"node-dir": {
+			cfg: &types.NodeConfig{
+				LabDir: labDir + "/srl1",
+				Binds: []string{"/opt/data:/data", labDir + "/srl1/config:/etc/opt/srlinux/:rw",
+					labDir + "/srl1/topology.yml:/tmp/topology.yml:ro"},
+			},
+			want: []string{labDir + "/srl1/config", labDir + "/srl1/topology.yml"},
+		},
+		"node-dir-outside-lab-dir": {
+			cfg: &types.NodeConfig{
+				LabDir: "/data/ceos1",
+				Binds:  []string{"/data/ceos1/flash:/mnt/flash/"},
+			},
+			want: []string{"/data/ceos1/flash"},
+		},
+		"lab-dir": {
+			cfg: &types.NodeConfig{
+				LabDir: labDir + "/n1",
+				Binds:  []string{labDir + "/ca/root/root-ca.pem:/ca.pem:ro"},
+			},
+			want: []string{labDir + "/ca/root/root-ca.pem"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if d := cmp.Diff(tt.want, labDirBinds(tt.cfg, labDir)); d != "" {
+				t.Errorf("binds mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestTarPaths(t *testing.T) {
+	dir := t.TempDir()
+	nodeDir := filepath.Join(dir, "clab-lab", "srl1")
+	if err := os.MkdirAll(filepath.Join(nodeDir, "config"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		filepath.Join(nodeDir, "config", "config.json"): "{}",
+		filepath.Join(nodeDir, "topology.yml"):          "type: ixrd2",
+	}
+	for f, c := range files {
+		if err := os.WriteFile(f, []byte(c), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink("config.json", filepath.Join(nodeDir, "config", "startup.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := tarPaths(buf, []string{filepath.Join(nodeDir, "config"), filepath.Join(nodeDir, "topology.yml")}); err != nil {
+		t.Fatal(err)
+	}
+
+	name := func(p string) string { return strings.TrimPrefix(p, "/") }
+	want := map[string]string{
+		name(filepath.Join(nodeDir, "config")) + "/":           "dir",
+		name(filepath.Join(nodeDir, "config", "config.json")):  "{}",
+		name(filepath.Join(nodeDir, "config", "startup.json")): "-> config.json",
+		name(filepath.Join(nodeDir, "topology.yml")):           "type: ixrd2",
+	}
+	got := map[string]string{}
+	tr := tar.NewReader(buf)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			got[hdr.Name] = "dir"
+		case tar.TypeSymlink:
+			got[hdr.Name] = "-> " + hdr.Linkname
+		default:
+			b, err := io.ReadAll(tr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got[hdr.Name] = string(b)
+		}
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("archive mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/vishvananda/netlink"
+)
+
+// SetStaticRoutes installs the static routes and the default gateways of the lab nodes
+func (c *CLab) SetStaticRoutes() {
+	for _, n := range c.Nodes {
+		c.setNodeRoutes(n.Config())
+	}
+}
+
+// setNodeRoutes installs the static routes and the default gateway of the node in its network namespace
+func (c *CLab) setNodeRoutes(cfg *types.NodeConfig) {
+	if len(cfg.StaticRoutes) == 0 && cfg.DefaultGateway == "" {
+		return
+	}
+	switch {
+	case cfg.NetworkMode == "host":
+		log.Warnf("node %s uses the host network, static routes are not installed", cfg.ShortName)
+		return
+	case nodes.IsVrKind(cfg.Kind):
+		log.Warnf("node %s runs a VM, its static routes are to be set in the node configuration", cfg.ShortName)
+		return
+	case c.remoteWiring():
+		log.Warnf("node %s runs on a remote host, static routes are not installed", cfg.ShortName)
+		return
+	}
+	if err := setRoutes(cfg); err != nil {
+		log.Errorf("failed to install static routes of node %s: %v", cfg.ShortName, err)
+		return
+	}
+	log.Infof("Installed static routes of node %s", cfg.ShortName)
+}
+
+// setRoutes sets the default gateway and installs the static routes inside the node netns
+func setRoutes(cfg *types.NodeConfig) error {
+	nodeNS, err := ns.GetNS(cfg.NSPath)
+	if err != nil {
+		return err
+	}
+	defer nodeNS.Close()
+	return nodeNS.Do(func(_ ns.NetNS) error {
+		if err := setDefaultGateway(cfg.DefaultGateway); err != nil {
+			return err
+		}
+		for _, r := range cfg.StaticRoutes {
+			route, err := netlinkRoute(r)
+			if err != nil {
+				return err
+			}
+			if err := netlink.RouteReplace(route); err != nil {
+				return fmt.Errorf("failed to install route to %s: %v", r.Dst, err)
+			}
+		}
+		return nil
+	})
+}
+
+// setDefaultGateway removes the default routes of the management interface when gw is none,
+// and replaces the default route of the gw address family with the route via gw otherwise
+func setDefaultGateway(gw string) error {
+	if gw == "" {
+		return nil
+	}
+	if gw == types.NoDefaultGateway {
+		mgmt, err := netlink.LinkByName(mgmtIfName)
+		if err != nil {
+			return fmt.Errorf("failed to lookup %s: %v", mgmtIfName, err)
+		}
+		routes, err := netlink.RouteList(mgmt, netlink.FAMILY_ALL)
+		if err != nil {
+			return err
+		}
+		for i := range routes {
+			if !isDefaultRoute(&routes[i]) {
+				continue
+			}
+			if err := netlink.RouteDel(&routes[i]); err != nil {
+				return fmt.Errorf("failed to remove default route: %v", err)
+			}
+		}
+		return nil
+	}
+	return netlink.RouteReplace(&netlink.Route{Dst: defaultDst(net.ParseIP(gw)), Gw: net.ParseIP(gw)})
+}
+
+// isDefaultRoute returns true for the IPv4 and IPv6 default routes
+func isDefaultRoute(r *netlink.Route) bool {
+	if r.Dst == nil {
+		return true
+	}
+	ones, _ := r.Dst.Mask.Size()
+	return ones == 0
+}
+
+// defaultDst returns the default prefix of the ip address family
+func defaultDst(ip net.IP) *net.IPNet {
+	if ip.To4() != nil {
+		return &net.IPNet{IP: net.IPv4zero, Mask: net.CIDRMask(0, 32)}
+	}
+	return &net.IPNet{IP: net.IPv6zero, Mask: net.CIDRMask(0, 128)}
+}
+
+// netlinkRoute returns the netlink route of the static route,
+// the interface the route points to is looked up in the current netns
+func netlinkRoute(r *types.StaticRoute) (*netlink.Route, error) {
+	_, dst, err := net.ParseCIDR(r.Dst)
+	if err != nil {
+		return nil, err
+	}
+	route := &netlink.Route{Dst: dst, Gw: net.ParseIP(r.Via), Priority: r.Metric}
+	if r.Dev != "" {
+		l, err := netlink.LinkByName(r.Dev)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lookup %s: %v", r.Dev, err)
+		}
+		route.LinkIndex = l.Attrs().Index
+	}
+	return route, nil
+}
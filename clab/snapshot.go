@@ -0,0 +1,227 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/srl-labs/containerlab/types"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// snapshotManifestFile is the snapshot manifest in the root of the snapshot bundle
+	snapshotManifestFile = "snapshot.yml"
+	// SnapshotTopoFile is the topology of the snapshot in the root of the snapshot bundle
+	SnapshotTopoFile = "topology.clab.yml"
+	// snapshotFilesDir is the directory of the snapshot bundle with the files referenced by the node definitions
+	snapshotFilesDir = "files"
+)
+
+// Snapshot is the manifest of the lab snapshot bundle
+type Snapshot struct {
+	Name    string                   `yaml:"name"`
+	Created time.Time                `yaml:"created"`
+	Nodes   map[string]*SnapshotNode `yaml:"nodes"`
+}
+
+// SnapshotNode is the state of a lab node captured in the snapshot
+type SnapshotNode struct {
+	Kind          string `yaml:"kind"`
+	Image         string `yaml:"image,omitempty"`
+	MgmtIPv4      string `yaml:"mgmt-ipv4,omitempty"`
+	MgmtIPv6      string `yaml:"mgmt-ipv6,omitempty"`
+	StartupConfig string `yaml:"startup-config,omitempty"`
+	License       string `yaml:"license,omitempty"`
+}
+
+// SnapshotFileName returns the name of the snapshot bundle of the lab taken at the time
+func SnapshotFileName(lab string, now time.Time) string {
+	return fmt.Sprintf("%s-%s.snapshot.tar.gz", lab, now.Format(archiveTimeFormat))
+}
+
+// snapshotConfig returns the copy of the lab config which deploys the lab with the state of its containers:
+// the nodes are pinned to the images and the management addresses of the containers,
+// and the startup configs and licenses of the nodes are referenced in the files directory of the bundle.
+// It returns the snapshot manifest and the files to bundle mapped to their paths in the bundle
+func (c *CLab) snapshotConfig(containers []types.GenericContainer, now time.Time) (*Config, *Snapshot, map[string]string, error) {
+	b, err := yaml.Marshal(c.Config)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	conf := new(Config)
+	if err := yaml.Unmarshal(b, conf); err != nil {
+		return nil, nil, nil, err
+	}
+	// the lab directory is restored next to the topology file
+	conf.ConfigPath = "."
+	// the group settings are merged into the node definitions already
+	conf.Topology.Groups = nil
+
+	images := map[string]string{}
+	for _, ctr := range containers {
+		images[ctr.Labels[NodeNameLabel]] = ctr.Image
+	}
+	snap := &Snapshot{Name: c.Config.Name, Created: now, Nodes: map[string]*SnapshotNode{}}
+	files := map[string]string{}
+	for name, n := range c.Nodes {
+		cfg := n.Config()
+		def := conf.Topology.Nodes[name]
+		if def == nil {
+			def = new(types.NodeDefinition)
+			conf.Topology.Nodes[name] = def
+		}
+		sn := &SnapshotNode{Kind: cfg.Kind, Image: cfg.Image}
+		if img := images[name]; img != "" {
+			sn.Image = img
+		}
+		if sn.Image != "" {
+			def.Image = sn.Image
+		}
+		if _, ok := noMgmtKinds[cfg.Kind]; !ok && cfg.NetworkMode == "" {
+			sn.MgmtIPv4, sn.MgmtIPv6 = cfg.MgmtIPv4Address, cfg.MgmtIPv6Address
+			def.MgmtIPv4, def.MgmtIPv6 = sn.MgmtIPv4, sn.MgmtIPv6
+		}
+		for _, f := range []struct {
+			src      string
+			def, dst *string
+		}{
+			{cfg.StartupConfig, &def.StartupConfig, &sn.StartupConfig},
+			{cfg.License, &def.License, &sn.License},
+		} {
+			if fi, err := os.Stat(f.src); f.src == "" || err != nil || !fi.Mode().IsRegular() {
+				continue
+			}
+			p := filepath.ToSlash(filepath.Join(snapshotFilesDir, name, filepath.Base(f.src)))
+			files[p] = f.src
+			*f.def, *f.dst = p, p
+		}
+		snap.Nodes[name] = sn
+	}
+	return conf, snap, files, nil
+}
+
+// CreateSnapshot writes the tar.gz snapshot bundle of the deployed lab to w.
+// The bundle contains the snapshot manifest, the topology deploying the lab with the state of the containers,
+// the startup configs and licenses of the nodes and the lab directory with the node configs and certificates
+func (c *CLab) CreateSnapshot(w io.Writer, containers []types.GenericContainer, now time.Time) error {
+	conf, snap, files, err := c.snapshotConfig(containers, now)
+	if err != nil {
+		return err
+	}
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	for _, f := range []struct {
+		name string
+		v    interface{}
+	}{{snapshotManifestFile, snap}, {SnapshotTopoFile, conf}} {
+		b, err := yaml.Marshal(f.v)
+		if err != nil {
+			return err
+		}
+		if err := addBytesToArchive(tw, b, f.name, now); err != nil {
+			return err
+		}
+	}
+	names := make([]string, 0, len(files))
+	for p := range files {
+		names = append(names, p)
+	}
+	sort.Strings(names)
+	for _, p := range names {
+		if err := addFileToArchive(tw, files[p], p); err != nil {
+			return err
+		}
+	}
+	if _, err := os.Stat(c.Dir.Lab); err == nil {
+		if err := addDirToArchive(tw, c.Dir.Lab, filepath.Base(c.Dir.Lab)); err != nil {
+			return fmt.Errorf("failed to add lab directory %s to the snapshot: %v", c.Dir.Lab, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// RestoreSnapshot extracts the snapshot bundle read from r to the directory dir
+// and returns the snapshot manifest and the path to the topology file deploying the lab of the snapshot
+func RestoreSnapshot(r io.Reader, dir string) (*Snapshot, string, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read snapshot: %v", err)
+	}
+	defer gr.Close()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, "", err
+	}
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read snapshot: %v", err)
+		}
+		if err := extractArchiveEntry(tr, hdr, dir); err != nil {
+			return nil, "", err
+		}
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, snapshotManifestFile))
+	if err != nil {
+		return nil, "", fmt.Errorf("snapshot manifest is not found: %v", err)
+	}
+	snap := new(Snapshot)
+	if err := yaml.Unmarshal(b, snap); err != nil {
+		return nil, "", fmt.Errorf("failed to parse snapshot manifest: %v", err)
+	}
+	return snap, filepath.Join(dir, SnapshotTopoFile), nil
+}
+
+// extractArchiveEntry writes the archive entry to the directory dir,
+// the entries pointing outside of dir are rejected
+func extractArchiveEntry(tr *tar.Reader, hdr *tar.Header, dir string) error {
+	p := filepath.Join(dir, filepath.FromSlash(hdr.Name))
+	if p != filepath.Clean(dir) && !strings.HasPrefix(p, filepath.Clean(dir)+string(filepath.Separator)) {
+		return fmt.Errorf("snapshot entry %q is outside of the snapshot directory", hdr.Name)
+	}
+	mode := os.FileMode(hdr.Mode).Perm()
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		return os.MkdirAll(p, mode|0700)
+	case tar.TypeSymlink:
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			return err
+		}
+		return os.Symlink(hdr.Linkname, p)
+	case tar.TypeReg:
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(p, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, tr); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo1.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Dir.Lab = filepath.Join(t.TempDir(), "clab-topo1")
+	if err := os.MkdirAll(filepath.Join(c.Dir.Lab, "node1", "config"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(c.Dir.Lab, "node1", "config", "config.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	containers := []types.GenericContainer{
+		{Labels: map[string]string{NodeNameLabel: "node1"}, Image: "srlinux:21.6.4", State: "running"},
+	}
+
+	buf := new(bytes.Buffer)
+	now := time.Date(2021, 6, 1, 10, 20, 30, 0, time.UTC)
+	if err := c.CreateSnapshot(buf, containers, now); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	snap, topo, err := RestoreSnapshot(buf, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Snapshot{
+		Name:    "topo1",
+		Created: now,
+		Nodes: map[string]*SnapshotNode{
+			"node1": {Kind: "srl", Image: "srlinux:21.6.4", MgmtIPv4: "172.100.100.11", License: "files/node1/node1.lic"},
+			"node2": {Kind: "srl", Image: c.Nodes["node2"].Config().Image, MgmtIPv4: "172.100.100.12", License: "files/node2/node1.lic"},
+		},
+	}
+	if d := cmp.Diff(want, snap); d != "" {
+		t.Errorf("snapshot mismatch (-want +got):\n%s", d)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "clab-topo1", "node1", "config", "config.json")); err != nil {
+		t.Errorf("lab directory is not restored: %v", err)
+	}
+
+	// the restored topology deploys the lab with the state of the snapshot,
+	// the files of the nodes are relative to the snapshot directory
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewContainerLab(WithTopoFile(topo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Dir.Lab != filepath.Join(dir, "clab-topo1") {
+		t.Errorf("wanted lab directory %s, got %s", filepath.Join(dir, "clab-topo1"), r.Dir.Lab)
+	}
+	for name, sn := range want.Nodes {
+		cfg := r.Nodes[name].Config()
+		got := &SnapshotNode{Kind: cfg.Kind, Image: cfg.Image, MgmtIPv4: cfg.MgmtIPv4Address, License: cfg.License}
+		wantNode := *sn
+		wantNode.License = filepath.Join(dir, sn.License)
+		if d := cmp.Diff(&wantNode, got); d != "" {
+			t.Errorf("restored node %s mismatch (-want +got):\n%s", name, d)
+		}
+	}
+}
+
+func TestRestoreSnapshotOutsideDir(t *testing.T) {
+	buf := new(bytes.Buffer)
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+	if err := addBytesToArchive(tw, []byte("x"), "../evil", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	tw.Close()
+	gw.Close()
+	if _, _, err := RestoreSnapshot(buf, t.TempDir()); err == nil {
+		t.Error("expected error for the snapshot entry outside of the snapshot directory")
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+// sshConfigFile is the name of the lab nodes ssh_config file in the lab directory
+const sshConfigFile = "ssh_config"
+
+// SSHConfig returns the ssh_config snippet with the entries of the lab nodes running an SSH server,
+// the nodes are reachable by their names, the <node>.<lab> and the container names.
+// proxyJump is set as the ProxyJump of the entries when not empty
+func (c *CLab) SSHConfig(proxyJump string) string {
+	names := make([]string, 0, len(c.Nodes))
+	for name := range c.Nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		cfg := c.Nodes[name].Config()
+		ka, ok := nodeAccess(cfg)
+		if !ok || ka.ssh == 0 {
+			continue
+		}
+		addr := cfg.MgmtIPv4Address
+		if addr == "" {
+			addr = cfg.MgmtIPv6Address
+		}
+		if addr == "" {
+			continue
+		}
+		if b.Len() != 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "Host %s %s.%s %s\n", name, name, c.Config.Name, cfg.LongName)
+		fmt.Fprintf(&b, "    HostName %s\n", addr)
+		if ka.ssh != 22 {
+			fmt.Fprintf(&b, "    Port %d\n", ka.ssh)
+		}
+		if u := nodes.SaveCredentials(cfg).Username; u != "" {
+			fmt.Fprintf(&b, "    User %s\n", u)
+		}
+		if proxyJump != "" {
+			fmt.Fprintf(&b, "    ProxyJump %s\n", proxyJump)
+		}
+		// the host keys of the nodes change with every deployment
+		b.WriteString("    StrictHostKeyChecking no\n")
+		b.WriteString("    UserKnownHostsFile /dev/null\n")
+	}
+	return b.String()
+}
+
+// sshProxyJump returns the ProxyJump the lab nodes are reached with,
+// the management network of the lab running on a remote container host is reached through that host
+func (c *CLab) sshProxyJump() string {
+	r := c.GlobalRuntime()
+	if r == nil || !runtime.IsRemoteHost(r.Config().Host) {
+		return ""
+	}
+	return proxyJumpOf(r.Config().Host)
+}
+
+// proxyJumpOf returns the ProxyJump destination of the container runtime daemon address,
+// e.g. user@lab-server for ssh://user@lab-server. The daemon port is dropped for the non-ssh addresses
+func proxyJumpOf(host string) string {
+	u, err := url.Parse(host)
+	if err != nil || u.Hostname() == "" {
+		return ""
+	}
+	dst := u.Hostname()
+	if u.Scheme == "ssh" && u.Port() != "" {
+		dst = u.Host
+	}
+	if u.User != nil && u.User.Username() != "" {
+		dst = u.User.Username() + "@" + dst
+	}
+	return dst
+}
+
+// WriteSSHConfig writes the ssh_config snippet of the lab nodes to the lab directory
+// and returns the path of the written file
+func (c *CLab) WriteSSHConfig() (string, error) {
+	if err := os.MkdirAll(c.Dir.Lab, 0755); err != nil {
+		return "", err
+	}
+	p := SSHConfigPath(c.Dir.Lab)
+	return p, ioutil.WriteFile(p, []byte(c.SSHConfig(c.sshProxyJump())), 0644)
+}
+
+// SSHConfigPath returns the path to the ssh_config file of the lab nodes in the lab directory labDir
+func SSHConfigPath(labDir string) string {
+	return filepath.Join(labDir, sshConfigFile)
+}
+
+// UserSSHConfig returns the path to the ssh client config of the user running containerlab,
+// for containerlab run with sudo it is the config of the user invoking sudo
+func UserSSHConfig() (string, error) {
+	if name := os.Getenv("SUDO_USER"); name != "" {
+		u, err := user.Lookup(name)
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(u.HomeDir, ".ssh", "config"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".ssh", "config"), nil
+}
+
+// sshInclude returns the Include directive of the ssh_config file p
+func sshInclude(p string) string {
+	return "Include " + p
+}
+
+// InstallSSHConfigInclude adds the Include directive of the lab ssh_config file p to the top of the ssh client config,
+// as the directives following a Host entry apply to that entry only.
+// It returns false when the directive is already present
+func InstallSSHConfigInclude(config, p string) (bool, error) {
+	b, err := ioutil.ReadFile(config)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	for _, l := range strings.Split(string(b), "\n") {
+		if strings.TrimSpace(l) == sshInclude(p) {
+			return false, nil
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(config), 0700); err != nil {
+		return false, err
+	}
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "%s\n", sshInclude(p))
+	if len(b) != 0 {
+		buf.WriteString("\n")
+		buf.Write(b)
+	}
+	if err := ioutil.WriteFile(config, buf.Bytes(), 0600); err != nil {
+		return false, err
+	}
+	return true, chownToSudoUser(config)
+}
+
+// RemoveSSHConfigInclude removes the Include directive of the lab ssh_config file p from the ssh client config
+func RemoveSSHConfigInclude(config, p string) error {
+	b, err := ioutil.ReadFile(config)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(b), "\n")
+	res := make([]string, 0, len(lines))
+	for i := 0; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) != sshInclude(p) {
+			res = append(res, lines[i])
+			continue
+		}
+		// the empty line separating the directive from the rest of the config is removed as well
+		if i+1 < len(lines) && lines[i+1] == "" && i+2 < len(lines) {
+			i++
+		}
+	}
+	if len(res) == len(lines) {
+		return nil
+	}
+	return ioutil.WriteFile(config, []byte(strings.Join(res, "\n")), 0600)
+}
+
+// chownToSudoUser makes the user invoking sudo the owner of the ssh config and its directory created by root
+func chownToSudoUser(p string) error {
+	name := os.Getenv("SUDO_USER")
+	if name == "" {
+		return nil
+	}
+	u, err := user.Lookup(name)
+	if err != nil {
+		return err
+	}
+	uid, err := strconv.Atoi(u.Uid)
+	if err != nil {
+		return err
+	}
+	gid, err := strconv.Atoi(u.Gid)
+	if err != nil {
+		return err
+	}
+	for _, f := range []string{filepath.Dir(p), p} {
+		if err := os.Chown(f, uid, gid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSSHConfig(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo12.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// node2 is a linux node without an ssh server
+	want := `Host node1 node1.topo12 clab-topo12-node1
+    HostName 172.100.100.11
+    User admin
+    ProxyJump admin@lab-server
+    StrictHostKeyChecking no
+    UserKnownHostsFile /dev/null
+`
+	got := c.SSHConfig("admin@lab-server")
+	if !cmp.Equal(got, want) {
+		t.Errorf("diff (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestProxyJumpOf(t *testing.T) {
+	tests := map[string]string{
+		"ssh://admin@lab-server":      "admin@lab-server",
+		"ssh://admin@lab-server:2222": "admin@lab-server:2222",
+		"tcp://lab-server:2376":       "lab-server",
+		"unix:///var/run/docker.sock": "",
+	}
+	for host, want := range tests {
+		t.Run(host, func(t *testing.T) {
+			if got := proxyJumpOf(host); got != want {
+				t.Errorf("got: %q, want: %q", got, want)
+			}
+		})
+	}
+}
+
+func TestSSHConfigInclude(t *testing.T) {
+	cfg := filepath.Join(t.TempDir(), ".ssh", "config")
+	p := "/tmp/clab-topo12/ssh_config"
+	userCfg := "Host lab-server\n    User admin\n"
+	if err := os.MkdirAll(filepath.Dir(cfg), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(cfg, []byte(userCfg), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, wantAdded := range []bool{true, false} {
+		added, err := InstallSSHConfigInclude(cfg, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if added != wantAdded {
+			t.Errorf("install %d: expected added %v, got %v", i, wantAdded, added)
+		}
+	}
+	b, _ := ioutil.ReadFile(cfg)
+	if want := "Include " + p + "\n\n" + userCfg; string(b) != want {
+		t.Errorf("diff after install (-want +got):\n%s", cmp.Diff(want, string(b)))
+	}
+
+	if err := RemoveSSHConfigInclude(cfg, p); err != nil {
+		t.Fatal(err)
+	}
+	b, _ = ioutil.ReadFile(cfg)
+	if string(b) != userCfg {
+		t.Errorf("diff after removal (-want +got):\n%s", cmp.Diff(userCfg, string(b)))
+	}
+}
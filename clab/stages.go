@@ -0,0 +1,158 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes"
+)
+
+// deployStages orders the lab nodes by their dependencies into stages,
+// the nodes of a stage depend only on the nodes of the preceding stages.
+// The node names of the stages are sorted
+func (c *CLab) deployStages() ([][]string, error) {
+	stage := make(map[string]int, len(c.Nodes))
+	// visiting marks the nodes on the current dependency path to detect the cycles
+	visiting := map[string]bool{}
+	var visit func(name string, path []string) (int, error)
+	visit = func(name string, path []string) (int, error) {
+		if s, ok := stage[name]; ok {
+			return s, nil
+		}
+		if visiting[name] {
+			return 0, fmt.Errorf("dependency cycle %s", strings.Join(append(path, name), " -> "))
+		}
+		visiting[name] = true
+		s := 0
+		for _, d := range c.Nodes[name].Config().DependsOn {
+			if _, ok := c.Nodes[d]; !ok {
+				return 0, fmt.Errorf("node %q depends on unknown node %q", name, d)
+			}
+			ds, err := visit(d, append(path, name))
+			if err != nil {
+				return 0, err
+			}
+			if ds+1 > s {
+				s = ds + 1
+			}
+		}
+		visiting[name] = false
+		stage[name] = s
+		return s, nil
+	}
+
+	names := make([]string, 0, len(c.Nodes))
+	for name := range c.Nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var stages [][]string
+	for _, name := range names {
+		s, err := visit(name, nil)
+		if err != nil {
+			return nil, err
+		}
+		for len(stages) <= s {
+			stages = append(stages, nil)
+		}
+		stages[s] = append(stages[s], name)
+	}
+	return stages, nil
+}
+
+// destroyStages returns the deploy stages of the nodes in the reverse order, so that the nodes are destroyed
+// before the nodes they depend on. Only the nodes of ns are kept in the stages
+func (c *CLab) destroyStages(ns map[string]nodes.Node) ([][]string, error) {
+	stages, err := c.deployStages()
+	if err != nil {
+		return nil, err
+	}
+	var res [][]string
+	for i := len(stages) - 1; i >= 0; i-- {
+		var stage []string
+		for _, name := range stages[i] {
+			if _, ok := ns[name]; ok {
+				stage = append(stage, name)
+			}
+		}
+		if len(stage) > 0 {
+			res = append(res, stage)
+		}
+	}
+	return res, nil
+}
+
+// verifyDependencies verifies that the node dependencies reference the lab nodes and have no cycles
+func (c *CLab) verifyDependencies() error {
+	_, err := c.deployStages()
+	return err
+}
+
+// createNodesInStages deploys the nodes stage by stage, the next stage is started
+// once the nodes of the current stage are deployed and the nodes other nodes depend on are ready
+func (c *CLab) createNodesInStages(ctx context.Context, maxWorkers uint,
+	serialNodes map[string]struct{}, stages [][]string) *sync.WaitGroup {
+	dependedOn := map[string]struct{}{}
+	for _, n := range c.Nodes {
+		for _, d := range n.Config().DependsOn {
+			dependedOn[d] = struct{}{}
+		}
+	}
+
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i, stage := range stages {
+			staticIPNodes := make(map[string]nodes.Node)
+			dynIPNodes := make(map[string]nodes.Node)
+			for _, name := range stage {
+				n := c.Nodes[name]
+				if n.Config().MgmtIPv4Address != "" || n.Config().MgmtIPv6Address != "" {
+					staticIPNodes[name] = n
+					continue
+				}
+				dynIPNodes[name] = n
+			}
+			log.Infof("Deploying stage %d: %s", i+1, strings.Join(stage, ", "))
+			// nodes with static IPs are scheduled first, as in the unstaged deployment
+			if len(staticIPNodes) > 0 {
+				c.createNodes(ctx, int(maxWorkers), serialNodes, staticIPNodes).Wait()
+			}
+			if len(dynIPNodes) > 0 {
+				c.createNodes(ctx, int(maxWorkers), serialNodes, dynIPNodes).Wait()
+			}
+			if i == len(stages)-1 {
+				return
+			}
+			c.waitStageReady(ctx, stage, dependedOn)
+		}
+	}()
+	return wg
+}
+
+// waitStageReady waits for the nodes of the stage other nodes depend on to become ready
+func (c *CLab) waitStageReady(ctx context.Context, stage []string, dependedOn map[string]struct{}) {
+	wg := new(sync.WaitGroup)
+	for _, name := range stage {
+		if _, ok := dependedOn[name]; !ok {
+			continue
+		}
+		wg.Add(1)
+		go func(n nodes.Node) {
+			defer wg.Done()
+			if err := WaitForNode(ctx, n, true); err != nil {
+				log.Errorf("%v, deploying its dependent nodes anyway", err)
+			}
+		}(c.Nodes[name])
+	}
+	wg.Wait()
+}
@@ -0,0 +1,98 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/nodes"
+)
+
+func TestDeployStages(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo21.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// rr depends on itself via its kind, the self dependency is dropped
+	want := [][]string{
+		{"license", "rr"},
+		{"client1", "client2"},
+		{"tgen"},
+	}
+	got, err := c.deployStages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("diff (-want +got):\n%s", cmp.Diff(want, got))
+	}
+
+	c.Nodes["rr"].Config().DependsOn = []string{"tgen"}
+	if _, err := c.deployStages(); err == nil {
+		t.Error("wanted dependency cycle error")
+	}
+
+	c.Nodes["rr"].Config().DependsOn = []string{"ghost"}
+	if _, err := c.deployStages(); err == nil {
+		t.Error("wanted unknown node error")
+	}
+}
+
+func TestDestroyStages(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo21.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the nodes are destroyed before the nodes they depend on
+	want := [][]string{
+		{"tgen"},
+		{"client1", "client2"},
+		{"license", "rr"},
+	}
+	got, err := c.destroyStages(c.Nodes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("diff (-want +got):\n%s", d)
+	}
+
+	// the nodes not being deleted are dropped along with the emptied stages
+	ns := map[string]nodes.Node{"rr": c.Nodes["rr"], "tgen": c.Nodes["tgen"]}
+	want = [][]string{{"tgen"}, {"rr"}}
+	got, err = c.destroyStages(ns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("diff (-want +got):\n%s", d)
+	}
+}
+
+func TestNodeStopTimeout(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo21.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the node stop-timeout takes precedence over the kind one
+	want := map[string]time.Duration{
+		"rr":      30 * time.Second,
+		"license": 5 * time.Minute,
+		"client1": 30 * time.Second,
+		"tgen":    time.Minute,
+	}
+	for name, d := range want {
+		if got := c.Nodes[name].Config().StopTimeout; got != d {
+			t.Errorf("node %s: got stop timeout %s, want %s", name, got, d)
+		}
+	}
+
+	c.Config.Topology.Nodes["tgen"].StopTimeout = "soon"
+	if _, err := c.createNodeCfg("tgen", c.Config.Topology.Nodes["tgen"], 0); err == nil {
+		t.Error("wanted invalid stop-timeout error")
+	}
+}
@@ -0,0 +1,271 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	// labHostEnv sets the name of the local lab host when it can't be found by the host addresses
+	labHostEnv = "CLAB_LAB_HOST"
+	// default VNI of the first link stitched between the lab hosts
+	defaultVNIBase = 1000
+	// default UDP port of the VxLAN tunnels between the lab hosts,
+	// different from the IANA port to not collide with the VxLAN interfaces of the host
+	defaultVxlanPort = 14789
+	// overhead of the VxLAN encapsulation over IPv4
+	vxlanOverhead = 50
+	maxVNI        = 1<<24 - 1
+)
+
+// localLabHost returns the name of the lab host containerlab runs on.
+// The host is set with the CLAB_LAB_HOST env var or found by the addresses of the local interfaces
+func localLabHost(hosts map[string]*types.LabHost) (string, error) {
+	if h := os.Getenv(labHostEnv); h != "" {
+		if _, ok := hosts[h]; !ok {
+			return "", fmt.Errorf("lab host %q set with %s is not defined in the lab hosts", h, labHostEnv)
+		}
+		return h, nil
+	}
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return "", err
+	}
+	names := make([]string, 0, len(hosts))
+	for name := range hosts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ip := net.ParseIP(hosts[name].Address)
+		for _, a := range addrs {
+			if ipNet, ok := a.(*net.IPNet); ok && ipNet.IP.Equal(ip) {
+				return name, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("none of the lab hosts %v has an address of this host, set the %s env var to the name of the local lab host", names, labHostEnv)
+}
+
+// validateLabHosts checks the addresses of the lab hosts and the VxLAN settings
+func validateLabHosts(hosts map[string]*types.LabHost, vx *types.VxlanSettings) error {
+	for name, h := range hosts {
+		if h == nil || net.ParseIP(h.Address) == nil {
+			return fmt.Errorf("lab host %q has no valid IP address", name)
+		}
+	}
+	if vx == nil {
+		return nil
+	}
+	if vx.VNIBase < 0 || vx.VNIBase > maxVNI {
+		return fmt.Errorf("VxLAN VNI base %d is out of the range 1-%d", vx.VNIBase, maxVNI)
+	}
+	if vx.Port < 0 || vx.Port > 65535 {
+		return fmt.Errorf("VxLAN port %d is out of the range 1-65535", vx.Port)
+	}
+	return nil
+}
+
+// labHosts are the lab hosts of a multi-host lab
+type labHosts struct {
+	hosts map[string]*types.LabHost
+	// name of the lab host containerlab runs on
+	local string
+	// lab hosts of the nodes deployed on the other lab hosts
+	remote  map[string]string
+	vniBase int
+	port    int
+}
+
+// initLabHosts finds the local lab host of a multi-host lab and the nodes deployed on the other lab hosts.
+// Every node of a multi-host lab is deployed on the lab host set with its lab-host
+func (c *CLab) initLabHosts() error {
+	hosts := c.Config.Settings.GetLabHosts()
+	if len(hosts) == 0 {
+		return nil
+	}
+	vx := c.Config.Settings.GetVxlan()
+	if err := validateLabHosts(hosts, vx); err != nil {
+		return err
+	}
+	lh := &labHosts{
+		hosts:   hosts,
+		remote:  map[string]string{},
+		vniBase: defaultVNIBase,
+		port:    defaultVxlanPort,
+	}
+	if vx != nil && vx.VNIBase != 0 {
+		lh.vniBase = vx.VNIBase
+	}
+	if vx != nil && vx.Port != 0 {
+		lh.port = vx.Port
+	}
+	var err error
+	if lh.local, err = localLabHost(hosts); err != nil {
+		return err
+	}
+	log.Debugf("deploying the nodes of lab host %s", lh.local)
+	for name := range c.Config.Topology.Nodes {
+		h := c.Config.Topology.GetNodeLabHost(name)
+		if h == "" {
+			return fmt.Errorf("node %q has no lab host, set the lab-host of the node, its kind or the defaults", name)
+		}
+		if hosts[h] == nil {
+			return fmt.Errorf("node %q lab host %q is not defined in the lab hosts", name, h)
+		}
+		if h != lh.local {
+			lh.remote[name] = h
+		}
+	}
+	c.labHosts = lh
+	return nil
+}
+
+// isRemoteNode returns true when the node is deployed on another lab host
+func (c *CLab) isRemoteNode(name string) bool {
+	if c.labHosts == nil {
+		return false
+	}
+	_, ok := c.labHosts.remote[name]
+	return ok
+}
+
+// newStitchedLink initializes the i-th link of the topology of a multi-host lab.
+// The link between the local and the remote nodes is stitched with the VxLAN tunnel to the remote lab host,
+// its remote endpoint is replaced with the veth interface in the host netns.
+// The VNI of the tunnel is derived from the index of the link in the topology,
+// so that the lab hosts deploying the same topology file agree on it.
+// The links between the remote nodes and the macvlan and ipvlan links of the remote nodes
+// are skipped, nil is returned for them
+func (c *CLab) newStitchedLink(i int, l *types.LinkConfig) (*types.Link, error) {
+	if c.labHosts == nil || len(l.Endpoints) != 2 {
+		return c.NewLink(l), nil
+	}
+	remote := -1
+	for j, e := range l.Endpoints {
+		if !c.isRemoteNode(strings.SplitN(e, ":", 2)[0]) {
+			continue
+		}
+		if remote != -1 {
+			return nil, nil
+		}
+		remote = j
+	}
+	if remote == -1 {
+		return c.NewLink(l), nil
+	}
+	// the host NIC of the macvlan and ipvlan links is on the lab host of the remote node
+	if l.Type == linkTypeMacvlan || l.Type == linkTypeIPvlan {
+		return nil, nil
+	}
+	vni := c.labHosts.vniBase + i
+	if vni > maxVNI {
+		return nil, fmt.Errorf("link %v: VNI %d is out of the range 1-%d", l.Endpoints, vni, maxVNI)
+	}
+	h := c.labHosts.remote[strings.SplitN(l.Endpoints[remote], ":", 2)[0]]
+	s := &types.VxlanStitch{
+		Host:   h,
+		Remote: net.ParseIP(c.labHosts.hosts[h].Address),
+		VNI:    vni,
+		Port:   c.labHosts.port,
+		Name:   fmt.Sprintf("vx-%d", vni),
+		HostIf: fmt.Sprintf("vs-%d", vni),
+	}
+	log.Debugf("link %v: stitching %s on lab host %s with VNI %d", l.Endpoints, l.Endpoints[remote], h, vni)
+	lc := *l
+	lc.Endpoints = []string{l.Endpoints[0], l.Endpoints[1]}
+	lc.Endpoints[remote] = "host:" + s.HostIf
+	link := c.NewLink(&lc)
+	link.Stitch = s
+	return link, nil
+}
+
+// dropRemoteDependencies removes the dependencies on the nodes deployed on the other lab hosts,
+// as they can't be waited on
+func (c *CLab) dropRemoteDependencies() {
+	for _, n := range c.Nodes {
+		cfg := n.Config()
+		var deps []string
+		for _, d := range cfg.DependsOn {
+			if h, ok := c.labHosts.remote[d]; ok {
+				log.Warnf("node %s dependency on %s deployed on lab host %s is ignored", cfg.ShortName, d, h)
+				continue
+			}
+			deps = append(deps, d)
+		}
+		cfg.DependsOn = deps
+	}
+}
+
+// createVxlanStitch wires the link which remote endpoint is deployed on another lab host.
+// The local endpoint is connected to the host netns with a veth and the host side of the veth
+// is stitched to the VxLAN tunnel towards the remote lab host with the tc redirect rules
+func (c *CLab) createVxlanStitch(l *types.Link) error {
+	s := l.Stitch
+	if err := c.CreateVirtualWiring(l); err != nil {
+		return err
+	}
+	parentIf, err := VxlanParentIf(s.Remote)
+	if err != nil {
+		return err
+	}
+	parent, err := netlink.LinkByName(parentIf)
+	if err != nil {
+		return fmt.Errorf("failed to get VxLAN parent interface %s: %v", parentIf, err)
+	}
+	mtu := parent.Attrs().MTU - vxlanOverhead
+	if l.MTU > mtu {
+		log.Warnf("%s: link MTU %d exceeds the MTU %d of the VxLAN tunnel to lab host %s, larger frames are dropped", l, l.MTU, mtu, s.Host)
+	}
+	// the tunnel is left over by a previous deployment of the lab
+	if err := deleteVxlanStitch(s); err != nil {
+		return err
+	}
+	err = AddVxLanInterface(VxLAN{
+		Name:     s.Name,
+		ParentIf: parentIf,
+		ID:       s.VNI,
+		Remote:   s.Remote,
+		MTU:      mtu,
+		UDPPort:  s.Port,
+	})
+	if err != nil {
+		return err
+	}
+	return BindIfacesWithTC(s.Name, s.HostIf)
+}
+
+// DeleteVxlanStitches deletes the VxLAN tunnels of the links stitched to the other lab hosts
+func (c *CLab) DeleteVxlanStitches() {
+	for _, l := range c.Links {
+		if l.Stitch == nil {
+			continue
+		}
+		log.Debugf("Deleting VxLAN link %s", l.Stitch.Name)
+		if err := deleteVxlanStitch(l.Stitch); err != nil {
+			log.Warnf("failed to delete VxLAN link %s: %v", l.Stitch.Name, err)
+		}
+	}
+}
+
+func deleteVxlanStitch(s *types.VxlanStitch) error {
+	link, err := netlink.LinkByName(s.Name)
+	if err != nil {
+		if _, ok := err.(netlink.LinkNotFoundError); ok {
+			return nil
+		}
+		return err
+	}
+	return netlink.LinkDel(link)
+}
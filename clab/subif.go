@@ -0,0 +1,130 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"fmt"
+	"net"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	linkTypeVeth    = "veth"
+	linkTypeMacvlan = "macvlan"
+	linkTypeIPvlan  = "ipvlan"
+)
+
+var (
+	// macvlanModes maps the modes of the macvlan links to the netlink modes,
+	// the empty mode is the default bridge mode
+	macvlanModes = map[string]netlink.MacvlanMode{
+		"":         netlink.MACVLAN_MODE_BRIDGE,
+		"bridge":   netlink.MACVLAN_MODE_BRIDGE,
+		"vepa":     netlink.MACVLAN_MODE_VEPA,
+		"private":  netlink.MACVLAN_MODE_PRIVATE,
+		"passthru": netlink.MACVLAN_MODE_PASSTHRU,
+	}
+	// ipvlanModes maps the modes of the ipvlan links to the netlink modes,
+	// the empty mode is the default l2 mode
+	ipvlanModes = map[string]netlink.IPVlanMode{
+		"":    netlink.IPVLAN_MODE_L2,
+		"l2":  netlink.IPVLAN_MODE_L2,
+		"l3":  netlink.IPVLAN_MODE_L3,
+		"l3s": netlink.IPVLAN_MODE_L3S,
+	}
+)
+
+// isSubIfLink returns true when the link is realized as a macvlan or ipvlan sub-interface of a host NIC
+func isSubIfLink(l *types.Link) bool {
+	return l.Type == linkTypeMacvlan || l.Type == linkTypeIPvlan
+}
+
+// verifyLinkTypes checks the types and modes of the links.
+// The macvlan and ipvlan links connect a node to the host NIC referenced by the host endpoint
+func (c *CLab) verifyLinkTypes() error {
+	for _, l := range c.Links {
+		var ok bool
+		switch l.Type {
+		case "", linkTypeVeth:
+			if l.Mode != "" {
+				return fmt.Errorf("%s: mode %q is only supported by the macvlan and ipvlan links", l, l.Mode)
+			}
+			continue
+		case linkTypeMacvlan:
+			_, ok = macvlanModes[l.Mode]
+		case linkTypeIPvlan:
+			_, ok = ipvlanModes[l.Mode]
+		default:
+			return fmt.Errorf("%s: unknown link type %q, expected one of veth, macvlan, ipvlan", l, l.Type)
+		}
+		if !ok {
+			return fmt.Errorf("%s: unknown %s mode %q", l, l.Type, l.Mode)
+		}
+		host, peer := hostEndpoints(l)
+		if host == nil {
+			return fmt.Errorf("%s: %s links connect a node to a host NIC and need one host endpoint", l, l.Type)
+		}
+		if peer.Node.Kind == nodes.NodeKindBridge || peer.Node.Kind == nodes.NodeKindOVS {
+			return fmt.Errorf("%s: %s links can't be connected to the bridge %s", l, l.Type, peer.Node.ShortName)
+		}
+		if l.VLAN != nil {
+			return fmt.Errorf("%s: vlan settings are not supported for the %s links", l, l.Type)
+		}
+	}
+	return nil
+}
+
+// createSubIfLink creates the macvlan or ipvlan sub-interface of the host NIC referenced by the host endpoint of the link
+// and moves it to the node netns under the node endpoint name
+func (c *CLab) createSubIfLink(l *types.Link) error {
+	host, peer := hostEndpoints(l)
+	log.Infof("Creating %s link: %s:%s <--> host NIC %s", l.Type, peer.Node.ShortName, peer.EndpointName, host.EndpointName)
+	parent, err := netlink.LinkByName(host.EndpointName)
+	if err != nil {
+		return fmt.Errorf("failed to lookup host interface %q: %v", host.EndpointName, err)
+	}
+	// the sub-interface can't have a larger MTU than its parent
+	mtu := l.MTU
+	if pmtu := parent.Attrs().MTU; mtu > pmtu {
+		log.Debugf("%s: using the MTU %d of the host interface %s", l, pmtu, host.EndpointName)
+		mtu = pmtu
+	}
+	attrs := netlink.LinkAttrs{
+		Name:        fmt.Sprintf("clab-%s", genIfName()),
+		ParentIndex: parent.Attrs().Index,
+		MTU:         mtu,
+	}
+	var link netlink.Link
+	switch l.Type {
+	case linkTypeMacvlan:
+		if attrs.HardwareAddr, err = net.ParseMAC(peer.MAC); err != nil {
+			return err
+		}
+		link = &netlink.Macvlan{LinkAttrs: attrs, Mode: macvlanModes[l.Mode]}
+	case linkTypeIPvlan:
+		// ipvlan sub-interfaces share the MAC of the parent
+		link = &netlink.IPVlan{LinkAttrs: attrs, Mode: ipvlanModes[l.Mode]}
+	}
+	if err := netlink.LinkAdd(link); err != nil {
+		return fmt.Errorf("failed to create %s sub-interface of %s: %v", l.Type, host.EndpointName, err)
+	}
+	ep := &vEthEndpoint{
+		Link:     link,
+		LinkName: peer.EndpointName,
+		NSName:   peer.Node.LongName,
+		NSPath:   peer.Node.NSPath,
+		Profile:  peer.Node.InterfaceProfile,
+		Netem:    l.Netem,
+	}
+	if err := ep.toNS(); err != nil {
+		_ = netlink.LinkDel(link)
+		return err
+	}
+	return nil
+}
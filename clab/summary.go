@@ -0,0 +1,253 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// kindAccess holds the management services of a kind
+type kindAccess struct {
+	// ssh and gnmi ports, 0 if the service is not available
+	ssh  int
+	gnmi int
+	// cli command executed in a container to reach the node's CLI
+	cli string
+	// telnet port of the node's serial console
+	telnet int
+}
+
+// vrnetlab based kinds expose the serial console over telnet
+var vrAccess = &kindAccess{ssh: 22, telnet: nodes.VrConsolePort}
+
+// kindsAccess holds the management services per each kind,
+// kinds without an entry are not provided with the access details
+var kindsAccess = map[string]*kindAccess{
+	nodes.NodeKindSRL:       {ssh: 22, gnmi: 57400, cli: "sr_cli"},
+	nodes.NodeKindCEOS:      {ssh: 22, gnmi: 6030, cli: "Cli"},
+	nodes.NodeKindCRPD:      {ssh: 22, cli: "cli"},
+	nodes.NodeKindCVX:       {ssh: 22},
+	nodes.NodeKindSonic:     {cli: "vtysh"},
+	nodes.NodeKindLinux:     {cli: "sh"},
+	nodes.NodeKindVrCSR:     vrAccess,
+	nodes.NodeKindVrPAN:     vrAccess,
+	nodes.NodeKindVrN9KV:    vrAccess,
+	nodes.NodeKindVrFTOSV:   vrAccess,
+	nodes.NodeKindVrROS:     vrAccess,
+	nodes.NodeKindVrSROS:    {ssh: 22, gnmi: 57400, telnet: nodes.VrConsolePort},
+	nodes.NodeKindVrVEOS:    vrAccess,
+	nodes.NodeKindVrVMX:     vrAccess,
+	nodes.NodeKindVrVQFX:    vrAccess,
+	nodes.NodeKindVrXRV:     vrAccess,
+	nodes.NodeKindVrXRV9K:   {ssh: 22, gnmi: 57400, telnet: nodes.VrConsolePort},
+	nodes.NodeKindVrNXOS:    vrAccess,
+	nodes.NodeKindGenericVM: vrAccess,
+}
+
+// nodeAccess returns the management services of the node's kind
+// with the serial console port set for the generic_vm node
+func nodeAccess(cfg *types.NodeConfig) (*kindAccess, bool) {
+	ka, ok := kindsAccess[cfg.Kind]
+	if !ok {
+		return nil, false
+	}
+	if p := cfg.Extras.GetGenericVM(); p != nil && p.ConsolePort != 0 {
+		nka := *ka
+		nka.telnet = p.ConsolePort
+		return &nka, true
+	}
+	return ka, true
+}
+
+// NodeAccess holds the details a user needs to access a lab node
+type NodeAccess struct {
+	Name     string `json:"name"`
+	Kind     string `json:"kind"`
+	State    string `json:"state,omitempty"`
+	MgmtIPv4 string `json:"mgmt_ipv4,omitempty"`
+	MgmtIPv6 string `json:"mgmt_ipv6,omitempty"`
+	// ssh and gnmi are the commands/addresses to reach the services
+	SSH      string `json:"ssh,omitempty"`
+	GNMI     string `json:"gnmi,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Console  string `json:"console,omitempty"`
+}
+
+// AccessSummary returns the access details of the lab nodes sorted by node name.
+// The state of the nodes and the published ports are taken from the containers
+func (c *CLab) AccessSummary(containers []types.GenericContainer) []*NodeAccess {
+	ctrs := map[string]types.GenericContainer{}
+	for _, ctr := range containers {
+		ctrs[ctr.Labels[NodeNameLabel]] = ctr
+	}
+
+	res := make([]*NodeAccess, 0, len(c.Nodes))
+	for name, n := range c.Nodes {
+		cfg := n.Config()
+		ctr := ctrs[name]
+		na := &NodeAccess{
+			Name:     cfg.LongName,
+			Kind:     cfg.Kind,
+			State:    ctr.State,
+			MgmtIPv4: cfg.MgmtIPv4Address,
+			MgmtIPv6: cfg.MgmtIPv6Address,
+		}
+		if creds := nodes.SaveCredentials(cfg); creds.Username != "" {
+			na.Username, na.Password = creds.Username, creds.Password
+		}
+
+		if ka, ok := nodeAccess(cfg); ok {
+			if ka.ssh != 0 {
+				if host, port := nodeAddress(cfg, ctr, ka.ssh); host != "" {
+					na.SSH = sshCommand(na.Username, host, port)
+				}
+			}
+			if ka.gnmi != 0 {
+				if host, port := nodeAddress(cfg, ctr, ka.gnmi); host != "" {
+					na.GNMI = net.JoinHostPort(host, strconv.Itoa(port))
+				}
+			}
+			na.Console = consoleCommand(n.GetRuntime(), cfg.LongName, ka)
+		}
+		res = append(res, na)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
+// nodeAddress returns the address and port to reach a node's service listening on port.
+// Management address is preferred, a port published on the host is used otherwise
+func nodeAddress(cfg *types.NodeConfig, ctr types.GenericContainer, port int) (string, int) {
+	if cfg.MgmtIPv4Address != "" {
+		return cfg.MgmtIPv4Address, port
+	}
+	if cfg.MgmtIPv6Address != "" {
+		return cfg.MgmtIPv6Address, port
+	}
+	for _, p := range ctr.Ports {
+		if p.ContainerPort != port || p.Protocol != "tcp" {
+			continue
+		}
+		host := p.HostIP
+		if host == "" || host == "0.0.0.0" || host == "::" {
+			host = "localhost"
+		}
+		return host, p.HostPort
+	}
+	return "", 0
+}
+
+func sshCommand(user, host string, port int) string {
+	target := host
+	if user != "" {
+		target = user + "@" + host
+	}
+	if port != 22 {
+		return fmt.Sprintf("ssh -p %d %s", port, target)
+	}
+	return "ssh " + target
+}
+
+// consoleCommand returns a command to reach a node's CLI or serial console
+func consoleCommand(r runtime.ContainerRuntime, name string, ka *kindAccess) string {
+	cmd := ka.cli
+	if ka.telnet != 0 {
+		cmd = fmt.Sprintf("telnet 127.0.0.1 %d", ka.telnet)
+	}
+	if cmd == "" {
+		return ""
+	}
+	return interactiveExec(r, name, cmd)
+}
+
+// interactiveExec returns a command running cmd in the container with a terminal attached
+func interactiveExec(r runtime.ContainerRuntime, name, cmd string) string {
+	if r == nil {
+		return ""
+	}
+	switch r.GetName() {
+	case runtime.DockerRuntime:
+		return fmt.Sprintf("docker exec -it %s %s", name, cmd)
+	case runtime.PodmanRuntime:
+		return fmt.Sprintf("podman exec -it %s %s", name, cmd)
+	case runtime.ContainerdRuntime:
+		return fmt.Sprintf("ctr -n clab task exec -t --exec-id clab %s %s", name, cmd)
+	}
+	return ""
+}
+
+// InteractiveCommand returns a command opening an interactive session to the node running cmd.
+// With empty cmd the session is opened to the node's CLI or serial console, or to the shell for the kinds without them.
+// Empty string is returned for the nodes of runtimes not supporting the interactive sessions
+func (c *CLab) InteractiveCommand(name, cmd string) string {
+	n, ok := c.Nodes[name]
+	if !ok {
+		return ""
+	}
+	if cmd == "" {
+		if ka, ok := nodeAccess(n.Config()); ok {
+			if console := consoleCommand(n.GetRuntime(), n.Config().LongName, ka); console != "" {
+				return console
+			}
+		}
+		cmd = "sh"
+	}
+	return interactiveExec(n.GetRuntime(), n.Config().LongName, cmd)
+}
+
+const (
+	SummaryFormatJSON     = "json"
+	SummaryFormatMarkdown = "markdown"
+)
+
+// WriteAccessSummary writes the access summary to w in the json or markdown format
+func (c *CLab) WriteAccessSummary(w io.Writer, format string, s []*NodeAccess) error {
+	switch format {
+	case SummaryFormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Name  string        `json:"name"`
+			Nodes []*NodeAccess `json:"nodes"`
+		}{c.Config.Name, s})
+	case SummaryFormatMarkdown:
+		return c.writeAccessSummaryMarkdown(w, s)
+	}
+	return fmt.Errorf("unsupported summary format %q", format)
+}
+
+// writeAccessSummaryMarkdown writes the access summary as a markdown table,
+// commands and addresses are rendered as code spans
+func (c *CLab) writeAccessSummaryMarkdown(w io.Writer, s []*NodeAccess) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Lab %s\n\n", c.Config.Name)
+	fmt.Fprintln(&b, "| Node | Kind | IPv4 Address | IPv6 Address | SSH | gNMI | Username | Password | Console |")
+	fmt.Fprintln(&b, "|---|---|---|---|---|---|---|---|---|")
+	for _, n := range s {
+		fields := []string{n.Name, n.Kind, n.MgmtIPv4, n.MgmtIPv6, n.SSH, n.GNMI, n.Username, n.Password, n.Console}
+		for i, f := range fields {
+			if f != "" && i > 1 {
+				f = "`" + f + "`"
+			}
+			fields[i] = strings.ReplaceAll(f, "|", `\|`)
+		}
+		fmt.Fprintf(&b, "| %s |\n", strings.Join(fields, " | "))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
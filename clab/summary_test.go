@@ -0,0 +1,134 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestAccessSummary(t *testing.T) {
+	tests := map[string]struct {
+		got        string
+		containers []types.GenericContainer
+		want       []*NodeAccess
+	}{
+		"mgmt_addresses": {
+			got: "test_data/topo1.yml",
+			containers: []types.GenericContainer{
+				{Labels: map[string]string{NodeNameLabel: "node1"}, State: "running"},
+			},
+			want: []*NodeAccess{
+				{
+					Name:     "clab-topo1-node1",
+					Kind:     "srl",
+					State:    "running",
+					MgmtIPv4: "172.100.100.11",
+					SSH:      "ssh admin@172.100.100.11",
+					GNMI:     "172.100.100.11:57400",
+					Username: "admin",
+					Password: "admin",
+				},
+				{
+					Name:     "clab-topo1-node2",
+					Kind:     "srl",
+					MgmtIPv4: "172.100.100.12",
+					SSH:      "ssh admin@172.100.100.12",
+					GNMI:     "172.100.100.12:57400",
+					Username: "admin",
+					Password: "admin",
+				},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewContainerLab(WithTopoFile(tc.got))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := c.AccessSummary(tc.containers)
+			if !cmp.Equal(got, tc.want) {
+				t.Errorf("failed at '%s', diff (-want +got):\n%s", name, cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
+
+func TestNodeAddress(t *testing.T) {
+	tests := map[string]struct {
+		cfg      *types.NodeConfig
+		ctr      types.GenericContainer
+		wantHost string
+		wantPort int
+	}{
+		"mgmt_ipv4": {
+			cfg:      &types.NodeConfig{MgmtIPv4Address: "172.20.20.2", MgmtIPv6Address: "2001:db8::2"},
+			wantHost: "172.20.20.2",
+			wantPort: 22,
+		},
+		"mgmt_ipv6": {
+			cfg:      &types.NodeConfig{MgmtIPv6Address: "2001:db8::2"},
+			wantHost: "2001:db8::2",
+			wantPort: 22,
+		},
+		"published_port": {
+			cfg: &types.NodeConfig{},
+			ctr: types.GenericContainer{Ports: []*types.GenericPortBinding{
+				{HostIP: "0.0.0.0", HostPort: 8080, ContainerPort: 80, Protocol: "tcp"},
+				{HostIP: "0.0.0.0", HostPort: 2222, ContainerPort: 22, Protocol: "tcp"},
+			}},
+			wantHost: "localhost",
+			wantPort: 2222,
+		},
+		"no_address": {
+			cfg: &types.NodeConfig{},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			host, port := nodeAddress(tc.cfg, tc.ctr, 22)
+			if host != tc.wantHost || port != tc.wantPort {
+				t.Errorf("got %s:%d, want %s:%d", host, port, tc.wantHost, tc.wantPort)
+			}
+		})
+	}
+}
+
+func TestWriteAccessSummaryMarkdown(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo1.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var s strings.Builder
+	err = c.WriteAccessSummary(&s, SummaryFormatMarkdown, []*NodeAccess{
+		{
+			Name:     "clab-topo1-node1",
+			Kind:     "srl",
+			MgmtIPv4: "172.100.100.11",
+			SSH:      "ssh admin@172.100.100.11",
+			Username: "admin",
+			Password: "ad|min",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "# Lab topo1\n\n" +
+		"| Node | Kind | IPv4 Address | IPv6 Address | SSH | gNMI | Username | Password | Console |\n" +
+		"|---|---|---|---|---|---|---|---|---|\n" +
+		"| clab-topo1-node1 | srl | `172.100.100.11` |  | `ssh admin@172.100.100.11` |  | `admin` | `ad\\|min` |  |\n"
+	if !cmp.Equal(s.String(), want) {
+		t.Errorf("expected\n%v, got\n%v", want, s.String())
+	}
+}
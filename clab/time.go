@@ -0,0 +1,99 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+const (
+	// NTPServerName is the name of the lab NTP server node
+	NTPServerName      = "ntp"
+	defaultNTPImage    = "cturra/ntp:latest"
+	zoneInfoDir        = "/usr/share/zoneinfo"
+	localtimeContainer = "/etc/localtime"
+)
+
+// setTimezone sets the TZ env var of the node and mounts the zone file of the timezone
+// to /etc/localtime if it exists on the container host
+func setTimezone(cfg *types.NodeConfig) error {
+	if cfg.Timezone == "" {
+		return nil
+	}
+	if _, err := time.LoadLocation(cfg.Timezone); err != nil {
+		return fmt.Errorf("node %q: unknown timezone %q: %v", cfg.ShortName, cfg.Timezone, err)
+	}
+	if _, ok := noMgmtKinds[cfg.Kind]; ok {
+		return nil
+	}
+	if cfg.Env == nil {
+		cfg.Env = map[string]string{}
+	}
+	if _, ok := cfg.Env["TZ"]; !ok {
+		cfg.Env["TZ"] = cfg.Timezone
+	}
+	zoneFile := filepath.Join(zoneInfoDir, cfg.Timezone)
+	if utils.FileExists(zoneFile) {
+		cfg.Binds = append(cfg.Binds, zoneFile+":"+localtimeContainer+":ro")
+	}
+	return nil
+}
+
+// addNTPServer adds the NTP server node to the topology if the lab NTP server is enabled
+func (c *CLab) addNTPServer() error {
+	ntp := c.Config.NTP
+	if ntp == nil {
+		return nil
+	}
+	if _, ok := c.Config.Topology.Nodes[NTPServerName]; ok {
+		return fmt.Errorf("node name %q is reserved for the NTP server", NTPServerName)
+	}
+	if ntp.Image == "" {
+		ntp.Image = defaultNTPImage
+	}
+	if c.Config.Topology.Nodes == nil {
+		c.Config.Topology.Nodes = map[string]*types.NodeDefinition{}
+	}
+	ndef := &types.NodeDefinition{
+		Kind:  nodes.NodeKindLinux,
+		Image: ntp.Image,
+	}
+	if len(ntp.Servers) != 0 {
+		ndef.Env = map[string]string{"NTP_SERVERS": strings.Join(ntp.Servers, ",")}
+	}
+	c.Config.Topology.Nodes[NTPServerName] = ndef
+	return nil
+}
+
+// ConfigureNTP sets the management address of the lab NTP server as the NTP server of the nodes.
+// Must be called after the management addresses are allocated
+func (c *CLab) ConfigureNTP() {
+	if c.Config.NTP == nil {
+		return
+	}
+	srv := c.Nodes[NTPServerName].Config()
+	addr := srv.MgmtIPv4Address
+	if addr == "" {
+		addr = srv.MgmtIPv6Address
+	}
+	if addr == "" {
+		log.Warnf("NTP server has no static management address, nodes are not configured to use it")
+		return
+	}
+	for name, n := range c.Nodes {
+		if name == NTPServerName {
+			continue
+		}
+		n.Config().NTPServers = []string{addr}
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestTimezoneInit(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo13.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		timezone string
+		tzEnv    string
+	}{
+		"node1":      {timezone: "UTC", tzEnv: "UTC"},
+		"node2":      {timezone: "UTC", tzEnv: "Europe/Brussels"},
+		"ntp-client": {timezone: "Etc/GMT+1", tzEnv: "Etc/GMT+1"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := c.Nodes[name].Config()
+			if cfg.Timezone != tc.timezone {
+				t.Errorf("expected timezone %q, got %q", tc.timezone, cfg.Timezone)
+			}
+			if cfg.Env["TZ"] != tc.tzEnv {
+				t.Errorf("expected TZ env %q, got %q", tc.tzEnv, cfg.Env["TZ"])
+			}
+		})
+	}
+}
+
+func TestSetTimezoneUnknown(t *testing.T) {
+	cfg := &types.NodeConfig{ShortName: "node1", Kind: "linux", Timezone: "Mars/Olympus_Mons"}
+	if err := setTimezone(cfg); err == nil {
+		t.Errorf("expected an error for an unknown timezone")
+	}
+}
+
+func TestConfigureNTP(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo13.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ntp, ok := c.Nodes[NTPServerName]
+	if !ok {
+		t.Fatalf("NTP server node is not created")
+	}
+	if got := ntp.Config().Env["NTP_SERVERS"]; got != "time.example.com,192.0.2.1" {
+		t.Errorf("unexpected upstream NTP servers %q", got)
+	}
+
+	// no management address yet
+	c.ConfigureNTP()
+	if s := c.Nodes["node1"].Config().NTPServers; s != nil {
+		t.Errorf("expected no NTP servers, got %v", s)
+	}
+
+	ntp.Config().MgmtIPv4Address = "172.100.100.2"
+	c.ConfigureNTP()
+	for name, n := range c.Nodes {
+		var want []string
+		if name != NTPServerName {
+			want = []string{"172.100.100.2"}
+		}
+		if got := n.Config().NTPServers; !cmp.Equal(got, want) {
+			t.Errorf("node %s: expected NTP servers %v, got %v", name, want, got)
+		}
+	}
+}
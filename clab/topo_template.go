@@ -0,0 +1,120 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"text/template"
+
+	"github.com/srl-labs/containerlab/utils"
+	"gopkg.in/yaml.v2"
+)
+
+// topoTemplateFuncs are the functions available in the topology file templates
+// in addition to the text/template builtins
+var topoTemplateFuncs = template.FuncMap{
+	"seq":      seq,
+	"add":      func(a, b int) int { return a + b },
+	"sub":      func(a, b int) int { return a - b },
+	"mul":      func(a, b int) int { return a * b },
+	"div":      div,
+	"default":  defaultValue,
+	"env":      env,
+	"cidrHost": utils.CIDRHost,
+}
+
+// WithTopoVars sets the file with the variables of the topology file template,
+// the option is to be set before the topology file
+func WithTopoVars(file string) ClabOption {
+	return func(c *CLab) {
+		c.topoVarsFile = file
+	}
+}
+
+// readTopoVars reads the variables of the topology template from the YAML or JSON file
+func readTopoVars(file string) (map[string]interface{}, error) {
+	vars := map[string]interface{}{}
+	if file == "" {
+		return vars, nil
+	}
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read topology vars file: %v", err)
+	}
+	if err := yaml.Unmarshal(b, &vars); err != nil {
+		return nil, fmt.Errorf("failed to parse topology vars file %s: %v", file, err)
+	}
+	return vars, nil
+}
+
+// renderTopology executes the topology file as a Go template with the variables.
+// The missing variables are reported as errors, the optional ones are referenced with
+// the index function, e.g. {{ index . "version" | default "latest" }}
+func renderTopology(file string, data []byte, vars map[string]interface{}) ([]byte, error) {
+	t, err := template.New(filepath.Base(file)).
+		Option("missingkey=error").
+		Funcs(topoTemplateFuncs).
+		Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse topology template: %v", err)
+	}
+	buf := new(bytes.Buffer)
+	if err := t.Execute(buf, vars); err != nil {
+		return nil, fmt.Errorf("failed to render topology template: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// seq returns the sequence of integers from 1 to end, or from start to end with two arguments
+func seq(args ...int) ([]int, error) {
+	start, end := 1, 0
+	switch len(args) {
+	case 1:
+		end = args[0]
+	case 2:
+		start, end = args[0], args[1]
+	default:
+		return nil, fmt.Errorf("seq expects 1 or 2 arguments, got %d", len(args))
+	}
+	s := []int{}
+	for i := start; i <= end; i++ {
+		s = append(s, i)
+	}
+	return s, nil
+}
+
+func div(a, b int) (int, error) {
+	if b == 0 {
+		return 0, fmt.Errorf("division by zero")
+	}
+	return a / b, nil
+}
+
+// defaultValue returns the default value when the value is not set or is empty
+func defaultValue(def, v interface{}) interface{} {
+	if v == nil {
+		return def
+	}
+	if rv := reflect.ValueOf(v); rv.IsZero() {
+		return def
+	}
+	return v
+}
+
+// env returns the value of the env var, or the default value when the var is not set
+func env(name string, def ...string) string {
+	if v, ok := os.LookupEnv(name); ok {
+		return v
+	}
+	if len(def) > 0 {
+		return def[0]
+	}
+	return ""
+}
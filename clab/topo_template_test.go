@@ -0,0 +1,104 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRenderTopology(t *testing.T) {
+	os.Setenv("CLAB_TEST_IMAGE", "alpine")
+	defer os.Unsetenv("CLAB_TEST_IMAGE")
+
+	tests := map[string]struct {
+		tmpl    string
+		vars    map[string]interface{}
+		want    string
+		wantErr bool
+	}{
+		"no-template": {
+			tmpl: "name: lab1",
+			want: "name: lab1",
+		},
+		"var": {
+			tmpl: "image: srl:{{ .version }}",
+			vars: map[string]interface{}{"version": "21.6.4"},
+			want: "image: srl:21.6.4",
+		},
+		"missing-var": {
+			tmpl:    "image: srl:{{ .version }}",
+			wantErr: true,
+		},
+		"optional-var": {
+			tmpl: `image: srl:{{ index . "version" | default "latest" }}`,
+			want: "image: srl:latest",
+		},
+		"seq": {
+			tmpl: `{{ range seq 2 4 }}n{{ . }} {{ end }}`,
+			want: "n2 n3 n4 ",
+		},
+		"arithmetic": {
+			tmpl: `{{ add 2 3 }} {{ sub 5 3 }} {{ mul 2 3 }} {{ div 7 2 }}`,
+			want: "5 2 6 3",
+		},
+		"env": {
+			tmpl: `{{ env "CLAB_TEST_IMAGE" }} {{ env "CLAB_TEST_UNSET" "busybox" }}`,
+			want: "alpine busybox",
+		},
+		"cidr-host": {
+			tmpl: `{{ cidrHost "10.0.0.0/24" 5 }} {{ cidrHost "2001:db8::/64" 257 }}`,
+			want: "10.0.0.5 2001:db8::101",
+		},
+		"cidr-host-out-of-range": {
+			tmpl:    `{{ cidrHost "10.0.0.0/30" 4 }}`,
+			wantErr: true,
+		},
+		"bad-template": {
+			tmpl:    "name: {{ .name",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			vars := tc.vars
+			if vars == nil {
+				vars = map[string]interface{}{}
+			}
+			got, err := renderTopology("topo.yml", []byte(tc.tmpl), vars)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got error %v, wantErr %v", err, tc.wantErr)
+			}
+			if d := cmp.Diff(tc.want, string(got)); !tc.wantErr && d != "" {
+				t.Errorf("rendered topology mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestTopologyTemplateVars(t *testing.T) {
+	c, err := NewContainerLab(
+		WithTopoVars("test_data/topo27_vars.yml"),
+		WithTopoFile("test_data/topo27.yml"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Config.Name != "topo27" {
+		t.Errorf("got lab name %q, want topo27", c.Config.Name)
+	}
+	if len(c.Nodes) != 4 || len(c.Links) != 3 {
+		t.Errorf("got %d nodes and %d links, want 4 nodes and 3 links", len(c.Nodes), len(c.Links))
+	}
+	if ip := c.Nodes["leaf2"].Config().MgmtIPv4Address; ip != "172.100.100.12" {
+		t.Errorf("got leaf2 mgmt address %q, want 172.100.100.12", ip)
+	}
+	if img := c.Nodes["spine"].Config().Image; img != "ghcr.io/nokia/srlinux:21.6.4" {
+		t.Errorf("got spine image %q, want ghcr.io/nokia/srlinux:21.6.4", img)
+	}
+}
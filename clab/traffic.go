@@ -0,0 +1,194 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/vishvananda/netlink"
+)
+
+// clockTicks is the number of the kernel clock ticks per second the process start times are reported in
+const clockTicks = 100
+
+// LinkTraffic holds the traffic counters of a lab link
+type LinkTraffic struct {
+	// link endpoints in the format of <node>:<interface>
+	A string `json:"a"`
+	B string `json:"b"`
+	// bytes and packets sent from A to B and from B to A
+	BytesAB   uint64 `json:"bytes_a_b"`
+	BytesBA   uint64 `json:"bytes_b_a"`
+	PacketsAB uint64 `json:"packets_a_b"`
+	PacketsBA uint64 `json:"packets_b_a"`
+	// time the counters were accumulated over, since the link was created
+	Elapsed time.Duration `json:"elapsed_ns"`
+}
+
+// RateAB returns the average rate of the traffic sent from A to B in bits per second
+func (t *LinkTraffic) RateAB() float64 { return bitRate(t.BytesAB, t.Elapsed) }
+
+// RateBA returns the average rate of the traffic sent from B to A in bits per second
+func (t *LinkTraffic) RateBA() float64 { return bitRate(t.BytesBA, t.Elapsed) }
+
+func bitRate(bytes uint64, d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+	return float64(bytes) * 8 / d.Seconds()
+}
+
+// Sub returns the traffic between the prev sample of the link counters and t
+func (t *LinkTraffic) Sub(prev *LinkTraffic) *LinkTraffic {
+	return &LinkTraffic{
+		A:         t.A,
+		B:         t.B,
+		BytesAB:   t.BytesAB - prev.BytesAB,
+		BytesBA:   t.BytesBA - prev.BytesBA,
+		PacketsAB: t.PacketsAB - prev.PacketsAB,
+		PacketsBA: t.PacketsBA - prev.PacketsBA,
+		Elapsed:   t.Elapsed - prev.Elapsed,
+	}
+}
+
+// LinksTraffic samples the interface counters of the deployed lab links.
+// A link is sampled on its endpoint in a container, the counters are accumulated since the container started
+func (c *CLab) LinksTraffic(ctx context.Context) ([]*LinkTraffic, error) {
+	if r := c.GlobalRuntime(); r != nil && runtime.IsRemoteHost(r.Config().Host) {
+		return nil, fmt.Errorf("traffic of the labs running on a remote host can't be sampled")
+	}
+	keys := make([]int, 0, len(c.Links))
+	for k := range c.Links {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+
+	nsPaths := map[string]string{}
+	var res []*LinkTraffic
+	for _, k := range keys {
+		l := c.Links[k]
+		a, swapped := l.A, false
+		// the counters are read on the container side of the links to the host and the bridges
+		if _, ok := noMgmtKinds[a.Node.Kind]; ok {
+			a, swapped = l.B, true
+		}
+		if _, ok := noMgmtKinds[a.Node.Kind]; ok {
+			continue
+		}
+		nsPath, ok := nsPaths[a.Node.LongName]
+		if !ok {
+			var err error
+			if nsPath, err = c.GlobalRuntime().GetNSPath(ctx, a.Node.LongName); err != nil {
+				return nil, fmt.Errorf("node %s: %v", a.Node.ShortName, err)
+			}
+			nsPaths[a.Node.LongName] = nsPath
+		}
+		st, err := interfaceStats(nsPath, a.EndpointName)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%s: %v", a.Node.ShortName, a.EndpointName, err)
+		}
+		started, err := procStartTime(filepath.Dir(filepath.Dir(nsPath)))
+		if err != nil {
+			return nil, fmt.Errorf("node %s: %v", a.Node.ShortName, err)
+		}
+		t := &LinkTraffic{
+			A:         endpointName(l.A),
+			B:         endpointName(l.B),
+			BytesAB:   st.TxBytes,
+			BytesBA:   st.RxBytes,
+			PacketsAB: st.TxPackets,
+			PacketsBA: st.RxPackets,
+			Elapsed:   time.Since(started),
+		}
+		if swapped {
+			t.BytesAB, t.BytesBA = t.BytesBA, t.BytesAB
+			t.PacketsAB, t.PacketsBA = t.PacketsBA, t.PacketsAB
+		}
+		res = append(res, t)
+	}
+	return res, nil
+}
+
+func endpointName(e *types.Endpoint) string {
+	return e.Node.ShortName + ":" + e.EndpointName
+}
+
+// interfaceStats returns the counters of the interface in the network namespace of nsPath
+func interfaceStats(nsPath, iface string) (*netlink.LinkStatistics, error) {
+	netNS, err := ns.GetNS(nsPath)
+	if err != nil {
+		return nil, err
+	}
+	defer netNS.Close()
+	var st *netlink.LinkStatistics
+	err = netNS.Do(func(_ ns.NetNS) error {
+		l, err := netlink.LinkByName(iface)
+		if err != nil {
+			return err
+		}
+		if st = l.Attrs().Statistics; st == nil {
+			return fmt.Errorf("no interface statistics")
+		}
+		return nil
+	})
+	return st, err
+}
+
+// procStartTime returns the start time of the process of the /proc/<pid> dir
+func procStartTime(procDir string) (time.Time, error) {
+	b, err := ioutil.ReadFile(filepath.Join(procDir, "stat"))
+	if err != nil {
+		return time.Time{}, err
+	}
+	ticks, err := parseProcStartTicks(string(b))
+	if err != nil {
+		return time.Time{}, err
+	}
+	stat, err := ioutil.ReadFile("/proc/stat")
+	if err != nil {
+		return time.Time{}, err
+	}
+	btime, err := parseBootTime(string(stat))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(btime, 0).Add(time.Duration(ticks) * time.Second / clockTicks), nil
+}
+
+// parseProcStartTicks returns the start time of the process in clock ticks since boot
+// from the contents of its /proc/<pid>/stat file
+func parseProcStartTicks(stat string) (uint64, error) {
+	// the command name in parentheses may contain spaces, the fields are counted after it
+	i := strings.LastIndex(stat, ")")
+	if i < 0 {
+		return 0, fmt.Errorf("malformed process stat")
+	}
+	// starttime is the 22nd field, the 20th after the command name
+	fields := strings.Fields(stat[i+1:])
+	if len(fields) < 20 {
+		return 0, fmt.Errorf("malformed process stat")
+	}
+	return strconv.ParseUint(fields[19], 10, 64)
+}
+
+// parseBootTime returns the boot time in seconds since the epoch from the contents of /proc/stat
+func parseBootTime(stat string) (int64, error) {
+	for _, line := range strings.Split(stat, "\n") {
+		if strings.HasPrefix(line, "btime ") {
+			return strconv.ParseInt(strings.TrimSpace(strings.TrimPrefix(line, "btime ")), 10, 64)
+		}
+	}
+	return 0, fmt.Errorf("boot time not found")
+}
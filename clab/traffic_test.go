@@ -0,0 +1,81 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseProcStartTicks(t *testing.T) {
+	tests := map[string]struct {
+		stat    string
+		want    uint64
+		wantErr bool
+	}{
+		"plain": {
+			stat: "1234 (sleep) S 1 1234 1234 0 -1 4194560 99 0 0 0 0 0 0 0 20 0 1 0 56789 5586944 128 18446744073709551615",
+			want: 56789,
+		},
+		"command_with_spaces_and_parens": {
+			stat: "42 (my (odd) cmd) R 1 42 42 0 -1 4194560 99 0 0 0 0 0 0 0 20 0 1 0 1001 5586944 128",
+			want: 1001,
+		},
+		"truncated": {
+			stat:    "42 (sleep) S 1 42",
+			wantErr: true,
+		},
+		"no_command": {
+			stat:    "42 sleep S 1 42",
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseProcStartTicks(tc.stat)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got error %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("got %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseBootTime(t *testing.T) {
+	got, err := parseBootTime("cpu  2255 34 2290 22625563 6290 127 456\nintr 1462898\nbtime 1634281813\nprocesses 26442\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 1634281813 {
+		t.Errorf("got %d, want 1634281813", got)
+	}
+	if _, err := parseBootTime("cpu  2255 34\n"); err == nil {
+		t.Error("expected an error for the missing boot time")
+	}
+}
+
+func TestLinkTrafficSub(t *testing.T) {
+	prev := &LinkTraffic{A: "n1:e1-1", B: "n2:e1-1", BytesAB: 1000, BytesBA: 500, PacketsAB: 10, PacketsBA: 5, Elapsed: 10 * time.Second}
+	cur := &LinkTraffic{A: "n1:e1-1", B: "n2:e1-1", BytesAB: 126000, BytesBA: 500, PacketsAB: 110, PacketsBA: 5, Elapsed: 20 * time.Second}
+
+	got := cur.Sub(prev)
+	want := &LinkTraffic{A: "n1:e1-1", B: "n2:e1-1", BytesAB: 125000, PacketsAB: 100, Elapsed: 10 * time.Second}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Fatalf("diff (-want +got):\n%s", d)
+	}
+	if got.RateAB() != 100000 {
+		t.Errorf("got A->B rate %f, want 100000", got.RateAB())
+	}
+	if got.RateBA() != 0 {
+		t.Errorf("got B->A rate %f, want 0", got.RateBA())
+	}
+	if r := (&LinkTraffic{BytesAB: 100}).RateAB(); r != 0 {
+		t.Errorf("got rate %f over zero time, want 0", r)
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/srl-labs/containerlab/schemas"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// ValidationError is an issue of the topology file found by the ValidateTopology.
+// Line and Column locate the issue in the file and are 0 when the location is unknown
+type ValidationError struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Path    string `json:"path,omitempty"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func (e *ValidationError) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Message)
+}
+
+// yamlLineRe matches the line number reported by the YAML decoders errors
+var yamlLineRe = regexp.MustCompile(`line (\d+)`)
+
+// ValidateTopology checks the topology file before anything is deployed.
+// The file is checked against the topology schema for the syntax errors, unknown keys and wrong values,
+// a file conforming to the schema is then checked for the lint errors, such as unknown kinds and duplicate links.
+// An error is returned if the file can't be read.
+// The options set the lab settings the topology file is read with, e.g. the topology template vars
+func ValidateTopology(file string, opts ...ClabOption) ([]*ValidationError, error) {
+	c, err := NewContainerLab(opts...)
+	if err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	vars, err := readTopoVars(c.topoVarsFile)
+	if err != nil {
+		return nil, err
+	}
+	if b, err = renderTopology(file, b, vars); err != nil {
+		return []*ValidationError{fileError(file, "template", err)}, nil
+	}
+	if b, err = decodeTopology(file, b); err != nil {
+		return []*ValidationError{fileError(file, "syntax", err)}, nil
+	}
+	b = []byte(os.ExpandEnv(string(b)))
+
+	doc := new(yamlv3.Node)
+	if err := yamlv3.Unmarshal(b, doc); err != nil {
+		return []*ValidationError{fileError(file, "syntax", err)}, nil
+	}
+	violations, err := schemas.ValidateTopology(doc)
+	if err != nil {
+		return nil, err
+	}
+	if len(violations) != 0 {
+		res := make([]*ValidationError, 0, len(violations))
+		for _, v := range violations {
+			res = append(res, &ValidationError{File: file, Line: v.Line, Column: v.Column, Path: v.Path,
+				Rule: "schema", Message: v.Message})
+		}
+		return res, nil
+	}
+
+	findings, err := LintTopology(file, opts...)
+	if err != nil {
+		return []*ValidationError{fileError(file, "decode", err)}, nil
+	}
+	var res []*ValidationError
+	for _, f := range findings {
+		if f.Severity != LintError {
+			continue
+		}
+		e := &ValidationError{File: file, Rule: f.Rule, Message: f.Message}
+		if f.Node != "" {
+			e.Message = fmt.Sprintf("node %s: %s", f.Node, f.Message)
+		}
+		if n, path := locateFinding(doc, f); n != nil {
+			e.Line, e.Column, e.Path = n.Line, n.Column, path
+		}
+		res = append(res, e)
+	}
+	return res, nil
+}
+
+// fileError returns the validation error of the file, located by the line number of the decoder error if it has one
+func fileError(file, rule string, err error) *ValidationError {
+	e := &ValidationError{File: file, Rule: rule, Message: err.Error()}
+	if m := yamlLineRe.FindStringSubmatch(err.Error()); m != nil {
+		e.Line, _ = strconv.Atoi(m[1])
+	}
+	return e
+}
+
+// locateFinding returns the topology file node the lint finding refers to and its path.
+// The link findings are located by the link endpoints of the node, the rest by the node definition
+func locateFinding(doc *yamlv3.Node, f *LintFinding) (*yamlv3.Node, string) {
+	topo := yamlValue(doc, "topology")
+	if strings.HasPrefix(f.Rule, "link-") || strings.HasSuffix(f.Rule, "-endpoint") {
+		links := yamlValue(topo, "links")
+		var found *yamlv3.Node
+		var path string
+		for i, l := range yamlItems(links) {
+			for j, e := range yamlItems(yamlValue(l, "endpoints")) {
+				if f.Node == "" || !strings.HasPrefix(e.Value, f.Node+":") {
+					continue
+				}
+				found, path = e, fmt.Sprintf("topology.links.%d.endpoints.%d", i, j)
+				// the duplicate endpoint is reported at its last use
+				if f.Rule != "duplicate-endpoint" {
+					return found, path
+				}
+			}
+		}
+		if found != nil {
+			return found, path
+		}
+		if links != nil {
+			return links, "topology.links"
+		}
+	}
+	if f.Node == "" {
+		return topo, "topology"
+	}
+	node := yamlValue(yamlValue(topo, "nodes"), f.Node)
+	if kind := yamlValue(node, "kind"); kind != nil && f.Rule == "node-kind" {
+		return kind, fmt.Sprintf("topology.nodes.%s.kind", f.Node)
+	}
+	if key, _ := yamlPair(yamlValue(topo, "nodes"), f.Node); key != nil {
+		return key, "topology.nodes." + f.Node
+	}
+	return topo, "topology"
+}
+
+// yamlPair returns the key and the value nodes of the mapping key, nils if n is not a mapping or has no such key
+func yamlPair(n *yamlv3.Node, key string) (*yamlv3.Node, *yamlv3.Node) {
+	if n != nil && n.Kind == yamlv3.DocumentNode && len(n.Content) != 0 {
+		n = n.Content[0]
+	}
+	if n == nil || n.Kind != yamlv3.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i], n.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// yamlValue returns the value node of the mapping key, nil if n is not a mapping or has no such key
+func yamlValue(n *yamlv3.Node, key string) *yamlv3.Node {
+	_, v := yamlPair(n, key)
+	return v
+}
+
+// yamlItems returns the items of the sequence node, nil if n is not a sequence
+func yamlItems(n *yamlv3.Node) []*yamlv3.Node {
+	if n == nil || n.Kind != yamlv3.SequenceNode {
+		return nil
+	}
+	return n.Content
+}
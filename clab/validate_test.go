@@ -0,0 +1,41 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValidateTopology(t *testing.T) {
+	tests := map[string][]*ValidationError{
+		"test_data/topo31.yml": {
+			{File: "test_data/topo31.yml", Line: 5, Column: 7, Path: "topology.nodes.srl1", Rule: "schema",
+				Message: "additionalProperties 'imagee' not allowed"},
+			{File: "test_data/topo31.yml", Line: 10, Column: 18, Path: "topology.nodes.linux1.mgmt_ipv4", Rule: "schema",
+				Message: `does not match pattern '^(([0-9]|[1-9][0-9]|1[0-9][0-9]|2[0-4][0-9]|25[0-5])\\.){3}([0-9]|[1-9][0-9]|1[0-9][0-9]|2[0-4][0-9]|25[0-5])(%[\\p{N}\\p{L}]+)?$'`},
+		},
+		"test_data/topo32.json": {
+			{File: "test_data/topo32.json", Line: 17, Column: 19, Path: "topology.links.1.endpoints.0", Rule: "duplicate-endpoint",
+				Message: `node linux1: endpoint "linux1:eth1" is used by more than one link`},
+			{File: "test_data/topo32.json", Line: 17, Column: 34, Path: "topology.links.1.endpoints.1", Rule: "link-endpoints",
+				Message: `node ghost: endpoint "ghost:eth1" references a node not defined in the nodes section`},
+			{File: "test_data/topo32.json", Line: 11, Column: 13, Path: "topology.nodes.junk.kind", Rule: "node-kind",
+				Message: `node junk: kind "junos" is not supported`},
+		},
+	}
+	for file, want := range tests {
+		t.Run(file, func(t *testing.T) {
+			got, err := ValidateTopology(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(got, want) {
+				t.Errorf("diff (-want +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
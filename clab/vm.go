@@ -0,0 +1,120 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/docker/go-units"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// verifyVMs checks the vm settings of the nodes and that the container host has enough free hugepages
+// for the VMs backed by the hugepages
+func (c *CLab) verifyVMs() error {
+	var hugepages int64
+	for _, name := range c.nodeNames() {
+		cfg := c.Nodes[name].Config()
+		if cfg.VM == nil {
+			continue
+		}
+		size, err := verifyVM(cfg)
+		if err != nil {
+			return fmt.Errorf("node %q: vm: %v", name, err)
+		}
+		hugepages += size
+	}
+	if hugepages == 0 {
+		return nil
+	}
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	free, err := freeHugepages(f)
+	if err != nil {
+		return err
+	}
+	if hugepages > free {
+		return fmt.Errorf("the VMs need %s of hugepages, but the container host has %s of free hugepages, reserve the hugepages with the vm.nr_hugepages sysctl",
+			units.BytesSize(float64(hugepages)), units.BytesSize(float64(free)))
+	}
+	return nil
+}
+
+// verifyVM checks the vm settings of the node and returns the size of its hugepages backed memory in bytes
+func verifyVM(cfg *types.NodeConfig) (int64, error) {
+	if !nodes.IsVrKind(cfg.Kind) {
+		return 0, fmt.Errorf("vm settings are supported by the vrnetlab based kinds only, not by %s", cfg.Kind)
+	}
+	vm := cfg.VM
+	if vm.CPUPinning != "" {
+		pinned, err := types.ParseCPUSet(vm.CPUPinning)
+		if err != nil {
+			return 0, fmt.Errorf("cpu-pinning: %v", err)
+		}
+		// the vCPUs can't be pinned to the CPUs outside of the cgroup cpuset of the container
+		if cfg.CPUSet != "" && cfg.CPUSet != vm.CPUPinning {
+			allowed, err := types.ParseCPUSet(cfg.CPUSet)
+			if err != nil {
+				return 0, fmt.Errorf("cpu-set: %v", err)
+			}
+			set := map[int]struct{}{}
+			for _, cpu := range allowed {
+				set[cpu] = struct{}{}
+			}
+			for _, cpu := range pinned {
+				if _, ok := set[cpu]; !ok {
+					return 0, fmt.Errorf("cpu-pinning CPU %d is not in the cpu-set %s of the node", cpu, cfg.CPUSet)
+				}
+			}
+		}
+	}
+	if strings.ContainsAny(vm.CPUModel, " \t") {
+		return 0, fmt.Errorf("invalid cpu-model %q", vm.CPUModel)
+	}
+	if vm.Hugepages == "" {
+		return 0, nil
+	}
+	size, err := units.RAMInBytes(vm.Hugepages)
+	if err != nil || size <= 0 {
+		return 0, fmt.Errorf("invalid hugepages value %q", vm.Hugepages)
+	}
+	return size, nil
+}
+
+// freeHugepages returns the size in bytes of the free hugepages of the default size reported by /proc/meminfo
+func freeHugepages(r io.Reader) (int64, error) {
+	var pages, pageSize int64
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		v, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "HugePages_Free:":
+			pages = v
+		case "Hugepagesize:":
+			// the page size is reported in kB
+			pageSize = v * 1024
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return pages * pageSize, nil
+}
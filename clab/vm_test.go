@@ -0,0 +1,73 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestVerifyVM(t *testing.T) {
+	tests := map[string]struct {
+		cfg      *types.NodeConfig
+		wantSize int64
+		wantErr  bool
+	}{
+		"pinning-model": {
+			cfg: &types.NodeConfig{Kind: "vr-sros", VM: &types.VMConfig{CPUPinning: "4-7", CPUModel: "host"}},
+		},
+		"pinning-in-cpuset": {
+			cfg: &types.NodeConfig{Kind: "vr-sros", CPUSet: "2-9", VM: &types.VMConfig{CPUPinning: "4-7"}},
+		},
+		"pinning-outside-cpuset": {
+			cfg:     &types.NodeConfig{Kind: "vr-sros", CPUSet: "0-3", VM: &types.VMConfig{CPUPinning: "4-7"}},
+			wantErr: true,
+		},
+		"invalid-pinning": {
+			cfg:     &types.NodeConfig{Kind: "vr-sros", VM: &types.VMConfig{CPUPinning: "7-4"}},
+			wantErr: true,
+		},
+		"hugepages": {
+			cfg:      &types.NodeConfig{Kind: "generic_vm", VM: &types.VMConfig{Hugepages: "4GB"}},
+			wantSize: 4 << 30,
+		},
+		"invalid-hugepages": {
+			cfg:     &types.NodeConfig{Kind: "vr-sros", VM: &types.VMConfig{Hugepages: "lots"}},
+			wantErr: true,
+		},
+		"not-vr-kind": {
+			cfg:     &types.NodeConfig{Kind: "linux", VM: &types.VMConfig{CPUModel: "host"}},
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			size, err := verifyVM(tc.cfg)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("verifyVM() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if size != tc.wantSize {
+				t.Errorf("verifyVM() = %d, want %d", size, tc.wantSize)
+			}
+		})
+	}
+}
+
+func TestFreeHugepages(t *testing.T) {
+	meminfo := `MemTotal:       65755284 kB
+HugePages_Total:    4096
+HugePages_Free:     3072
+Hugepagesize:       2048 kB
+`
+	got, err := freeHugepages(strings.NewReader(meminfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := int64(3072 * 2048 * 1024); got != want {
+		t.Errorf("freeHugepages() = %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/vishvananda/netlink"
+)
+
+// vrIfRe matches the data interface names of the vrnetlab nodes,
+// the eth<N> interface is connected to the N-th data NIC of the VM
+var vrIfRe = regexp.MustCompile(`^eth([1-9]\d*)$`)
+
+// vrIfIndex returns the index of the VM data NIC the interface of a vrnetlab node is connected to
+func vrIfIndex(name string) (int, bool) {
+	m := vrIfRe.FindStringSubmatch(name)
+	if m == nil {
+		return 0, false
+	}
+	i, err := strconv.Atoi(m[1])
+	return i, err == nil
+}
+
+// verifyVrEndpoints verifies that the link interfaces of the vrnetlab nodes are named eth<N>, N starting from 1
+func (c *CLab) verifyVrEndpoints() error {
+	for i := 0; i < len(c.Links); i++ {
+		l, ok := c.Links[i]
+		if !ok {
+			continue
+		}
+		for _, ep := range []*types.Endpoint{l.A, l.B} {
+			if !nodes.IsVrKind(ep.Node.Kind) {
+				continue
+			}
+			if _, ok := vrIfIndex(ep.EndpointName); !ok {
+				return fmt.Errorf("interface %q of %s node %q must be named eth<N> with N starting from 1, eth0 is the management interface",
+					ep.EndpointName, ep.Node.Kind, ep.Node.ShortName)
+			}
+		}
+	}
+	return nil
+}
+
+// vrLinkPredecessors returns the links to be wired before a link of a vrnetlab node.
+// The links of a vrnetlab node are wired one by one in the order of its interface indexes,
+// so that the interfaces are attached to the container, and the NICs to the VM, in the topology order
+// regardless of the number of the link workers
+func (c *CLab) vrLinkPredecessors() map[*types.Link][]*types.Link {
+	byNode := map[string][]*types.Link{}
+	for _, l := range c.Links {
+		for _, ep := range []*types.Endpoint{l.A, l.B} {
+			if nodes.IsVrKind(ep.Node.Kind) {
+				byNode[ep.Node.ShortName] = append(byNode[ep.Node.ShortName], l)
+			}
+		}
+	}
+	preds := map[*types.Link][]*types.Link{}
+	for name, links := range byNode {
+		idx := func(l *types.Link) int {
+			ep := l.A
+			if ep.Node.ShortName != name {
+				ep = l.B
+			}
+			i, _ := vrIfIndex(ep.EndpointName)
+			return i
+		}
+		sort.Slice(links, func(i, j int) bool { return idx(links[i]) < idx(links[j]) })
+		for i := 1; i < len(links); i++ {
+			preds[links[i]] = append(preds[links[i]], links[i-1])
+		}
+	}
+	return preds
+}
+
+// VerifyVrInterfaces verifies that the data interfaces of the vrnetlab node are attached to its container
+// in the order of their indexes, as the VM NICs are expected to be in the topology order
+func (c *CLab) VerifyVrInterfaces(cfg *types.NodeConfig) error {
+	if !nodes.IsVrKind(cfg.Kind) || c.remoteWiring() || cfg.NSPath == "" {
+		return nil
+	}
+	nodeNS, err := ns.GetNS(cfg.NSPath)
+	if err != nil {
+		return err
+	}
+	defer nodeNS.Close()
+	var links []netlink.Link
+	err = nodeNS.Do(func(_ ns.NetNS) error {
+		links, err = netlink.LinkList()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	sort.Slice(links, func(i, j int) bool { return links[i].Attrs().Index < links[j].Attrs().Index })
+	attached := make([]string, 0, len(links))
+	for _, l := range links {
+		attached = append(attached, l.Attrs().Name)
+	}
+	want := make([]string, 0, len(cfg.Endpoints))
+	for _, ep := range cfg.Endpoints {
+		want = append(want, ep.EndpointName)
+	}
+	if err := vrInterfaceOrderErr(attached, want); err != nil {
+		return fmt.Errorf("node %s: %v", cfg.ShortName, err)
+	}
+	return nil
+}
+
+// vrInterfaceOrderErr returns an error when the data interfaces of a vrnetlab node in the order of their attachment
+// are not ordered by their indexes or the interfaces of the topology endpoints want are missing
+func vrInterfaceOrderErr(attached, want []string) error {
+	present := map[string]bool{}
+	var ordered []string
+	last := 0
+	for _, name := range attached {
+		i, ok := vrIfIndex(name)
+		if !ok {
+			continue
+		}
+		present[name] = true
+		ordered = append(ordered, name)
+		if i < last {
+			return fmt.Errorf("data interfaces are attached out of order: %s", strings.Join(ordered, ", "))
+		}
+		last = i
+	}
+	var missing []string
+	for _, name := range want {
+		if !present[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("data interfaces %s are not attached", strings.Join(missing, ", "))
+	}
+	return nil
+}
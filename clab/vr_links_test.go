@@ -0,0 +1,93 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestVrLinkPredecessors(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo22.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.verifyVrEndpoints(); err != nil {
+		t.Fatal(err)
+	}
+	name := func(l *types.Link) string {
+		return endpointName(l.A) + "<->" + endpointName(l.B)
+	}
+	got := map[string][]string{}
+	for l, preds := range c.vrLinkPredecessors() {
+		for _, p := range preds {
+			got[name(l)] = append(got[name(l)], name(p))
+		}
+	}
+	want := map[string][]string{
+		// sr1 links are wired in the eth1, eth2, eth3 order
+		"sr1:eth2<->srl1:e1-2": {"sr1:eth1<->sr2:eth2"},
+		"sr1:eth3<->srl1:e1-3": {"sr1:eth2<->srl1:e1-2"},
+		// sr2 links are wired in the eth1, eth2 order
+		"sr1:eth1<->sr2:eth2": {"sr2:eth1<->srl1:e1-1"},
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("diff (-want +got):\n%s", d)
+	}
+
+	var upgradeOrder []string
+	for _, l := range c.nodeLinks("sr1") {
+		upgradeOrder = append(upgradeOrder, name(l))
+	}
+	wantOrder := []string{"sr1:eth1<->sr2:eth2", "sr1:eth2<->srl1:e1-2", "sr1:eth3<->srl1:e1-3"}
+	if d := cmp.Diff(wantOrder, upgradeOrder); d != "" {
+		t.Errorf("node links order diff (-want +got):\n%s", d)
+	}
+}
+
+func TestVerifyVrEndpoints(t *testing.T) {
+	c, err := NewContainerLab(WithTopoFile("test_data/topo22.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"eth0", "e1-1", "eth01"} {
+		c.Links[0].A.EndpointName = name
+		if err := c.verifyVrEndpoints(); err == nil {
+			t.Errorf("expected an error for the %s interface of a vrnetlab node", name)
+		}
+	}
+}
+
+func TestVrInterfaceOrderErr(t *testing.T) {
+	tests := map[string]struct {
+		attached []string
+		want     []string
+		wantErr  bool
+	}{
+		"ordered": {
+			attached: []string{"lo", "eth0", "eth1", "eth2", "eth10"},
+			want:     []string{"eth10", "eth1", "eth2"},
+		},
+		"out_of_order": {
+			attached: []string{"lo", "eth0", "eth2", "eth1"},
+			want:     []string{"eth1", "eth2"},
+			wantErr:  true,
+		},
+		"missing": {
+			attached: []string{"lo", "eth0", "eth1"},
+			want:     []string{"eth1", "eth2"},
+			wantErr:  true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := vrInterfaceOrderErr(tc.attached, tc.want); (err != nil) != tc.wantErr {
+				t.Fatalf("got error %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net"
 
+	"github.com/jsimonetti/rtnetlink/rtnl"
 	log "github.com/sirupsen/logrus"
 	"github.com/vishvananda/netlink"
 )
@@ -76,3 +77,18 @@ func AddVxLanInterface(vxlan VxLAN) (err error) {
 	}
 	return nil
 }
+
+// VxlanParentIf returns the name of the interface the remote VTEP address is reachable via
+// as seen by the kernel routing table
+func VxlanParentIf(remote net.IP) (string, error) {
+	conn, err := rtnl.Dial(nil)
+	if err != nil {
+		return "", fmt.Errorf("can't establish netlink connection: %s", err)
+	}
+	defer conn.Close()
+	r, err := conn.RouteGet(remote)
+	if err != nil {
+		return "", fmt.Errorf("failed to find a route to VxLAN remote address %s", remote)
+	}
+	return r.Interface.Name, nil
+}
@@ -0,0 +1,189 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// hookTimeout bounds the run time of the hooks of the nodes without a timeout set
+const hookTimeout = 5 * time.Minute
+
+// NodeEvent is a state change of a lab node reported by the watch of the lab
+type NodeEvent struct {
+	Time      time.Time `json:"time"`
+	Lab       string    `json:"lab"`
+	Node      string    `json:"node"`
+	Kind      string    `json:"kind,omitempty"`
+	Container string    `json:"container"`
+	State     string    `json:"state"`
+	// exit code of the exited containers
+	ExitCode *int `json:"exit_code,omitempty"`
+}
+
+// nodeWatcher tracks the readiness probes of the running nodes and runs the hooks of the nodes
+type nodeWatcher struct {
+	c       *CLab
+	lab     string
+	handler func(*NodeEvent)
+	// mu serializes the handler calls of the container events and the readiness probes
+	mu sync.Mutex
+	// probes are the cancel funcs of the readiness probes of the running nodes
+	probes map[string]context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// WatchNodes reports the state changes of the containers of the lab to the handler until ctx is canceled.
+// The running nodes of the topology are probed in the background and reported ready once their readiness probe
+// and wait-for checks succeed, e.g. when the VM of a vrnetlab node has booted.
+// The on-ready and on-exit hooks of the nodes are run on the container host
+func (c *CLab) WatchNodes(ctx context.Context, lab string, handler func(*NodeEvent)) error {
+	labels := []*types.GenericFilter{{FilterType: "label", Match: lab, Field: ContainerlabLabel, Operator: "="}}
+	// the management addresses of the running containers are used by the readiness probes and the hooks
+	containers, err := c.ListContainers(ctx, labels)
+	if err != nil {
+		return err
+	}
+	for i := range containers {
+		if n, ok := c.Nodes[containers[i].Labels[NodeNameLabel]]; ok {
+			keepMgmtAddresses(n.Config(), &containers[i])
+		}
+	}
+
+	w := &nodeWatcher{c: c, lab: lab, handler: handler, probes: map[string]context.CancelFunc{}}
+	events, errs := c.GlobalRuntime().Events(ctx, labels)
+	for ev := range events {
+		w.handle(ctx, ev)
+	}
+	w.wg.Wait()
+	if err := <-errs; err != nil && ctx.Err() == nil {
+		return err
+	}
+	return nil
+}
+
+// handle reports the container event and starts the readiness probe of the running node
+// or runs the on-exit hook of the exited node
+func (w *nodeWatcher) handle(ctx context.Context, ev types.ContainerEvent) {
+	name := ev.Labels[NodeNameLabel]
+	ne := &NodeEvent{
+		Time:      ev.Time,
+		Lab:       w.lab,
+		Node:      name,
+		Kind:      ev.Labels[NodeKindLabel],
+		Container: ev.ContainerName,
+		State:     ev.State,
+	}
+	if ev.State == types.EventExited {
+		code := ev.ExitCode
+		ne.ExitCode = &code
+	}
+	w.report(ne)
+
+	w.mu.Lock()
+	if cancel, ok := w.probes[name]; ok {
+		cancel()
+		delete(w.probes, name)
+	}
+	n, ok := w.c.Nodes[name]
+	if !ok {
+		w.mu.Unlock()
+		return
+	}
+	switch ev.State {
+	case types.EventRunning:
+		pctx, cancel := context.WithCancel(ctx)
+		w.probes[name] = cancel
+		w.wg.Add(1)
+		go func() {
+			defer w.wg.Done()
+			w.probe(pctx, n, ne)
+		}()
+	case types.EventExited:
+		w.runHook(ctx, n.Config(), ne, n.Config().OnExit)
+	}
+	w.mu.Unlock()
+}
+
+// probe waits for the running node to become ready, reports it ready and runs its on-ready hook,
+// the probe is canceled when the node container changes its state
+func (w *nodeWatcher) probe(ctx context.Context, n nodes.Node, running *NodeEvent) {
+	if err := WaitForNode(ctx, n, true); err != nil {
+		if ctx.Err() == nil {
+			log.Warnf("%v", err)
+		}
+		return
+	}
+	ready := *running
+	ready.Time, ready.State = time.Now(), types.EventReady
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if ctx.Err() != nil {
+		return
+	}
+	w.report(&ready)
+	w.runHook(ctx, n.Config(), &ready, n.Config().OnReady)
+}
+
+func (w *nodeWatcher) report(ev *NodeEvent) {
+	if w.handler != nil {
+		w.handler(ev)
+	}
+}
+
+// runHook runs the hook command with sh in the directory of the topology file in the background,
+// the node and the event are passed to the command with the CLAB_* env vars
+func (w *nodeWatcher) runHook(ctx context.Context, cfg *types.NodeConfig, ev *NodeEvent, hook string) {
+	if hook == "" {
+		return
+	}
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		ctx, cancel := context.WithTimeout(ctx, hookTimeout)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, "sh", "-c", hook)
+		if w.c.TopoFile != nil && w.c.TopoFile.path != "" {
+			cmd.Dir = filepath.Dir(w.c.TopoFile.path)
+		}
+		cmd.Env = append(os.Environ(), hookEnv(cfg, ev)...)
+		log.Infof("Running %s hook of node %s", ev.State, ev.Node)
+		out, err := cmd.CombinedOutput()
+		if len(out) > 0 {
+			log.Infof("%s hook of node %s output:\n%s", ev.State, ev.Node, strings.TrimRight(string(out), "\n"))
+		}
+		if err != nil {
+			log.Errorf("%s hook of node %s failed: %v", ev.State, ev.Node, err)
+		}
+	}()
+}
+
+// hookEnv returns the env vars describing the node and the event to the hooks
+func hookEnv(cfg *types.NodeConfig, ev *NodeEvent) []string {
+	env := []string{
+		"CLAB_LAB=" + ev.Lab,
+		"CLAB_NODE=" + ev.Node,
+		"CLAB_KIND=" + cfg.Kind,
+		"CLAB_CONTAINER=" + ev.Container,
+		"CLAB_STATE=" + ev.State,
+		"CLAB_MGMT_IPV4=" + cfg.MgmtIPv4Address,
+		"CLAB_MGMT_IPV6=" + cfg.MgmtIPv6Address,
+	}
+	if ev.ExitCode != nil {
+		env = append(env, "CLAB_EXIT_CODE="+strconv.Itoa(*ev.ExitCode))
+	}
+	return env
+}
@@ -0,0 +1,43 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package clab
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestHookEnv(t *testing.T) {
+	cfg := &types.NodeConfig{Kind: "vr-sros", MgmtIPv4Address: "172.20.20.2", MgmtIPv6Address: "2001:172:20:20::2"}
+	code := 137
+	tests := map[string]struct {
+		ev   *NodeEvent
+		want []string
+	}{
+		"ready": {
+			ev: &NodeEvent{Lab: "lab1", Node: "r1", Container: "clab-lab1-r1", State: types.EventReady},
+			want: []string{
+				"CLAB_LAB=lab1", "CLAB_NODE=r1", "CLAB_KIND=vr-sros", "CLAB_CONTAINER=clab-lab1-r1", "CLAB_STATE=ready",
+				"CLAB_MGMT_IPV4=172.20.20.2", "CLAB_MGMT_IPV6=2001:172:20:20::2",
+			},
+		},
+		"exited": {
+			ev: &NodeEvent{Lab: "lab1", Node: "r1", Container: "clab-lab1-r1", State: types.EventExited, ExitCode: &code},
+			want: []string{
+				"CLAB_LAB=lab1", "CLAB_NODE=r1", "CLAB_KIND=vr-sros", "CLAB_CONTAINER=clab-lab1-r1", "CLAB_STATE=exited",
+				"CLAB_MGMT_IPV4=172.20.20.2", "CLAB_MGMT_IPV6=2001:172:20:20::2", "CLAB_EXIT_CODE=137",
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if d := cmp.Diff(tc.want, hookEnv(cfg, tc.ev)); d != "" {
+				t.Errorf("hookEnv() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/agent"
+	"github.com/srl-labs/containerlab/runner"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// agentTokenEnv is the env var holding the bearer token of the agent
+const agentTokenEnv = "CLAB_AGENT_TOKEN"
+
+var (
+	agentListen  string
+	agentWorkDir string
+	agentTLSCert string
+	agentTLSKey  string
+)
+
+// agentCmd represents the agent command
+var agentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: "serve the labs of this host to the remote containerlab clients",
+	Long: `agent serves the gRPC API running the containerlab commands sent by the remote clients with the 'containerlab remote' command
+and proxying their SSH and console sessions to the management addresses of the lab nodes
+reference: https://containerlab.srlinux.dev/cmd/agent/`,
+	PreRunE: sudoCheck,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token := os.Getenv(agentTokenEnv)
+		if token == "" {
+			return errors.New("the agent token is required to be set with " + agentTokenEnv + " env var")
+		}
+		srv := &agent.Server{
+			Token:   token,
+			WorkDir: agentWorkDir,
+			Runner:  new(runner.Runner),
+		}
+		var opts []grpc.ServerOption
+		if agentTLSCert == "" {
+			log.Warn("Agent TLS certificate is not set, the agent API and the proxied sessions are not encrypted")
+		} else {
+			creds, err := credentials.NewServerTLSFromFile(agentTLSCert, agentTLSKey)
+			if err != nil {
+				return fmt.Errorf("failed to load agent TLS certificate: %v", err)
+			}
+			opts = append(opts, grpc.Creds(creds))
+		}
+		l, err := net.Listen("tcp", agentListen)
+		if err != nil {
+			return err
+		}
+		log.Infof("Containerlab agent listening on %s", agentListen)
+		return srv.GRPCServer(opts...).Serve(l)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(agentCmd)
+	agentCmd.Flags().StringVarP(&agentListen, "listen", "", ":"+strconv.Itoa(agent.DefaultPort), "address the agent API listens on")
+	agentCmd.Flags().StringVarP(&agentWorkDir, "work-dir", "", "/var/lib/containerlab/agent", "directory the topology files of the clients and their lab directories are stored in")
+	agentCmd.Flags().StringVarP(&agentTLSCert, "tls-cert", "", "", "path to the TLS certificate of the agent API")
+	agentCmd.Flags().StringVarP(&agentTLSKey, "tls-key", "", "", "path to the TLS key of the agent API")
+}
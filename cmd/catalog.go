@@ -0,0 +1,188 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/catalog"
+	terminal "golang.org/x/term"
+)
+
+var (
+	catalogIndex  string
+	catalogParams []string
+	catalogDir    string
+)
+
+// defaultCatalogIndex returns the catalog index used when neither the --index flag nor the CLAB_CATALOG env var is set
+func defaultCatalogIndex() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".clab", "catalog")
+}
+
+func init() {
+	rootCmd.AddCommand(catalogCmd)
+	catalogCmd.AddCommand(catalogListCmd)
+	catalogCmd.AddCommand(catalogDeployCmd)
+	catalogCmd.PersistentFlags().StringVarP(&catalogIndex, "index", "", "", "catalog index, a directory path, git repository URL or oci:// artifact reference, CLAB_CATALOG env var or ~/.clab/catalog by default")
+	catalogDeployCmd.Flags().StringSliceVarP(&catalogParams, "param", "", []string{}, "template parameter in the format of <name>=<value>, the parameters not set are prompted for")
+	catalogDeployCmd.Flags().StringVarP(&catalogDir, "dir", "", "", "directory the lab files are rendered to, ./<template-name> by default")
+	catalogDeployCmd.Flags().UintVarP(&maxWorkers, "max-workers", "", 0, "limit the maximum number of workers creating nodes and virtual wires")
+}
+
+var catalogCmd = &cobra.Command{
+	Use:   "catalog",
+	Short: "reusable lab templates",
+	Long:  "list and deploy the lab templates of a catalog shared across a team\nreference: https://containerlab.srlinux.dev/cmd/catalog/",
+}
+
+var catalogListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list the lab templates of the catalog",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		idx, err := openCatalog()
+		if err != nil {
+			return err
+		}
+		tmpls, err := idx.List()
+		if err != nil {
+			return err
+		}
+		if len(tmpls) == 0 {
+			log.Infof("no lab templates found in %s", idx.Dir)
+			return nil
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Name", "Description", "Parameters"})
+		table.SetAutoFormatHeaders(false)
+		table.SetAutoWrapText(false)
+		for _, t := range tmpls {
+			params := make([]string, 0, len(t.Params))
+			for _, p := range t.Params {
+				params = append(params, paramUsage(p))
+			}
+			table.Append([]string{t.Name, t.Description, strings.Join(params, "\n")})
+		}
+		table.Render()
+		return nil
+	},
+}
+
+var catalogDeployCmd = &cobra.Command{
+	Use:     "deploy <template-name>",
+	Short:   "render a lab template into a topology and deploy it",
+	Args:    cobra.ExactArgs(1),
+	PreRunE: sudoCheck,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		idx, err := openCatalog()
+		if err != nil {
+			return err
+		}
+		t, err := idx.Get(args[0])
+		if err != nil {
+			return err
+		}
+		set, err := parseCatalogParams(catalogParams)
+		if err != nil {
+			return err
+		}
+		if terminal.IsTerminal(int(os.Stdin.Fd())) {
+			if err := promptParams(t, set, os.Stdin, os.Stdout); err != nil {
+				return err
+			}
+		}
+		vals, err := t.Values(set)
+		if err != nil {
+			return err
+		}
+		dir := catalogDir
+		if dir == "" {
+			dir = t.Name
+		}
+		topoFile, err := t.Render(vals, dir)
+		if err != nil {
+			return err
+		}
+		log.Infof("Rendered template %s to %s", t.Name, topoFile)
+		topo = topoFile
+		return deployCmd.RunE(deployCmd, nil)
+	},
+}
+
+// openCatalog opens the catalog index set with the flag, the env var or the default one
+func openCatalog() (*catalog.Index, error) {
+	src := catalogIndex
+	if src == "" {
+		src = os.Getenv("CLAB_CATALOG")
+	}
+	if src == "" {
+		src = defaultCatalogIndex()
+	}
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		cache = os.TempDir()
+	}
+	return catalog.Open(src, filepath.Join(cache, "containerlab", "catalog"))
+}
+
+// parseCatalogParams parses the template parameters in the format of <name>=<value>
+func parseCatalogParams(params []string) (map[string]string, error) {
+	set := make(map[string]string, len(params))
+	for _, p := range params {
+		i := strings.Index(p, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("malformed template parameter %q, use <name>=<value>", p)
+		}
+		set[p[:i]] = p[i+1:]
+	}
+	return set, nil
+}
+
+// promptParams prompts for the values of the template parameters not set with the flags,
+// an empty answer keeps the parameter default
+func promptParams(t *catalog.Template, set map[string]string, in io.Reader, out io.Writer) error {
+	r := bufio.NewReader(in)
+	for _, p := range t.Params {
+		if _, ok := set[p.Name]; ok {
+			continue
+		}
+		fmt.Fprintf(out, "%s: ", paramUsage(p))
+		line, err := r.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if v := strings.TrimSpace(line); v != "" {
+			set[p.Name] = v
+		}
+		if err == io.EOF {
+			return nil
+		}
+	}
+	return nil
+}
+
+// paramUsage returns the parameter name with its description and default value
+func paramUsage(p *catalog.Param) string {
+	s := p.Name
+	if p.Description != "" {
+		s += " (" + p.Description + ")"
+	}
+	if p.Default != "" {
+		s += " [" + p.Default + "]"
+	}
+	return s
+}
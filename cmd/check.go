@@ -0,0 +1,98 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+// checkCmd represents the check command
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "check if the container host is ready to run a lab",
+	Long:  "verify kernel modules, system limits, memory, virtualization support and binaries needed by a lab\nreference: https://containerlab.srlinux.dev/cmd/check/",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if topo == "" {
+			return fmt.Errorf("provide topology file path with --topo flag")
+		}
+		c, err := clab.NewContainerLab(
+			clab.WithTimeout(timeout),
+			clab.WithTopoVars(topoVars),
+			clab.WithTopoFile(topo),
+			clab.WithLabDirPath(labDirPath),
+			clab.WithRuntime(rt,
+				&runtime.RuntimeConfig{
+					Debug:   debug,
+					Timeout: timeout,
+					Host:    host,
+				},
+			),
+		)
+		if err != nil {
+			return err
+		}
+
+		res := c.CheckHost()
+		printCheckResults(res)
+		return checkResultsErr(res)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+}
+
+func printCheckResults(res []*clab.CheckResult) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Check", "Status", "Details", "Fix"})
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+	for _, r := range res {
+		table.Append([]string{r.Name, r.Status, r.Message, r.Fix})
+	}
+	table.Render()
+}
+
+// checkResultsErr returns an error if any of the checks failed
+func checkResultsErr(res []*clab.CheckResult) error {
+	failed := 0
+	for _, r := range res {
+		if r.Status == clab.CheckFail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d host check(s) failed", failed)
+	}
+	return nil
+}
+
+// runHostChecks logs the warnings and failures of the host checks run before the deployment
+func runHostChecks(c *clab.CLab) error {
+	res := c.CheckHost()
+	for _, r := range res {
+		msg := fmt.Sprintf("host check %q: %s", r.Name, r.Message)
+		if r.Fix != "" {
+			msg = fmt.Sprintf("%s. Fix: %s", msg, r.Fix)
+		}
+		switch r.Status {
+		case clab.CheckWarn:
+			log.Warn(msg)
+		case clab.CheckFail:
+			log.Error(msg)
+		}
+	}
+	if err := checkResultsErr(res); err != nil {
+		return fmt.Errorf("%v, use --skip-checks to deploy anyway", err)
+	}
+	return nil
+}
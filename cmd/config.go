@@ -23,7 +23,7 @@ var configCmd = &cobra.Command{
 	Short:        "configure a lab",
 	Long:         "configure a lab based on templates and variables from the topology definition file\nreference: https://containerlab.srlinux.dev/cmd/config/",
 	Aliases:      []string{"conf"},
-	ValidArgs:    []string{"commit", "send", "compare", "template"},
+	ValidArgs:    []string{"commit", "send", "compare", "template", "push"},
 	SilenceUsage: true,
 	RunE:         configRun,
 }
@@ -60,7 +60,9 @@ func configRun(cmd *cobra.Command, args []string) error {
 
 	c, err := clab.NewContainerLab(
 		clab.WithTimeout(timeout),
+		clab.WithTopoVars(topoVars),
 		clab.WithTopoFile(topo),
+		clab.WithLabDirPath(labDirPath),
 	)
 	if err != nil {
 		return err
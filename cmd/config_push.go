@@ -0,0 +1,47 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+)
+
+// nodes to push the config to
+var pushNodes []string
+
+// configPushCmd represents the config push command
+var configPushCmd = &cobra.Command{
+	Use:          "push",
+	Short:        "push the config snippets and templates of the topology to a running lab",
+	Long:         "push the config snippets and templates set in the topology to the lab nodes with the gNMI or NETCONF transport of their kind\nreference: https://containerlab.srlinux.dev/cmd/config/push/",
+	SilenceUsage: true,
+	PreRunE:      sudoCheck,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 {
+			return fmt.Errorf("unexpected arguments: %s", args)
+		}
+		c, err := clab.NewContainerLab(
+			clab.WithTimeout(timeout),
+			clab.WithTopoVars(topoVars),
+			clab.WithTopoFile(topo),
+			clab.WithLabDirPath(labDirPath),
+		)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		return c.PushConfigs(ctx, pushNodes)
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configPushCmd)
+	configPushCmd.Flags().StringSliceVarP(&pushNodes, "node", "", []string{}, "comma separated list of the nodes to push the config to, the nodes with the config set in the topology by default")
+}
@@ -23,7 +23,9 @@ var configTemplateCmd = &cobra.Command{
 
 		c, err := clab.NewContainerLab(
 			clab.WithTimeout(timeout),
+			clab.WithTopoVars(topoVars),
 			clab.WithTopoFile(topo),
+			clab.WithLabDirPath(labDirPath),
 		)
 		if err != nil {
 			return err
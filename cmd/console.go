@@ -0,0 +1,147 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+	terminal "golang.org/x/term"
+)
+
+// serial console port overriding the discovered one
+var consolePort int
+
+// consoleCmd represents the console command
+var consoleCmd = &cobra.Command{
+	Use:   "console <node>",
+	Short: "attach to the console of a node",
+	Long: `attach the terminal to the serial console of a VM based node or to the CLI or the shell of a container node.
+The console output is logged to the console.log file of the node directory for debugging boot failures
+reference: https://containerlab.srlinux.dev/cmd/console/`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: sudoCheck,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if topo == "" {
+			return fmt.Errorf("provide topology file path with --topo flag")
+		}
+		opts := []clab.ClabOption{
+			clab.WithTimeout(timeout),
+			clab.WithTopoVars(topoVars),
+			clab.WithTopoFile(topo),
+			clab.WithLabDirPath(labDirPath),
+			clab.WithRuntime(rt,
+				&runtime.RuntimeConfig{
+					Debug:            debug,
+					Timeout:          timeout,
+					GracefulShutdown: graceful,
+					Host:             host,
+				},
+			),
+		}
+		c, err := clab.NewContainerLab(opts...)
+		if err != nil {
+			return err
+		}
+		node := args[0]
+		n, ok := c.Nodes[node]
+		if !ok {
+			return fmt.Errorf("node %q is not defined in the topology", node)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		labels := []*types.GenericFilter{
+			{FilterType: "label", Match: c.Config.Name, Field: clab.ContainerlabLabel, Operator: "="},
+			{FilterType: "label", Match: node, Field: clab.NodeNameLabel, Operator: "="},
+		}
+		containers, err := c.ListContainers(ctx, labels)
+		if err != nil {
+			return err
+		}
+		if len(containers) == 0 || containers[0].State != "running" {
+			return fmt.Errorf("node %s is not running", node)
+		}
+
+		logFile, err := openConsoleLog(c.ConsoleLogPath(node), node)
+		if err != nil {
+			return err
+		}
+		defer logFile.Close()
+
+		port := consolePort
+		if port == 0 {
+			port, err = c.SerialConsolePort(ctx, node)
+			if err != nil {
+				return err
+			}
+		}
+		// the serial console is reached over the management network of the local containers,
+		// the consoles of the remote containers are opened with telnet running in the container
+		ip := containers[0].NetworkSettings.IPv4addr
+		if port != 0 && ip != "" && !runtime.IsRemoteHost(host) {
+			return serialConsole(node, net.JoinHostPort(ip, strconv.Itoa(port)), logFile)
+		}
+
+		shell := c.InteractiveCommand(node, "")
+		if port != 0 {
+			shell = c.InteractiveCommand(node, fmt.Sprintf("telnet 127.0.0.1 %d", port))
+		}
+		if shell == "" {
+			return fmt.Errorf("%s runtime doesn't support interactive sessions", n.GetRuntime().GetName())
+		}
+		log.Infof("Attaching to %s, console output is logged to %s", node, logFile.Name())
+		s := exec.Command("sh", "-c", shell)
+		s.Stdin, s.Stdout, s.Stderr = os.Stdin, io.MultiWriter(os.Stdout, logFile), os.Stderr
+		return s.Run()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(consoleCmd)
+	consoleCmd.Flags().IntVarP(&consolePort, "port", "p", 0, "serial console telnet port of the VM based node, discovered from the qemu command line when 0")
+}
+
+// openConsoleLog opens the console log file of the node for appending and writes the session header to it
+func openConsoleLog(path, node string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open console log of node %s: %v", node, err)
+	}
+	fmt.Fprintf(f, "\n--- console session to %s started at %s ---\n", node, time.Now().Format(time.RFC3339))
+	return f, nil
+}
+
+// serialConsole connects the terminal to the serial console telnet server at addr.
+// The terminal is put in the raw mode for the session, so that the control keys reach the console
+func serialConsole(node, addr string, logFile io.Writer) error {
+	conn, err := net.DialTimeout("tcp", addr, 10*time.Second)
+	if err != nil {
+		return fmt.Errorf("failed to connect to the serial console of node %s at %s: %v", node, addr, err)
+	}
+	defer conn.Close()
+	log.Infof("Connected to the serial console of %s at %s, press Ctrl-] to exit", node, addr)
+
+	if fd := int(os.Stdin.Fd()); terminal.IsTerminal(fd) {
+		state, err := terminal.MakeRaw(fd)
+		if err != nil {
+			return err
+		}
+		defer terminal.Restore(fd, state)
+	}
+	return clab.ConsoleSession(conn, os.Stdin, os.Stdout, logFile)
+}
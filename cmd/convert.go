@@ -0,0 +1,79 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/convert"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	// format of the converted topology, detected by the file extension when not set
+	convertFormat string
+	convertKind   string
+	convertImages []string
+	convertFile   string
+)
+
+func init() {
+	rootCmd.AddCommand(convertCmd)
+	convertCmd.Flags().StringVarP(&convertFormat, "format", "f", "", fmt.Sprintf("format of the converted topology, one of %v. Detected by the file extension when not set", convert.Formats))
+	convertCmd.Flags().StringVarP(&convertKind, "kind", "", "linux", "kind of the nodes which type has no matching containerlab kind")
+	convertCmd.Flags().StringSliceVarP(&convertImages, "image", "", []string{}, "image of the kind in the format <kind>=<image_name>")
+	convertCmd.Flags().StringVarP(&convertFile, "file", "", "", "file path to save the converted topology")
+}
+
+// convertCmd represents the convert command
+var convertCmd = &cobra.Command{
+	Use:   "convert file",
+	Short: "convert the topology of the other network emulators to the containerlab topology",
+	Long: `convert the GNS3 project (.gns3), EVE-NG lab (.unl) or NetJSON NetworkGraph (.json) to the containerlab topology.
+The node types are mapped to the containerlab kinds where possible, the elements which can't be converted are reported
+reference: https://containerlab.srlinux.dev/cmd/convert/`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		srcFormat := convertFormat
+		if srcFormat == "" {
+			var err error
+			if srcFormat, err = convert.DetectFormat(args[0]); err != nil {
+				return err
+			}
+		}
+		images, err := parseFlag("", convertImages)
+		if err != nil {
+			return err
+		}
+		data, err := ioutil.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		res, err := convert.Convert(srcFormat, data, &convert.Options{
+			Name:        name,
+			DefaultKind: convertKind,
+			Images:      images,
+		})
+		if err != nil {
+			return err
+		}
+		for _, w := range res.Warnings {
+			log.Warn(w)
+		}
+		b, err := yaml.Marshal(res.Config)
+		if err != nil {
+			return err
+		}
+		log.Infof("Converted %d nodes and %d links of %s", len(res.Config.Topology.Nodes), len(res.Config.Topology.Links), args[0])
+		if convertFile == "" {
+			fmt.Print(string(b))
+			return nil
+		}
+		return saveTopoFile(convertFile, b)
+	},
+}
@@ -11,10 +11,12 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 
 	cfssllog "github.com/cloudflare/cfssl/log"
+	"github.com/olekukonko/tablewriter"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/srl-labs/containerlab/cert"
@@ -35,9 +37,42 @@ var mgmtIPv6Subnet net.IPNet
 // reconfigure flag
 var reconfigure bool
 
+// deploy only the nodes and links missing from the running lab
+var reconcile bool
+
+// resume the paused nodes and restore the checkpointed nodes of the lab
+var restore bool
+
 // max-workers flag
 var maxWorkers uint
 
+// per kind limits of the nodes deployed at once, e.g. vr-*=4
+var kindConcurrency []string
+
+// skip-checks flag
+var skipChecks bool
+
+// path to the file the deployment summary is written to
+var summaryFile string
+
+// wait for the nodes to become ready before the post-deploy tasks
+var waitReady bool
+
+// write the ssh_config file of the lab nodes
+var sshConfig bool
+
+// include the ssh_config file of the lab nodes in the ssh client config of the user
+var sshConfigInclude bool
+
+// parse the topology without deploying the lab
+var dryRun bool
+
+// print the rendered topology in the dry run
+var render bool
+
+// use the local images only
+var skipPull bool
+
 // deployCmd represents the deploy command
 var deployCmd = &cobra.Command{
 	Use:          "deploy",
@@ -47,22 +82,56 @@ var deployCmd = &cobra.Command{
 	SilenceUsage: true,
 	PreRunE:      sudoCheck,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		var err error
+		limits, err := clab.ParseKindConcurrency(kindConcurrency)
+		if err != nil {
+			return err
+		}
+		if render && !dryRun {
+			return fmt.Errorf("--render flag needs the --dry-run flag")
+		}
+		if reconcile && reconfigure {
+			return fmt.Errorf("--reconcile and --reconfigure flags are mutually exclusive")
+		}
+		if restore && reconfigure {
+			return fmt.Errorf("--restore and --reconfigure flags are mutually exclusive")
+		}
 		opts := []clab.ClabOption{
 			clab.WithTimeout(timeout),
+			clab.WithTopoVars(topoVars),
 			clab.WithTopoFile(topo),
+			clab.WithLabDirPath(labDirPath),
 			clab.WithRuntime(rt,
 				&runtime.RuntimeConfig{
 					Debug:            debug,
 					Timeout:          timeout,
 					GracefulShutdown: graceful,
+					Host:             host,
 				},
 			),
+			clab.WithKindConcurrency(limits),
+		}
+		if skipPull {
+			opts = append(opts, clab.WithSkipPull())
 		}
 		c, err := clab.NewContainerLab(opts...)
 		if err != nil {
 			return err
 		}
+		if dryRun {
+			return dryRunLab(c)
+		}
+
+		summaryFormat, err := summaryFileFormat(summaryFile)
+		if err != nil {
+			return err
+		}
+
+		// keep prune from removing the resources of the lab while it is being deployed
+		release, err := clab.DeployLock()
+		if err != nil {
+			return fmt.Errorf("failed to take the deploy lock: %v", err)
+		}
+		defer release()
 
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
@@ -74,6 +143,10 @@ var deployCmd = &cobra.Command{
 		vCh := make(chan string)
 		go getLatestVersion(vCh)
 
+		if reconcile || restore {
+			return reconcileLab(ctx, c)
+		}
+
 		if reconfigure {
 			if err != nil {
 				return err
@@ -93,8 +166,37 @@ var deployCmd = &cobra.Command{
 			return err
 		}
 
+		if err = c.CheckQuota(); err != nil {
+			return err
+		}
+
+		if err = c.ProvisionJumpHost(); err != nil {
+			return err
+		}
+
+		if err = c.InitCA(); err != nil {
+			return err
+		}
+
+		if !skipChecks {
+			if err = runHostChecks(c); err != nil {
+				return err
+			}
+		}
+
+		if err = c.AllocateMgmtAddresses(ctx); err != nil {
+			return err
+		}
+		if err = c.RenderEnvTemplates(); err != nil {
+			return err
+		}
+		c.ConfigureNTP()
+
 		log.Info("Creating lab directory: ", c.Dir.Lab)
 		utils.CreateDirectory(c.Dir.Lab, 0755)
+		if err = c.CreatePersistDirs(); err != nil {
+			return err
+		}
 
 		// create an empty ansible inventory file that will get populated later
 		// we create it here first, so that bind mounts of ansible-inventory.yml file could work
@@ -111,6 +213,9 @@ var deployCmd = &cobra.Command{
 		if err := cert.CreateRootCA(c.Config.Name, c.Dir.LabCARoot, c.Nodes); err != nil {
 			return err
 		}
+		if err := c.InstallCABundle(); err != nil {
+			return err
+		}
 
 		// create docker network or use existing one
 		if err = c.GlobalRuntime().CreateNet(ctx); err != nil {
@@ -153,10 +258,21 @@ var deployCmd = &cobra.Command{
 
 		for _, n := range c.Nodes {
 			n.Config().ExtraHosts = extraHosts
+			// peer entries go first to resolve peer names to the link addresses
+			if n.Config().PeerHosts {
+				n.Config().ExtraHosts = append(c.PeerHosts(n.Config()), extraHosts...)
+			}
+		}
+
+		if err := c.RunHooks(ctx, clab.HookPreDeploy, nil); err != nil {
+			return err
 		}
 
 		nodesStaticWg, nodesDynWg := c.CreateNodes(ctx, nodeWorkers, serialNodes)
 		c.CreateLinks(ctx, linkWorkers, false)
+		if err := c.RemoveWiringAgent(ctx); err != nil {
+			log.Warnf("failed to remove wiring agent: %v", err)
+		}
 		if nodesStaticWg != nil {
 			nodesStaticWg.Wait()
 		}
@@ -175,17 +291,35 @@ var deployCmd = &cobra.Command{
 
 		log.Debug("enriching nodes with IP information...")
 		enrichNodes(containers, c.Nodes)
+		c.SetMgmtNetem()
+		c.SetStaticRoutes()
 
 		if err := c.GenerateInventories(); err != nil {
 			return err
 		}
 
+		if err := c.GenerateManifest(); err != nil {
+			return err
+		}
+		log.Infof("Lab manifest written to %s", c.ManifestPath())
+
+		if err := c.PopulateNodeHosts(ctx); err != nil {
+			log.Error(err)
+		}
+
 		wg := &sync.WaitGroup{}
 		wg.Add(len(c.Nodes))
 
 		for _, node := range c.Nodes {
 			go func(node nodes.Node, wg *sync.WaitGroup) {
 				defer wg.Done()
+				if err := clab.WaitForNode(ctx, node, waitReady); err != nil {
+					log.Errorf("skipping postdeploy task: %v", err)
+					return
+				}
+				if err := c.VerifyVrInterfaces(node.Config()); err != nil {
+					log.Errorf("interfaces of the VM may be misordered: %v", err)
+				}
 				err := node.PostDeploy(ctx, c.Nodes)
 				if err != nil {
 					log.Errorf("failed to run postdeploy task for node %s: %v", node.Config().ShortName, err)
@@ -194,6 +328,26 @@ var deployCmd = &cobra.Command{
 		}
 		wg.Wait()
 
+		// copy the license files of the nodes licensed after boot
+		if err := c.CopyLicenses(ctx); err != nil {
+			log.Error(err)
+		}
+
+		// install the lab certificates on the nodes importing them after boot
+		if err := c.InstallCerts(ctx); err != nil {
+			log.Error(err)
+		}
+
+		// push the config snippets and templates set in the topology
+		if err := c.PushConfigs(ctx, nil); err != nil {
+			log.Error(err)
+		}
+
+		// register the lab nodes with the external controller
+		if err := c.OnboardNodes(ctx); err != nil {
+			log.Error(err)
+		}
+
 		// Update containers after postDeploy action
 		containers, err = c.ListContainers(ctx, labels)
 		if err != nil {
@@ -233,11 +387,53 @@ var deployCmd = &cobra.Command{
 			fmt.Println(string(result))
 		}
 
+		if err := c.RunHooks(ctx, clab.HookPostDeploy, containers); err != nil {
+			return err
+		}
+
 		// log new version availability info if ready
 		newVerNotification(vCh)
 
-		// print table summary
-		printContainerInspect(c, containers, c.Config.Mgmt.Network, format)
+		// print deployment summary with the access details and the resources of the nodes
+		summary := c.AccessSummary(containers)
+		labSummary := saveLabSummary(ctx, c, containers)
+		if format == "json" {
+			if err := c.WriteAccessSummary(os.Stdout, clab.SummaryFormatJSON, summary); err != nil {
+				return err
+			}
+		} else {
+			printAccessSummary(summary, labSummary)
+		}
+		if summaryFile != "" {
+			if err := writeSummaryFile(c, summaryFile, summaryFormat, summary); err != nil {
+				return err
+			}
+			log.Infof("Lab summary written to %s", summaryFile)
+		}
+
+		if sshConfig || sshConfigInclude {
+			p, err := c.WriteSSHConfig()
+			if err != nil {
+				return err
+			}
+			if sshConfigInclude {
+				if err := includeSSHConfig(p); err != nil {
+					return err
+				}
+			} else if format != "json" {
+				fmt.Printf("\nLab nodes ssh config is saved to %s, include it in ~/.ssh/config to reach the nodes with 'ssh <node>':\n\nInclude %s\n", p, p)
+			}
+		}
+
+		if c.Config.JumpHost != nil {
+			p, sshCfg, err := c.WriteJumpHostSSHConfig()
+			if err != nil {
+				return err
+			}
+			if format != "json" {
+				fmt.Printf("\nLab nodes are reachable through the jump host with the following ssh config (saved to %s):\n\n%s", p, sshCfg)
+			}
+		}
 
 		return nil
 	},
@@ -250,7 +446,152 @@ func init() {
 	deployCmd.Flags().IPNetVarP(&mgmtIPv4Subnet, "ipv4-subnet", "4", net.IPNet{}, "management network IPv4 subnet range")
 	deployCmd.Flags().IPNetVarP(&mgmtIPv6Subnet, "ipv6-subnet", "6", net.IPNet{}, "management network IPv6 subnet range")
 	deployCmd.Flags().BoolVarP(&reconfigure, "reconfigure", "", false, "regenerate configuration artifacts and overwrite the previous ones if any")
+	deployCmd.Flags().BoolVarP(&reconcile, "reconcile", "", false, "deploy the nodes and links of the topology missing from the running lab, leaving the deployed nodes untouched")
+	deployCmd.Flags().BoolVarP(&restore, "restore", "", false, "resume the paused nodes and restore the checkpointed nodes of the lab where they left off")
 	deployCmd.Flags().UintVarP(&maxWorkers, "max-workers", "", 0, "limit the maximum number of workers creating nodes and virtual wires")
+	deployCmd.Flags().StringSliceVarP(&kindConcurrency, "concurrency", "", []string{}, "limit the number of the nodes of the kinds matching a pattern deployed at once, e.g. vr-*=4")
+	deployCmd.Flags().BoolVarP(&skipChecks, "skip-checks", "", false, "do not run host checks before the deployment")
+	deployCmd.Flags().BoolVarP(&skipPull, "skip-pull", "", false, "do not pull the node images, the deployment fails before creating the nodes when an image is not present locally")
+	deployCmd.Flags().BoolVarP(&waitReady, "wait", "", false, "wait for the nodes to become ready, e.g. the VMs of vrnetlab nodes to boot, before running the post-deploy tasks")
+	deployCmd.Flags().BoolVarP(&sshConfig, "ssh-config", "", false, "write the ssh_config file with the entries of the lab nodes to the lab directory")
+	deployCmd.Flags().BoolVarP(&sshConfigInclude, "ssh-config-include", "", false, "write the ssh_config file of the lab nodes and include it in ~/.ssh/config")
+	deployCmd.Flags().BoolVarP(&dryRun, "dry-run", "", false, "parse the topology and exit without deploying the lab")
+	deployCmd.Flags().BoolVarP(&render, "render", "", false, "print the topology rendered with the template vars and the env vars in the dry run")
+	deployCmd.Flags().StringVarP(&summaryFile, "summary-file", "", "", "write the deployment summary to a file, the format (json or markdown) is derived from the .json or .md extension")
+}
+
+// summaryFileFormat returns the summary format matching the file extension
+func summaryFileFormat(p string) (string, error) {
+	if p == "" {
+		return "", nil
+	}
+	switch strings.ToLower(filepath.Ext(p)) {
+	case ".json":
+		return clab.SummaryFormatJSON, nil
+	case ".md", ".markdown":
+		return clab.SummaryFormatMarkdown, nil
+	}
+	return "", fmt.Errorf("unsupported summary file extension %q, use .json or .md", filepath.Ext(p))
+}
+
+func writeSummaryFile(c *clab.CLab, p, format string, summary []*clab.NodeAccess) error {
+	f, err := os.Create(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return c.WriteAccessSummary(f, format, summary)
+}
+
+// printAccessSummary prints the access details of the nodes as a table.
+// The image digests and the resources of the nodes are taken from the lab summary s, if set,
+// the columns no node has a value for are left out
+func printAccessSummary(summary []*clab.NodeAccess, s *clab.LabSummary) {
+	nodeSummary := map[string]*clab.NodeSummary{}
+	if s != nil {
+		for _, n := range s.Nodes {
+			nodeSummary[n.Name] = n
+		}
+	}
+	columns := []struct {
+		name  string
+		value func(*clab.NodeSummary) string
+	}{
+		{"Image Digest", func(n *clab.NodeSummary) string { return shortDigest(n.ImageDigest) }},
+		{"CPU", func(n *clab.NodeSummary) string { return n.CPU }},
+		{"Memory", func(n *clab.NodeSummary) string { return n.Memory }},
+		{"CPU Set", func(n *clab.NodeSummary) string { return n.CPUSet }},
+		{"Cert Fingerprint", func(n *clab.NodeSummary) string { return shortFingerprint(n.CertFingerprint) }},
+	}
+	header := []string{"#", "Name", "Kind", "State", "IPv4 Address", "IPv6 Address", "SSH", "gNMI", "Credentials", "Console"}
+	rows := make([][]string, len(summary))
+	for i, n := range summary {
+		creds := ""
+		if n.Username != "" {
+			creds = n.Username + ":" + n.Password
+		}
+		rows[i] = []string{
+			strconv.Itoa(i + 1),
+			n.Name,
+			n.Kind,
+			n.State,
+			n.MgmtIPv4,
+			n.MgmtIPv6,
+			n.SSH,
+			n.GNMI,
+			creds,
+			n.Console,
+		}
+	}
+	for _, col := range columns {
+		values := make([]string, len(summary))
+		set := false
+		for i, n := range summary {
+			if ns, ok := nodeSummary[n.Name]; ok {
+				values[i] = col.value(ns)
+				set = set || values[i] != ""
+			}
+		}
+		if !set {
+			continue
+		}
+		header = append(header, col.name)
+		for i := range rows {
+			rows[i] = append(rows[i], values[i])
+		}
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(header)
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+	table.AppendBulk(rows)
+	table.Render()
+
+	if s != nil && (s.CPU > 0 || s.Memory != "") {
+		cpu, mem := "no", "no"
+		if s.CPU > 0 {
+			cpu = strconv.FormatFloat(s.CPU, 'g', -1, 64)
+		}
+		if s.Memory != "" {
+			mem = s.Memory
+		}
+		fmt.Printf("Lab %s nodes reserve %s CPUs and %s of memory\n", s.Name, cpu, mem)
+	}
+}
+
+// saveLabSummary writes the summary of the lab nodes resources and links to the lab directory
+// and returns it. The lab is deployed by then, so the summary errors are logged as warnings
+func saveLabSummary(ctx context.Context, c *clab.CLab, containers []types.GenericContainer) *clab.LabSummary {
+	s, err := c.LabSummary(ctx, containers)
+	if err != nil {
+		log.Warnf("failed to generate lab summary: %v", err)
+		return nil
+	}
+	p, err := c.SaveLabSummary(s)
+	if err != nil {
+		log.Warnf("failed to save lab summary: %v", err)
+		return s
+	}
+	log.Infof("Lab topology summary written to %s", p)
+	return s
+}
+
+// shortDigest returns the digest with the hex part shortened to 12 characters, e.g. sha256:0123456789ab
+func shortDigest(d string) string {
+	i := strings.Index(d, ":")
+	if i < 0 || len(d)-i-1 <= 12 {
+		return d
+	}
+	return d[:i+13]
+}
+
+// shortFingerprint returns the first 4 bytes of the colon separated fingerprint
+func shortFingerprint(f string) string {
+	if len(f) <= 11 {
+		return f
+	}
+	return f[:11] + "..."
 }
 
 func setFlags(conf *clab.Config) {
@@ -289,3 +630,73 @@ func enrichNodes(containers []types.GenericContainer, nodesMap map[string]nodes.
 		}
 	}
 }
+
+// includeSSHConfig adds the Include directive of the lab ssh_config file p to the ssh client config of the user
+func includeSSHConfig(p string) error {
+	userCfg, err := clab.UserSSHConfig()
+	if err != nil {
+		return err
+	}
+	added, err := clab.InstallSSHConfigInclude(userCfg, p)
+	if err != nil {
+		return fmt.Errorf("failed to include lab ssh config in %s: %v", userCfg, err)
+	}
+	if added {
+		log.Infof("Lab nodes ssh config %s is included in %s", p, userCfg)
+	}
+	return nil
+}
+
+// reconcileLab deploys the nodes and links missing from the running lab, with --restore flag
+// the paused and checkpointed nodes are resumed first, then the lab inventories are regenerated
+// and the access summary is printed
+func reconcileLab(ctx context.Context, c *clab.CLab) error {
+	var restored, added []string
+	var err error
+	if restore {
+		restored, added, err = c.RestoreLab(ctx)
+	} else {
+		added, err = c.ReconcileLab(ctx)
+	}
+	if err != nil {
+		return err
+	}
+	labels := []*types.GenericFilter{{FilterType: "label", Match: c.Config.Name, Field: "containerlab", Operator: "="}}
+	containers, err := c.ListContainers(ctx, labels)
+	if err != nil {
+		return err
+	}
+	enrichNodes(containers, c.Nodes)
+	if err := c.GenerateInventories(); err != nil {
+		return err
+	}
+	if err := c.GenerateManifest(); err != nil {
+		return err
+	}
+	if err := c.PopulateNodeHosts(ctx); err != nil {
+		log.Error(err)
+	}
+	if len(restored) > 0 {
+		log.Infof("Nodes %s of lab %s resumed", strings.Join(restored, ", "), c.Config.Name)
+	}
+	if len(added) > 0 {
+		log.Infof("Nodes %s added to lab %s", strings.Join(added, ", "), c.Config.Name)
+	}
+	summary := c.AccessSummary(containers)
+	labSummary := saveLabSummary(ctx, c, containers)
+	if format == "json" {
+		return c.WriteAccessSummary(os.Stdout, clab.SummaryFormatJSON, summary)
+	}
+	printAccessSummary(summary, labSummary)
+	return nil
+}
+
+// dryRunLab reports the nodes and links of the parsed topology, or prints the rendered topology
+func dryRunLab(c *clab.CLab) error {
+	if render {
+		_, err := os.Stdout.Write(c.RenderedTopology())
+		return err
+	}
+	log.Infof("Dry run of lab %s: %d nodes and %d links parsed, nothing is deployed", c.Config.Name, len(c.Nodes), len(c.Links))
+	return nil
+}
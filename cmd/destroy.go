@@ -9,18 +9,28 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/srl-labs/containerlab/clab"
 	"github.com/srl-labs/containerlab/runtime"
 	"github.com/srl-labs/containerlab/types"
+	terminal "golang.org/x/term"
 )
 
 var (
-	cleanup     bool
-	graceful    bool
-	keepMgmtNet bool
+	cleanup         bool
+	graceful        bool
+	keepMgmtNet     bool
+	shutdownTimeout time.Duration
+	// save the node configs and archive the lab directory before the lab is destroyed
+	saveLab bool
+	// destroy all labs without confirmation
+	destroyYes bool
+	// remove the nodes without shutting them down
+	destroyForce bool
 )
 
 // destroyCmd represents the destroy command
@@ -38,11 +48,14 @@ var destroyCmd = &cobra.Command{
 
 		opts := []clab.ClabOption{
 			clab.WithTimeout(timeout),
+			clab.WithShutdownTimeout(shutdownTimeout),
 			clab.WithRuntime(rt,
 				&runtime.RuntimeConfig{
-					Debug:            debug,
-					Timeout:          timeout,
-					GracefulShutdown: graceful,
+					Debug:   debug,
+					Timeout: timeout,
+					// the nodes are shut down before they are removed unless forced
+					GracefulShutdown: !destroyForce,
+					Host:             host,
 				},
 			),
 		}
@@ -71,15 +84,27 @@ var destroyCmd = &cobra.Command{
 			if len(containers) == 0 {
 				return fmt.Errorf("no containerlab labs were found")
 			}
+			found := clab.DiscoverLabs(containers)
+			if !destroyYes {
+				if !terminal.IsTerminal(int(os.Stdin.Fd())) {
+					return fmt.Errorf("%d labs would be destroyed, use --yes flag to destroy them without confirmation", len(found))
+				}
+				if !confirmDestroy(found, os.Stdin, os.Stdout) {
+					log.Info("Destroy cancelled")
+					return nil
+				}
+			}
 			// get unique topo files from all labs
-			for _, cont := range containers {
-				topos[cont.Labels["clab-topo-file"]] = struct{}{}
+			for _, l := range found {
+				topos[l.TopoFile] = struct{}{}
 			}
 		}
 
 		for topo := range topos {
 			opts := append(opts,
+				clab.WithTopoVars(topoVars),
 				clab.WithTopoFile(topo),
+				clab.WithLabDirPath(labDirPath),
 			)
 			c, err := clab.NewContainerLab(opts...)
 			if err != nil {
@@ -113,9 +138,14 @@ func init() {
 	rootCmd.AddCommand(destroyCmd)
 	destroyCmd.Flags().BoolVarP(&cleanup, "cleanup", "", false, "delete lab directory")
 	destroyCmd.Flags().BoolVarP(&graceful, "graceful", "", false, "attempt to stop containers before removing")
+	_ = destroyCmd.Flags().MarkDeprecated("graceful", "the nodes are shut down gracefully by default, use --force to remove them right away")
+	destroyCmd.Flags().BoolVarP(&destroyForce, "force", "f", false, "remove the nodes right away, without shutting them down")
+	destroyCmd.Flags().DurationVarP(&shutdownTimeout, "shutdown-timeout", "", 2*time.Minute, "time given to the VM based nodes without stop-timeout to shut down gracefully")
 	destroyCmd.Flags().BoolVarP(&all, "all", "a", false, "destroy all containerlab labs")
+	destroyCmd.Flags().BoolVarP(&destroyYes, "yes", "y", false, "destroy all labs without confirmation")
 	destroyCmd.Flags().UintVarP(&maxWorkers, "max-workers", "", 0, "limit the maximum number of workers deleting nodes")
 	destroyCmd.Flags().BoolVarP(&keepMgmtNet, "keep-mgmt-net", "", false, "do not remove the management network")
+	destroyCmd.Flags().BoolVarP(&saveLab, "save", "", false, "save the node configs and archive the lab directory to a timestamped tar.gz file before destroying the lab")
 }
 
 func destroyLab(ctx context.Context, c *clab.CLab) (err error) {
@@ -125,13 +155,33 @@ func destroyLab(ctx context.Context, c *clab.CLab) (err error) {
 	if err != nil {
 		return err
 	}
+	imported, err := c.ListImportedContainers(ctx)
+	if err != nil {
+		return err
+	}
+	containers = append(containers, imported...)
 	if len(containers) == 0 {
 		return nil
 	}
 
+	if saveLab {
+		if err := saveAndArchiveLab(ctx, c, containers); err != nil {
+			return err
+		}
+	}
+
 	var labDir string
 	if cleanup {
-		labDir = filepath.Dir(containers[0].Labels["clab-node-lab-dir"])
+		labDir = clab.LabDirFromLabels(containers[0].Labels)
+	}
+
+	if err := c.RunHooks(ctx, clab.HookPreDestroy, containers); err != nil {
+		return err
+	}
+
+	// the imported containers are kept running, only their lab links and management network are removed
+	if err := c.ReleaseImported(ctx); err != nil {
+		log.Errorf("failed to release imported containers: %v", err)
 	}
 
 	if maxWorkers == 0 {
@@ -154,14 +204,39 @@ func destroyLab(ctx context.Context, c *clab.CLab) (err error) {
 	}
 
 	log.Infof("Destroying lab: %s", c.Config.Name)
+	c.DetachHostNICs(ctx)
 	c.DeleteNodes(ctx, maxWorkers, c.Nodes, serialNodes)
-
-	// remove the lab directories
+	c.DeleteVxlanStitches()
 	if cleanup {
+		c.DeletePersistVolumes(ctx)
+	}
+
+	if err = c.ReleaseMgmtAddresses(ctx); err != nil {
+		log.Errorf("failed to release management addresses: %v", err)
+	}
+
+	// remove the lab directory, the node directories moved out of it with the lab-dir setting are kept,
+	// as they may hold the data of the user
+	if cleanup && labDir != "" {
 		err = os.RemoveAll(labDir)
 		if err != nil {
 			log.Errorf("error deleting lab directory: %v", err)
 		}
+		for _, n := range c.Nodes {
+			if d := n.Config().LabDir; d != "" && !strings.HasPrefix(d, labDir+string(filepath.Separator)) {
+				log.Infof("Keeping the directory %s of node %s outside of the lab directory", d, n.Config().ShortName)
+			}
+		}
+	}
+
+	// the lab ssh_config file included with deploy --ssh-config-include is removed with the lab directory
+	if cleanup {
+		if userCfg, err := clab.UserSSHConfig(); err == nil {
+			p := clab.SSHConfigPath(clab.LabDirFromLabels(containers[0].Labels))
+			if err := clab.RemoveSSHConfigInclude(userCfg, p); err != nil {
+				log.Warnf("failed to remove lab ssh config include from %s: %v", userCfg, err)
+			}
+		}
 	}
 
 	log.Info("Removing containerlab host entries from /etc/hosts file")
@@ -182,3 +257,20 @@ func destroyLab(ctx context.Context, c *clab.CLab) (err error) {
 	// delete container network namespaces symlinks
 	return c.DeleteNetnsSymlinks()
 }
+
+// saveAndArchiveLab saves the configs of the lab nodes and archives the lab directory
+func saveAndArchiveLab(ctx context.Context, c *clab.CLab, containers []types.GenericContainer) error {
+	log.Infof("Saving configuration of lab %s nodes", c.Config.Name)
+	saveConfigs(ctx, c)
+	enrichNodes(containers, c.Nodes)
+	labDir := clab.LabDirFromLabels(containers[0].Labels)
+	if labDir == "" {
+		labDir = c.Dir.Lab
+	}
+	p, err := c.ArchiveLab(labDir, containers, time.Now())
+	if err != nil {
+		return err
+	}
+	log.Infof("Lab %s archived to %s", c.Config.Name, p)
+	return nil
+}
@@ -9,7 +9,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/google/shlex"
 	log "github.com/sirupsen/logrus"
@@ -20,9 +24,16 @@ import (
 )
 
 var (
-	labels      []string
-	execFormat  string
-	execCommand string
+	labels         []string
+	execFormat     string
+	execCommand    string
+	execSaveOutput bool
+	execJUnit      string
+	execExpect     string
+	execAttach     string
+	// node names and kinds the exec command is limited to, glob patterns are accepted
+	execNodes []string
+	execKinds []string
 )
 
 // execCmd represents the exec command
@@ -36,8 +47,14 @@ var execCmd = &cobra.Command{
 
 		}
 
-		if execCommand == "" {
-			return errors.New("provide command to execute")
+		switch execAttach {
+		case "":
+			if execCommand == "" {
+				return errors.New("provide command to execute")
+			}
+		case attachTmux, attachSequential:
+		default:
+			return fmt.Errorf("unsupported attach layout %q, use one of [%s, %s]", execAttach, attachTmux, attachSequential)
 		}
 
 		switch execFormat {
@@ -49,12 +66,15 @@ var execCmd = &cobra.Command{
 		}
 		opts := []clab.ClabOption{
 			clab.WithTimeout(timeout),
+			clab.WithTopoVars(topoVars),
 			clab.WithTopoFile(topo),
+			clab.WithLabDirPath(labDirPath),
 			clab.WithRuntime(rt,
 				&runtime.RuntimeConfig{
 					Debug:            debug,
 					Timeout:          timeout,
 					GracefulShutdown: graceful,
+					Host:             host,
 				},
 			),
 		}
@@ -76,10 +96,30 @@ var execCmd = &cobra.Command{
 			return err
 		}
 
+		if containers, err = filterExecContainers(containers, execNodes, execKinds); err != nil {
+			return err
+		}
+
 		if len(containers) == 0 {
 			return errors.New("no containers found")
 		}
 
+		if execAttach != "" {
+			return attachNodes(c, name, containers)
+		}
+
+		var expect *regexp.Regexp
+		if execExpect != "" {
+			if expect, err = regexp.Compile(execExpect); err != nil {
+				return fmt.Errorf("failed to parse --expect regexp: %v", err)
+			}
+		}
+		cmdArgs, err := shlex.Split(execCommand)
+		if err != nil {
+			return err
+		}
+
+		var results []*execResult
 		jsonResult := make(map[string]map[string]map[string]interface{})
 		for _, cont := range containers {
 			if cont.State != "running" {
@@ -94,10 +134,29 @@ var execCmd = &cobra.Command{
 			}
 
 			contName := strings.TrimLeft(cont.Names[0], "/")
-			if jsonResult[contName], err = execCmds(
-				ctx, cont, nodeRuntime, []string{execCommand}, execFormat,
-			); err != nil {
-				return err
+			res := &execResult{node: contName, cmd: execCommand}
+			start := time.Now()
+			res.stdout, res.stderr, res.exitCode, res.err = nodeRuntime.ExecWithExitCode(ctx, cont.ID, cmdArgs)
+			res.duration = time.Since(start)
+			res.evaluate(expect)
+			results = append(results, res)
+			if res.err != nil {
+				log.Errorf("%s: failed to execute cmd: %v", contName, res.err)
+				continue
+			}
+
+			out := execOutput(contName, execCommand, res.stdout, res.stderr, execFormat)
+			if out != nil {
+				out["exit_code"] = res.exitCode
+			}
+			jsonResult[contName] = map[string]map[string]interface{}{
+				execCommand: out,
+			}
+
+			if execSaveOutput {
+				if err := saveExecOutput(clab.LabDirFromLabels(cont.Labels), cont.Labels[clab.NodeNameLabel], res); err != nil {
+					log.Errorf("%s: failed to save cmd output: %v", contName, err)
+				}
 			}
 		}
 		if execFormat == "json" {
@@ -107,10 +166,75 @@ var execCmd = &cobra.Command{
 			}
 			fmt.Println(string(result))
 		}
-		return err
+
+		if execJUnit != "" {
+			if err := writeJUnitReport(execJUnit, name, results); err != nil {
+				return fmt.Errorf("failed to write JUnit report: %v", err)
+			}
+			log.Infof("JUnit report written to %s", execJUnit)
+		}
+		// exec fails on the failed commands only when used as a test step
+		if execJUnit != "" || expect != nil {
+			if failed := failedExecResults(results); failed > 0 {
+				return fmt.Errorf("command failed on %d of %d nodes", failed, len(results))
+			}
+		}
+		return nil
 	},
 }
 
+// filterExecContainers returns the containers of the nodes matching any of the node name patterns
+// and any of the kind patterns, an empty list of patterns matches all the nodes
+func filterExecContainers(containers []types.GenericContainer, nodes, kinds []string) ([]types.GenericContainer, error) {
+	for _, p := range append(append([]string{}, nodes...), kinds...) {
+		if _, err := filepath.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %v", p, err)
+		}
+	}
+	matchAny := func(patterns []string, s string) bool {
+		if len(patterns) == 0 {
+			return true
+		}
+		for _, p := range patterns {
+			if ok, _ := filepath.Match(p, s); ok {
+				return true
+			}
+		}
+		return false
+	}
+	var res []types.GenericContainer
+	for _, cont := range containers {
+		if matchAny(nodes, cont.Labels[clab.NodeNameLabel]) && matchAny(kinds, cont.Labels[clab.NodeKindLabel]) {
+			res = append(res, cont)
+		}
+	}
+	return res, nil
+}
+
+// attachNodes opens the interactive sessions to the running containers
+func attachNodes(c *clab.CLab, lab string, containers []types.GenericContainer) error {
+	var sessions []*execSession
+	for _, cont := range containers {
+		if cont.State != "running" {
+			continue
+		}
+		node := cont.Labels[clab.NodeNameLabel]
+		cmd := c.InteractiveCommand(node, execCommand)
+		if cmd == "" {
+			log.Warnf("%s: runtime doesn't support interactive sessions, skipping", node)
+			continue
+		}
+		sessions = append(sessions, &execSession{node: node, cmd: cmd})
+	}
+	if len(sessions) == 0 {
+		return errors.New("no running containers to attach to")
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].node < sessions[j].node
+	})
+	return attachSessions(execAttach, lab, sessions)
+}
+
 func execCmds(
 	ctx context.Context,
 	cont types.GenericContainer,
@@ -118,8 +242,6 @@ func execCmds(
 	cmds []string,
 	format string,
 ) (result map[string]map[string]interface{}, err error) {
-	var doc interface{}
-
 	result = make(map[string]map[string]interface{})
 	for _, cmd := range cmds {
 		c, err := shlex.Split(cmd)
@@ -133,32 +255,48 @@ func execCmds(
 			return nil, nil
 		}
 
-		switch format {
-		case "json":
-			result[cmd] = make(map[string]interface{})
-			if json.Unmarshal([]byte(stdout), &doc) == nil {
-				result[cmd]["stdout"] = doc
-			} else {
-				result[cmd]["stdout"] = string(stdout)
-			}
-			result[cmd]["stderr"] = string(stderr)
-		case "plain", "table":
-			contName := strings.TrimLeft(cont.Names[0], "/")
-			if len(stdout) > 0 {
-				log.Infof("Executed command '%s' on %s. stdout:\n%s", cmd, contName, string(stdout))
-			}
-			if len(stderr) > 0 {
-				log.Infof("Executed command '%s' on %s. stderr:\n%s", cmd, contName, string(stderr))
-			}
+		contName := strings.TrimLeft(cont.Names[0], "/")
+		if out := execOutput(contName, cmd, stdout, stderr, format); out != nil {
+			result[cmd] = out
 		}
 	}
 
 	return result, nil
 }
 
+// execOutput returns the cmd output for the json format and logs it for the plain and table formats
+func execOutput(contName, cmd string, stdout, stderr []byte, format string) map[string]interface{} {
+	switch format {
+	case "json":
+		var doc interface{}
+		result := make(map[string]interface{})
+		if json.Unmarshal([]byte(stdout), &doc) == nil {
+			result["stdout"] = doc
+		} else {
+			result["stdout"] = string(stdout)
+		}
+		result["stderr"] = string(stderr)
+		return result
+	case "plain", "table":
+		if len(stdout) > 0 {
+			log.Infof("Executed command '%s' on %s. stdout:\n%s", cmd, contName, string(stdout))
+		}
+		if len(stderr) > 0 {
+			log.Infof("Executed command '%s' on %s. stderr:\n%s", cmd, contName, string(stderr))
+		}
+	}
+	return nil
+}
+
 func init() {
 	rootCmd.AddCommand(execCmd)
 	execCmd.Flags().StringVarP(&execCommand, "cmd", "", "", "command to execute")
 	execCmd.Flags().StringSliceVarP(&labels, "label", "", []string{}, "labels to filter container subset")
+	execCmd.Flags().StringSliceVarP(&execNodes, "node", "", []string{}, "names of the nodes to execute the command on, glob patterns are accepted")
+	execCmd.Flags().StringSliceVarP(&execKinds, "kind", "", []string{}, "kinds of the nodes to execute the command on, glob patterns are accepted")
 	execCmd.Flags().StringVarP(&execFormat, "format", "f", "plain", "output format. One of [json, plain]")
+	execCmd.Flags().BoolVarP(&execSaveOutput, "save-output", "", false, "save the command output of each node to the exec directory of the lab")
+	execCmd.Flags().StringVarP(&execJUnit, "junit", "", "", "write JUnit XML report with the command results to a file")
+	execCmd.Flags().StringVarP(&execExpect, "expect", "", "", "regular expression the command stdout is expected to match")
+	execCmd.Flags().StringVarP(&execAttach, "attach", "", "", "open interactive sessions to the nodes instead of running the command. One of [tmux, sequential]")
 }
@@ -0,0 +1,97 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// interactive session layouts of the exec command
+const (
+	attachTmux       = "tmux"
+	attachSequential = "sequential"
+)
+
+// execSession is an interactive session to a node
+type execSession struct {
+	node string
+	// shell command line opening the session
+	cmd string
+}
+
+// attachSessions opens the interactive sessions with the layout
+func attachSessions(layout, lab string, sessions []*execSession) error {
+	switch layout {
+	case attachTmux:
+		return attachTmuxSessions("clab-"+lab, sessions)
+	case attachSequential:
+		return attachSequentialSessions(sessions)
+	}
+	return fmt.Errorf("unsupported attach layout %q, use one of [%s, %s]", layout, attachTmux, attachSequential)
+}
+
+// attachSequentialSessions attaches the terminal to the sessions one after another,
+// the next session is opened once the previous one is exited
+func attachSequentialSessions(sessions []*execSession) error {
+	for _, s := range sessions {
+		log.Infof("Attaching to %s, exit the session to continue with the next node", s.node)
+		c := exec.Command("sh", "-c", s.cmd)
+		c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if err := c.Run(); err != nil {
+			log.Warnf("%s: session exited with error: %v", s.node, err)
+		}
+	}
+	return nil
+}
+
+// attachTmuxSessions opens the sessions in the tiled panes of a tmux window
+// and attaches the terminal to the tmux session
+func attachTmuxSessions(name string, sessions []*execSession) error {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		return fmt.Errorf("tmux is required for %q layout: %v", attachTmux, err)
+	}
+	// the session of the previous run is replaced, the error of a missing session is ignored
+	_ = exec.Command("tmux", "kill-session", "-t", name).Run()
+	for _, args := range tmuxCmds(name, sessions) {
+		if out, err := exec.Command("tmux", args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("tmux %v failed: %v: %s", args, err, out)
+		}
+	}
+	attach := []string{"attach-session", "-t", name}
+	// switch the client of the running tmux instead of nesting the sessions
+	if os.Getenv("TMUX") != "" {
+		attach = []string{"switch-client", "-t", name}
+	}
+	c := exec.Command("tmux", attach...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return c.Run()
+}
+
+// tmuxCmds returns the tmux commands creating a detached tmux session with a pane per session.
+// The panes are titled with the node names and keep open after the session exits
+func tmuxCmds(name string, sessions []*execSession) [][]string {
+	var cmds [][]string
+	for i, s := range sessions {
+		if i == 0 {
+			cmds = append(cmds,
+				[]string{"new-session", "-d", "-s", name, "-n", "clab", s.cmd},
+				[]string{"set-option", "-t", name, "remain-on-exit", "on"},
+				[]string{"set-option", "-t", name, "pane-border-status", "top"},
+			)
+		} else {
+			cmds = append(cmds,
+				[]string{"split-window", "-t", name, s.cmd},
+				// panes are tiled after every split, so that the window has room for the next one
+				[]string{"select-layout", "-t", name, "tiled"},
+			)
+		}
+		cmds = append(cmds, []string{"select-pane", "-t", name, "-T", s.node})
+	}
+	return cmds
+}
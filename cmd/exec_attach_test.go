@@ -0,0 +1,31 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTmuxCmds(t *testing.T) {
+	sessions := []*execSession{
+		{node: "srl1", cmd: "docker exec -it clab-lab-srl1 sr_cli"},
+		{node: "srl2", cmd: "docker exec -it clab-lab-srl2 sr_cli"},
+	}
+	want := [][]string{
+		{"new-session", "-d", "-s", "clab-lab", "-n", "clab", "docker exec -it clab-lab-srl1 sr_cli"},
+		{"set-option", "-t", "clab-lab", "remain-on-exit", "on"},
+		{"set-option", "-t", "clab-lab", "pane-border-status", "top"},
+		{"select-pane", "-t", "clab-lab", "-T", "srl1"},
+		{"split-window", "-t", "clab-lab", "docker exec -it clab-lab-srl2 sr_cli"},
+		{"select-layout", "-t", "clab-lab", "tiled"},
+		{"select-pane", "-t", "clab-lab", "-T", "srl2"},
+	}
+	got := tmuxCmds("clab-lab", sessions)
+	if !cmp.Equal(got, want) {
+		t.Errorf("diff (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// execResult holds the outcome of a command executed on a node
+type execResult struct {
+	node     string
+	cmd      string
+	stdout   []byte
+	stderr   []byte
+	exitCode int
+	duration time.Duration
+	// error executing the command
+	err error
+	// reason the command is considered failed, empty for passed commands
+	failure string
+}
+
+// evaluate sets the failure reason of the result based on the exit code
+// and the match of the stdout against the expected regexp
+func (r *execResult) evaluate(expect *regexp.Regexp) {
+	switch {
+	case r.err != nil:
+		r.failure = fmt.Sprintf("failed to execute command: %v", r.err)
+	case r.exitCode != 0:
+		r.failure = fmt.Sprintf("command exited with code %d", r.exitCode)
+	case expect != nil && !expect.Match(r.stdout):
+		r.failure = fmt.Sprintf("output doesn't match %q", expect.String())
+	}
+}
+
+func failedExecResults(results []*execResult) int {
+	failed := 0
+	for _, r := range results {
+		if r.failure != "" {
+			failed++
+		}
+	}
+	return failed
+}
+
+// saveExecOutput writes the stdout and stderr of the command to <node>.stdout and <node>.stderr files
+// in the exec directory of the lab
+func saveExecOutput(labDir, node string, r *execResult) error {
+	if labDir == "" {
+		return fmt.Errorf("lab directory of node %s is unknown", node)
+	}
+	dir := filepath.Join(labDir, "exec")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, node+".stdout"), r.stdout, 0644); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, node+".stderr"), r.stderr, 0644)
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name          `xml:"testsuites"`
+	Suites  []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string           `xml:"name,attr"`
+	Tests     int              `xml:"tests,attr"`
+	Failures  int              `xml:"failures,attr"`
+	Errors    int              `xml:"errors,attr"`
+	Time      string           `xml:"time,attr"`
+	TestCases []*junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+	SystemErr string        `xml:"system-err,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+}
+
+func junitTime(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// writeJUnit writes the JUnit XML report with a test case per node to w
+func writeJUnit(w io.Writer, suite string, results []*execResult) error {
+	s := &junitTestSuite{Name: suite, Tests: len(results)}
+	var total time.Duration
+	for _, r := range results {
+		tc := &junitTestCase{
+			Name:      r.node,
+			ClassName: suite,
+			Time:      junitTime(r.duration),
+			SystemOut: string(r.stdout),
+			SystemErr: string(r.stderr),
+		}
+		switch {
+		case r.err != nil:
+			s.Errors++
+			tc.Error = &junitMessage{Message: r.failure}
+		case r.failure != "":
+			s.Failures++
+			tc.Failure = &junitMessage{Message: r.failure}
+		}
+		total += r.duration
+		s.TestCases = append(s.TestCases, tc)
+	}
+	s.Time = junitTime(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(&junitTestSuites{Suites: []*junitTestSuite{s}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func writeJUnitReport(path, suite string, results []*execResult) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return writeJUnit(f, suite, results)
+}
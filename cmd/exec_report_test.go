@@ -0,0 +1,83 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExecResultEvaluate(t *testing.T) {
+	tests := map[string]struct {
+		res    *execResult
+		expect string
+		want   string
+	}{
+		"passed": {
+			res: &execResult{stdout: []byte("ok")},
+		},
+		"exit_code": {
+			res:  &execResult{exitCode: 2},
+			want: "command exited with code 2",
+		},
+		"exec_error": {
+			res:  &execResult{err: errors.New("no such container")},
+			want: "failed to execute command: no such container",
+		},
+		"expect_matched": {
+			res:    &execResult{stdout: []byte("3 packets transmitted, 3 received")},
+			expect: `3 received`,
+		},
+		"expect_not_matched": {
+			res:    &execResult{stdout: []byte("3 packets transmitted, 0 received")},
+			expect: `3 received`,
+			want:   `output doesn't match "3 received"`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var expect *regexp.Regexp
+			if tc.expect != "" {
+				expect = regexp.MustCompile(tc.expect)
+			}
+			tc.res.evaluate(expect)
+			if tc.res.failure != tc.want {
+				t.Errorf("got failure %q, want %q", tc.res.failure, tc.want)
+			}
+		})
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	results := []*execResult{
+		{node: "clab-lab-n1", cmd: "ping -c1 n2", stdout: []byte("1 received"), duration: 1500 * time.Millisecond},
+		{node: "clab-lab-n2", cmd: "ping -c1 n2", exitCode: 1, failure: "command exited with code 1", duration: 500 * time.Millisecond},
+	}
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="lab" tests="2" failures="1" errors="0" time="2.000">
+    <testcase name="clab-lab-n1" classname="lab" time="1.500">
+      <system-out>1 received</system-out>
+    </testcase>
+    <testcase name="clab-lab-n2" classname="lab" time="0.500">
+      <failure message="command exited with code 1"></failure>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+	var s strings.Builder
+	if err := writeJUnit(&s, "lab", results); err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(s.String(), want) {
+		t.Errorf("expected\n%v, got\n%v", want, s.String())
+	}
+}
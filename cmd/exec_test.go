@@ -0,0 +1,77 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestFilterExecContainers(t *testing.T) {
+	cont := func(node, kind string) types.GenericContainer {
+		return types.GenericContainer{
+			Names:  []string{"clab-lab-" + node},
+			Labels: map[string]string{clab.NodeNameLabel: node, clab.NodeKindLabel: kind},
+		}
+	}
+	containers := []types.GenericContainer{
+		cont("leaf1", "srl"),
+		cont("leaf2", "srl"),
+		cont("spine1", "ceos"),
+		cont("client1", "linux"),
+	}
+	tests := map[string]struct {
+		nodes   []string
+		kinds   []string
+		want    []string
+		wantErr bool
+	}{
+		"no_filters": {
+			want: []string{"leaf1", "leaf2", "spine1", "client1"},
+		},
+		"node_names": {
+			nodes: []string{"leaf2", "client1"},
+			want:  []string{"leaf2", "client1"},
+		},
+		"node_pattern": {
+			nodes: []string{"leaf*"},
+			want:  []string{"leaf1", "leaf2"},
+		},
+		"kinds": {
+			kinds: []string{"ceos", "linux"},
+			want:  []string{"spine1", "client1"},
+		},
+		"nodes_and_kinds": {
+			nodes: []string{"*1"},
+			kinds: []string{"srl"},
+			want:  []string{"leaf1"},
+		},
+		"no_match": {
+			kinds: []string{"vr-*"},
+		},
+		"bad_pattern": {
+			nodes:   []string{"leaf["},
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := filterExecContainers(containers, tc.nodes, tc.kinds)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got error %v, wantErr %v", err, tc.wantErr)
+			}
+			var gotNodes []string
+			for _, c := range got {
+				gotNodes = append(gotNodes, c.Labels[clab.NodeNameLabel])
+			}
+			if d := cmp.Diff(tc.want, gotNodes); d != "" {
+				t.Errorf("diff (-want +got):\n%s", d)
+			}
+		})
+	}
+}
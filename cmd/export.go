@@ -0,0 +1,75 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+// path to the exported snapshot directory
+var snapshotDir string
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "export lab to the formats of other tools",
+	Long:  "export command groups the exporters of the lab state to the formats of other tools\nreference: https://containerlab.srlinux.dev/cmd/export/batfish/",
+}
+
+// exportBatfishCmd represents the export batfish command
+var exportBatfishCmd = &cobra.Command{
+	Use:     "batfish",
+	Short:   "export lab to Batfish snapshot",
+	Long:    "export the configuration of the lab nodes and the lab topology to the Batfish snapshot directory\nreference: https://containerlab.srlinux.dev/cmd/export/batfish/",
+	PreRunE: sudoCheck,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if topo == "" {
+			return fmt.Errorf("provide topology file path with --topo flag")
+		}
+		opts := []clab.ClabOption{
+			clab.WithTimeout(timeout),
+			clab.WithTopoVars(topoVars),
+			clab.WithTopoFile(topo),
+			clab.WithLabDirPath(labDirPath),
+			clab.WithRuntime(rt,
+				&runtime.RuntimeConfig{
+					Debug:            debug,
+					Timeout:          timeout,
+					GracefulShutdown: graceful,
+					Host:             host,
+				},
+			),
+		}
+		c, err := clab.NewContainerLab(opts...)
+		if err != nil {
+			return err
+		}
+
+		dir := snapshotDir
+		if dir == "" {
+			dir = filepath.Join(c.Dir.Lab, "batfish")
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		if err := c.ExportBatfish(ctx, dir); err != nil {
+			return err
+		}
+		log.Infof("Batfish snapshot saved to %s", dir)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.AddCommand(exportBatfishCmd)
+	exportBatfishCmd.Flags().StringVarP(&snapshotDir, "dir", "", "", "path to the snapshot directory. Default is batfish directory in the lab directory")
+}
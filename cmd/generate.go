@@ -7,6 +7,7 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"os"
 	"strconv"
@@ -16,6 +17,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/srl-labs/containerlab/clab"
 	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
 	"gopkg.in/yaml.v2"
 )
 
@@ -37,6 +39,9 @@ const (
 	defaultSRLType     = "ixrd2"
 	defaultNodePrefix  = "node"
 	defaultGroupPrefix = "tier"
+	// the static management addresses of the generated nodes start after the offset,
+	// leaving the first addresses of the management subnet to the gateway and other hosts
+	mgmtIPOffset = 10
 )
 
 var errDuplicatedValue = errors.New("duplicated value definition")
@@ -51,10 +56,19 @@ var groupPrefix string
 var file string
 var deploy bool
 
+// number of the spine and leaf nodes of the spine-leaf fabric
+var spines uint
+var leaves uint
+
+// allocate static management addresses to the generated nodes
+var mgmtIPs bool
+
 type nodesDef struct {
 	numNodes uint
 	kind     string
 	typ      string
+	// name of the tier used in the node names and groups instead of the tier number, e.g. leaf
+	name string
 }
 
 // generateCmd represents the generate command
@@ -78,13 +92,30 @@ var generateCmd = &cobra.Command{
 		}
 		log.Debugf("parsed images: %+v", images)
 
-		nodeDefs, err := parseNodesFlag(kind, nodesFlag...)
+		var nodeDefs []nodesDef
+		if (spines > 0 || leaves > 0) && len(nodesFlag) > 0 {
+			return errors.New("--spines and --leaves flags can't be used with --nodes flag")
+		}
+		if spines > 0 || leaves > 0 {
+			nodeDefs, err = spineLeafNodes(kind, spines, leaves)
+			// the nodes of the named tiers are not prefixed unless the prefix is set
+			if !cmd.Flags().Changed("node-prefix") {
+				nodePrefix = ""
+			}
+		} else {
+			nodeDefs, err = parseNodesFlag(kind, nodesFlag...)
+		}
 		if err != nil {
 			return err
 		}
 		log.Debugf("parsed nodes definitions: %+v", nodeDefs)
 
-		b, err := generateTopologyConfig(name, mgmtNetName, mgmtIPv4Subnet.String(), mgmtIPv6Subnet.String(), images, licenses, nodeDefs...)
+		ipv4Range, ipv6Range := mgmtIPv4Subnet.String(), mgmtIPv6Subnet.String()
+		if mgmtIPs && ipv4Range == "<nil>" && ipv6Range == "<nil>" {
+			return errors.New("--mgmt-ips flag needs the management subnet set with --ipv4-subnet or --ipv6-subnet flags")
+		}
+
+		b, err := generateTopologyConfig(name, mgmtNetName, ipv4Range, ipv6Range, mgmtIPs, images, licenses, nodeDefs...)
 		if err != nil {
 			return err
 		}
@@ -122,6 +153,9 @@ func init() {
 	generateCmd.Flags().StringSliceVarP(&image, "image", "", []string{}, "container image name, can be prefixed with the node kind. <kind>=<image_name>")
 	generateCmd.Flags().StringVarP(&kind, "kind", "", "srl", fmt.Sprintf("container kind, one of %v", supportedKinds))
 	generateCmd.Flags().StringSliceVarP(&nodesFlag, "nodes", "", []string{}, "comma separated nodes definitions in format <num_nodes>:<kind>:<type>, each defining a Clos network stage")
+	generateCmd.Flags().UintVarP(&spines, "spines", "", 0, "number of the spine nodes of a spine-leaf fabric, used with --leaves instead of --nodes")
+	generateCmd.Flags().UintVarP(&leaves, "leaves", "", 0, "number of the leaf nodes of a spine-leaf fabric, used with --spines instead of --nodes")
+	generateCmd.Flags().BoolVarP(&mgmtIPs, "mgmt-ips", "", false, "allocate static management addresses to the nodes from the management subnets")
 	generateCmd.Flags().StringSliceVarP(&license, "license", "", []string{}, "path to license file, can be prefix with the node kind. <kind>=/path/to/file")
 	generateCmd.Flags().StringVarP(&nodePrefix, "node-prefix", "", defaultNodePrefix, "prefix used in node names")
 	generateCmd.Flags().StringVarP(&groupPrefix, "group-prefix", "", defaultGroupPrefix, "prefix used in group names")
@@ -130,7 +164,7 @@ func init() {
 	generateCmd.Flags().UintVarP(&maxWorkers, "max-workers", "", 0, "limit the maximum number of workers creating nodes and virtual wires")
 }
 
-func generateTopologyConfig(name, network, ipv4range, ipv6range string, images map[string]string, licenses map[string]string, nodes ...nodesDef) ([]byte, error) {
+func generateTopologyConfig(name, network, ipv4range, ipv6range string, mgmtIPs bool, images map[string]string, licenses map[string]string, nodes ...nodesDef) ([]byte, error) {
 	numStages := len(nodes)
 	config := &clab.Config{
 		Name: name,
@@ -158,15 +192,12 @@ func generateTopologyConfig(name, network, ipv4range, ipv6range string, images m
 		}
 		config.Topology.Kinds[k] = &types.NodeDefinition{License: lic}
 	}
-	if numStages == 1 {
-		for j := uint(0); j < nodes[0].numNodes; j++ {
-			node1 := fmt.Sprintf("%s1-%d", nodePrefix, j+1)
-			if _, ok := config.Topology.Nodes[node1]; !ok {
-				config.Topology.Nodes[node1] = &types.NodeDefinition{
-					Group: fmt.Sprintf("%s-1", groupPrefix),
-					Kind:  nodes[0].kind,
-					Type:  nodes[0].typ,
-				}
+	for i := 0; i < numStages; i++ {
+		for j := uint(0); j < nodes[i].numNodes; j++ {
+			config.Topology.Nodes[tierNodeName(nodes, i, j)] = &types.NodeDefinition{
+				Group: tierGroup(nodes, i),
+				Kind:  nodes[i].kind,
+				Type:  nodes[i].typ,
 			}
 		}
 	}
@@ -176,23 +207,9 @@ func generateTopologyConfig(name, network, ipv4range, ipv6range string, images m
 			interfaceOffset = nodes[i-1].numNodes
 		}
 		for j := uint(0); j < nodes[i].numNodes; j++ {
-			node1 := fmt.Sprintf("%s%d-%d", nodePrefix, i+1, j+1)
-			if _, ok := config.Topology.Nodes[node1]; !ok {
-				config.Topology.Nodes[node1] = &types.NodeDefinition{
-					Group: fmt.Sprintf("%s-%d", groupPrefix, i+1),
-					Kind:  nodes[i].kind,
-					Type:  nodes[i].typ,
-				}
-			}
+			node1 := tierNodeName(nodes, i, j)
 			for k := uint(0); k < nodes[i+1].numNodes; k++ {
-				node2 := fmt.Sprintf("%s%d-%d", nodePrefix, i+2, k+1)
-				if _, ok := config.Topology.Nodes[node2]; !ok {
-					config.Topology.Nodes[node2] = &types.NodeDefinition{
-						Group: fmt.Sprintf("%s-%d", groupPrefix, i+2),
-						Kind:  nodes[i+1].kind,
-						Type:  nodes[i+1].typ,
-					}
-				}
+				node2 := tierNodeName(nodes, i+1, k)
 				config.Topology.Links = append(config.Topology.Links, &types.LinkConfig{
 					Endpoints: []string{
 						node1 + ":" + fmt.Sprintf(interfaceFormat[nodes[i].kind], k+1+interfaceOffset),
@@ -202,9 +219,69 @@ func generateTopologyConfig(name, network, ipv4range, ipv6range string, images m
 			}
 		}
 	}
+	if mgmtIPs {
+		if err := allocateMgmtIPs(config, nodes); err != nil {
+			return nil, err
+		}
+	}
 	return yaml.Marshal(config)
 }
 
+// tierNodeName returns the name of the j-th node of the i-th tier,
+// e.g. node1-1 for the numbered tiers or leaf1 for the named tiers
+func tierNodeName(nodes []nodesDef, i int, j uint) string {
+	if nodes[i].name != "" {
+		return fmt.Sprintf("%s%s%d", nodePrefix, nodes[i].name, j+1)
+	}
+	return fmt.Sprintf("%s%d-%d", nodePrefix, i+1, j+1)
+}
+
+// tierGroup returns the group of the nodes of the i-th tier
+func tierGroup(nodes []nodesDef, i int) string {
+	if nodes[i].name != "" {
+		return nodes[i].name
+	}
+	return fmt.Sprintf("%s-%d", groupPrefix, i+1)
+}
+
+// spineLeafNodes returns the tiers of the spine-leaf fabric, the leaves are the first tier
+func spineLeafNodes(kind string, spines, leaves uint) ([]nodesDef, error) {
+	if spines == 0 || leaves == 0 {
+		return nil, errors.New("both --spines and --leaves flags are to be set")
+	}
+	defs, err := parseNodesFlag(kind, strconv.Itoa(int(leaves)), strconv.Itoa(int(spines)))
+	if err != nil {
+		return nil, err
+	}
+	defs[0].name = "leaf"
+	defs[1].name = "spine"
+	return defs, nil
+}
+
+// allocateMgmtIPs sets the static management addresses of the nodes from the management subnets,
+// the addresses are allocated tier by tier in the order of the node names
+func allocateMgmtIPs(config *clab.Config, nodes []nodesDef) error {
+	n := mgmtIPOffset
+	for i := range nodes {
+		for j := uint(0); j < nodes[i].numNodes; j++ {
+			n++
+			node := config.Topology.Nodes[tierNodeName(nodes, i, j)]
+			var err error
+			if config.Mgmt.IPv4Subnet != "" {
+				if node.MgmtIPv4, err = utils.CIDRHost(config.Mgmt.IPv4Subnet, n); err != nil {
+					return fmt.Errorf("failed to allocate the management address: %v", err)
+				}
+			}
+			if config.Mgmt.IPv6Subnet != "" {
+				if node.MgmtIPv6, err = utils.CIDRHost(config.Mgmt.IPv6Subnet, n); err != nil {
+					return fmt.Errorf("failed to allocate the management address: %v", err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 func parseFlag(kind string, ls []string) (map[string]string, error) {
 	result := make(map[string]string)
 	for _, l := range ls {
@@ -293,7 +370,19 @@ func parseNodesFlag(kind string, nodes ...string) ([]nodesDef, error) {
 	return result, nil
 }
 
+// saveTopoFile writes the topology to a file,
+// comments and keys order of an existing topology file are preserved
 func saveTopoFile(path string, data []byte) error {
+	if utils.FileExists(path) {
+		existing, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		data, err = utils.MergeYAML(existing, data)
+		if err != nil {
+			return fmt.Errorf("failed to update topology file %s: %v", path, err)
+		}
+	}
 	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
 	if err != nil {
 		return err
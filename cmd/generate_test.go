@@ -8,6 +8,8 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/clab"
+	"gopkg.in/yaml.v2"
 )
 
 type flagInput struct {
@@ -203,3 +205,59 @@ func TestParseNodes(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateSpineLeaf(t *testing.T) {
+	defer func(p string) { nodePrefix = p }(nodePrefix)
+	nodePrefix = ""
+	nodes, err := spineLeafNodes("srl", 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := generateTopologyConfig("fabric", "", "172.100.100.0/24", "<nil>", true, nil, nil, nodes...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &clab.Config{}
+	if err := yaml.Unmarshal(b, config); err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]string{}
+	for name, n := range config.Topology.Nodes {
+		got[name] = n.Group + " " + n.MgmtIPv4
+	}
+	want := map[string]string{
+		"leaf1":  "leaf 172.100.100.11",
+		"leaf2":  "leaf 172.100.100.12",
+		"leaf3":  "leaf 172.100.100.13",
+		"spine1": "spine 172.100.100.14",
+		"spine2": "spine 172.100.100.15",
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("nodes mismatch (-want +got):\n%s", d)
+	}
+	var links [][]string
+	for _, l := range config.Topology.Links {
+		links = append(links, l.Endpoints)
+	}
+	wantLinks := [][]string{
+		{"leaf1:e1-1", "spine1:e1-1"},
+		{"leaf1:e1-2", "spine2:e1-1"},
+		{"leaf2:e1-1", "spine1:e1-2"},
+		{"leaf2:e1-2", "spine2:e1-2"},
+		{"leaf3:e1-1", "spine1:e1-3"},
+		{"leaf3:e1-2", "spine2:e1-3"},
+	}
+	if d := cmp.Diff(wantLinks, links); d != "" {
+		t.Errorf("links mismatch (-want +got):\n%s", d)
+	}
+
+	if _, err := spineLeafNodes("srl", 2, 0); err == nil {
+		t.Error("expected error for the fabric without leaves")
+	}
+	// the addresses out of the management subnet are not allocated
+	nodes, _ = spineLeafNodes("srl", 4, 4)
+	if _, err := generateTopologyConfig("fabric", "", "172.100.100.0/28", "<nil>", true, nil, nil, nodes...); err == nil {
+		t.Error("expected error for the management subnet without enough addresses")
+	}
+}
@@ -5,20 +5,25 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	_ "embed"
 	"encoding/json"
 	"fmt"
 	"html/template"
+	"net"
 	"net/http"
 	"sort"
 	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/nodes"
 	"github.com/srl-labs/containerlab/runtime"
 	"github.com/srl-labs/containerlab/types"
+	"golang.org/x/net/websocket"
 )
 
 var (
@@ -26,14 +31,26 @@ var (
 	tmpl    string
 	offline bool
 	dot     bool
+	export  string
+	serve   bool
+	refresh time.Duration
 
 	//go:embed graph-template.html
 	graphTemplate string
+	//go:embed graph-live-template.html
+	graphLiveTemplate string
 )
 
 type graphTopo struct {
-	Nodes []containerDetails `json:"nodes,omitempty"`
-	Links []link             `json:"links,omitempty"`
+	Nodes []graphNode `json:"nodes,omitempty"`
+	Links []link      `json:"links,omitempty"`
+}
+
+// graphNode is a node of the HTML graph with the click-through urls of the deployed node
+type graphNode struct {
+	containerDetails
+	SSHURL     string `json:"ssh_url,omitempty"`
+	ConsoleURL string `json:"console_url,omitempty"`
 }
 type link struct {
 	Source         string `json:"source,omitempty"`
@@ -58,12 +75,15 @@ var graphCmd = &cobra.Command{
 
 		opts := []clab.ClabOption{
 			clab.WithTimeout(timeout),
+			clab.WithTopoVars(topoVars),
 			clab.WithTopoFile(topo),
+			clab.WithLabDirPath(labDirPath),
 			clab.WithRuntime(rt,
 				&runtime.RuntimeConfig{
 					Debug:            debug,
 					Timeout:          timeout,
 					GracefulShutdown: graceful,
+					Host:             host,
 				},
 			),
 		}
@@ -78,43 +98,30 @@ var graphCmd = &cobra.Command{
 			}
 			return nil
 		}
-		gtopo := graphTopo{
-			Nodes: make([]containerDetails, 0, len(c.Nodes)),
-			Links: make([]link, 0, len(c.Links)),
-		}
-
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
 
-		var containers []types.GenericContainer
-		// if offline mode is not enforced, list containers matching lab name
-		if !offline {
-			labels := []*types.GenericFilter{{FilterType: "label", Match: c.Config.Name, Field: "containerlab", Operator: "="}}
-			containers, err = c.ListContainers(ctx, labels)
+		if export != "" {
+			f, err := clab.GraphExportFormat(export)
 			if err != nil {
 				return err
 			}
-
-			log.Debugf("found %d containers", len(containers))
-		}
-
-		switch {
-		case len(containers) == 0:
-			buildGraphFromTopo(&gtopo, c)
-		case len(containers) > 0:
-			buildGraphFromDeployedLab(&gtopo, c, containers)
+			var states map[string]string
+			// the exports other than the images color the nodes by their state
+			if !offline && f != clab.GraphFormatSVG && f != clab.GraphFormatPNG {
+				if states, err = graphNodeStates(ctx, c); err != nil {
+					return err
+				}
+			}
+			if err := c.ExportGraph(export, states); err != nil {
+				return err
+			}
+			log.Infof("Topology graph saved to %s", export)
+			return nil
 		}
-
-		sort.Slice(gtopo.Nodes, func(i, j int) bool {
-			return gtopo.Nodes[i].Name < gtopo.Nodes[j].Name
-		})
-		for _, l := range c.Links {
-			gtopo.Links = append(gtopo.Links, link{
-				Source:         l.A.Node.ShortName,
-				SourceEndpoint: l.A.EndpointName,
-				Target:         l.B.Node.ShortName,
-				TargetEndpoint: l.B.EndpointName,
-			})
+		gtopo, err := buildGraphTopo(ctx, c)
+		if err != nil {
+			return err
 		}
 		b, err := json.Marshal(gtopo)
 		if err != nil {
@@ -126,15 +133,23 @@ var graphCmd = &cobra.Command{
 			Data: template.JS(string(b)),
 		}
 		var t *template.Template
-		if tmpl != "" {
+		switch {
+		case tmpl != "":
 			t = template.Must(template.ParseFiles(tmpl))
-		} else {
+		case serve:
+			t = template.Must(template.New("graph").Parse(graphLiveTemplate))
+		default:
 			t = template.Must(template.New("graph").Parse(graphTemplate))
 		}
 
 		http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 			_ = t.Execute(w, topoD)
 		})
+		if serve {
+			http.Handle("/ws", websocket.Handler(func(ws *websocket.Conn) {
+				sendGraphUpdates(ctx, ws, c)
+			}))
+		}
 
 		log.Infof("Listening on %s...", srv)
 		err = http.ListenAndServe(srv, nil)
@@ -145,10 +160,127 @@ var graphCmd = &cobra.Command{
 	},
 }
 
+// buildGraphTopo builds the graph of the lab from the lab containers,
+// or from the topology file when the lab is not deployed or the offline mode is enforced
+func buildGraphTopo(ctx context.Context, c *clab.CLab) (*graphTopo, error) {
+	gtopo := &graphTopo{
+		Nodes: make([]graphNode, 0, len(c.Nodes)),
+		Links: make([]link, 0, len(c.Links)),
+	}
+
+	var containers []types.GenericContainer
+	// if offline mode is not enforced, list containers matching lab name
+	if !offline {
+		var err error
+		labels := []*types.GenericFilter{{FilterType: "label", Match: c.Config.Name, Field: "containerlab", Operator: "="}}
+		containers, err = c.ListContainers(ctx, labels)
+		if err != nil {
+			return nil, err
+		}
+
+		log.Debugf("found %d containers", len(containers))
+	}
+
+	switch {
+	case len(containers) == 0:
+		buildGraphFromTopo(gtopo, c)
+	case len(containers) > 0:
+		buildGraphFromDeployedLab(gtopo, c, containers)
+	}
+
+	sort.Slice(gtopo.Nodes, func(i, j int) bool {
+		return gtopo.Nodes[i].Name < gtopo.Nodes[j].Name
+	})
+	keys := make([]int, 0, len(c.Links))
+	for k := range c.Links {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	for _, k := range keys {
+		l := c.Links[k]
+		gtopo.Links = append(gtopo.Links, link{
+			Source:         l.A.Node.ShortName,
+			SourceEndpoint: l.A.EndpointName,
+			Target:         l.B.Node.ShortName,
+			TargetEndpoint: l.B.EndpointName,
+		})
+	}
+	return gtopo, nil
+}
+
+// sendGraphUpdates sends the graph of the lab to the websocket of the live graph once connected,
+// and then every refresh interval when the graph differs from the last sent one
+func sendGraphUpdates(ctx context.Context, ws *websocket.Conn, c *clab.CLab) {
+	defer ws.Close()
+	ticker := time.NewTicker(refresh)
+	defer ticker.Stop()
+	var last []byte
+	for ; ; <-ticker.C {
+		if ctx.Err() != nil {
+			return
+		}
+		g, err := buildGraphTopo(ctx, c)
+		if err != nil {
+			log.Warnf("failed to update the graph: %v", err)
+			continue
+		}
+		b, err := json.Marshal(g)
+		if err != nil {
+			log.Warnf("failed to update the graph: %v", err)
+			continue
+		}
+		if bytes.Equal(b, last) {
+			continue
+		}
+		// the send fails once the page is closed
+		if err := websocket.Message.Send(ws, string(b)); err != nil {
+			log.Debugf("graph updates stopped: %v", err)
+			return
+		}
+		last = b
+	}
+}
+
+// graphNodeURLs returns the ssh and console urls of the node with the mgmt address addr,
+// the serial console is served over telnet by the vrnetlab nodes only
+func graphNodeURLs(kind, addr string) (sshURL, consoleURL string) {
+	ip := strings.Split(addr, "/")[0]
+	if net.ParseIP(ip) == nil {
+		return "", ""
+	}
+	host := ip
+	if strings.Contains(ip, ":") {
+		host = "[" + ip + "]"
+	}
+	sshURL = "ssh://" + host
+	if nodes.IsVrKind(kind) {
+		consoleURL = "telnet://" + net.JoinHostPort(ip, "5000")
+	}
+	return sshURL, consoleURL
+}
+
+// graphNodeStates returns the container states of the deployed lab nodes by the node names,
+// nil if the lab is not deployed
+func graphNodeStates(ctx context.Context, c *clab.CLab) (map[string]string, error) {
+	labels := []*types.GenericFilter{{FilterType: "label", Match: c.Config.Name, Field: "containerlab", Operator: "="}}
+	containers, err := c.ListContainers(ctx, labels)
+	if err != nil {
+		return nil, err
+	}
+	if len(containers) == 0 {
+		return nil, nil
+	}
+	states := make(map[string]string, len(containers))
+	for _, cont := range containers {
+		states[cont.Labels[clab.NodeNameLabel]] = cont.State
+	}
+	return states, nil
+}
+
 func buildGraphFromTopo(g *graphTopo, c *clab.CLab) {
-	log.Info("building graph from topology file")
+	log.Debug("building graph from topology file")
 	for _, node := range c.Nodes {
-		g.Nodes = append(g.Nodes, containerDetails{
+		g.Nodes = append(g.Nodes, graphNode{containerDetails: containerDetails{
 			Name:        node.Config().ShortName,
 			Kind:        node.Config().Kind,
 			Image:       node.Config().Image,
@@ -156,7 +288,7 @@ func buildGraphFromTopo(g *graphTopo, c *clab.CLab) {
 			State:       "N/A",
 			IPv4Address: node.Config().MgmtIPv4Address,
 			IPv6Address: node.Config().MgmtIPv6Address,
-		})
+		}})
 	}
 
 }
@@ -169,7 +301,7 @@ func buildGraphFromDeployedLab(g *graphTopo, c *clab.CLab, containers []types.Ge
 		}
 		log.Debugf("looking for node name %s", name)
 		if node, ok := c.Nodes[name]; ok {
-			g.Nodes = append(g.Nodes, containerDetails{
+			n := graphNode{containerDetails: containerDetails{
 				Name:        name,
 				Kind:        node.Config().Kind,
 				Image:       cont.Image,
@@ -177,7 +309,15 @@ func buildGraphFromDeployedLab(g *graphTopo, c *clab.CLab, containers []types.Ge
 				State:       fmt.Sprintf("%s/%s", cont.State, cont.Status),
 				IPv4Address: getContainerIPv4(cont, c.Config.Mgmt.Network),
 				IPv6Address: getContainerIPv6(cont, c.Config.Mgmt.Network),
-			})
+			}}
+			if cont.State == "running" {
+				addr := n.IPv4Address
+				if net.ParseIP(strings.Split(addr, "/")[0]) == nil {
+					addr = n.IPv6Address
+				}
+				n.SSHURL, n.ConsoleURL = graphNodeURLs(n.Kind, addr)
+			}
+			g.Nodes = append(g.Nodes, n)
 		}
 	}
 }
@@ -188,4 +328,7 @@ func init() {
 	graphCmd.Flags().BoolVarP(&offline, "offline", "o", false, "use only information from topo file when building graph")
 	graphCmd.Flags().BoolVarP(&dot, "dot", "", false, "generate dot file instead of launching the web server")
 	graphCmd.Flags().StringVarP(&tmpl, "template", "", "", "Go html template used to generate the graph")
+	graphCmd.Flags().BoolVarP(&serve, "serve", "", false, "serve the live graph updated with the state of the lab nodes")
+	graphCmd.Flags().DurationVarP(&refresh, "refresh", "", 2*time.Second, "interval of the live graph updates")
+	graphCmd.Flags().StringVarP(&export, "export", "", "", "export the graph to the .svg or .png image, the .drawio diagram, the .mmd mermaid or the .dot file instead of launching the web server")
 }
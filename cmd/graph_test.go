@@ -0,0 +1,48 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"testing"
+)
+
+func TestGraphNodeURLs(t *testing.T) {
+	tests := map[string]struct {
+		kind    string
+		addr    string
+		ssh     string
+		console string
+	}{
+		"srl": {
+			kind: "srl",
+			addr: "172.20.20.2/24",
+			ssh:  "ssh://172.20.20.2",
+		},
+		"vr_sros": {
+			kind:    "vr-sros",
+			addr:    "172.20.20.3/24",
+			ssh:     "ssh://172.20.20.3",
+			console: "telnet://172.20.20.3:5000",
+		},
+		"ipv6": {
+			kind:    "vr-sros",
+			addr:    "2001:172:20:20::3/64",
+			ssh:     "ssh://[2001:172:20:20::3]",
+			console: "telnet://[2001:172:20:20::3]:5000",
+		},
+		"no_address": {
+			kind: "linux",
+			addr: "NA",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ssh, console := graphNodeURLs(tc.kind, tc.addr)
+			if ssh != tc.ssh || console != tc.console {
+				t.Errorf("got %q, %q, want %q, %q", ssh, console, tc.ssh, tc.console)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.Flags().UintVarP(&maxWorkers, "max-workers", "", 0, "limit the maximum number of workers wiring the links")
+}
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "import running containers into a lab",
+	Long: `import adopts the running containers matched by the import setting of the topology nodes into the lab.
+The containers are connected to the management network and the links of the topology are wired into their network namespaces,
+inspect and destroy treat them as the lab nodes and destroy keeps the containers running
+reference: https://containerlab.srlinux.dev/cmd/import/`,
+	SilenceUsage: true,
+	PreRunE:      sudoCheck,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if topo == "" {
+			return fmt.Errorf("provide a topology file path with --topo flag")
+		}
+		c, err := clab.NewContainerLab(
+			clab.WithTimeout(timeout),
+			clab.WithTopoVars(topoVars),
+			clab.WithTopoFile(topo),
+			clab.WithLabDirPath(labDirPath),
+			clab.WithRuntime(rt,
+				&runtime.RuntimeConfig{
+					Debug:            debug,
+					Timeout:          timeout,
+					GracefulShutdown: graceful,
+					Host:             host,
+				},
+			),
+		)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		imported, err := c.Import(ctx, maxWorkers)
+		if err != nil {
+			return err
+		}
+		log.Infof("Imported %d containers into lab %s", len(imported), c.Config.Name)
+
+		containers, err := c.ListImportedContainers(ctx)
+		if err != nil {
+			return err
+		}
+		log.Info("Adding containerlab host entries to /etc/hosts file")
+		if err := clab.AppendHostsFileEntries(containers, c.Config.Name); err != nil {
+			log.Errorf("failed to create hosts file: %v", err)
+		}
+		printContainerInspect(c, containers, c.Config.Mgmt.Network, format)
+		return nil
+	},
+}
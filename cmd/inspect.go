@@ -8,6 +8,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
@@ -17,26 +18,87 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/nodes"
 	"github.com/srl-labs/containerlab/runtime"
 	"github.com/srl-labs/containerlab/types"
+	"gopkg.in/yaml.v2"
 )
 
 var format string
 var details bool
+var inspectSummary bool
 var all bool
 
+// fields of the container details to output, all fields when empty
+var inspectFieldNames []string
+
 type containerDetails struct {
-	LabName     string `json:"lab_name,omitempty"`
-	LabPath     string `json:"labPath,omitempty"`
-	Name        string `json:"name,omitempty"`
-	ContainerID string `json:"container_id,omitempty"`
-	Image       string `json:"image,omitempty"`
-	Kind        string `json:"kind,omitempty"`
-	Group       string `json:"group,omitempty"`
-	State       string `json:"state,omitempty"`
-	IPv4Address string `json:"ipv4_address,omitempty"`
-	IPv6Address string `json:"ipv6_address,omitempty"`
+	LabName     string   `json:"lab_name,omitempty" yaml:"lab_name,omitempty"`
+	LabPath     string   `json:"labPath,omitempty" yaml:"labPath,omitempty"`
+	Name        string   `json:"name,omitempty" yaml:"name,omitempty"`
+	ContainerID string   `json:"container_id,omitempty" yaml:"container_id,omitempty"`
+	Image       string   `json:"image,omitempty" yaml:"image,omitempty"`
+	Kind        string   `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Group       string   `json:"group,omitempty" yaml:"group,omitempty"`
+	State       string   `json:"state,omitempty" yaml:"state,omitempty"`
+	Health      string   `json:"health,omitempty" yaml:"health,omitempty"`
+	IPv4Address string   `json:"ipv4_address,omitempty" yaml:"ipv4_address,omitempty"`
+	IPv6Address string   `json:"ipv6_address,omitempty" yaml:"ipv6_address,omitempty"`
+	Ports       []string `json:"ports,omitempty" yaml:"ports,omitempty"`
+	Access      []string `json:"access,omitempty" yaml:"access,omitempty"`
+}
+
+// inspectField is a field of the container details selectable with the --fields flag
+type inspectField struct {
+	// name of the field, the same as its json key
+	name   string
+	header string
+	value  func(d *containerDetails) interface{}
+}
+
+// inspectFields are the selectable fields in the order of the wide table columns
+var inspectFields = []*inspectField{
+	{"lab_name", "Lab Name", func(d *containerDetails) interface{} { return d.LabName }},
+	{"labPath", "Topo Path", func(d *containerDetails) interface{} { return d.LabPath }},
+	{"name", "Name", func(d *containerDetails) interface{} { return d.Name }},
+	{"container_id", "Container ID", func(d *containerDetails) interface{} { return d.ContainerID }},
+	{"image", "Image", func(d *containerDetails) interface{} { return d.Image }},
+	{"kind", "Kind", func(d *containerDetails) interface{} { return d.Kind }},
+	{"group", "Group", func(d *containerDetails) interface{} { return d.Group }},
+	{"state", "State", func(d *containerDetails) interface{} { return d.State }},
+	{"health", "Health", func(d *containerDetails) interface{} { return d.Health }},
+	{"ipv4_address", "IPv4 Address", func(d *containerDetails) interface{} { return d.IPv4Address }},
+	{"ipv6_address", "IPv6 Address", func(d *containerDetails) interface{} { return d.IPv6Address }},
+	{"ports", "Ports", func(d *containerDetails) interface{} { return append([]string{}, d.Ports...) }},
+	{"access", "Access", func(d *containerDetails) interface{} { return append([]string{}, d.Access...) }},
+}
+
+// selectInspectFields returns the fields of the names in the given order, all fields when no names are given
+func selectInspectFields(names []string) ([]*inspectField, error) {
+	if len(names) == 0 {
+		return inspectFields, nil
+	}
+	res := make([]*inspectField, 0, len(names))
+	for _, n := range names {
+		var f *inspectField
+		for _, ff := range inspectFields {
+			if ff.name == strings.TrimSpace(n) {
+				f = ff
+				break
+			}
+		}
+		if f == nil {
+			valid := make([]string, 0, len(inspectFields))
+			for _, ff := range inspectFields {
+				valid = append(valid, ff.name)
+			}
+			return nil, fmt.Errorf("unknown field %q, use any of [%s]", n, strings.Join(valid, ", "))
+		}
+		res = append(res, f)
+	}
+	return res, nil
 }
+
 type BridgeDetails struct{}
 
 // inspectCmd represents the inspect command
@@ -51,6 +113,17 @@ var inspectCmd = &cobra.Command{
 			fmt.Println("provide either a lab name (--name) or a topology file path (--topo) or the flag --all")
 			return
 		}
+		switch format {
+		case "table", "wide", "json", "yaml", "ansible":
+		default:
+			log.Fatalf("unsupported output format %q, use one of [table, wide, json, yaml, ansible]", format)
+		}
+		if _, err := selectInspectFields(inspectFieldNames); err != nil {
+			log.Fatal(err)
+		}
+		if inspectSummary && all {
+			log.Fatal("the lab summary is generated for a single lab, --summary can't be used with --all")
+		}
 		opts := []clab.ClabOption{
 			clab.WithTimeout(timeout),
 			clab.WithRuntime(rt,
@@ -58,11 +131,12 @@ var inspectCmd = &cobra.Command{
 					Debug:            debug,
 					Timeout:          timeout,
 					GracefulShutdown: graceful,
+					Host:             host,
 				},
 			),
 		}
 		if topo != "" {
-			opts = append(opts, clab.WithTopoFile(topo))
+			opts = append(opts, clab.WithTopoVars(topoVars), clab.WithTopoFile(topo), clab.WithLabDirPath(labDirPath))
 		}
 		c, err := clab.NewContainerLab(opts...)
 		if err != nil {
@@ -89,6 +163,14 @@ var inspectCmd = &cobra.Command{
 		if err != nil {
 			log.Fatalf("failed to list containers: %s", err)
 		}
+		// the containers imported into the lab are listed by the lab directory of the topology
+		if topo != "" && !all {
+			imported, err := c.ListImportedContainers(ctx)
+			if err != nil {
+				log.Fatalf("failed to list imported containers: %s", err)
+			}
+			containers = append(containers, imported...)
+		}
 
 		if len(containers) == 0 {
 			log.Println("no containers found")
@@ -102,6 +184,18 @@ var inspectCmd = &cobra.Command{
 			fmt.Println(string(b))
 			return
 		}
+		if inspectSummary {
+			if err := inspectLabSummary(ctx, c, opts, containers); err != nil {
+				log.Fatalf("failed to regenerate the lab summary: %v", err)
+			}
+			return
+		}
+		if format == "ansible" {
+			if err := c.WriteAnsibleInventory(os.Stdout, containers); err != nil {
+				log.Fatalf("failed to write ansible inventory: %v", err)
+			}
+			return
+		}
 		printContainerInspect(c, containers, c.Config.Mgmt.Network, format)
 	},
 }
@@ -110,18 +204,63 @@ func init() {
 	rootCmd.AddCommand(inspectCmd)
 
 	inspectCmd.Flags().BoolVarP(&details, "details", "", false, "print all details of lab containers")
-	inspectCmd.Flags().StringVarP(&format, "format", "f", "table", "output format. One of [table, json]")
+	inspectCmd.Flags().BoolVarP(&inspectSummary, "summary", "", false, "regenerate the lab summary from the running containers, print it and rewrite the lab-summary.json file of the lab directory")
+	inspectCmd.Flags().StringVarP(&format, "format", "f", "table", "output format. One of [table, wide, json, yaml, ansible]")
+	inspectCmd.Flags().StringSliceVarP(&inspectFieldNames, "fields", "", []string{}, "comma separated list of the fields to output, e.g. name,kind,state,ipv4_address")
 	inspectCmd.Flags().BoolVarP(&all, "all", "a", false, "show all deployed containerlab labs")
 }
 
-func toTableData(det []containerDetails) [][]string {
+// inspectLabSummary regenerates the lab summary from the lab containers, prints it and saves it to the lab directory.
+// When the lab is inspected by name, the topology is read from the topology file the lab was deployed with
+func inspectLabSummary(ctx context.Context, c *clab.CLab, opts []clab.ClabOption, containers []types.GenericContainer) error {
+	if topo == "" {
+		labs := clab.DiscoverLabs(containers)
+		if len(labs) == 0 {
+			return fmt.Errorf("no containerlab labs were found")
+		}
+		l := labs[0]
+		opts = append(opts, clab.WithTopoVars(topoVars), clab.WithTopoFile(l.TopoFile))
+		if l.LabDir != "" {
+			opts = append(opts, clab.WithLabDirPath(filepath.Dir(l.LabDir)))
+		}
+		var err error
+		if c, err = clab.NewContainerLab(opts...); err != nil {
+			return err
+		}
+	}
+	s, err := c.LabSummary(ctx, containers)
+	if err != nil {
+		return err
+	}
+	p, err := c.SaveLabSummary(s)
+	if err != nil {
+		return fmt.Errorf("failed to save lab summary: %v", err)
+	}
+	log.Infof("Lab topology summary written to %s", p)
+	if format == "json" {
+		return clab.WriteLabSummary(os.Stdout, s)
+	}
+	printAccessSummary(c.AccessSummary(containers), s)
+	return nil
+}
+
+func toTableData(det []containerDetails, withHealth, withPorts bool) [][]string {
 	tabData := make([][]string, 0, len(det))
 	for i, d := range det {
+		var row []string
 		if all {
-			tabData = append(tabData, []string{fmt.Sprintf("%d", i+1), d.LabPath, d.LabName, d.Name, d.ContainerID, d.Image, d.Kind, d.Group, d.State, d.IPv4Address, d.IPv6Address})
-			continue
+			row = []string{fmt.Sprintf("%d", i+1), d.LabPath, d.LabName, d.Name, d.ContainerID, d.Image, d.Kind, d.Group, d.State}
+		} else {
+			row = []string{fmt.Sprintf("%d", i+1), d.Name, d.ContainerID, d.Image, d.Kind, d.Group, d.State}
+		}
+		if withHealth {
+			row = append(row, d.Health)
 		}
-		tabData = append(tabData, []string{fmt.Sprintf("%d", i+1), d.Name, d.ContainerID, d.Image, d.Kind, d.Group, d.State, d.IPv4Address, d.IPv6Address})
+		row = append(row, d.IPv4Address, d.IPv6Address)
+		if withPorts {
+			row = append(row, strings.Join(d.Ports, "\n"), strings.Join(d.Access, "\n"))
+		}
+		tabData = append(tabData, row)
 	}
 	return tabData
 }
@@ -131,6 +270,12 @@ func printContainerInspect(c *clab.CLab, containers []types.GenericContainer, br
 	// do not print published ports unless mysocketio kind is found
 	printMysocket := false
 	var mysocketCID string
+	// ports column is only displayed when at least one container has published ports
+	printPorts := false
+	// health column is only displayed when the lab has vrnetlab nodes
+	printHealth := false
+	// address of the container host the published ports are reached with
+	publishAddr := clab.PublishAddress(c.GlobalRuntime())
 
 	for _, cont := range containers {
 		// get topo file path relative of the cwd
@@ -160,6 +305,15 @@ func printContainerInspect(c *clab.CLab, containers []types.GenericContainer, br
 		if group, ok := cont.Labels["clab-node-group"]; ok {
 			cdet.Group = group
 		}
+		if nodes.IsVrKind(cdet.Kind) {
+			printHealth = true
+			cdet.Health = getVrHealth(c, cont)
+		}
+		cdet.Ports = getContainerPorts(cont)
+		cdet.Access = clab.AccessURLs(cont, publishAddr)
+		if len(cdet.Ports) > 0 {
+			printPorts = true
+		}
 		contDetails = append(contDetails, cdet)
 	}
 	sort.Slice(contDetails, func(i, j int) bool {
@@ -168,7 +322,7 @@ func printContainerInspect(c *clab.CLab, containers []types.GenericContainer, br
 		}
 		return contDetails[i].LabName < contDetails[j].LabName
 	})
-	if format == "json" {
+	if format == "json" && len(inspectFieldNames) == 0 {
 		b, err := json.MarshalIndent(contDetails, "", "  ")
 		if err != nil {
 			log.Fatalf("failed to marshal container details: %v", err)
@@ -176,7 +330,17 @@ func printContainerInspect(c *clab.CLab, containers []types.GenericContainer, br
 		fmt.Println(string(b))
 		return
 	}
-	tabData := toTableData(contDetails)
+	if format == "json" || format == "yaml" || format == "wide" || len(inspectFieldNames) > 0 {
+		fields, err := selectInspectFields(inspectFieldNames)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := printInspectFields(os.Stdout, contDetails, fields, format); err != nil {
+			log.Fatalf("failed to print container details: %v", err)
+		}
+		return
+	}
+	tabData := toTableData(contDetails, printHealth, printPorts)
 	table := tablewriter.NewWriter(os.Stdout)
 	header := []string{
 		"Lab Name",
@@ -186,8 +350,13 @@ func printContainerInspect(c *clab.CLab, containers []types.GenericContainer, br
 		"Kind",
 		"Group",
 		"State",
-		"IPv4 Address",
-		"IPv6 Address",
+	}
+	if printHealth {
+		header = append(header, "Health")
+	}
+	header = append(header, "IPv4 Address", "IPv6 Address")
+	if printPorts {
+		header = append(header, "Ports", "Access")
 	}
 	if all {
 		table.SetHeader(append([]string{"#", "Topo Path"}, header...))
@@ -219,6 +388,79 @@ func printContainerInspect(c *clab.CLab, containers []types.GenericContainer, br
 	fmt.Println(string(stdout))
 }
 
+// printInspectFields prints the fields of the container details in the format,
+// a table with a column per field unless the format is json or yaml
+func printInspectFields(w io.Writer, det []containerDetails, fields []*inspectField, format string) error {
+	switch format {
+	case "json":
+		res := make([]map[string]interface{}, 0, len(det))
+		for i := range det {
+			m := make(map[string]interface{}, len(fields))
+			for _, f := range fields {
+				m[f.name] = f.value(&det[i])
+			}
+			res = append(res, m)
+		}
+		b, err := json.MarshalIndent(res, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	case "yaml":
+		res := make([]yaml.MapSlice, 0, len(det))
+		for i := range det {
+			m := make(yaml.MapSlice, 0, len(fields))
+			for _, f := range fields {
+				m = append(m, yaml.MapItem{Key: f.name, Value: f.value(&det[i])})
+			}
+			res = append(res, m)
+		}
+		b, err := yaml.Marshal(res)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(b)
+		return err
+	}
+	table := tablewriter.NewWriter(w)
+	header := []string{"#"}
+	for _, f := range fields {
+		header = append(header, f.header)
+	}
+	table.SetHeader(header)
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+	for i := range det {
+		row := []string{fmt.Sprintf("%d", i+1)}
+		for _, f := range fields {
+			switch v := f.value(&det[i]).(type) {
+			case []string:
+				row = append(row, strings.Join(v, "\n"))
+			default:
+				row = append(row, fmt.Sprint(v))
+			}
+		}
+		table.Append(row)
+	}
+	table.Render()
+	return nil
+}
+
+// getVrHealth returns the health state of the VM running in the vrnetlab container
+func getVrHealth(c *clab.CLab, ctr types.GenericContainer) string {
+	if ctr.State != "running" {
+		return ""
+	}
+	state, msg, err := nodes.VrHealth(context.Background(), c.GlobalRuntime(), ctr.ID)
+	if err != nil {
+		log.Debugf("failed to run vrnetlab healthcheck in container %s: %v", ctr.ID, err)
+		return "unknown"
+	}
+	log.Debugf("vrnetlab healthcheck of container %s: %s", ctr.ID, msg)
+	return state
+}
+
 func getContainerIPv4(ctr types.GenericContainer, bridgeName string) string {
 	if !ctr.NetworkSettings.Set {
 		return ""
@@ -243,3 +485,21 @@ func getContainerIPv6(ctr types.GenericContainer, bridgeName string) string {
 
 	return fmt.Sprintf("%s/%d", ctr.NetworkSettings.IPv6addr, ctr.NetworkSettings.IPv6pLen)
 }
+
+// getContainerPorts returns a sorted list of host port bindings of a container
+// in the docker notation, IPv4 bindings are listed before IPv6 ones
+func getContainerPorts(ctr types.GenericContainer) []string {
+	ports := make([]string, 0, len(ctr.Ports))
+	for _, p := range ctr.Ports {
+		ports = append(ports, p.String())
+	}
+	sort.Slice(ports, func(i, j int) bool {
+		iv6 := strings.HasPrefix(ports[i], "[")
+		jv6 := strings.HasPrefix(ports[j], "[")
+		if iv6 != jv6 {
+			return jv6
+		}
+		return ports[i] < ports[j]
+	})
+	return ports
+}
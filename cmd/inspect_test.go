@@ -0,0 +1,140 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestSelectInspectFields(t *testing.T) {
+	fields, err := selectInspectFields([]string{"name", " ipv4_address", "kind"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range fields {
+		got = append(got, f.name)
+	}
+	if d := cmp.Diff([]string{"name", "ipv4_address", "kind"}, got); d != "" {
+		t.Errorf("diff (-want +got):\n%s", d)
+	}
+
+	if fields, _ := selectInspectFields(nil); len(fields) != len(inspectFields) {
+		t.Errorf("got %d fields, want all %d fields", len(fields), len(inspectFields))
+	}
+	if _, err := selectInspectFields([]string{"name", "uptime"}); err == nil {
+		t.Error("expected an error for the unknown field")
+	}
+}
+
+func TestPrintInspectFields(t *testing.T) {
+	det := []containerDetails{
+		{LabName: "srl02", Name: "clab-srl02-srl1", Kind: "srl", IPv4Address: "172.20.20.2/24", Ports: []string{"0.0.0.0:8080->80/tcp"}},
+		{LabName: "srl02", Name: "clab-srl02-srl2", Kind: "srl", IPv4Address: "172.20.20.3/24"},
+	}
+	fields, err := selectInspectFields([]string{"name", "ipv4_address", "ports"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]string{
+		"yaml": `- name: clab-srl02-srl1
+  ipv4_address: 172.20.20.2/24
+  ports:
+  - 0.0.0.0:8080->80/tcp
+- name: clab-srl02-srl2
+  ipv4_address: 172.20.20.3/24
+  ports: []
+`,
+		"json": `[
+  {
+    "ipv4_address": "172.20.20.2/24",
+    "name": "clab-srl02-srl1",
+    "ports": [
+      "0.0.0.0:8080-\u003e80/tcp"
+    ]
+  },
+  {
+    "ipv4_address": "172.20.20.3/24",
+    "name": "clab-srl02-srl2",
+    "ports": []
+  }
+]
+`,
+	}
+	for format, want := range tests {
+		t.Run(format, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := printInspectFields(&b, det, fields, format); err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff(want, b.String()); d != "" {
+				t.Errorf("diff (-want +got):\n%s", d)
+			}
+		})
+	}
+
+	t.Run("table", func(t *testing.T) {
+		var b bytes.Buffer
+		if err := printInspectFields(&b, det, fields, "wide"); err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(b.String(), "\n")
+		if !strings.Contains(lines[1], "Name") || !strings.Contains(lines[1], "IPv4 Address") || !strings.Contains(lines[1], "Ports") {
+			t.Errorf("unexpected table header %q", lines[1])
+		}
+		if strings.Contains(lines[1], "Kind") {
+			t.Errorf("unselected field in table header %q", lines[1])
+		}
+	})
+}
+
+func TestGetContainerPorts(t *testing.T) {
+	tests := map[string]struct {
+		ports []*types.GenericPortBinding
+		want  []string
+	}{
+		"no_ports": {
+			want: []string{},
+		},
+		"ipv4": {
+			ports: []*types.GenericPortBinding{
+				{HostIP: "0.0.0.0", HostPort: 8080, ContainerPort: 80, Protocol: "tcp"},
+			},
+			want: []string{"0.0.0.0:8080->80/tcp"},
+		},
+		"dual_stack_ipv4_first": {
+			ports: []*types.GenericPortBinding{
+				{HostIP: "::", HostPort: 8080, ContainerPort: 80, Protocol: "tcp"},
+				{HostIP: "0.0.0.0", HostPort: 8080, ContainerPort: 80, Protocol: "tcp"},
+			},
+			want: []string{"0.0.0.0:8080->80/tcp", "[::]:8080->80/tcp"},
+		},
+		"sorted_bindings": {
+			ports: []*types.GenericPortBinding{
+				{HostIP: "2001:db8::1", HostPort: 2222, ContainerPort: 22, Protocol: "tcp"},
+				{HostIP: "127.0.0.1", HostPort: 1161, ContainerPort: 161, Protocol: "udp"},
+				{HostIP: "0.0.0.0", HostPort: 2222, ContainerPort: 22, Protocol: "tcp"},
+			},
+			want: []string{
+				"0.0.0.0:2222->22/tcp",
+				"127.0.0.1:1161->161/udp",
+				"[2001:db8::1]:2222->22/tcp",
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := getContainerPorts(types.GenericContainer{Ports: tc.ports})
+			if d := cmp.Diff(tc.want, got); d != "" {
+				t.Errorf("ports mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
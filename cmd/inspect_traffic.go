@@ -0,0 +1,146 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+// interval the link counters are sampled with, the averages since deploy are reported when not set
+var trafficInterval time.Duration
+
+func init() {
+	inspectCmd.AddCommand(inspectTrafficCmd)
+	inspectTrafficCmd.Flags().StringVarP(&format, "format", "f", "table", "output format. One of [table, json]")
+	inspectTrafficCmd.Flags().DurationVarP(&trafficInterval, "interval", "i", 0, "sample the link counters periodically with the interval and report the traffic of every interval, e.g. 5s")
+}
+
+// inspectTrafficCmd represents the inspect traffic command
+var inspectTrafficCmd = &cobra.Command{
+	Use:   "traffic",
+	Short: "show the traffic of the lab links",
+	Long: `show the bytes, packets and throughput of the lab links in both directions, sampled from the interface counters of the link endpoints.
+The average throughput since the deployment is reported unless the sampling interval is set
+reference: https://containerlab.srlinux.dev/cmd/inspect/traffic/`,
+	PreRunE: sudoCheck,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if topo == "" {
+			return fmt.Errorf("provide topology file path with --topo flag")
+		}
+		if format != "table" && format != "json" {
+			return fmt.Errorf("unsupported output format %q, use one of [table, json]", format)
+		}
+		c, err := clab.NewContainerLab(
+			clab.WithTimeout(timeout),
+			clab.WithTopoVars(topoVars),
+			clab.WithTopoFile(topo),
+			clab.WithLabDirPath(labDirPath),
+			clab.WithRuntime(rt,
+				&runtime.RuntimeConfig{
+					Debug:   debug,
+					Timeout: timeout,
+					Host:    host,
+				},
+			),
+		)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		prev, err := c.LinksTraffic(ctx)
+		if err != nil {
+			return err
+		}
+		if trafficInterval <= 0 {
+			return printLinksTraffic(prev)
+		}
+
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+		tick := time.NewTicker(trafficInterval)
+		defer tick.Stop()
+		for {
+			select {
+			case <-sig:
+				return nil
+			case <-tick.C:
+			}
+			cur, err := c.LinksTraffic(ctx)
+			if err != nil {
+				return err
+			}
+			diff := make([]*clab.LinkTraffic, len(cur))
+			for i := range cur {
+				diff[i] = cur[i].Sub(prev[i])
+			}
+			if format == "table" {
+				fmt.Println(time.Now().Format(time.RFC3339))
+			}
+			if err := printLinksTraffic(diff); err != nil {
+				return err
+			}
+			prev = cur
+		}
+	},
+}
+
+func printLinksTraffic(traffic []*clab.LinkTraffic) error {
+	if format == "json" {
+		type linkTrafficRates struct {
+			*clab.LinkTraffic
+			RateAB float64 `json:"rate_a_b_bps"`
+			RateBA float64 `json:"rate_b_a_bps"`
+		}
+		res := make([]linkTrafficRates, 0, len(traffic))
+		for _, t := range traffic {
+			res = append(res, linkTrafficRates{LinkTraffic: t, RateAB: t.RateAB(), RateBA: t.RateBA()})
+		}
+		b, err := json.Marshal(res)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+		return nil
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"A", "B", "A->B bytes", "A->B packets", "A->B rate", "B->A bytes", "B->A packets", "B->A rate", "Interval"})
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+	for _, t := range traffic {
+		table.Append([]string{
+			t.A, t.B,
+			units.HumanSize(float64(t.BytesAB)), fmt.Sprint(t.PacketsAB), formatBitRate(t.RateAB()),
+			units.HumanSize(float64(t.BytesBA)), fmt.Sprint(t.PacketsBA), formatBitRate(t.RateBA()),
+			t.Elapsed.Round(time.Second).String(),
+		})
+	}
+	table.Render()
+	return nil
+}
+
+// formatBitRate formats the rate in bits per second with the decimal unit prefixes
+func formatBitRate(bps float64) string {
+	for _, u := range []string{"bps", "kbps", "Mbps", "Gbps"} {
+		if bps < 1000 {
+			return fmt.Sprintf("%.1f %s", bps, u)
+		}
+		bps /= 1000
+	}
+	return fmt.Sprintf("%.1f Tbps", bps)
+}
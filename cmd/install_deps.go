@@ -0,0 +1,99 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+var (
+	depsDryRun bool
+	depsUndo   bool
+)
+
+// installDepsCmd represents the install-deps command
+var installDepsCmd = &cobra.Command{
+	Use:   "install-deps",
+	Short: "prepare the container host for a lab",
+	Long: `load the kernel modules and raise the kernel parameters, e.g. inotify limits or hugepages, needed by the lab nodes.
+The changes are recorded, so that they can be undone with --undo
+reference: https://containerlab.srlinux.dev/cmd/install-deps/`,
+	PreRunE: sudoCheck,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if topo == "" {
+			return fmt.Errorf("provide topology file path with --topo flag")
+		}
+		c, err := clab.NewContainerLab(
+			clab.WithTimeout(timeout),
+			clab.WithTopoVars(topoVars),
+			clab.WithTopoFile(topo),
+			clab.WithLabDirPath(labDirPath),
+			clab.WithRuntime(rt,
+				&runtime.RuntimeConfig{
+					Debug:   debug,
+					Timeout: timeout,
+					Host:    host,
+				},
+			),
+		)
+		if err != nil {
+			return err
+		}
+
+		if depsUndo {
+			reverted, err := c.UndoDeps()
+			printHostChanges(reverted, true)
+			return err
+		}
+
+		changes, err := c.HostDeps()
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			log.Info("The container host has all the dependencies of the lab")
+			return nil
+		}
+		if depsDryRun {
+			printHostChanges(changes, false)
+			return nil
+		}
+		applied, err := c.InstallDeps(changes)
+		printHostChanges(applied, false)
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(installDepsCmd)
+	installDepsCmd.Flags().BoolVarP(&depsDryRun, "dry-run", "", false, "print the changes without applying them")
+	installDepsCmd.Flags().BoolVarP(&depsUndo, "undo", "", false, "revert the changes made for the lab")
+}
+
+// printHostChanges prints the host changes, the reverted changes are printed with their states swapped
+func printHostChanges(changes []*clab.HostChange, reverted bool) {
+	if len(changes) == 0 {
+		return
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Type", "Name", "From", "To"})
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+	for _, h := range changes {
+		from, to := h.From, h.To
+		if reverted {
+			from, to = to, from
+		}
+		table.Append([]string{h.Type, h.Name, from, to})
+	}
+	table.Render()
+}
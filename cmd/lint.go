@@ -0,0 +1,84 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+)
+
+var (
+	// output format of the lint findings
+	lintFormat string
+	// minimal severity of the findings failing the lint
+	lintFailOn string
+)
+
+// lintCmd represents the lint command
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "lint topology file",
+	Long:  "check the topology file for the issues beyond the schema violations, such as unused kinds, duplicate endpoints and untagged images\nreference: https://containerlab.srlinux.dev/cmd/lint/",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if topo == "" {
+			return fmt.Errorf("provide topology file path with --topo flag")
+		}
+		if lintFormat != "table" && lintFormat != "json" {
+			return fmt.Errorf("unsupported output format %q, use one of [table, json]", lintFormat)
+		}
+		if !clab.IsLintSeverity(lintFailOn) {
+			return fmt.Errorf("unsupported severity %q, use one of [error, warning, info]", lintFailOn)
+		}
+		findings, err := clab.LintTopology(topo, clab.WithTopoVars(topoVars))
+		if err != nil {
+			return err
+		}
+		if err := printLintFindings(findings, lintFormat); err != nil {
+			return err
+		}
+		if clab.LintFailed(findings, lintFailOn) {
+			return fmt.Errorf("topology has findings with %s or higher severity", lintFailOn)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+	lintCmd.Flags().StringVarP(&lintFormat, "format", "f", "table", "output format. One of [table, json]")
+	lintCmd.Flags().StringVarP(&lintFailOn, "fail-on", "", clab.LintError, "minimal severity of the findings failing the lint. One of [error, warning, info]")
+}
+
+func printLintFindings(findings []*clab.LintFinding, format string) error {
+	if format == "json" {
+		if findings == nil {
+			findings = []*clab.LintFinding{}
+		}
+		b, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal lint findings: %v", err)
+		}
+		fmt.Println(string(b))
+		return nil
+	}
+	if len(findings) == 0 {
+		fmt.Println("no issues found")
+		return nil
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Severity", "Rule", "Node", "Message"})
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+	for _, f := range findings {
+		table.Append([]string{f.Severity, f.Rule, f.Node, f.Message})
+	}
+	table.Render()
+	return nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+	listCmd.Flags().StringVarP(&format, "format", "f", "table", "output format. One of [table, json]")
+}
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "list deployed labs",
+	Long:    "list the labs deployed on the container host with their topology files, node counts and age\nreference: https://containerlab.srlinux.dev/cmd/list/",
+	Aliases: []string{"ls"},
+	PreRunE: sudoCheck,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if format != "table" && format != "json" {
+			return fmt.Errorf("unsupported output format %q, use one of [table, json]", format)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		labs, err := deployedLabs(ctx)
+		if err != nil {
+			return err
+		}
+		if format == "json" {
+			b, err := json.MarshalIndent(labs, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+			return nil
+		}
+		if len(labs) == 0 {
+			fmt.Println("no containerlab labs were found")
+			return nil
+		}
+		printLabs(os.Stdout, labs, time.Now())
+		return nil
+	},
+}
+
+// deployedLabs returns the labs deployed on the container host discovered by the labels of their containers
+func deployedLabs(ctx context.Context) ([]*clab.LabInfo, error) {
+	c, err := clab.NewContainerLab(
+		clab.WithTimeout(timeout),
+		clab.WithRuntime(rt,
+			&runtime.RuntimeConfig{
+				Debug:   debug,
+				Timeout: timeout,
+				Host:    host,
+			},
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+	labels := []*types.GenericFilter{{FilterType: "label", Field: "containerlab", Operator: "exists"}}
+	containers, err := c.ListContainers(ctx, labels)
+	if err != nil {
+		return nil, err
+	}
+	return clab.DiscoverLabs(containers), nil
+}
+
+// printLabs prints the table of the labs with their age at the given time
+func printLabs(w io.Writer, labs []*clab.LabInfo, now time.Time) {
+	cwd, _ := os.Getwd()
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"#", "Lab Name", "Topo Path", "Nodes", "Running", "Age"})
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+	for i, l := range labs {
+		path := l.TopoFile
+		if rel, err := filepath.Rel(cwd, path); err == nil && path != "" {
+			path = rel
+		}
+		table.Append([]string{
+			fmt.Sprintf("%d", i+1), l.Name, path,
+			fmt.Sprintf("%d", l.Nodes), fmt.Sprintf("%d", l.Running), labAge(l.Created, now),
+		})
+	}
+	table.Render()
+}
+
+// labAge returns the age of the lab created at the given time in the two largest units, e.g. 3d4h or 25m
+func labAge(created, now time.Time) string {
+	if created.IsZero() {
+		return "-"
+	}
+	d := now.Sub(created)
+	switch {
+	case d < time.Minute:
+		return "<1m"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
+
+// confirmDestroy lists the labs to be destroyed and asks the user to confirm the deletion
+func confirmDestroy(labs []*clab.LabInfo, in io.Reader, out io.Writer) bool {
+	fmt.Fprintf(out, "The following %d labs will be destroyed:\n", len(labs))
+	for _, l := range labs {
+		fmt.Fprintf(out, "  %s (%d nodes, %s)\n", l.Name, l.Nodes, l.TopoFile)
+	}
+	fmt.Fprint(out, "Proceed? [y/N]: ")
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
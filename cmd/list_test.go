@@ -0,0 +1,52 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/srl-labs/containerlab/clab"
+)
+
+func TestLabAge(t *testing.T) {
+	now := time.Date(2021, 6, 3, 12, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		created time.Time
+		want    string
+	}{
+		"unknown":  {want: "-"},
+		"seconds":  {created: now.Add(-30 * time.Second), want: "<1m"},
+		"minutes":  {created: now.Add(-25 * time.Minute), want: "25m"},
+		"hours":    {created: now.Add(-(2*time.Hour + 15*time.Minute)), want: "2h15m"},
+		"days":     {created: now.Add(-(27*time.Hour + 40*time.Minute)), want: "1d3h"},
+		"in-month": {created: now.Add(-30 * 24 * time.Hour), want: "30d0h"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := labAge(tc.created, now); got != tc.want {
+				t.Errorf("labAge() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestConfirmDestroy(t *testing.T) {
+	labs := []*clab.LabInfo{{Name: "ws-team1", Nodes: 3, TopoFile: "/labs/team1.clab.yml"}}
+	tests := map[string]bool{
+		"y\n":   true,
+		"YES\n": true,
+		"n\n":   false,
+		"\n":    false,
+		"":      false,
+	}
+	for answer, want := range tests {
+		if got := confirmDestroy(labs, strings.NewReader(answer), ioutil.Discard); got != want {
+			t.Errorf("confirmDestroy(%q) = %v, want %v", answer, got, want)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/clab"
+)
+
+// address of the metrics endpoint of the serve and watch commands, the endpoint is disabled when empty
+var metricsListen string
+
+// serveMetrics serves the lab metrics on the /metrics path of the metrics address until ctx is canceled
+func serveMetrics(ctx context.Context, m *clab.LabMetrics) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m)
+	srv := &http.Server{Addr: metricsListen, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		sctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(sctx)
+	}()
+	go func() {
+		log.Infof("Serving lab metrics on %s/metrics", metricsListen)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("metrics server failed: %v", err)
+		}
+	}()
+}
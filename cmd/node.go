@@ -0,0 +1,168 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+var (
+	likeNode  string
+	newNode   string
+	nodeLinks []string
+	// image the node is upgraded to
+	upgradeImage string
+)
+
+func init() {
+	rootCmd.AddCommand(nodeCmd)
+	nodeCmd.AddCommand(nodeAddCmd)
+	nodeAddCmd.Flags().StringVarP(&likeNode, "like", "", "", "name of the lab node to clone the definition of")
+	nodeAddCmd.Flags().StringVarP(&newNode, "name", "", "", "name of the new node")
+	nodeAddCmd.Flags().StringSliceVarP(&nodeLinks, "links", "", []string{}, "comma separated list of the new node links in the format of <nodeA>:<ifaceA><-><nodeB>:<ifaceB>")
+	_ = nodeAddCmd.MarkFlagRequired("like")
+	_ = nodeAddCmd.MarkFlagRequired("name")
+	nodeCmd.AddCommand(nodeUpgradeCmd)
+	nodeUpgradeCmd.Flags().StringVarP(&upgradeImage, "image", "", "", "image to replace the node container image with")
+	_ = nodeUpgradeCmd.MarkFlagRequired("image")
+}
+
+// nodeCmd represents the node command
+var nodeCmd = &cobra.Command{
+	Use:   "node",
+	Short: "operations on the nodes of a deployed lab",
+}
+
+// nodeAddCmd represents the node add command
+var nodeAddCmd = &cobra.Command{
+	Use:   "add [lab-name]",
+	Short: "add a node cloned from an existing node to a deployed lab",
+	Long: `add deploys a new node with the definition of an existing lab node and wires its links without redeploying the rest of the lab.
+The lab is selected by the topology file path (--topo) or by the lab name passed as an argument
+reference: https://containerlab.srlinux.dev/cmd/node/add/`,
+	Args:    cobra.MaximumNArgs(1),
+	PreRunE: sudoCheck,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rtOpt := clab.WithRuntime(rt,
+			&runtime.RuntimeConfig{
+				Debug:            debug,
+				Timeout:          timeout,
+				GracefulShutdown: graceful,
+				Host:             host,
+			},
+		)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		topoFile := topo
+		if len(args) > 0 {
+			var err error
+			if topoFile, err = labTopoFile(ctx, args[0], rtOpt); err != nil {
+				return err
+			}
+		}
+		if topoFile == "" {
+			return fmt.Errorf("provide either a lab name or a topology file path with --topo flag")
+		}
+
+		links, err := clab.ParseLinks(nodeLinks)
+		if err != nil {
+			return err
+		}
+		c, err := clab.NewContainerLab(
+			clab.WithTimeout(timeout),
+			clab.WithTopoVars(topoVars),
+			clab.WithTopoFile(topoFile),
+			clab.WithLabDirPath(labDirPath),
+			rtOpt,
+		)
+		if err != nil {
+			return err
+		}
+		if err := c.AddNode(ctx, newNode, likeNode, links); err != nil {
+			return err
+		}
+		log.Infof("Node %s added to lab %s", newNode, c.Config.Name)
+		return nil
+	},
+}
+
+// nodeUpgradeCmd represents the node upgrade command
+var nodeUpgradeCmd = &cobra.Command{
+	Use:   "upgrade [lab-name] node-name",
+	Short: "replace the image of a node of a deployed lab",
+	Long: `upgrade saves the configuration of a lab node, replaces its container with a container of the new image, rewires its links and waits for the node to become ready.
+The lab is selected by the topology file path (--topo) or by the lab name passed as the first argument
+reference: https://containerlab.srlinux.dev/cmd/node/upgrade/`,
+	Args:    cobra.RangeArgs(1, 2),
+	PreRunE: sudoCheck,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rtOpt := clab.WithRuntime(rt,
+			&runtime.RuntimeConfig{
+				Debug:            debug,
+				Timeout:          timeout,
+				GracefulShutdown: graceful,
+				Host:             host,
+			},
+		)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		topoFile := topo
+		node := args[len(args)-1]
+		if len(args) > 1 {
+			var err error
+			if topoFile, err = labTopoFile(ctx, args[0], rtOpt); err != nil {
+				return err
+			}
+		}
+		if topoFile == "" {
+			return fmt.Errorf("provide either a lab name or a topology file path with --topo flag")
+		}
+
+		c, err := clab.NewContainerLab(
+			clab.WithTimeout(timeout),
+			clab.WithTopoVars(topoVars),
+			clab.WithTopoFile(topoFile),
+			clab.WithLabDirPath(labDirPath),
+			rtOpt,
+		)
+		if err != nil {
+			return err
+		}
+		if err := c.UpgradeNode(ctx, node, upgradeImage); err != nil {
+			return err
+		}
+		log.Infof("Node %s of lab %s upgraded to %s", node, c.Config.Name, upgradeImage)
+		return nil
+	},
+}
+
+// labTopoFile returns the path to the topology file of the deployed lab
+func labTopoFile(ctx context.Context, lab string, opts ...clab.ClabOption) (string, error) {
+	c, err := clab.NewContainerLab(opts...)
+	if err != nil {
+		return "", err
+	}
+	labels := []*types.GenericFilter{{FilterType: "label", Match: lab, Field: "containerlab", Operator: "="}}
+	containers, err := c.ListContainers(ctx, labels)
+	if err != nil {
+		return "", err
+	}
+	for _, cont := range containers {
+		if t := cont.Labels[clab.TopoFileLabel]; t != "" {
+			return utils.LocalPath(t), nil
+		}
+	}
+	return "", fmt.Errorf("lab %q is not found", lab)
+}
@@ -0,0 +1,70 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+// save the state of the nodes to the disk and stop them
+var pauseCheckpoint bool
+
+// pauseCmd represents the pause command
+var pauseCmd = &cobra.Command{
+	Use:   "pause",
+	Short: "pause the nodes of a lab",
+	Long: `pause freezes the nodes of a deployed lab, with --checkpoint flag the state of the nodes is saved to the lab directory and the nodes are stopped.
+The lab is resumed where it left off with 'containerlab deploy --restore'
+reference: https://containerlab.srlinux.dev/cmd/pause/`,
+	PreRunE: sudoCheck,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if name == "" && topo == "" {
+			return fmt.Errorf("provide topology file path with --topo flag")
+		}
+		opts := []clab.ClabOption{
+			clab.WithTimeout(timeout),
+			clab.WithTopoVars(topoVars),
+			clab.WithTopoFile(topo),
+			clab.WithLabDirPath(labDirPath),
+			clab.WithRuntime(rt,
+				&runtime.RuntimeConfig{
+					Debug:            debug,
+					Timeout:          timeout,
+					GracefulShutdown: graceful,
+					Host:             host,
+				},
+			),
+		}
+		c, err := clab.NewContainerLab(opts...)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		paused, err := c.PauseNodes(ctx, pauseCheckpoint)
+		if len(paused) > 0 {
+			if pauseCheckpoint {
+				log.Infof("Nodes %s of lab %s checkpointed to %s", strings.Join(paused, ", "), c.Config.Name, c.CheckpointDir(""))
+			} else {
+				log.Infof("Nodes %s of lab %s paused", strings.Join(paused, ", "), c.Config.Name)
+			}
+		}
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pauseCmd)
+	pauseCmd.Flags().BoolVarP(&pauseCheckpoint, "checkpoint", "", false, "save the state of the nodes to the lab directory with CRIU and stop the nodes")
+}
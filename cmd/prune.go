@@ -0,0 +1,167 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/vishvananda/netlink"
+)
+
+var (
+	pruneDryRun bool
+	pruneDir    string
+)
+
+// pruneCmd represents the prune command
+var pruneCmd = &cobra.Command{
+	Use:     "prune",
+	Short:   "remove orphaned containerlab resources",
+	Long:    "remove containers, veth interfaces, netns symlinks, networks and lab directories left behind by failed deployments\nreference: https://containerlab.srlinux.dev/cmd/prune/",
+	PreRunE: sudoCheck,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// the resources of the labs being deployed look like the leftovers of the failed deployments
+		release, err := clab.PruneLock()
+		if err != nil {
+			if errors.Is(err, clab.ErrDeployInProgress) {
+				return fmt.Errorf("%v, run prune when it finishes", err)
+			}
+			return fmt.Errorf("failed to take the deploy lock: %v", err)
+		}
+		defer release()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		c, err := clab.NewContainerLab(
+			clab.WithTimeout(timeout),
+			clab.WithRuntime(rt,
+				&runtime.RuntimeConfig{
+					Debug:   debug,
+					Timeout: timeout,
+					Host:    host,
+				},
+			),
+		)
+		if err != nil {
+			return err
+		}
+
+		action := "Removing"
+		if pruneDryRun {
+			action = "Would remove"
+		}
+
+		var errs []error
+
+		// containers
+		labels := []*types.GenericFilter{{FilterType: "label", Field: "containerlab", Operator: "exists"}}
+		containers, err := c.ListContainers(ctx, labels)
+		if err != nil {
+			return err
+		}
+		activeLabs := clab.ActiveLabs(containers)
+		labDirsInUse := map[string]struct{}{}
+		orphaned := 0
+		for _, ctr := range containers {
+			name := strings.TrimLeft(ctr.Names[0], "/")
+			if !clab.IsOrphanedContainer(ctr, activeLabs) {
+				if dir := clab.LabDirFromLabels(ctr.Labels); dir != "" {
+					labDirsInUse[dir] = struct{}{}
+				}
+				continue
+			}
+			orphaned++
+			log.Infof("%s orphaned container: %s (lab %s, state %s)", action, name, ctr.Labels["containerlab"], ctr.State)
+			if pruneDryRun {
+				continue
+			}
+			if err := c.GlobalRuntime().DeleteContainer(ctx, name); err != nil {
+				errs = append(errs, fmt.Errorf("failed to remove container %s: %v", name, err))
+			}
+		}
+
+		// veth interfaces
+		veths, err := clab.DanglingVeths()
+		if err != nil {
+			return err
+		}
+		for _, l := range veths {
+			log.Infof("%s dangling veth interface: %s", action, l.Attrs().Name)
+			if pruneDryRun {
+				continue
+			}
+			if err := netlink.LinkDel(l); err != nil {
+				errs = append(errs, fmt.Errorf("failed to remove interface %s: %v", l.Attrs().Name, err))
+			}
+		}
+
+		// netns symlinks
+		symlinks, err := clab.StaleNetnsSymlinks()
+		if err != nil {
+			return err
+		}
+		for _, p := range symlinks {
+			log.Infof("%s stale netns symlink: %s", action, p)
+			if pruneDryRun {
+				continue
+			}
+			if err := os.Remove(p); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		// networks
+		nets, err := c.GlobalRuntime().PruneNets(ctx, pruneDryRun)
+		for _, n := range nets {
+			log.Infof("%s unused network: %s", action, n)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to prune networks: %v", err))
+		}
+
+		// lab directories
+		dirs, err := clab.StaleLabDirs(pruneDir, labDirsInUse)
+		if err != nil {
+			return err
+		}
+		for _, d := range dirs {
+			log.Infof("%s empty lab directory: %s", action, d)
+			if pruneDryRun {
+				continue
+			}
+			if err := os.RemoveAll(d); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		if orphaned == 0 && len(veths) == 0 && len(symlinks) == 0 && len(nets) == 0 && len(dirs) == 0 {
+			log.Info("no orphaned resources found")
+		}
+
+		if len(errs) != 0 {
+			for _, err := range errs {
+				log.Error(err)
+			}
+			return fmt.Errorf("error(s) occurred during pruning. Check log messages")
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pruneCmd)
+	pruneCmd.Flags().BoolVarP(&pruneDryRun, "dry-run", "", false, "list orphaned resources without removing them")
+	pruneCmd.Flags().StringVarP(&pruneDir, "dir", "", ".", "directory to look for lab directories in")
+}
@@ -0,0 +1,75 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+// name of the node to redeploy
+var redeployNode string
+
+func init() {
+	rootCmd.AddCommand(redeployCmd)
+	redeployCmd.Flags().StringVarP(&redeployNode, "node", "", "", "name of the lab node to redeploy")
+	_ = redeployCmd.MarkFlagRequired("node")
+}
+
+// redeployCmd represents the redeploy command
+var redeployCmd = &cobra.Command{
+	Use:   "redeploy [lab-name]",
+	Short: "redeploy a single node of a deployed lab",
+	Long: `redeploy destroys and recreates the container of a lab node, rewires its links to the peers and runs the deployment tasks of the node kind again, the rest of the lab is not touched.
+The lab is selected by the topology file path (--topo) or by the lab name passed as an argument
+reference: https://containerlab.srlinux.dev/cmd/redeploy/`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	PreRunE:      sudoCheck,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rtOpt := clab.WithRuntime(rt,
+			&runtime.RuntimeConfig{
+				Debug:            debug,
+				Timeout:          timeout,
+				GracefulShutdown: graceful,
+				Host:             host,
+			},
+		)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		topoFile := topo
+		if len(args) > 0 {
+			var err error
+			if topoFile, err = labTopoFile(ctx, args[0], rtOpt); err != nil {
+				return err
+			}
+		}
+		if topoFile == "" {
+			return fmt.Errorf("provide either a lab name or a topology file path with --topo flag")
+		}
+
+		c, err := clab.NewContainerLab(
+			clab.WithTimeout(timeout),
+			clab.WithTopoVars(topoVars),
+			clab.WithTopoFile(topoFile),
+			clab.WithLabDirPath(labDirPath),
+			rtOpt,
+		)
+		if err != nil {
+			return err
+		}
+		if err := c.RedeployNode(ctx, redeployNode); err != nil {
+			return err
+		}
+		log.Infof("Node %s of lab %s redeployed", redeployNode, c.Config.Name)
+		return nil
+	},
+}
@@ -0,0 +1,74 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/agent"
+)
+
+var (
+	remoteAgent    string
+	remoteInsecure bool
+)
+
+// remoteCmd represents the remote command
+var remoteCmd = &cobra.Command{
+	Use:   "remote [flags] <command> [command flags]",
+	Short: "run containerlab commands on a remote containerlab agent",
+	Long: `remote runs the containerlab commands on the Linux host running the containerlab agent,
+the topology file set with --topo flag is sent to the agent and the command output is streamed back.
+The 'proxy <host:port>' command connects the stdin/stdout to the TCP port of a lab node, e.g. to be used as SSH ProxyCommand
+reference: https://containerlab.srlinux.dev/cmd/remote/`,
+	Example: `containerlab remote --agent https://lab-server:50080 deploy -t srl02.clab.yml
+ssh -o ProxyCommand="containerlab remote --agent https://lab-server:50080 proxy %h:%p" admin@clab-srl02-srl1`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if remoteAgent == "" {
+			return errors.New("provide the agent URL with --agent flag or CLAB_AGENT env var")
+		}
+		c := &agent.Client{
+			URL:      remoteAgent,
+			Token:    os.Getenv(agentTokenEnv),
+			Insecure: remoteInsecure,
+		}
+		if args[0] == "proxy" {
+			if len(args) != 2 {
+				return errors.New("provide the address to proxy to as host:port")
+			}
+			return c.Proxy(args[1], struct {
+				io.Reader
+				io.Writer
+			}{os.Stdin, os.Stdout})
+		}
+		// the global flags set before the command are passed to the agent as well
+		if topo != "" {
+			args = append(args, "--topo", topo)
+		}
+		if name != "" {
+			args = append(args, "--name", name)
+		}
+		rc, err := c.Run(args, os.Stdout)
+		if err != nil {
+			return err
+		}
+		if rc != 0 {
+			os.Exit(rc)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(remoteCmd)
+	// the flags following the command are passed to the agent
+	remoteCmd.Flags().SetInterspersed(false)
+	remoteCmd.Flags().StringVarP(&remoteAgent, "agent", "", os.Getenv("CLAB_AGENT"), "URL of the containerlab agent, e.g. https://lab-server:50080")
+	remoteCmd.Flags().BoolVarP(&remoteInsecure, "insecure", "", false, "skip the verification of the agent TLS certificate")
+}
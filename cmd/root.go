@@ -6,11 +6,14 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/utils"
 )
 
 var debugCount int
@@ -19,21 +22,34 @@ var timeout time.Duration
 
 // path to the topology file
 var topo string
+
+// path to the file with the variables of the topology file template
+var topoVars string
 var graph bool
 var rt string
 
+// address of the container runtime daemon
+var host string
+
 // lab name
 var name string
 
+// path to the directory where lab directories are created
+var labDirPath string
+
+// path where the root filesystem of the container host is mounted when containerlab runs in a container
+var hostRoot string
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "containerlab",
 	Short: "deploy container based lab environments with a user-defined interconnections",
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		debug = debugCount > 0
 		if debug {
 			log.SetLevel(log.DebugLevel)
 		}
+		return setHostRoot()
 	},
 }
 
@@ -49,10 +65,38 @@ func init() {
 	rootCmd.SilenceUsage = true
 	rootCmd.PersistentFlags().CountVarP(&debugCount, "debug", "d", "enable debug mode")
 	rootCmd.PersistentFlags().StringVarP(&topo, "topo", "t", "", "path to the file with topology information")
-	_ = rootCmd.MarkPersistentFlagFilename("topo", "*.yaml", "*.yml")
+	_ = rootCmd.MarkPersistentFlagFilename("topo", "*.yaml", "*.yml", "*.json", "*.cue")
+	rootCmd.PersistentFlags().StringVarP(&topoVars, "vars", "", "", "path to the YAML or JSON file with the variables of the topology file template")
+	_ = rootCmd.MarkPersistentFlagFilename("vars", "*.yaml", "*.yml", "*.json")
 	rootCmd.PersistentFlags().StringVarP(&name, "name", "n", "", "lab name")
 	rootCmd.PersistentFlags().DurationVarP(&timeout, "timeout", "", 30*time.Second, "timeout for docker requests, e.g: 30s, 1m, 2m30s")
 	rootCmd.PersistentFlags().StringVarP(&rt, "runtime", "r", "", "container runtime")
+	rootCmd.PersistentFlags().StringVarP(&host, "host", "H", "", "container runtime daemon address, e.g. ssh://user@lab-server")
+	rootCmd.PersistentFlags().StringVarP(&labDirPath, "lab-dir-path", "", "", "path to the directory where the lab directory is created")
+	rootCmd.PersistentFlags().StringVarP(&hostRoot, "host-root", "", "", "path where the root filesystem of the container host is mounted when containerlab runs in a container")
+}
+
+// setHostRoot sets the host root used to map the paths when containerlab runs inside a container.
+// order of preference: cli flag -> env var
+func setHostRoot() error {
+	if hostRoot == "" {
+		hostRoot = os.Getenv("CLAB_HOST_ROOT")
+	}
+	if hostRoot != "" && runtime.IsRemoteHost(host) {
+		return fmt.Errorf("host root %s can't be used with the remote container runtime %s", hostRoot, host)
+	}
+	if err := utils.SetHostRoot(hostRoot); err != nil {
+		return fmt.Errorf("failed to set host root: %v", err)
+	}
+	if !utils.InContainer() {
+		return nil
+	}
+	if utils.HostRoot() != "" {
+		log.Debugf("containerlab runs in a container, host root is %s", utils.HostRoot())
+		return nil
+	}
+	log.Debug("containerlab runs in a container with no host root set, the lab directory and bind paths have to be mounted at the same paths as on the container host")
+	return nil
 }
 
 func sudoCheck(cmd *cobra.Command, args []string) error {
@@ -7,7 +7,13 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
 	"sync"
+	"syscall"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -16,6 +22,13 @@ import (
 	"github.com/srl-labs/containerlab/runtime"
 )
 
+var (
+	// interval of the periodic config save
+	saveWatch time.Duration
+	// print systemd unit running the periodic config save
+	saveSystemdUnit bool
+)
+
 // saveCmd represents the save command
 var saveCmd = &cobra.Command{
 	Use:   "save",
@@ -27,14 +40,28 @@ Refer to the https://containerlab.srlinux.dev/cmd/save/ documentation to see the
 		if name == "" && topo == "" {
 			return fmt.Errorf("provide topology file path  with --topo flag")
 		}
+		if saveSystemdUnit {
+			if saveWatch <= 0 || topo == "" {
+				return fmt.Errorf("--systemd-unit requires the topology file set with --topo flag and the save interval set with --watch flag")
+			}
+			unit, err := saveUnit(topo, saveWatch)
+			if err != nil {
+				return err
+			}
+			fmt.Print(unit)
+			return nil
+		}
 		opts := []clab.ClabOption{
 			clab.WithTimeout(timeout),
+			clab.WithTopoVars(topoVars),
 			clab.WithTopoFile(topo),
+			clab.WithLabDirPath(labDirPath),
 			clab.WithRuntime(rt,
 				&runtime.RuntimeConfig{
 					Debug:            debug,
 					Timeout:          timeout,
 					GracefulShutdown: graceful,
+					Host:             host,
 				},
 			),
 		}
@@ -46,24 +73,76 @@ Refer to the https://containerlab.srlinux.dev/cmd/save/ documentation to see the
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
 
-		var wg sync.WaitGroup
-		wg.Add(len(c.Nodes))
-		for _, node := range c.Nodes {
-			go func(node nodes.Node) {
-				defer wg.Done()
-
-				err := node.SaveConfig(ctx)
-				if err != nil {
-					log.Errorf("err: %v", err)
-				}
-			}(node)
+		saveConfigs(ctx, c)
+		if saveWatch <= 0 {
+			return nil
 		}
-		wg.Wait()
 
-		return nil
+		log.Infof("Saving configuration every %s, press Ctrl+C to stop", saveWatch)
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+		ticker := time.NewTicker(saveWatch)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				saveConfigs(ctx, c)
+			case s := <-sig:
+				log.Infof("Received %s, stopping the periodic configuration save", s)
+				return nil
+			}
+		}
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(saveCmd)
+	saveCmd.Flags().DurationVarP(&saveWatch, "watch", "", 0, "save the configuration periodically with the interval, e.g. 30m")
+	saveCmd.Flags().BoolVarP(&saveSystemdUnit, "systemd-unit", "", false, "print systemd unit running the periodic save with the --watch interval")
+}
+
+// saveConfigs saves the configuration of all lab nodes in parallel
+func saveConfigs(ctx context.Context, c *clab.CLab) {
+	var wg sync.WaitGroup
+	wg.Add(len(c.Nodes))
+	for _, node := range c.Nodes {
+		go func(node nodes.Node) {
+			defer wg.Done()
+
+			err := node.SaveConfig(ctx)
+			if err != nil {
+				log.Errorf("err: %v", err)
+			}
+		}(node)
+	}
+	wg.Wait()
+}
+
+// saveUnit returns the systemd unit running the periodic config save of the lab defined in topo file
+func saveUnit(topo string, interval time.Duration) (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	topo, err = filepath.Abs(topo)
+	if err != nil {
+		return "", err
+	}
+	return systemdSaveUnit(exe, topo, interval), nil
+}
+
+func systemdSaveUnit(exe, topo string, interval time.Duration) string {
+	lab := strings.TrimSuffix(filepath.Base(topo), filepath.Ext(topo))
+	return fmt.Sprintf(`[Unit]
+Description=containerlab periodic configuration save of %[1]s lab
+After=docker.service
+
+[Service]
+ExecStart=%[2]s save -t %[3]s --watch %[4]s
+Restart=on-failure
+RestartSec=30
+
+[Install]
+WantedBy=multi-user.target
+`, lab, exe, topo, interval)
 }
@@ -0,0 +1,31 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSystemdSaveUnit(t *testing.T) {
+	want := `[Unit]
+Description=containerlab periodic configuration save of srl02.clab lab
+After=docker.service
+
+[Service]
+ExecStart=/usr/bin/containerlab save -t /labs/srl02.clab.yml --watch 30m0s
+Restart=on-failure
+RestartSec=30
+
+[Install]
+WantedBy=multi-user.target
+`
+	got := systemdSaveUnit("/usr/bin/containerlab", "/labs/srl02.clab.yml", 30*time.Minute)
+	if got != want {
+		t.Errorf("diff (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/api"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/runner"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+// serveTokenEnv is the env var holding the bearer token of the API server
+const serveTokenEnv = "CLAB_API_TOKEN"
+
+var (
+	serveListen   string
+	serveStateDir string
+	serveTLSCert  string
+	serveTLSKey   string
+)
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "serve the HTTP and gRPC API managing the labs of this host",
+	Long: `serve runs the API server deploying, destroying and inspecting the labs and executing the commands on their nodes
+on behalf of the API clients, e.g. CI systems. The topologies and the states of the labs are kept in the state directory
+reference: https://containerlab.srlinux.dev/cmd/serve/`,
+	PreRunE: sudoCheck,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token := os.Getenv(serveTokenEnv)
+		if token == "" {
+			return errors.New("the API token is required to be set with " + serveTokenEnv + " env var")
+		}
+		store, err := api.NewStore(serveStateDir)
+		if err != nil {
+			return err
+		}
+		s, err := api.NewServer(store, &api.CLIBackend{Runner: &runner.Runner{GlobalArgs: serveGlobalArgs()}}, token)
+		if err != nil {
+			return err
+		}
+		srv := &http.Server{Addr: serveListen, Handler: s.Handler()}
+
+		ctx, cancelMetrics := context.WithCancel(context.Background())
+		defer cancelMetrics()
+		if metricsListen != "" {
+			c, err := clab.NewContainerLab(
+				clab.WithTimeout(timeout),
+				clab.WithRuntime(rt, &runtime.RuntimeConfig{Debug: debug, Timeout: timeout, Host: host}),
+			)
+			if err != nil {
+				return err
+			}
+			// the metrics of all labs of the host are exported, the labs are not required to be managed by the API
+			metrics := clab.NewLabMetrics(c, "")
+			go func() {
+				if err := metrics.Run(ctx); err != nil {
+					log.Errorf("failed to watch the lab nodes for metrics: %v", err)
+				}
+			}()
+			serveMetrics(ctx, metrics)
+		}
+
+		errCh := make(chan error, 1)
+		go func() {
+			log.Infof("Containerlab API server listening on %s, state directory %s", serveListen, store.Dir())
+			if serveTLSCert == "" {
+				log.Warn("API TLS certificate is not set, the API requests are not encrypted")
+				errCh <- srv.ListenAndServe()
+				return
+			}
+			errCh <- srv.ListenAndServeTLS(serveTLSCert, serveTLSKey)
+		}()
+
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+		select {
+		case err := <-errCh:
+			return err
+		case sg := <-sig:
+			log.Infof("Received %s, waiting for the running lab operations to finish", sg)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			log.Warnf("failed to shut down API server: %v", err)
+		}
+		s.Wait()
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.Flags().StringVarP(&serveListen, "listen", "", ":8080", "address the API server listens on")
+	serveCmd.Flags().StringVarP(&serveStateDir, "state-dir", "", "/var/lib/containerlab/api", "directory the topologies, the states and the lab directories of the labs are stored in")
+	serveCmd.Flags().StringVarP(&serveTLSCert, "tls-cert", "", "", "path to the TLS certificate of the API server")
+	serveCmd.Flags().StringVarP(&serveTLSKey, "tls-key", "", "", "path to the TLS key of the API server")
+	serveCmd.Flags().StringVarP(&metricsListen, "metrics-listen", "", "", "address to serve the Prometheus metrics of the lab nodes on, e.g. :9450")
+}
+
+// serveGlobalArgs returns the global flags of the serve command passed on to the lab commands of the API server
+func serveGlobalArgs() []string {
+	var args []string
+	if rt != "" {
+		args = append(args, "--runtime", rt)
+	}
+	if debug {
+		args = append(args, "--debug")
+	}
+	if timeout != 30*time.Second {
+		args = append(args, "--timeout", timeout.String())
+	}
+	return args
+}
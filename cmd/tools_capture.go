@@ -0,0 +1,168 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/vishvananda/netlink"
+)
+
+// captureDir is the directory of the lab directory the pcap files are written to
+const captureDir = "captures"
+
+var (
+	captureStdout bool
+	captureCount  int
+)
+
+func init() {
+	toolsCmd.AddCommand(captureCmd)
+	captureCmd.Flags().BoolVarP(&captureStdout, "stdout", "", false, "stream the captured packets in pcap format to stdout instead of writing them to the lab directory")
+	captureCmd.Flags().IntVarP(&captureCount, "count", "c", 0, "exit after capturing the number of packets")
+}
+
+var captureCmd = &cobra.Command{
+	Use:   "capture node:interface [-- filter expression]",
+	Short: "capture the packets of a node interface",
+	Long: `capture runs tcpdump of the container host in the network namespace of a node
+and writes the captured packets to a pcap file in the lab directory or streams them to stdout, e.g. to wireshark.
+reference: https://containerlab.srlinux.dev/cmd/tools/capture/`,
+	Args:    cobra.MinimumNArgs(1),
+	PreRunE: sudoCheck,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		node, iface, err := parseCaptureEndpoint(args[0])
+		if err != nil {
+			return err
+		}
+		if runtime.IsRemoteHost(host) {
+			return fmt.Errorf("packets of the containers running on a remote host can't be captured")
+		}
+		if !captureStdout && topo == "" {
+			return fmt.Errorf("provide the topology with --topo to write the capture to the lab directory or stream it with --stdout")
+		}
+		opts := []clab.ClabOption{
+			clab.WithTimeout(timeout),
+			clab.WithRuntime(rt,
+				&runtime.RuntimeConfig{
+					Debug:   debug,
+					Timeout: timeout,
+					Host:    host,
+				},
+			),
+		}
+		if topo != "" {
+			opts = append(opts, clab.WithTopoVars(topoVars), clab.WithTopoFile(topo))
+		}
+		c, err := clab.NewContainerLab(opts...)
+		if err != nil {
+			return err
+		}
+
+		cntName := node
+		// the lab nodes are referenced by their names in the topology
+		if n, ok := c.Nodes[node]; ok {
+			cntName = n.Config().LongName
+		}
+		nsPath, err := c.GlobalRuntime().GetNSPath(context.Background(), cntName)
+		if err != nil {
+			return err
+		}
+
+		w := "-"
+		if !captureStdout {
+			w = captureFilePath(filepath.Join(c.Dir.Lab, captureDir), node, iface, time.Now())
+			if err := os.MkdirAll(filepath.Dir(w), 0755); err != nil {
+				return err
+			}
+			log.Infof("Capturing packets of %s:%s to %s, press Ctrl-C to stop", node, iface, w)
+		}
+
+		// the interrupt stops tcpdump running in the foreground process group,
+		// containerlab waits for it to flush the captured packets
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+		defer signal.Stop(sig)
+
+		if err := runCapture(nsPath, iface, tcpdumpArgs(iface, w, captureCount, args[1:])); err != nil {
+			return err
+		}
+		if !captureStdout {
+			log.Infof("Packets of %s:%s are written to %s", node, iface, w)
+		}
+		return nil
+	},
+}
+
+// parseCaptureEndpoint returns the node and the interface of the endpoint in the node:interface format,
+// the interface names may contain the colons
+func parseCaptureEndpoint(ep string) (string, string, error) {
+	i := strings.Index(ep, ":")
+	if i <= 0 || i == len(ep)-1 {
+		return "", "", fmt.Errorf("endpoint %q is not in the node:interface format", ep)
+	}
+	return ep[:i], ep[i+1:], nil
+}
+
+// captureFilePath returns the path of the pcap file in the directory dir for the capture of the node interface started at now
+func captureFilePath(dir, node, iface string, now time.Time) string {
+	// the slashes of the interface names, e.g. Ethernet1/1, are not valid in the file names
+	iface = strings.ReplaceAll(iface, "/", "_")
+	return filepath.Join(dir, fmt.Sprintf("%s-%s-%s.pcap", node, iface, now.Format("20060102-150405")))
+}
+
+// tcpdumpArgs returns the tcpdump command capturing the packets of the interface to the file w,
+// the packets are written unbuffered, so that the stream to stdout ("-") is read by wireshark as it arrives
+func tcpdumpArgs(iface, w string, count int, filter []string) []string {
+	args := []string{"tcpdump", "-nni", iface, "-U", "-w", w}
+	if w != "-" {
+		// tcpdump drops privileges before opening the file, which is in the lab directory owned by root
+		args = append(args, "-Z", "root")
+	}
+	if count > 0 {
+		args = append(args, "-c", strconv.Itoa(count))
+	}
+	return append(args, filter...)
+}
+
+// runCapture runs the tcpdump command in the network namespace of nsPath after checking that the interface exists,
+// the packets streamed to stdout are not mixed with the tcpdump messages which are written to stderr
+func runCapture(nsPath, iface string, args []string) error {
+	if _, err := exec.LookPath(args[0]); err != nil {
+		return fmt.Errorf("%s is not found on the container host: %v", args[0], err)
+	}
+	netNS, err := ns.GetNS(nsPath)
+	if err != nil {
+		return err
+	}
+	defer netNS.Close()
+	return netNS.Do(func(_ ns.NetNS) error {
+		if _, err := netlink.LinkByName(iface); err != nil {
+			return fmt.Errorf("interface %s is not found in network namespace %s: %v", iface, nsPath, err)
+		}
+		c := exec.Command(args[0], args[1:]...)
+		c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+		err := c.Run()
+		// tcpdump exits on the interrupt after flushing the capture
+		if exitErr, ok := err.(*exec.ExitError); ok && !exitErr.Exited() {
+			return nil
+		}
+		return err
+	})
+}
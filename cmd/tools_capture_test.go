@@ -0,0 +1,98 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseCaptureEndpoint(t *testing.T) {
+	tests := map[string]struct {
+		ep        string
+		wantNode  string
+		wantIface string
+		wantErr   bool
+	}{
+		"endpoint": {
+			ep:        "srl1:e1-1",
+			wantNode:  "srl1",
+			wantIface: "e1-1",
+		},
+		"interface-with-colon": {
+			ep:        "clab-lab-vmx:eth1:0",
+			wantNode:  "clab-lab-vmx",
+			wantIface: "eth1:0",
+		},
+		"no-interface": {
+			ep:      "srl1:",
+			wantErr: true,
+		},
+		"no-node": {
+			ep:      ":e1-1",
+			wantErr: true,
+		},
+		"no-separator": {
+			ep:      "srl1",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			node, iface, err := parseCaptureEndpoint(tc.ep)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if node != tc.wantNode || iface != tc.wantIface {
+				t.Errorf("got %s:%s, want %s:%s", node, iface, tc.wantNode, tc.wantIface)
+			}
+		})
+	}
+}
+
+func TestCaptureFilePath(t *testing.T) {
+	now := time.Date(2021, 6, 1, 10, 20, 30, 0, time.UTC)
+	got := captureFilePath("/lab/captures", "ceos1", "Ethernet1/1", now)
+	want := "/lab/captures/ceos1-Ethernet1_1-20210601-102030.pcap"
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestTcpdumpArgs(t *testing.T) {
+	tests := map[string]struct {
+		w      string
+		count  int
+		filter []string
+		want   []string
+	}{
+		"stdout": {
+			w:    "-",
+			want: []string{"tcpdump", "-nni", "e1-1", "-U", "-w", "-"},
+		},
+		"file": {
+			w:    "/lab/captures/srl1-e1-1.pcap",
+			want: []string{"tcpdump", "-nni", "e1-1", "-U", "-w", "/lab/captures/srl1-e1-1.pcap", "-Z", "root"},
+		},
+		"count-and-filter": {
+			w:      "-",
+			count:  10,
+			filter: []string{"ether", "proto", "0x88cc"},
+			want:   []string{"tcpdump", "-nni", "e1-1", "-U", "-w", "-", "-c", "10", "ether", "proto", "0x88cc"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := tcpdumpArgs("e1-1", tc.w, tc.count, tc.filter)
+			if !cmp.Equal(got, tc.want) {
+				t.Errorf("diff (-want +got):\n%s", cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
@@ -5,8 +5,10 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strings"
 	"text/template"
 
 	cfssllog "github.com/cloudflare/cfssl/log"
@@ -14,6 +16,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/srl-labs/containerlab/cert"
 	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/runtime"
 )
 
 var (
@@ -29,12 +32,16 @@ var (
 	certHosts        []string
 	caCertPath       string
 	caKeyPath        string
+	renewCA          bool
+	renewNodes       []string
+	reloadCerts      bool
 )
 
 func init() {
 	toolsCmd.AddCommand(certCmd)
 	certCmd.AddCommand(CACmd)
 	certCmd.AddCommand(signCertCmd)
+	certCmd.AddCommand(renewCertCmd)
 	CACmd.AddCommand(CACreateCmd)
 
 	CACreateCmd.Flags().StringVarP(&commonName, "cn", "", "containerlab.srlinux.dev", "Common Name")
@@ -56,6 +63,10 @@ func init() {
 	signCertCmd.Flags().StringVarP(&organizationUnit, "ou", "", "Containerlab Tools", "Organization Unit")
 	signCertCmd.Flags().StringVarP(&path, "path", "p", "", "path to write certificate and key to. Default is current working directory")
 	signCertCmd.Flags().StringVarP(&certNamePrefix, "name", "n", "cert", "certificate/key filename prefix")
+
+	renewCertCmd.Flags().BoolVarP(&renewCA, "ca", "", false, "regenerate the lab root CA and renew certificates of all nodes")
+	renewCertCmd.Flags().StringSliceVarP(&renewNodes, "node", "", []string{}, "comma separated list of nodes to renew certificates of. Default is all nodes using the lab certificates")
+	renewCertCmd.Flags().BoolVarP(&reloadCerts, "reload", "", false, "install renewed certificates on running nodes")
 }
 
 var certCmd = &cobra.Command{
@@ -80,6 +91,13 @@ var signCertCmd = &cobra.Command{
 	RunE:  signCert,
 }
 
+var renewCertCmd = &cobra.Command{
+	Use:     "renew",
+	Short:   "renew lab nodes certificates",
+	PreRunE: sudoCheck,
+	RunE:    renewCert,
+}
+
 func createCA(cmd *cobra.Command, args []string) error {
 	csr := `{
 	"CN": "{{.CommonName}}",
@@ -196,7 +214,6 @@ func signCert(cmd *cobra.Command, args []string) error {
 		Locality:         locality,
 		Organization:     organization,
 		OrganizationUnit: organizationUnit,
-		Expiry:           expiry,
 		Name:             certNamePrefix,
 	},
 		path,
@@ -207,3 +224,46 @@ func signCert(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// renew certificates of the lab nodes
+func renewCert(cmd *cobra.Command, args []string) error {
+	if topo == "" {
+		return fmt.Errorf("provide topology file path  with --topo flag")
+	}
+	opts := []clab.ClabOption{
+		clab.WithTimeout(timeout),
+		clab.WithTopoVars(topoVars),
+		clab.WithTopoFile(topo),
+		clab.WithLabDirPath(labDirPath),
+		clab.WithRuntime(rt,
+			&runtime.RuntimeConfig{
+				Debug:            debug,
+				Timeout:          timeout,
+				GracefulShutdown: graceful,
+				Host:             host,
+			},
+		),
+	}
+	c, err := clab.NewContainerLab(opts...)
+	if err != nil {
+		return err
+	}
+
+	cfssllog.Level = cfssllog.LevelError
+	if debug {
+		cfssllog.Level = cfssllog.LevelDebug
+	}
+
+	renewed, err := c.RenewCerts(renewCA, renewNodes)
+	if err != nil {
+		return fmt.Errorf("failed to renew certificates: %v", err)
+	}
+	log.Infof("Renewed certificates of nodes %s are stored in %s", strings.Join(renewed, ", "), c.Dir.LabCA)
+
+	if !reloadCerts {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	return c.ReloadCerts(ctx, renewed)
+}
@@ -0,0 +1,107 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// schedule of the interface flaps
+var linkFlap clab.LinkFlap
+
+func init() {
+	toolsCmd.AddCommand(linkCmd)
+	linkCmd.AddCommand(linkSetCmd)
+	linkCmd.AddCommand(linkFlapCmd)
+
+	linkFlapCmd.Flags().DurationVarP(&linkFlap.Down, "down-time", "", 0, "time the interface stays down during a flap, e.g. 5s")
+	linkFlapCmd.Flags().DurationVarP(&linkFlap.Interval, "interval", "", 0, "interval between the starts of the flaps, e.g. 30s")
+	linkFlapCmd.Flags().IntVarP(&linkFlap.Count, "count", "c", 0, "number of flaps, the interface flaps until interrupted when 0")
+}
+
+var linkCmd = &cobra.Command{
+	Use:   "link",
+	Short: "link state operations",
+	Long:  "bring the node interfaces down and up, once or on a schedule, to script the failover and convergence scenarios\nreference: https://containerlab.srlinux.dev/cmd/tools/link/",
+}
+
+var linkSetCmd = &cobra.Command{
+	Use:     "set node:interface up|down",
+	Short:   "set the administrative state of the node interface",
+	Args:    cobra.ExactArgs(2),
+	PreRunE: sudoCheck,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		state := args[1]
+		if state != types.InterfaceStateUp && state != types.InterfaceStateDown {
+			return fmt.Errorf("invalid interface state %q, use one of [up, down]", state)
+		}
+		node, iface, nsPath, err := linkEndpointNSPath(args[0])
+		if err != nil {
+			return err
+		}
+		if err := clab.SetInterfaceState(nsPath, iface, state); err != nil {
+			return err
+		}
+		log.Infof("Interface %s:%s is %s", node, iface, state)
+		return nil
+	},
+}
+
+var linkFlapCmd = &cobra.Command{
+	Use:     "flap node:interface",
+	Short:   "flap the node interface on a schedule",
+	Args:    cobra.ExactArgs(1),
+	PreRunE: sudoCheck,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := linkFlap.Validate(); err != nil {
+			return err
+		}
+		node, iface, nsPath, err := linkEndpointNSPath(args[0])
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		// the interrupted flap brings the interface up before exiting
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+		defer signal.Stop(sig)
+		go func() {
+			select {
+			case <-sig:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		log.Infof("Flapping interface %s:%s down for %s every %s, press Ctrl-C to stop", node, iface, linkFlap.Down, linkFlap.Interval)
+		return clab.FlapInterface(ctx, nsPath, iface, &linkFlap)
+	},
+}
+
+// linkEndpointNSPath returns the node, the interface and the netns path of the node of the endpoint
+// in the node:interface format
+func linkEndpointNSPath(ep string) (string, string, string, error) {
+	node, iface, err := parseCaptureEndpoint(ep)
+	if err != nil {
+		return "", "", "", err
+	}
+	if runtime.IsRemoteHost(host) {
+		return "", "", "", fmt.Errorf("interfaces of the containers running on a remote host can't be managed")
+	}
+	nsPath, err := nodeNSPath(node)
+	return node, iface, nsPath, err
+}
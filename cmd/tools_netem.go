@@ -0,0 +1,190 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+)
+
+var (
+	// node and its interface the impairments are set on
+	netemNode  string
+	netemIface string
+	netem      types.Netem
+)
+
+func init() {
+	toolsCmd.AddCommand(netemCmd)
+	netemCmd.AddCommand(netemSetCmd)
+	netemCmd.AddCommand(netemShowCmd)
+	netemCmd.AddCommand(netemResetCmd)
+
+	netemCmd.PersistentFlags().StringVarP(&netemNode, "node", "", "", "name of the lab node (with --topo) or of the container")
+
+	netemSetCmd.Flags().StringVarP(&netemIface, "interface", "i", "", "interface of the container")
+	netemSetCmd.Flags().IntVarP(&netem.Delay, "delay", "", 0, "delay in milliseconds")
+	netemSetCmd.Flags().IntVarP(&netem.Jitter, "jitter", "", 0, "delay jitter in milliseconds")
+	netemSetCmd.Flags().Float64VarP(&netem.Loss, "loss", "", 0, "percentage of the lost packets")
+	netemSetCmd.Flags().Float64VarP(&netem.Duplicate, "duplicate", "", 0, "percentage of the duplicated packets")
+	netemSetCmd.Flags().Float64VarP(&netem.Corruption, "corruption", "", 0, "percentage of the corrupted packets")
+	netemSetCmd.Flags().IntVarP(&netem.Rate, "rate", "", 0, "rate limit in kbit/s")
+
+	netemShowCmd.Flags().StringVarP(&format, "format", "f", "table", "output format. One of [table, json]")
+
+	netemResetCmd.Flags().StringVarP(&netemIface, "interface", "i", "", "interface of the container")
+}
+
+var netemCmd = &cobra.Command{
+	Use:   "netem",
+	Short: "link impairments operations",
+	Long:  "set, show and reset the impairments of the container interfaces emulated with the netem qdisc\nreference: https://containerlab.srlinux.dev/cmd/tools/netem/",
+}
+
+var netemSetCmd = &cobra.Command{
+	Use:     "set",
+	Short:   "set the impairments of the container interface, replacing the impairments set before",
+	PreRunE: sudoCheck,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if netemIface == "" {
+			return fmt.Errorf("provide the interface with --interface flag")
+		}
+		if err := netem.Validate(); err != nil {
+			return err
+		}
+		nsPath, err := netemNodeNSPath()
+		if err != nil {
+			return err
+		}
+		if err := clab.SetInterfaceNetem(nsPath, netemIface, &netem); err != nil {
+			return err
+		}
+		log.Infof("Applied netem %+v to %s:%s", netem, netemNode, netemIface)
+		return nil
+	},
+}
+
+var netemShowCmd = &cobra.Command{
+	Use:     "show",
+	Short:   "show the impairments of the container interfaces",
+	PreRunE: sudoCheck,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if format != "table" && format != "json" {
+			return fmt.Errorf("unsupported output format %q, use one of [table, json]", format)
+		}
+		nsPath, err := netemNodeNSPath()
+		if err != nil {
+			return err
+		}
+		ifaces, err := clab.InterfacesNetem(nsPath)
+		if err != nil {
+			return err
+		}
+		if format == "json" {
+			b, err := json.Marshal(ifaces)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+			return nil
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Interface", "Delay", "Jitter", "Loss", "Duplicate", "Corruption", "Rate"})
+		table.SetAutoFormatHeaders(false)
+		table.SetAutoWrapText(false)
+		for _, i := range ifaces {
+			table.Append(netemRow(i))
+		}
+		table.Render()
+		return nil
+	},
+}
+
+var netemResetCmd = &cobra.Command{
+	Use:     "reset",
+	Short:   "remove the impairments of the container interface",
+	PreRunE: sudoCheck,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if netemIface == "" {
+			return fmt.Errorf("provide the interface with --interface flag")
+		}
+		nsPath, err := netemNodeNSPath()
+		if err != nil {
+			return err
+		}
+		if err := clab.ResetInterfaceNetem(nsPath, netemIface); err != nil {
+			return err
+		}
+		log.Infof("Removed netem from %s:%s", netemNode, netemIface)
+		return nil
+	},
+}
+
+// netemNodeNSPath returns the netns path of the node the impairments are managed for
+func netemNodeNSPath() (string, error) {
+	if netemNode == "" {
+		return "", fmt.Errorf("provide the node with --node flag")
+	}
+	if runtime.IsRemoteHost(host) {
+		return "", fmt.Errorf("impairments of the containers running on a remote host can't be managed")
+	}
+	return nodeNSPath(netemNode)
+}
+
+// nodeNSPath returns the netns path of the node referenced by its name in the topology or by its container name
+func nodeNSPath(node string) (string, error) {
+	opts := []clab.ClabOption{
+		clab.WithTimeout(timeout),
+		clab.WithRuntime(rt,
+			&runtime.RuntimeConfig{
+				Debug:   debug,
+				Timeout: timeout,
+				Host:    host,
+			},
+		),
+	}
+	if topo != "" {
+		opts = append(opts, clab.WithTopoVars(topoVars), clab.WithTopoFile(topo))
+	}
+	c, err := clab.NewContainerLab(opts...)
+	if err != nil {
+		return "", err
+	}
+	cntName := node
+	if n, ok := c.Nodes[node]; ok {
+		cntName = n.Config().LongName
+	}
+	return c.GlobalRuntime().GetNSPath(context.Background(), cntName)
+}
+
+// netemRow returns the table row with the impairments of the interface,
+// the impairments of the interfaces without the netem qdisc are not set
+func netemRow(i clab.InterfaceNetem) []string {
+	if i.Netem == nil {
+		return []string{i.Interface, "-", "-", "-", "-", "-", "-"}
+	}
+	rate := "-"
+	if i.Rate != 0 {
+		rate = fmt.Sprintf("%d kbit/s", i.Rate)
+	}
+	return []string{
+		i.Interface,
+		fmt.Sprintf("%d ms", i.Delay),
+		fmt.Sprintf("%d ms", i.Jitter),
+		fmt.Sprintf("%v%%", i.Loss),
+		fmt.Sprintf("%v%%", i.Duplicate),
+		fmt.Sprintf("%v%%", i.Corruption),
+		rate,
+	}
+}
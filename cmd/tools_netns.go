@@ -0,0 +1,106 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+var netnsNode string
+
+func init() {
+	toolsCmd.AddCommand(netnsCmd)
+	netnsCmd.AddCommand(netnsAttachCmd)
+	netnsAttachCmd.Flags().StringVarP(&netnsNode, "node", "", "", "name of the lab node (with --topo) or of the container to attach to")
+	_ = netnsAttachCmd.MarkFlagRequired("node")
+}
+
+var netnsCmd = &cobra.Command{
+	Use:   "netns",
+	Short: "network namespace operations",
+}
+
+var netnsAttachCmd = &cobra.Command{
+	Use:   "attach [-- command [args...]]",
+	Short: "run a command or a shell in the network namespace of a node",
+	Long: `attach runs the command with the host tools, e.g. tcpdump or ip, in the network namespace of a node,
+the interactive shell of the user is started when no command is given.
+reference: https://containerlab.srlinux.dev/cmd/tools/netns/attach/`,
+	PreRunE: sudoCheck,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if runtime.IsRemoteHost(host) {
+			return fmt.Errorf("network namespaces of the containers running on a remote host can't be attached to")
+		}
+		opts := []clab.ClabOption{
+			clab.WithTimeout(timeout),
+			clab.WithRuntime(rt,
+				&runtime.RuntimeConfig{
+					Debug:            debug,
+					Timeout:          timeout,
+					GracefulShutdown: graceful,
+					Host:             host,
+				},
+			),
+		}
+		if topo != "" {
+			opts = append(opts, clab.WithTopoVars(topoVars), clab.WithTopoFile(topo))
+		}
+		c, err := clab.NewContainerLab(opts...)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		cntName := netnsNode
+		// the lab nodes are referenced by their names in the topology
+		if n, ok := c.Nodes[netnsNode]; ok {
+			cntName = n.Config().LongName
+		}
+		nsPath, err := c.GlobalRuntime().GetNSPath(ctx, cntName)
+		if err != nil {
+			return err
+		}
+		log.Debugf("attaching to network namespace %s of %s", nsPath, cntName)
+		return runInNetNS(nsPath, netnsCmdArgs(args))
+	},
+}
+
+// netnsCmdArgs returns the command run in the network namespace,
+// the shell of the user by default
+func netnsCmdArgs(args []string) []string {
+	if len(args) != 0 {
+		return args
+	}
+	if sh := os.Getenv("SHELL"); sh != "" {
+		return []string{sh}
+	}
+	return []string{"/bin/sh"}
+}
+
+// runInNetNS runs the command attached to the terminal in the network namespace of nsPath,
+// the mount namespace of the host is kept, so the host tools and files are used
+func runInNetNS(nsPath string, args []string) error {
+	netNS, err := ns.GetNS(nsPath)
+	if err != nil {
+		return err
+	}
+	defer netNS.Close()
+	// the command is forked from the thread switched to the network namespace and inherits it
+	return netNS.Do(func(_ ns.NetNS) error {
+		c := exec.Command(args[0], args[1:]...)
+		c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+		return c.Run()
+	})
+}
@@ -0,0 +1,41 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNetnsCmdArgs(t *testing.T) {
+	tests := map[string]struct {
+		args  []string
+		shell string
+		want  []string
+	}{
+		"command": {
+			args:  []string{"ip", "-br", "a"},
+			shell: "/bin/bash",
+			want:  []string{"ip", "-br", "a"},
+		},
+		"user-shell": {
+			shell: "/usr/bin/zsh",
+			want:  []string{"/usr/bin/zsh"},
+		},
+		"no-shell": {
+			want: []string{"/bin/sh"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("SHELL", tc.shell)
+			if got := netnsCmdArgs(tc.args); !cmp.Equal(got, tc.want) {
+				t.Errorf("diff (-want +got):\n%s", cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
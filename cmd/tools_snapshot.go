@@ -0,0 +1,142 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+)
+
+var (
+	// path to the snapshot bundle
+	snapshotFile string
+	// directory the snapshot is restored to
+	snapshotRestoreDir string
+	// restore the snapshot without deploying the lab
+	snapshotExtractOnly bool
+)
+
+func init() {
+	toolsCmd.AddCommand(snapshotCmd)
+	snapshotCmd.AddCommand(snapshotCreateCmd)
+	snapshotCmd.AddCommand(snapshotRestoreCmd)
+
+	snapshotCreateCmd.Flags().StringVarP(&snapshotFile, "output", "o", "", "path to the snapshot bundle, defaults to <lab-name>-<timestamp>.snapshot.tar.gz in the current directory")
+
+	snapshotRestoreCmd.Flags().StringVarP(&snapshotFile, "file", "f", "", "path to the snapshot bundle")
+	snapshotRestoreCmd.Flags().StringVarP(&snapshotRestoreDir, "dir", "", "", "directory the snapshot is extracted to, defaults to the bundle name without the extension")
+	snapshotRestoreCmd.Flags().BoolVarP(&snapshotExtractOnly, "extract-only", "", false, "extract the snapshot without deploying the lab")
+	_ = snapshotRestoreCmd.MarkFlagRequired("file")
+}
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "lab snapshot operations",
+	Long:  "capture the state of a deployed lab in a portable bundle and deploy the lab of a bundle\nreference: https://containerlab.srlinux.dev/cmd/tools/snapshot/",
+}
+
+var snapshotCreateCmd = &cobra.Command{
+	Use:          "create",
+	Short:        "save the node configs and capture the lab state in a snapshot bundle",
+	SilenceUsage: true,
+	PreRunE:      sudoCheck,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if topo == "" {
+			return fmt.Errorf("provide topology file path with --topo flag")
+		}
+		c, err := clab.NewContainerLab(
+			clab.WithTimeout(timeout),
+			clab.WithTopoVars(topoVars),
+			clab.WithTopoFile(topo),
+			clab.WithLabDirPath(labDirPath),
+			clab.WithRuntime(rt,
+				&runtime.RuntimeConfig{
+					Debug:            debug,
+					Timeout:          timeout,
+					GracefulShutdown: graceful,
+					Host:             host,
+				},
+			),
+		)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		labels := []*types.GenericFilter{{FilterType: "label", Match: c.Config.Name, Field: "containerlab", Operator: "="}}
+		containers, err := c.ListContainers(ctx, labels)
+		if err != nil {
+			return err
+		}
+		if len(containers) == 0 {
+			return fmt.Errorf("lab %s is not deployed", c.Config.Name)
+		}
+		log.Infof("Saving configuration of lab %s nodes", c.Config.Name)
+		saveConfigs(ctx, c)
+		enrichNodes(containers, c.Nodes)
+
+		now := time.Now()
+		p := snapshotFile
+		if p == "" {
+			p = clab.SnapshotFileName(c.Config.Name, now)
+		}
+		f, err := os.Create(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := c.CreateSnapshot(f, containers, now); err != nil {
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		log.Infof("Snapshot of lab %s saved to %s", c.Config.Name, p)
+		return nil
+	},
+}
+
+var snapshotRestoreCmd = &cobra.Command{
+	Use:          "restore",
+	Short:        "extract a snapshot bundle and deploy its lab",
+	SilenceUsage: true,
+	PreRunE:      sudoCheck,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := snapshotRestoreDir
+		if dir == "" {
+			dir = strings.TrimSuffix(strings.TrimSuffix(filepath.Base(snapshotFile), ".tar.gz"), ".snapshot")
+		}
+		f, err := os.Open(snapshotFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		snap, topoFile, err := clab.RestoreSnapshot(f, dir)
+		if err != nil {
+			return err
+		}
+		log.Infof("Snapshot of lab %s taken at %s extracted to %s", snap.Name, snap.Created.Format(time.RFC3339), dir)
+		if snapshotExtractOnly {
+			return nil
+		}
+		// the files of the snapshot nodes are relative to the snapshot directory
+		if err := os.Chdir(dir); err != nil {
+			return err
+		}
+		topo = filepath.Base(topoFile)
+		return deployCmd.RunE(deployCmd, nil)
+	},
+}
@@ -47,6 +47,7 @@ var vethCreateCmd = &cobra.Command{
 					Debug:            debug,
 					Timeout:          timeout,
 					GracefulShutdown: graceful,
+					Host:             host,
 				},
 			),
 		}
@@ -0,0 +1,77 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+)
+
+// output format of the validation errors
+var validateFormat string
+
+// validateCmd represents the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "validate topology file",
+	Long:  "check the topology file against the topology schema and for the errors preventing the lab deployment, such as unknown keys, unknown kinds and duplicate links\nreference: https://containerlab.srlinux.dev/cmd/validate/",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if topo == "" {
+			return fmt.Errorf("provide topology file path with --topo flag")
+		}
+		if validateFormat != "table" && validateFormat != "json" {
+			return fmt.Errorf("unsupported output format %q, use one of [table, json]", validateFormat)
+		}
+		errs, err := clab.ValidateTopology(topo, clab.WithTopoVars(topoVars))
+		if err != nil {
+			return err
+		}
+		if err := printValidationErrors(errs, validateFormat); err != nil {
+			return err
+		}
+		if len(errs) != 0 {
+			return fmt.Errorf("topology file %s is not valid, %d errors found", topo, len(errs))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+	validateCmd.Flags().StringVarP(&validateFormat, "format", "f", "table", "output format. One of [table, json]")
+}
+
+func printValidationErrors(errs []*clab.ValidationError, format string) error {
+	if format == "json" {
+		if errs == nil {
+			errs = []*clab.ValidationError{}
+		}
+		b, err := json.MarshalIndent(errs, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal validation errors: %v", err)
+		}
+		fmt.Println(string(b))
+		return nil
+	}
+	if len(errs) == 0 {
+		fmt.Printf("topology file %s is valid\n", topo)
+		return nil
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Line", "Column", "Rule", "Path", "Message"})
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+	for _, e := range errs {
+		table.Append([]string{strconv.Itoa(e.Line), strconv.Itoa(e.Column), e.Rule, e.Path, e.Message})
+	}
+	table.Render()
+	return nil
+}
@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"net"
 
-	"github.com/jsimonetti/rtnetlink/rtnl"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/srl-labs/containerlab/clab"
@@ -20,6 +19,7 @@ var cntLink string
 var parentDev string
 var vxlanMTU int
 var vxlanID int
+var vxlanPort int
 var delPrefix string
 
 func init() {
@@ -32,6 +32,7 @@ func init() {
 	vxlanCreateCmd.Flags().StringVarP(&parentDev, "dev", "", "", "parent (source) interface name for VxLAN")
 	vxlanCreateCmd.Flags().StringVarP(&cntLink, "link", "l", "", "link to which 'attach' vxlan tunnel with tc redirect")
 	vxlanCreateCmd.Flags().IntVarP(&vxlanMTU, "mtu", "m", 1554, "VxLAN MTU")
+	vxlanCreateCmd.Flags().IntVarP(&vxlanPort, "port", "", 4789, "VxLAN destination UDP port")
 
 	_ = vxlanCreateCmd.MarkFlagRequired("remote")
 	_ = vxlanCreateCmd.MarkFlagRequired("id")
@@ -57,16 +58,10 @@ var vxlanCreateCmd = &cobra.Command{
 		// if vxlan device was not set specifically, we will use
 		// the device that is reported by `ip route get $remote`
 		if parentDev == "" {
-			conn, err := rtnl.Dial(nil)
-			if err != nil {
-				return fmt.Errorf("can't establish netlink connection: %s", err)
-			}
-			defer conn.Close()
-			r, err := conn.RouteGet(net.ParseIP(vxlanRemote))
-			if err != nil {
-				return fmt.Errorf("failed to find a route to VxLAN remote address %s", vxlanRemote)
+			var err error
+			if parentDev, err = clab.VxlanParentIf(net.ParseIP(vxlanRemote)); err != nil {
+				return err
 			}
-			parentDev = r.Interface.Name
 		}
 
 		vxlanCfg := clab.VxLAN{
@@ -75,6 +70,7 @@ var vxlanCreateCmd = &cobra.Command{
 			ParentIf: parentDev,
 			Remote:   net.ParseIP(vxlanRemote),
 			MTU:      vxlanMTU,
+			UDPPort:  vxlanPort,
 		}
 
 		if err := clab.AddVxLanInterface(vxlanCfg); err != nil {
@@ -0,0 +1,118 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/runtime"
+)
+
+// output format of the watch command
+var watchFormat string
+
+// watchCmd represents the watch command
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "watch the state changes of the lab nodes",
+	Long: `watch prints the state changes of the lab nodes, i.e. created, running, ready, exited and oom, as they happen
+and runs the on-ready and on-exit hooks of the nodes defined in the topology file
+reference: https://containerlab.srlinux.dev/cmd/watch/`,
+	PreRunE: sudoCheck,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if name == "" && topo == "" {
+			return fmt.Errorf("provide either a lab name (--name) or a topology file path (--topo)")
+		}
+		switch watchFormat {
+		case "plain", "json":
+		default:
+			return fmt.Errorf("unsupported output format %q, use one of [plain, json]", watchFormat)
+		}
+		opts := []clab.ClabOption{
+			clab.WithTimeout(timeout),
+			clab.WithRuntime(rt,
+				&runtime.RuntimeConfig{
+					Debug:            debug,
+					Timeout:          timeout,
+					GracefulShutdown: graceful,
+					Host:             host,
+				},
+			),
+		}
+		if topo != "" {
+			opts = append(opts, clab.WithTopoVars(topoVars), clab.WithTopoFile(topo), clab.WithLabDirPath(labDirPath))
+		}
+		c, err := clab.NewContainerLab(opts...)
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			name = c.Config.Name
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+		go func() {
+			s := <-sig
+			log.Infof("Received %s, stopping the watch of lab %s", s, name)
+			cancel()
+		}()
+
+		var metrics *clab.LabMetrics
+		if metricsListen != "" {
+			metrics = clab.NewLabMetrics(c, name)
+			serveMetrics(ctx, metrics)
+		}
+
+		log.Infof("Watching the nodes of lab %s, press Ctrl+C to stop", name)
+		return c.WatchNodes(ctx, name, func(ev *clab.NodeEvent) {
+			if metrics != nil {
+				metrics.Observe(ev)
+			}
+			if err := printNodeEvent(os.Stdout, ev, watchFormat); err != nil {
+				log.Errorf("failed to print event: %v", err)
+			}
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	watchCmd.Flags().StringVarP(&watchFormat, "format", "f", "plain", "output format. One of [plain, json]")
+	watchCmd.Flags().StringVarP(&metricsListen, "metrics-listen", "", "", "address to serve the Prometheus metrics of the lab nodes on, e.g. :9450")
+}
+
+// printNodeEvent writes the node event as a line of text or a json document
+func printNodeEvent(w io.Writer, ev *clab.NodeEvent, format string) error {
+	if format == "json" {
+		b, err := json.Marshal(ev)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	}
+	node := ev.Node
+	if node == "" {
+		node = ev.Container
+	}
+	line := fmt.Sprintf("%s %s %s", ev.Time.Format("2006-01-02T15:04:05Z07:00"), node, ev.State)
+	if ev.ExitCode != nil {
+		line += fmt.Sprintf(" (exit code %d)", *ev.ExitCode)
+	}
+	_, err := fmt.Fprintln(w, line)
+	return err
+}
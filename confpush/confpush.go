@@ -0,0 +1,94 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package confpush pushes the configuration declared in the topology to the deployed nodes
+// over the management network with the transports declared by the kinds in the push templates.
+package confpush
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/types"
+)
+
+const (
+	// GNMI updates the config of the node with the gNMI Set RPC, the payloads are JSON or YAML documents
+	GNMI = "gnmi"
+	// Netconf edits the config datastore of the node with the edit-config RPC, the payloads are XML documents
+	Netconf = "netconf"
+)
+
+// permanentError is the push error not resolved by retrying the push, e.g. the config rejected by the node
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks the push error as not resolved by retrying the push
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// Template declares how the config of a kind is pushed
+type Template struct {
+	// name of the registered transport performing the push
+	Transport string
+	// netconf datastore the config is edited in, the candidate datastore is committed after the edit.
+	// The running datastore is used when unset
+	Datastore string
+	// port of the gNMI server, 57400 when unset
+	Port int
+}
+
+// Transport pushes the config payloads to a node as declared by the template
+type Transport interface {
+	Push(ctx context.Context, node *types.NodeConfig, creds *types.Credentials, t *Template, payloads []string) error
+}
+
+var transports = map[string]Transport{}
+
+// Register makes the transport available to the templates by its name
+func Register(name string, t Transport) {
+	transports[name] = t
+}
+
+func init() {
+	Register(GNMI, new(gnmiTransport))
+	Register(Netconf, new(netconfTransport))
+}
+
+// Push pushes the payloads to the node with the template transport.
+// The push is retried every poll interval until the node accepts the config or ctx is done,
+// as the management services of the node become available some time after its container is started
+func Push(ctx context.Context, node *types.NodeConfig, creds *types.Credentials, t *Template, payloads []string, poll time.Duration) error {
+	if t == nil {
+		return fmt.Errorf("%s: config push is not supported for kind %s", node.ShortName, node.Kind)
+	}
+	tr, ok := transports[t.Transport]
+	if !ok {
+		return fmt.Errorf("%s: unknown config push transport %q", node.ShortName, t.Transport)
+	}
+	for {
+		err := tr.Push(ctx, node, creds, t, payloads)
+		if err == nil {
+			return nil
+		}
+		var perr *permanentError
+		if errors.As(err, &perr) {
+			return fmt.Errorf("%s: %v", node.ShortName, err)
+		}
+		log.Debugf("%s: config push via %s failed, retrying: %v", node.ShortName, t.Transport, err)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s: config push via %s failed: %v, last error: %v", node.ShortName, t.Transport, ctx.Err(), err)
+		case <-time.After(poll):
+		}
+	}
+}
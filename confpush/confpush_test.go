@@ -0,0 +1,93 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package confpush
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// fakeTransport fails the pushes with the errors in order and records the pushed payloads
+type fakeTransport struct {
+	errs   []error
+	pushed [][]string
+}
+
+func (f *fakeTransport) Push(_ context.Context, _ *types.NodeConfig, _ *types.Credentials, _ *Template, payloads []string) error {
+	f.pushed = append(f.pushed, payloads)
+	if len(f.errs) == 0 {
+		return nil
+	}
+	err := f.errs[0]
+	f.errs = f.errs[1:]
+	return err
+}
+
+func TestPush(t *testing.T) {
+	node := &types.NodeConfig{ShortName: "r1", Kind: "vr-test"}
+	payloads := []string{"<system/>"}
+	refused := errors.New("connection refused")
+	tests := map[string]struct {
+		errs       []error
+		timeout    time.Duration
+		wantPushes int
+		wantErr    bool
+	}{
+		"first-attempt": {
+			wantPushes: 1,
+		},
+		"retried-until-reachable": {
+			errs:       []error{refused, refused},
+			wantPushes: 3,
+		},
+		"rejected": {
+			errs:       []error{refused, Permanent(errors.New("invalid value"))},
+			wantPushes: 2,
+			wantErr:    true,
+		},
+		"timeout": {
+			errs:    []error{refused, refused, refused, refused, refused, refused, refused, refused, refused, refused},
+			timeout: 5 * time.Millisecond,
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			tr := &fakeTransport{errs: tc.errs}
+			Register("fake", tr)
+			ctx := context.Background()
+			if tc.timeout != 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tc.timeout)
+				defer cancel()
+			}
+			err := Push(ctx, node, &types.Credentials{}, &Template{Transport: "fake"}, payloads, time.Millisecond)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if tc.wantPushes != 0 && len(tr.pushed) != tc.wantPushes {
+				t.Errorf("expected %d pushes, got %d", tc.wantPushes, len(tr.pushed))
+			}
+			if d := cmp.Diff(payloads, tr.pushed[0]); d != "" {
+				t.Errorf("payloads mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestPushUnsupported(t *testing.T) {
+	node := &types.NodeConfig{ShortName: "r1", Kind: "vr-test"}
+	if err := Push(context.Background(), node, &types.Credentials{}, nil, nil, time.Millisecond); err == nil {
+		t.Errorf("expected an error for the kind without the push template")
+	}
+	if err := Push(context.Background(), node, &types.Credentials{}, &Template{Transport: "unknown"}, nil, time.Millisecond); err == nil {
+		t.Errorf("expected an error for an unknown transport")
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package confpush
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// defaultGNMIPort is used when the template doesn't set the gNMI port
+const defaultGNMIPort = 57400
+
+// gnmiTransport runs the Set RPC with the gnmic client, each payload updates the root path
+type gnmiTransport struct{}
+
+func (*gnmiTransport) Push(ctx context.Context, node *types.NodeConfig, creds *types.Credentials, t *Template, payloads []string) error {
+	if _, err := exec.LookPath("gnmic"); err != nil {
+		return Permanent(errors.New("gnmic binary is required to push the config via gNMI, see https://gnmic.kmrd.dev/install"))
+	}
+	port := t.Port
+	if port == 0 {
+		port = defaultGNMIPort
+	}
+	for _, p := range payloads {
+		f, err := ioutil.TempFile("", "clab-push-*")
+		if err != nil {
+			return err
+		}
+		_, err = f.WriteString(p)
+		f.Close()
+		if err != nil {
+			os.Remove(f.Name())
+			return err
+		}
+		out, err := exec.CommandContext(ctx, "gnmic",
+			"--address", net.JoinHostPort(node.LongName, strconv.Itoa(port)),
+			"--username", creds.Username,
+			"--password", creds.Password,
+			"--skip-verify",
+			"--encoding", "json_ietf",
+			"set", "--update-path", "/", "--update-file", f.Name(),
+		).CombinedOutput()
+		os.Remove(f.Name())
+		if err != nil {
+			// the gRPC status errors are returned by the node processing the request
+			if bytes.Contains(out, []byte("rpc error:")) {
+				return Permanent(fmt.Errorf("gnmi set failed: %v\n%s", err, out))
+			}
+			return fmt.Errorf("gnmi set failed: %v\n%s", err, out)
+		}
+	}
+	return nil
+}
+
+// netconfTransport edits the datastore of the template with the edit-config RPC
+type netconfTransport struct{}
+
+func (*netconfTransport) Push(_ context.Context, node *types.NodeConfig, creds *types.Credentials, t *Template, payloads []string) error {
+	err := utils.EditCfgViaNetconf(node.LongName, creds.Username, creds.Password, t.Datastore, payloads)
+	if errors.Is(err, utils.ErrNetconfRPC) {
+		return Permanent(err)
+	}
+	return err
+}
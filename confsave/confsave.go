@@ -0,0 +1,96 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package confsave saves the running configuration of the nodes over the management network
+// with the transports declared by the kinds in the save templates.
+package confsave
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/types"
+)
+
+const (
+	// Netconf copies the running datastore to the startup datastore
+	Netconf = "netconf"
+	// SSHCLI sends the save command to the CLI over SSH
+	SSHCLI = "ssh-cli"
+	// GNMI sets the save path of the node with a gNMI Set RPC
+	GNMI = "gnmi"
+)
+
+// Template declares how the running config of a kind is saved
+type Template struct {
+	// name of the registered transport performing the save
+	Transport string
+	// CLI command sent by the ssh-cli transport,
+	// rendered as a text/template with the node config, e.g. `copy running-config {{ .ShortName }}.cfg`
+	Command string
+	// strings the CLI reports the command errors with
+	FailedWhenContains []string
+	// path and JSON value set by the gnmi transport
+	Path  string
+	Value string
+	// port of the gNMI server, 57400 when unset
+	Port int
+}
+
+// Transport saves the running config of a node as declared by the template
+type Transport interface {
+	Save(ctx context.Context, node *types.NodeConfig, creds *types.Credentials, t *Template) error
+}
+
+var transports = map[string]Transport{}
+
+// Register makes the transport available to the templates by its name
+func Register(name string, t Transport) {
+	transports[name] = t
+}
+
+func init() {
+	Register(Netconf, new(netconfTransport))
+	Register(SSHCLI, new(sshCLITransport))
+	Register(GNMI, new(gnmiTransport))
+}
+
+// Save saves the running config of the node with the templates tried in order,
+// the next template is used when the save with the previous one fails
+func Save(ctx context.Context, node *types.NodeConfig, creds *types.Credentials, templates []*Template) error {
+	if len(templates) == 0 {
+		return fmt.Errorf("%s: config save is not supported for kind %s", node.ShortName, node.Kind)
+	}
+	errs := make([]string, 0, len(templates))
+	for _, t := range templates {
+		tr, ok := transports[t.Transport]
+		if !ok {
+			return fmt.Errorf("%s: unknown config save transport %q", node.ShortName, t.Transport)
+		}
+		err := tr.Save(ctx, node, creds, t)
+		if err == nil {
+			return nil
+		}
+		log.Debugf("%s: config save via %s failed: %v", node.ShortName, t.Transport, err)
+		errs = append(errs, fmt.Sprintf("%s: %v", t.Transport, err))
+	}
+	return fmt.Errorf("%s: config save failed: %s", node.ShortName, strings.Join(errs, "; "))
+}
+
+// renderCommand renders the template command with the node config
+func renderCommand(cmd string, node *types.NodeConfig) (string, error) {
+	t, err := template.New("cmd").Parse(cmd)
+	if err != nil {
+		return "", err
+	}
+	var b bytes.Buffer
+	if err := t.Execute(&b, node); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package confsave
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// fakeTransport records the commands of the templates it is called with
+type fakeTransport struct {
+	err  error
+	cmds []string
+}
+
+func (f *fakeTransport) Save(_ context.Context, node *types.NodeConfig, _ *types.Credentials, t *Template) error {
+	cmd, err := renderCommand(t.Command, node)
+	if err != nil {
+		return err
+	}
+	f.cmds = append(f.cmds, cmd)
+	return f.err
+}
+
+func TestSave(t *testing.T) {
+	failing := &fakeTransport{err: errors.New("connection refused")}
+	working := new(fakeTransport)
+	Register("failing", failing)
+	Register("working", working)
+
+	node := &types.NodeConfig{ShortName: "r1", Kind: "vr-test"}
+	tests := map[string]struct {
+		templates []*Template
+		wantCmds  []string
+		wantErr   bool
+	}{
+		"first-template": {
+			templates: []*Template{
+				{Transport: "working", Command: "save {{ .ShortName }}"},
+				{Transport: "failing", Command: "unused"},
+			},
+			wantCmds: []string{"save r1"},
+		},
+		"fallback": {
+			templates: []*Template{
+				{Transport: "failing", Command: "copy run start"},
+				{Transport: "working", Command: "write memory"},
+			},
+			wantCmds: []string{"copy run start", "write memory"},
+		},
+		"all-failed": {
+			templates: []*Template{{Transport: "failing", Command: "copy run start"}},
+			wantCmds:  []string{"copy run start"},
+			wantErr:   true,
+		},
+		"unknown-transport": {
+			templates: []*Template{{Transport: "telnet"}},
+			wantErr:   true,
+		},
+		"no-templates": {
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			failing.cmds, working.cmds = nil, nil
+			err := Save(context.Background(), node, &types.Credentials{}, tc.templates)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got error: %v, want error: %v", err, tc.wantErr)
+			}
+			got := append(failing.cmds, working.cmds...)
+			if !cmp.Equal(got, tc.wantCmds) {
+				t.Errorf("got commands: %v, want: %v", got, tc.wantCmds)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package confsave
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os/exec"
+	"strconv"
+
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// defaultGNMIPort is used when the template doesn't set the gNMI port
+const defaultGNMIPort = 57400
+
+type netconfTransport struct{}
+
+func (*netconfTransport) Save(_ context.Context, node *types.NodeConfig, creds *types.Credentials, _ *Template) error {
+	return utils.SaveCfgViaNetconf(node.LongName, creds.Username, creds.Password)
+}
+
+type sshCLITransport struct{}
+
+func (*sshCLITransport) Save(_ context.Context, node *types.NodeConfig, creds *types.Credentials, t *Template) error {
+	cmd, err := renderCommand(t.Command, node)
+	if err != nil {
+		return fmt.Errorf("failed to render save command %q: %v", t.Command, err)
+	}
+	return utils.SaveCfgViaSSHCLI(node.LongName, creds.Username, creds.Password, cmd, t.FailedWhenContains)
+}
+
+// gnmiTransport runs the Set RPC with the gnmic client
+type gnmiTransport struct{}
+
+func (*gnmiTransport) Save(ctx context.Context, node *types.NodeConfig, creds *types.Credentials, t *Template) error {
+	if _, err := exec.LookPath("gnmic"); err != nil {
+		return errors.New("gnmic binary is required to save the config via gNMI, see https://gnmic.kmrd.dev/install")
+	}
+	port := t.Port
+	if port == 0 {
+		port = defaultGNMIPort
+	}
+	out, err := exec.CommandContext(ctx, "gnmic",
+		"--address", net.JoinHostPort(node.LongName, strconv.Itoa(port)),
+		"--username", creds.Username,
+		"--password", creds.Password,
+		"--skip-verify",
+		"--encoding", "json_ietf",
+		"set", "--update-path", t.Path, "--update-value", t.Value,
+	).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("gnmi set of %s failed: %v\n%s", t.Path, err, out)
+	}
+	return nil
+}
@@ -0,0 +1,296 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package convert converts the topologies of the other network emulators to the containerlab topologies
+package convert
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/srl-labs/containerlab/clab"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// formats of the converted topologies
+const (
+	FormatGNS3    = "gns3"
+	FormatEVENG   = "eve-ng"
+	FormatNetJSON = "netjson"
+)
+
+// Formats lists the formats of the converted topologies
+var Formats = []string{FormatGNS3, FormatEVENG, FormatNetJSON}
+
+// Options are the options of the conversion
+type Options struct {
+	// Name of the lab, the name of the source topology is used when not set
+	Name string
+	// DefaultKind is the kind of the nodes which type has no containerlab kind
+	DefaultKind string
+	// Images of the kinds
+	Images map[string]string
+}
+
+// Result is the converted topology along with the warnings about the elements
+// of the source topology which were not converted or converted approximately
+type Result struct {
+	Config   *clab.Config
+	Warnings []string
+}
+
+// DetectFormat returns the format of the topology file by its extension
+func DetectFormat(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".gns3":
+		return FormatGNS3, nil
+	case ".unl":
+		return FormatEVENG, nil
+	case ".json":
+		return FormatNetJSON, nil
+	}
+	return "", fmt.Errorf("can't detect the format of %s, set one of %v", path, Formats)
+}
+
+// Convert converts the topology data of the format to the containerlab topology
+func Convert(format string, data []byte, opts *Options) (*Result, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	if opts.DefaultKind == "" {
+		opts.DefaultKind = nodes.NodeKindLinux
+	}
+	switch format {
+	case FormatGNS3:
+		return convertGNS3(data, opts)
+	case FormatEVENG:
+		return convertEVENG(data, opts)
+	case FormatNetJSON:
+		return convertNetJSON(data, opts)
+	}
+	return nil, fmt.Errorf("unsupported format %q, use one of %v", format, Formats)
+}
+
+// kindRules map the substrings of the node types, templates, images and names of the source topologies
+// to the containerlab kinds, the first matching rule wins.
+// The rules with no kind match the systems which have no containerlab kind
+var kindRules = []struct {
+	match string
+	kind  string
+}{
+	// Cisco IOU/IOL images are linux binaries, they are not the linux hosts
+	{"i86bi", ""},
+	{"adventerprise", ""},
+	{"srlinux", nodes.NodeKindSRL},
+	{"ceos", nodes.NodeKindCEOS},
+	{"veos", nodes.NodeKindVrVEOS},
+	{"crpd", nodes.NodeKindCRPD},
+	{"vmx", nodes.NodeKindVrVMX},
+	{"vqfx", nodes.NodeKindVrVQFX},
+	{"xrv9k", nodes.NodeKindVrXRV9K},
+	{"xrv", nodes.NodeKindVrXRV},
+	{"csr1000v", nodes.NodeKindVrCSR},
+	{"csr", nodes.NodeKindVrCSR},
+	{"n9kv", nodes.NodeKindVrN9KV},
+	{"nxosv9k", nodes.NodeKindVrN9KV},
+	{"nxos", nodes.NodeKindVrNXOS},
+	{"sros", nodes.NodeKindVrSROS},
+	{"timos", nodes.NodeKindVrSROS},
+	{"routeros", nodes.NodeKindVrROS},
+	{"mikrotik", nodes.NodeKindVrROS},
+	{"ftos", nodes.NodeKindVrFTOSV},
+	{"paloalto", nodes.NodeKindVrPAN},
+	{"panos", nodes.NodeKindVrPAN},
+	{"cumulus", nodes.NodeKindCVX},
+	{"cvx", nodes.NodeKindCVX},
+	{"sonic", "sonic-vs"},
+	{"vpcs", nodes.NodeKindLinux},
+	{"alpine", nodes.NodeKindLinux},
+	{"ubuntu", nodes.NodeKindLinux},
+	{"debian", nodes.NodeKindLinux},
+	{"centos", nodes.NodeKindLinux},
+	{"multitool", nodes.NodeKindLinux},
+	{"linux", nodes.NodeKindLinux},
+}
+
+// mapKind returns the containerlab kind matching the first of the hints matched by the kind rules
+func mapKind(hints ...string) (string, bool) {
+	for _, h := range hints {
+		h = strings.ToLower(h)
+		if h == "" {
+			continue
+		}
+		for _, r := range kindRules {
+			if strings.Contains(h, r.match) {
+				return r.kind, r.kind != ""
+			}
+		}
+	}
+	return "", false
+}
+
+// interfaceName returns the name of the i-th data interface of the node of the kind, i starts from 1
+func interfaceName(kind string, i int) string {
+	switch kind {
+	case nodes.NodeKindSRL:
+		return fmt.Sprintf("e1-%d", i)
+	case nodes.NodeKindCVX:
+		return fmt.Sprintf("swp%d", i)
+	}
+	return fmt.Sprintf("eth%d", i)
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// maxBridgeName is the length the bridge names are cut to, leaving the room for the uniqueness suffix
+// within the 15 characters of the linux interface names
+const maxBridgeName = 12
+
+// builder builds the containerlab topology from the nodes and links of the source topology
+type builder struct {
+	opts     *Options
+	cfg      *clab.Config
+	warnings []string
+	// containerlab nodes by the ids of the source nodes
+	nodes map[string]*node
+	// names of the containerlab nodes
+	names map[string]struct{}
+	// kinds which images were checked
+	imageKinds map[string]struct{}
+}
+
+// node is a node of the containerlab topology
+type node struct {
+	name string
+	kind string
+	// number of the ports of the bridge nodes
+	ports int
+}
+
+func newBuilder(name string, opts *Options) *builder {
+	if opts.Name != "" {
+		name = opts.Name
+	}
+	if name = sanitizeName(name); name == "" {
+		name = "converted"
+	}
+	cfg := &clab.Config{
+		Name: name,
+		Topology: &types.Topology{
+			Nodes: map[string]*types.NodeDefinition{},
+		},
+	}
+	for k, img := range opts.Images {
+		if cfg.Topology.Kinds == nil {
+			cfg.Topology.Kinds = map[string]*types.NodeDefinition{}
+		}
+		cfg.Topology.Kinds[k] = &types.NodeDefinition{Image: img}
+	}
+	return &builder{
+		opts:       opts,
+		cfg:        cfg,
+		nodes:      map[string]*node{},
+		names:      map[string]struct{}{},
+		imageKinds: map[string]struct{}{},
+	}
+}
+
+func (b *builder) warnf(format string, args ...interface{}) {
+	b.warnings = append(b.warnings, fmt.Sprintf(format, args...))
+}
+
+// addNode adds the node id of the source topology of the type typ with the image.
+// The kind of the node is mapped from the hints, the nodes with no kind matched get the default kind
+func (b *builder) addNode(id, name, typ, image string, hints ...string) {
+	kind, ok := mapKind(hints...)
+	switch {
+	case ok:
+	case typ == "":
+		kind = b.opts.DefaultKind
+		b.warnf("node %s has no matching kind, converted to %s kind", name, kind)
+	default:
+		kind = b.opts.DefaultKind
+		b.warnf("node %s of type %s has no matching kind, converted to %s kind", name, typ, kind)
+	}
+	b.addNodeKind(id, name, kind, image)
+}
+
+// addNodeKind adds the node id of the source topology as the node of the kind
+func (b *builder) addNodeKind(id, name, kind, image string) {
+	if kind == nodes.NodeKindBridge && len(name) > maxBridgeName {
+		name = name[:maxBridgeName]
+	}
+	n := &node{name: b.uniqueName(name), kind: kind}
+	b.nodes[id] = n
+	def := &types.NodeDefinition{Kind: kind}
+	// the images of the source emulators are kept for the container based nodes only
+	if image != "" && !nodes.IsVrKind(kind) && kind != nodes.NodeKindBridge {
+		def.Image = image
+	}
+	b.cfg.Topology.Nodes[n.name] = def
+	if kind == nodes.NodeKindBridge {
+		b.warnf("bridge %s has to be created on the container host before the lab is deployed", n.name)
+		return
+	}
+	if _, ok := b.imageKinds[kind]; ok || def.Image != "" || b.opts.Images[kind] != "" {
+		return
+	}
+	b.imageKinds[kind] = struct{}{}
+	b.warnf("kind %s has no image, set it in the kinds section or with --image %s=<image>", kind, kind)
+}
+
+// uniqueName returns the sanitized name not used by the other nodes
+func (b *builder) uniqueName(name string) string {
+	base := sanitizeName(name)
+	if base == "" {
+		base = "node"
+	}
+	name = base
+	for i := 2; ; i++ {
+		if _, ok := b.names[name]; !ok {
+			break
+		}
+		name = fmt.Sprintf("%s-%d", base, i)
+	}
+	b.names[name] = struct{}{}
+	return name
+}
+
+// sanitizeName replaces the characters not allowed in the container names
+func sanitizeName(name string) string {
+	return strings.Trim(invalidNameChars.ReplaceAllString(name, "-"), "-._")
+}
+
+// endpoint returns the endpoint of the i-th data interface of the node id,
+// the bridge nodes get the ports numbered in the order of the links
+func (b *builder) endpoint(id string, i int) string {
+	n := b.nodes[id]
+	if n.kind != nodes.NodeKindBridge {
+		return n.name + ":" + interfaceName(n.kind, i)
+	}
+	n.ports++
+	// bridge ports are created in the host netns and their names are limited to 15 characters
+	port := fmt.Sprintf("-p%d", n.ports)
+	prefix := n.name
+	if len(prefix)+len(port) > 15 {
+		prefix = prefix[:15-len(port)]
+	}
+	return n.name + ":" + prefix + port
+}
+
+// addLink adds the link between the ai-th interface of the node a and the bi-th interface of the node b
+func (b *builder) addLink(a string, ai int, bn string, bi int, netem *types.Netem) {
+	lc := &types.LinkConfig{Endpoints: []string{b.endpoint(a, ai), b.endpoint(bn, bi)}}
+	if netem.IsSet() {
+		lc.Netem = *netem
+	}
+	b.cfg.Topology.Links = append(b.cfg.Topology.Links, lc)
+}
+
+func (b *builder) result() *Result {
+	return &Result{Config: b.cfg, Warnings: b.warnings}
+}
@@ -0,0 +1,209 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package convert
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestMapKind(t *testing.T) {
+	tests := map[string]struct {
+		hints []string
+		want  string
+		ok    bool
+	}{
+		"image": {
+			hints: []string{"vEOS-lab-4.25.0F.vmdk"},
+			want:  "vr-veos",
+			ok:    true,
+		},
+		"xrv9k_before_xrv": {
+			hints: []string{"xrv9k-fullk9-x-7.2.1.qcow2"},
+			want:  "vr-xrv9k",
+			ok:    true,
+		},
+		"first_matching_hint": {
+			hints: []string{"", "unknown", "srlinux:21.6.1", "alpine"},
+			want:  "srl",
+			ok:    true,
+		},
+		"iou_not_linux": {
+			hints: []string{"i86bi-linux-l3-adventerprisek9-15.4.2T.bin"},
+		},
+		"no_match": {
+			hints: []string{"R1"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			kind, ok := mapKind(tc.hints...)
+			if kind != tc.want || ok != tc.ok {
+				t.Errorf("got %q, %v, want %q, %v", kind, ok, tc.want, tc.ok)
+			}
+		})
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := map[string]string{
+		"lab.gns3":      FormatGNS3,
+		"/tmp/Lab.UNL":  FormatEVENG,
+		"graph.json":    FormatNetJSON,
+		"topo.clab.yml": "",
+		"no-extension":  "",
+	}
+	for path, want := range tests {
+		got, err := DetectFormat(path)
+		if want == "" {
+			if err == nil {
+				t.Errorf("%s: expected error, got format %q", path, got)
+			}
+			continue
+		}
+		if err != nil || got != want {
+			t.Errorf("%s: got %q, %v, want %q", path, got, err, want)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := map[string]struct {
+		path     string
+		format   string
+		opts     *Options
+		name     string
+		nodes    map[string]*types.NodeDefinition
+		links    []*types.LinkConfig
+		warnings []string
+	}{
+		"gns3": {
+			path:   "test_data/lab.gns3",
+			format: FormatGNS3,
+			opts:   &Options{Images: map[string]string{"vr-veos": "vrnetlab/vr-veos:4.25.0F"}},
+			name:   "campus-lab",
+			nodes: map[string]*types.NodeDefinition{
+				"R1":      {Kind: "vr-veos"},
+				"ceos-2":  {Kind: "ceos", Image: "ceos:4.26.0F"},
+				"Switch1": {Kind: "bridge"},
+				"PC1":     {Kind: "linux"},
+				"IOU1":    {Kind: "linux"},
+			},
+			links: []*types.LinkConfig{
+				{
+					Endpoints: []string{"R1:eth3", "ceos-2:eth1"},
+					Netem:     types.Netem{Delay: 20, Jitter: 5, Loss: 1},
+				},
+				{Endpoints: []string{"ceos-2:eth2", "Switch1:Switch1-p1"}},
+				{Endpoints: []string{"PC1:eth1", "Switch1:Switch1-p2"}},
+			},
+			warnings: []string{
+				"bridge Switch1 has to be created on the container host before the lab is deployed",
+				"node PC1 of type vpcs has no matching kind, converted to linux kind",
+				"kind linux has no image, set it in the kinds section or with --image linux=<image>",
+				"cloud node Cloud1 is not converted, connect the lab to the external networks with the host or bridge endpoints",
+				"node IOU1 of type iou has no matching kind, converted to linux kind",
+				"link R1 <-> Cloud1 to the node which is not converted is dropped",
+			},
+		},
+		"eve-ng": {
+			path:   "test_data/lab.unl",
+			format: FormatEVENG,
+			opts:   &Options{Name: "wan lab", DefaultKind: "crpd"},
+			name:   "wan-lab",
+			nodes: map[string]*types.NodeDefinition{
+				"vMX1":        {Kind: "vr-vmx"},
+				"XRv-2":       {Kind: "vr-xrv"},
+				"host":        {Kind: "linux", Image: "alpine:3.13"},
+				"R4":          {Kind: "crpd"},
+				"LAN-segment": {Kind: "bridge"},
+			},
+			links: []*types.LinkConfig{
+				{Endpoints: []string{"vMX1:eth1", "XRv-2:eth2"}},
+				{Endpoints: []string{"vMX1:eth2", "LAN-segment:LAN-segment-p1"}},
+				{Endpoints: []string{"XRv-2:eth3", "LAN-segment:LAN-segment-p2"}},
+				{Endpoints: []string{"host:eth1", "LAN-segment:LAN-segment-p3"}},
+			},
+			warnings: []string{
+				"kind vr-vmx has no image, set it in the kinds section or with --image vr-vmx=<image>",
+				"kind vr-xrv has no image, set it in the kinds section or with --image vr-xrv=<image>",
+				"node R4 of type iol has no matching kind, converted to crpd kind",
+				"kind crpd has no image, set it in the kinds section or with --image crpd=<image>",
+				"serial interface s0/0 of node R4 is not converted",
+				"bridge LAN-segment has to be created on the container host before the lab is deployed",
+				"network Internet attached to the host interface pnet0 is not converted, connect the lab to the external networks with the host or bridge endpoints",
+			},
+		},
+		"netjson": {
+			path:   "test_data/graph.json",
+			format: FormatNetJSON,
+			opts:   &Options{Images: map[string]string{"linux": "alpine"}},
+			name:   "mesh",
+			nodes: map[string]*types.NodeDefinition{
+				"srl1":     {Kind: "srl", Image: "ghcr.io/nokia/srlinux"},
+				"node-2":   {Kind: "linux"},
+				"10.0.0.3": {Kind: "linux"},
+			},
+			links: []*types.LinkConfig{
+				{Endpoints: []string{"srl1:e1-1", "node-2:eth1"}},
+				{Endpoints: []string{"node-2:eth2", "10.0.0.3:eth1"}},
+			},
+			warnings: []string{
+				"node node 2 has no matching kind, converted to linux kind",
+				"node 10.0.0.3 has no matching kind, converted to linux kind",
+				"link 10.0.0.3 <-> 10.0.0.4 to the unknown node is dropped",
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			data, err := ioutil.ReadFile(tc.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res, err := Convert(tc.format, data, tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Config.Name != tc.name {
+				t.Errorf("got name %q, want %q", res.Config.Name, tc.name)
+			}
+			if d := cmp.Diff(tc.nodes, res.Config.Topology.Nodes); d != "" {
+				t.Errorf("nodes mismatch (-want +got):\n%s", d)
+			}
+			if d := cmp.Diff(tc.links, res.Config.Topology.Links); d != "" {
+				t.Errorf("links mismatch (-want +got):\n%s", d)
+			}
+			if d := cmp.Diff(tc.warnings, res.Warnings); d != "" {
+				t.Errorf("warnings mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+	tests := map[string]struct {
+		format string
+		data   string
+	}{
+		"unknown_format": {format: "vagrant", data: "{}"},
+		"not_networkgraph": {
+			format: FormatNetJSON,
+			data:   `{"type": "NetworkRoutes", "routes": []}`,
+		},
+		"invalid_gns3":   {format: FormatGNS3, data: "not json"},
+		"invalid_eve-ng": {format: FormatEVENG, data: "<lab"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Convert(tc.format, []byte(tc.data), nil); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package convert
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/srl-labs/containerlab/nodes"
+)
+
+// evengLab is the EVE-NG lab file, only the fields used in the conversion are decoded
+type evengLab struct {
+	Name  string      `xml:"name,attr"`
+	Nodes []evengNode `xml:"topology>nodes>node"`
+	Nets  []evengNet  `xml:"topology>networks>network"`
+}
+
+type evengNode struct {
+	ID         string           `xml:"id,attr"`
+	Name       string           `xml:"name,attr"`
+	Type       string           `xml:"type,attr"`
+	Template   string           `xml:"template,attr"`
+	Image      string           `xml:"image,attr"`
+	Interfaces []evengInterface `xml:"interface"`
+}
+
+type evengInterface struct {
+	ID      int    `xml:"id,attr"`
+	Name    string `xml:"name,attr"`
+	Type    string `xml:"type,attr"`
+	Network string `xml:"network_id,attr"`
+}
+
+type evengNet struct {
+	ID   string `xml:"id,attr"`
+	Name string `xml:"name,attr"`
+	Type string `xml:"type,attr"`
+}
+
+// evengEndpoint is the interface of a node attached to a network
+type evengEndpoint struct {
+	node string
+	// data interface index of the interface
+	index int
+}
+
+// convertEVENG converts the EVE-NG lab file.
+// The networks connecting two interfaces are converted to the links, the other networks to the bridges
+func convertEVENG(data []byte, opts *Options) (*Result, error) {
+	var lab evengLab
+	if err := xml.Unmarshal(data, &lab); err != nil {
+		return nil, fmt.Errorf("failed to parse EVE-NG lab: %v", err)
+	}
+	b := newBuilder(lab.Name, opts)
+	for _, n := range lab.Nodes {
+		image := ""
+		if n.Type == "docker" {
+			image = n.Image
+		}
+		b.addNode("node-"+n.ID, n.Name, n.Type, image, n.Template, n.Image, n.Name)
+	}
+
+	nets := map[string]evengNet{}
+	for _, net := range lab.Nets {
+		nets[net.ID] = net
+	}
+	// endpoints of the networks in the order of the nodes and their interfaces
+	var netIDs []string
+	netEndpoints := map[string][]evengEndpoint{}
+	for _, n := range lab.Nodes {
+		for _, i := range n.Interfaces {
+			// the serial interfaces are connected point to point with no network
+			if i.Type == "serial" {
+				b.warnf("serial interface %s of node %s is not converted", i.Name, n.Name)
+				continue
+			}
+			if i.Network == "" || i.Network == "0" {
+				continue
+			}
+			if _, ok := netEndpoints[i.Network]; !ok {
+				netIDs = append(netIDs, i.Network)
+			}
+			// the interface ids start from 0 and the data interfaces from 1
+			netEndpoints[i.Network] = append(netEndpoints[i.Network], evengEndpoint{node: "node-" + n.ID, index: i.ID + 1})
+		}
+	}
+
+	for _, id := range netIDs {
+		net, eps := nets[id], netEndpoints[id]
+		switch {
+		case strings.HasPrefix(net.Type, "pnet"):
+			b.warnf("network %s attached to the host interface %s is not converted, connect the lab to the external networks with the host or bridge endpoints", net.Name, net.Type)
+		case len(eps) < 2:
+		case len(eps) == 2:
+			b.addLink(eps[0].node, eps[0].index, eps[1].node, eps[1].index, nil)
+		default:
+			name := net.Name
+			if name == "" {
+				name = "net" + id
+			}
+			b.addNodeKind("net-"+id, name, nodes.NodeKindBridge, "")
+			for _, ep := range eps {
+				b.addLink(ep.node, ep.index, "net-"+id, 0, nil)
+			}
+		}
+	}
+	return b.result(), nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package convert
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// gns3Project is the GNS3 project file, only the fields used in the conversion are decoded
+type gns3Project struct {
+	Name     string `json:"name"`
+	Topology struct {
+		Nodes []gns3Node `json:"nodes"`
+		Links []gns3Link `json:"links"`
+	} `json:"topology"`
+}
+
+type gns3Node struct {
+	ID         string `json:"node_id"`
+	Name       string `json:"name"`
+	Type       string `json:"node_type"`
+	Properties struct {
+		Image     string `json:"image"`
+		DiskImage string `json:"hda_disk_image"`
+		Path      string `json:"path"`
+		Platform  string `json:"platform"`
+	} `json:"properties"`
+	Ports []gns3Port `json:"ports"`
+}
+
+type gns3Port struct {
+	Adapter int `json:"adapter_number"`
+	Port    int `json:"port_number"`
+}
+
+type gns3Link struct {
+	Nodes []struct {
+		ID string `json:"node_id"`
+		gns3Port
+	} `json:"nodes"`
+	// link filters emulating the impairments, e.g. delay: [latency, jitter]
+	Filters map[string][]float64 `json:"filters"`
+}
+
+// gns3Bridges are the GNS3 node types converted to the bridges
+var gns3Bridges = map[string]struct{}{
+	"ethernet_switch": {},
+	"ethernet_hub":    {},
+}
+
+// convertGNS3 converts the GNS3 project file
+func convertGNS3(data []byte, opts *Options) (*Result, error) {
+	var p gns3Project
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse GNS3 project: %v", err)
+	}
+	b := newBuilder(p.Name, opts)
+	// data interface indexes of the node ports by the node id
+	ports := map[string]map[gns3Port]int{}
+	for _, n := range p.Topology.Nodes {
+		switch _, bridge := gns3Bridges[n.Type]; {
+		case bridge:
+			b.addNodeKind(n.ID, n.Name, nodes.NodeKindBridge, "")
+		case n.Type == "cloud" || n.Type == "nat":
+			b.warnf("%s node %s is not converted, connect the lab to the external networks with the host or bridge endpoints", n.Type, n.Name)
+			continue
+		case n.Type == "docker":
+			b.addNode(n.ID, n.Name, n.Type, n.Properties.Image, n.Properties.Image, n.Name)
+		default:
+			b.addNode(n.ID, n.Name, n.Type, "", n.Properties.DiskImage, n.Properties.Path, n.Properties.Platform, n.Name)
+		}
+		ports[n.ID] = gns3PortIndexes(n, p.Topology.Links)
+	}
+
+	for _, l := range p.Topology.Links {
+		if len(l.Nodes) != 2 {
+			continue
+		}
+		a, z := l.Nodes[0], l.Nodes[1]
+		if b.nodes[a.ID] == nil || b.nodes[z.ID] == nil {
+			b.warnf("link %s <-> %s to the node which is not converted is dropped", gns3NodeName(&p, a.ID), gns3NodeName(&p, z.ID))
+			continue
+		}
+		b.addLink(a.ID, ports[a.ID][a.gns3Port], z.ID, ports[z.ID][z.gns3Port], gns3Netem(l.Filters))
+	}
+	return b.result(), nil
+}
+
+// gns3PortIndexes returns the data interface indexes of the ports of the node.
+// The ports are numbered in the order of the ports list of the node, or of the linked ports
+// when the project has no ports list
+func gns3PortIndexes(n gns3Node, links []gns3Link) map[gns3Port]int {
+	list := n.Ports
+	if len(list) == 0 {
+		for _, l := range links {
+			for _, ln := range l.Nodes {
+				if ln.ID == n.ID {
+					list = append(list, ln.gns3Port)
+				}
+			}
+		}
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].Adapter != list[j].Adapter {
+				return list[i].Adapter < list[j].Adapter
+			}
+			return list[i].Port < list[j].Port
+		})
+	}
+	res := make(map[gns3Port]int, len(list))
+	for _, p := range list {
+		if _, ok := res[p]; !ok {
+			res[p] = len(res) + 1
+		}
+	}
+	return res
+}
+
+func gns3NodeName(p *gns3Project, id string) string {
+	for _, n := range p.Topology.Nodes {
+		if n.ID == id {
+			return n.Name
+		}
+	}
+	return id
+}
+
+// gns3Netem converts the GNS3 link filters to the link impairments
+func gns3Netem(filters map[string][]float64) *types.Netem {
+	n := &types.Netem{}
+	if d := filters["delay"]; len(d) > 0 {
+		n.Delay = int(d[0])
+		if len(d) > 1 {
+			n.Jitter = int(d[1])
+		}
+	}
+	if l := filters["packet_loss"]; len(l) > 0 {
+		n.Loss = l[0]
+	}
+	if c := filters["corrupt"]; len(c) > 0 {
+		n.Corruption = c[0]
+	}
+	return n
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package convert
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// netJSONGraph is the NetJSON NetworkGraph object
+type netJSONGraph struct {
+	Type  string `json:"type"`
+	Label string `json:"label"`
+	Nodes []struct {
+		ID         string                 `json:"id"`
+		Label      string                 `json:"label"`
+		Properties map[string]interface{} `json:"properties"`
+	} `json:"nodes"`
+	Links []struct {
+		Source string `json:"source"`
+		Target string `json:"target"`
+	} `json:"links"`
+}
+
+// convertNetJSON converts the NetJSON NetworkGraph.
+// The kinds and images of the nodes are taken from the kind and image node properties,
+// the interfaces of the nodes are numbered in the order of the links.
+// A link listed in both directions is converted to a single link
+func convertNetJSON(data []byte, opts *Options) (*Result, error) {
+	var g netJSONGraph
+	if err := json.Unmarshal(data, &g); err != nil {
+		return nil, fmt.Errorf("failed to parse NetJSON: %v", err)
+	}
+	if g.Type != "NetworkGraph" {
+		return nil, fmt.Errorf("NetJSON object of type %q is not a NetworkGraph", g.Type)
+	}
+	b := newBuilder(g.Label, opts)
+	for _, n := range g.Nodes {
+		name := n.Label
+		if name == "" {
+			name = n.ID
+		}
+		image, _ := n.Properties["image"].(string)
+		if kind, _ := n.Properties["kind"].(string); kind != "" {
+			b.addNodeKind(n.ID, name, kind, image)
+			continue
+		}
+		b.addNode(n.ID, name, "", image, image)
+	}
+
+	ifaces := map[string]int{}
+	// the links seen in one direction, the graphs of the routing protocols list the links in both directions
+	directed := map[[2]string]int{}
+	for _, l := range g.Links {
+		if b.nodes[l.Source] == nil || b.nodes[l.Target] == nil {
+			b.warnf("link %s <-> %s to the unknown node is dropped", l.Source, l.Target)
+			continue
+		}
+		if reverse := [2]string{l.Target, l.Source}; directed[reverse] > 0 {
+			directed[reverse]--
+			continue
+		}
+		directed[[2]string{l.Source, l.Target}]++
+		ifaces[l.Source]++
+		ifaces[l.Target]++
+		b.addLink(l.Source, ifaces[l.Source], l.Target, ifaces[l.Target], nil)
+	}
+	return b.result(), nil
+}
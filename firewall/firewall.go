@@ -0,0 +1,71 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package firewall
+
+import (
+	"fmt"
+	"os/exec"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	AutoFirewall     = "auto"
+	IPTablesFirewall = "iptables"
+	NFTablesFirewall = "nftables"
+	NoneFirewall     = "none"
+
+	// chain where docker expects user rules to be placed,
+	// the rules of this chain are evaluated before the docker isolation rules
+	DockerUserChain = "DOCKER-USER"
+	// comment attached to the rules installed by containerlab
+	RuleComment = "set by containerlab"
+)
+
+// Firewall manages the rules allowing traffic to and from the management network bridge
+type Firewall interface {
+	// Name returns the firewall backend name
+	Name() string
+	// InstallForwardingRules allows forwarding of the traffic egressing the bridge
+	InstallForwardingRules(bridge string) error
+	// DeleteForwardingRules removes the rules installed for the bridge
+	DeleteForwardingRules(bridge string) error
+}
+
+type Initializer func() (Firewall, error)
+
+var Firewalls = map[string]Initializer{}
+
+func Register(name string, initFn Initializer) {
+	Firewalls[name] = initFn
+}
+
+// New returns the firewall backend of a given type,
+// the backend is detected when the type is empty or auto
+func New(t string) (Firewall, error) {
+	if t == "" || t == AutoFirewall {
+		t = detect()
+		log.Debugf("detected %s firewall backend", t)
+	}
+	initFn, ok := Firewalls[t]
+	if !ok {
+		return nil, fmt.Errorf("unknown firewall type %q", t)
+	}
+	return initFn()
+}
+
+// detect returns the firewall backend available on the host,
+// iptables is preferred as docker manages its rules with it,
+// nftables is used on the hosts without iptables
+func detect() string {
+	if _, err := exec.LookPath("iptables"); err == nil {
+		return IPTablesFirewall
+	}
+	if _, err := exec.LookPath("nft"); err == nil {
+		return NFTablesFirewall
+	}
+	log.Warn("neither iptables nor nft found, firewall rules for the management network are not installed")
+	return NoneFirewall
+}
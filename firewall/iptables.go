@@ -0,0 +1,60 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package firewall
+
+import (
+	"github.com/coreos/go-iptables/iptables"
+	log "github.com/sirupsen/logrus"
+)
+
+const filterTable = "filter"
+
+func init() {
+	Register(IPTablesFirewall, func() (Firewall, error) {
+		ipt, err := iptables.New()
+		if err != nil {
+			return nil, err
+		}
+		return &iptablesFirewall{ipt: ipt}, nil
+	})
+}
+
+// iptablesFirewall manages the rules with iptables
+type iptablesFirewall struct {
+	ipt *iptables.IPTables
+}
+
+func (*iptablesFirewall) Name() string { return IPTablesFirewall }
+
+func forwardingRule(bridge string) []string {
+	return []string{"-o", bridge, "-m", "comment", "--comment", RuleComment, "-j", "ACCEPT"}
+}
+
+func (f *iptablesFirewall) InstallForwardingRules(bridge string) error {
+	ok, err := f.ipt.ChainExists(filterTable, DockerUserChain)
+	if err != nil {
+		return err
+	}
+	// the chain is missing when docker doesn't manage iptables
+	if !ok {
+		log.Debugf("%s chain doesn't exist, skipping forwarding rules installation", DockerUserChain)
+		return nil
+	}
+	rule := forwardingRule(bridge)
+	if ok, err := f.ipt.Exists(filterTable, DockerUserChain, rule...); err != nil || ok {
+		return err
+	}
+	log.Debugf("installing iptables forwarding rule for the %s bridge", bridge)
+	return f.ipt.Insert(filterTable, DockerUserChain, 1, rule...)
+}
+
+func (f *iptablesFirewall) DeleteForwardingRules(bridge string) error {
+	ok, err := f.ipt.ChainExists(filterTable, DockerUserChain)
+	if err != nil || !ok {
+		return err
+	}
+	log.Debugf("removing iptables forwarding rule for the %s bridge", bridge)
+	return f.ipt.DeleteIfExists(filterTable, DockerUserChain, forwardingRule(bridge)...)
+}
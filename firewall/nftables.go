@@ -0,0 +1,106 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package firewall
+
+import (
+	"bufio"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// nftables rules are managed in the chain docker creates in the ip filter table
+var nftChain = []string{"ip", "filter", DockerUserChain}
+
+func init() {
+	Register(NFTablesFirewall, func() (Firewall, error) {
+		p, err := exec.LookPath("nft")
+		if err != nil {
+			return nil, err
+		}
+		return &nftablesFirewall{path: p}, nil
+	})
+}
+
+// nftablesFirewall manages the rules with the nft tool
+type nftablesFirewall struct {
+	path string
+}
+
+func (*nftablesFirewall) Name() string { return NFTablesFirewall }
+
+func (f *nftablesFirewall) run(args ...string) (string, error) {
+	out, err := exec.Command(f.path, args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("nft %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}
+
+// listChain returns the rules of the chain with their handles,
+// ok is false when the chain doesn't exist
+func (f *nftablesFirewall) listChain() (rules string, ok bool) {
+	out, err := f.run(append([]string{"-a", "list", "chain"}, nftChain...)...)
+	if err != nil {
+		log.Debugf("failed to list nftables chain %s: %v", DockerUserChain, err)
+		return "", false
+	}
+	return out, true
+}
+
+func (f *nftablesFirewall) InstallForwardingRules(bridge string) error {
+	rules, ok := f.listChain()
+	// the chain is missing when docker doesn't manage the firewall
+	if !ok {
+		log.Debugf("%s chain doesn't exist, skipping forwarding rules installation", DockerUserChain)
+		return nil
+	}
+	if len(nftRuleHandles(rules, bridge)) > 0 {
+		return nil
+	}
+	log.Debugf("installing nftables forwarding rule for the %s bridge", bridge)
+	args := append([]string{"insert", "rule"}, nftChain...)
+	args = append(args, "oifname", fmt.Sprintf("%q", bridge), "counter", "accept", "comment", fmt.Sprintf("%q", RuleComment))
+	_, err := f.run(args...)
+	return err
+}
+
+func (f *nftablesFirewall) DeleteForwardingRules(bridge string) error {
+	rules, ok := f.listChain()
+	if !ok {
+		return nil
+	}
+	for _, h := range nftRuleHandles(rules, bridge) {
+		log.Debugf("removing nftables forwarding rule for the %s bridge", bridge)
+		args := append([]string{"delete", "rule"}, nftChain...)
+		if _, err := f.run(append(args, "handle", h)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var nftHandleRe = regexp.MustCompile(`# handle (\d+)\s*$`)
+
+// nftRuleHandles returns the handles of the containerlab rules for the bridge
+// found in the `nft -a list chain` output
+func nftRuleHandles(rules, bridge string) []string {
+	var handles []string
+	oif := fmt.Sprintf("oifname %q ", bridge)
+	scanner := bufio.NewScanner(strings.NewReader(rules))
+	for scanner.Scan() {
+		l := scanner.Text()
+		if !strings.Contains(l, oif) || !strings.Contains(l, fmt.Sprintf("comment %q", RuleComment)) {
+			continue
+		}
+		if m := nftHandleRe.FindStringSubmatch(l); m != nil {
+			handles = append(handles, m[1])
+		}
+	}
+	return handles
+}
@@ -0,0 +1,48 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package firewall
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNFTRuleHandles(t *testing.T) {
+	rules := `table ip filter {
+	chain DOCKER-USER { # handle 7
+		oifname "br-0123456789ab" counter packets 10 bytes 840 accept comment "set by containerlab" # handle 21
+		oifname "br-0123456789ab" counter packets 0 bytes 0 accept # handle 20
+		oifname "br-ba9876543210" counter packets 0 bytes 0 accept comment "set by containerlab" # handle 19
+		counter packets 1510 bytes 1266120 return # handle 8
+	}
+}
+`
+	tests := map[string]struct {
+		bridge string
+		want   []string
+	}{
+		"clab_rule": {
+			bridge: "br-0123456789ab",
+			want:   []string{"21"},
+		},
+		"other_bridge": {
+			bridge: "br-ba9876543210",
+			want:   []string{"19"},
+		},
+		"no_rule": {
+			bridge: "docker0",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := nftRuleHandles(rules, tc.bridge)
+			if !cmp.Equal(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
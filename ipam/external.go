@@ -0,0 +1,116 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package ipam
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/srl-labs/containerlab/types"
+)
+
+// externalTimeout limits the time of a request to an external IPAM,
+// so that an unresponsive IPAM doesn't hang the deployment
+const externalTimeout = 30 * time.Second
+
+func init() {
+	Register(ExternalIPAM, func() IPAM {
+		return new(external)
+	})
+}
+
+// externalRequest is the body of the requests sent to an external IPAM
+type externalRequest struct {
+	Lab        string `json:"lab"`
+	Node       string `json:"node"`
+	Kind       string `json:"kind"`
+	IPv4Subnet string `json:"ipv4_subnet,omitempty"`
+	IPv6Subnet string `json:"ipv6_subnet,omitempty"`
+}
+
+// externalResponse is the body of the external IPAM reply to an allocation request
+type externalResponse struct {
+	IPv4 string `json:"ipv4,omitempty"`
+	IPv6 string `json:"ipv6,omitempty"`
+}
+
+// external IPAM delegates addresses allocation to an HTTP service,
+// such as a gateway in front of phpIPAM or NetBox.
+// Allocation is a POST request to the configured URL, release is a DELETE request.
+type external struct {
+	url    string
+	token  string
+	mgmt   *types.MgmtNet
+	client *http.Client
+}
+
+func (e *external) Init(cfg *types.IPAMConfig, mgmt *types.MgmtNet) error {
+	if cfg.URL == "" {
+		return fmt.Errorf("url is not set")
+	}
+	e.url = cfg.URL
+	e.token = cfg.Token
+	e.mgmt = mgmt
+	e.client = &http.Client{Timeout: externalTimeout}
+	return nil
+}
+
+func (e *external) Allocate(ctx context.Context, labName string, node *types.NodeConfig) (string, string, error) {
+	body, err := e.do(ctx, http.MethodPost, labName, node)
+	if err != nil {
+		return "", "", err
+	}
+	resp := new(externalResponse)
+	if err := json.Unmarshal(body, resp); err != nil {
+		return "", "", fmt.Errorf("failed to parse IPAM response for node %q: %v", node.ShortName, err)
+	}
+	return resp.IPv4, resp.IPv6, nil
+}
+
+func (e *external) Release(ctx context.Context, labName string, node *types.NodeConfig) error {
+	_, err := e.do(ctx, http.MethodDelete, labName, node)
+	return err
+}
+
+func (e *external) do(ctx context.Context, method, labName string, node *types.NodeConfig) ([]byte, error) {
+	b, err := json.Marshal(&externalRequest{
+		Lab:        labName,
+		Node:       node.ShortName,
+		Kind:       node.Kind,
+		IPv4Subnet: e.mgmt.IPv4Subnet,
+		IPv6Subnet: e.mgmt.IPv6Subnet,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, e.url, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.token != "" {
+		req.Header.Set("Authorization", "Bearer "+e.token)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("IPAM %s request for node %q failed with status %s: %s",
+			strings.ToLower(method), node.ShortName, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package ipam
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestExternalAllocate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := new(externalRequest)
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(&externalResponse{IPv4: "172.20.20.10", IPv6: "2001:172:20:20::10"})
+	}))
+	defer srv.Close()
+
+	i, err := New(&types.IPAMConfig{Type: ExternalIPAM, URL: srv.URL, Token: "secret"}, &types.MgmtNet{IPv4Subnet: "172.20.20.0/24"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := i.(*external).client; c.Timeout != externalTimeout {
+		t.Errorf("got client timeout %s, want %s", c.Timeout, externalTimeout)
+	}
+	v4, v6, err := i.Allocate(context.Background(), "lab1", &types.NodeConfig{ShortName: "node1", Kind: "linux"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff([]string{"172.20.20.10", "2001:172:20:20::10"}, []string{v4, v6}); d != "" {
+		t.Errorf("addresses mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestExternalTimeout(t *testing.T) {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	i, err := New(&types.IPAMConfig{Type: ExternalIPAM, URL: srv.URL}, &types.MgmtNet{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	i.(*external).client.Timeout = 50 * time.Millisecond
+	if _, _, err := i.Allocate(context.Background(), "lab1", &types.NodeConfig{ShortName: "node1"}); err == nil {
+		t.Fatal("wanted the timeout error of the unresponsive IPAM")
+	}
+}
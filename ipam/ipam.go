@@ -0,0 +1,58 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package ipam
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/srl-labs/containerlab/types"
+)
+
+const (
+	SequentialIPAM = "sequential"
+	StaticIPAM     = "static"
+	ExternalIPAM   = "external"
+)
+
+// IPAM allocates management addresses for the lab nodes
+type IPAM interface {
+	// Init initializes the IPAM with its configuration and the management network parameters
+	Init(*types.IPAMConfig, *types.MgmtNet) error
+	// Allocate returns IPv4 and IPv6 management addresses for a node of a lab
+	// empty addresses are returned when the addresses are to be assigned by the container runtime
+	Allocate(ctx context.Context, labName string, node *types.NodeConfig) (ipv4, ipv6 string, err error)
+	// Release frees the addresses allocated for a node of a lab
+	Release(ctx context.Context, labName string, node *types.NodeConfig) error
+}
+
+type Initializer func() IPAM
+
+var IPAMs = map[string]Initializer{}
+
+func Register(name string, initFn Initializer) {
+	IPAMs[name] = initFn
+}
+
+// New returns an initialized IPAM of the type set in the config
+// sequential IPAM is used when config is nil or its type is not set
+func New(cfg *types.IPAMConfig, mgmt *types.MgmtNet) (IPAM, error) {
+	if cfg == nil {
+		cfg = new(types.IPAMConfig)
+	}
+	t := cfg.Type
+	if t == "" {
+		t = SequentialIPAM
+	}
+	initFn, ok := IPAMs[t]
+	if !ok {
+		return nil, fmt.Errorf("unknown IPAM type %q", t)
+	}
+	i := initFn()
+	if err := i.Init(cfg, mgmt); err != nil {
+		return nil, fmt.Errorf("failed to initialize %s IPAM: %v", t, err)
+	}
+	return i, nil
+}
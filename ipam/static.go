@@ -0,0 +1,68 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package ipam
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+
+	"github.com/srl-labs/containerlab/types"
+	"gopkg.in/yaml.v2"
+)
+
+func init() {
+	Register(StaticIPAM, func() IPAM {
+		return new(static)
+	})
+}
+
+// staticEntry is a node's record in the static addresses file
+type staticEntry struct {
+	IPv4 string `yaml:"ipv4,omitempty"`
+	IPv6 string `yaml:"ipv6,omitempty"`
+}
+
+// static IPAM allocates addresses from a file mapping node names to addresses
+type static struct {
+	entries map[string]*staticEntry
+}
+
+func (s *static) Init(cfg *types.IPAMConfig, _ *types.MgmtNet) error {
+	if cfg.File == "" {
+		return fmt.Errorf("addresses file is not set")
+	}
+	b, err := ioutil.ReadFile(cfg.File)
+	if err != nil {
+		return err
+	}
+	s.entries = map[string]*staticEntry{}
+	if err := yaml.UnmarshalStrict(b, &s.entries); err != nil {
+		return fmt.Errorf("failed to parse addresses file %s: %v", cfg.File, err)
+	}
+	for name, e := range s.entries {
+		if e == nil {
+			continue
+		}
+		if e.IPv4 != "" && net.ParseIP(e.IPv4).To4() == nil {
+			return fmt.Errorf("node %q has invalid IPv4 address %q", name, e.IPv4)
+		}
+		if e.IPv6 != "" && net.ParseIP(e.IPv6) == nil {
+			return fmt.Errorf("node %q has invalid IPv6 address %q", name, e.IPv6)
+		}
+	}
+	return nil
+}
+
+func (s *static) Allocate(_ context.Context, _ string, node *types.NodeConfig) (string, string, error) {
+	e, ok := s.entries[node.ShortName]
+	if !ok || e == nil {
+		return "", "", fmt.Errorf("no addresses found for node %q", node.ShortName)
+	}
+	return e.IPv4, e.IPv6, nil
+}
+
+func (*static) Release(context.Context, string, *types.NodeConfig) error { return nil }
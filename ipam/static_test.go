@@ -0,0 +1,57 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package ipam
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestStaticAllocate(t *testing.T) {
+	tests := map[string]struct {
+		node    string
+		want    []string
+		wantErr bool
+	}{
+		"dual-stack": {
+			node: "node1",
+			want: []string{"172.100.100.11", "2001:172:100:100::11"},
+		},
+		"ipv4-only": {
+			node: "node2",
+			want: []string{"172.100.100.12", ""},
+		},
+		"missing-node": {
+			node:    "node3",
+			wantErr: true,
+		},
+	}
+
+	i, err := New(&types.IPAMConfig{Type: StaticIPAM, File: "test_data/addresses.yml"}, &types.MgmtNet{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			v4, v6, err := i.Allocate(context.Background(), "test", &types.NodeConfig{ShortName: tc.node})
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error for node %q", tc.node)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff([]string{v4, v6}, tc.want); d != "" {
+				t.Fatalf("addresses do not match %s", d)
+			}
+		})
+	}
+}
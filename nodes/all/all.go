@@ -9,6 +9,7 @@ import (
 	_ "github.com/srl-labs/containerlab/nodes/ceos"
 	_ "github.com/srl-labs/containerlab/nodes/crpd"
 	_ "github.com/srl-labs/containerlab/nodes/cvx"
+	_ "github.com/srl-labs/containerlab/nodes/generic_vm"
 	_ "github.com/srl-labs/containerlab/nodes/host"
 	_ "github.com/srl-labs/containerlab/nodes/linux"
 	_ "github.com/srl-labs/containerlab/nodes/mysocketio"
@@ -16,14 +17,15 @@ import (
 	_ "github.com/srl-labs/containerlab/nodes/sonic"
 	_ "github.com/srl-labs/containerlab/nodes/srl"
 	_ "github.com/srl-labs/containerlab/nodes/vr_csr"
+	_ "github.com/srl-labs/containerlab/nodes/vr_ftosv"
+	_ "github.com/srl-labs/containerlab/nodes/vr_n9kv"
 	_ "github.com/srl-labs/containerlab/nodes/vr_nxos"
 	_ "github.com/srl-labs/containerlab/nodes/vr_pan"
-	_ "github.com/srl-labs/containerlab/nodes/vr_n9kv"
-	_ "github.com/srl-labs/containerlab/nodes/vr_ftosv"
 	_ "github.com/srl-labs/containerlab/nodes/vr_ros"
 	_ "github.com/srl-labs/containerlab/nodes/vr_sros"
 	_ "github.com/srl-labs/containerlab/nodes/vr_veos"
 	_ "github.com/srl-labs/containerlab/nodes/vr_vmx"
+	_ "github.com/srl-labs/containerlab/nodes/vr_vqfx"
 	_ "github.com/srl-labs/containerlab/nodes/vr_xrv"
 	_ "github.com/srl-labs/containerlab/nodes/vr_xrv9k"
 )
@@ -6,10 +6,13 @@ package bridge
 
 import (
 	"context"
+	"fmt"
 
+	log "github.com/sirupsen/logrus"
 	"github.com/srl-labs/containerlab/nodes"
 	"github.com/srl-labs/containerlab/runtime"
 	"github.com/srl-labs/containerlab/types"
+	"github.com/vishvananda/netlink"
 )
 
 func init() {
@@ -28,12 +31,40 @@ func (s *bridge) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 	for _, o := range opts {
 		o(s)
 	}
-	s.cfg.DeploymentStatus = "created" // since we do not create bridges with clab, the status is implied here
+	// the status of the pre-existing bridge is implied, the bridge created by clab
+	// gets its status once deployed, so that the links are not wired before the bridge exists
+	if !s.cfg.Bridge.GetCreate() {
+		s.cfg.DeploymentStatus = "created"
+	}
 	return nil
 }
 func (s *bridge) Config() *types.NodeConfig                              { return s.cfg }
 func (s *bridge) PreDeploy(configName, labCADir, labCARoot string) error { return nil }
-func (s *bridge) Deploy(ctx context.Context) error                       { return nil }
+
+// Deploy creates the bridge if it is set to be created and doesn't exist yet
+func (s *bridge) Deploy(ctx context.Context) error {
+	if !s.cfg.Bridge.GetCreate() {
+		return nil
+	}
+	if _, err := netlink.LinkByName(s.cfg.ShortName); err == nil {
+		log.Debugf("bridge %s already exists", s.cfg.ShortName)
+		return nil
+	}
+	log.Infof("Creating bridge %s", s.cfg.ShortName)
+	vlanFiltering := s.cfg.Bridge.VLANFiltering
+	br := &netlink.Bridge{
+		LinkAttrs:     netlink.LinkAttrs{Name: s.cfg.ShortName},
+		VlanFiltering: &vlanFiltering,
+	}
+	if err := netlink.LinkAdd(br); err != nil {
+		return fmt.Errorf("failed to create bridge %s: %v", s.cfg.ShortName, err)
+	}
+	// the alias marks the bridge as created by the lab, so that it is deleted on destroy
+	if err := netlink.LinkSetAlias(br, s.cfg.LongName); err != nil {
+		return err
+	}
+	return netlink.LinkSetUp(br)
+}
 func (s *bridge) PostDeploy(ctx context.Context, ns map[string]nodes.Node) error {
 	return nil
 }
@@ -49,6 +80,15 @@ func (s *bridge) SaveConfig(ctx context.Context) error { return nil }
 
 func (s *bridge) GetImages() map[string]string { return map[string]string{} }
 
+// Delete deletes the bridge created by the lab, the pre-existing bridges are left intact
 func (s *bridge) Delete(ctx context.Context) error {
-	return nil
+	if !s.cfg.Bridge.GetCreate() {
+		return nil
+	}
+	br, err := netlink.LinkByName(s.cfg.ShortName)
+	if err != nil || br.Attrs().Alias != s.cfg.LongName {
+		return nil
+	}
+	log.Infof("Deleting bridge %s", s.cfg.ShortName)
+	return netlink.LinkDel(br)
 }
@@ -16,6 +16,7 @@ import (
 	"strings"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/cert"
 	"github.com/srl-labs/containerlab/nodes"
 	"github.com/srl-labs/containerlab/runtime"
 	"github.com/srl-labs/containerlab/types"
@@ -77,9 +78,19 @@ func (s *ceos) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 
 func (s *ceos) Config() *types.NodeConfig { return s.cfg }
 
-func (s *ceos) PreDeploy(_, _, _ string) error {
+func (s *ceos) PreDeploy(configName, labCADir, labCARoot string) error {
 	utils.CreateDirectory(s.cfg.LabDir, 0777)
-	return createCEOSFiles(s.cfg)
+	if err := createCEOSFiles(s.cfg); err != nil {
+		return err
+	}
+	if s.cfg.TLS {
+		// flash dir is mounted to /mnt/flash, making the files available as flash:tls/
+		tlsDir := filepath.Join(s.cfg.LabDir, "flash", "tls")
+		if err := cert.InstallNodeCerts(s.cfg, configName, labCADir, labCARoot, tlsDir); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (s *ceos) Deploy(ctx context.Context) error {
@@ -96,6 +107,15 @@ func (*ceos) WithMgmtNet(*types.MgmtNet)               {}
 func (s *ceos) WithRuntime(r runtime.ContainerRuntime) { s.runtime = r }
 func (s *ceos) GetRuntime() runtime.ContainerRuntime   { return s.runtime }
 
+// ReloadCerts installs the renewed node certificate to the TLS directory mounted to the running node
+func (s *ceos) ReloadCerts(_ context.Context, configName, labCADir, labCARoot string) error {
+	if err := cert.InstallNodeCerts(s.cfg, configName, labCADir, labCARoot, filepath.Join(s.cfg.LabDir, "flash", "tls")); err != nil {
+		return err
+	}
+	log.Infof("installed renewed TLS certificate to %s node", s.cfg.ShortName)
+	return nil
+}
+
 func (s *ceos) SaveConfig(ctx context.Context) error {
 	_, stderr, err := s.runtime.Exec(ctx, s.cfg.LongName, saveCmd)
 	if err != nil {
@@ -14,6 +14,7 @@ import (
 	"path/filepath"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/cert"
 	"github.com/srl-labs/containerlab/nodes"
 	"github.com/srl-labs/containerlab/runtime"
 	"github.com/srl-labs/containerlab/types"
@@ -61,7 +62,17 @@ func (s *crpd) Config() *types.NodeConfig { return s.cfg }
 
 func (s *crpd) PreDeploy(configName, labCADir, labCARoot string) error {
 	utils.CreateDirectory(s.cfg.LabDir, 0777)
-	return createCRPDFiles(s.cfg)
+	if err := createCRPDFiles(s.cfg); err != nil {
+		return err
+	}
+	if s.cfg.TLS {
+		// config dir is mounted to /config
+		tlsDir := path.Join(s.cfg.LabDir, "config", "tls")
+		if err := cert.InstallNodeCerts(s.cfg, configName, labCADir, labCARoot, tlsDir); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (s *crpd) Deploy(ctx context.Context) error {
@@ -97,6 +108,15 @@ func (s *crpd) Delete(ctx context.Context) error {
 	return s.runtime.DeleteContainer(ctx, s.Config().LongName)
 }
 
+// ReloadCerts installs the renewed node certificate to the TLS directory mounted to the running node
+func (s *crpd) ReloadCerts(_ context.Context, configName, labCADir, labCARoot string) error {
+	if err := cert.InstallNodeCerts(s.cfg, configName, labCADir, labCARoot, path.Join(s.cfg.LabDir, "config", "tls")); err != nil {
+		return err
+	}
+	log.Infof("installed renewed TLS certificate to %s node", s.cfg.ShortName)
+	return nil
+}
+
 func (s *crpd) SaveConfig(ctx context.Context) error {
 	stdout, stderr, err := s.runtime.Exec(ctx, s.cfg.LongName, saveCmd)
 	if err != nil {
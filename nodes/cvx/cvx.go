@@ -8,6 +8,7 @@ import (
 	"github.com/srl-labs/containerlab/nodes"
 	"github.com/srl-labs/containerlab/runtime"
 	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
 	meta "github.com/weaveworks/ignite/pkg/apis/meta/v1alpha1"
 	"github.com/weaveworks/ignite/pkg/operations"
 )
@@ -17,6 +18,10 @@ var (
 	defaultIgniteSandboxImage = "networkop/ignite:dev"
 )
 
+// cvxEnv lets systemd running as the init process of the cvx image
+// detect the container environment when the node runs in the container runtime
+var cvxEnv = map[string]string{"container": "docker"}
+
 var memoryReqs = map[string]string{
 	"4.3.0": "512MB",
 	"4.4.0": "768MB",
@@ -40,6 +45,10 @@ func (c *cvx) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 		o(c)
 	}
 
+	if c.runtime.GetName() != runtime.IgniteRuntime {
+		c.cfg.Env = utils.MergeStringMaps(cvxEnv, c.cfg.Env)
+	}
+
 	if c.cfg.Kernel == "" {
 		c.cfg.Kernel = defaultCvxKernelImageRef
 	}
@@ -0,0 +1,147 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package generic_vm
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/srl-labs/containerlab/cert"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// env vars the generic_vm settings are passed to the VM launcher with
+const (
+	envQemuArgs    = "QEMU_ADDITIONAL_ARGS"
+	envNICs        = "NUM_NICS"
+	envConsolePort = "CONSOLE_PORT"
+)
+
+func init() {
+	nodes.Register(nodes.NodeKindGenericVM, func() nodes.Node {
+		return new(genericVM)
+	})
+}
+
+type genericVM struct {
+	cfg     *types.NodeConfig
+	mgmt    *types.MgmtNet
+	runtime runtime.ContainerRuntime
+}
+
+func (s *genericVM) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
+	s.cfg = cfg
+	for _, o := range opts {
+		o(s)
+	}
+	// env vars are used to set launch.py arguments in vrnetlab container
+	defEnv := map[string]string{
+		"USERNAME":           "admin",
+		"PASSWORD":           "admin",
+		"CONNECTION_MODE":    nodes.VrDefConnMode,
+		"DOCKER_NET_V4_ADDR": s.mgmt.IPv4Subnet,
+		"DOCKER_NET_V6_ADDR": s.mgmt.IPv6Subnet,
+	}
+	s.cfg.Env = utils.MergeStringMaps(defEnv, launcherEnv(s.cfg.Extras.GetGenericVM()), s.cfg.Env)
+	nodes.VrApplyVM(s.cfg)
+
+	if s.cfg.Env["CONNECTION_MODE"] == "macvtap" {
+		// mount dev dir to enable macvtap
+		s.cfg.Binds = append(s.cfg.Binds, "/dev:/dev")
+	}
+
+	s.cfg.Cmd = fmt.Sprintf("--username %s --password %s --hostname %s --connection-mode %s --trace",
+		s.cfg.Env["USERNAME"], s.cfg.Env["PASSWORD"], s.cfg.ShortName, s.cfg.Env["CONNECTION_MODE"])
+
+	nodes.VrAddTLSBind(s.cfg)
+	nodes.VrAddStartupConfigBind(s.cfg)
+	return nil
+}
+
+func (s *genericVM) Config() *types.NodeConfig { return s.cfg }
+
+func (s *genericVM) PreDeploy(configName, labCADir, labCARoot string) error {
+	if err := checkNICs(s.cfg.Extras.GetGenericVM(), len(s.cfg.Endpoints)); err != nil {
+		return fmt.Errorf("node %q: %v", s.cfg.ShortName, err)
+	}
+	utils.CreateDirectory(s.cfg.LabDir, 0777)
+	if s.cfg.TLS {
+		if err := cert.InstallNodeCerts(s.cfg, configName, labCADir, labCARoot, nodes.VrTLSDir(s.cfg)); err != nil {
+			return err
+		}
+	}
+	return nodes.VrGenerateStartupConfig(s.cfg)
+}
+
+func (s *genericVM) Deploy(ctx context.Context) error {
+	_, err := s.runtime.CreateContainer(ctx, s.cfg)
+	return err
+}
+
+func (s *genericVM) GetImages() map[string]string {
+	return map[string]string{
+		nodes.ImageKey: s.cfg.Image,
+	}
+}
+
+func (*genericVM) PostDeploy(_ context.Context, _ map[string]nodes.Node) error {
+	return nil
+}
+
+func (s *genericVM) WithMgmtNet(mgmt *types.MgmtNet)        { s.mgmt = mgmt }
+func (s *genericVM) WithRuntime(r runtime.ContainerRuntime) { s.runtime = r }
+func (s *genericVM) GetRuntime() runtime.ContainerRuntime   { return s.runtime }
+
+func (s *genericVM) Ready(ctx context.Context) (bool, error) {
+	return nodes.VrReady(ctx, s.runtime, s.cfg.LongName)
+}
+
+func (s *genericVM) Shutdown(ctx context.Context) error {
+	return nodes.VrShutdown(ctx, s.runtime, s.cfg.LongName)
+}
+
+func (s *genericVM) Delete(ctx context.Context) error {
+	return s.runtime.DeleteContainer(ctx, s.Config().LongName)
+}
+
+func (*genericVM) SaveConfig(_ context.Context) error {
+	return nil
+}
+
+// launcherEnv returns the env vars passing the generic_vm settings to the VM launcher
+func launcherEnv(vm *types.GenericVM) map[string]string {
+	env := map[string]string{}
+	if vm == nil {
+		return env
+	}
+	if vm.QemuArgs != "" {
+		env[envQemuArgs] = vm.QemuArgs
+	}
+	if vm.NICs != 0 {
+		env[envNICs] = strconv.Itoa(vm.NICs)
+	}
+	if vm.ConsolePort != 0 {
+		env[envConsolePort] = strconv.Itoa(vm.ConsolePort)
+	}
+	return env
+}
+
+// checkNICs verifies that the VM has enough NICs for the links of the node
+func checkNICs(vm *types.GenericVM, links int) error {
+	if vm == nil || vm.NICs == 0 {
+		return nil
+	}
+	if vm.NICs < 0 {
+		return fmt.Errorf("invalid number of NICs %d", vm.NICs)
+	}
+	if links > vm.NICs {
+		return fmt.Errorf("node has %d links, but its VM has %d NICs only", links, vm.NICs)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package generic_vm
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestLauncherEnv(t *testing.T) {
+	tests := map[string]struct {
+		vm   *types.GenericVM
+		want map[string]string
+	}{
+		"no-settings": {
+			want: map[string]string{},
+		},
+		"all-settings": {
+			vm: &types.GenericVM{QemuArgs: "-cpu host -machine q35", NICs: 8, ConsolePort: 5001},
+			want: map[string]string{
+				"QEMU_ADDITIONAL_ARGS": "-cpu host -machine q35",
+				"NUM_NICS":             "8",
+				"CONSOLE_PORT":         "5001",
+			},
+		},
+		"nics-only": {
+			vm:   &types.GenericVM{NICs: 4},
+			want: map[string]string{"NUM_NICS": "4"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if d := cmp.Diff(tc.want, launcherEnv(tc.vm)); d != "" {
+				t.Errorf("env mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestCheckNICs(t *testing.T) {
+	tests := map[string]struct {
+		vm      *types.GenericVM
+		links   int
+		wantErr bool
+	}{
+		"nics-not-set": {links: 16},
+		"enough-nics":  {vm: &types.GenericVM{NICs: 4}, links: 4},
+		"too-many-links": {
+			vm:      &types.GenericVM{NICs: 2},
+			links:   3,
+			wantErr: true,
+		},
+		"negative-nics": {
+			vm:      &types.GenericVM{NICs: -1},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := checkNICs(tc.vm, tc.links); (err != nil) != tc.wantErr {
+				t.Errorf("got error %v, want error %v", err, tc.wantErr)
+			}
+		})
+	}
+}
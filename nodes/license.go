@@ -0,0 +1,224 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package nodes
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/types"
+	"golang.org/x/crypto/ssh"
+)
+
+// license delivery modes
+const (
+	// the kind installs the license file itself, e.g. srl copies it to the lab directory of the node
+	LicenseByKind = "kind"
+	// the license file is bind-mounted to the container
+	LicenseMount = "mount"
+	// the license file is bind-mounted to the vrnetlab container and passed to launch.py with a flag
+	LicenseFlag = "flag"
+	// the license file is copied to the VM over SCP once the node has booted
+	LicenseSCP = "scp"
+)
+
+// DefaultLicensePath is the path the license file is mounted at in the containers of the kinds
+// without a license delivery of their own
+const DefaultLicensePath = "/etc/clab/license"
+
+// LicenseEnv is the env var holding the path of the mounted license file in the container
+const LicenseEnv = "CLAB_LICENSE"
+
+// LicenseDelivery defines how the license file of a node is delivered to its NOS
+type LicenseDelivery struct {
+	// one of the License* modes
+	Mode string
+	// path the license file is mounted at in the container or copied to on the VM
+	Path string
+	// launch.py flag taking the path of the mounted license file
+	Flag string
+}
+
+// LicenseDeliveries holds the license delivery per kind. The container kinds not listed here
+// get the license mounted at DefaultLicensePath, the vrnetlab kinds not listed here don't support the license
+var LicenseDeliveries = map[string]*LicenseDelivery{
+	NodeKindSRL:       {Mode: LicenseByKind},
+	NodeKindCRPD:      {Mode: LicenseByKind},
+	NodeKindVrSROS:    {Mode: LicenseByKind},
+	NodeKindVrCSR:     {Mode: LicenseFlag, Path: "/license.lic", Flag: "--license"},
+	NodeKindVrXRV9K:   {Mode: LicenseFlag, Path: "/license.lic", Flag: "--license"},
+	NodeKindVrVMX:     {Mode: LicenseSCP, Path: "/var/tmp/license.lic"},
+	NodeKindVrVQFX:    {Mode: LicenseSCP, Path: "/var/tmp/license.lic"},
+	NodeKindVrN9KV:    {Mode: LicenseSCP, Path: "bootflash:license.lic"},
+	NodeKindGenericVM: {Mode: LicenseSCP, Path: "license.lic"},
+}
+
+// LicenseDeliveryOf returns the license delivery of the kind
+func LicenseDeliveryOf(kind string) (*LicenseDelivery, error) {
+	if d, ok := LicenseDeliveries[kind]; ok {
+		return d, nil
+	}
+	if IsVrKind(kind) {
+		return nil, fmt.Errorf("license is not supported for kind %s", kind)
+	}
+	return &LicenseDelivery{Mode: LicenseMount, Path: DefaultLicensePath}, nil
+}
+
+// CheckLicense checks that the license file of the node is readable and that its kind supports the license
+func CheckLicense(cfg *types.NodeConfig) error {
+	if cfg.License == "" {
+		return nil
+	}
+	if _, err := LicenseDeliveryOf(cfg.Kind); err != nil {
+		return fmt.Errorf("node %q: %v", cfg.ShortName, err)
+	}
+	f, err := os.Open(cfg.License)
+	if err != nil {
+		return fmt.Errorf("node %q: license file is not readable: %v", cfg.ShortName, err)
+	}
+	defer f.Close()
+	if fi, err := f.Stat(); err != nil || fi.IsDir() {
+		return fmt.Errorf("node %q: license %s is not a file", cfg.ShortName, cfg.License)
+	}
+	return nil
+}
+
+// MountLicense mounts the license file of the node read-only to its container when the license is delivered
+// with a bind mount or a launch.py flag. The path of the mounted file is set to the CLAB_LICENSE env var
+// or passed with the launch.py flag, so it must be called after the kind has set the node command
+func MountLicense(cfg *types.NodeConfig) {
+	if cfg.License == "" {
+		return
+	}
+	d, err := LicenseDeliveryOf(cfg.Kind)
+	if err != nil {
+		return
+	}
+	switch d.Mode {
+	case LicenseMount:
+		if cfg.Env == nil {
+			cfg.Env = map[string]string{}
+		}
+		cfg.Env[LicenseEnv] = d.Path
+	case LicenseFlag:
+		cfg.Cmd = strings.TrimSpace(cfg.Cmd + " " + d.Flag + " " + d.Path)
+	default:
+		return
+	}
+	cfg.Binds = append(cfg.Binds, cfg.License+":"+d.Path+":ro")
+}
+
+// CopyLicense copies the license file of the node to its VM over SCP, retrying every poll interval
+// until the VM accepts the file or ctx is done. The nodes of the other license delivery modes are skipped
+func CopyLicense(ctx context.Context, cfg *types.NodeConfig, poll time.Duration) error {
+	if cfg.License == "" {
+		return nil
+	}
+	d, err := LicenseDeliveryOf(cfg.Kind)
+	if err != nil || d.Mode != LicenseSCP {
+		return err
+	}
+	b, err := ioutil.ReadFile(cfg.License)
+	if err != nil {
+		return fmt.Errorf("%s: %v", cfg.ShortName, err)
+	}
+	creds := SaveCredentials(cfg)
+	addr := net.JoinHostPort(cfg.MgmtIPv4Address, "22")
+	for {
+		err := scpFile(ctx, addr, creds, d.Path, b)
+		if err == nil {
+			return nil
+		}
+		log.Debugf("%s: license copy failed, retrying: %v", cfg.ShortName, err)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s: license copy failed: %v, last error: %v", cfg.ShortName, ctx.Err(), err)
+		case <-time.After(poll):
+		}
+	}
+}
+
+// scpFile writes the file contents to the path on the ssh server with the scp sink protocol
+func scpFile(ctx context.Context, addr string, creds *types.Credentials, dst string, data []byte) error {
+	conf := &ssh.ClientConfig{
+		User: creds.Username,
+		Auth: []ssh.AuthMethod{
+			ssh.Password(creds.Password),
+			ssh.KeyboardInteractive(func(_, _ string, questions []string, _ []bool) ([]string, error) {
+				answers := make([]string, len(questions))
+				for i := range answers {
+					answers[i] = creds.Password
+				}
+				return answers, nil
+			}),
+		},
+		// the lab nodes get new host keys with every deployment
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         10 * time.Second,
+	}
+	client, err := ssh.Dial("tcp", addr, conf)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	go func() {
+		<-ctx.Done()
+		client.Close()
+	}()
+	sess, err := client.NewSession()
+	if err != nil {
+		return err
+	}
+	defer sess.Close()
+	stdin, err := sess.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := sess.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := sess.Start("scp -t " + dst); err != nil {
+		return err
+	}
+	if err := scpSend(stdin, stdout, path.Base(dst), data); err != nil {
+		return err
+	}
+	stdin.Close()
+	return sess.Wait()
+}
+
+// scpSend sends the file with the scp sink protocol, every message is acknowledged by the sink with a zero byte
+func scpSend(w io.Writer, r io.Reader, name string, data []byte) error {
+	ack := func() error {
+		b := make([]byte, 1)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return err
+		}
+		if b[0] != 0 {
+			msg, _ := ioutil.ReadAll(io.LimitReader(r, 1024))
+			return fmt.Errorf("scp: %s", msg)
+		}
+		return nil
+	}
+	if _, err := fmt.Fprintf(w, "C0644 %d %s\n", len(data), name); err != nil {
+		return err
+	}
+	if err := ack(); err != nil {
+		return err
+	}
+	if _, err := w.Write(append(data, 0)); err != nil {
+		return err
+	}
+	return ack()
+}
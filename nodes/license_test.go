@@ -0,0 +1,137 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package nodes
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestCheckLicense(t *testing.T) {
+	dir := t.TempDir()
+	lic := filepath.Join(dir, "license.txt")
+	if err := ioutil.WriteFile(lic, []byte("key"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		cfg     *types.NodeConfig
+		wantErr string
+	}{
+		"no-license": {
+			cfg: &types.NodeConfig{ShortName: "n1", Kind: NodeKindVrXRV},
+		},
+		"readable": {
+			cfg: &types.NodeConfig{ShortName: "n1", Kind: NodeKindLinux, License: lic},
+		},
+		"missing": {
+			cfg:     &types.NodeConfig{ShortName: "n1", Kind: NodeKindSRL, License: filepath.Join(dir, "missing")},
+			wantErr: "license file is not readable",
+		},
+		"directory": {
+			cfg:     &types.NodeConfig{ShortName: "n1", Kind: NodeKindSRL, License: dir},
+			wantErr: "is not a file",
+		},
+		"unsupported-kind": {
+			cfg:     &types.NodeConfig{ShortName: "n1", Kind: NodeKindVrXRV, License: lic},
+			wantErr: "license is not supported for kind vr-xrv",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := CheckLicense(tc.cfg)
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+				t.Errorf("got error: %v, want error containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestMountLicense(t *testing.T) {
+	tests := map[string]struct {
+		cfg  *types.NodeConfig
+		want *types.NodeConfig
+	}{
+		"mount": {
+			cfg: &types.NodeConfig{Kind: NodeKindLinux, License: "/lic.txt"},
+			want: &types.NodeConfig{Kind: NodeKindLinux, License: "/lic.txt",
+				Binds: []string{"/lic.txt:/etc/clab/license:ro"},
+				Env:   map[string]string{"CLAB_LICENSE": "/etc/clab/license"},
+			},
+		},
+		"flag": {
+			cfg: &types.NodeConfig{Kind: NodeKindVrCSR, License: "/lic.txt", Cmd: "--trace"},
+			want: &types.NodeConfig{Kind: NodeKindVrCSR, License: "/lic.txt",
+				Cmd:   "--trace --license /license.lic",
+				Binds: []string{"/lic.txt:/license.lic:ro"},
+			},
+		},
+		"by-kind": {
+			cfg:  &types.NodeConfig{Kind: NodeKindSRL, License: "/lic.txt"},
+			want: &types.NodeConfig{Kind: NodeKindSRL, License: "/lic.txt"},
+		},
+		"scp": {
+			cfg:  &types.NodeConfig{Kind: NodeKindVrVMX, License: "/lic.txt"},
+			want: &types.NodeConfig{Kind: NodeKindVrVMX, License: "/lic.txt"},
+		},
+		"no-license": {
+			cfg:  &types.NodeConfig{Kind: NodeKindLinux},
+			want: &types.NodeConfig{Kind: NodeKindLinux},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			MountLicense(tc.cfg)
+			if d := cmp.Diff(tc.want, tc.cfg); d != "" {
+				t.Errorf("config mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestSCPSend(t *testing.T) {
+	tests := map[string]struct {
+		acks    string
+		want    string
+		wantErr string
+	}{
+		"ok": {
+			acks: "\x00\x00",
+			want: "C0644 3 license.lic\nkey\x00",
+		},
+		"rejected": {
+			acks:    "\x01scp: /var/tmp/license.lic: Permission denied\n",
+			want:    "C0644 3 license.lic\n",
+			wantErr: "Permission denied",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var w bytes.Buffer
+			err := scpSend(&w, strings.NewReader(tc.acks), "license.lic", []byte("key"))
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+				t.Errorf("got error: %v, want error containing %q", err, tc.wantErr)
+			}
+			if w.String() != tc.want {
+				t.Errorf("got: %q, want: %q", w.String(), tc.want)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ package linux
 
 import (
 	"context"
+	"fmt"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/srl-labs/containerlab/nodes"
@@ -32,10 +33,22 @@ func (l *linux) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 
 	// make ipv6 enabled on all linux node interfaces
 	// but not for the nodes with host network mode, as this is not supported on gh action runners
-	if l.Config().NetworkMode != "host" {
+	if _, ok := cfg.Sysctls["net.ipv6.conf.all.disable_ipv6"]; !ok && l.Config().NetworkMode != "host" {
 		cfg.Sysctls["net.ipv6.conf.all.disable_ipv6"] = "0"
 	}
 
+	if len(cfg.Interfaces) > 0 && cfg.NetworkMode == "host" {
+		return fmt.Errorf("interfaces can't be configured for the nodes in host network mode")
+	}
+	for name, i := range cfg.Interfaces {
+		if i == nil {
+			continue
+		}
+		if err := i.Validate(); err != nil {
+			return fmt.Errorf("interface %s: %v", name, err)
+		}
+	}
+
 	return nil
 }
 
@@ -50,7 +63,10 @@ func (l *linux) Deploy(ctx context.Context) error {
 
 func (l *linux) PostDeploy(ctx context.Context, ns map[string]nodes.Node) error {
 	log.Debugf("Running postdeploy actions for Linux '%s' node", l.cfg.ShortName)
-	return types.DisableTxOffload(l.cfg)
+	if err := types.DisableTxOffload(l.cfg); err != nil {
+		return err
+	}
+	return types.ConfigureInterfaces(l.cfg)
 }
 
 func (s *linux) GetImages() map[string]string {
@@ -30,7 +30,7 @@ const (
 	NodeKindHOST       = "host"
 	NodeKindLinux      = "linux"
 	NodeKindMySocketIO = "mysocketio"
-	NodeKindOVS        = "bridge-ovs"
+	NodeKindOVS        = "ovs-bridge"
 	NodeKindSonic      = "sonic"
 	NodeKindSRL        = "srl"
 	NodeKindVrCSR      = "vr-csr"
@@ -41,9 +41,11 @@ const (
 	NodeKindVrSROS     = "vr-sros"
 	NodeKindVrVEOS     = "vr-veos"
 	NodeKindVrVMX      = "vr-vmx"
+	NodeKindVrVQFX     = "vr-vqfx"
 	NodeKindVrXRV      = "vr-xrv"
 	NodeKindVrXRV9K    = "vr-xrv9k"
 	NodeKindVrNXOS     = "vr-nxos"
+	NodeKindGenericVM  = "generic_vm"
 )
 
 // a map of node kinds overriding the default global runtime
@@ -51,6 +53,16 @@ var NonDefaultRuntimes = map[string]string{
 	NodeKindCVX: runtime.IgniteRuntime,
 }
 
+// forwardingEnabled is used by the interface profiles of the kinds routing in the linux kernel
+var forwardingEnabled = true
+
+// DefaultInterfaceProfiles holds the interface profiles of the kinds used
+// when no interface profile is set for the node in the topology
+var DefaultInterfaceProfiles = map[string]*types.InterfaceProfile{
+	// cRPD programs the routes to the linux kernel of the container
+	NodeKindCRPD: {Forwarding: &forwardingEnabled},
+}
+
 type Node interface {
 	Init(*types.NodeConfig, ...NodeOption) error
 	Config() *types.NodeConfig
@@ -65,6 +77,30 @@ type Node interface {
 	GetRuntime() runtime.ContainerRuntime
 }
 
+// CertReloader is implemented by the nodes able to install renewed TLS certificates while running
+type CertReloader interface {
+	ReloadCerts(ctx context.Context, configName, labCADir, labCARoot string) error
+}
+
+// CertInstaller is implemented by the nodes which NOS doesn't read the certificates mounted to the container,
+// e.g. vrnetlab nodes importing the node certificate to the PKI of the VM. InstallCerts is called once the node is ready
+type CertInstaller interface {
+	InstallCerts(ctx context.Context, configName, labCADir, labCARoot string) error
+}
+
+// ReadyChecker is implemented by the nodes which become usable some time after the container is started,
+// e.g. vrnetlab nodes booting a VM. Ready reports whether the node is ready to be used
+type ReadyChecker interface {
+	Ready(ctx context.Context) (bool, error)
+}
+
+// Shutdowner is implemented by the nodes which need to shut down the NOS before the container is removed,
+// e.g. vrnetlab nodes powering down the VM to keep its disks consistent.
+// Shutdown returns once the NOS has stopped or ctx is done
+type Shutdowner interface {
+	Shutdown(ctx context.Context) error
+}
+
 var Nodes = map[string]Initializer{}
 
 type Initializer func() Node
@@ -97,11 +133,33 @@ var DefaultConfigTemplates = map[string]string{
 
 // DefaultCredentials holds default username and password per each kind
 var DefaultCredentials = map[string][]string{
-	"srl":      {"admin", "admin"},
-	"vr-pan":  {"admin", "Admin@123"},
-	"vr-n9kv":  {"admin", "admin"},
-	"vr-ftosv":  {"admin", "admin"},
-	"vr-sros":  {"admin", "admin"},
-	"vr-vmx":   {"admin", "admin@123"},
-	"vr-xrv9k": {"clab", "clab@123"},
+	"srl":        {"admin", "admin"},
+	"vr-pan":     {"admin", "Admin@123"},
+	"vr-n9kv":    {"admin", "admin"},
+	"vr-ftosv":   {"admin", "admin"},
+	"vr-sros":    {"admin", "admin"},
+	"vr-vmx":     {"admin", "admin@123"},
+	"vr-vqfx":    {"admin", "admin@123"},
+	"vr-xrv9k":   {"clab", "clab@123"},
+	"generic_vm": {"admin", "admin"},
+}
+
+// AnsibleConnections holds the ansible_network_os and ansible_connection inventory variables per each kind
+// managed with the Ansible network collections
+var AnsibleConnections = map[string][]string{
+	NodeKindSRL:     {"nokia.srlinux.srlinux", "ansible.netcommon.httpapi"},
+	NodeKindCEOS:    {"arista.eos.eos", "ansible.netcommon.network_cli"},
+	NodeKindVrVEOS:  {"arista.eos.eos", "ansible.netcommon.network_cli"},
+	NodeKindCRPD:    {"junipernetworks.junos.junos", "ansible.netcommon.netconf"},
+	NodeKindVrVMX:   {"junipernetworks.junos.junos", "ansible.netcommon.netconf"},
+	NodeKindVrVQFX:  {"junipernetworks.junos.junos", "ansible.netcommon.netconf"},
+	NodeKindVrSROS:  {"nokia.sros.classic", "ansible.netcommon.network_cli"},
+	NodeKindVrCSR:   {"cisco.ios.ios", "ansible.netcommon.network_cli"},
+	NodeKindVrXRV:   {"cisco.iosxr.iosxr", "ansible.netcommon.network_cli"},
+	NodeKindVrXRV9K: {"cisco.iosxr.iosxr", "ansible.netcommon.network_cli"},
+	NodeKindVrNXOS:  {"cisco.nxos.nxos", "ansible.netcommon.network_cli"},
+	NodeKindVrN9KV:  {"cisco.nxos.nxos", "ansible.netcommon.network_cli"},
+	NodeKindVrROS:   {"community.routeros.routeros", "ansible.netcommon.network_cli"},
+	NodeKindVrFTOSV: {"dellemc.os10.os10", "ansible.netcommon.network_cli"},
+	NodeKindSonic:   {"dellemc.enterprise_sonic.sonic", "ansible.netcommon.network_cli"},
 }
@@ -6,10 +6,14 @@ package ovs
 
 import (
 	"context"
+	"fmt"
 
+	goOvs "github.com/digitalocean/go-openvswitch/ovs"
+	log "github.com/sirupsen/logrus"
 	"github.com/srl-labs/containerlab/nodes"
 	"github.com/srl-labs/containerlab/runtime"
 	"github.com/srl-labs/containerlab/types"
+	"github.com/vishvananda/netlink"
 )
 
 func init() {
@@ -35,7 +39,30 @@ func (l *ovs) Config() *types.NodeConfig { return l.cfg }
 
 func (l *ovs) PreDeploy(configName, labCADir, labCARoot string) error { return nil }
 
-func (l *ovs) Deploy(ctx context.Context) error { return nil }
+// Deploy creates the ovs bridge if it is set to be created and doesn't exist yet
+func (l *ovs) Deploy(ctx context.Context) error {
+	if !l.cfg.Bridge.GetCreate() {
+		return nil
+	}
+	if _, err := netlink.LinkByName(l.cfg.ShortName); err == nil {
+		log.Debugf("ovs bridge %s already exists", l.cfg.ShortName)
+		return nil
+	}
+	log.Infof("Creating ovs bridge %s", l.cfg.ShortName)
+	if err := goOvs.New(goOvs.Sudo()).VSwitch.AddBridge(l.cfg.ShortName); err != nil {
+		return fmt.Errorf("failed to create ovs bridge %s: %v", l.cfg.ShortName, err)
+	}
+	br, err := netlink.LinkByName(l.cfg.ShortName)
+	if err != nil {
+		return err
+	}
+	// the alias of the bridge internal interface marks the bridge as created by the lab,
+	// so that it is deleted on destroy
+	if err := netlink.LinkSetAlias(br, l.cfg.LongName); err != nil {
+		return err
+	}
+	return netlink.LinkSetUp(br)
+}
 
 func (l *ovs) PostDeploy(ctx context.Context, ns map[string]nodes.Node) error {
 	return nil
@@ -49,8 +76,24 @@ func (s *ovs) GetContainer(ctx context.Context) (*types.GenericContainer, error)
 	return nil, nil
 }
 
+// Delete removes the ports of the lab links from the ovs bridge, as the ports of the deleted
+// interfaces are not removed from the ovs database, and deletes the bridge created by the lab
 func (s *ovs) Delete(ctx context.Context) error {
-	return nil
+	br, err := netlink.LinkByName(s.cfg.ShortName)
+	if err != nil {
+		return nil
+	}
+	c := goOvs.New(goOvs.Sudo())
+	for _, ep := range s.cfg.Endpoints {
+		if err := c.VSwitch.DeletePort(s.cfg.ShortName, ep.EndpointName); err != nil {
+			log.Warnf("failed to delete port %s of ovs bridge %s: %v", ep.EndpointName, s.cfg.ShortName, err)
+		}
+	}
+	if !s.cfg.Bridge.GetCreate() || br.Attrs().Alias != s.cfg.LongName {
+		return nil
+	}
+	log.Infof("Deleting ovs bridge %s", s.cfg.ShortName)
+	return c.VSwitch.DeleteBridge(s.cfg.ShortName)
 }
 
 func (s *ovs) GetImages() map[string]string { return map[string]string{} }
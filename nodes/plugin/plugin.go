@@ -0,0 +1,372 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package plugin registers the node kinds implemented by the out-of-tree plugin executables.
+//
+// A plugin is an executable named clab-kind-<kind> placed in a plugins directory. Containerlab runs it
+// with the hook name as the only argument, writes the JSON Request to its stdin and reads the JSON Response
+// from its stdout. A non-zero exit code fails the hook with the stderr of the plugin as the error message.
+// The describe hook is run when the plugin is loaded and returns the Manifest of the kind
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// ExecPrefix is the prefix of the plugin executable names, followed by the kind name
+const ExecPrefix = "clab-kind-"
+
+// ProtocolVersion is the version of the plugin protocol set in the requests
+const ProtocolVersion = 1
+
+// DefaultDir is the plugins directory the plugins are loaded from along with the directories set by the user
+const DefaultDir = "/etc/containerlab/plugins"
+
+// hookTimeout bounds the hooks run without the deployment context
+const hookTimeout = 5 * time.Minute
+
+// plugin hooks
+const (
+	HookDescribe   = "describe"
+	HookInit       = "init"
+	HookPreDeploy  = "pre-deploy"
+	HookPostDeploy = "post-deploy"
+	HookSave       = "save"
+	HookDelete     = "delete"
+)
+
+// Manifest is returned by the describe hook of the plugin
+type Manifest struct {
+	// protocol version implemented by the plugin
+	Version int `json:"version"`
+	// hooks implemented by the plugin besides describe, the hooks not listed are not run
+	Hooks []string `json:"hooks,omitempty"`
+	// default credentials of the NOS, used to log in to the node
+	Credentials *Credentials `json:"credentials,omitempty"`
+	// container runtime the nodes of the kind are deployed with instead of the default runtime
+	Runtime string `json:"runtime,omitempty"`
+}
+
+// Credentials are the default credentials of the plugin kind
+type Credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// Node is the node config exchanged with the plugin. The init hook returns it with the container settings
+// changed by the kind, e.g. the default env vars, binds and the command of the NOS
+type Node struct {
+	Name            string            `json:"name"`
+	LongName        string            `json:"long-name"`
+	Kind            string            `json:"kind"`
+	Type            string            `json:"type,omitempty"`
+	Image           string            `json:"image,omitempty"`
+	LabDir          string            `json:"lab-dir"`
+	StartupConfig   string            `json:"startup-config,omitempty"`
+	License         string            `json:"license,omitempty"`
+	MgmtIPv4Address string            `json:"mgmt-ipv4,omitempty"`
+	MgmtIPv6Address string            `json:"mgmt-ipv6,omitempty"`
+	Entrypoint      string            `json:"entrypoint,omitempty"`
+	Cmd             string            `json:"cmd,omitempty"`
+	User            string            `json:"user,omitempty"`
+	Env             map[string]string `json:"env,omitempty"`
+	Binds           []string          `json:"binds,omitempty"`
+	Sysctls         map[string]string `json:"sysctls,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+}
+
+// Request is written to the stdin of the plugin
+type Request struct {
+	Version int    `json:"version"`
+	Hook    string `json:"hook"`
+	Node    *Node  `json:"node"`
+	// lab name and the lab CA directories, set for the pre-deploy hook
+	Lab       string `json:"lab,omitempty"`
+	LabCADir  string `json:"lab-ca-dir,omitempty"`
+	LabCARoot string `json:"lab-ca-root,omitempty"`
+}
+
+// Response is read from the stdout of the plugin, an empty output is an empty response
+type Response struct {
+	// node config returned by the init hook
+	Node *Node `json:"node,omitempty"`
+}
+
+// loaded holds the paths of the loaded plugins by their kinds
+var (
+	loaded   = map[string]string{}
+	loadedMu sync.Mutex
+)
+
+// Load registers the kinds of the plugins found in the dirs, the dirs which don't exist are skipped.
+// The plugins of the kinds already registered are skipped with a warning, so a plugin can't replace a built-in kind,
+// the plugins loaded before are not loaded again. Returns the names of the plugin kinds
+func Load(dirs ...string) ([]string, error) {
+	loadedMu.Lock()
+	defer loadedMu.Unlock()
+	var kinds []string
+	for _, dir := range dirs {
+		entries, err := ioutil.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read plugins dir %s: %v", dir, err)
+		}
+		for _, e := range entries {
+			if !strings.HasPrefix(e.Name(), ExecPrefix) || e.IsDir() || e.Mode()&0111 == 0 {
+				continue
+			}
+			kind := strings.TrimPrefix(e.Name(), ExecPrefix)
+			path := filepath.Join(dir, e.Name())
+			if loaded[kind] == path {
+				kinds = append(kinds, kind)
+				continue
+			}
+			if _, ok := nodes.Nodes[kind]; ok {
+				log.Warnf("plugin %s is skipped, kind %s is already registered", path, kind)
+				continue
+			}
+			p := &plugin{kind: kind, path: path}
+			if err := p.describe(); err != nil {
+				return nil, fmt.Errorf("failed to load plugin %s: %v", p.path, err)
+			}
+			p.register()
+			loaded[kind] = path
+			log.Debugf("loaded plugin %s of kind %s", path, kind)
+			kinds = append(kinds, kind)
+		}
+	}
+	sort.Strings(kinds)
+	return kinds, nil
+}
+
+// plugin is a plugin executable implementing a kind
+type plugin struct {
+	kind     string
+	path     string
+	manifest *Manifest
+}
+
+// describe runs the describe hook and validates the manifest of the plugin
+func (p *plugin) describe() error {
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+	out, err := p.exec(ctx, HookDescribe, nil)
+	if err != nil {
+		return err
+	}
+	m := new(Manifest)
+	if err := json.Unmarshal(out, m); err != nil {
+		return fmt.Errorf("failed to parse manifest: %v", err)
+	}
+	if m.Version != ProtocolVersion {
+		return fmt.Errorf("unsupported protocol version %d, containerlab supports version %d", m.Version, ProtocolVersion)
+	}
+	for _, h := range m.Hooks {
+		switch h {
+		case HookInit, HookPreDeploy, HookPostDeploy, HookSave, HookDelete:
+		default:
+			return fmt.Errorf("unknown hook %q", h)
+		}
+	}
+	p.manifest = m
+	return nil
+}
+
+// register registers the kind of the plugin along with its default credentials and runtime
+func (p *plugin) register() {
+	nodes.Register(p.kind, func() nodes.Node {
+		return &node{plugin: p}
+	})
+	if c := p.manifest.Credentials; c != nil {
+		nodes.DefaultCredentials[p.kind] = []string{c.Username, c.Password}
+	}
+	if p.manifest.Runtime != "" {
+		nodes.NonDefaultRuntimes[p.kind] = p.manifest.Runtime
+	}
+}
+
+// implements returns true when the plugin implements the hook
+func (p *plugin) implements(hook string) bool {
+	for _, h := range p.manifest.Hooks {
+		if h == hook {
+			return true
+		}
+	}
+	return false
+}
+
+// run runs the hook of the plugin with the request and returns its response,
+// the hooks not implemented by the plugin return an empty response
+func (p *plugin) run(ctx context.Context, req *Request) (*Response, error) {
+	resp := new(Response)
+	if !p.implements(req.Hook) {
+		return resp, nil
+	}
+	req.Version = ProtocolVersion
+	in, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	out, err := p.exec(ctx, req.Hook, in)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s hook %s failed: %v", p.kind, req.Hook, err)
+	}
+	if len(bytes.TrimSpace(out)) == 0 {
+		return resp, nil
+	}
+	if err := json.Unmarshal(out, resp); err != nil {
+		return nil, fmt.Errorf("plugin %s hook %s returned invalid response: %v", p.kind, req.Hook, err)
+	}
+	return resp, nil
+}
+
+// exec runs the plugin executable with the hook argument and the input written to its stdin
+func (p *plugin) exec(ctx context.Context, hook string, in []byte) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, p.path, hook) // skipcq: GSC-G204
+	cmd.Stdin = bytes.NewReader(in)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%v: %s", err, msg)
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+// node is a node of a plugin kind, deployed as a container with the settings returned by the init hook
+type node struct {
+	plugin  *plugin
+	cfg     *types.NodeConfig
+	runtime runtime.ContainerRuntime
+}
+
+func (n *node) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
+	n.cfg = cfg
+	for _, o := range opts {
+		o(n)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+	resp, err := n.plugin.run(ctx, &Request{Hook: HookInit, Node: toPluginNode(cfg)})
+	if err != nil {
+		return err
+	}
+	if resp.Node != nil {
+		applyPluginNode(cfg, resp.Node)
+	}
+	return nil
+}
+
+func (n *node) Config() *types.NodeConfig { return n.cfg }
+
+func (n *node) PreDeploy(configName, labCADir, labCARoot string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+	_, err := n.plugin.run(ctx, &Request{
+		Hook:      HookPreDeploy,
+		Node:      toPluginNode(n.cfg),
+		Lab:       configName,
+		LabCADir:  labCADir,
+		LabCARoot: labCARoot,
+	})
+	return err
+}
+
+func (n *node) Deploy(ctx context.Context) error {
+	_, err := n.runtime.CreateContainer(ctx, n.cfg)
+	return err
+}
+
+func (n *node) PostDeploy(ctx context.Context, _ map[string]nodes.Node) error {
+	_, err := n.plugin.run(ctx, &Request{Hook: HookPostDeploy, Node: toPluginNode(n.cfg)})
+	return err
+}
+
+func (n *node) GetImages() map[string]string {
+	return map[string]string{
+		nodes.ImageKey: n.cfg.Image,
+	}
+}
+
+func (n *node) WithMgmtNet(*types.MgmtNet)             {}
+func (n *node) WithRuntime(r runtime.ContainerRuntime) { n.runtime = r }
+func (n *node) GetRuntime() runtime.ContainerRuntime   { return n.runtime }
+
+func (n *node) Delete(ctx context.Context) error {
+	if _, err := n.plugin.run(ctx, &Request{Hook: HookDelete, Node: toPluginNode(n.cfg)}); err != nil {
+		log.Warn(err)
+	}
+	return n.runtime.DeleteContainer(ctx, n.cfg.LongName)
+}
+
+func (n *node) SaveConfig(ctx context.Context) error {
+	if !n.plugin.implements(HookSave) {
+		return nil
+	}
+	if _, err := n.plugin.run(ctx, &Request{Hook: HookSave, Node: toPluginNode(n.cfg)}); err != nil {
+		return err
+	}
+	log.Infof("saved %s running configuration to startup configuration file\n", n.cfg.ShortName)
+	return nil
+}
+
+// toPluginNode returns the plugin node config of the node
+func toPluginNode(cfg *types.NodeConfig) *Node {
+	return &Node{
+		Name:            cfg.ShortName,
+		LongName:        cfg.LongName,
+		Kind:            cfg.Kind,
+		Type:            cfg.NodeType,
+		Image:           cfg.Image,
+		LabDir:          cfg.LabDir,
+		StartupConfig:   cfg.StartupConfig,
+		License:         cfg.License,
+		MgmtIPv4Address: cfg.MgmtIPv4Address,
+		MgmtIPv6Address: cfg.MgmtIPv6Address,
+		Entrypoint:      cfg.Entrypoint,
+		Cmd:             cfg.Cmd,
+		User:            cfg.User,
+		Env:             cfg.Env,
+		Binds:           cfg.Binds,
+		Sysctls:         cfg.Sysctls,
+		Labels:          cfg.Labels,
+	}
+}
+
+// applyPluginNode sets the container settings of the plugin node config returned by the init hook to the node
+func applyPluginNode(cfg *types.NodeConfig, pn *Node) {
+	if pn.Image != "" {
+		cfg.Image = pn.Image
+	}
+	cfg.Entrypoint = pn.Entrypoint
+	cfg.Cmd = pn.Cmd
+	cfg.User = pn.User
+	cfg.Env = pn.Env
+	cfg.Binds = pn.Binds
+	if pn.Sysctls != nil {
+		cfg.Sysctls = pn.Sysctls
+	}
+	cfg.Labels = pn.Labels
+}
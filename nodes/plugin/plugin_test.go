@@ -0,0 +1,131 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package plugin
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// testPlugin sets the env var and the command of the NOS in the init hook
+const testPlugin = `#!/bin/sh
+case "$1" in
+describe)
+  echo '{"version": 1, "hooks": ["init", "save"], "credentials": {"username": "admin", "password": "nos"}}'
+  ;;
+init)
+  # the node config is echoed back with the kind settings
+  sed -e 's/"env":{/"env":{"NOS_MODE":"lab",/' -e 's/"long-name"/"cmd":"\/start.sh","long-name"/'
+  ;;
+save)
+  echo "save failed" >&2
+  exit 1
+  ;;
+esac
+`
+
+func writePlugin(t *testing.T, dir, name, script string, mode uint32) {
+	t.Helper()
+	if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(script), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(filepath.Join(dir, name), os.FileMode(mode)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	writePlugin(t, dir, ExecPrefix+"test-nos", testPlugin, 0755)
+	// not executable
+	writePlugin(t, dir, ExecPrefix+"disabled", testPlugin, 0644)
+	// built-in kind
+	writePlugin(t, dir, ExecPrefix+nodes.NodeKindLinux, testPlugin, 0755)
+	nodes.Register(nodes.NodeKindLinux, func() nodes.Node { return nil })
+	defer delete(nodes.Nodes, nodes.NodeKindLinux)
+	defer delete(nodes.Nodes, "test-nos")
+	defer delete(nodes.DefaultCredentials, "test-nos")
+
+	kinds, err := Load(filepath.Join(dir, "missing"), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(kinds, []string{"test-nos"}) {
+		t.Fatalf("got kinds: %v, want: [test-nos]", kinds)
+	}
+	// loading again keeps the kind registered
+	if kinds, err = Load(dir); err != nil || len(kinds) != 1 {
+		t.Fatalf("got kinds: %v, error: %v on reload", kinds, err)
+	}
+	if got := nodes.DefaultCredentials["test-nos"]; !cmp.Equal(got, []string{"admin", "nos"}) {
+		t.Errorf("got credentials: %v", got)
+	}
+
+	n := nodes.Nodes["test-nos"]()
+	cfg := &types.NodeConfig{
+		ShortName: "n1",
+		LongName:  "clab-lab-n1",
+		Kind:      "test-nos",
+		Image:     "nos:1.0",
+		Env:       map[string]string{"A": "1"},
+		Sysctls:   map[string]string{},
+	}
+	if err := n.Init(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(map[string]string{"A": "1", "NOS_MODE": "lab"}, cfg.Env); d != "" {
+		t.Errorf("env mismatch (-want +got):\n%s", d)
+	}
+	if cfg.Cmd != "/start.sh" || cfg.Image != "nos:1.0" {
+		t.Errorf("got cmd: %q, image: %q", cfg.Cmd, cfg.Image)
+	}
+
+	if err := n.SaveConfig(context.Background()); err == nil || !strings.Contains(err.Error(), "save failed") {
+		t.Errorf("got save error: %v, want plugin stderr", err)
+	}
+	// pre-deploy is not implemented by the plugin
+	if err := n.PreDeploy("lab", "", ""); err != nil {
+		t.Errorf("unexpected pre-deploy error: %v", err)
+	}
+}
+
+func TestLoadInvalidManifest(t *testing.T) {
+	tests := map[string]struct {
+		manifest string
+		wantErr  string
+	}{
+		"version": {
+			manifest: `{"version": 2}`,
+			wantErr:  "unsupported protocol version 2",
+		},
+		"hook": {
+			manifest: `{"version": 1, "hooks": ["boot"]}`,
+			wantErr:  `unknown hook "boot"`,
+		},
+		"json": {
+			manifest: `version: 1`,
+			wantErr:  "failed to parse manifest",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			writePlugin(t, dir, ExecPrefix+"bad-"+name, "#!/bin/sh\necho '"+tc.manifest+"'\n", 0755)
+			_, err := Load(dir)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("got error: %v, want error containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
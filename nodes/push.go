@@ -0,0 +1,57 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package nodes
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/srl-labs/containerlab/confpush"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// PushTemplates holds the config push template per kind used by PushConfig.
+// A kind gets the config push support by declaring its template here
+var PushTemplates = map[string]*confpush.Template{
+	NodeKindSRL:     {Transport: confpush.GNMI},
+	NodeKindCEOS:    {Transport: confpush.GNMI, Port: 6030},
+	NodeKindCRPD:    {Transport: confpush.Netconf, Datastore: "candidate"},
+	NodeKindVrSROS:  {Transport: confpush.Netconf, Datastore: "candidate"},
+	NodeKindVrVMX:   {Transport: confpush.Netconf, Datastore: "candidate"},
+	NodeKindVrVQFX:  {Transport: confpush.Netconf, Datastore: "candidate"},
+	NodeKindVrXRV:   {Transport: confpush.Netconf, Datastore: "candidate"},
+	NodeKindVrXRV9K: {Transport: confpush.Netconf, Datastore: "candidate"},
+	NodeKindVrVEOS:  {Transport: confpush.Netconf},
+	NodeKindVrCSR:   {Transport: confpush.Netconf},
+}
+
+// PushTemplate returns the config push template of the node.
+// The transport set in the node config overrides the transport of the kind
+func PushTemplate(cfg *types.NodeConfig) (*confpush.Template, error) {
+	t := PushTemplates[cfg.Kind]
+	tr := cfg.Config.GetTransport()
+	if tr == "" || (t != nil && t.Transport == tr) {
+		if t == nil {
+			return nil, fmt.Errorf("config push is not supported for kind %s, set the config transport", cfg.Kind)
+		}
+		return t, nil
+	}
+	switch tr {
+	case confpush.GNMI, confpush.Netconf:
+		return &confpush.Template{Transport: tr}, nil
+	}
+	return nil, fmt.Errorf("unknown config push transport %q", tr)
+}
+
+// PushConfig pushes the config payloads to the node with the push template of the node,
+// retrying every poll interval until the node accepts the config or ctx is done
+func PushConfig(ctx context.Context, cfg *types.NodeConfig, payloads []string, poll time.Duration) error {
+	t, err := PushTemplate(cfg)
+	if err != nil {
+		return fmt.Errorf("%s: %v", cfg.ShortName, err)
+	}
+	return confpush.Push(ctx, cfg, SaveCredentials(cfg), t, payloads, poll)
+}
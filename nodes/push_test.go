@@ -0,0 +1,53 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package nodes
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/confpush"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestPushTemplate(t *testing.T) {
+	tests := map[string]struct {
+		cfg     *types.NodeConfig
+		want    *confpush.Template
+		wantErr bool
+	}{
+		"kind": {
+			cfg:  &types.NodeConfig{Kind: NodeKindVrSROS},
+			want: &confpush.Template{Transport: confpush.Netconf, Datastore: "candidate"},
+		},
+		"kind-transport": {
+			cfg:  &types.NodeConfig{Kind: NodeKindCEOS, Config: &types.ConfigDispatcher{Transport: confpush.GNMI}},
+			want: &confpush.Template{Transport: confpush.GNMI, Port: 6030},
+		},
+		"transport-override": {
+			cfg:  &types.NodeConfig{Kind: NodeKindCEOS, Config: &types.ConfigDispatcher{Transport: confpush.Netconf}},
+			want: &confpush.Template{Transport: confpush.Netconf},
+		},
+		"unsupported-kind": {
+			cfg:     &types.NodeConfig{Kind: NodeKindLinux},
+			wantErr: true,
+		},
+		"unknown-transport": {
+			cfg:     &types.NodeConfig{Kind: NodeKindSRL, Config: &types.ConfigDispatcher{Transport: "ssh"}},
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := PushTemplate(tc.cfg)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if d := cmp.Diff(tc.want, got); d != "" {
+				t.Errorf("template mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package nodes
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/confsave"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// SaveTemplates holds the config save templates per kind, tried in order by SaveConfig.
+// A kind gets the config save support by declaring its templates here
+var SaveTemplates = map[string][]*confsave.Template{
+	NodeKindVrCSR:  {{Transport: confsave.Netconf}},
+	NodeKindVrSROS: {{Transport: confsave.Netconf}},
+	NodeKindVrVEOS: {{Transport: confsave.Netconf}},
+	NodeKindVrVMX:  {{Transport: confsave.Netconf}},
+	NodeKindVrVQFX: {{Transport: confsave.Netconf}},
+	NodeKindVrXRV:  {{Transport: confsave.Netconf}},
+	// the OS10 CLI is used when netconf is disabled on the node
+	NodeKindVrFTOSV: {
+		{Transport: confsave.Netconf},
+		{Transport: confsave.SSHCLI, Command: "write memory", FailedWhenContains: []string{"% Error"}},
+	},
+}
+
+// sources of the node credentials reported by NodeCredentials
+const (
+	CredentialsSourceTopology = "topology"
+	CredentialsSourceEnv      = "env"
+	CredentialsSourceKind     = "kind-default"
+)
+
+// SaveCredentials returns the credentials the config of the node is saved with.
+// The credentials set in the topology take precedence over the USERNAME and PASSWORD env vars
+// the vrnetlab launchers create the user with, the kind default credentials are used otherwise
+func SaveCredentials(cfg *types.NodeConfig) *types.Credentials {
+	creds, _ := NodeCredentials(cfg)
+	return creds
+}
+
+// NodeCredentials returns the credentials of the node along with where they come from,
+// the source is empty when the node has no credentials
+func NodeCredentials(cfg *types.NodeConfig) (*types.Credentials, string) {
+	if cfg.Credentials != nil && cfg.Credentials.Username != "" {
+		return cfg.Credentials, CredentialsSourceTopology
+	}
+	if u, ok := cfg.Env["USERNAME"]; ok {
+		return &types.Credentials{Username: u, Password: cfg.Env["PASSWORD"]}, CredentialsSourceEnv
+	}
+	if c, ok := DefaultCredentials[cfg.Kind]; ok {
+		return &types.Credentials{Username: c[0], Password: c[1]}, CredentialsSourceKind
+	}
+	return &types.Credentials{}, ""
+}
+
+// SaveConfig saves the running config of the node with the save templates of its kind
+func SaveConfig(ctx context.Context, cfg *types.NodeConfig) error {
+	if err := confsave.Save(ctx, cfg, SaveCredentials(cfg), SaveTemplates[cfg.Kind]); err != nil {
+		return err
+	}
+	log.Infof("saved %s running configuration to startup configuration file\n", cfg.ShortName)
+	return nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package nodes
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestSaveCredentials(t *testing.T) {
+	tests := map[string]struct {
+		cfg        *types.NodeConfig
+		want       *types.Credentials
+		wantSource string
+	}{
+		"topology-credentials": {
+			cfg: &types.NodeConfig{
+				Kind:        NodeKindVrSROS,
+				Credentials: &types.Credentials{Username: "ops", Password: "secret"},
+				Env:         map[string]string{"USERNAME": "clab", "PASSWORD": "clab@123"},
+			},
+			want:       &types.Credentials{Username: "ops", Password: "secret"},
+			wantSource: CredentialsSourceTopology,
+		},
+		"launcher-env": {
+			cfg: &types.NodeConfig{
+				Kind: NodeKindVrXRV,
+				Env:  map[string]string{"USERNAME": "clab", "PASSWORD": "clab@123"},
+			},
+			want:       &types.Credentials{Username: "clab", Password: "clab@123"},
+			wantSource: CredentialsSourceEnv,
+		},
+		"kind-defaults": {
+			cfg:        &types.NodeConfig{Kind: NodeKindVrSROS},
+			want:       &types.Credentials{Username: "admin", Password: "admin"},
+			wantSource: CredentialsSourceKind,
+		},
+		"unknown": {
+			cfg:  &types.NodeConfig{Kind: NodeKindLinux},
+			want: &types.Credentials{},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := SaveCredentials(tc.cfg)
+			if !cmp.Equal(got, tc.want) {
+				t.Errorf("got: %+v, want: %+v", got, tc.want)
+			}
+			if _, source := NodeCredentials(tc.cfg); source != tc.wantSource {
+				t.Errorf("got source: %q, want: %q", source, tc.wantSource)
+			}
+		})
+	}
+}
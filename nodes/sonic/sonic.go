@@ -7,6 +7,9 @@ package sonic
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/srl-labs/containerlab/nodes"
@@ -15,6 +18,18 @@ import (
 	"github.com/srl-labs/containerlab/utils"
 )
 
+const (
+	// configDBFile is the file in the node directory with the SONiC configuration database
+	configDBFile = "config_db.json"
+	// configDBPath is the path of the configuration database loaded by SONiC on start
+	configDBPath = "/etc/sonic/config_db.json"
+)
+
+var (
+	// saveCmd dumps the running configuration database
+	saveCmd = []string{"sonic-cfggen", "-d", "--print-data"}
+)
+
 func init() {
 	nodes.Register(nodes.NodeKindSonic, func() nodes.Node {
 		return new(sonic)
@@ -32,6 +47,8 @@ func (s *sonic) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 		o(s)
 	}
 	s.cfg.Entrypoint = "/bin/bash"
+	// the virtual switch SAI creates the tap interfaces of the front panel ports
+	s.cfg.Binds = append(s.cfg.Binds, "/dev/net/tun:/dev/net/tun")
 	return nil
 }
 func (s *sonic) Config() *types.NodeConfig { return s.cfg }
@@ -39,7 +56,7 @@ func (s *sonic) Config() *types.NodeConfig { return s.cfg }
 func (s *sonic) PreDeploy(configName, labCADir, labCARoot string) error {
 	utils.CreateDirectory(s.cfg.LabDir, 0777)
 
-	return nil
+	return createSonicFiles(s.cfg)
 }
 func (s *sonic) Deploy(ctx context.Context) error {
 	_, err := s.runtime.CreateContainer(ctx, s.cfg)
@@ -81,6 +98,44 @@ func (s *sonic) GetImages() map[string]string {
 	}
 }
 
+// SaveConfig dumps the running configuration database to config_db.json file of the node directory,
+// the file is loaded by the node on the next deployment
 func (s *sonic) SaveConfig(ctx context.Context) error {
+	stdout, stderr, err := s.runtime.Exec(ctx, s.cfg.LongName, saveCmd)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute cmd: %v", s.cfg.ShortName, err)
+	}
+	if len(stderr) > 0 {
+		return fmt.Errorf("%s errors: %s", s.cfg.ShortName, string(stderr))
+	}
+
+	confPath := filepath.Join(s.cfg.LabDir, configDBFile)
+	if err := ioutil.WriteFile(confPath, stdout, 0666); err != nil {
+		return fmt.Errorf("failed to write config by %s path from %s container: %v", confPath, s.cfg.ShortName, err)
+	}
+	log.Infof("saved sonic-vs configuration from %s node to %s\n", s.cfg.ShortName, confPath)
+
+	return nil
+}
+
+// createSonicFiles generates the configuration database from the startup-config of the node.
+// The database in the node directory, generated or saved before, is mounted to the node to be loaded on start
+func createSonicFiles(node *types.NodeConfig) error {
+	cfg := filepath.Join(node.LabDir, configDBFile)
+	if node.StartupConfig != "" {
+		c, err := os.ReadFile(node.StartupConfig)
+		if err != nil {
+			return err
+		}
+		if err := node.GenerateConfig(cfg, string(c)); err != nil {
+			return err
+		}
+	}
+	if !utils.FileExists(cfg) {
+		// without the database SONiC starts with the default configuration
+		return nil
+	}
+	node.ResStartupConfig = cfg
+	node.Binds = append(node.Binds, cfg+":"+configDBPath)
 	return nil
 }
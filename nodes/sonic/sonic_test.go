@@ -0,0 +1,55 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package sonic
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestCreateSonicFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clab-sonic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	startup := filepath.Join(dir, "startup.json")
+	if err := ioutil.WriteFile(startup, []byte(`{"DEVICE_METADATA": {"localhost": {"hostname": "{{ .ShortName }}"}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// without startup-config and saved database the default configuration is used
+	node := &types.NodeConfig{ShortName: "sonic1", LabDir: filepath.Join(dir, "sonic1")}
+	if err := os.MkdirAll(node.LabDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := createSonicFiles(node); err != nil {
+		t.Fatal(err)
+	}
+	if len(node.Binds) != 0 {
+		t.Errorf("expected no binds, got %v", node.Binds)
+	}
+
+	node.StartupConfig = startup
+	if err := createSonicFiles(node); err != nil {
+		t.Fatal(err)
+	}
+	cfg := filepath.Join(node.LabDir, configDBFile)
+	if !cmp.Equal(node.Binds, []string{cfg + ":" + configDBPath}) {
+		t.Errorf("unexpected binds %v", node.Binds)
+	}
+	b, err := ioutil.ReadFile(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"DEVICE_METADATA": {"localhost": {"hostname": "sonic1"}}}`; string(b) != want {
+		t.Errorf("wanted config %s, got %s", want, b)
+	}
+}
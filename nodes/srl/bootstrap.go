@@ -0,0 +1,147 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package srl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/srl-labs/containerlab/types"
+)
+
+// applyBootstrap merges the banners and users of the bootstrap config into the SR Linux config file
+// and writes the CLI aliases to the global environment file next to it
+func applyBootstrap(cfgFile string, b *types.SRLBootstrap) error {
+	if b == nil {
+		return nil
+	}
+	if b.LoginBanner != "" || b.MOTD != "" || len(b.Users) != 0 {
+		c, err := ioutil.ReadFile(cfgFile)
+		if err != nil {
+			return err
+		}
+		c, err = mergeBootstrap(c, b)
+		if err != nil {
+			return fmt.Errorf("failed to merge bootstrap config into %s: %v", cfgFile, err)
+		}
+		if err := ioutil.WriteFile(cfgFile, c, 0666); err != nil {
+			return err
+		}
+	}
+	if len(b.Aliases) != 0 {
+		// config directory is mounted to /etc/opt/srlinux
+		rc := filepath.Join(filepath.Dir(cfgFile), "srlinux.rc")
+		return ioutil.WriteFile(rc, aliasesEnv(b.Aliases), 0666)
+	}
+	return nil
+}
+
+// mergeBootstrap sets the banners and adds the local users of the bootstrap config to the json config c
+func mergeBootstrap(c []byte, b *types.SRLBootstrap) ([]byte, error) {
+	cfg := map[string]interface{}{}
+	if err := json.Unmarshal(escapeControlChars(c), &cfg); err != nil {
+		return nil, err
+	}
+	system := jsonObject(cfg, "srl_nokia-system:system")
+
+	if b.LoginBanner != "" || b.MOTD != "" {
+		banner := jsonObject(system, "srl_nokia-system-banner:banner")
+		if b.LoginBanner != "" {
+			banner["login-banner"] = b.LoginBanner
+		}
+		if b.MOTD != "" {
+			banner["motd-banner"] = b.MOTD
+		}
+	}
+
+	if len(b.Users) != 0 {
+		auth := jsonObject(jsonObject(system, "srl_nokia-aaa:aaa"), "authentication")
+		users, _ := auth["user"].([]interface{})
+		for _, u := range b.Users {
+			if u.Name == "" {
+				return nil, fmt.Errorf("user name is not set")
+			}
+			users = append(users, map[string]interface{}{
+				"username": u.Name,
+				"password": u.Password,
+			})
+		}
+		auth["user"] = users
+	}
+
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(cfg); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// escapeControlChars escapes the line breaks and tabs found inside the json strings.
+// The generated config has the PEM encoded TLS data with raw line breaks
+// which SR Linux accepts, but encoding/json doesn't
+func escapeControlChars(c []byte) []byte {
+	var b bytes.Buffer
+	inString, escaped := false, false
+	for _, ch := range c {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case ch == '\\':
+				escaped = true
+			case ch == '"':
+				inString = false
+			case ch == '\n':
+				b.WriteString(`\n`)
+				continue
+			case ch == '\r':
+				b.WriteString(`\r`)
+				continue
+			case ch == '\t':
+				b.WriteString(`\t`)
+				continue
+			}
+		} else if ch == '"' {
+			inString = true
+		}
+		b.WriteByte(ch)
+	}
+	return b.Bytes()
+}
+
+// jsonObject returns the object stored under the key of m, creating it if it doesn't exist
+func jsonObject(m map[string]interface{}, key string) map[string]interface{} {
+	if o, ok := m[key].(map[string]interface{}); ok {
+		return o
+	}
+	o := map[string]interface{}{}
+	m[key] = o
+	return o
+}
+
+// aliasesEnv returns the SR Linux environment file content with the CLI aliases sorted by name
+func aliasesEnv(aliases map[string]string) []byte {
+	names := make([]string, 0, len(aliases))
+	for n := range aliases {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("[alias]\n")
+	for _, n := range names {
+		fmt.Fprintf(&b, "%s = %s\n", strconv.Quote(n), strconv.Quote(aliases[n]))
+	}
+	return []byte(b.String())
+}
@@ -0,0 +1,79 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package srl
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestMergeBootstrap(t *testing.T) {
+	tests := map[string]struct {
+		cfg  string
+		b    *types.SRLBootstrap
+		want string
+	}{
+		"banner": {
+			cfg: `{"srl_nokia-system:system": {"name": "srl"}}`,
+			b:   &types.SRLBootstrap{LoginBanner: "Lab <1>\nWelcome", MOTD: "Read the lab guide"},
+			want: `{"srl_nokia-system:system": {"name": "srl", "srl_nokia-system-banner:banner": ` +
+				`{"login-banner": "Lab <1>\nWelcome", "motd-banner": "Read the lab guide"}}}`,
+		},
+		"users_appended": {
+			cfg: `{"srl_nokia-system:system": {"srl_nokia-aaa:aaa": {"authentication": ` +
+				`{"authentication-method": ["local"], "user": [{"username": "u1", "password": "p1"}]}}}}`,
+			b: &types.SRLBootstrap{Users: []*types.SRLUser{{Name: "student", Password: "student"}}},
+			want: `{"srl_nokia-system:system": {"srl_nokia-aaa:aaa": {"authentication": {"authentication-method": ["local"], ` +
+				`"user": [{"username": "u1", "password": "p1"}, {"username": "student", "password": "student"}]}}}}`,
+		},
+		"raw_line_breaks": {
+			cfg: "{\"srl_nokia-tls:tls\": {\"certificate\": \"-----BEGIN CERTIFICATE-----\nMIID\n\"}}",
+			b:   &types.SRLBootstrap{MOTD: "motd"},
+			want: `{"srl_nokia-tls:tls": {"certificate": "-----BEGIN CERTIFICATE-----\nMIID\n"}, ` +
+				`"srl_nokia-system:system": {"srl_nokia-system-banner:banner": {"motd-banner": "motd"}}}`,
+		},
+		"missing_containers": {
+			cfg: `{}`,
+			b:   &types.SRLBootstrap{Users: []*types.SRLUser{{Name: "student", Password: "student"}}},
+			want: `{"srl_nokia-system:system": {"srl_nokia-aaa:aaa": {"authentication": ` +
+				`{"user": [{"username": "student", "password": "student"}]}}}}`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := mergeBootstrap([]byte(tc.cfg), tc.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got, want interface{}
+			if err := json.Unmarshal(c, &got); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tc.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(got, want) {
+				t.Errorf("failed at '%s', diff (-want +got):\n%s", name, cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestAliasesEnv(t *testing.T) {
+	got := string(aliasesEnv(map[string]string{
+		"sbgp":        "show network-instance default protocols bgp neighbor",
+		"intf {name}": "show interface {name} brief",
+	}))
+	want := "[alias]\n" +
+		"\"intf {name}\" = \"show interface {name} brief\"\n" +
+		"\"sbgp\" = \"show network-instance default protocols bgp neighbor\"\n"
+	if got != want {
+		t.Errorf("expected\n%v, got\n%v", want, got)
+	}
+}
@@ -27,6 +27,8 @@ import (
 
 const (
 	srlDefaultType = "ixrd2"
+	// name of the TLS server profile using the node certificate
+	tlsProfile = "tls-profile-1"
 )
 
 var (
@@ -58,6 +60,12 @@ var (
 	saveCmd []string = []string{"sr_cli", "-d", "tools", "system", "configuration", "save"}
 )
 
+// IsValidType returns true if t is a supported SR Linux node type
+func IsValidType(t string) bool {
+	_, ok := srlTypes[t]
+	return ok
+}
+
 func init() {
 	nodes.Register(nodes.NodeKindSRL, func() nodes.Node {
 		return new(srl)
@@ -97,7 +105,9 @@ func (s *srl) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 		s.cfg.User = "0:0"
 	}
 	for k, v := range srlSysctl {
-		s.cfg.Sysctls[k] = v
+		if _, ok := s.cfg.Sysctls[k]; !ok {
+			s.cfg.Sysctls[k] = v
+		}
 	}
 
 	if s.cfg.License != "" {
@@ -120,36 +130,11 @@ func (s *srl) Config() *types.NodeConfig { return s.cfg }
 
 func (s *srl) PreDeploy(configName, labCADir, labCARoot string) error {
 	utils.CreateDirectory(s.cfg.LabDir, 0777)
-	// retrieve node certificates
-	nodeCerts, err := cert.RetrieveNodeCertData(s.cfg, labCADir)
-	// if not available on disk, create cert in next step
+	nodeCerts, err := cert.NodeCerts(s.cfg, configName, labCADir, labCARoot)
 	if err != nil {
-		// create CERT
-		certTpl, err := template.New("node-cert").Parse(cert.NodeCSRTempl)
-		if err != nil {
-			log.Errorf("failed to parse Node CSR Template: %v", err)
-		}
-		certInput := cert.CertInput{
-			Name:     s.cfg.ShortName,
-			LongName: s.cfg.LongName,
-			Fqdn:     s.cfg.Fqdn,
-			Prefix:   configName,
-		}
-		nodeCerts, err = cert.GenerateCert(
-			path.Join(labCARoot, "root-ca.pem"),
-			path.Join(labCARoot, "root-ca-key.pem"),
-			certTpl,
-			certInput,
-			path.Join(labCADir, certInput.Name),
-		)
-		if err != nil {
-			log.Errorf("failed to generate certificates for node %s: %v", s.cfg.ShortName, err)
-		}
-		log.Debugf("%s CSR: %s", s.cfg.ShortName, string(nodeCerts.Csr))
-		log.Debugf("%s Cert: %s", s.cfg.ShortName, string(nodeCerts.Cert))
-		log.Debugf("%s Key: %s", s.cfg.ShortName, string(nodeCerts.Key))
+		return err
 	}
-	s.cfg.TLSCert = string(nodeCerts.Cert)
+	s.cfg.TLSCert = string(nodeCerts.CertChain)
 	s.cfg.TLSKey = string(nodeCerts.Key)
 
 	// Create appmgr subdir for agent specs and copy files, if needed
@@ -197,6 +182,28 @@ func (s *srl) Delete(ctx context.Context) error {
 	return s.runtime.DeleteContainer(ctx, s.Config().LongName)
 }
 
+// ReloadCerts sets the renewed node certificate and key in the TLS server profile of the running node
+func (s *srl) ReloadCerts(ctx context.Context, configName, labCADir, labCARoot string) error {
+	nodeCerts, err := cert.NodeCerts(s.cfg, configName, labCADir, labCARoot)
+	if err != nil {
+		return err
+	}
+	s.cfg.TLSCert = string(nodeCerts.CertChain)
+	s.cfg.TLSKey = string(nodeCerts.Key)
+
+	cmd := []string{"sr_cli", "-ec", fmt.Sprintf("system tls server-profile %s key \"%s\" certificate \"%s\"",
+		tlsProfile, strings.TrimSpace(s.cfg.TLSKey), strings.TrimSpace(s.cfg.TLSCert))}
+	_, stderr, err := s.runtime.Exec(ctx, s.cfg.LongName, cmd)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute cmd: %v", s.cfg.ShortName, err)
+	}
+	if len(stderr) > 0 {
+		return fmt.Errorf("%s errors: %s", s.cfg.ShortName, string(stderr))
+	}
+	log.Infof("reloaded TLS certificate of %s node", s.cfg.ShortName)
+	return nil
+}
+
 func (s *srl) SaveConfig(ctx context.Context) error {
 	stdout, stderr, err := s.runtime.Exec(ctx, s.cfg.LongName, saveCmd)
 	if err != nil {
@@ -248,9 +255,17 @@ func createSRLFiles(nodeCfg *types.NodeConfig) error {
 		}
 		cfgTemplate = string(c)
 	}
+	generate := nodeCfg.ShouldGenerateConfig(dst)
 	err = nodeCfg.GenerateConfig(dst, cfgTemplate)
 	if err != nil {
 		log.Errorf("node=%s, failed to generate config: %v", nodeCfg.ShortName, err)
+		return err
+	}
+
+	// bootstrap config is merged only into a freshly generated config
+	// to not override the changes saved in the existing one
+	if generate && nodeCfg.Extras != nil {
+		err = applyBootstrap(dst, nodeCfg.Extras.SRLBootstrap)
 	}
 
 	return err
@@ -0,0 +1,137 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package vr_csr
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/scrapli/scrapligo/channel"
+	"github.com/scrapli/scrapligo/driver/base"
+	"github.com/scrapli/scrapligo/driver/core"
+	"github.com/scrapli/scrapligo/transport"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/cert"
+	"github.com/srl-labs/containerlab/nodes"
+)
+
+// gnmiPort is the port of the secure gNMI server enabled with the node certificate
+const gnmiPort = 9339
+
+// pemPrompt is printed by IOS-XE when it waits for a PEM block during the import
+const pemPrompt = `End with a blank line or "quit" on a line by itself`
+
+// certFailedWhenContains are the IOS-XE messages of the failed certificate import
+var certFailedWhenContains = []string{
+	"% Invalid input detected",
+	"% Incomplete command",
+	"% PEM files import failed",
+	"% Error",
+}
+
+// InstallCerts imports the node certificate to a PKI trustpoint of the IOS-XE VM over the SSH CLI
+// and enables the secure HTTP server serving RESTCONF and the secure gNMI server with it
+func (s *vrCsr) InstallCerts(ctx context.Context, configName, labCADir, labCARoot string) error {
+	nodeCerts, err := cert.NodeCerts(s.cfg, configName, labCADir, labCARoot)
+	if err != nil {
+		return err
+	}
+	certs, err := cert.NewImportBundle(nodeCerts, labCARoot)
+	if err != nil {
+		return err
+	}
+	creds := nodes.SaveCredentials(s.cfg)
+	d, err := core.NewCoreDriver(
+		s.cfg.LongName,
+		"cisco_iosxe",
+		base.WithAuthStrictKey(false),
+		base.WithAuthUsername(creds.Username),
+		base.WithAuthPassword(creds.Password),
+		base.WithTransportType(transport.StandardTransportName),
+		base.WithFailedWhenContains(certFailedWhenContains),
+		base.WithTimeoutOps(time.Minute),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: could not create ssh driver: %v", s.cfg.ShortName, err)
+	}
+	if err := d.Open(); err != nil {
+		return fmt.Errorf("%s: failed to open ssh session: %v", s.cfg.ShortName, err)
+	}
+	defer d.Close()
+
+	mr, err := d.SendConfigs(trustpointConfig(certs.Name))
+	if err == nil && mr.Failed != nil {
+		err = mr.Failed
+	}
+	if err != nil {
+		return fmt.Errorf("%s: failed to create trustpoint %s: %v", s.cfg.ShortName, certs.Name, err)
+	}
+	r, err := d.SendInteractive(importEvents(certs), base.WithDesiredPrivilegeLevel("configuration"))
+	if err == nil && r.Failed != nil {
+		err = fmt.Errorf("%s", r.Result)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: failed to import certificate: %v", s.cfg.ShortName, err)
+	}
+	mr, err = d.SendConfigs(secureServersConfig(certs.Name))
+	if err == nil && mr.Failed != nil {
+		err = mr.Failed
+	}
+	if err != nil {
+		return fmt.Errorf("%s: failed to enable secure servers: %v", s.cfg.ShortName, err)
+	}
+	if _, err := d.SendCommand("write memory"); err != nil {
+		return fmt.Errorf("%s: failed to save config: %v", s.cfg.ShortName, err)
+	}
+	log.Infof("installed TLS certificate to trustpoint %s of %s node", certs.Name, s.cfg.ShortName)
+	return nil
+}
+
+// ReloadCerts imports the renewed node certificate to a new trustpoint of the running VM
+// and switches the secure servers to it
+func (s *vrCsr) ReloadCerts(ctx context.Context, configName, labCADir, labCARoot string) error {
+	return s.InstallCerts(ctx, configName, labCADir, labCARoot)
+}
+
+// trustpointConfig returns the config of the trustpoint the node certificate is imported to
+func trustpointConfig(tp string) []string {
+	return []string{
+		"crypto pki trustpoint " + tp,
+		"enrollment terminal pem",
+		"revocation-check none",
+		"exit",
+	}
+}
+
+// importEvents returns the interactive import of the CA certificate, the encrypted key and the node certificate
+// to the trustpoint, every PEM block is ended with quit
+func importEvents(certs *cert.ImportBundle) []*channel.SendInteractiveEvent {
+	return []*channel.SendInteractiveEvent{
+		{
+			ChannelInput:    fmt.Sprintf("crypto pki import %s pem terminal password %s", certs.Name, certs.KeyPassword),
+			ChannelResponse: pemPrompt,
+			HideInput:       false,
+		},
+		{ChannelInput: certs.CA + "\nquit", ChannelResponse: pemPrompt, HideInput: true},
+		{ChannelInput: certs.Key + "\nquit", ChannelResponse: pemPrompt, HideInput: true},
+		// the import ends at the config prompt
+		{ChannelInput: certs.Cert + "\nquit", ChannelResponse: "", HideInput: true},
+	}
+}
+
+// secureServersConfig returns the config serving RESTCONF and gNMI with the certificate of the trustpoint
+func secureServersConfig(tp string) []string {
+	return []string{
+		"ip http secure-server",
+		"ip http secure-trustpoint " + tp,
+		"ip http authentication local",
+		"restconf",
+		"gnmi-yang",
+		"gnmi-yang secure-server",
+		"gnmi-yang secure-trustpoint " + tp,
+		fmt.Sprintf("gnmi-yang secure-port %d", gnmiPort),
+	}
+}
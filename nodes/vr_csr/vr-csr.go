@@ -8,7 +8,7 @@ import (
 	"context"
 	"fmt"
 
-	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/cert"
 	"github.com/srl-labs/containerlab/nodes"
 	"github.com/srl-labs/containerlab/runtime"
 	"github.com/srl-labs/containerlab/types"
@@ -41,6 +41,7 @@ func (s *vrCsr) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 		"DOCKER_NET_V6_ADDR": s.mgmt.IPv6Subnet,
 	}
 	s.cfg.Env = utils.MergeStringMaps(defEnv, s.cfg.Env)
+	nodes.VrApplyVM(s.cfg)
 
 	if s.cfg.Env["CONNECTION_MODE"] == "macvtap" {
 		// mount dev dir to enable macvtap
@@ -49,12 +50,20 @@ func (s *vrCsr) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 
 	s.cfg.Cmd = fmt.Sprintf("--username %s --password %s --hostname %s --connection-mode %s --trace",
 		s.cfg.Env["USERNAME"], s.cfg.Env["PASSWORD"], s.cfg.ShortName, s.cfg.Env["CONNECTION_MODE"])
+
+	nodes.VrAddTLSBind(s.cfg)
+	nodes.VrAddStartupConfigBind(s.cfg)
 	return nil
 }
 func (s *vrCsr) Config() *types.NodeConfig { return s.cfg }
 func (s *vrCsr) PreDeploy(configName, labCADir, labCARoot string) error {
 	utils.CreateDirectory(s.cfg.LabDir, 0777)
-	return nil
+	if s.cfg.TLS {
+		if err := cert.InstallNodeCerts(s.cfg, configName, labCADir, labCARoot, nodes.VrTLSDir(s.cfg)); err != nil {
+			return err
+		}
+	}
+	return nodes.VrGenerateStartupConfig(s.cfg)
 }
 func (s *vrCsr) Deploy(ctx context.Context) error {
 	_, err := s.runtime.CreateContainer(ctx, s.cfg)
@@ -75,20 +84,18 @@ func (s *vrCsr) WithMgmtNet(mgmt *types.MgmtNet)        { s.mgmt = mgmt }
 func (s *vrCsr) WithRuntime(r runtime.ContainerRuntime) { s.runtime = r }
 func (s *vrCsr) GetRuntime() runtime.ContainerRuntime   { return s.runtime }
 
+func (s *vrCsr) Ready(ctx context.Context) (bool, error) {
+	return nodes.VrReady(ctx, s.runtime, s.cfg.LongName)
+}
+
+func (s *vrCsr) Shutdown(ctx context.Context) error {
+	return nodes.VrShutdown(ctx, s.runtime, s.cfg.LongName)
+}
+
 func (s *vrCsr) Delete(ctx context.Context) error {
 	return s.runtime.DeleteContainer(ctx, s.Config().LongName)
 }
 
 func (s *vrCsr) SaveConfig(ctx context.Context) error {
-	err := utils.SaveCfgViaNetconf(s.cfg.LongName,
-		nodes.DefaultCredentials[s.cfg.Kind][0],
-		nodes.DefaultCredentials[s.cfg.Kind][1],
-	)
-
-	if err != nil {
-		return err
-	}
-
-	log.Infof("saved %s running configuration to startup configuration file\n", s.cfg.ShortName)
-	return nil
+	return nodes.SaveConfig(ctx, s.cfg)
 }
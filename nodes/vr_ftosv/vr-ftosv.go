@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/srl-labs/containerlab/cert"
 	"github.com/srl-labs/containerlab/nodes"
 	"github.com/srl-labs/containerlab/runtime"
 	"github.com/srl-labs/containerlab/types"
@@ -40,6 +41,7 @@ func (s *vrFtosv) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 		"DOCKER_NET_V6_ADDR": s.mgmt.IPv6Subnet,
 	}
 	s.cfg.Env = utils.MergeStringMaps(defEnv, s.cfg.Env)
+	nodes.VrApplyVM(s.cfg)
 
 	if s.cfg.Env["CONNECTION_MODE"] == "macvtap" {
 		// mount dev dir to enable macvtap
@@ -48,12 +50,20 @@ func (s *vrFtosv) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 
 	s.cfg.Cmd = fmt.Sprintf("--username %s --password %s --hostname %s --connection-mode %s --trace",
 		s.cfg.Env["USERNAME"], s.cfg.Env["PASSWORD"], s.cfg.ShortName, s.cfg.Env["CONNECTION_MODE"])
+
+	nodes.VrAddTLSBind(s.cfg)
+	nodes.VrAddStartupConfigBind(s.cfg)
 	return nil
 }
 func (s *vrFtosv) Config() *types.NodeConfig { return s.cfg }
-func (s *vrFtosv) PreDeploy(_, _, _ string) error {
+func (s *vrFtosv) PreDeploy(configName, labCADir, labCARoot string) error {
 	utils.CreateDirectory(s.cfg.LabDir, 0777)
-	return nil
+	if s.cfg.TLS {
+		if err := cert.InstallNodeCerts(s.cfg, configName, labCADir, labCARoot, nodes.VrTLSDir(s.cfg)); err != nil {
+			return err
+		}
+	}
+	return nodes.VrGenerateStartupConfig(s.cfg)
 }
 func (s *vrFtosv) Deploy(ctx context.Context) error {
 	_, err := s.runtime.CreateContainer(ctx, s.cfg)
@@ -74,10 +84,18 @@ func (s *vrFtosv) WithMgmtNet(mgmt *types.MgmtNet)        { s.mgmt = mgmt }
 func (s *vrFtosv) WithRuntime(r runtime.ContainerRuntime) { s.runtime = r }
 func (s *vrFtosv) GetRuntime() runtime.ContainerRuntime   { return s.runtime }
 
+func (s *vrFtosv) Ready(ctx context.Context) (bool, error) {
+	return nodes.VrReady(ctx, s.runtime, s.cfg.LongName)
+}
+
+func (s *vrFtosv) Shutdown(ctx context.Context) error {
+	return nodes.VrShutdown(ctx, s.runtime, s.cfg.LongName)
+}
+
 func (s *vrFtosv) Delete(ctx context.Context) error {
 	return s.runtime.DeleteContainer(ctx, s.Config().LongName)
 }
 
-func (*vrFtosv) SaveConfig(_ context.Context) error {
-	return nil
+func (s *vrFtosv) SaveConfig(ctx context.Context) error {
+	return nodes.SaveConfig(ctx, s.cfg)
 }
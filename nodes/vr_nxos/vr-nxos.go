@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/srl-labs/containerlab/cert"
 	"github.com/srl-labs/containerlab/nodes"
 	"github.com/srl-labs/containerlab/runtime"
 	"github.com/srl-labs/containerlab/types"
@@ -42,10 +43,13 @@ func (s *vrNXOS) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 		"DOCKER_NET_V6_ADDR": s.mgmt.IPv6Subnet,
 	}
 	s.cfg.Env = utils.MergeStringMaps(defEnv, s.cfg.Env)
+	nodes.VrApplyVM(s.cfg)
 
 	s.cfg.Cmd = fmt.Sprintf("--username %s --password %s --hostname %s --connection-mode %s --trace",
 		s.cfg.Env["USERNAME"], s.cfg.Env["PASSWORD"], s.cfg.ShortName, s.cfg.Env["CONNECTION_MODE"])
 
+	nodes.VrAddTLSBind(s.cfg)
+	nodes.VrAddStartupConfigBind(s.cfg)
 	return nil
 }
 
@@ -53,7 +57,12 @@ func (s *vrNXOS) Config() *types.NodeConfig { return s.cfg }
 
 func (s *vrNXOS) PreDeploy(configName, labCADir, labCARoot string) error {
 	utils.CreateDirectory(s.cfg.LabDir, 0777)
-	return nil
+	if s.cfg.TLS {
+		if err := cert.InstallNodeCerts(s.cfg, configName, labCADir, labCARoot, nodes.VrTLSDir(s.cfg)); err != nil {
+			return err
+		}
+	}
+	return nodes.VrGenerateStartupConfig(s.cfg)
 }
 
 func (s *vrNXOS) Deploy(ctx context.Context) error {
@@ -77,6 +86,14 @@ func (s *vrNXOS) WithRuntime(r runtime.ContainerRuntime) {
 }
 func (s *vrNXOS) GetRuntime() runtime.ContainerRuntime { return s.runtime }
 
+func (s *vrNXOS) Ready(ctx context.Context) (bool, error) {
+	return nodes.VrReady(ctx, s.runtime, s.cfg.LongName)
+}
+
+func (s *vrNXOS) Shutdown(ctx context.Context) error {
+	return nodes.VrShutdown(ctx, s.runtime, s.cfg.LongName)
+}
+
 func (s *vrNXOS) Delete(ctx context.Context) error {
 	return s.runtime.DeleteContainer(ctx, s.Config().LongName)
 }
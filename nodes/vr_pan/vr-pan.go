@@ -8,6 +8,8 @@ import (
 	"context"
 	"fmt"
 
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/cert"
 	"github.com/srl-labs/containerlab/nodes"
 	"github.com/srl-labs/containerlab/runtime"
 	"github.com/srl-labs/containerlab/types"
@@ -42,6 +44,7 @@ func (s *vrPan) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 		"DOCKER_NET_V6_ADDR": s.mgmt.IPv6Subnet,
 	}
 	s.cfg.Env = utils.MergeStringMaps(defEnv, s.cfg.Env)
+	nodes.VrApplyVM(s.cfg)
 
 	if s.cfg.Env["CONNECTION_MODE"] == "macvtap" {
 		// mount dev dir to enable macvtap
@@ -50,12 +53,20 @@ func (s *vrPan) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 
 	s.cfg.Cmd = fmt.Sprintf("--username %s --password %s --hostname %s --connection-mode %s --trace",
 		s.cfg.Env["USERNAME"], s.cfg.Env["PASSWORD"], s.cfg.ShortName, s.cfg.Env["CONNECTION_MODE"])
+
+	nodes.VrAddTLSBind(s.cfg)
+	nodes.VrAddStartupConfigBind(s.cfg)
 	return nil
 }
 func (s *vrPan) Config() *types.NodeConfig { return s.cfg }
 func (s *vrPan) PreDeploy(configName, labCADir, labCARoot string) error {
 	utils.CreateDirectory(s.cfg.LabDir, 0777)
-	return nil
+	if s.cfg.TLS {
+		if err := cert.InstallNodeCerts(s.cfg, configName, labCADir, labCARoot, nodes.VrTLSDir(s.cfg)); err != nil {
+			return err
+		}
+	}
+	return nodes.VrGenerateStartupConfig(s.cfg)
 }
 func (s *vrPan) Deploy(ctx context.Context) error {
 	_, err := s.runtime.CreateContainer(ctx, s.cfg)
@@ -76,10 +87,38 @@ func (s *vrPan) WithMgmtNet(mgmt *types.MgmtNet)        { s.mgmt = mgmt }
 func (s *vrPan) WithRuntime(r runtime.ContainerRuntime) { s.runtime = r }
 func (s *vrPan) GetRuntime() runtime.ContainerRuntime   { return s.runtime }
 
+func (s *vrPan) Ready(ctx context.Context) (bool, error) {
+	return nodes.VrReady(ctx, s.runtime, s.cfg.LongName)
+}
+
+func (s *vrPan) Shutdown(ctx context.Context) error {
+	return nodes.VrShutdown(ctx, s.runtime, s.cfg.LongName)
+}
+
 func (s *vrPan) Delete(ctx context.Context) error {
 	return s.runtime.DeleteContainer(ctx, s.Config().LongName)
 }
 
+// InstallCerts installs the node certificate to the management plane of the firewall
+// over the XML API and serves it on the HTTPS management interface
+func (s *vrPan) InstallCerts(ctx context.Context, configName, labCADir, labCARoot string) error {
+	nodeCerts, err := cert.NodeCerts(s.cfg, configName, labCADir, labCARoot)
+	if err != nil {
+		return err
+	}
+	api := newXMLAPI(s.cfg.LongName, s.cfg.Env["USERNAME"], s.cfg.Env["PASSWORD"])
+	if err := api.installMgmtCert(ctx, nodeCerts.CertChain, nodeCerts.Key); err != nil {
+		return fmt.Errorf("%s: failed to install TLS certificate: %v", s.cfg.ShortName, err)
+	}
+	log.Infof("installed TLS certificate to the management plane of %s node", s.cfg.ShortName)
+	return nil
+}
+
+// ReloadCerts installs the renewed node certificate to the management plane of the running firewall
+func (s *vrPan) ReloadCerts(ctx context.Context, configName, labCADir, labCARoot string) error {
+	return s.InstallCerts(ctx, configName, labCADir, labCARoot)
+}
+
 func (s *vrPan) SaveConfig(ctx context.Context) error {
 	return nil
 }
@@ -0,0 +1,184 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package vr_pan
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// certName is the name of the certificate installed to the management plane
+	certName = "clab"
+	// tlsProfileName is the name of the SSL/TLS service profile of the management plane
+	tlsProfileName = "clab"
+	// certPassphrase protects the imported key on the firewall
+	certPassphrase = "containerlab"
+
+	deviceXPath = "/config/devices/entry[@name='localhost.localdomain']"
+)
+
+// xmlAPI is a client of the PAN-OS XML API
+type xmlAPI struct {
+	addr     string
+	username string
+	password string
+	key      string
+	client   *http.Client
+}
+
+// xmlAPIResponse is the response envelope of the XML API
+type xmlAPIResponse struct {
+	Status string `xml:"status,attr"`
+	Key    string `xml:"result>key"`
+	// error message is set in msg or result/msg elements, in some responses as msg/line elements
+	Msg       xmlAPIMsg `xml:"msg"`
+	ResultMsg string    `xml:"result>msg"`
+}
+
+type xmlAPIMsg struct {
+	Text  string   `xml:",chardata"`
+	Lines []string `xml:"line"`
+}
+
+// message returns the error message of the response
+func (r *xmlAPIResponse) message() string {
+	msg := strings.TrimSpace(r.Msg.Text + r.ResultMsg)
+	for _, l := range r.Msg.Lines {
+		msg += strings.TrimSpace(l)
+	}
+	return msg
+}
+
+func newXMLAPI(addr, username, password string) *xmlAPI {
+	return &xmlAPI{
+		addr:     addr,
+		username: username,
+		password: password,
+		client: &http.Client{
+			Timeout: 2 * time.Minute,
+			// the management plane uses a self-signed certificate until clab certificate is installed
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}, // skipcq: GSC-G402
+		},
+	}
+}
+
+// do sends the request to the API and returns an error if the firewall reports a failure
+func (a *xmlAPI) do(req *http.Request) (*xmlAPIResponse, error) {
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	r := new(xmlAPIResponse)
+	if err := xml.Unmarshal(b, r); err != nil {
+		return nil, fmt.Errorf("failed to parse XML API response (HTTP %d): %v", resp.StatusCode, err)
+	}
+	if r.Status != "success" {
+		return nil, fmt.Errorf("XML API request failed: %s", r.message())
+	}
+	return r, nil
+}
+
+func (a *xmlAPI) url(params url.Values) string {
+	return fmt.Sprintf("https://%s/api/?%s", a.addr, params.Encode())
+}
+
+// get sends the request with the params, the API key is added once the client has it
+func (a *xmlAPI) get(ctx context.Context, params url.Values) (*xmlAPIResponse, error) {
+	if a.key != "" {
+		params.Set("key", a.key)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.url(params), nil)
+	if err != nil {
+		return nil, err
+	}
+	return a.do(req)
+}
+
+// keygen retrieves the API key of the user
+func (a *xmlAPI) keygen(ctx context.Context) error {
+	r, err := a.get(ctx, url.Values{"type": {"keygen"}, "user": {a.username}, "password": {a.password}})
+	if err != nil {
+		return err
+	}
+	a.key = r.Key
+	return nil
+}
+
+// importKeypair imports the PEM encoded certificate and key as the name certificate
+func (a *xmlAPI) importKeypair(ctx context.Context, name string, certPEM, keyPEM []byte) error {
+	body := new(bytes.Buffer)
+	w := multipart.NewWriter(body)
+	f, err := w.CreateFormFile("file", name+".pem")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(bytes.Join([][]byte{certPEM, keyPEM}, []byte("\n"))); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	params := url.Values{
+		"type":             {"import"},
+		"category":         {"keypair"},
+		"certificate-name": {name},
+		"format":           {"pem"},
+		"passphrase":       {certPassphrase},
+		"key":              {a.key},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url(params), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	_, err = a.do(req)
+	return err
+}
+
+// set merges the element into the candidate config at xpath
+func (a *xmlAPI) set(ctx context.Context, xpath, element string) error {
+	_, err := a.get(ctx, url.Values{"type": {"config"}, "action": {"set"}, "xpath": {xpath}, "element": {element}})
+	return err
+}
+
+// commit commits the candidate config
+func (a *xmlAPI) commit(ctx context.Context) error {
+	_, err := a.get(ctx, url.Values{"type": {"commit"}, "cmd": {"<commit></commit>"}})
+	return err
+}
+
+// installMgmtCert imports the certificate and key and configures the management plane to serve them
+func (a *xmlAPI) installMgmtCert(ctx context.Context, certPEM, keyPEM []byte) error {
+	if err := a.keygen(ctx); err != nil {
+		return err
+	}
+	if err := a.importKeypair(ctx, certName, certPEM, keyPEM); err != nil {
+		return err
+	}
+	if err := a.set(ctx, "/config/shared/ssl-tls-service-profile",
+		fmt.Sprintf("<entry name=%q><certificate>%s</certificate></entry>", tlsProfileName, certName)); err != nil {
+		return err
+	}
+	if err := a.set(ctx, deviceXPath+"/deviceconfig/system",
+		fmt.Sprintf("<ssl-tls-service-profile>%s</ssl-tls-service-profile>", tlsProfileName)); err != nil {
+		return err
+	}
+	return a.commit(ctx)
+}
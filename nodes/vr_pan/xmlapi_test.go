@@ -0,0 +1,69 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package vr_pan
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestInstallMgmtCert(t *testing.T) {
+	var got []string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch q.Get("type") {
+		case "keygen":
+			if q.Get("user") != "admin" || q.Get("password") != "Admin@123" {
+				fmt.Fprint(w, `<response status="error"><result><msg>Invalid credentials.</msg></result></response>`)
+				return
+			}
+			fmt.Fprint(w, `<response status="success"><result><key>secret</key></result></response>`)
+			return
+		case "import":
+			f, _, err := r.FormFile("file")
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, _ := ioutil.ReadAll(f)
+			got = append(got, "import "+q.Get("certificate-name")+" "+strings.ReplaceAll(string(b), "\n", "|"))
+		case "config":
+			got = append(got, "set "+q.Get("xpath")+" "+q.Get("element"))
+		default:
+			got = append(got, q.Get("type"))
+		}
+		if q.Get("key") != "secret" {
+			fmt.Fprint(w, `<response status="error"><msg><line>Invalid key</line></msg></response>`)
+			return
+		}
+		fmt.Fprint(w, `<response status="success"/>`)
+	}))
+	defer srv.Close()
+
+	api := newXMLAPI(strings.TrimPrefix(srv.URL, "https://"), "admin", "Admin@123")
+	if err := api.installMgmtCert(context.Background(), []byte("CERT"), []byte("KEY")); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"import clab CERT|KEY",
+		`set /config/shared/ssl-tls-service-profile <entry name="clab"><certificate>clab</certificate></entry>`,
+		"set " + deviceXPath + "/deviceconfig/system <ssl-tls-service-profile>clab</ssl-tls-service-profile>",
+		"commit",
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("diff (-want +got):\n%s", cmp.Diff(want, got))
+	}
+
+	api = newXMLAPI(strings.TrimPrefix(srv.URL, "https://"), "admin", "wrong")
+	if err := api.installMgmtCert(context.Background(), []byte("CERT"), []byte("KEY")); err == nil {
+		t.Error("expected an error for invalid credentials")
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/cert"
 	"github.com/srl-labs/containerlab/nodes"
 	"github.com/srl-labs/containerlab/runtime"
 	"github.com/srl-labs/containerlab/types"
@@ -53,6 +54,7 @@ func (s *vrSROS) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 		"DOCKER_NET_V6_ADDR": s.mgmt.IPv6Subnet,
 	}
 	s.cfg.Env = utils.MergeStringMaps(defEnv, s.cfg.Env)
+	nodes.VrApplyVM(s.cfg)
 
 	// mount tftpboot dir
 	s.cfg.Binds = append(s.cfg.Binds, fmt.Sprint(path.Join(s.cfg.LabDir, "tftpboot"), ":/tftpboot"))
@@ -65,6 +67,8 @@ func (s *vrSROS) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 		s.cfg.ShortName,
 		s.cfg.NodeType,
 	)
+
+	nodes.VrAddTLSBind(s.cfg)
 	return nil
 }
 
@@ -72,6 +76,11 @@ func (s *vrSROS) Config() *types.NodeConfig { return s.cfg }
 
 func (s *vrSROS) PreDeploy(configName, labCADir, labCARoot string) error {
 	utils.CreateDirectory(s.cfg.LabDir, 0777)
+	if s.cfg.TLS {
+		if err := cert.InstallNodeCerts(s.cfg, configName, labCADir, labCARoot, nodes.VrTLSDir(s.cfg)); err != nil {
+			return err
+		}
+	}
 	return createVrSROSFiles(s.cfg)
 }
 
@@ -88,6 +97,14 @@ func (s *vrSROS) WithMgmtNet(mgmt *types.MgmtNet)        { s.mgmt = mgmt }
 func (s *vrSROS) WithRuntime(r runtime.ContainerRuntime) { s.runtime = r }
 func (s *vrSROS) GetRuntime() runtime.ContainerRuntime   { return s.runtime }
 
+func (s *vrSROS) Ready(ctx context.Context) (bool, error) {
+	return nodes.VrReady(ctx, s.runtime, s.cfg.LongName)
+}
+
+func (s *vrSROS) Shutdown(ctx context.Context) error {
+	return nodes.VrShutdown(ctx, s.runtime, s.cfg.LongName)
+}
+
 func (s *vrSROS) Delete(ctx context.Context) error {
 	return s.runtime.DeleteContainer(ctx, s.Config().LongName)
 }
@@ -99,17 +116,7 @@ func (s *vrSROS) GetImages() map[string]string {
 }
 
 func (s *vrSROS) SaveConfig(ctx context.Context) error {
-	err := utils.SaveCfgViaNetconf(s.cfg.LongName,
-		nodes.DefaultCredentials[s.cfg.Kind][0],
-		nodes.DefaultCredentials[s.cfg.Kind][1],
-	)
-
-	if err != nil {
-		return err
-	}
-
-	log.Infof("saved %s running configuration to startup configuration file\n", s.cfg.ShortName)
-	return nil
+	return nodes.SaveConfig(ctx, s.cfg)
 }
 
 //
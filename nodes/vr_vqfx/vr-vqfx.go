@@ -0,0 +1,105 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package vr_vqfx
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/srl-labs/containerlab/cert"
+	"github.com/srl-labs/containerlab/nodes"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+func init() {
+	nodes.Register(nodes.NodeKindVrVQFX, func() nodes.Node {
+		return new(vrVQFX)
+	})
+}
+
+type vrVQFX struct {
+	cfg     *types.NodeConfig
+	mgmt    *types.MgmtNet
+	runtime runtime.ContainerRuntime
+}
+
+func (s *vrVQFX) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
+	s.cfg = cfg
+	for _, o := range opts {
+		o(s)
+	}
+	// env vars are used to set launch.py arguments in vrnetlab container.
+	// vrnetlab runs both the RE and the PFE VMs of vQFX in a single container
+	defEnv := map[string]string{
+		"USERNAME":           "admin",
+		"PASSWORD":           "admin@123",
+		"CONNECTION_MODE":    nodes.VrDefConnMode,
+		"DOCKER_NET_V4_ADDR": s.mgmt.IPv4Subnet,
+		"DOCKER_NET_V6_ADDR": s.mgmt.IPv6Subnet,
+	}
+	s.cfg.Env = utils.MergeStringMaps(defEnv, s.cfg.Env)
+	nodes.VrApplyVM(s.cfg)
+
+	if s.cfg.Env["CONNECTION_MODE"] == "macvtap" {
+		// mount dev dir to enable macvtap
+		s.cfg.Binds = append(s.cfg.Binds, "/dev:/dev")
+	}
+
+	s.cfg.Cmd = fmt.Sprintf("--username %s --password %s --hostname %s --connection-mode %s --trace",
+		s.cfg.Env["USERNAME"], s.cfg.Env["PASSWORD"], s.cfg.ShortName, s.cfg.Env["CONNECTION_MODE"])
+
+	nodes.VrAddTLSBind(s.cfg)
+	nodes.VrAddStartupConfigBind(s.cfg)
+	return nil
+}
+
+func (s *vrVQFX) Config() *types.NodeConfig { return s.cfg }
+
+func (s *vrVQFX) PreDeploy(configName, labCADir, labCARoot string) error {
+	utils.CreateDirectory(s.cfg.LabDir, 0777)
+	if s.cfg.TLS {
+		if err := cert.InstallNodeCerts(s.cfg, configName, labCADir, labCARoot, nodes.VrTLSDir(s.cfg)); err != nil {
+			return err
+		}
+	}
+	return nodes.VrGenerateStartupConfig(s.cfg)
+}
+
+func (s *vrVQFX) Deploy(ctx context.Context) error {
+	_, err := s.runtime.CreateContainer(ctx, s.cfg)
+	return err
+}
+
+func (s *vrVQFX) PostDeploy(ctx context.Context, ns map[string]nodes.Node) error {
+	return nil
+}
+
+func (s *vrVQFX) GetImages() map[string]string {
+	return map[string]string{
+		nodes.ImageKey: s.cfg.Image,
+	}
+}
+
+func (s *vrVQFX) WithMgmtNet(mgmt *types.MgmtNet)        { s.mgmt = mgmt }
+func (s *vrVQFX) WithRuntime(r runtime.ContainerRuntime) { s.runtime = r }
+func (s *vrVQFX) GetRuntime() runtime.ContainerRuntime   { return s.runtime }
+
+func (s *vrVQFX) Ready(ctx context.Context) (bool, error) {
+	return nodes.VrReady(ctx, s.runtime, s.cfg.LongName)
+}
+
+func (s *vrVQFX) Shutdown(ctx context.Context) error {
+	return nodes.VrShutdown(ctx, s.runtime, s.cfg.LongName)
+}
+
+func (s *vrVQFX) Delete(ctx context.Context) error {
+	return s.runtime.DeleteContainer(ctx, s.Config().LongName)
+}
+
+func (s *vrVQFX) SaveConfig(ctx context.Context) error {
+	return nodes.SaveConfig(ctx, s.cfg)
+}
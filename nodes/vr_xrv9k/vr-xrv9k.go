@@ -7,14 +7,25 @@ package vr_xrv9k
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"time"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/cert"
 	"github.com/srl-labs/containerlab/nodes"
 	"github.com/srl-labs/containerlab/runtime"
 	"github.com/srl-labs/containerlab/types"
 	"github.com/srl-labs/containerlab/utils"
 )
 
+const (
+	// saveConfigFile is the file in the node directory the running config is saved to
+	saveConfigFile = "running-config.xml"
+	// saveConfigTimeout bounds the netconf operations of the config retrieval
+	saveConfigTimeout = 2 * time.Minute
+)
+
 func init() {
 	nodes.Register(nodes.NodeKindVrXRV9K, func() nodes.Node {
 		return new(vrXRV9K)
@@ -37,11 +48,16 @@ func (s *vrXRV9K) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 		"USERNAME":           "clab",
 		"PASSWORD":           "clab@123",
 		"CONNECTION_MODE":    nodes.VrDefConnMode,
-		"VCPU":               "2",
-		"RAM":                "12288",
+		"VCPU":               "4",
+		"RAM":                "16384",
 		"DOCKER_NET_V4_ADDR": s.mgmt.IPv4Subnet,
 		"DOCKER_NET_V6_ADDR": s.mgmt.IPv6Subnet,
 	}
+	nodes.VrApplyVM(s.cfg)
+	// the launcher is passed the number of vCPUs with the --vcpu flag, one per pinned host CPU
+	if smp := s.cfg.Env[nodes.VrEnvQemuSMP]; smp != "" {
+		defEnv["VCPU"] = smp
+	}
 	s.cfg.Env = utils.MergeStringMaps(defEnv, s.cfg.Env)
 
 	if s.cfg.Env["CONNECTION_MODE"] == "macvtap" {
@@ -52,6 +68,8 @@ func (s *vrXRV9K) Init(cfg *types.NodeConfig, opts ...nodes.NodeOption) error {
 	s.cfg.Cmd = fmt.Sprintf("--username %s --password %s --hostname %s --connection-mode %s --vcpu %s --ram %s --trace",
 		s.cfg.Env["USERNAME"], s.cfg.Env["PASSWORD"], s.cfg.ShortName, s.cfg.Env["CONNECTION_MODE"], s.cfg.Env["VCPU"], s.cfg.Env["RAM"])
 
+	nodes.VrAddTLSBind(s.cfg)
+	nodes.VrAddStartupConfigBind(s.cfg)
 	return nil
 }
 
@@ -59,7 +77,12 @@ func (s *vrXRV9K) Config() *types.NodeConfig { return s.cfg }
 
 func (s *vrXRV9K) PreDeploy(configName, labCADir, labCARoot string) error {
 	utils.CreateDirectory(s.cfg.LabDir, 0777)
-	return nil
+	if s.cfg.TLS {
+		if err := cert.InstallNodeCerts(s.cfg, configName, labCADir, labCARoot, nodes.VrTLSDir(s.cfg)); err != nil {
+			return err
+		}
+	}
+	return nodes.VrGenerateStartupConfig(s.cfg)
 }
 
 func (s *vrXRV9K) Deploy(ctx context.Context) error {
@@ -83,20 +106,35 @@ func (s *vrXRV9K) WithRuntime(r runtime.ContainerRuntime) {
 }
 func (s *vrXRV9K) GetRuntime() runtime.ContainerRuntime { return s.runtime }
 
+func (s *vrXRV9K) Ready(ctx context.Context) (bool, error) {
+	return nodes.VrReady(ctx, s.runtime, s.cfg.LongName)
+}
+
+func (s *vrXRV9K) Shutdown(ctx context.Context) error {
+	return nodes.VrShutdown(ctx, s.runtime, s.cfg.LongName)
+}
+
 func (s *vrXRV9K) Delete(ctx context.Context) error {
 	return s.runtime.DeleteContainer(ctx, s.Config().LongName)
 }
 
+// SaveConfig saves the running config retrieved over netconf to the node directory,
+// as XR has no startup datastore to copy the running config to
 func (s *vrXRV9K) SaveConfig(ctx context.Context) error {
-	err := utils.SaveCfgViaNetconf(s.cfg.LongName,
-		nodes.DefaultCredentials[s.cfg.Kind][0],
-		nodes.DefaultCredentials[s.cfg.Kind][1],
+	cfg, err := utils.GetCfgViaNetconf(s.cfg.LongName,
+		s.cfg.Env["USERNAME"],
+		s.cfg.Env["PASSWORD"],
+		saveConfigTimeout,
 	)
-
 	if err != nil {
 		return err
 	}
 
-	log.Infof("saved %s running configuration to startup configuration file\n", s.cfg.ShortName)
+	dst := filepath.Join(s.cfg.LabDir, saveConfigFile)
+	if err := ioutil.WriteFile(dst, []byte(cfg), 0666); err != nil {
+		return fmt.Errorf("failed to write config of %s: %v", s.cfg.ShortName, err)
+	}
+
+	log.Infof("saved %s running configuration to %s\n", s.cfg.ShortName, dst)
 	return nil
 }
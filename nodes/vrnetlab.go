@@ -0,0 +1,217 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package nodes
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// health states of the vrnetlab nodes
+const (
+	// the container is running, but the VM is still booting
+	VrHealthBooting = "booting"
+	// the VM has booted and is ready to be used
+	VrHealthReady = "ready"
+)
+
+// vrHealthcheckCmd is the vrnetlab healthcheck reporting the state of the VM started by launch.py.
+// It exits with 0 once the VM is running and prints the state message written by launch.py
+var vrHealthcheckCmd = []string{"/healthcheck.py"}
+
+// vrPowerdownCmd sends the ACPI powerdown request to the VM over the qemu monitor launch.py opens on port 4000
+var vrPowerdownCmd = []string{"python3", "-c",
+	`import socket; s = socket.create_connection(("127.0.0.1", 4000)); s.sendall(b"system_powerdown\n"); s.close()`}
+
+// vrQemuRunningCmd exits with 0 while the qemu process of the VM is running
+var vrQemuRunningCmd = []string{"sh", "-c", "grep -qs qemu /proc/[0-9]*/comm"}
+
+// vrShutdownPoll is the interval of checking whether the VM has powered down
+const vrShutdownPoll = 2 * time.Second
+
+// VrDefBootTimeout is the time the VM of a vrnetlab node is given to boot
+const VrDefBootTimeout = 10 * time.Minute
+
+// VrBootTimeouts holds the boot timeouts of the vrnetlab kinds which VMs take longer than VrDefBootTimeout to boot
+var VrBootTimeouts = map[string]time.Duration{
+	NodeKindVrXRV9K: 30 * time.Minute,
+}
+
+// VrBootTimeout returns the boot timeout of the vrnetlab kind
+func VrBootTimeout(kind string) time.Duration {
+	if t, ok := VrBootTimeouts[kind]; ok {
+		return t
+	}
+	return VrDefBootTimeout
+}
+
+// VrConsolePort is the telnet port of the serial console exposed by the vrnetlab based nodes
+const VrConsolePort = 5000
+
+// VrStartupConfigFile is the name of the startup config file the vrnetlab launchers apply to the VM once it has booted,
+// the file is read from the /config dir of the container
+const VrStartupConfigFile = "startup-config.cfg"
+
+// VrStartupConfigBind returns the bind mount of the node config dir holding the startup config
+func VrStartupConfigBind(cfg *types.NodeConfig) string {
+	return filepath.Join(cfg.LabDir, "config") + ":/config"
+}
+
+// VrAddStartupConfigBind mounts the node config dir to the /config dir of the container
+// when the node has a startup config, the launcher applies it to the VM once it has booted
+func VrAddStartupConfigBind(cfg *types.NodeConfig) {
+	if cfg.StartupConfig != "" {
+		cfg.Binds = append(cfg.Binds, VrStartupConfigBind(cfg))
+	}
+}
+
+// VrTLSDir returns the node directory the TLS certificates of the vrnetlab node are written to
+func VrTLSDir(cfg *types.NodeConfig) string {
+	return filepath.Join(cfg.LabDir, "tls")
+}
+
+// VrAddTLSBind mounts the node certificate, key and the lab CA certificate to the /tls dir of the container
+// when TLS is enabled for the node. The VM doesn't read the mounted files,
+// the kinds implementing CertInstaller import the certificate to the VM once it has booted
+func VrAddTLSBind(cfg *types.NodeConfig) {
+	if cfg.TLS {
+		cfg.Binds = append(cfg.Binds, VrTLSDir(cfg)+":/tls:ro")
+	}
+}
+
+// VrGenerateStartupConfig renders the startup-config of the vrnetlab node to the node config dir
+func VrGenerateStartupConfig(cfg *types.NodeConfig) error {
+	if cfg.StartupConfig == "" {
+		return nil
+	}
+	dir := filepath.Join(cfg.LabDir, "config")
+	utils.CreateDirectory(dir, 0777)
+	c, err := os.ReadFile(cfg.StartupConfig)
+	if err != nil {
+		return err
+	}
+	if err := cfg.GenerateConfig(filepath.Join(dir, VrStartupConfigFile), string(c)); err != nil {
+		return fmt.Errorf("node %q: failed to generate startup config: %v", cfg.ShortName, err)
+	}
+	return nil
+}
+
+// env vars the vrnetlab launchers read the qemu CPU and memory settings of the VM from
+const (
+	VrEnvQemuSMP         = "QEMU_SMP"
+	VrEnvQemuCPU         = "QEMU_CPU"
+	VrEnvQemuCPUAffinity = "QEMU_CPU_AFFINITY"
+	VrEnvQemuArgs        = "QEMU_ADDITIONAL_ARGS"
+)
+
+// VrHugepagesDir is the hugetlbfs mount of the container host the VM memory is allocated from
+const VrHugepagesDir = "/dev/hugepages"
+
+// VrApplyVM passes the vm settings of the node to the vrnetlab launcher.
+// The container is pinned to the host CPUs of the vCPUs with the cgroup cpuset unless the cpu-set setting is used,
+// and the launcher is passed the number of vCPUs, their host CPUs, the CPU model and the hugepages backed memory
+// with the env vars. The env vars set by the user are kept, the settings are validated by the topology checks
+func VrApplyVM(cfg *types.NodeConfig) {
+	vm := cfg.VM
+	if vm == nil {
+		return
+	}
+	if cfg.Env == nil {
+		cfg.Env = map[string]string{}
+	}
+	setEnv := func(k, v string) {
+		if _, ok := cfg.Env[k]; !ok {
+			cfg.Env[k] = v
+		}
+	}
+	if vm.CPUPinning != "" {
+		if cpus, err := types.ParseCPUSet(vm.CPUPinning); err == nil {
+			setEnv(VrEnvQemuSMP, strconv.Itoa(len(cpus)))
+			setEnv(VrEnvQemuCPUAffinity, vm.CPUPinning)
+			if cfg.CPUSet == "" {
+				cfg.CPUSet = vm.CPUPinning
+			}
+		}
+	}
+	if vm.CPUModel != "" {
+		setEnv(VrEnvQemuCPU, vm.CPUModel)
+	}
+	if vm.Hugepages != "" {
+		args := "-mem-path " + VrHugepagesDir + " -mem-prealloc"
+		if cfg.Env[VrEnvQemuArgs] != "" {
+			args = cfg.Env[VrEnvQemuArgs] + " " + args
+		}
+		cfg.Env[VrEnvQemuArgs] = args
+		cfg.Binds = append(cfg.Binds, VrHugepagesDir+":"+VrHugepagesDir)
+	}
+}
+
+// IsVrKind returns true for the vrnetlab based kinds
+func IsVrKind(kind string) bool {
+	return strings.HasPrefix(kind, "vr-") || kind == NodeKindGenericVM
+}
+
+// VrHealth queries the vrnetlab healthcheck of the container identified by id
+// and returns the health state of the VM along with the message reported by launch.py
+func VrHealth(ctx context.Context, r runtime.ContainerRuntime, id string) (string, string, error) {
+	stdout, _, rc, err := r.ExecWithExitCode(ctx, id, vrHealthcheckCmd)
+	if err != nil {
+		return "", "", err
+	}
+	state, msg := parseVrHealth(stdout, rc)
+	return state, msg, nil
+}
+
+// parseVrHealth returns the health state and the message from the healthcheck output and exit code.
+// The healthcheck fails without a message until launch.py reports the VM state for the first time
+func parseVrHealth(stdout []byte, rc int) (string, string) {
+	msg := strings.TrimSpace(string(stdout))
+	if rc != 0 {
+		if msg == "" {
+			msg = "starting"
+		}
+		return VrHealthBooting, msg
+	}
+	return VrHealthReady, msg
+}
+
+// VrReady is the Ready probe of the vrnetlab nodes, the node is ready once its VM has booted
+func VrReady(ctx context.Context, r runtime.ContainerRuntime, name string) (bool, error) {
+	state, _, err := VrHealth(ctx, r, name)
+	return state == VrHealthReady, err
+}
+
+// VrShutdown is the Shutdown step of the vrnetlab nodes, it requests the ACPI powerdown of the VM
+// and waits for the qemu process to exit
+func VrShutdown(ctx context.Context, r runtime.ContainerRuntime, name string) error {
+	_, stderr, rc, err := r.ExecWithExitCode(ctx, name, vrPowerdownCmd)
+	if err != nil {
+		return err
+	}
+	if rc != 0 {
+		return fmt.Errorf("failed to request VM powerdown: %s", strings.TrimSpace(string(stderr)))
+	}
+	for {
+		_, _, rc, err := r.ExecWithExitCode(ctx, name, vrQemuRunningCmd)
+		// launch.py exits along with the VM, stopping the container
+		if err != nil || rc != 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("VM hasn't powered down: %v", ctx.Err())
+		case <-time.After(vrShutdownPoll):
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package nodes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestParseVrHealth(t *testing.T) {
+	tests := map[string]struct {
+		stdout    string
+		rc        int
+		wantState string
+		wantMsg   string
+	}{
+		"vm-running": {
+			stdout:    "running\n",
+			rc:        0,
+			wantState: VrHealthReady,
+			wantMsg:   "running",
+		},
+		"vm-starting": {
+			stdout:    "VM starting\n",
+			rc:        1,
+			wantState: VrHealthBooting,
+			wantMsg:   "VM starting",
+		},
+		"no-health-file": {
+			stdout:    "",
+			rc:        1,
+			wantState: VrHealthBooting,
+			wantMsg:   "starting",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			state, msg := parseVrHealth([]byte(tc.stdout), tc.rc)
+			if state != tc.wantState || msg != tc.wantMsg {
+				t.Fatalf("wanted %q %q, got %q %q", tc.wantState, tc.wantMsg, state, msg)
+			}
+		})
+	}
+}
+
+func TestIsVrKind(t *testing.T) {
+	for kind, want := range map[string]bool{
+		NodeKindVrSROS:  true,
+		NodeKindVrXRV9K: true,
+		NodeKindSRL:     false,
+		NodeKindCEOS:    false,
+	} {
+		if got := IsVrKind(kind); got != want {
+			t.Errorf("kind %s: wanted %v, got %v", kind, want, got)
+		}
+	}
+}
+
+func TestVrBootTimeout(t *testing.T) {
+	for kind, want := range map[string]time.Duration{
+		NodeKindVrXRV9K: 30 * time.Minute,
+		NodeKindVrSROS:  VrDefBootTimeout,
+	} {
+		if got := VrBootTimeout(kind); got != want {
+			t.Errorf("kind %s: wanted %v, got %v", kind, want, got)
+		}
+	}
+}
+
+func TestVrGenerateStartupConfig(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "r1.cfg")
+	if err := os.WriteFile(src, []byte("hostname {{ .ShortName }}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &types.NodeConfig{
+		ShortName:     "r1",
+		LabDir:        filepath.Join(dir, "r1"),
+		StartupConfig: src,
+	}
+	if err := VrGenerateStartupConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(filepath.Join(cfg.LabDir, "config", VrStartupConfigFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "hostname r1\n" {
+		t.Errorf("wanted rendered startup config, got %q", got)
+	}
+	if want := filepath.Join(cfg.LabDir, "config") + ":/config"; VrStartupConfigBind(cfg) != want {
+		t.Errorf("wanted bind %q, got %q", want, VrStartupConfigBind(cfg))
+	}
+	VrAddStartupConfigBind(cfg)
+	if !cmp.Equal(cfg.Binds, []string{VrStartupConfigBind(cfg)}) {
+		t.Errorf("wanted the startup config bind, got %v", cfg.Binds)
+	}
+
+	// no startup config, nothing is generated
+	cfg = &types.NodeConfig{ShortName: "r2", LabDir: filepath.Join(dir, "r2")}
+	if err := VrGenerateStartupConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.LabDir, "config")); !os.IsNotExist(err) {
+		t.Errorf("wanted no config dir, got %v", err)
+	}
+	VrAddStartupConfigBind(cfg)
+	if len(cfg.Binds) != 0 {
+		t.Errorf("wanted no binds, got %v", cfg.Binds)
+	}
+}
+
+func TestVrApplyVM(t *testing.T) {
+	cfg := &types.NodeConfig{
+		Env: map[string]string{VrEnvQemuCPU: "Skylake-Server", VrEnvQemuArgs: "-smbios type=1"},
+		VM:  &types.VMConfig{CPUPinning: "4-7", CPUModel: "host", Hugepages: "8GB"},
+	}
+	VrApplyVM(cfg)
+
+	wantEnv := map[string]string{
+		VrEnvQemuSMP:         "4",
+		VrEnvQemuCPUAffinity: "4-7",
+		// the env vars set by the user are kept
+		VrEnvQemuCPU:  "Skylake-Server",
+		VrEnvQemuArgs: "-smbios type=1 -mem-path /dev/hugepages -mem-prealloc",
+	}
+	for k, v := range wantEnv {
+		if cfg.Env[k] != v {
+			t.Errorf("env %s: wanted %q, got %q", k, v, cfg.Env[k])
+		}
+	}
+	if cfg.CPUSet != "4-7" {
+		t.Errorf("wanted cpu-set 4-7, got %q", cfg.CPUSet)
+	}
+	if len(cfg.Binds) != 1 || cfg.Binds[0] != "/dev/hugepages:/dev/hugepages" {
+		t.Errorf("wanted the hugepages bind, got %v", cfg.Binds)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package runner runs the containerlab commands on behalf of the containerlab agent and the API server
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Command is the containerlab command run by the Runner
+type Command struct {
+	// command arguments, e.g. ["deploy", "--topo", "lab.clab.yml"]
+	Args []string
+	// directory the command runs in, the lab directory is created in it
+	Dir string
+	// Lab is the topology file of the lab the command changes, e.g. with deploy or destroy.
+	// The commands changing the same lab run one at a time and are not canceled with the context,
+	// so that a client going away doesn't leave the lab partially deployed or destroyed.
+	// The commands with no lab set run concurrently and are canceled with the context
+	Lab string
+	// outputs of the command, the output is discarded when not set
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// Runner runs the containerlab commands
+type Runner struct {
+	// containerlab binary, the running executable by default
+	Binary string
+	// global flags passed to the commands, e.g. the container runtime
+	GlobalArgs []string
+
+	mu sync.Mutex
+	// locks of the labs changed by the commands
+	labs map[string]*sync.Mutex
+}
+
+// Run runs the command and returns its exit code,
+// an error is returned when the command can't be run
+func (r *Runner) Run(ctx context.Context, c *Command) (int, error) {
+	bin := r.Binary
+	if bin == "" {
+		var err error
+		if bin, err = os.Executable(); err != nil {
+			return 0, err
+		}
+	}
+	args := append(append([]string{}, r.GlobalArgs...), c.Args...)
+
+	var cmd *exec.Cmd
+	if c.Lab != "" {
+		l := r.labLock(c.Lab)
+		l.Lock()
+		defer l.Unlock()
+		cmd = exec.Command(bin, args...)
+	} else {
+		cmd = exec.CommandContext(ctx, bin, args...)
+	}
+	log.Infof("Running containerlab %s", strings.Join(args, " "))
+	cmd.Dir = c.Dir
+	cmd.Stdout, cmd.Stderr = c.Stdout, c.Stderr
+	if err := cmd.Run(); err != nil {
+		if ee, ok := err.(*exec.ExitError); ok {
+			return ee.ExitCode(), nil
+		}
+		return 0, fmt.Errorf("failed to run containerlab: %v", err)
+	}
+	return 0, nil
+}
+
+// labLock returns the lock of the lab
+func (r *Runner) labLock(lab string) *sync.Mutex {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.labs == nil {
+		r.labs = make(map[string]*sync.Mutex)
+	}
+	l, ok := r.labs[lab]
+	if !ok {
+		l = new(sync.Mutex)
+		r.labs[lab] = l
+	}
+	return l
+}
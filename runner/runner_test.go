@@ -0,0 +1,62 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package runner
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	tests := map[string]struct {
+		cmd     *Command
+		wantRC  int
+		wantOut string
+	}{
+		"success": {
+			cmd:     &Command{Args: []string{"-c", "echo deployed"}},
+			wantOut: "deployed\n",
+		},
+		"exit-code": {
+			cmd:     &Command{Args: []string{"-c", "echo failed >&2; exit 3"}, Lab: "/labs/lab.clab.yml"},
+			wantRC:  3,
+			wantOut: "failed\n",
+		},
+		"dir": {
+			cmd:     &Command{Args: []string{"-c", "pwd"}, Dir: "/"},
+			wantOut: "/\n",
+		},
+	}
+	r := &Runner{Binary: "sh"}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			tc.cmd.Stdout, tc.cmd.Stderr = &out, &out
+			rc, err := r.Run(context.Background(), tc.cmd)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rc != tc.wantRC || out.String() != tc.wantOut {
+				t.Errorf("got rc %d, output %q, want rc %d, output %q", rc, out.String(), tc.wantRC, tc.wantOut)
+			}
+		})
+	}
+
+	r = &Runner{Binary: "/nonexistent/containerlab"}
+	if _, err := r.Run(context.Background(), &Command{Args: []string{"inspect"}}); err == nil {
+		t.Errorf("expected an error for the missing binary")
+	}
+}
+
+func TestLabLock(t *testing.T) {
+	r := &Runner{}
+	if r.labLock("/labs/a.clab.yml") != r.labLock("/labs/a.clab.yml") {
+		t.Errorf("the commands of the same lab got different locks")
+	}
+	if r.labLock("/labs/a.clab.yml") == r.labLock("/labs/b.clab.yml") {
+		t.Errorf("the commands of different labs got the same lock")
+	}
+}
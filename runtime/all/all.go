@@ -4,4 +4,5 @@ import (
 	_ "github.com/srl-labs/containerlab/runtime/containerd"
 	_ "github.com/srl-labs/containerlab/runtime/docker"
 	_ "github.com/srl-labs/containerlab/runtime/ignite"
+	_ "github.com/srl-labs/containerlab/runtime/podman"
 )
@@ -0,0 +1,118 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package runtime
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/srl-labs/containerlab/types"
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// cgroupUnlimited is the lowest memory limit reported by cgroup v1 for the cgroups without a limit
+const cgroupUnlimited = 1 << 62
+
+// CgroupStats returns the resource usage of the cgroup of the process pid read from the cgroup filesystem of the host,
+// it serves the runtimes without a stats API
+func CgroupStats(pid int) (*types.ContainerStats, error) {
+	b, err := ioutil.ReadFile(utils.LocalPath(fmt.Sprintf("/proc/%d/cgroup", pid)))
+	if err != nil {
+		return nil, err
+	}
+	return cgroupStats(utils.LocalPath("/sys/fs/cgroup"), string(b))
+}
+
+// cgroupStats returns the resource usage of the cgroup listed in the /proc/<pid>/cgroup file contents
+// from the cgroup filesystem mounted at root, both cgroup v1 and the unified v2 hierarchies are supported
+func cgroupStats(root, procCgroup string) (*types.ContainerStats, error) {
+	var unified, cpuacct, memory string
+	for _, l := range strings.Split(strings.TrimSpace(procCgroup), "\n") {
+		// hierarchy-ID:controller-list:cgroup-path
+		f := strings.SplitN(l, ":", 3)
+		if len(f) != 3 {
+			continue
+		}
+		if f[0] == "0" && f[1] == "" {
+			unified = f[2]
+		}
+		for _, ctrl := range strings.Split(f[1], ",") {
+			switch ctrl {
+			case "cpuacct":
+				cpuacct = filepath.Join(root, f[1], f[2])
+			case "memory":
+				memory = filepath.Join(root, f[1], f[2])
+			}
+		}
+	}
+
+	st := &types.ContainerStats{}
+	switch {
+	case cpuacct != "" && memory != "":
+		usage, err := readCgroupValue(filepath.Join(cpuacct, "cpuacct.usage"))
+		if err != nil {
+			return nil, err
+		}
+		st.CPUUsage = time.Duration(usage)
+		if st.MemoryUsage, err = readCgroupValue(filepath.Join(memory, "memory.usage_in_bytes")); err != nil {
+			return nil, err
+		}
+		if inactive, err := readCgroupStat(filepath.Join(memory, "memory.stat"), "total_inactive_file"); err == nil && inactive < st.MemoryUsage {
+			st.MemoryUsage -= inactive
+		}
+		if st.MemoryLimit, err = readCgroupValue(filepath.Join(memory, "memory.limit_in_bytes")); err != nil {
+			return nil, err
+		}
+		if st.MemoryLimit >= cgroupUnlimited {
+			st.MemoryLimit = 0
+		}
+	case unified != "":
+		dir := filepath.Join(root, unified)
+		usec, err := readCgroupStat(filepath.Join(dir, "cpu.stat"), "usage_usec")
+		if err != nil {
+			return nil, err
+		}
+		st.CPUUsage = time.Duration(usec) * time.Microsecond
+		if st.MemoryUsage, err = readCgroupValue(filepath.Join(dir, "memory.current")); err != nil {
+			return nil, err
+		}
+		if inactive, err := readCgroupStat(filepath.Join(dir, "memory.stat"), "inactive_file"); err == nil && inactive < st.MemoryUsage {
+			st.MemoryUsage -= inactive
+		}
+		// the limit is "max" for the cgroups without a limit
+		st.MemoryLimit, _ = readCgroupValue(filepath.Join(dir, "memory.max"))
+	default:
+		return nil, fmt.Errorf("cpu and memory cgroups not found")
+	}
+	return st, nil
+}
+
+// readCgroupValue reads the single value cgroup file
+func readCgroupValue(path string) (uint64, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64)
+}
+
+// readCgroupStat reads the value of the key from the flat keyed cgroup file, e.g. memory.stat
+func readCgroupStat(path, key string) (uint64, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	for _, l := range strings.Split(string(b), "\n") {
+		f := strings.Fields(l)
+		if len(f) == 2 && f[0] == key {
+			return strconv.ParseUint(f[1], 10, 64)
+		}
+	}
+	return 0, fmt.Errorf("%s not found in %s", key, path)
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package runtime
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestCgroupStats(t *testing.T) {
+	tests := map[string]struct {
+		procCgroup string
+		files      map[string]string
+		want       *types.ContainerStats
+		wantErr    bool
+	}{
+		"v1": {
+			procCgroup: "12:memory:/default/r1\n4:cpu,cpuacct:/default/r1\n1:name=systemd:/default/r1\n",
+			files: map[string]string{
+				"cpu,cpuacct/default/r1/cpuacct.usage":    "2500000000\n",
+				"memory/default/r1/memory.usage_in_bytes": "3000\n",
+				"memory/default/r1/memory.stat":           "cache 1000\ntotal_inactive_file 1000\n",
+				"memory/default/r1/memory.limit_in_bytes": "9223372036854771712\n",
+			},
+			want: &types.ContainerStats{CPUUsage: 2500 * time.Millisecond, MemoryUsage: 2000},
+		},
+		"v2": {
+			procCgroup: "0::/default/r1\n",
+			files: map[string]string{
+				"default/r1/cpu.stat":       "usage_usec 1500000\nuser_usec 1000000\n",
+				"default/r1/memory.current": "4096\n",
+				"default/r1/memory.stat":    "anon 2048\ninactive_file 1024\n",
+				"default/r1/memory.max":     "8192\n",
+			},
+			want: &types.ContainerStats{CPUUsage: 1500 * time.Millisecond, MemoryUsage: 3072, MemoryLimit: 8192},
+		},
+		"v2-unlimited": {
+			procCgroup: "0::/default/r1\n",
+			files: map[string]string{
+				"default/r1/cpu.stat":       "usage_usec 10\n",
+				"default/r1/memory.current": "4096\n",
+				"default/r1/memory.max":     "max\n",
+			},
+			want: &types.ContainerStats{CPUUsage: 10 * time.Microsecond, MemoryUsage: 4096},
+		},
+		"no-cgroups": {
+			procCgroup: "1:name=systemd:/default/r1\n",
+			wantErr:    true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			for p, c := range tc.files {
+				p = filepath.Join(root, p)
+				if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := ioutil.WriteFile(p, []byte(c), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := cgroupStats(root, tc.procCgroup)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("cgroupStats() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if d := cmp.Diff(tc.want, got); d != "" {
+				t.Errorf("cgroupStats() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
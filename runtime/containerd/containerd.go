@@ -4,19 +4,29 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/containerd/containerd"
+	apievents "github.com/containerd/containerd/api/events"
 	"github.com/containerd/containerd/cio"
 	"github.com/containerd/containerd/containers"
 	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/events"
+	"github.com/containerd/containerd/images"
 	"github.com/containerd/containerd/namespaces"
 	"github.com/containerd/containerd/oci"
+	"github.com/containerd/containerd/remotes/docker"
+	"github.com/containerd/containerd/runtime/linux/runctypes"
+	"github.com/containerd/containerd/runtime/v2/runc/options"
+	"github.com/containerd/typeurl"
 	"github.com/containernetworking/cni/libcni"
 	"github.com/containernetworking/cni/pkg/types/current"
 	"github.com/docker/go-units"
@@ -34,6 +44,12 @@ const (
 	cniCache            = "/opt/cni/cache"
 	runtimeName         = "containerd"
 	defaultTimeout      = 30 * time.Second
+	// defaultAddress is the containerd socket used when CONTAINERD_ADDRESS env var is not set
+	defaultAddress = "/run/containerd/containerd.sock"
+	// logDir is the directory the container logs are written to
+	logDir = "/tmp/clab"
+	// cpuCFSPeriod is the CFS period in microseconds the cpu limit of a container is enforced over
+	cpuCFSPeriod = 100000
 )
 
 func init() {
@@ -46,12 +62,18 @@ func init() {
 func (c *ContainerdRuntime) Init(opts ...runtime.RuntimeOption) error {
 	var err error
 	log.Debug("Runtime: containerd")
-	c.client, err = containerd.New("/run/containerd/containerd.sock")
+	address := defaultAddress
+	// containerd socket differs on some distributions, e.g. k3s uses /run/k3s/containerd/containerd.sock
+	if a := os.Getenv("CONTAINERD_ADDRESS"); a != "" {
+		address = a
+	}
+	log.Debugf("Using containerd at %s", address)
+	c.client, err = containerd.New(address)
 	if err != nil {
 		return err
 	}
 	cniPath := utils.GetCNIBinaryPath()
-	binaries := []string{"tuning", "bridge", "host-local"}
+	binaries := []string{"tuning", "bridge", "host-local", "portmap"}
 	for _, binary := range binaries {
 		binary = filepath.Join(cniPath, binary)
 		if _, err := os.Stat(binary); err != nil {
@@ -125,25 +147,115 @@ func (c *ContainerdRuntime) DeleteNet(context.Context) error {
 	return utils.DeleteLinkByName(bridgename)
 }
 
+func (c *ContainerdRuntime) PruneNets(context.Context, bool) ([]string, error) {
+	log.Debug("PruneNets() - containerd runtime doesn't create container networks")
+	return nil, nil
+}
+
 func (c *ContainerdRuntime) PullImageIfRequired(ctx context.Context, imagename string) error {
+	exists, err := c.ImageExists(ctx, imagename)
+	if err != nil {
+		return err
+	}
+	if exists {
+		log.Debugf("Image %s present, skip pulling", imagename)
+		return nil
+	}
+	log.Infof("Pulling %s container image", utils.GetCanonicalImageName(imagename))
+	return c.PullImage(ctx, imagename, nil)
+}
+
+// ImageExists checks if the image is present in the containerd image store by its name or its canonical name
+func (c *ContainerdRuntime) ImageExists(ctx context.Context, imagename string) (bool, error) {
 	log.Debugf("Looking up %s container image", imagename)
 	ctx = namespaces.WithNamespace(ctx, containerdNamespace)
 	if !strings.Contains(imagename, ":") {
 		imagename = imagename + ":latest"
 	}
-	_, err := c.client.GetImage(ctx, imagename)
-	if err == nil {
-		log.Debugf("Image %s present, skip pulling", imagename)
-		return nil
+	// images pulled by containerlab are stored with the canonical name
+	for _, name := range []string{imagename, utils.GetCanonicalImageName(imagename)} {
+		_, err := c.client.GetImage(ctx, name)
+		if err == nil {
+			return true, nil
+		}
+		if !errdefs.IsNotFound(err) {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// ImageDigest returns the digest of the manifest the image was pulled with, containerd images have no IDs
+func (c *ContainerdRuntime) ImageDigest(ctx context.Context, image, _ string) (string, error) {
+	ctx = namespaces.WithNamespace(ctx, containerdNamespace)
+	img, err := c.client.GetImage(ctx, image)
+	if errdefs.IsNotFound(err) {
+		// images pulled by containerlab are stored with the canonical name
+		img, err = c.client.GetImage(ctx, utils.GetCanonicalImageName(image))
+	}
+	if err != nil {
+		return "", err
+	}
+	return img.Target().Digest.String(), nil
+}
+
+// PullImage pulls the image with the registry credentials and stores it with the canonical image name,
+// the download progress is not reported by containerd runtime
+func (c *ContainerdRuntime) PullImage(ctx context.Context, imagename string, opts *types.PullOptions) error {
+	ctx = namespaces.WithNamespace(ctx, containerdNamespace)
+	if opts == nil {
+		opts = &types.PullOptions{}
 	}
 	n := utils.GetCanonicalImageName(imagename)
-	_, err = c.client.Pull(ctx, n, containerd.WithPullUnpack)
+	ref := n
+	if opts.Source != "" {
+		ref = utils.GetCanonicalImageName(opts.Source)
+	}
+	pullOpts := []containerd.RemoteOpt{containerd.WithPullUnpack}
+	if auth := opts.Auth; auth != nil {
+		authorizer := docker.NewDockerAuthorizer(docker.WithAuthCreds(func(string) (string, string, error) {
+			return auth.Username, auth.Password, nil
+		}))
+		pullOpts = append(pullOpts, containerd.WithResolver(docker.NewResolver(docker.ResolverOptions{
+			Hosts: docker.ConfigureDefaultRegistries(docker.WithAuthorizer(authorizer)),
+		})))
+	}
+	img, err := c.client.Pull(ctx, ref, pullOpts...)
 	if err != nil {
 		return err
 	}
+	if ref == n {
+		return nil
+	}
+	// the image pulled from the source is stored with the image name as well
+	is := c.client.ImageService()
+	record := images.Image{Name: n, Target: img.Target()}
+	if _, err := is.Create(ctx, record); err != nil {
+		if !errdefs.IsAlreadyExists(err) {
+			return err
+		}
+		if _, err := is.Update(ctx, record, "target"); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// BuildImage is not supported by containerd runtime, containerd has no image builder
+func (*ContainerdRuntime) BuildImage(_ context.Context, imagename string, _ *types.BuildConfig) error {
+	return fmt.Errorf("building image %s is not supported by %s runtime, build it with nerdctl or buildctl and set it as the node image", imagename, runtimeName)
+}
+
+// ConnectMgmtNet is not supported by containerd runtime, the management network is attached with CNI on container creation
+func (*ContainerdRuntime) ConnectMgmtNet(_ context.Context, id string, _ *types.NodeConfig) error {
+	return fmt.Errorf("connecting container %s to the management network is not supported by %s runtime", id, runtimeName)
+}
+
+// DisconnectMgmtNet is not supported by containerd runtime
+func (*ContainerdRuntime) DisconnectMgmtNet(_ context.Context, id string) error {
+	return fmt.Errorf("disconnecting container %s from the management network is not supported by %s runtime", id, runtimeName)
+}
+
 func (c *ContainerdRuntime) CreateContainer(ctx context.Context, node *types.NodeConfig) (interface{}, error) {
 	ctx = namespaces.WithNamespace(ctx, containerdNamespace)
 
@@ -166,20 +278,48 @@ func (c *ContainerdRuntime) CreateContainer(ctx context.Context, node *types.Nod
 		return nil, err
 	}
 
-	mounts := make([]specs.Mount, len(node.Binds))
+	mounts := make([]specs.Mount, 0, len(node.Binds)+len(node.Tmpfs))
 
-	for idx, mount := range node.Binds {
+	for _, mount := range node.Binds {
 		s := strings.Split(mount, ":")
 
+		// the propagation set with the bind options replaces the default one
+		propagation := types.BindPropagation(mount)
+		if propagation == "" {
+			propagation = "rprivate"
+		}
 		m := specs.Mount{
-			Source:      s[0],
+			Source:      utils.HostPath(s[0]),
 			Destination: s[1],
-			Options:     []string{"rbind", "rprivate"},
+			Options:     []string{"rbind", propagation},
+		}
+		if len(s) == 3 {
+			for _, o := range strings.Split(s[2], ",") {
+				if o != propagation {
+					m.Options = append(m.Options, o)
+				}
+			}
+		}
+		mounts = append(mounts, m)
+	}
+	// the nested tmpfs paths are mounted after their parents
+	tmpfs := make([]string, 0, len(node.Tmpfs))
+	for dst := range node.Tmpfs {
+		tmpfs = append(tmpfs, dst)
+	}
+	sort.Strings(tmpfs)
+	for _, dst := range tmpfs {
+		opts := node.Tmpfs[dst]
+		m := specs.Mount{
+			Type:        "tmpfs",
+			Source:      "tmpfs",
+			Destination: dst,
+			Options:     []string{"nosuid", "nodev"},
 		}
-		if len(mount) == 3 {
-			m.Options = append(m.Options, strings.Split(s[2], ",")...)
+		if opts != "" {
+			m.Options = append(m.Options, strings.Split(opts, ",")...)
 		}
-		mounts[idx] = m
+		mounts = append(mounts, m)
 	}
 
 	opts := []oci.SpecOpts{
@@ -188,13 +328,16 @@ func (c *ContainerdRuntime) CreateContainer(ctx context.Context, node *types.Nod
 		oci.WithHostname(node.ShortName),
 		WithSysctls(node.Sysctls),
 		oci.WithoutRunMount,
-		oci.WithPrivileged,
 		oci.WithHostLocaltime,
 		oci.WithNamespacedCgroup(),
-		oci.WithAllDevicesAllowed,
 		oci.WithDefaultUnixDevices,
 		oci.WithNewPrivileges,
 	}
+	if node.Privileged() {
+		opts = append(opts, oci.WithPrivileged, oci.WithAllDevicesAllowed)
+	} else {
+		opts = append(opts, WithCapabilities(node.CapAdd, node.CapDrop))
+	}
 	if len(cmd) > 0 {
 		opts = append(opts, oci.WithProcessArgs(cmd...))
 	}
@@ -202,10 +345,40 @@ func (c *ContainerdRuntime) CreateContainer(ctx context.Context, node *types.Nod
 		opts = append(opts, oci.WithUser(node.User))
 	}
 
+	if node.DNS != nil {
+		// the resolv.conf with the node DNS settings is kept in the node directory
+		if err := os.MkdirAll(node.LabDir, 0755); err != nil {
+			return nil, err
+		}
+		resolvConf := filepath.Join(node.LabDir, "resolv.conf")
+		if err := ioutil.WriteFile(resolvConf, node.DNS.ResolvConf(), 0644); err != nil {
+			return nil, err
+		}
+		mounts = append(mounts, specs.Mount{
+			Source:      resolvConf,
+			Destination: "/etc/resolv.conf",
+			Options:     []string{"rbind", "rprivate", "ro"},
+		})
+	}
+
 	if len(mounts) > 0 {
 		opts = append(opts, oci.WithMounts(mounts))
 	}
 
+	nanoCPUs, memory, err := node.ResourceLimits()
+	if err != nil {
+		return nil, err
+	}
+	if nanoCPUs > 0 {
+		opts = append(opts, oci.WithCPUCFS(nanoCPUs*cpuCFSPeriod/1e9, cpuCFSPeriod))
+	}
+	if memory > 0 {
+		opts = append(opts, oci.WithMemoryLimit(uint64(memory)))
+	}
+	if node.CPUSet != "" {
+		opts = append(opts, oci.WithCPUs(node.CPUSet))
+	}
+
 	var cnic *libcni.CNIConfig
 	var cncl *libcni.NetworkConfigList
 	var cnirc *libcni.RuntimeConf
@@ -394,6 +567,30 @@ type portMapping struct {
 	Protocol      string `json:"protocol"`
 }
 
+// WithCapabilities drops the capabilities from and then adds the capabilities to the default capabilities of the container,
+// the capabilities are in the CAP_ prefixed form or ALL
+func WithCapabilities(add, drop []string) oci.SpecOpts {
+	return func(ctx context.Context, client oci.Client, c *containers.Container, s *specs.Spec) error {
+		var opts []oci.SpecOpts
+		if _, ok := utils.StringInSlice(drop, types.CapAll); ok {
+			opts = append(opts, oci.WithCapabilities(nil))
+		} else {
+			opts = append(opts, oci.WithDroppedCapabilities(drop))
+		}
+		if _, ok := utils.StringInSlice(add, types.CapAll); ok {
+			opts = append(opts, oci.WithAllKnownCapabilities)
+		} else {
+			opts = append(opts, oci.WithAddedCapabilities(add))
+		}
+		for _, o := range opts {
+			if err := o(ctx, client, c, s); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
 func WithSysctls(sysctls map[string]string) oci.SpecOpts {
 	return func(ctx context.Context, client oci.Client, c *containers.Container, s *specs.Spec) error {
 		if s.Linux == nil {
@@ -410,11 +607,13 @@ func WithSysctls(sysctls map[string]string) oci.SpecOpts {
 }
 
 func (c *ContainerdRuntime) StartContainer(ctx context.Context, containername string) error {
+	ctx = namespaces.WithNamespace(ctx, containerdNamespace)
 	container, err := c.client.LoadContainer(ctx, containername)
 	if err != nil {
 		return err
 	}
-	task, err := container.NewTask(ctx, cio.LogFile("/tmp/clab/"+containername+".log"))
+	utils.CreateDirectory(logDir, 0755)
+	task, err := container.NewTask(ctx, cio.LogFile(filepath.Join(logDir, containername+".log")))
 	if err != nil {
 		return err
 	}
@@ -478,6 +677,103 @@ func (c *ContainerdRuntime) StopContainer(ctx context.Context, containername str
 	return nil
 }
 
+// GracefulStopContainer sends SIGTERM to the running task of the container and waits for it to exit within the timeout.
+// The task still running after the timeout is killed when the container is deleted
+func (c *ContainerdRuntime) GracefulStopContainer(ctx context.Context, containername string, timeout time.Duration) error {
+	ctask, err := c.getContainerTask(ctx, containername)
+	if err != nil {
+		log.Debugf("container %s: %v", containername, err)
+		return nil
+	}
+	ctx = namespaces.WithNamespace(ctx, containerdNamespace)
+	status, err := ctask.Status(ctx)
+	if err != nil {
+		return err
+	}
+	if status.Status != containerd.Running {
+		return nil
+	}
+	exitCh, err := ctask.Wait(ctx)
+	if err != nil {
+		return err
+	}
+	if err := ctask.Kill(ctx, syscall.SIGTERM); err != nil {
+		return err
+	}
+	tctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if err := waitContainerStop(tctx, exitCh); err != nil {
+		return fmt.Errorf("container %s hasn't stopped within %s: %v", containername, timeout, err)
+	}
+	return nil
+}
+
+// PauseContainer pauses the task of the container
+func (c *ContainerdRuntime) PauseContainer(ctx context.Context, containername string) error {
+	ctask, err := c.getContainerTask(ctx, containername)
+	if err != nil {
+		return err
+	}
+	return ctask.Pause(namespaces.WithNamespace(ctx, containerdNamespace))
+}
+
+// UnpauseContainer resumes the paused task of the container
+func (c *ContainerdRuntime) UnpauseContainer(ctx context.Context, containername string) error {
+	ctask, err := c.getContainerTask(ctx, containername)
+	if err != nil {
+		return err
+	}
+	return ctask.Resume(namespaces.WithNamespace(ctx, containerdNamespace))
+}
+
+// CheckpointContainer checkpoints the task of the container to the dir with CRIU and deletes the exited task
+func (c *ContainerdRuntime) CheckpointContainer(ctx context.Context, containername, dir string) error {
+	ctask, err := c.getContainerTask(ctx, containername)
+	if err != nil {
+		return err
+	}
+	ctx = namespaces.WithNamespace(ctx, containerdNamespace)
+	exitCh, err := ctask.Wait(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := ctask.Checkpoint(ctx, containerd.WithCheckpointImagePath(dir), withCheckpointExit); err != nil {
+		return err
+	}
+	<-exitCh
+	_, err = ctask.Delete(ctx)
+	return err
+}
+
+// withCheckpointExit makes the task exit once it is checkpointed
+func withCheckpointExit(r *containerd.CheckpointTaskInfo) error {
+	switch opts := r.Options.(type) {
+	case *options.CheckpointOptions:
+		opts.Exit = true
+	case *runctypes.CheckpointOptions:
+		opts.Exit = true
+	default:
+		return fmt.Errorf("unsupported checkpoint options of runtime %s", r.Runtime())
+	}
+	return nil
+}
+
+// RestoreContainer creates the task of the container from the checkpoint saved in the dir and starts it
+func (c *ContainerdRuntime) RestoreContainer(ctx context.Context, containername, dir string) error {
+	ctx = namespaces.WithNamespace(ctx, containerdNamespace)
+	container, err := c.client.LoadContainer(ctx, containername)
+	if err != nil {
+		return err
+	}
+	utils.CreateDirectory(logDir, 0755)
+	task, err := container.NewTask(ctx, cio.LogFile(filepath.Join(logDir, containername+".log")),
+		containerd.WithRestoreImagePath(dir))
+	if err != nil {
+		return err
+	}
+	return task.Start(ctx)
+}
+
 func waitContainerStop(ctx context.Context, exitCh <-chan containerd.ExitStatus) error {
 	select {
 	case <-ctx.Done():
@@ -509,21 +805,16 @@ func (c *ContainerdRuntime) ListContainers(ctx context.Context, filter []*types.
 	return c.produceGenericContainerList(ctx, containerlist)
 }
 
-// TODO this will probably not work. need to work out the exact filter format
+// GetContainer returns the container by its ID, containers are created with the node long name as the ID
 func (c *ContainerdRuntime) GetContainer(ctx context.Context, containerID string) (*types.GenericContainer, error) {
-	var ctr *types.GenericContainer
-	gFilter := types.GenericFilter{
-		FilterType: "name",
-		Field:      "",
-		Operator:   "",
-		Match:      containerID,
-	}
-	ctrs, err := c.ListContainers(ctx, []*types.GenericFilter{&gFilter})
+	ctx = namespaces.WithNamespace(ctx, containerdNamespace)
+	cont, err := c.client.LoadContainer(ctx, containerID)
 	if err != nil {
-		return ctr, err
+		return nil, err
 	}
-	if len(ctrs) != 1 {
-		return ctr, fmt.Errorf("found unexpected number of containers: %d", len(ctrs))
+	ctrs, err := c.produceGenericContainerList(ctx, []containerd.Container{cont})
+	if err != nil {
+		return nil, err
 	}
 	return &ctrs[0], nil
 }
@@ -577,6 +868,7 @@ func (c *ContainerdRuntime) produceGenericContainerList(ctx context.Context, inp
 		ctr.ShortID = ctr.ID
 		ctr.Image = info.Image
 		ctr.Labels = info.Labels
+		ctr.Created = info.CreatedAt
 
 		ctr.NetworkSettings, err = extractIPInfoFromLabels(ctr.Labels)
 		if err != nil {
@@ -590,9 +882,10 @@ func (c *ContainerdRuntime) produceGenericContainerList(ctx context.Context, inp
 			// In docker/CRI-containerd plugin, the task will be deleted
 			// when it exits. So, the status will be "created" for this
 			// case.
-			if errdefs.IsNotFound(err) {
-				taskfound = false
+			if !errdefs.IsNotFound(err) {
+				return nil, err
 			}
+			taskfound = false
 		}
 		if taskfound {
 			status, err := task.Status(ctx)
@@ -653,24 +946,39 @@ func (c *ContainerdRuntime) GetNSPath(ctx context.Context, containername string)
 	if err != nil {
 		return "", err
 	}
-	return "/proc/" + strconv.Itoa(int(task.Pid())) + "/ns/net", nil
+	return utils.LocalPath("/proc/" + strconv.Itoa(int(task.Pid())) + "/ns/net"), nil
+}
+
+// ContainerStats returns the resource usage of the container read from the cgroup of its task
+func (c *ContainerdRuntime) ContainerStats(ctx context.Context, containername string) (*types.ContainerStats, error) {
+	task, err := c.getContainerTask(ctx, containername)
+	if err != nil {
+		return nil, err
+	}
+	return runtime.CgroupStats(int(task.Pid()))
 }
+
 func (c *ContainerdRuntime) Exec(ctx context.Context, containername string, cmd []string) ([]byte, []byte, error) {
+	stdout, stderr, _, err := c.exec(ctx, containername, cmd, false)
+	return stdout, stderr, err
+}
+
+func (c *ContainerdRuntime) ExecWithExitCode(ctx context.Context, containername string, cmd []string) ([]byte, []byte, int, error) {
 	return c.exec(ctx, containername, cmd, false)
 }
 
 func (c *ContainerdRuntime) ExecNotWait(ctx context.Context, containername string, cmd []string) error {
-	_, _, err := c.exec(ctx, containername, cmd, true)
+	_, _, _, err := c.exec(ctx, containername, cmd, true)
 	return err
 }
 
-func (c *ContainerdRuntime) exec(ctx context.Context, containername string, cmd []string, detach bool) ([]byte, []byte, error) {
+func (c *ContainerdRuntime) exec(ctx context.Context, containername string, cmd []string, detach bool) ([]byte, []byte, int, error) {
 
 	clabExecId := "clabexec"
 	ctx = namespaces.WithNamespace(ctx, containerdNamespace)
 	container, err := c.client.LoadContainer(ctx, containername)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, 0, err
 	}
 
 	var stdinbuf, stdoutbuf, stderrbuf bytes.Buffer
@@ -680,14 +988,14 @@ func (c *ContainerdRuntime) exec(ctx context.Context, containername string, cmd
 
 	spec, err := container.Spec(ctx)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, 0, err
 	}
 	pspec := spec.Process
 	pspec.Terminal = false
 	pspec.Args = cmd
 	task, err := container.Task(ctx, nil)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, 0, err
 	}
 
 	needToDelete := true
@@ -700,14 +1008,14 @@ func (c *ContainerdRuntime) exec(ctx context.Context, containername string, cmd
 		log.Debugf("Deleting old process with exec-id %s", clabExecId)
 		_, err := p.Delete(ctx, containerd.WithProcessKill)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, 0, err
 		}
 	}
 
 	process, err := task.Exec(ctx, clabExecId, pspec, ioCreator)
 	//task, err := container.NewTask(ctx, cio.NewCreator(cio_opt))
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, 0, err
 	}
 
 	var statusC <-chan containerd.ExitStatus
@@ -726,23 +1034,36 @@ func (c *ContainerdRuntime) exec(ctx context.Context, containername string, cmd
 
 		statusC, err = process.Wait(ctx)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, 0, err
 		}
 	}
 
 	if err := process.Start(ctx); err != nil {
-		return nil, nil, err
+		return nil, nil, 0, err
 	}
+	var exitCode int
 	if !detach {
 		status := <-statusC
 		code, _, err := status.Result()
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, 0, err
 		}
 
 		log.Infof("Exit code: %d", code)
+		exitCode = int(code)
 	}
-	return stdoutbuf.Bytes(), stderrbuf.Bytes(), nil
+	return stdoutbuf.Bytes(), stderrbuf.Bytes(), exitCode, nil
+}
+
+// CopyToContainer is not supported by containerd runtime, the containers run on the local host
+// and mount the files of the lab directory as is
+func (*ContainerdRuntime) CopyToContainer(_ context.Context, id, _ string, _ io.Reader) error {
+	return fmt.Errorf("copying files to container %s is not supported by %s runtime", id, runtimeName)
+}
+
+func (*ContainerdRuntime) DeleteVolume(_ context.Context, name string) error {
+	log.Debugf("DeleteVolume() - containerd runtime doesn't create named volumes, skipping %s", name)
+	return nil
 }
 
 func (c *ContainerdRuntime) DeleteContainer(ctx context.Context, containerID string) error {
@@ -779,3 +1100,116 @@ func (c *ContainerdRuntime) DeleteContainer(ctx context.Context, containerID str
 
 	return nil
 }
+
+// eventTopics are the topics of the containerd events reported as the container events
+var eventTopics = []string{
+	`topic=="/containers/create"`,
+	`topic=="/tasks/start"`,
+	`topic=="/tasks/exit"`,
+	`topic=="/tasks/oom"`,
+	`topic=="/containers/delete"`,
+}
+
+// Events streams the life-cycle events of the containers matching the label filters from the containerd events service.
+// The labels of the containers are loaded on their first event and kept to report their removal
+func (c *ContainerdRuntime) Events(ctx context.Context, filter []*types.GenericFilter) (<-chan types.ContainerEvent, <-chan error) {
+	ctx = namespaces.WithNamespace(ctx, containerdNamespace)
+	envs, envErrs := c.client.Subscribe(ctx, eventTopics...)
+
+	out := make(chan types.ContainerEvent)
+	errs := make(chan error, 1)
+	labels := map[string]map[string]string{}
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case env := <-envs:
+				if env.Namespace != containerdNamespace {
+					continue
+				}
+				ev, ok := containerdEvent(env)
+				if !ok {
+					continue
+				}
+				if _, ok := labels[ev.ContainerID]; !ok && ev.State != types.EventRemoved {
+					if cont, err := c.client.LoadContainer(ctx, ev.ContainerID); err == nil {
+						labels[ev.ContainerID], _ = cont.Labels(ctx)
+					}
+				}
+				ev.Labels = labels[ev.ContainerID]
+				if ev.State == types.EventRemoved {
+					delete(labels, ev.ContainerID)
+				}
+				if !matchLabels(filter, ev.Labels) {
+					continue
+				}
+				select {
+				case out <- ev:
+				case <-ctx.Done():
+					errs <- ctx.Err()
+					return
+				}
+			case err := <-envErrs:
+				errs <- err
+				return
+			}
+		}
+	}()
+	return out, errs
+}
+
+// containerdEvent returns the container event of the containerd event envelope,
+// the exits of the exec processes are not reported
+func containerdEvent(env *events.Envelope) (types.ContainerEvent, bool) {
+	ev := types.ContainerEvent{Time: env.Timestamp}
+	e, err := typeurl.UnmarshalAny(env.Event)
+	if err != nil {
+		log.Debugf("failed to decode containerd event %s: %v", env.Topic, err)
+		return ev, false
+	}
+	switch e := e.(type) {
+	case *apievents.ContainerCreate:
+		ev.ContainerID, ev.State = e.ID, types.EventCreated
+	case *apievents.TaskStart:
+		ev.ContainerID, ev.State = e.ContainerID, types.EventRunning
+	case *apievents.TaskExit:
+		if e.ID != e.ContainerID {
+			return ev, false
+		}
+		ev.ContainerID, ev.State, ev.ExitCode = e.ContainerID, types.EventExited, int(e.ExitStatus)
+	case *apievents.TaskOOM:
+		ev.ContainerID, ev.State = e.ContainerID, types.EventOOM
+	case *apievents.ContainerDelete:
+		ev.ContainerID, ev.State = e.ID, types.EventRemoved
+	default:
+		return ev, false
+	}
+	// the containers are created with the node long name as the ID
+	ev.ContainerName = ev.ContainerID
+	return ev, true
+}
+
+// matchLabels returns true when the labels match all the label filters
+func matchLabels(filter []*types.GenericFilter, labels map[string]string) bool {
+	for _, f := range filter {
+		if f.FilterType != "label" {
+			continue
+		}
+		v, ok := labels[f.Field]
+		switch f.Operator {
+		case "exists":
+			if !ok {
+				return false
+			}
+		case "=":
+			if !ok || v != f.Match {
+				return false
+			}
+		case "!=":
+			if v == f.Match {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,182 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package docker
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	dockerTypes "github.com/docker/docker/api/types"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/types"
+)
+
+// BuildImage builds the image with the given name from the Dockerfile and the context directory of the build config,
+// the build output is logged at debug level
+func (c *DockerRuntime) BuildImage(ctx context.Context, imageName string, build *types.BuildConfig) error {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeBuildContext(pw, build.Context, build.GetDockerfile()))
+	}()
+	defer pr.Close()
+
+	args := make(map[string]*string, len(build.Args))
+	for k, v := range build.Args {
+		v := v
+		args[k] = &v
+	}
+	resp, err := c.Client.ImageBuild(ctx, pr, dockerTypes.ImageBuildOptions{
+		Tags:        []string{imageName},
+		Dockerfile:  filepath.ToSlash(build.GetDockerfile()),
+		BuildArgs:   args,
+		Remove:      true,
+		ForceRemove: true,
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var m struct {
+			Stream string `json:"stream"`
+			Error  string `json:"error"`
+		}
+		if err := dec.Decode(&m); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if m.Error != "" {
+			return errors.New(m.Error)
+		}
+		if s := strings.TrimSpace(m.Stream); s != "" {
+			log.Debugf("build %s: %s", imageName, s)
+		}
+	}
+}
+
+// writeBuildContext writes the tar archive of the build context directory without the files matching
+// the .dockerignore patterns, the Dockerfile is always included
+func writeBuildContext(w io.Writer, dir, dockerfile string) error {
+	ignore, err := readDockerignore(filepath.Join(dir, ".dockerignore"))
+	if err != nil {
+		return err
+	}
+	tw := tar.NewWriter(w)
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel != filepath.ToSlash(filepath.Clean(dockerfile)) && ignored(rel, ignore) {
+			// the ignored directories are walked when their files can be re-included with the ! patterns
+			if info.IsDir() && !hasExceptions(ignore) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		var link string
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = rel
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// readDockerignore returns the patterns of the .dockerignore file, none when the file doesn't exist
+func readDockerignore(path string) ([]string, error) {
+	b, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var patterns []string
+	for _, l := range strings.Split(string(b), "\n") {
+		l = strings.TrimSpace(l)
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		neg := strings.HasPrefix(l, "!")
+		p := filepath.ToSlash(filepath.Clean(strings.TrimPrefix(strings.TrimPrefix(l, "!"), "/")))
+		if neg {
+			p = "!" + p
+		}
+		patterns = append(patterns, p)
+	}
+	return patterns, nil
+}
+
+// ignored returns true when the path relative to the build context matches the .dockerignore patterns,
+// the last matching pattern wins and the patterns prefixed with ! re-include the matching paths.
+// A pattern matching a directory matches the paths inside it as well
+func ignored(rel string, patterns []string) bool {
+	res := false
+	for _, p := range patterns {
+		neg := strings.HasPrefix(p, "!")
+		p = strings.TrimPrefix(p, "!")
+		if matchPathPrefix(p, rel) {
+			res = !neg
+		}
+	}
+	return res
+}
+
+// hasExceptions returns true when any of the patterns re-includes the matching paths
+func hasExceptions(patterns []string) bool {
+	for _, p := range patterns {
+		if strings.HasPrefix(p, "!") {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPathPrefix returns true when the pattern matches the path or any of its parent directories
+func matchPathPrefix(pattern, rel string) bool {
+	for path := rel; path != "." && path != "/"; path = filepath.ToSlash(filepath.Dir(path)) {
+		if ok, _ := filepath.Match(pattern, path); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriteBuildContext(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"Dockerfile":         "FROM alpine:3\n",
+		".dockerignore":      "# comment\n*.log\nbuild\n!build/keep.txt\nDockerfile\n",
+		"run.sh":             "echo 1\n",
+		"debug.log":          "",
+		"build/out.bin":      "",
+		"build/keep.txt":     "",
+		"scripts/setup.sh":   "",
+		"scripts/nested.log": "",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	if err := writeBuildContext(buf, dir, "Dockerfile"); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	tr := tar.NewReader(buf)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, hdr.Name)
+	}
+	sort.Strings(got)
+	// nested.log doesn't match *.log, as the patterns are matched from the context root like docker does
+	want := []string{".dockerignore", "Dockerfile", "build/keep.txt", "run.sh", "scripts", "scripts/nested.log", "scripts/setup.sh"}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("build context mismatch (-want +got):\n%s", d)
+	}
+}
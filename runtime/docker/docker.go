@@ -7,9 +7,14 @@ package docker
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/http"
+	"os"
 	"path"
 	"strconv"
 	"strings"
@@ -23,15 +28,21 @@ import (
 	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/google/shlex"
 	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/firewall"
 	"github.com/srl-labs/containerlab/runtime"
 	"github.com/srl-labs/containerlab/types"
 	"github.com/srl-labs/containerlab/utils"
+	"github.com/vishvananda/netlink"
 )
 
 const (
 	runtimeName    = "docker"
 	sysctlBase     = "/proc/sys"
 	defaultTimeout = 30 * time.Second
+	// ID of the checkpoints created in the node checkpoint dirs
+	checkpointID = "clab"
+	// defaultMgmtNet is the management network the labs share by default
+	defaultMgmtNet = "clab"
 )
 
 func init() {
@@ -51,14 +62,32 @@ type DockerRuntime struct {
 func (c *DockerRuntime) Init(opts ...runtime.RuntimeOption) error {
 	var err error
 	log.Debug("Runtime: Docker")
-	c.Client, err = dockerC.NewClientWithOpts(dockerC.FromEnv, dockerC.WithAPIVersionNegotiation())
-	if err != nil {
-		return err
-	}
 	for _, o := range opts {
 		o(c)
 	}
-	return nil
+	if c.config.Host == "" {
+		c.config.Host = os.Getenv("DOCKER_HOST")
+	}
+	clientOpts := []dockerC.Opt{dockerC.FromEnv, dockerC.WithAPIVersionNegotiation()}
+	switch {
+	case strings.HasPrefix(c.config.Host, "ssh://"):
+		log.Debugf("Using docker daemon at %s over ssh", c.config.Host)
+		dialer, err := sshDialer(c.config.Host)
+		if err != nil {
+			return err
+		}
+		clientOpts = append(clientOpts,
+			dockerC.WithHTTPClient(&http.Client{Transport: &http.Transport{DialContext: dialer}}),
+			// the host is not used to connect, the dialer connects to the daemon over ssh
+			dockerC.WithHost("http://docker.example.com"),
+			dockerC.WithDialContext(dialer),
+		)
+	case c.config.Host != "":
+		log.Debugf("Using docker daemon at %s", c.config.Host)
+		clientOpts = append(clientOpts, dockerC.WithHost(c.config.Host))
+	}
+	c.Client, err = dockerC.NewClientWithOpts(clientOpts...)
+	return err
 }
 
 func (c *DockerRuntime) WithKeepMgmtNet() {
@@ -71,6 +100,7 @@ func (c *DockerRuntime) WithConfig(cfg *runtime.RuntimeConfig) {
 	c.config.Timeout = cfg.Timeout
 	c.config.Debug = cfg.Debug
 	c.config.GracefulShutdown = cfg.GracefulShutdown
+	c.config.Host = cfg.Host
 	if c.config.Timeout <= 0 {
 		c.config.Timeout = defaultTimeout
 	}
@@ -91,8 +121,13 @@ func (c *DockerRuntime) CreateNet(ctx context.Context) (err error) {
 	log.Debugf("Checking if docker network '%s' exists", c.Mgmt.Network)
 	netResource, err := c.Client.NetworkInspect(nctx, c.Mgmt.Network, dockerTypes.NetworkInspectOptions{})
 	switch {
+	case dockerC.IsErrNotFound(err) && c.Mgmt.External && !externalBridgeExists(c.config.Host, bridgeName):
+		return fmt.Errorf("external management network %q is not found, create it or set the name of the existing bridge with mgmt.bridge", c.Mgmt.Network)
 	case dockerC.IsErrNotFound(err):
 		log.Debugf("Network '%s' does not exist", c.Mgmt.Network)
+		if c.Mgmt.External {
+			log.Infof("Attaching management network '%s' to the existing bridge %s", c.Mgmt.Network, bridgeName)
+		}
 		log.Infof("Creating docker network: Name='%s', IPv4Subnet='%s', IPv6Subnet='%s', MTU='%s'",
 			c.Mgmt.Network, c.Mgmt.IPv4Subnet, c.Mgmt.IPv6Subnet, c.Mgmt.MTU)
 
@@ -176,6 +211,12 @@ func (c *DockerRuntime) CreateNet(ctx context.Context) (err error) {
 
 	log.Debugf("Docker network '%s', bridge name '%s'", c.Mgmt.Network, bridgeName)
 
+	// the bridge of a remote docker daemon can't be tuned from this host
+	if runtime.IsRemoteHost(c.config.Host) {
+		log.Debugf("Skipping the setup of %s bridge on the remote docker host", bridgeName)
+		return nil
+	}
+
 	log.Debug("Disable RPF check on the docker host")
 	err = setSysctl("net/ipv4/conf/all/rp_filter", 0)
 	if err != nil {
@@ -199,13 +240,24 @@ func (c *DockerRuntime) CreateNet(ctx context.Context) (err error) {
 	if err != nil {
 		log.Warnf("failed to disable TX checksum offloading for the %s bridge interface: %v", bridgeName, err)
 	}
+
+	// the lab is deployed without the forwarding rules when the firewall is not available
+	fw, err := firewall.New(c.Mgmt.Firewall)
+	if err != nil {
+		log.Warnf("failed to initialize the firewall, skipping the forwarding rules for the %s bridge: %v", bridgeName, err)
+		return nil
+	}
+	log.Debugf("Installing %s forwarding rules for the %s bridge", fw.Name(), bridgeName)
+	if err = fw.InstallForwardingRules(bridgeName); err != nil {
+		log.Warnf("failed to install %s forwarding rules for the %s bridge: %v", fw.Name(), bridgeName, err)
+	}
 	return nil
 }
 
 // DeleteNet deletes a docker bridge
 func (c *DockerRuntime) DeleteNet(ctx context.Context) (err error) {
 	network := c.Mgmt.Network
-	if network == "bridge" || c.config.KeepMgmtNet {
+	if network == "bridge" || c.config.KeepMgmtNet || c.Mgmt.External {
 		log.Debugf("Skipping deletion of '%s' network", network)
 		return nil
 	}
@@ -216,6 +268,11 @@ func (c *DockerRuntime) DeleteNet(ctx context.Context) (err error) {
 	if err != nil {
 		return err
 	}
+	// the networks created outside of containerlab are shared with other tools
+	if _, ok := nres.Labels["containerlab"]; !ok {
+		log.Debugf("Skipping deletion of '%s' network not created by containerlab", network)
+		return nil
+	}
 	numEndpoints := len(nres.Containers)
 	if numEndpoints > 0 {
 		if c.config.Debug {
@@ -226,6 +283,8 @@ func (c *DockerRuntime) DeleteNet(ctx context.Context) (err error) {
 		}
 		return nil
 	}
+	c.deleteForwardingRules(nres)
+
 	err = c.Client.NetworkRemove(nctx, network)
 	if err != nil {
 		return err
@@ -233,6 +292,114 @@ func (c *DockerRuntime) DeleteNet(ctx context.Context) (err error) {
 	return nil
 }
 
+// deleteForwardingRules deletes the firewall rules allowing the forwarding of the traffic of the network bridge,
+// the rules of a remote docker host are left to the host
+func (c *DockerRuntime) deleteForwardingRules(nres dockerTypes.NetworkResource) {
+	bridgeName := nres.Options["com.docker.network.bridge.name"]
+	if bridgeName == "" && len(nres.ID) >= 12 {
+		bridgeName = "br-" + nres.ID[:12]
+	}
+	if runtime.IsRemoteHost(c.config.Host) {
+		return
+	}
+	if fw, err := firewall.New(c.Mgmt.Firewall); err != nil {
+		log.Warnf("failed to initialize the firewall, skipping the forwarding rules of the %s bridge: %v", bridgeName, err)
+	} else if err = fw.DeleteForwardingRules(bridgeName); err != nil {
+		log.Warnf("failed to delete %s forwarding rules for the %s bridge: %v", fw.Name(), bridgeName, err)
+	}
+}
+
+// externalBridgeExists returns true when the existing bridge is set for the external management network,
+// the bridges of a remote docker host are expected to exist
+func externalBridgeExists(host, bridge string) bool {
+	if bridge == "" {
+		return false
+	}
+	if runtime.IsRemoteHost(host) {
+		return true
+	}
+	_, err := netlink.LinkByName(bridge)
+	return err == nil
+}
+
+// PruneNets removes containerlab docker networks that have no endpoints and aren't used by the lab containers in any state,
+// e.g. the stopped containers of a lab paused with a checkpoint.
+// The default management network is kept as the labs being deployed attach to it
+func (c *DockerRuntime) PruneNets(ctx context.Context, dryRun bool) ([]string, error) {
+	nctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	f := filters.NewArgs()
+	f.Add("label", "containerlab")
+	nets, err := c.Client.NetworkList(nctx, dockerTypes.NetworkListOptions{
+		Filters: f,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ctrs, err := c.Client.ContainerList(nctx, dockerTypes.ContainerListOptions{
+		All:     true,
+		Filters: f,
+	})
+	if err != nil {
+		return nil, err
+	}
+	labNets := containerNetworks(ctrs)
+
+	var pruned []string
+	for _, n := range nets {
+		if n.Name == defaultMgmtNet {
+			continue
+		}
+		if _, ok := labNets[n.Name]; ok {
+			log.Debugf("network '%s' is used by lab containers, skipping", n.Name)
+			continue
+		}
+		if _, ok := labNets[n.ID]; ok {
+			log.Debugf("network '%s' is used by lab containers, skipping", n.Name)
+			continue
+		}
+		// network list doesn't report attached containers, thus inspecting each network
+		nres, err := c.Client.NetworkInspect(nctx, n.ID, dockerTypes.NetworkInspectOptions{})
+		if err != nil {
+			return pruned, err
+		}
+		if len(nres.Containers) > 0 {
+			log.Debugf("network '%s' has %d active endpoints, skipping", n.Name, len(nres.Containers))
+			continue
+		}
+		if !dryRun {
+			c.deleteForwardingRules(nres)
+			if err := c.Client.NetworkRemove(nctx, n.ID); err != nil {
+				return pruned, err
+			}
+		}
+		pruned = append(pruned, n.Name)
+	}
+	return pruned, nil
+}
+
+// containerNetworks returns the names and IDs of the networks the containers are attached to,
+// the stopped containers keep their network settings
+func containerNetworks(ctrs []dockerTypes.Container) map[string]struct{} {
+	res := map[string]struct{}{}
+	for _, ctr := range ctrs {
+		if ctr.HostConfig.NetworkMode != "" {
+			res[ctr.HostConfig.NetworkMode] = struct{}{}
+		}
+		if ctr.NetworkSettings == nil {
+			continue
+		}
+		for name, es := range ctr.NetworkSettings.Networks {
+			res[name] = struct{}{}
+			if es != nil && es.NetworkID != "" {
+				res[es.NetworkID] = struct{}{}
+			}
+		}
+	}
+	return res
+}
+
 // CreateContainer creates a docker container
 func (c *DockerRuntime) CreateContainer(ctx context.Context, node *types.NodeConfig) (interface{}, error) {
 	log.Infof("Creating container: %s", node.ShortName)
@@ -268,14 +435,37 @@ func (c *DockerRuntime) CreateContainer(ctx context.Context, node *types.NodeCon
 		ExposedPorts: node.PortSet,
 		MacAddress:   node.MacAddress,
 	}
+	nanoCPUs, memory, err := node.ResourceLimits()
+	if err != nil {
+		return nil, err
+	}
+	// bind paths are resolved by the docker daemon on the container host
+	binds := make([]string, 0, len(node.Binds))
+	for _, b := range node.Binds {
+		binds = append(binds, utils.HostBind(b))
+	}
 	containerHostConfig := &container.HostConfig{
-		Binds:        node.Binds,
+		Binds:        binds,
+		Tmpfs:        node.Tmpfs,
 		PortBindings: node.PortBindings,
 		Sysctls:      node.Sysctls,
-		Privileged:   true,
+		Privileged:   node.Privileged(),
+		CapAdd:       node.CapAdd,
+		CapDrop:      node.CapDrop,
 		NetworkMode:  container.NetworkMode(c.Mgmt.Network),
+		PidMode:      container.PidMode(node.PidMode),
 		ExtraHosts:   node.ExtraHosts, // add static /etc/hosts entries
 	}
+	if node.DNS != nil {
+		containerHostConfig.DNS = node.DNS.Servers
+		containerHostConfig.DNSSearch = node.DNS.Search
+		containerHostConfig.DNSOptions = node.DNS.Options
+	}
+	containerHostConfig.Resources = container.Resources{
+		NanoCPUs:   nanoCPUs,
+		Memory:     memory,
+		CpusetCpus: node.CPUSet,
+	}
 
 	containerNetworkingConfig := &network.NetworkingConfig{}
 
@@ -293,6 +483,10 @@ func (c *DockerRuntime) CreateContainer(ctx context.Context, node *types.NodeCon
 				},
 			},
 		}
+		// the default bridge network has no embedded DNS and doesn't support aliases
+		if c.Mgmt.Network != "bridge" {
+			containerNetworkingConfig.EndpointsConfig[c.Mgmt.Network].Aliases = node.DNSAliases
+		}
 	}
 
 	cont, err := c.Client.ContainerCreate(
@@ -319,6 +513,10 @@ func (c *DockerRuntime) CreateContainer(ctx context.Context, node *types.NodeCon
 	if err != nil {
 		return nil, err
 	}
+	// netns path of a container on a remote host doesn't exist on this host
+	if runtime.IsRemoteHost(c.config.Host) {
+		return nil, nil
+	}
 
 	return nil, utils.LinkContainerNS(node.NSPath, node.LongName)
 
@@ -332,42 +530,118 @@ func (c *DockerRuntime) GetNSPath(ctx context.Context, containerId string) (stri
 	if err != nil {
 		return "", err
 	}
-	return "/proc/" + strconv.Itoa(cJSON.State.Pid) + "/ns/net", nil
+	return utils.LocalPath("/proc/" + strconv.Itoa(cJSON.State.Pid) + "/ns/net"), nil
 }
 
 func (c *DockerRuntime) PullImageIfRequired(ctx context.Context, imageName string) error {
+	exists, err := c.ImageExists(ctx, imageName)
+	if err != nil {
+		return err
+	}
+	// If Image doesn't exist, we need to pull it
+	if exists {
+		log.Debugf("Image %s present, skip pulling", imageName)
+		return nil
+	}
+	canonicalImageName := utils.GetCanonicalImageName(imageName)
+	log.Infof("Pulling %s Docker image", canonicalImageName)
+	if err := c.PullImage(ctx, imageName, nil); err != nil {
+		return err
+	}
+	log.Infof("Done pulling %s", canonicalImageName)
+	return nil
+}
+
+// ImageExists checks if the image is present in the docker image store
+func (c *DockerRuntime) ImageExists(ctx context.Context, imageName string) (bool, error) {
 	filter := filters.NewArgs()
 	filter.Add("reference", imageName)
 
-	ilo := dockerTypes.ImageListOptions{
+	log.Debugf("Looking up %s Docker image", imageName)
+
+	images, err := c.Client.ImageList(ctx, dockerTypes.ImageListOptions{
 		All:     false,
 		Filters: filter,
+	})
+	if err != nil {
+		return false, err
 	}
+	return len(images) > 0, nil
+}
 
-	log.Debugf("Looking up %s Docker image", imageName)
+// pullMessage is a message of the image pull progress stream
+type pullMessage struct {
+	ID             string `json:"id"`
+	Status         string `json:"status"`
+	ProgressDetail struct {
+		Current int64 `json:"current"`
+		Total   int64 `json:"total"`
+	} `json:"progressDetail"`
+	Error string `json:"error"`
+}
 
-	images, err := c.Client.ImageList(ctx, ilo)
-	if err != nil {
-		return err
+// PullImage pulls the image with the registry credentials and reports the download progress of its layers,
+// the image pulled from the source reference is tagged with the image name
+func (c *DockerRuntime) PullImage(ctx context.Context, imageName string, opts *types.PullOptions) error {
+	if opts == nil {
+		opts = &types.PullOptions{}
 	}
-
-	// If Image doesn't exist, we need to pull it
-	if len(images) > 0 {
-		log.Debugf("Image %s present, skip pulling", imageName)
-		return nil
+	ref := utils.GetCanonicalImageName(imageName)
+	if opts.Source != "" {
+		ref = utils.GetCanonicalImageName(opts.Source)
 	}
-
-	canonicalImageName := utils.GetCanonicalImageName(imageName)
-	log.Infof("Pulling %s Docker image", canonicalImageName)
-	reader, err := c.Client.ImagePull(ctx, canonicalImageName, dockerTypes.ImagePullOptions{})
+	po := dockerTypes.ImagePullOptions{}
+	if opts.Auth != nil {
+		b, err := json.Marshal(dockerTypes.AuthConfig{Username: opts.Auth.Username, Password: opts.Auth.Password})
+		if err != nil {
+			return err
+		}
+		po.RegistryAuth = base64.URLEncoding.EncodeToString(b)
+	}
+	reader, err := c.Client.ImagePull(ctx, ref, po)
 	if err != nil {
 		return err
 	}
 	defer reader.Close()
+
 	// must read from reader, otherwise image is not properly pulled
-	_, _ = io.Copy(ioutil.Discard, reader)
-	log.Infof("Done pulling %s", canonicalImageName)
+	type layer struct{ current, total int64 }
+	layers := map[string]*layer{}
+	dec := json.NewDecoder(reader)
+	for {
+		var m pullMessage
+		if err := dec.Decode(&m); err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		if m.Error != "" {
+			return errors.New(m.Error)
+		}
+		if opts.Progress == nil || m.ID == "" {
+			continue
+		}
+		switch m.Status {
+		case "Downloading":
+			layers[m.ID] = &layer{m.ProgressDetail.Current, m.ProgressDetail.Total}
+		case "Download complete", "Pull complete":
+			if l, ok := layers[m.ID]; ok {
+				l.current = l.total
+			}
+		default:
+			continue
+		}
+		var current, total int64
+		for _, l := range layers {
+			current += l.current
+			total += l.total
+		}
+		opts.Progress(current, total)
+	}
 
+	if opts.Source != "" {
+		return c.Client.ImageTag(ctx, ref, imageName)
+	}
 	return nil
 }
 
@@ -428,6 +702,46 @@ func (c *DockerRuntime) ListContainers(ctx context.Context, gfilters []*types.Ge
 	return c.produceGenericContainerList(ctrs, nr)
 }
 
+// ImageDigest returns the registry digest of the image from its repo digests,
+// the images built locally or loaded from an archive have no registry digest
+func (c *DockerRuntime) ImageDigest(ctx context.Context, image, imageID string) (string, error) {
+	ref := imageID
+	if ref == "" {
+		ref = image
+	}
+	img, _, err := c.Client.ImageInspectWithRaw(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	return repoDigest(image, img.RepoDigests), nil
+}
+
+// repoDigest returns the digest of the repo digests entry of the image repository,
+// or the digest of the first entry when the image was referenced by its ID
+func repoDigest(image string, repoDigests []string) string {
+	repo := image
+	if i := strings.Index(repo, "@"); i >= 0 {
+		repo = repo[:i]
+	}
+	if i := strings.LastIndex(repo, ":"); i > strings.LastIndex(repo, "/") {
+		repo = repo[:i]
+	}
+	var first string
+	for _, rd := range repoDigests {
+		i := strings.Index(rd, "@")
+		if i < 0 {
+			continue
+		}
+		if rd[:i] == repo {
+			return rd[i+1:]
+		}
+		if first == "" {
+			first = rd[i+1:]
+		}
+	}
+	return first
+}
+
 func (c *DockerRuntime) GetContainer(ctx context.Context, containerID string) (*types.GenericContainer, error) {
 	var ctr *types.GenericContainer
 	gFilter := types.GenericFilter{
@@ -469,13 +783,16 @@ func (c *DockerRuntime) produceGenericContainerList(inputContainers []dockerType
 			ID:      i.ID,
 			ShortID: i.ID[:12],
 			Image:   i.Image,
+			ImageID: i.ImageID,
 			State:   i.State,
 			Status:  i.Status,
 			Labels:  i.Labels,
+			Created: time.Unix(i.Created, 0),
 			NetworkSettings: &types.GenericMgmtIPs{
 				Set: false,
 			},
 		}
+		ctr.Ports = portBindings(i.Ports)
 		bridgeName := c.Mgmt.Network
 		// if bridgeName is "", try to find a network created by clab that the container is connected to
 		if bridgeName == "" && inputNetworkRessources != nil {
@@ -499,11 +816,35 @@ func (c *DockerRuntime) produceGenericContainerList(inputContainers []dockerType
 	return result, nil
 }
 
+// portBindings returns the host port bindings of the container ports,
+// the exposed ports that are not bound to the host are skipped
+func portBindings(ports []dockerTypes.Port) []*types.GenericPortBinding {
+	var res []*types.GenericPortBinding
+	for _, p := range ports {
+		if p.PublicPort == 0 {
+			continue
+		}
+		res = append(res, &types.GenericPortBinding{
+			HostIP:        p.IP,
+			HostPort:      int(p.PublicPort),
+			ContainerPort: int(p.PrivatePort),
+			Protocol:      p.Type,
+		})
+	}
+	return res
+}
+
 // Exec executes cmd on container identified with id and returns stdout, stderr bytes and an error
 func (c *DockerRuntime) Exec(ctx context.Context, id string, cmd []string) ([]byte, []byte, error) {
+	stdout, stderr, _, err := c.ExecWithExitCode(ctx, id, cmd)
+	return stdout, stderr, err
+}
+
+// ExecWithExitCode executes cmd on container identified with id and returns stdout, stderr and exit code of the cmd
+func (c *DockerRuntime) ExecWithExitCode(ctx context.Context, id string, cmd []string) ([]byte, []byte, int, error) {
 	cont, err := c.Client.ContainerInspect(ctx, id)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, 0, err
 	}
 	execID, err := c.Client.ContainerExecCreate(ctx, id, dockerTypes.ExecConfig{
 		User:         "root",
@@ -513,14 +854,14 @@ func (c *DockerRuntime) Exec(ctx context.Context, id string, cmd []string) ([]by
 	})
 	if err != nil {
 		log.Errorf("failed to create exec in container %s: %v", cont.Name, err)
-		return nil, nil, err
+		return nil, nil, 0, err
 	}
 	log.Debugf("%s exec created %v", cont.Name, id)
 
 	rsp, err := c.Client.ContainerExecAttach(ctx, execID.ID, dockerTypes.ExecStartCheck{})
 	if err != nil {
 		log.Errorf("failed exec in container %s: %v", cont.Name, err)
-		return nil, nil, err
+		return nil, nil, 0, err
 	}
 	defer rsp.Close()
 	log.Debugf("%s exec attached %v", cont.Name, id)
@@ -536,12 +877,17 @@ func (c *DockerRuntime) Exec(ctx context.Context, id string, cmd []string) ([]by
 	select {
 	case err := <-outputDone:
 		if err != nil {
-			return outBuf.Bytes(), errBuf.Bytes(), err
+			return outBuf.Bytes(), errBuf.Bytes(), 0, err
 		}
 	case <-ctx.Done():
-		return nil, nil, ctx.Err()
+		return nil, nil, 0, ctx.Err()
 	}
-	return outBuf.Bytes(), errBuf.Bytes(), nil
+
+	execInspect, err := c.Client.ContainerExecInspect(ctx, execID.ID)
+	if err != nil {
+		return outBuf.Bytes(), errBuf.Bytes(), 0, err
+	}
+	return outBuf.Bytes(), errBuf.Bytes(), execInspect.ExitCode, nil
 }
 
 // ExecNotWait executes cmd on container identified with id but doesn't wait for output nor attaches stdout/err
@@ -581,6 +927,141 @@ func (c *DockerRuntime) DeleteContainer(ctx context.Context, containerID string)
 	return nil
 }
 
+// CopyToContainer extracts the tar archive to the directory dstPath of the container
+func (c *DockerRuntime) CopyToContainer(ctx context.Context, id, dstPath string, content io.Reader) error {
+	return c.Client.CopyToContainer(ctx, id, dstPath, content, dockerTypes.CopyToContainerOptions{})
+}
+
+// DeleteVolume removes the named volume, the volume that doesn't exist is skipped
+func (c *DockerRuntime) DeleteVolume(ctx context.Context, name string) error {
+	nctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+	err := c.Client.VolumeRemove(nctx, name, false)
+	if err != nil && !dockerC.IsErrNotFound(err) {
+		return err
+	}
+	if err == nil {
+		log.Infof("Removed volume: %s", name)
+	}
+	return nil
+}
+
+// ConnectMgmtNet connects the running container to the management network with the management addresses
+// and the DNS aliases of the node config, the container already connected to the network is left as is
+func (c *DockerRuntime) ConnectMgmtNet(ctx context.Context, id string, node *types.NodeConfig) error {
+	nctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+	cJSON, err := c.Client.ContainerInspect(nctx, id)
+	if err != nil {
+		return err
+	}
+	if _, ok := cJSON.NetworkSettings.Networks[c.Mgmt.Network]; ok {
+		log.Debugf("container %s is already connected to network %s", id, c.Mgmt.Network)
+		return nil
+	}
+	settings := &network.EndpointSettings{
+		IPAMConfig: &network.EndpointIPAMConfig{
+			IPv4Address: node.MgmtIPv4Address,
+			IPv6Address: node.MgmtIPv6Address,
+		},
+	}
+	// the default bridge network has no embedded DNS and doesn't support aliases
+	if c.Mgmt.Network != "bridge" {
+		settings.Aliases = node.DNSAliases
+	}
+	return c.Client.NetworkConnect(nctx, c.Mgmt.Network, id, settings)
+}
+
+// DisconnectMgmtNet disconnects the container from the management network
+func (c *DockerRuntime) DisconnectMgmtNet(ctx context.Context, id string) error {
+	nctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+	return c.Client.NetworkDisconnect(nctx, c.Mgmt.Network, id, false)
+}
+
+// dockerEventStates maps the actions of the docker container events to the event states,
+// the other actions, e.g. exec_start or attach, are not reported
+var dockerEventStates = map[string]string{
+	"create":  types.EventCreated,
+	"start":   types.EventRunning,
+	"die":     types.EventExited,
+	"oom":     types.EventOOM,
+	"destroy": types.EventRemoved,
+}
+
+// Events streams the life-cycle events of the containers matching the filters from the docker events API
+func (c *DockerRuntime) Events(ctx context.Context, gfilters []*types.GenericFilter) (<-chan types.ContainerEvent, <-chan error) {
+	filter := c.buildFilterString(gfilters)
+	filter.Add("type", "container")
+	msgs, msgErrs := c.Client.Events(ctx, dockerTypes.EventsOptions{Filters: filter})
+
+	out := make(chan types.ContainerEvent)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case m := <-msgs:
+				state, ok := dockerEventStates[m.Action]
+				if !ok {
+					continue
+				}
+				// the attributes of the container events are the container labels with its name and image
+				ev := types.ContainerEvent{
+					Time:          time.Unix(0, m.TimeNano),
+					ContainerID:   m.Actor.ID,
+					ContainerName: m.Actor.Attributes["name"],
+					Labels:        m.Actor.Attributes,
+					State:         state,
+				}
+				if state == types.EventExited {
+					ev.ExitCode, _ = strconv.Atoi(m.Actor.Attributes["exitCode"])
+				}
+				select {
+				case out <- ev:
+				case <-ctx.Done():
+					errs <- ctx.Err()
+					return
+				}
+			case err := <-msgErrs:
+				errs <- err
+				return
+			}
+		}
+	}()
+	return out, errs
+}
+
+// ContainerStats returns the resource usage of the container reported by the docker stats API,
+// the memory usage excludes the inactive page cache the same way docker stats command does
+func (c *DockerRuntime) ContainerStats(ctx context.Context, cID string) (*types.ContainerStats, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+	resp, err := c.Client.ContainerStatsOneShot(ctx, cID)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var st dockerTypes.StatsJSON
+	if err := json.NewDecoder(resp.Body).Decode(&st); err != nil {
+		return nil, err
+	}
+	res := &types.ContainerStats{
+		CPUUsage:    time.Duration(st.CPUStats.CPUUsage.TotalUsage),
+		MemoryUsage: st.MemoryStats.Usage,
+		MemoryLimit: st.MemoryStats.Limit,
+	}
+	// cgroup v1 reports total_inactive_file and cgroup v2 inactive_file
+	inactive, ok := st.MemoryStats.Stats["total_inactive_file"]
+	if !ok {
+		inactive = st.MemoryStats.Stats["inactive_file"]
+	}
+	if inactive < res.MemoryUsage {
+		res.MemoryUsage -= inactive
+	}
+	return res, nil
+}
+
 // setSysctl writes sysctl data by writing to a specific file
 func setSysctl(sysctl string, newVal int) error {
 	return ioutil.WriteFile(path.Join(sysctlBase, sysctl), []byte(strconv.Itoa(newVal)), 0640)
@@ -589,3 +1070,41 @@ func setSysctl(sysctl string, newVal int) error {
 func (c *DockerRuntime) StopContainer(ctx context.Context, name string) error {
 	return c.Client.ContainerKill(ctx, name, "kill")
 }
+
+// GracefulStopContainer sends SIGTERM to the docker container and kills it if it hasn't exited within the timeout
+func (c *DockerRuntime) GracefulStopContainer(ctx context.Context, name string, timeout time.Duration) error {
+	return c.Client.ContainerStop(ctx, name, &timeout)
+}
+
+// PauseContainer pauses the docker container
+func (c *DockerRuntime) PauseContainer(ctx context.Context, name string) error {
+	nctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+	return c.Client.ContainerPause(nctx, name)
+}
+
+// UnpauseContainer unpauses the docker container
+func (c *DockerRuntime) UnpauseContainer(ctx context.Context, name string) error {
+	nctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+	return c.Client.ContainerUnpause(nctx, name)
+}
+
+// CheckpointContainer checkpoints the docker container to the dir and stops it.
+// Checkpoints require docker daemon running with the experimental features enabled and CRIU installed.
+// The checkpoint of a VM node takes as long as its memory takes to be written to the disk, hence no timeout is set
+func (c *DockerRuntime) CheckpointContainer(ctx context.Context, name, dir string) error {
+	return c.Client.CheckpointCreate(ctx, name, dockerTypes.CheckpointCreateOptions{
+		CheckpointID:  checkpointID,
+		CheckpointDir: dir,
+		Exit:          true,
+	})
+}
+
+// RestoreContainer starts the stopped docker container from the checkpoint saved in the dir
+func (c *DockerRuntime) RestoreContainer(ctx context.Context, name, dir string) error {
+	return c.Client.ContainerStart(ctx, name, dockerTypes.ContainerStartOptions{
+		CheckpointID:  checkpointID,
+		CheckpointDir: dir,
+	})
+}
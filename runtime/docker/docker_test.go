@@ -0,0 +1,114 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package docker
+
+import (
+	"testing"
+
+	dockerTypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestPortBindings(t *testing.T) {
+	tests := map[string]struct {
+		ports []dockerTypes.Port
+		want  []*types.GenericPortBinding
+	}{
+		"no_ports": {
+			ports: nil,
+			want:  nil,
+		},
+		"exposed_only": {
+			ports: []dockerTypes.Port{{PrivatePort: 80, Type: "tcp"}},
+			want:  nil,
+		},
+		"dual_stack": {
+			ports: []dockerTypes.Port{
+				{IP: "0.0.0.0", PrivatePort: 22, PublicPort: 32768, Type: "tcp"},
+				{IP: "::", PrivatePort: 22, PublicPort: 32768, Type: "tcp"},
+			},
+			want: []*types.GenericPortBinding{
+				{HostIP: "0.0.0.0", HostPort: 32768, ContainerPort: 22, Protocol: "tcp"},
+				{HostIP: "::", HostPort: 32768, ContainerPort: 22, Protocol: "tcp"},
+			},
+		},
+		"udp_and_exposed": {
+			ports: []dockerTypes.Port{
+				{IP: "127.0.0.1", PrivatePort: 161, PublicPort: 1161, Type: "udp"},
+				{PrivatePort: 830, Type: "tcp"},
+			},
+			want: []*types.GenericPortBinding{
+				{HostIP: "127.0.0.1", HostPort: 1161, ContainerPort: 161, Protocol: "udp"},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := portBindings(tc.ports)
+			if d := cmp.Diff(tc.want, got); d != "" {
+				t.Errorf("port bindings mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestRepoDigest(t *testing.T) {
+	tests := map[string]struct {
+		image       string
+		repoDigests []string
+		want        string
+	}{
+		"tag": {
+			image:       "ghcr.io/nokia/srlinux:21.6.1",
+			repoDigests: []string{"srlinux@sha256:aaaa", "ghcr.io/nokia/srlinux@sha256:bbbb"},
+			want:        "sha256:bbbb",
+		},
+		"registry_port": {
+			image:       "registry:5000/srlinux",
+			repoDigests: []string{"registry:5000/srlinux@sha256:cccc"},
+			want:        "sha256:cccc",
+		},
+		"digest": {
+			image:       "alpine@sha256:dddd",
+			repoDigests: []string{"alpine@sha256:dddd"},
+			want:        "sha256:dddd",
+		},
+		"image_id": {
+			image:       "sha256:0123",
+			repoDigests: []string{"alpine@sha256:eeee"},
+			want:        "sha256:eeee",
+		},
+		"local_image": {
+			image: "srlinux:dev",
+			want:  "",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := repoDigest(tc.image, tc.repoDigests); got != tc.want {
+				t.Errorf("got digest %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestContainerNetworks(t *testing.T) {
+	ctrs := []dockerTypes.Container{
+		{
+			// stopped container of a lab paused with a checkpoint
+			State: "exited",
+			NetworkSettings: &dockerTypes.SummaryNetworkSettings{
+				Networks: map[string]*network.EndpointSettings{"lab-mgmt": {NetworkID: "0123456789ab"}},
+			},
+		},
+		{State: "running"},
+	}
+	want := map[string]struct{}{"lab-mgmt": {}, "0123456789ab": {}}
+	if d := cmp.Diff(want, containerNetworks(ctrs)); d != "" {
+		t.Errorf("container networks mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os/exec"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// sshArgs returns the ssh command arguments running `docker system dial-stdio` on the host of the ssh:// address.
+// dial-stdio proxies the stdio of the ssh session to the docker daemon socket of the remote host
+func sshArgs(host string) ([]string, error) {
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "ssh" || u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid ssh docker host %q, expected ssh://[user@]host[:port]", host)
+	}
+	if u.Path != "" && u.Path != "/" {
+		return nil, fmt.Errorf("ssh docker host %q can't have a path", host)
+	}
+	var args []string
+	if u.User != nil {
+		args = append(args, "-l", u.User.Username())
+	}
+	if u.Port() != "" {
+		args = append(args, "-p", u.Port())
+	}
+	return append(args, "--", u.Hostname(), "docker", "system", "dial-stdio"), nil
+}
+
+// sshDialer returns the dialer connecting to the docker daemon of the ssh:// host address
+func sshDialer(host string) (func(ctx context.Context, network, addr string) (net.Conn, error), error) {
+	args, err := sshArgs(host)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, _, _ string) (net.Conn, error) {
+		// the connection outlives the dial context, so the ssh process isn't bound to it
+		cmd := exec.Command("ssh", args...) // skipcq: GSC-G204
+		log.Debugf("connecting to docker daemon with %s", cmd.String())
+		return newCommandConn(cmd)
+	}, nil
+}
+
+// commandConn is the net.Conn over the stdio of a command
+type commandConn struct {
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	stdout    io.ReadCloser
+	closeOnce sync.Once
+}
+
+func newCommandConn(cmd *exec.Cmd) (*commandConn, error) {
+	var err error
+	c := &commandConn{cmd: cmd}
+	if c.stdin, err = cmd.StdinPipe(); err != nil {
+		return nil, err
+	}
+	if c.stdout, err = cmd.StdoutPipe(); err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %v", cmd.Path, err)
+	}
+	return c, nil
+}
+
+func (c *commandConn) Read(p []byte) (int, error)  { return c.stdout.Read(p) }
+func (c *commandConn) Write(p []byte) (int, error) { return c.stdin.Write(p) }
+
+// Close closes the stdin of the command and kills it
+func (c *commandConn) Close() error {
+	c.closeOnce.Do(func() {
+		c.stdin.Close()
+		if c.cmd.Process != nil {
+			_ = c.cmd.Process.Kill()
+		}
+		_ = c.cmd.Wait()
+	})
+	return nil
+}
+
+func (*commandConn) LocalAddr() net.Addr              { return commandAddr{} }
+func (*commandConn) RemoteAddr() net.Addr             { return commandAddr{} }
+func (*commandConn) SetDeadline(time.Time) error      { return nil }
+func (*commandConn) SetReadDeadline(time.Time) error  { return nil }
+func (*commandConn) SetWriteDeadline(time.Time) error { return nil }
+
+type commandAddr struct{}
+
+func (commandAddr) Network() string { return "stdio" }
+func (commandAddr) String() string  { return "stdio" }
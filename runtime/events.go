@@ -0,0 +1,95 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package runtime
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/srl-labs/containerlab/types"
+)
+
+// PollEvents reports the state changes of the containers matching the filters by listing the containers every interval,
+// it serves the runtimes without an event stream. The containers existing when the polling starts are not reported.
+// The events channel is closed when ctx is canceled or the containers can't be listed, the error is sent to the errors channel
+func PollEvents(ctx context.Context, r ContainerRuntime, filters []*types.GenericFilter, interval time.Duration) (<-chan types.ContainerEvent, <-chan error) {
+	out := make(chan types.ContainerEvent)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(out)
+		var prev map[string]types.GenericContainer
+		for {
+			ctrs, err := r.ListContainers(ctx, filters)
+			if err != nil {
+				errs <- err
+				return
+			}
+			cur := make(map[string]types.GenericContainer, len(ctrs))
+			for _, ctr := range ctrs {
+				cur[ctr.ID] = ctr
+			}
+			if prev != nil {
+				for _, ev := range containerStateChanges(prev, cur, time.Now()) {
+					select {
+					case out <- ev:
+					case <-ctx.Done():
+						errs <- ctx.Err()
+						return
+					}
+				}
+			}
+			prev = cur
+			select {
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			case <-time.After(interval):
+			}
+		}
+	}()
+	return out, errs
+}
+
+// containerStateChanges returns the events of the containers which changed their state between the prev and cur listings
+// sorted by the container name, the containers missing from the cur listing are reported removed
+func containerStateChanges(prev, cur map[string]types.GenericContainer, now time.Time) []types.ContainerEvent {
+	var res []types.ContainerEvent
+	event := func(ctr types.GenericContainer, state string) types.ContainerEvent {
+		ev := types.ContainerEvent{Time: now, ContainerID: ctr.ID, Labels: ctr.Labels, State: state}
+		if len(ctr.Names) > 0 {
+			ev.ContainerName = strings.TrimPrefix(ctr.Names[0], "/")
+		}
+		return ev
+	}
+	for id, ctr := range cur {
+		state := pollState(ctr.State)
+		if p, ok := prev[id]; (ok && pollState(p.State) == state) || state == "" {
+			continue
+		}
+		res = append(res, event(ctr, state))
+	}
+	for id, ctr := range prev {
+		if _, ok := cur[id]; !ok {
+			res = append(res, event(ctr, types.EventRemoved))
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].ContainerName < res[j].ContainerName })
+	return res
+}
+
+// pollState returns the event state of the listed container state, the unknown states are not reported
+func pollState(state string) string {
+	switch strings.ToLower(state) {
+	case "created":
+		return types.EventCreated
+	case "running":
+		return types.EventRunning
+	case "exited", "stopped", "dead":
+		return types.EventExited
+	}
+	return ""
+}
@@ -0,0 +1,66 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package runtime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/types"
+)
+
+func TestContainerStateChanges(t *testing.T) {
+	now := time.Date(2021, 6, 7, 10, 0, 0, 0, time.UTC)
+	ctr := func(id, state string) types.GenericContainer {
+		return types.GenericContainer{ID: id, Names: []string{"/clab-lab-" + id}, State: state}
+	}
+	event := func(id, state string) types.ContainerEvent {
+		return types.ContainerEvent{Time: now, ContainerID: id, ContainerName: "clab-lab-" + id, State: state}
+	}
+	tests := map[string]struct {
+		prev, cur []types.GenericContainer
+		want      []types.ContainerEvent
+	}{
+		"unchanged": {
+			prev: []types.GenericContainer{ctr("n1", "running")},
+			cur:  []types.GenericContainer{ctr("n1", "running")},
+		},
+		"exited-and-started": {
+			prev: []types.GenericContainer{ctr("n2", "running"), ctr("n1", "exited")},
+			cur:  []types.GenericContainer{ctr("n2", "exited"), ctr("n1", "running")},
+			want: []types.ContainerEvent{event("n1", types.EventRunning), event("n2", types.EventExited)},
+		},
+		"created-and-removed": {
+			prev: []types.GenericContainer{ctr("n1", "running")},
+			cur:  []types.GenericContainer{ctr("n2", "created")},
+			want: []types.ContainerEvent{event("n1", types.EventRemoved), event("n2", types.EventCreated)},
+		},
+		"unknown-state": {
+			prev: []types.GenericContainer{ctr("n1", "running")},
+			cur:  []types.GenericContainer{ctr("n1", "paused")},
+		},
+		"runtime-state-names": {
+			prev: []types.GenericContainer{ctr("n1", "Running")},
+			cur:  []types.GenericContainer{ctr("n1", "Stopped")},
+			want: []types.ContainerEvent{event("n1", types.EventExited)},
+		},
+	}
+	toMap := func(ctrs []types.GenericContainer) map[string]types.GenericContainer {
+		m := map[string]types.GenericContainer{}
+		for _, c := range ctrs {
+			m[c.ID] = c
+		}
+		return m
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := containerStateChanges(toMap(tc.prev), toMap(tc.cur), now)
+			if d := cmp.Diff(tc.want, got); d != "" {
+				t.Errorf("containerStateChanges() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
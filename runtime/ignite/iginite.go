@@ -3,6 +3,7 @@ package ignite
 import (
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"strings"
@@ -137,6 +138,10 @@ func (c *IgniteRuntime) DeleteNet(ctx context.Context) error {
 	return c.ctrRuntime.DeleteNet(ctx)
 }
 
+func (c *IgniteRuntime) PruneNets(ctx context.Context, dryRun bool) ([]string, error) {
+	return c.ctrRuntime.PruneNets(ctx, dryRun)
+}
+
 func (*IgniteRuntime) PullImageIfRequired(_ context.Context, imageName string) error {
 	ociRef, err := meta.NewOCIImageRef(imageName)
 	if err != nil {
@@ -150,6 +155,29 @@ func (*IgniteRuntime) PullImageIfRequired(_ context.Context, imageName string) e
 	return nil
 }
 
+// PullImage imports the image with ignite, the registry credentials and the pull source are not supported
+func (c *IgniteRuntime) PullImage(ctx context.Context, imageName string, opts *types.PullOptions) error {
+	if opts != nil && (opts.Auth != nil || opts.Source != "") {
+		log.Warnf("ignite runtime doesn't support the registry credentials and mirrors, pulling %s without them", imageName)
+	}
+	return c.PullImageIfRequired(ctx, imageName)
+}
+
+// ImageExists checks if the image is present in the image store of the container runtime ignite imports the images from
+func (c *IgniteRuntime) ImageExists(ctx context.Context, imageName string) (bool, error) {
+	return c.ctrRuntime.ImageExists(ctx, imageName)
+}
+
+// ImageDigest returns the registry digest of the image of the container runtime ignite imports the images from
+func (c *IgniteRuntime) ImageDigest(ctx context.Context, image, imageID string) (string, error) {
+	return c.ctrRuntime.ImageDigest(ctx, image, imageID)
+}
+
+// BuildImage builds the image with the container runtime ignite imports the images from
+func (c *IgniteRuntime) BuildImage(ctx context.Context, imageName string, build *types.BuildConfig) error {
+	return c.ctrRuntime.BuildImage(ctx, imageName, build)
+}
+
 func (c *IgniteRuntime) CreateContainer(ctx context.Context, node *types.NodeConfig) (interface{}, error) {
 
 	vm := c.baseVM.DeepCopy()
@@ -191,6 +219,14 @@ func (c *IgniteRuntime) CreateContainer(ctx context.Context, node *types.NodeCon
 	vm.Labels = node.Labels
 	metadata.SetNameAndUID(vm, providers.Client)
 
+	// the binds are copied to the VM, there are no mounts to back the tmpfs with
+	if len(node.Tmpfs) > 0 {
+		return nil, fmt.Errorf("tmpfs mounts are not supported by %s runtime", runtimeName)
+	}
+	// the node runs as a VM, there is no container to set the capabilities of
+	if !node.Privileged() {
+		return nil, fmt.Errorf("cap-add and cap-drop are not supported by %s runtime", runtimeName)
+	}
 	copyFiles := []api.FileMapping{}
 	for _, bind := range node.Binds {
 		parts := strings.Split(bind, ":")
@@ -271,6 +307,31 @@ func (*IgniteRuntime) StopContainer(_ context.Context, _ string) error {
 	return nil
 }
 
+// GracefulStopContainer is a no-op, the ignite VMs are stopped when they are removed
+func (*IgniteRuntime) GracefulStopContainer(_ context.Context, _ string, _ time.Duration) error {
+	return nil
+}
+
+// PauseContainer is not supported by ignite runtime
+func (*IgniteRuntime) PauseContainer(_ context.Context, id string) error {
+	return fmt.Errorf("pausing %s is not supported by %s runtime", id, runtimeName)
+}
+
+// UnpauseContainer is not supported by ignite runtime
+func (*IgniteRuntime) UnpauseContainer(_ context.Context, id string) error {
+	return fmt.Errorf("unpausing %s is not supported by %s runtime", id, runtimeName)
+}
+
+// CheckpointContainer is not supported by ignite runtime
+func (*IgniteRuntime) CheckpointContainer(_ context.Context, id, _ string) error {
+	return fmt.Errorf("checkpointing %s is not supported by %s runtime", id, runtimeName)
+}
+
+// RestoreContainer is not supported by ignite runtime
+func (*IgniteRuntime) RestoreContainer(_ context.Context, id, _ string) error {
+	return fmt.Errorf("restoring %s from a checkpoint is not supported by %s runtime", id, runtimeName)
+}
+
 func (c *IgniteRuntime) ListContainers(_ context.Context, gfilters []*types.GenericFilter) ([]types.GenericContainer, error) {
 
 	var result []types.GenericContainer
@@ -340,6 +401,7 @@ func (*IgniteRuntime) produceGenericContainerList(input []*api.VM) ([]types.Gene
 			ShortID: i.PrefixedID(),
 			Labels:  i.Labels,
 			Image:   i.Spec.Image.OCI.Normalized(),
+			Created: i.GetCreated().Time.Time,
 			NetworkSettings: &types.GenericMgmtIPs{
 				Set: false,
 			},
@@ -380,10 +442,24 @@ func (*IgniteRuntime) Exec(context.Context, string, []string) ([]byte, []byte, e
 	log.Infof("Exec is not yet implemented for Ignite runtime")
 	return []byte{}, []byte{}, nil
 }
+func (*IgniteRuntime) ExecWithExitCode(context.Context, string, []string) ([]byte, []byte, int, error) {
+	log.Infof("ExecWithExitCode is not yet implemented for Ignite runtime")
+	return []byte{}, []byte{}, 0, nil
+}
 func (*IgniteRuntime) ExecNotWait(context.Context, string, []string) error {
 	log.Infof("ExecNotWait is not yet implemented for Ignite runtime")
 	return nil
 }
+
+// CopyToContainer is not supported by ignite runtime, the files are not copied to the VM
+func (*IgniteRuntime) CopyToContainer(_ context.Context, id, _ string, _ io.Reader) error {
+	return fmt.Errorf("copying files to container %s is not supported by %s runtime", id, runtimeName)
+}
+
+func (c *IgniteRuntime) DeleteVolume(ctx context.Context, name string) error {
+	return c.ctrRuntime.DeleteVolume(ctx, name)
+}
+
 func (c *IgniteRuntime) DeleteContainer(ctx context.Context, containerID string) error {
 	vm, err := providers.Client.VMs().Find(filter.NewVMFilter(containerID))
 	if err != nil {
@@ -414,3 +490,26 @@ func (c *IgniteRuntime) DeleteContainer(ctx context.Context, containerID string)
 
 	return nil
 }
+
+// eventsPollInterval is the interval the state of the VMs is polled with to report their events
+const eventsPollInterval = 2 * time.Second
+
+// Events reports the state changes of the VMs by polling them, ignite has no event stream of its own
+// ContainerStats returns the resource usage of the container running the VM
+// ConnectMgmtNet is not supported by ignite runtime, the VMs are connected to the management network on creation
+func (*IgniteRuntime) ConnectMgmtNet(_ context.Context, id string, _ *types.NodeConfig) error {
+	return fmt.Errorf("connecting %s to the management network is not supported by %s runtime", id, runtimeName)
+}
+
+// DisconnectMgmtNet is not supported by ignite runtime
+func (*IgniteRuntime) DisconnectMgmtNet(_ context.Context, id string) error {
+	return fmt.Errorf("disconnecting %s from the management network is not supported by %s runtime", id, runtimeName)
+}
+
+func (c *IgniteRuntime) ContainerStats(ctx context.Context, ctrId string) (*types.ContainerStats, error) {
+	return c.ctrRuntime.ContainerStats(ctx, ctrId)
+}
+
+func (c *IgniteRuntime) Events(ctx context.Context, filters []*types.GenericFilter) (<-chan types.ContainerEvent, <-chan error) {
+	return runtime.PollEvents(ctx, c, filters, eventsPollInterval)
+}
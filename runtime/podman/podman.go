@@ -0,0 +1,95 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package podman
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	dockerTypes "github.com/docker/docker/api/types"
+	dockerC "github.com/docker/docker/client"
+	log "github.com/sirupsen/logrus"
+	"github.com/srl-labs/containerlab/runtime"
+	"github.com/srl-labs/containerlab/runtime/docker"
+	"github.com/srl-labs/containerlab/types"
+)
+
+const (
+	runtimeName = "podman"
+	// socket of the podman service run by root
+	rootSocket = "/run/podman/podman.sock"
+)
+
+func init() {
+	runtime.Register(runtimeName, func() runtime.ContainerRuntime {
+		return &PodmanRuntime{
+			DockerRuntime: docker.DockerRuntime{
+				Mgmt: new(types.MgmtNet),
+			},
+		}
+	})
+}
+
+// PodmanRuntime manages the containers with podman through the Docker compatible API of the podman service
+type PodmanRuntime struct {
+	docker.DockerRuntime
+}
+
+func (p *PodmanRuntime) Init(opts ...runtime.RuntimeOption) error {
+	var err error
+	log.Debug("Runtime: Podman")
+	host := podmanHost(os.Getenv("CONTAINER_HOST"), os.Geteuid(), os.Getenv("XDG_RUNTIME_DIR"))
+	log.Debugf("Using podman service at %s", host)
+	p.Client, err = dockerC.NewClientWithOpts(dockerC.WithHost(host), dockerC.WithAPIVersionNegotiation())
+	if err != nil {
+		return err
+	}
+	for _, o := range opts {
+		o(p)
+	}
+	return nil
+}
+
+func (*PodmanRuntime) GetName() string { return runtimeName }
+
+// CreateNet creates the management network or reuses it if it exists.
+// Podman names the bridges of the networks it creates by itself,
+// so containerlab sets the bridge name of a new network explicitly
+func (p *PodmanRuntime) CreateNet(ctx context.Context) error {
+	if p.Mgmt.Bridge == "" {
+		_, err := p.Client.NetworkInspect(ctx, p.Mgmt.Network, dockerTypes.NetworkInspectOptions{})
+		switch {
+		case dockerC.IsErrNotFound(err):
+			p.Mgmt.Bridge = bridgeName(p.Mgmt.Network)
+		case err != nil:
+			return err
+		}
+	}
+	return p.DockerRuntime.CreateNet(ctx)
+}
+
+// podmanHost returns the address of the podman service.
+// CONTAINER_HOST env var takes precedence, otherwise the socket of the root podman service is used for root
+// and the socket of the rootless podman service of the user for others
+func podmanHost(containerHost string, uid int, xdgRuntimeDir string) string {
+	switch {
+	case containerHost != "":
+		return containerHost
+	case uid == 0:
+		return "unix://" + rootSocket
+	case xdgRuntimeDir == "":
+		xdgRuntimeDir = fmt.Sprintf("/run/user/%d", uid)
+	}
+	return "unix://" + filepath.Join(xdgRuntimeDir, "podman", "podman.sock")
+}
+
+// bridgeName returns the name of the linux bridge of the management network,
+// the name fits the 15 characters limit of the interface names
+func bridgeName(network string) string {
+	return fmt.Sprintf("br-%x", sha256.Sum256([]byte(network)))[:15]
+}
@@ -6,6 +6,10 @@ package runtime
 
 import (
 	"context"
+	"io"
+	"net"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/srl-labs/containerlab/types"
@@ -15,6 +19,7 @@ const (
 	DockerRuntime     = "docker"
 	ContainerdRuntime = "containerd"
 	IgniteRuntime     = "ignite"
+	PodmanRuntime     = "podman"
 )
 
 type ContainerRuntime interface {
@@ -30,8 +35,20 @@ type ContainerRuntime interface {
 	CreateNet(context.Context) error
 	// Delete container (bridge) network
 	DeleteNet(context.Context) error
+	// Delete container networks created by containerlab that have no containers attached
+	// returns the names of the pruned networks, with dry-run set to true networks are only listed
+	PruneNets(ctx context.Context, dryRun bool) ([]string, error)
 	// Pull container image if not present
 	PullImageIfRequired(context.Context, string) error
+	// Pull container image with the options, regardless of its presence
+	PullImage(context.Context, string, *types.PullOptions) error
+	// Check if the container image is present in the local image store
+	ImageExists(context.Context, string) (bool, error)
+	// Registry digest of the container image identified by its ID, if set, or by its reference,
+	// empty for the images without one, e.g. the locally built images
+	ImageDigest(ctx context.Context, image, imageID string) (string, error)
+	// Build container image with the given name from the Dockerfile of the build config
+	BuildImage(context.Context, string, *types.BuildConfig) error
 	// Create container returns an extra interface that can be used to receive signals
 	// about the container life-cycle after it was created, e.g. for post-deploy tassks
 	CreateContainer(context.Context, *types.NodeConfig) (interface{}, error)
@@ -39,16 +56,42 @@ type ContainerRuntime interface {
 	StartContainer(context.Context, string) error
 	// Stop running container by its name
 	StopContainer(context.Context, string) error
+	// Stop running container by its name with SIGTERM, the container is killed if it hasn't exited within the timeout
+	GracefulStopContainer(ctx context.Context, name string, timeout time.Duration) error
+	// Pause (freeze) the processes of the running container by its name
+	PauseContainer(context.Context, string) error
+	// Resume the processes of the paused container by its name
+	UnpauseContainer(context.Context, string) error
+	// Checkpoint the state of the running container by its name to the directory with CRIU and stop the container
+	CheckpointContainer(ctx context.Context, name string, dir string) error
+	// Start the stopped container by its name from the checkpoint saved in the directory
+	RestoreContainer(ctx context.Context, name string, dir string) error
 	// List all containers matching labels
 	ListContainers(context.Context, []*types.GenericFilter) ([]types.GenericContainer, error)
 	// Get a netns path using the pid of a container
 	GetNSPath(context.Context, string) (string, error)
 	// Executes cmd on container identified with id and returns stdout, stderr bytes and an error
 	Exec(context.Context, string, []string) ([]byte, []byte, error)
+	// ExecWithExitCode executes cmd on container identified with id and returns stdout, stderr bytes, exit code of the cmd and an error
+	ExecWithExitCode(context.Context, string, []string) ([]byte, []byte, int, error)
 	// ExecNotWait executes cmd on container identified with id but doesn't wait for output nor attaches stodout/err
 	ExecNotWait(context.Context, string, []string) error
 	// Delete container by its name
 	DeleteContainer(context.Context, string) error
+	// Copy the files of the tar archive to the directory dstPath of the container identified with id
+	CopyToContainer(ctx context.Context, id, dstPath string, content io.Reader) error
+	// Delete named volume by its name, a missing volume is not an error
+	DeleteVolume(context.Context, string) error
+	// Connect the running container identified with id to the management network
+	// with the management addresses and DNS aliases of the node config
+	ConnectMgmtNet(context.Context, string, *types.NodeConfig) error
+	// Disconnect the container identified with id from the management network
+	DisconnectMgmtNet(context.Context, string) error
+	// Events streams the life-cycle events of the containers matching the filters until the context is canceled,
+	// the error ending the stream is sent to the errors channel
+	Events(context.Context, []*types.GenericFilter) (<-chan types.ContainerEvent, <-chan error)
+	// ContainerStats returns the CPU and memory usage of the running container identified with id
+	ContainerStats(context.Context, string) (*types.ContainerStats, error)
 	// Getter for runtime config options
 	Config() RuntimeConfig
 	GetName() string
@@ -63,6 +106,26 @@ type RuntimeConfig struct {
 	GracefulShutdown bool
 	Debug            bool
 	KeepMgmtNet      bool
+	// address of the container runtime daemon, e.g. tcp://lab-server:2376 or ssh://user@lab-server
+	Host string
+}
+
+// IsRemoteHost returns true when the runtime daemon address points to another host,
+// the tcp:// and ssh:// addresses of localhost and the loopback addresses are local
+func IsRemoteHost(host string) bool {
+	if host == "" || strings.HasPrefix(host, "unix://") || strings.HasPrefix(host, "npipe://") {
+		return false
+	}
+	u, err := url.Parse(host)
+	if err != nil {
+		return true
+	}
+	h := u.Hostname()
+	if h == "localhost" {
+		return false
+	}
+	ip := net.ParseIP(h)
+	return ip == nil || !ip.IsLoopback()
 }
 
 var ContainerRuntimes = map[string]Initializer{}
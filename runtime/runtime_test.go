@@ -0,0 +1,29 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package runtime
+
+import "testing"
+
+func TestIsRemoteHost(t *testing.T) {
+	tests := map[string]bool{
+		"":                            false,
+		"unix:///var/run/docker.sock": false,
+		"npipe:////./pipe/docker":     false,
+		"tcp://localhost:2375":        false,
+		"tcp://127.0.0.1:2375":        false,
+		"tcp://[::1]:2375":            false,
+		"ssh://admin@localhost":       false,
+		"tcp://lab-server:2376":       true,
+		"tcp://10.1.1.1:2376":         true,
+		"ssh://admin@lab-server":      true,
+	}
+	for host, want := range tests {
+		t.Run(host, func(t *testing.T) {
+			if got := IsRemoteHost(host); got != want {
+				t.Errorf("IsRemoteHost(%q) = %v, want %v", host, got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package schemas holds the published JSON schema of the topology file
+// and validates the topology documents against it
+package schemas
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"gopkg.in/yaml.v3"
+)
+
+// TopologySchema is the JSON schema of the topology file
+//
+//go:embed clab.schema.json
+var TopologySchema []byte
+
+// schemaURL is the $id of the topology schema
+const schemaURL = "https://containerlab.srlinux.dev/clab.schema.json"
+
+// Violation is a schema violation found at a location of the validated document
+type Violation struct {
+	// path to the violating value, e.g. topology.nodes.r1.kind
+	Path    string `json:"path"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+func (v *Violation) String() string {
+	return fmt.Sprintf("%d:%d: %s: %s", v.Line, v.Column, v.Path, v.Message)
+}
+
+var (
+	schemaOnce sync.Once
+	schema     *jsonschema.Schema
+	schemaErr  error
+)
+
+// ValidateTopology validates the YAML or JSON topology document against the topology schema
+// and returns the violations sorted by their location
+func ValidateTopology(doc *yaml.Node) ([]*Violation, error) {
+	schemaOnce.Do(func() {
+		c := jsonschema.NewCompiler()
+		c.Draft = jsonschema.Draft7
+		if schemaErr = c.AddResource(schemaURL, bytes.NewReader(TopologySchema)); schemaErr != nil {
+			return
+		}
+		schema, schemaErr = c.Compile(schemaURL)
+	})
+	if schemaErr != nil {
+		return nil, fmt.Errorf("failed to read topology schema: %v", schemaErr)
+	}
+
+	root := doc
+	if root.Kind == yaml.DocumentNode {
+		if len(root.Content) == 0 {
+			return []*Violation{{Path: ".", Line: root.Line, Column: root.Column, Message: "document is empty"}}, nil
+		}
+		root = root.Content[0]
+	}
+	v, err := jsonValue(root)
+	if err != nil {
+		return nil, err
+	}
+	err = schema.Validate(v)
+	if err == nil {
+		return nil, nil
+	}
+	ve, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return nil, err
+	}
+	var res []*Violation
+	for _, e := range leafErrors(ve) {
+		path, n := locate(root, e.InstanceLocation)
+		res = append(res, &Violation{Path: path, Line: n.Line, Column: n.Column, Message: e.Message})
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Line != res[j].Line {
+			return res[i].Line < res[j].Line
+		}
+		return res[i].Column < res[j].Column
+	})
+	return res, nil
+}
+
+// jsonValue decodes the YAML node to the value the schema validates, i.e. the value decoded from the JSON document.
+// The aliases and merge keys are resolved, the keys of the mappings are turned to strings
+func jsonValue(n *yaml.Node) (interface{}, error) {
+	var v interface{}
+	if err := n.Decode(&v); err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(stringKeys(v))
+	if err != nil {
+		return nil, err
+	}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// stringKeys returns the value with the keys of the mappings turned to strings, as JSON objects have string keys only
+func stringKeys(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = stringKeys(e)
+		}
+		return v
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[fmt.Sprint(k)] = stringKeys(e)
+		}
+		return m
+	case []interface{}:
+		for i, e := range v {
+			v[i] = stringKeys(e)
+		}
+	}
+	return v
+}
+
+// leafErrors returns the errors of the validation error tree which have no causes,
+// i.e. the errors of the keywords the document violates.
+// The oneOf and anyOf alternatives of another type than the value are left out,
+// e.g. the null alternative of the node definition
+func leafErrors(e *jsonschema.ValidationError) []*jsonschema.ValidationError {
+	if len(e.Causes) == 0 {
+		return []*jsonschema.ValidationError{e}
+	}
+	causes := e.Causes
+	if strings.HasSuffix(e.KeywordLocation, "/oneOf") || strings.HasSuffix(e.KeywordLocation, "/anyOf") {
+		var matching []*jsonschema.ValidationError
+		for _, c := range causes {
+			if !typeMismatch(c, e.InstanceLocation) {
+				matching = append(matching, c)
+			}
+		}
+		if len(matching) > 0 {
+			causes = matching
+		}
+	}
+	var res []*jsonschema.ValidationError
+	for _, c := range causes {
+		res = append(res, leafErrors(c)...)
+	}
+	return res
+}
+
+// typeMismatch returns true for the error reporting the value at the instance location has a type the schema doesn't allow
+func typeMismatch(e *jsonschema.ValidationError, instance string) bool {
+	for len(e.Causes) == 1 {
+		e = e.Causes[0]
+	}
+	return len(e.Causes) == 0 && e.InstanceLocation == instance && strings.HasSuffix(e.KeywordLocation, "/type")
+}
+
+// locate returns the dotted path and the YAML node of the JSON pointer location in the document n,
+// the location not found in the document is reported at the closest node found
+func locate(n *yaml.Node, ptr string) (string, *yaml.Node) {
+	var path string
+	n = resolve(n)
+	for _, tok := range strings.Split(strings.TrimPrefix(ptr, "/"), "/") {
+		if tok == "" {
+			continue
+		}
+		tok = strings.NewReplacer("~1", "/", "~0", "~").Replace(tok)
+		var next *yaml.Node
+		switch n.Kind {
+		case yaml.MappingNode:
+			for _, p := range mappingPairs(n) {
+				if p[0].Value == tok {
+					next = p[1]
+					break
+				}
+			}
+		case yaml.SequenceNode:
+			if i, err := strconv.Atoi(tok); err == nil && i >= 0 && i < len(n.Content) {
+				next = n.Content[i]
+			}
+		}
+		if next == nil {
+			break
+		}
+		path = joinPath(path, tok)
+		n = resolve(next)
+	}
+	if path == "" {
+		path = "."
+	}
+	return path, n
+}
+
+// resolve returns the node the alias node refers to
+func resolve(n *yaml.Node) *yaml.Node {
+	for n.Kind == yaml.AliasNode && n.Alias != nil {
+		n = n.Alias
+	}
+	return n
+}
+
+// mappingPairs returns the key and value nodes of the mapping with the merge keys expanded,
+// the keys set explicitly override the merged ones
+func mappingPairs(n *yaml.Node) [][2]*yaml.Node {
+	var merged, own [][2]*yaml.Node
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, val := n.Content[i], n.Content[i+1]
+		if k.Value == "<<" && k.ShortTag() == "!!merge" {
+			val = resolve(val)
+			srcs := []*yaml.Node{val}
+			if val.Kind == yaml.SequenceNode {
+				srcs = val.Content
+			}
+			for _, src := range srcs {
+				if src = resolve(src); src.Kind == yaml.MappingNode {
+					merged = append(merged, mappingPairs(src)...)
+				}
+			}
+			continue
+		}
+		own = append(own, [2]*yaml.Node{k, val})
+	}
+	if len(merged) == 0 {
+		return own
+	}
+	set := map[string]struct{}{}
+	for _, p := range own {
+		set[p[0].Value] = struct{}{}
+	}
+	res := own
+	for _, p := range merged {
+		if _, ok := set[p[0].Value]; !ok {
+			set[p[0].Value] = struct{}{}
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,118 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package schemas
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gopkg.in/yaml.v3"
+)
+
+func TestValidateTopology(t *testing.T) {
+	tests := map[string]struct {
+		doc  string
+		want []*Violation
+	}{
+		"valid": {
+			doc: `name: lab
+topology:
+  defaults: &defaults
+    env:
+      A: b
+  nodes:
+    r1:
+      kind: srl
+      type: ixrd2
+      <<: *defaults
+    r2:
+  links:
+    - endpoints: ["r1:e1-1", "r2:eth1"]
+`,
+		},
+		"unknown-key": {
+			doc: `name: lab
+topology:
+  nodes:
+    r1:
+      kind: linux
+      imagee: alpine:3
+`,
+			want: []*Violation{
+				{Path: "topology.nodes.r1", Line: 5, Column: 7, Message: "additionalProperties 'imagee' not allowed"},
+			},
+		},
+		"wrong-type": {
+			doc: `name: lab
+topology:
+  nodes:
+    r1:
+      kind: linux
+      binds: /tmp:/tmp
+`,
+			want: []*Violation{
+				{Path: "topology.nodes.r1.binds", Line: 6, Column: 14, Message: "expected array, but got string"},
+			},
+		},
+		"numeric-cmd": {
+			doc: `name: lab
+topology:
+  nodes:
+    r1:
+      kind: linux
+      cmd: 2
+`,
+		},
+		"missing-topology": {
+			doc: `name: lab
+`,
+			want: []*Violation{
+				{Path: ".", Line: 1, Column: 1, Message: "missing properties: 'topology'"},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var doc yaml.Node
+			if err := yaml.Unmarshal([]byte(tc.doc), &doc); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ValidateTopology(&doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(got, tc.want) {
+				t.Errorf("diff (-want +got):\n%s", cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
+
+// TestValidateLabExamples checks the lab examples conform to the topology schema
+func TestValidateLabExamples(t *testing.T) {
+	files, err := filepath.Glob("../lab-examples/*/*.clab.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var doc yaml.Node
+		if err := yaml.Unmarshal(b, &doc); err != nil {
+			t.Fatalf("%s: %v", f, err)
+		}
+		got, err := ValidateTopology(&doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, v := range got {
+			t.Errorf("%s: %s", f, v)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BridgeConfig defines the settings of the bridge and ovs-bridge nodes
+type BridgeConfig struct {
+	// create the bridge if it doesn't exist on the lab host
+	Create bool `yaml:"create,omitempty"`
+	// enable VLAN filtering on the created linux bridge
+	VLANFiltering bool `yaml:"vlan-filtering,omitempty"`
+}
+
+// GetCreate returns true if the bridge is to be created by containerlab
+func (b *BridgeConfig) GetCreate() bool {
+	return b != nil && b.Create
+}
+
+// LinkVLAN defines the VLANs of the bridge port a link endpoint is attached to
+type LinkVLAN struct {
+	// untagged VLAN of the port
+	Access int `yaml:"access,omitempty"`
+	// tagged VLANs of the port
+	Trunk []int `yaml:"trunk,omitempty"`
+}
+
+// Validate checks that the VLANs are set and their IDs are within the 1-4094 range
+func (v *LinkVLAN) Validate() error {
+	if v.Access == 0 && len(v.Trunk) == 0 {
+		return fmt.Errorf("vlan settings need access or trunk VLANs")
+	}
+	vids := v.Trunk
+	if v.Access != 0 {
+		vids = append([]int{v.Access}, vids...)
+	}
+	for _, vid := range vids {
+		if vid < 1 || vid > 4094 {
+			return fmt.Errorf("invalid VLAN ID %d, expected a value in the 1-4094 range", vid)
+		}
+	}
+	return nil
+}
+
+// OVSPortArgs returns the ovs-vsctl arguments setting the VLANs of the OVS port
+func (v *LinkVLAN) OVSPortArgs(port string) []string {
+	args := []string{"set", "port", port}
+	if v.Access != 0 {
+		args = append(args, "tag="+strconv.Itoa(v.Access))
+	}
+	if len(v.Trunk) != 0 {
+		vids := make([]string, 0, len(v.Trunk))
+		for _, vid := range v.Trunk {
+			vids = append(vids, strconv.Itoa(vid))
+		}
+		args = append(args, "trunks="+strings.Join(vids, ","))
+	}
+	return args
+}
@@ -0,0 +1,59 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package types
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLinkVLAN(t *testing.T) {
+	tests := map[string]struct {
+		vlan     *LinkVLAN
+		wantErr  bool
+		wantArgs []string
+	}{
+		"access": {
+			vlan:     &LinkVLAN{Access: 10},
+			wantArgs: []string{"set", "port", "p1", "tag=10"},
+		},
+		"trunk": {
+			vlan:     &LinkVLAN{Trunk: []int{20, 30}},
+			wantArgs: []string{"set", "port", "p1", "trunks=20,30"},
+		},
+		"native-and-trunk": {
+			vlan:     &LinkVLAN{Access: 10, Trunk: []int{20}},
+			wantArgs: []string{"set", "port", "p1", "tag=10", "trunks=20"},
+		},
+		"empty": {
+			vlan:    &LinkVLAN{},
+			wantErr: true,
+		},
+		"access-out-of-range": {
+			vlan:    &LinkVLAN{Access: 4095},
+			wantErr: true,
+		},
+		"trunk-zero": {
+			vlan:    &LinkVLAN{Access: 10, Trunk: []int{0}},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tc.vlan.Validate()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if d := cmp.Diff(tc.wantArgs, tc.vlan.OVSPortArgs("p1")); d != "" {
+				t.Errorf("ovs-vsctl args mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CapAll stands for all the capabilities in the cap-add and cap-drop lists
+const CapAll = "ALL"
+
+// capRe matches the capability names in the CAP_ prefixed form, e.g. CAP_NET_ADMIN
+var capRe = regexp.MustCompile(`^CAP_[A-Z_]+$`)
+
+// sysctlRe matches the kernel parameter names, e.g. net.ipv4.conf.all.rp_filter
+var sysctlRe = regexp.MustCompile(`^[a-z0-9_-]+(\.[a-zA-Z0-9_-]+)+$`)
+
+// NormalizeCapabilities returns the capabilities in the upper case CAP_ prefixed form the container runtimes expect,
+// e.g. net_admin becomes CAP_NET_ADMIN. ALL is kept as is
+func NormalizeCapabilities(caps []string) ([]string, error) {
+	if len(caps) == 0 {
+		return nil, nil
+	}
+	res := make([]string, 0, len(caps))
+	seen := map[string]struct{}{}
+	for _, c := range caps {
+		n := strings.ToUpper(strings.TrimSpace(c))
+		if n != CapAll && !strings.HasPrefix(n, "CAP_") {
+			n = "CAP_" + n
+		}
+		if n != CapAll && !capRe.MatchString(n) {
+			return nil, fmt.Errorf("invalid capability %q", c)
+		}
+		if _, ok := seen[n]; ok {
+			continue
+		}
+		seen[n] = struct{}{}
+		res = append(res, n)
+	}
+	return res, nil
+}
+
+// ValidateSysctls checks the names and the values of the kernel parameters set in the node container
+func ValidateSysctls(sysctls map[string]string) error {
+	for k, v := range sysctls {
+		if !sysctlRe.MatchString(k) {
+			return fmt.Errorf("invalid sysctl name %q", k)
+		}
+		if strings.TrimSpace(v) == "" {
+			return fmt.Errorf("sysctl %s has no value", k)
+		}
+	}
+	return nil
+}
+
+// Privileged returns true when the node container runs privileged, with all the capabilities and the host devices.
+// The nodes with the cap-add or cap-drop settings run unprivileged with the default capabilities of the container runtime
+// adjusted by these settings
+func (node *NodeConfig) Privileged() bool {
+	return len(node.CapAdd) == 0 && len(node.CapDrop) == 0
+}
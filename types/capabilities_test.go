@@ -0,0 +1,55 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeCapabilities(t *testing.T) {
+	tests := map[string]struct {
+		in      []string
+		want    []string
+		wantErr bool
+	}{
+		"empty":     {},
+		"prefixed":  {in: []string{"CAP_NET_ADMIN"}, want: []string{"CAP_NET_ADMIN"}},
+		"short":     {in: []string{"net_admin", "SYS_ADMIN"}, want: []string{"CAP_NET_ADMIN", "CAP_SYS_ADMIN"}},
+		"all":       {in: []string{"all"}, want: []string{"ALL"}},
+		"duplicate": {in: []string{"NET_RAW", "cap_net_raw"}, want: []string{"CAP_NET_RAW"}},
+		"invalid":   {in: []string{"net-admin"}, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := NormalizeCapabilities(tc.in)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("wanted error %v, got %v", tc.wantErr, err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("wanted %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestValidateSysctls(t *testing.T) {
+	tests := map[string]struct {
+		in      map[string]string
+		wantErr bool
+	}{
+		"valid":    {in: map[string]string{"net.ipv4.ip_forward": "1", "net.ipv4.conf.eth1.rp_filter": "0"}},
+		"no-dots":  {in: map[string]string{"ip_forward": "1"}, wantErr: true},
+		"slashes":  {in: map[string]string{"net/ipv4/ip_forward": "1"}, wantErr: true},
+		"no-value": {in: map[string]string{"net.ipv4.ip_forward": ""}, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := ValidateSysctls(tc.in); (err != nil) != tc.wantErr {
+				t.Errorf("wanted error %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package types
+
+import "time"
+
+// states of the containers reported with the container events
+const (
+	EventCreated = "created"
+	EventRunning = "running"
+	EventExited  = "exited"
+	EventOOM     = "oom"
+	EventRemoved = "removed"
+	// EventReady is reported by containerlab when the running node becomes ready to be used,
+	// e.g. the VM of a vrnetlab node has booted
+	EventReady = "ready"
+)
+
+// ContainerEvent is a life-cycle event of a container reported by the container runtime
+type ContainerEvent struct {
+	Time          time.Time
+	ContainerID   string
+	ContainerName string
+	Labels        map[string]string
+	// state of the container after the event, one of the Event* states
+	State string
+	// exit code of the exited containers
+	ExitCode int
+}
@@ -0,0 +1,76 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package types
+
+import (
+	"reflect"
+
+	"github.com/srl-labs/containerlab/utils"
+)
+
+// ApplyGroups merges the settings of the groups into the definitions of their nodes,
+// so that the node settings take precedence over the group ones and the group settings
+// take precedence over the kind settings and the defaults.
+// The maps, e.g. env and labels, are merged key by key, the group exec commands run before the node ones
+func (t *Topology) ApplyGroups() {
+	if len(t.Groups) == 0 {
+		return
+	}
+	for name := range t.Nodes {
+		g, ok := t.Groups[t.GetNodeGroup(name)]
+		if !ok || g == nil {
+			continue
+		}
+		t.Nodes[name] = mergeNodeDefinitions(g, t.Nodes[name])
+	}
+}
+
+// mergeNodeDefinitions returns a new node definition with the fields of the base definition
+// overridden by the fields set in the node definition
+func mergeNodeDefinitions(base, node *NodeDefinition) *NodeDefinition {
+	res := *base
+	if node == nil {
+		node = new(NodeDefinition)
+	}
+	rv := reflect.ValueOf(&res).Elem()
+	nv := reflect.ValueOf(node).Elem()
+	for i := 0; i < nv.NumField(); i++ {
+		f := nv.Field(i)
+		// the maps are copied to not share them between the nodes of the group
+		if f.Kind() == reflect.Map && !(f.IsZero() && rv.Field(i).IsZero()) {
+			m := reflect.MakeMap(f.Type())
+			for _, src := range []reflect.Value{rv.Field(i), f} {
+				iter := src.MapRange()
+				for iter.Next() {
+					m.SetMapIndex(iter.Key(), iter.Value())
+				}
+			}
+			rv.Field(i).Set(m)
+			continue
+		}
+		if f.IsZero() {
+			continue
+		}
+		rv.Field(i).Set(f)
+	}
+	res.Exec = append(append([]string{}, base.Exec...), node.Exec...)
+	if base.Config != nil && node.Config != nil {
+		cfg := *base.Config
+		cfg.Vars = utils.MergeMaps(base.Config.GetVars(), node.Config.GetVars())
+		if node.Config.Transport != "" {
+			cfg.Transport = node.Config.Transport
+		}
+		if len(node.Config.Snippets) != 0 {
+			cfg.Snippets = node.Config.Snippets
+		}
+		if len(node.Config.Templates) != 0 {
+			cfg.Templates = node.Config.Templates
+		}
+		res.Config = &cfg
+	}
+	// the group of the node is not set by the group settings
+	res.Group = node.Group
+	return &res
+}
@@ -0,0 +1,111 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package types
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestApplyGroups(t *testing.T) {
+	topo := &Topology{
+		Defaults: &NodeDefinition{Image: "default:latest", Env: map[string]string{"env1": "default"}},
+		Kinds: map[string]*NodeDefinition{
+			"srl":   {Image: "srl:latest", Env: map[string]string{"env2": "kind"}},
+			"linux": {Group: "leaf"},
+		},
+		Groups: map[string]*NodeDefinition{
+			"leaf": {
+				Kind:     "srl",
+				Image:    "srl:21.6.4",
+				Position: "tier1",
+				Env:      map[string]string{"env2": "group", "env3": "group"},
+				Labels:   map[string]string{"role": "leaf"},
+				Binds:    []string{"leaf.cfg:/leaf.cfg"},
+				Exec:     []string{"echo group"},
+				Config:   &ConfigDispatcher{Vars: map[string]interface{}{"asn": 65001, "role": "leaf"}},
+			},
+		},
+		Nodes: map[string]*NodeDefinition{
+			"leaf1": {
+				Group:  "leaf",
+				Env:    map[string]string{"env3": "node"},
+				Exec:   []string{"echo node"},
+				Config: &ConfigDispatcher{Vars: map[string]interface{}{"asn": 65002}},
+			},
+			"leaf2": {Group: "leaf", Image: "srl:21.11.1"},
+			// group set with the kind
+			"host1":  {Kind: "linux"},
+			"spine1": {Kind: "srl", Group: "spine"},
+			"empty":  nil,
+		},
+	}
+	topo.ApplyGroups()
+
+	type node struct {
+		Kind, Image, Group, Position string
+		Env, Labels                  map[string]string
+		Binds, Exec                  []string
+		Vars                         map[string]interface{}
+	}
+	get := func(name string) node {
+		return node{
+			Kind:     topo.GetNodeKind(name),
+			Image:    topo.GetNodeImage(name),
+			Group:    topo.GetNodeGroup(name),
+			Position: topo.GetNodePosition(name),
+			Env:      topo.GetNodeEnv(name),
+			Labels:   topo.GetNodeLabels(name),
+			Binds:    topo.GetNodeBinds(name),
+			Exec:     topo.GetNodeExec(name),
+			Vars:     topo.GetNodeConfigDispatcher(name).GetVars(),
+		}
+	}
+	leafLabels := map[string]string{"role": "leaf"}
+	tests := map[string]node{
+		"leaf1": {
+			Kind: "srl", Image: "srl:21.6.4", Group: "leaf", Position: "tier1",
+			Env:    map[string]string{"env1": "default", "env2": "group", "env3": "node"},
+			Labels: leafLabels,
+			Binds:  []string{"leaf.cfg:/leaf.cfg"},
+			Exec:   []string{"echo group", "echo node"},
+			Vars:   map[string]interface{}{"asn": 65002, "role": "leaf"},
+		},
+		"leaf2": {
+			Kind: "srl", Image: "srl:21.11.1", Group: "leaf", Position: "tier1",
+			Env:    map[string]string{"env1": "default", "env2": "group", "env3": "group"},
+			Labels: leafLabels,
+			Binds:  []string{"leaf.cfg:/leaf.cfg"},
+			Exec:   []string{"echo group"},
+			Vars:   map[string]interface{}{"asn": 65001, "role": "leaf"},
+		},
+		"host1": {
+			Kind: "linux", Image: "srl:21.6.4", Group: "leaf", Position: "tier1",
+			Env:    map[string]string{"env1": "default", "env2": "group", "env3": "group"},
+			Labels: leafLabels,
+			Binds:  []string{"leaf.cfg:/leaf.cfg"},
+			Exec:   []string{"echo group"},
+			Vars:   map[string]interface{}{"asn": 65001, "role": "leaf"},
+		},
+		"spine1": {
+			Kind: "srl", Image: "srl:latest", Group: "spine",
+			Env:  map[string]string{"env1": "default", "env2": "kind"},
+			Vars: map[string]interface{}{},
+		},
+	}
+	for name, want := range tests {
+		t.Run(name, func(t *testing.T) {
+			if d := cmp.Diff(want, get(name)); d != "" {
+				t.Errorf("node settings mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+	// the group settings are not shared between the nodes
+	topo.Nodes["leaf2"].Env["env3"] = "changed"
+	if v := topo.Groups["leaf"].Env["env3"]; v != "group" {
+		t.Errorf("group env modified through the node, got %q", v)
+	}
+}
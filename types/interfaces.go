@@ -0,0 +1,234 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package types
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	InterfaceStateUp   = "up"
+	InterfaceStateDown = "down"
+)
+
+// InterfaceConfig defines addressing, routes and state of a node's interface
+type InterfaceConfig struct {
+	// list of IPv4 addresses in the address/prefix-length format
+	IPv4 []string `yaml:"ipv4,omitempty"`
+	// list of IPv6 addresses in the address/prefix-length format
+	IPv6 []string `yaml:"ipv6,omitempty"`
+	// administrative state of the interface, up or down
+	State string `yaml:"state,omitempty"`
+	// routes via this interface
+	Routes []*RouteConfig `yaml:"routes,omitempty"`
+}
+
+// InterfaceProfile defines the settings applied to the link interfaces of a node
+// when they are placed into the node netns
+type InterfaceProfile struct {
+	// MTU of the interfaces, overrides the link MTU on the node side
+	MTU int `yaml:"mtu,omitempty"`
+	// value of the arp_ignore sysctl of the interfaces
+	ARPIgnore *int `yaml:"arp-ignore,omitempty"`
+	// enables or disables IPv4 and IPv6 forwarding on the interfaces
+	Forwarding *bool `yaml:"forwarding,omitempty"`
+}
+
+// RouteConfig defines a route via an interface
+type RouteConfig struct {
+	// destination prefix, `default` for the default route
+	Dst string `yaml:"dst,omitempty"`
+	// next-hop address, when omitted the destination is directly connected
+	Via string `yaml:"via,omitempty"`
+}
+
+// Validate checks the interface config for errors
+func (i *InterfaceConfig) Validate() error {
+	for _, a := range i.IPv4 {
+		ip, _, err := net.ParseCIDR(a)
+		if err != nil || ip.To4() == nil {
+			return fmt.Errorf("invalid IPv4 address %q", a)
+		}
+	}
+	for _, a := range i.IPv6 {
+		ip, _, err := net.ParseCIDR(a)
+		if err != nil || ip.To4() != nil {
+			return fmt.Errorf("invalid IPv6 address %q", a)
+		}
+	}
+	switch i.State {
+	case "", InterfaceStateUp, InterfaceStateDown:
+	default:
+		return fmt.Errorf("invalid interface state %q, expected %s or %s", i.State, InterfaceStateUp, InterfaceStateDown)
+	}
+	for _, r := range i.Routes {
+		if _, err := r.dst(); err != nil {
+			return err
+		}
+		if r.Via != "" && net.ParseIP(r.Via) == nil {
+			return fmt.Errorf("invalid route next-hop %q", r.Via)
+		}
+	}
+	return nil
+}
+
+// Validate checks the interface profile for errors
+func (p *InterfaceProfile) Validate() error {
+	if p.MTU != 0 && (p.MTU < 68 || p.MTU > 65535) {
+		return fmt.Errorf("invalid interface profile MTU %d, expected a value between 68 and 65535", p.MTU)
+	}
+	if p.ARPIgnore != nil {
+		switch *p.ARPIgnore {
+		case 0, 1, 2, 3, 8:
+		default:
+			return fmt.Errorf("invalid interface profile arp-ignore %d, expected 0-3 or 8", *p.ARPIgnore)
+		}
+	}
+	return nil
+}
+
+// Sysctls returns the per-interface sysctls of the profile for the interface name,
+// the keys are the paths relative to /proc/sys
+func (p *InterfaceProfile) Sysctls(name string) map[string]string {
+	res := map[string]string{}
+	if p == nil {
+		return res
+	}
+	// dots in the interface names are replaced with slashes in the sysctl paths
+	name = strings.ReplaceAll(name, ".", "/")
+	if p.ARPIgnore != nil {
+		res[path.Join("net/ipv4/conf", name, "arp_ignore")] = strconv.Itoa(*p.ARPIgnore)
+	}
+	if p.Forwarding != nil {
+		v := "0"
+		if *p.Forwarding {
+			v = "1"
+		}
+		res[path.Join("net/ipv4/conf", name, "forwarding")] = v
+		res[path.Join("net/ipv6/conf", name, "forwarding")] = v
+	}
+	return res
+}
+
+// ApplyInterfaceProfile applies the profile to the link in the current netns
+func ApplyInterfaceProfile(link netlink.Link, p *InterfaceProfile) error {
+	if p == nil {
+		return nil
+	}
+	if p.MTU != 0 {
+		if err := netlink.LinkSetMTU(link, p.MTU); err != nil {
+			return fmt.Errorf("failed to set MTU of %s: %v", link.Attrs().Name, err)
+		}
+	}
+	// /proc/sys/net reflects the netns of the current thread
+	for k, v := range p.Sysctls(link.Attrs().Name) {
+		if err := ioutil.WriteFile(path.Join("/proc/sys", k), []byte(v), 0640); err != nil {
+			return fmt.Errorf("failed to set sysctl %s: %v", k, err)
+		}
+	}
+	return nil
+}
+
+// dst returns the destination prefix of the route
+func (r *RouteConfig) dst() (*net.IPNet, error) {
+	if r.Dst == "default" || r.Dst == "" {
+		// default route family is derived from the next-hop
+		if ip := net.ParseIP(r.Via); ip != nil && ip.To4() == nil {
+			_, n, _ := net.ParseCIDR("::/0")
+			return n, nil
+		}
+		_, n, _ := net.ParseCIDR("0.0.0.0/0")
+		return n, nil
+	}
+	_, n, err := net.ParseCIDR(r.Dst)
+	if err != nil {
+		return nil, fmt.Errorf("invalid route destination %q", r.Dst)
+	}
+	return n, nil
+}
+
+// ConfigureInterfaces applies the interfaces config of a node inside its network namespace
+func ConfigureInterfaces(n *NodeConfig) error {
+	if len(n.Interfaces) == 0 {
+		return nil
+	}
+	nodeNS, err := ns.GetNS(n.NSPath)
+	if err != nil {
+		return err
+	}
+	defer nodeNS.Close()
+
+	// interfaces are configured in a stable order
+	names := make([]string, 0, len(n.Interfaces))
+	for name := range n.Interfaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return nodeNS.Do(func(_ ns.NetNS) error {
+		for _, name := range names {
+			if err := configureInterface(name, n.Interfaces[name]); err != nil {
+				return fmt.Errorf("failed to configure interface %s of node %s: %v", name, n.ShortName, err)
+			}
+			log.Debugf("configured interface %s of node %s", name, n.ShortName)
+		}
+		return nil
+	})
+}
+
+// configureInterface applies interface config to a link in the current netns
+func configureInterface(name string, cfg *InterfaceConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	link, err := netlink.LinkByName(name)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range append(cfg.IPv4, cfg.IPv6...) {
+		addr, err := netlink.ParseAddr(a)
+		if err != nil {
+			return err
+		}
+		// replace makes the operation idempotent for the redeployed nodes
+		if err := netlink.AddrReplace(link, addr); err != nil {
+			return fmt.Errorf("failed to add address %s: %v", a, err)
+		}
+	}
+
+	if cfg.State == InterfaceStateDown {
+		return netlink.LinkSetDown(link)
+	}
+	if err := netlink.LinkSetUp(link); err != nil {
+		return err
+	}
+
+	for _, r := range cfg.Routes {
+		dst, err := r.dst()
+		if err != nil {
+			return err
+		}
+		route := &netlink.Route{
+			LinkIndex: link.Attrs().Index,
+			Dst:       dst,
+			Gw:        net.ParseIP(r.Via),
+		}
+		if err := netlink.RouteReplace(route); err != nil {
+			return fmt.Errorf("failed to add route to %s: %v", dst, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package types
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestInterfaceProfileSysctls(t *testing.T) {
+	arpIgnore, forwarding := 1, false
+	tests := map[string]struct {
+		profile *InterfaceProfile
+		iface   string
+		want    map[string]string
+	}{
+		"nil-profile": {
+			iface: "eth1",
+			want:  map[string]string{},
+		},
+		"mtu-only": {
+			profile: &InterfaceProfile{MTU: 1500},
+			iface:   "eth1",
+			want:    map[string]string{},
+		},
+		"arp-ignore-and-forwarding": {
+			profile: &InterfaceProfile{ARPIgnore: &arpIgnore, Forwarding: &forwarding},
+			iface:   "eth1",
+			want: map[string]string{
+				"net/ipv4/conf/eth1/arp_ignore": "1",
+				"net/ipv4/conf/eth1/forwarding": "0",
+				"net/ipv6/conf/eth1/forwarding": "0",
+			},
+		},
+		"vlan-interface": {
+			profile: &InterfaceProfile{ARPIgnore: &arpIgnore},
+			iface:   "eth1.10",
+			want:    map[string]string{"net/ipv4/conf/eth1/10/arp_ignore": "1"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := tc.profile.Sysctls(tc.iface)
+			if !cmp.Equal(got, tc.want) {
+				t.Errorf("got: %v, want: %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestInterfaceProfileValidate(t *testing.T) {
+	valid, invalid := 8, 5
+	tests := map[string]struct {
+		profile *InterfaceProfile
+		wantErr bool
+	}{
+		"valid":              {profile: &InterfaceProfile{MTU: 9000, ARPIgnore: &valid}},
+		"mtu-too-small":      {profile: &InterfaceProfile{MTU: 60}, wantErr: true},
+		"invalid-arp-ignore": {profile: &InterfaceProfile{ARPIgnore: &invalid}, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := tc.profile.Validate(); (err != nil) != tc.wantErr {
+				t.Errorf("got error %v, want error: %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package types
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// bind mount options by their group, a bind sets at most one option of a group
+var bindOptions = map[string]string{
+	"ro":       "mode",
+	"rw":       "mode",
+	"shared":   "propagation",
+	"rshared":  "propagation",
+	"slave":    "propagation",
+	"rslave":   "propagation",
+	"private":  "propagation",
+	"rprivate": "propagation",
+	"z":        "selinux",
+	"Z":        "selinux",
+}
+
+// tmpfs mount options taking a value and the patterns of their values
+var tmpfsValueOptions = map[string]*regexp.Regexp{
+	"size": regexp.MustCompile(`^[0-9]+[kKmMgG%]?$`),
+	"mode": regexp.MustCompile(`^[0-7]{3,4}$`),
+	"uid":  regexp.MustCompile(`^[0-9]+$`),
+	"gid":  regexp.MustCompile(`^[0-9]+$`),
+}
+
+// tmpfs mount flags
+var tmpfsFlags = map[string]struct{}{
+	"ro": {}, "rw": {}, "exec": {}, "noexec": {}, "suid": {}, "nosuid": {}, "dev": {}, "nodev": {},
+}
+
+// ValidateBind checks the options of the bind in the src:dst[:options] format,
+// the options are comma separated and set the read-only mode, the mount propagation and the selinux label
+func ValidateBind(bind string) error {
+	elems := strings.Split(bind, ":")
+	if len(elems) < 2 || len(elems) > 3 || elems[0] == "" || elems[1] == "" {
+		return fmt.Errorf("bind %q must be in the src:dst[:options] format", bind)
+	}
+	if !path.IsAbs(elems[1]) {
+		return fmt.Errorf("bind %q must have an absolute container path", bind)
+	}
+	if len(elems) == 2 {
+		return nil
+	}
+	groups := map[string]string{}
+	for _, o := range strings.Split(elems[2], ",") {
+		g, ok := bindOptions[o]
+		if !ok {
+			return fmt.Errorf("bind %q has unknown option %q", bind, o)
+		}
+		if other, ok := groups[g]; ok {
+			return fmt.Errorf("bind %q has conflicting options %q and %q", bind, other, o)
+		}
+		groups[g] = o
+	}
+	return nil
+}
+
+// BindPropagation returns the mount propagation option of the bind, empty when the bind doesn't set it
+func BindPropagation(bind string) string {
+	elems := strings.Split(bind, ":")
+	if len(elems) < 3 {
+		return ""
+	}
+	for _, o := range strings.Split(elems[2], ",") {
+		if bindOptions[o] == "propagation" {
+			return o
+		}
+	}
+	return ""
+}
+
+// ParseTmpfs parses the tmpfs mount in the path[:options] format and returns the container path
+// and the comma separated mount options, e.g. /scratch:size=2g,mode=1777
+func ParseTmpfs(s string) (string, string, error) {
+	p, opts := s, ""
+	if i := strings.Index(s, ":"); i >= 0 {
+		p, opts = s[:i], s[i+1:]
+	}
+	if !path.IsAbs(p) || path.Clean(p) == "/" {
+		return "", "", fmt.Errorf("tmpfs %q must have an absolute container path other than /", s)
+	}
+	if opts == "" {
+		return path.Clean(p), "", nil
+	}
+	for _, o := range strings.Split(opts, ",") {
+		if kv := strings.SplitN(o, "=", 2); len(kv) == 2 {
+			re, ok := tmpfsValueOptions[kv[0]]
+			if !ok {
+				return "", "", fmt.Errorf("tmpfs %q has unknown option %q", s, kv[0])
+			}
+			if !re.MatchString(kv[1]) {
+				return "", "", fmt.Errorf("tmpfs %q has invalid %s %q", s, kv[0], kv[1])
+			}
+			continue
+		}
+		if _, ok := tmpfsFlags[o]; !ok {
+			return "", "", fmt.Errorf("tmpfs %q has unknown option %q", s, o)
+		}
+	}
+	return path.Clean(p), opts, nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package types
+
+import "testing"
+
+func TestValidateBind(t *testing.T) {
+	tests := map[string]bool{
+		"/root/files:/root/files":              true,
+		"/root/files:/root/files:ro":           true,
+		"/var/run/netns:/run/netns:ro,rslave":  true,
+		"/data:/data:rw,shared,z":              true,
+		"data:/var/lib/data":                   true,
+		"/root/files":                          false,
+		"/root/files:root/files":               false,
+		"/root/files:/root/files:ro,rw":        false,
+		"/root/files:/root/files:slave,rslave": false,
+		"/root/files:/root/files:exec":         false,
+		"/root/files:/root/files:ro:z":         false,
+	}
+	for bind, valid := range tests {
+		if err := ValidateBind(bind); (err == nil) != valid {
+			t.Errorf("ValidateBind(%q) error = %v, want valid %v", bind, err, valid)
+		}
+	}
+}
+
+func TestBindPropagation(t *testing.T) {
+	tests := map[string]string{
+		"/a:/b":           "",
+		"/a:/b:ro":        "",
+		"/a:/b:ro,rslave": "rslave",
+		"/a:/b:shared":    "shared",
+	}
+	for bind, want := range tests {
+		if got := BindPropagation(bind); got != want {
+			t.Errorf("BindPropagation(%q) = %q, want %q", bind, got, want)
+		}
+	}
+}
+
+func TestParseTmpfs(t *testing.T) {
+	tests := map[string]struct {
+		tmpfs    string
+		wantPath string
+		wantOpts string
+		wantErr  bool
+	}{
+		"path-only":    {tmpfs: "/scratch", wantPath: "/scratch"},
+		"clean-path":   {tmpfs: "/scratch/", wantPath: "/scratch"},
+		"size-mode":    {tmpfs: "/scratch:size=2g,mode=1777", wantPath: "/scratch", wantOpts: "size=2g,mode=1777"},
+		"flags":        {tmpfs: "/tmp:noexec,nosuid,size=50%", wantPath: "/tmp", wantOpts: "noexec,nosuid,size=50%"},
+		"relative":     {tmpfs: "scratch", wantErr: true},
+		"root":         {tmpfs: "/", wantErr: true},
+		"bad-size":     {tmpfs: "/scratch:size=2tb", wantErr: true},
+		"bad-mode":     {tmpfs: "/scratch:mode=rwx", wantErr: true},
+		"unknown-opt":  {tmpfs: "/scratch:huge=always", wantErr: true},
+		"unknown-flag": {tmpfs: "/scratch:rshared", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, opts, err := ParseTmpfs(tc.tmpfs)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseTmpfs() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if p != tc.wantPath || opts != tc.wantOpts {
+				t.Errorf("ParseTmpfs() = %q, %q, want %q, %q", p, opts, tc.wantPath, tc.wantOpts)
+			}
+		})
+	}
+}
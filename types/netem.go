@@ -0,0 +1,212 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package types
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	// minimal burst of the rate limiting tbf qdisc in bytes, exceeding the jumbo frames size
+	tbfMinBurst = 16384
+	// the rate limited traffic is queued for up to tbfLatencyMs milliseconds
+	tbfLatencyMs = 50
+)
+
+// Netem defines the impairments of the traffic sent out of an interface, emulated with the netem qdisc
+type Netem struct {
+	// delay and its jitter in milliseconds
+	Delay  int `yaml:"delay,omitempty" json:"delay"`
+	Jitter int `yaml:"jitter,omitempty" json:"jitter"`
+	// percentages of the lost, duplicated and corrupted packets
+	Loss       float64 `yaml:"loss,omitempty" json:"loss"`
+	Duplicate  float64 `yaml:"duplicate,omitempty" json:"duplicate"`
+	Corruption float64 `yaml:"corruption,omitempty" json:"corruption"`
+	// rate limit in kbit/s, emulated with the tbf qdisc attached to the netem qdisc
+	Rate int `yaml:"rate,omitempty" json:"rate"`
+}
+
+// IsSet returns true if any of the impairments is set
+func (n *Netem) IsSet() bool {
+	return n != nil && *n != Netem{}
+}
+
+// Validate checks the netem settings for errors
+func (n *Netem) Validate() error {
+	if n.Delay < 0 || n.Jitter < 0 {
+		return fmt.Errorf("netem delay and jitter can't be negative")
+	}
+	if n.Rate < 0 {
+		return fmt.Errorf("netem rate can't be negative")
+	}
+	if n.Jitter != 0 && n.Delay == 0 {
+		return fmt.Errorf("netem jitter requires the delay to be set")
+	}
+	for name, v := range map[string]float64{"loss": n.Loss, "duplicate": n.Duplicate, "corruption": n.Corruption} {
+		if v < 0 || v > 100 {
+			return fmt.Errorf("netem %s %v is not a percentage in the 0-100 range", name, v)
+		}
+	}
+	return nil
+}
+
+// qdiscAttrs returns the attributes of the netem qdisc
+func (n *Netem) qdiscAttrs() netlink.NetemQdiscAttrs {
+	return netlink.NetemQdiscAttrs{
+		Latency:     uint32(n.Delay) * 1000,
+		Jitter:      uint32(n.Jitter) * 1000,
+		Loss:        float32(n.Loss),
+		Duplicate:   float32(n.Duplicate),
+		CorruptProb: float32(n.Corruption),
+	}
+}
+
+// tbfParams returns the rate in bytes per second, the burst and the queue limit in bytes
+// of the tbf qdisc limiting the rate
+func (n *Netem) tbfParams() (rate uint64, burst, limit uint32) {
+	rate = uint64(n.Rate) * 1000 / 8
+	// the bucket holds 10ms of the traffic
+	burst = uint32(rate / 100)
+	if burst < tbfMinBurst {
+		burst = tbfMinBurst
+	}
+	return rate, burst, burst + uint32(rate*tbfLatencyMs/1000)
+}
+
+// TCCommands returns the tc commands applying the impairments to the interface dev,
+// the same way ApplyNetem does with netlink
+func (n *Netem) TCCommands(dev string) []string {
+	if n == nil {
+		return nil
+	}
+	args := []string{"tc qdisc replace dev", dev, "root handle 1: netem"}
+	if n.Delay != 0 {
+		args = append(args, fmt.Sprintf("delay %dms", n.Delay))
+		if n.Jitter != 0 {
+			args = append(args, fmt.Sprintf("%dms", n.Jitter))
+		}
+	}
+	for _, p := range []struct {
+		name string
+		v    float64
+	}{{"loss", n.Loss}, {"duplicate", n.Duplicate}, {"corrupt", n.Corruption}} {
+		if p.v != 0 {
+			args = append(args, fmt.Sprintf("%s %v%%", p.name, p.v))
+		}
+	}
+	cmds := []string{strings.Join(args, " ")}
+	if n.Rate != 0 {
+		_, burst, limit := n.tbfParams()
+		cmds = append(cmds, fmt.Sprintf("tc qdisc replace dev %s parent 1:1 handle 10: tbf rate %dkbit burst %d limit %d",
+			dev, n.Rate, burst, limit))
+	}
+	return cmds
+}
+
+// ApplyNetem sets the netem qdisc as the root qdisc of the link in the current netns,
+// replacing the qdisc set before. The rate is limited with the tbf qdisc attached to the netem qdisc
+func ApplyNetem(link netlink.Link, n *Netem) error {
+	if n == nil {
+		return nil
+	}
+	q := netlink.NewNetem(netlink.QdiscAttrs{
+		LinkIndex: link.Attrs().Index,
+		Handle:    netlink.MakeHandle(1, 0),
+		Parent:    netlink.HANDLE_ROOT,
+	}, n.qdiscAttrs())
+	if err := netlink.QdiscReplace(q); err != nil {
+		return fmt.Errorf("failed to set netem qdisc on %s: %v", link.Attrs().Name, err)
+	}
+	tbfAttrs := netlink.QdiscAttrs{
+		LinkIndex: link.Attrs().Index,
+		Handle:    netlink.MakeHandle(10, 0),
+		Parent:    netlink.MakeHandle(1, 1),
+	}
+	if n.Rate == 0 {
+		// the rate limit set before is removed
+		qdiscs, err := netlink.QdiscList(link)
+		if err != nil {
+			return err
+		}
+		for _, q := range qdiscs {
+			if q.Type() == "tbf" && q.Attrs().Parent == tbfAttrs.Parent {
+				if err := netlink.QdiscDel(q); err != nil {
+					return fmt.Errorf("failed to remove tbf qdisc from %s: %v", link.Attrs().Name, err)
+				}
+			}
+		}
+		return nil
+	}
+	rate, burst, limit := n.tbfParams()
+	tbf := &netlink.Tbf{
+		QdiscAttrs: tbfAttrs,
+		Rate:       rate,
+		Buffer:     netlink.Xmittime(rate, burst),
+		Limit:      limit,
+	}
+	if err := netlink.QdiscReplace(tbf); err != nil {
+		return fmt.Errorf("failed to set tbf qdisc on %s: %v", link.Attrs().Name, err)
+	}
+	return nil
+}
+
+// ResetNetem removes the impairments set with ApplyNetem from the link in the current netns
+func ResetNetem(link netlink.Link) error {
+	qdiscs, err := netlink.QdiscList(link)
+	if err != nil {
+		return err
+	}
+	for _, q := range qdiscs {
+		if q.Type() == "netem" && q.Attrs().Parent == netlink.HANDLE_ROOT {
+			if err := netlink.QdiscDel(q); err != nil {
+				return fmt.Errorf("failed to remove netem qdisc from %s: %v", link.Attrs().Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// LinkNetem returns the impairments of the link in the current netns set with ApplyNetem,
+// nil if the link has no netem qdisc
+func LinkNetem(link netlink.Link) (*Netem, error) {
+	qdiscs, err := netlink.QdiscList(link)
+	if err != nil {
+		return nil, err
+	}
+	var n *Netem
+	var rate uint64
+	for _, q := range qdiscs {
+		switch q := q.(type) {
+		case *netlink.Netem:
+			if q.Parent != netlink.HANDLE_ROOT {
+				continue
+			}
+			n = &Netem{
+				Delay:      int(math.Round(float64(q.Latency) / netlink.TickInUsec() / 1000)),
+				Jitter:     int(math.Round(float64(q.Jitter) / netlink.TickInUsec() / 1000)),
+				Loss:       u32Percentage(q.Loss),
+				Duplicate:  u32Percentage(q.Duplicate),
+				Corruption: u32Percentage(q.CorruptProb),
+			}
+		case *netlink.Tbf:
+			if q.Parent == netlink.MakeHandle(1, 1) {
+				rate = q.Rate
+			}
+		}
+	}
+	if n != nil {
+		n.Rate = int(rate * 8 / 1000)
+	}
+	return n, nil
+}
+
+// u32Percentage converts the netem probability back to the percentage rounded to the hundredths
+func u32Percentage(v uint32) float64 {
+	return math.Round(float64(v)/math.MaxUint32*100*100) / 100
+}
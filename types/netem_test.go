@@ -0,0 +1,103 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package types
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/vishvananda/netlink"
+)
+
+func TestNetem(t *testing.T) {
+	tests := map[string]struct {
+		netem     *Netem
+		wantErr   bool
+		wantAttrs netlink.NetemQdiscAttrs
+	}{
+		"delay-and-loss": {
+			netem:     &Netem{Delay: 100, Jitter: 10, Loss: 2.5},
+			wantAttrs: netlink.NetemQdiscAttrs{Latency: 100000, Jitter: 10000, Loss: 2.5},
+		},
+		"duplicate-and-corruption": {
+			netem:     &Netem{Duplicate: 1, Corruption: 0.1},
+			wantAttrs: netlink.NetemQdiscAttrs{Duplicate: 1, CorruptProb: 0.1},
+		},
+		"jitter-without-delay": {
+			netem:   &Netem{Jitter: 10},
+			wantErr: true,
+		},
+		"negative-delay": {
+			netem:   &Netem{Delay: -1},
+			wantErr: true,
+		},
+		"loss-out-of-range": {
+			netem:   &Netem{Loss: 101},
+			wantErr: true,
+		},
+		"negative-rate": {
+			netem:   &Netem{Rate: -1},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tc.netem.Validate()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got error: %v, want error: %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if got := tc.netem.qdiscAttrs(); !cmp.Equal(got, tc.wantAttrs) {
+				t.Errorf("got: %+v, want: %+v", got, tc.wantAttrs)
+			}
+		})
+	}
+}
+
+func TestNetemTCCommands(t *testing.T) {
+	tests := map[string]struct {
+		netem *Netem
+		want  []string
+	}{
+		"nil": {},
+		"delay-jitter-loss": {
+			netem: &Netem{Delay: 100, Jitter: 10, Loss: 2.5},
+			want:  []string{"tc qdisc replace dev e1-1 root handle 1: netem delay 100ms 10ms loss 2.5%"},
+		},
+		"rate": {
+			netem: &Netem{Duplicate: 1, Rate: 10000},
+			want: []string{
+				"tc qdisc replace dev e1-1 root handle 1: netem duplicate 1%",
+				"tc qdisc replace dev e1-1 parent 1:1 handle 10: tbf rate 10000kbit burst 16384 limit 78884",
+			},
+		},
+		"high-rate": {
+			netem: &Netem{Rate: 1000000},
+			want: []string{
+				"tc qdisc replace dev e1-1 root handle 1: netem",
+				"tc qdisc replace dev e1-1 parent 1:1 handle 10: tbf rate 1000000kbit burst 1250000 limit 7500000",
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tc.netem.TCCommands("e1-1"); !cmp.Equal(got, tc.want) {
+				t.Errorf("got: %q, want: %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNetemIsSet(t *testing.T) {
+	if (*Netem)(nil).IsSet() || (&Netem{}).IsSet() {
+		t.Errorf("empty netem is set")
+	}
+	if !(&Netem{Rate: 100}).IsSet() {
+		t.Errorf("netem with the rate is not set")
+	}
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"fmt"
 	"os"
 	"strings"
 )
@@ -52,6 +53,66 @@ type NodeDefinition struct {
 	CPU string `yaml:"cpu,omitempty"`
 	// Set node RAM (cgroup or hypervisor)
 	RAM string `yaml:"ram,omitempty"`
+	// memory limit of the node container, e.g. 4GB
+	Memory string `yaml:"memory,omitempty"`
+	// CPUs the node container is pinned to, e.g. 0-3,8
+	CPUSet string `yaml:"cpu-set,omitempty"`
+	// Interfaces addressing, routes and state
+	Interfaces map[string]*InterfaceConfig `yaml:"interfaces,omitempty"`
+	// settings applied to the link interfaces of the node
+	InterfaceProfile *InterfaceProfile `yaml:"interface-profile,omitempty"`
+	// node directory path overriding the default location within the lab directory
+	LabDir string `yaml:"lab-dir,omitempty"`
+	// add link peers with their interface addresses to /etc/hosts
+	PeerHosts bool `yaml:"peer-hosts,omitempty"`
+	// node certificate subject, validity and SANs
+	Certificate *CertificateConfig `yaml:"certificate,omitempty"`
+	// generate TLS certificate and key for the node
+	TLS bool `yaml:"tls,omitempty"`
+	// timezone name from the IANA database, e.g. Europe/Brussels
+	Timezone string `yaml:"timezone,omitempty"`
+	// DNS servers, search domains and resolver options of the node
+	DNS *DNSConfig `yaml:"dns,omitempty"`
+	// container paths retained across redeploys, optionally prefixed with a volume name
+	Persist []string `yaml:"persist,omitempty"`
+	// settings of the bridge and ovs-bridge nodes
+	Bridge *BridgeConfig `yaml:"bridge,omitempty"`
+	// credentials used to log in to the node, e.g. to save its config
+	Credentials *Credentials `yaml:"credentials,omitempty"`
+	// readiness checks the deployment waits on before the node is used
+	WaitFor *WaitFor `yaml:"wait-for,omitempty"`
+	// impairments of the traffic the node sends over the management interface
+	MgmtNetem *Netem `yaml:"mgmt-netem,omitempty"`
+	// names of the nodes deployed and ready before the node is deployed
+	DependsOn []string `yaml:"depends-on,omitempty"`
+	// routes installed in the network namespace of the node
+	StaticRoutes []*StaticRoute `yaml:"static-routes,omitempty"`
+	// gateway replacing the default gateway of the management network, none removes the default routes
+	DefaultGateway string `yaml:"default-gateway,omitempty"`
+	// name of the lab host the node is deployed on in the multi-host labs
+	LabHost string `yaml:"lab-host,omitempty"`
+	// commands run on the container host by the watch command when the node becomes ready and when its container exits
+	OnReady string `yaml:"on-ready,omitempty"`
+	OnExit  string `yaml:"on-exit,omitempty"`
+	// Dockerfile the node image is built from instead of pulling the image
+	Build *BuildConfig `yaml:"build,omitempty"`
+	// running container adopted into the lab with the import command instead of being deployed
+	Import *ImportConfig `yaml:"import,omitempty"`
+	// tmpfs mounts in the path[:options] format
+	Tmpfs []string `yaml:"tmpfs,omitempty"`
+	// env files in the dotenv format read into the node env
+	EnvFiles []string `yaml:"env-files,omitempty"`
+	// commands run inside the node or on the container host at the lifecycle points of the node
+	Hooks *Hooks `yaml:"hooks,omitempty"`
+	// vCPU pinning, CPU model and hugepages of the VM of the vrnetlab based nodes
+	VM *VMConfig `yaml:"vm,omitempty"`
+	// kernel parameters set in the network namespace of the node container
+	Sysctls map[string]string `yaml:"sysctls,omitempty"`
+	// capabilities added to and dropped from the node container, the node container runs unprivileged when any is set
+	CapAdd  []string `yaml:"cap-add,omitempty"`
+	CapDrop []string `yaml:"cap-drop,omitempty"`
+	// time the node is given to shut down gracefully on destroy before it is removed, e.g. 3m
+	StopTimeout string `yaml:"stop-timeout,omitempty"`
 
 	// Extra options, may be kind specific
 	Extras *Extras `yaml:"extras,omitempty"`
@@ -240,6 +301,48 @@ func (n *NodeDefinition) GetNodeRAM() string {
 	return n.RAM
 }
 
+func (n *NodeDefinition) GetNodeMemory() string {
+	if n == nil {
+		return ""
+	}
+	return n.Memory
+}
+
+func (n *NodeDefinition) GetNodeCPUSet() string {
+	if n == nil {
+		return ""
+	}
+	return n.CPUSet
+}
+
+func (n *NodeDefinition) GetInterfaces() map[string]*InterfaceConfig {
+	if n == nil {
+		return nil
+	}
+	return n.Interfaces
+}
+
+func (n *NodeDefinition) GetInterfaceProfile() *InterfaceProfile {
+	if n == nil {
+		return nil
+	}
+	return n.InterfaceProfile
+}
+
+func (n *NodeDefinition) GetLabDir() string {
+	if n == nil {
+		return ""
+	}
+	return n.LabDir
+}
+
+func (n *NodeDefinition) GetPeerHosts() bool {
+	if n == nil {
+		return false
+	}
+	return n.PeerHosts
+}
+
 func (n *NodeDefinition) GetExec() []string {
 	if n == nil {
 		return nil
@@ -247,6 +350,181 @@ func (n *NodeDefinition) GetExec() []string {
 	return n.Exec
 }
 
+func (n *NodeDefinition) GetTimezone() string {
+	if n == nil {
+		return ""
+	}
+	return n.Timezone
+}
+
+func (n *NodeDefinition) GetOnReady() string {
+	if n == nil {
+		return ""
+	}
+	return n.OnReady
+}
+
+func (n *NodeDefinition) GetOnExit() string {
+	if n == nil {
+		return ""
+	}
+	return n.OnExit
+}
+
+func (n *NodeDefinition) GetBuild() *BuildConfig {
+	if n == nil {
+		return nil
+	}
+	return n.Build
+}
+
+func (n *NodeDefinition) GetImport() *ImportConfig {
+	if n == nil {
+		return nil
+	}
+	return n.Import
+}
+
+func (n *NodeDefinition) GetTmpfs() []string {
+	if n == nil {
+		return nil
+	}
+	return n.Tmpfs
+}
+
+func (n *NodeDefinition) GetEnvFiles() []string {
+	if n == nil {
+		return nil
+	}
+	return n.EnvFiles
+}
+
+func (n *NodeDefinition) GetHooks() *Hooks {
+	if n == nil {
+		return nil
+	}
+	return n.Hooks
+}
+
+func (n *NodeDefinition) GetVM() *VMConfig {
+	if n == nil {
+		return nil
+	}
+	return n.VM
+}
+
+func (n *NodeDefinition) GetSysctls() map[string]string {
+	if n == nil {
+		return nil
+	}
+	return n.Sysctls
+}
+
+func (n *NodeDefinition) GetCapAdd() []string {
+	if n == nil {
+		return nil
+	}
+	return n.CapAdd
+}
+
+func (n *NodeDefinition) GetCapDrop() []string {
+	if n == nil {
+		return nil
+	}
+	return n.CapDrop
+}
+
+func (n *NodeDefinition) GetStopTimeout() string {
+	if n == nil {
+		return ""
+	}
+	return n.StopTimeout
+}
+
+func (n *NodeDefinition) GetDNS() *DNSConfig {
+	if n == nil {
+		return nil
+	}
+	return n.DNS
+}
+
+func (n *NodeDefinition) GetPersist() []string {
+	if n == nil {
+		return nil
+	}
+	return n.Persist
+}
+
+func (n *NodeDefinition) GetBridge() *BridgeConfig {
+	if n == nil {
+		return nil
+	}
+	return n.Bridge
+}
+
+func (n *NodeDefinition) GetCredentials() *Credentials {
+	if n == nil {
+		return nil
+	}
+	return n.Credentials
+}
+
+func (n *NodeDefinition) GetWaitFor() *WaitFor {
+	if n == nil {
+		return nil
+	}
+	return n.WaitFor
+}
+
+func (n *NodeDefinition) GetMgmtNetem() *Netem {
+	if n == nil {
+		return nil
+	}
+	return n.MgmtNetem
+}
+
+func (n *NodeDefinition) GetDependsOn() []string {
+	if n == nil {
+		return nil
+	}
+	return n.DependsOn
+}
+
+func (n *NodeDefinition) GetStaticRoutes() []*StaticRoute {
+	if n == nil {
+		return nil
+	}
+	return n.StaticRoutes
+}
+
+func (n *NodeDefinition) GetDefaultGateway() string {
+	if n == nil {
+		return ""
+	}
+	return n.DefaultGateway
+}
+
+func (n *NodeDefinition) GetLabHost() string {
+	if n == nil {
+		return ""
+	}
+	return n.LabHost
+}
+
+func (n *NodeDefinition) GetTLS() bool {
+	if n == nil {
+		return false
+	}
+	return n.TLS
+}
+
+func (n *NodeDefinition) GetCertificate() *CertificateConfig {
+	if n == nil {
+		return nil
+	}
+	return n.Certificate
+}
+
 func (n *NodeDefinition) GetExtras() *Extras {
 	if n == nil {
 		return nil
@@ -254,6 +532,38 @@ func (n *NodeDefinition) GetExtras() *Extras {
 	return n.Extras
 }
 
+// UnmarshalYAML decodes the node definition accepting the mgmt-ipv4 and mgmt-ipv6 spellings
+// of the management addresses in addition to mgmt_ipv4 and mgmt_ipv6
+func (n *NodeDefinition) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type nodeDefinition NodeDefinition
+	var def struct {
+		nodeDefinition `yaml:",inline"`
+		MgmtIPv4       string `yaml:"mgmt-ipv4,omitempty"`
+		MgmtIPv6       string `yaml:"mgmt-ipv6,omitempty"`
+	}
+	if err := unmarshal(&def); err != nil {
+		return err
+	}
+	*n = NodeDefinition(def.nodeDefinition)
+	for _, a := range []struct {
+		name        string
+		addr, alias string
+		dst         *string
+	}{
+		{"ipv4", n.MgmtIPv4, def.MgmtIPv4, &n.MgmtIPv4},
+		{"ipv6", n.MgmtIPv6, def.MgmtIPv6, &n.MgmtIPv6},
+	} {
+		if a.alias == "" {
+			continue
+		}
+		if a.addr != "" && a.addr != a.alias {
+			return fmt.Errorf("mgmt_%s %q and mgmt-%s %q are both set", a.name, a.addr, a.name, a.alias)
+		}
+		*a.dst = a.alias
+	}
+	return nil
+}
+
 // ImportEnvs imports all environment variales defined in the shell
 // if __IMPORT_ENVS is set to true
 func (n *NodeDefinition) ImportEnvs() {
@@ -0,0 +1,55 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package types
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestNodeDefinitionMgmtAddresses(t *testing.T) {
+	tests := map[string]struct {
+		def        string
+		ipv4, ipv6 string
+		wantErr    bool
+	}{
+		"underscore": {
+			def:  "{mgmt_ipv4: 172.20.20.11, mgmt_ipv6: '2001:172:20:20::11'}",
+			ipv4: "172.20.20.11", ipv6: "2001:172:20:20::11",
+		},
+		"dash": {
+			def:  "{mgmt-ipv4: 172.20.20.11, mgmt-ipv6: '2001:172:20:20::11'}",
+			ipv4: "172.20.20.11", ipv6: "2001:172:20:20::11",
+		},
+		"both-same": {
+			def:  "{mgmt_ipv4: 172.20.20.11, mgmt-ipv4: 172.20.20.11}",
+			ipv4: "172.20.20.11",
+		},
+		"both-different": {
+			def:     "{mgmt_ipv4: 172.20.20.11, mgmt-ipv4: 172.20.20.12}",
+			wantErr: true,
+		},
+		"unknown-field": {
+			def:     "{mgmt-ipv5: 172.20.20.11}",
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			n := new(NodeDefinition)
+			err := yaml.UnmarshalStrict([]byte(tc.def), n)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("wanted error %v, got %v", tc.wantErr, err)
+			}
+			if tc.wantErr {
+				return
+			}
+			if n.GetMgmtIPv4() != tc.ipv4 || n.GetMgmtIPv6() != tc.ipv6 {
+				t.Errorf("wanted addresses %q %q, got %q %q", tc.ipv4, tc.ipv6, n.GetMgmtIPv4(), n.GetMgmtIPv6())
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/go-units"
+)
+
+// cpuSetRe matches the cpuset lists, e.g. 0-3,8
+var cpuSetRe = regexp.MustCompile(`^\d+(-\d+)?(,\d+(-\d+)?)*$`)
+
+// ResourceLimits returns the cgroup limits of the node container set with the cpu and memory settings,
+// the cpu limit is returned in units of 1e-9 CPUs and the memory limit in bytes.
+// Zero values mean the resource is not limited
+func (node *NodeConfig) ResourceLimits() (nanoCPUs, memory int64, err error) {
+	if node.CPU != "" {
+		cpu, err := strconv.ParseFloat(node.CPU, 64)
+		if err != nil || cpu <= 0 {
+			return 0, 0, fmt.Errorf("invalid cpu value %q", node.CPU)
+		}
+		nanoCPUs = int64(cpu * 1e9)
+	}
+	if node.Memory != "" {
+		if memory, err = units.RAMInBytes(node.Memory); err != nil || memory <= 0 {
+			return 0, 0, fmt.Errorf("invalid memory value %q", node.Memory)
+		}
+	}
+	if node.CPUSet != "" && !cpuSetRe.MatchString(node.CPUSet) {
+		return 0, 0, fmt.Errorf("invalid cpu-set value %q", node.CPUSet)
+	}
+	return nanoCPUs, memory, nil
+}
+
+// ParseCPUSet returns the CPUs of the cpuset list, e.g. 0-3,8, in the order they are listed
+func ParseCPUSet(s string) ([]int, error) {
+	if !cpuSetRe.MatchString(s) {
+		return nil, fmt.Errorf("invalid cpuset %q", s)
+	}
+	var cpus []int
+	seen := map[int]struct{}{}
+	for _, r := range strings.Split(s, ",") {
+		bounds := strings.SplitN(r, "-", 2)
+		first, _ := strconv.Atoi(bounds[0])
+		last := first
+		if len(bounds) == 2 {
+			last, _ = strconv.Atoi(bounds[1])
+		}
+		if last < first {
+			return nil, fmt.Errorf("invalid cpuset range %q", r)
+		}
+		for cpu := first; cpu <= last; cpu++ {
+			if _, ok := seen[cpu]; ok {
+				return nil, fmt.Errorf("cpu %d is listed more than once in cpuset %q", cpu, s)
+			}
+			seen[cpu] = struct{}{}
+			cpus = append(cpus, cpu)
+		}
+	}
+	return cpus, nil
+}
+
+// ContainerStats is the resource usage of a container reported by the container runtime
+type ContainerStats struct {
+	// CPU time consumed by the container since its start
+	CPUUsage time.Duration
+	// memory used by the container in bytes, excluding the inactive page cache
+	MemoryUsage uint64
+	// memory limit of the container in bytes, 0 when the memory is not limited
+	MemoryLimit uint64
+}
@@ -0,0 +1,81 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResourceLimits(t *testing.T) {
+	tests := map[string]struct {
+		node         *NodeConfig
+		wantErr      bool
+		wantNanoCPUs int64
+		wantMemory   int64
+	}{
+		"no-limits": {
+			node: &NodeConfig{},
+		},
+		"cpu-memory-cpuset": {
+			node:         &NodeConfig{CPU: "1.5", Memory: "2GB", CPUSet: "0-3,8"},
+			wantNanoCPUs: 1500000000,
+			wantMemory:   2 << 30,
+		},
+		"invalid-cpu": {
+			node:    &NodeConfig{CPU: "two"},
+			wantErr: true,
+		},
+		"negative-cpu": {
+			node:    &NodeConfig{CPU: "-1"},
+			wantErr: true,
+		},
+		"invalid-memory": {
+			node:    &NodeConfig{Memory: "lots"},
+			wantErr: true,
+		},
+		"invalid-cpuset": {
+			node:    &NodeConfig{CPUSet: "0-"},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cpus, mem, err := tc.node.ResourceLimits()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("wanted error %v, got %v", tc.wantErr, err)
+			}
+			if cpus != tc.wantNanoCPUs || mem != tc.wantMemory {
+				t.Errorf("wanted cpus %d memory %d, got cpus %d memory %d", tc.wantNanoCPUs, tc.wantMemory, cpus, mem)
+			}
+		})
+	}
+}
+
+func TestParseCPUSet(t *testing.T) {
+	tests := map[string]struct {
+		in      string
+		want    []int
+		wantErr bool
+	}{
+		"single":     {in: "3", want: []int{3}},
+		"range-list": {in: "4-6,1", want: []int{4, 5, 6, 1}},
+		"reversed":   {in: "6-4", wantErr: true},
+		"duplicate":  {in: "1-3,2", wantErr: true},
+		"malformed":  {in: "1,", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseCPUSet(tc.in)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("wanted error %v, got %v", tc.wantErr, err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("wanted %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package types
+
+import (
+	"fmt"
+	"net"
+)
+
+// NoDefaultGateway is the default-gateway value removing the default routes of the management network
+const NoDefaultGateway = "none"
+
+// StaticRoute is a route installed in the network namespace of the node
+type StaticRoute struct {
+	// destination prefix of the route
+	Dst string `yaml:"dst"`
+	// next-hop address, the route points to the dev interface when not set
+	Via string `yaml:"via,omitempty"`
+	// name of the interface the route points to
+	Dev    string `yaml:"dev,omitempty"`
+	Metric int    `yaml:"metric,omitempty"`
+}
+
+// Validate checks the route for errors
+func (r *StaticRoute) Validate() error {
+	_, dst, err := net.ParseCIDR(r.Dst)
+	if err != nil {
+		return fmt.Errorf("static route destination %q is not a prefix", r.Dst)
+	}
+	if r.Via == "" && r.Dev == "" {
+		return fmt.Errorf("static route to %s requires the via address or the dev interface", r.Dst)
+	}
+	if r.Via != "" {
+		via := net.ParseIP(r.Via)
+		if via == nil {
+			return fmt.Errorf("static route to %s has invalid via address %q", r.Dst, r.Via)
+		}
+		if (via.To4() == nil) != (dst.IP.To4() == nil) {
+			return fmt.Errorf("static route to %s has via address %s of another IP family", r.Dst, r.Via)
+		}
+	}
+	if r.Metric < 0 {
+		return fmt.Errorf("static route to %s has negative metric", r.Dst)
+	}
+	return nil
+}
+
+// ValidateDefaultGateway checks the default-gateway value is either an IP address or none
+func ValidateDefaultGateway(gw string) error {
+	if gw == "" || gw == NoDefaultGateway || net.ParseIP(gw) != nil {
+		return nil
+	}
+	return fmt.Errorf("default-gateway %q is neither an IP address nor %q", gw, NoDefaultGateway)
+}
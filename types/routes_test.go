@@ -0,0 +1,66 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package types
+
+import "testing"
+
+func TestStaticRouteValidate(t *testing.T) {
+	tests := map[string]struct {
+		route   *StaticRoute
+		wantErr bool
+	}{
+		"via": {
+			route: &StaticRoute{Dst: "10.0.0.0/8", Via: "192.168.1.1"},
+		},
+		"dev": {
+			route: &StaticRoute{Dst: "2001:db8::/32", Dev: "eth1", Metric: 100},
+		},
+		"ipv6-via": {
+			route: &StaticRoute{Dst: "::/0", Via: "2001:db8::1", Dev: "eth1"},
+		},
+		"not-a-prefix": {
+			route:   &StaticRoute{Dst: "10.0.0.1", Via: "192.168.1.1"},
+			wantErr: true,
+		},
+		"no-via-or-dev": {
+			route:   &StaticRoute{Dst: "10.0.0.0/8"},
+			wantErr: true,
+		},
+		"invalid-via": {
+			route:   &StaticRoute{Dst: "10.0.0.0/8", Via: "gw1"},
+			wantErr: true,
+		},
+		"family-mismatch": {
+			route:   &StaticRoute{Dst: "10.0.0.0/8", Via: "2001:db8::1"},
+			wantErr: true,
+		},
+		"negative-metric": {
+			route:   &StaticRoute{Dst: "10.0.0.0/8", Dev: "eth1", Metric: -1},
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := tc.route.Validate(); (err != nil) != tc.wantErr {
+				t.Fatalf("got error: %v, want error: %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateDefaultGateway(t *testing.T) {
+	for gw, wantErr := range map[string]bool{
+		"":            false,
+		"none":        false,
+		"192.168.1.1": false,
+		"2001:db8::1": false,
+		"gw1":         true,
+		"10.0.0.0/8":  true,
+	} {
+		if err := ValidateDefaultGateway(gw); (err != nil) != wantErr {
+			t.Errorf("gateway %q: got error: %v, want error: %v", gw, err, wantErr)
+		}
+	}
+}
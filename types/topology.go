@@ -13,14 +13,17 @@ import (
 type Topology struct {
 	Defaults *NodeDefinition            `yaml:"defaults,omitempty"`
 	Kinds    map[string]*NodeDefinition `yaml:"kinds,omitempty"`
-	Nodes    map[string]*NodeDefinition `yaml:"nodes,omitempty"`
-	Links    []*LinkConfig              `yaml:"links,omitempty"`
+	// settings shared by the nodes of a group, taking precedence over the kinds and the defaults
+	Groups map[string]*NodeDefinition `yaml:"groups,omitempty"`
+	Nodes  map[string]*NodeDefinition `yaml:"nodes,omitempty"`
+	Links  []*LinkConfig              `yaml:"links,omitempty"`
 }
 
 func NewTopology() *Topology {
 	return &Topology{
 		Defaults: new(NodeDefinition),
 		Kinds:    make(map[string]*NodeDefinition),
+		Groups:   make(map[string]*NodeDefinition),
 		Nodes:    make(map[string]*NodeDefinition),
 		Links:    make([]*LinkConfig, 0),
 	}
@@ -30,6 +33,17 @@ type LinkConfig struct {
 	Endpoints []string
 	Labels    map[string]string      `yaml:"labels,omitempty"`
 	Vars      map[string]interface{} `yaml:"vars,omitempty"`
+	// VLANs of the bridge port the link is attached to
+	VLAN *LinkVLAN `yaml:"vlan,omitempty"`
+	// MTU of both veth endpoints of the link, overrides the lab-wide link MTU
+	MTU int `yaml:"mtu,omitempty"`
+	// type of the link: veth (default), macvlan or ipvlan.
+	// The macvlan and ipvlan links create a sub-interface of the host NIC of the host endpoint
+	Type string `yaml:"type,omitempty"`
+	// mode of the macvlan or ipvlan sub-interface
+	Mode string `yaml:"mode,omitempty"`
+	// impairments of the traffic sent out of both endpoints of the link
+	Netem Netem `yaml:",inline"`
 }
 
 func (t *Topology) GetDefaults() *NodeDefinition {
@@ -140,9 +154,26 @@ func (t *Topology) GetNodeConfigDispatcher(name string) *ConfigDispatcher {
 			t.GetKind(t.GetNodeKind(name)).GetConfigDispatcher().GetVars(),
 			ndef.GetConfigDispatcher().GetVars())
 
-		return &ConfigDispatcher{
+		res := &ConfigDispatcher{
 			Vars: vars,
 		}
+		// the push settings are taken from the node, its kind or the defaults
+		for _, cd := range []*ConfigDispatcher{
+			t.Defaults.GetConfigDispatcher(),
+			t.GetKind(t.GetNodeKind(name)).GetConfigDispatcher(),
+			ndef.GetConfigDispatcher(),
+		} {
+			if cd.GetTransport() != "" {
+				res.Transport = cd.GetTransport()
+			}
+			if len(cd.GetSnippets()) != 0 {
+				res.Snippets = cd.GetSnippets()
+			}
+			if len(cd.GetTemplates()) != 0 {
+				res.Templates = cd.GetTemplates()
+			}
+		}
+		return res
 	}
 
 	return nil
@@ -400,6 +431,391 @@ func (t *Topology) GetNodeRAM(name string) string {
 	return ""
 }
 
+func (t *Topology) GetNodeMemory(name string) string {
+	if ndef, ok := t.Nodes[name]; ok {
+		if ndef.GetNodeMemory() != "" {
+			return ndef.GetNodeMemory()
+		}
+		if t.GetKind(t.GetNodeKind(name)).GetNodeMemory() != "" {
+			return t.GetKind(t.GetNodeKind(name)).GetNodeMemory()
+		}
+		return t.GetDefaults().GetNodeMemory()
+	}
+	return ""
+}
+
+func (t *Topology) GetNodeCPUSet(name string) string {
+	if ndef, ok := t.Nodes[name]; ok {
+		if ndef.GetNodeCPUSet() != "" {
+			return ndef.GetNodeCPUSet()
+		}
+		if t.GetKind(t.GetNodeKind(name)).GetNodeCPUSet() != "" {
+			return t.GetKind(t.GetNodeKind(name)).GetNodeCPUSet()
+		}
+		return t.GetDefaults().GetNodeCPUSet()
+	}
+	return ""
+}
+
+func (t *Topology) GetNodePeerHosts(name string) bool {
+	if ndef, ok := t.Nodes[name]; ok {
+		if ndef.GetPeerHosts() {
+			return true
+		}
+		if t.GetKind(t.GetNodeKind(name)).GetPeerHosts() {
+			return true
+		}
+		return t.GetDefaults().GetPeerHosts()
+	}
+	return false
+}
+
+func (t *Topology) GetNodeTimezone(name string) string {
+	if ndef, ok := t.Nodes[name]; ok {
+		if ndef.GetTimezone() != "" {
+			return ndef.GetTimezone()
+		}
+		if t.GetKind(t.GetNodeKind(name)).GetTimezone() != "" {
+			return t.GetKind(t.GetNodeKind(name)).GetTimezone()
+		}
+		return t.GetDefaults().GetTimezone()
+	}
+	return ""
+}
+
+// GetNodeOnReady returns the on-ready hook of the node, its kind or the defaults
+func (t *Topology) GetNodeOnReady(name string) string {
+	if ndef, ok := t.Nodes[name]; ok {
+		if ndef.GetOnReady() != "" {
+			return ndef.GetOnReady()
+		}
+		if t.GetKind(t.GetNodeKind(name)).GetOnReady() != "" {
+			return t.GetKind(t.GetNodeKind(name)).GetOnReady()
+		}
+		return t.GetDefaults().GetOnReady()
+	}
+	return ""
+}
+
+// GetNodeOnExit returns the on-exit hook of the node, its kind or the defaults
+func (t *Topology) GetNodeOnExit(name string) string {
+	if ndef, ok := t.Nodes[name]; ok {
+		if ndef.GetOnExit() != "" {
+			return ndef.GetOnExit()
+		}
+		if t.GetKind(t.GetNodeKind(name)).GetOnExit() != "" {
+			return t.GetKind(t.GetNodeKind(name)).GetOnExit()
+		}
+		return t.GetDefaults().GetOnExit()
+	}
+	return ""
+}
+
+// GetNodeBuild returns the build config of the node, its kind or the defaults
+func (t *Topology) GetNodeBuild(name string) *BuildConfig {
+	if ndef, ok := t.Nodes[name]; ok {
+		if ndef.GetBuild() != nil {
+			return ndef.GetBuild()
+		}
+		if t.GetKind(t.GetNodeKind(name)).GetBuild() != nil {
+			return t.GetKind(t.GetNodeKind(name)).GetBuild()
+		}
+		return t.GetDefaults().GetBuild()
+	}
+	return nil
+}
+
+// GetNodeImport returns the import config of the node, the container to import is set per node only
+func (t *Topology) GetNodeImport(name string) *ImportConfig {
+	if ndef, ok := t.Nodes[name]; ok {
+		return ndef.GetImport()
+	}
+	return nil
+}
+
+// GetNodeTmpfs returns the tmpfs mounts of the node, its kind or the defaults
+func (t *Topology) GetNodeTmpfs(name string) []string {
+	if ndef, ok := t.Nodes[name]; ok {
+		if len(ndef.GetTmpfs()) > 0 {
+			return ndef.GetTmpfs()
+		}
+		if len(t.GetKind(t.GetNodeKind(name)).GetTmpfs()) > 0 {
+			return t.GetKind(t.GetNodeKind(name)).GetTmpfs()
+		}
+		return t.GetDefaults().GetTmpfs()
+	}
+	return nil
+}
+
+// GetNodeEnvFiles returns the env files of the defaults, the node kind and the node in that order
+func (t *Topology) GetNodeEnvFiles(name string) []string {
+	if ndef, ok := t.Nodes[name]; ok {
+		var files []string
+		files = append(files, t.GetDefaults().GetEnvFiles()...)
+		files = append(files, t.GetKind(t.GetNodeKind(name)).GetEnvFiles()...)
+		return append(files, ndef.GetEnvFiles()...)
+	}
+	return nil
+}
+
+// GetNodeHooks returns the hooks of the node, its kind or the defaults
+func (t *Topology) GetNodeHooks(name string) *Hooks {
+	if ndef, ok := t.Nodes[name]; ok {
+		if ndef.GetHooks() != nil {
+			return ndef.GetHooks()
+		}
+		if t.GetKind(t.GetNodeKind(name)).GetHooks() != nil {
+			return t.GetKind(t.GetNodeKind(name)).GetHooks()
+		}
+		return t.GetDefaults().GetHooks()
+	}
+	return nil
+}
+
+// GetNodeVM returns the VM settings of the node, its kind or the defaults
+func (t *Topology) GetNodeVM(name string) *VMConfig {
+	if ndef, ok := t.Nodes[name]; ok {
+		if ndef.GetVM() != nil {
+			return ndef.GetVM()
+		}
+		if t.GetKind(t.GetNodeKind(name)).GetVM() != nil {
+			return t.GetKind(t.GetNodeKind(name)).GetVM()
+		}
+		return t.GetDefaults().GetVM()
+	}
+	return nil
+}
+
+// GetNodeSysctls returns the sysctls of the defaults, the node kind and the node merged in that order
+func (t *Topology) GetNodeSysctls(name string) map[string]string {
+	if ndef, ok := t.Nodes[name]; ok {
+		return utils.MergeStringMaps(t.GetDefaults().GetSysctls(),
+			t.GetKind(t.GetNodeKind(name)).GetSysctls(),
+			ndef.GetSysctls())
+	}
+	return nil
+}
+
+// GetNodeCapAdd returns the capabilities added to the node container by the node, its kind or the defaults
+func (t *Topology) GetNodeCapAdd(name string) []string {
+	if ndef, ok := t.Nodes[name]; ok {
+		if len(ndef.GetCapAdd()) > 0 {
+			return ndef.GetCapAdd()
+		}
+		if len(t.GetKind(t.GetNodeKind(name)).GetCapAdd()) > 0 {
+			return t.GetKind(t.GetNodeKind(name)).GetCapAdd()
+		}
+		return t.GetDefaults().GetCapAdd()
+	}
+	return nil
+}
+
+// GetNodeCapDrop returns the capabilities dropped from the node container by the node, its kind or the defaults
+func (t *Topology) GetNodeCapDrop(name string) []string {
+	if ndef, ok := t.Nodes[name]; ok {
+		if len(ndef.GetCapDrop()) > 0 {
+			return ndef.GetCapDrop()
+		}
+		if len(t.GetKind(t.GetNodeKind(name)).GetCapDrop()) > 0 {
+			return t.GetKind(t.GetNodeKind(name)).GetCapDrop()
+		}
+		return t.GetDefaults().GetCapDrop()
+	}
+	return nil
+}
+
+// GetNodeStopTimeout returns the stop timeout of the node, its kind or the defaults
+func (t *Topology) GetNodeStopTimeout(name string) string {
+	if ndef, ok := t.Nodes[name]; ok {
+		if ndef.GetStopTimeout() != "" {
+			return ndef.GetStopTimeout()
+		}
+		if t.GetKind(t.GetNodeKind(name)).GetStopTimeout() != "" {
+			return t.GetKind(t.GetNodeKind(name)).GetStopTimeout()
+		}
+		return t.GetDefaults().GetStopTimeout()
+	}
+	return ""
+}
+
+// GetNodeDNS returns the DNS settings of the node, its kind or the defaults
+func (t *Topology) GetNodeDNS(name string) *DNSConfig {
+	if ndef, ok := t.Nodes[name]; ok {
+		if ndef.GetDNS() != nil {
+			return ndef.GetDNS()
+		}
+		if t.GetKind(t.GetNodeKind(name)).GetDNS() != nil {
+			return t.GetKind(t.GetNodeKind(name)).GetDNS()
+		}
+		return t.GetDefaults().GetDNS()
+	}
+	return nil
+}
+
+func (t *Topology) GetNodePersist(name string) []string {
+	if ndef, ok := t.Nodes[name]; ok {
+		if len(ndef.GetPersist()) > 0 {
+			return ndef.GetPersist()
+		}
+		if len(t.GetKind(t.GetNodeKind(name)).GetPersist()) > 0 {
+			return t.GetKind(t.GetNodeKind(name)).GetPersist()
+		}
+		return t.GetDefaults().GetPersist()
+	}
+	return nil
+}
+
+func (t *Topology) GetNodeInterfaceProfile(name string) *InterfaceProfile {
+	if ndef, ok := t.Nodes[name]; ok {
+		if ndef.GetInterfaceProfile() != nil {
+			return ndef.GetInterfaceProfile()
+		}
+		if t.GetKind(t.GetNodeKind(name)).GetInterfaceProfile() != nil {
+			return t.GetKind(t.GetNodeKind(name)).GetInterfaceProfile()
+		}
+		return t.GetDefaults().GetInterfaceProfile()
+	}
+	return nil
+}
+
+func (t *Topology) GetNodeBridge(name string) *BridgeConfig {
+	if ndef, ok := t.Nodes[name]; ok {
+		if ndef.GetBridge() != nil {
+			return ndef.GetBridge()
+		}
+		if t.GetKind(t.GetNodeKind(name)).GetBridge() != nil {
+			return t.GetKind(t.GetNodeKind(name)).GetBridge()
+		}
+		return t.GetDefaults().GetBridge()
+	}
+	return nil
+}
+
+func (t *Topology) GetNodeCredentials(name string) *Credentials {
+	if ndef, ok := t.Nodes[name]; ok {
+		if ndef.GetCredentials() != nil {
+			return ndef.GetCredentials()
+		}
+		if t.GetKind(t.GetNodeKind(name)).GetCredentials() != nil {
+			return t.GetKind(t.GetNodeKind(name)).GetCredentials()
+		}
+		return t.GetDefaults().GetCredentials()
+	}
+	return nil
+}
+
+func (t *Topology) GetNodeWaitFor(name string) *WaitFor {
+	if ndef, ok := t.Nodes[name]; ok {
+		if ndef.GetWaitFor() != nil {
+			return ndef.GetWaitFor()
+		}
+		if t.GetKind(t.GetNodeKind(name)).GetWaitFor() != nil {
+			return t.GetKind(t.GetNodeKind(name)).GetWaitFor()
+		}
+		return t.GetDefaults().GetWaitFor()
+	}
+	return nil
+}
+
+func (t *Topology) GetNodeMgmtNetem(name string) *Netem {
+	if ndef, ok := t.Nodes[name]; ok {
+		if ndef.GetMgmtNetem() != nil {
+			return ndef.GetMgmtNetem()
+		}
+		if t.GetKind(t.GetNodeKind(name)).GetMgmtNetem() != nil {
+			return t.GetKind(t.GetNodeKind(name)).GetMgmtNetem()
+		}
+		return t.GetDefaults().GetMgmtNetem()
+	}
+	return nil
+}
+
+// GetNodeDependsOn returns the nodes the node depends on,
+// the node itself is dropped from the dependencies set for its kind or in the defaults
+func (t *Topology) GetNodeDependsOn(name string) []string {
+	ndef, ok := t.Nodes[name]
+	if !ok {
+		return nil
+	}
+	deps := ndef.GetDependsOn()
+	if len(deps) == 0 {
+		deps = t.GetKind(t.GetNodeKind(name)).GetDependsOn()
+	}
+	if len(deps) == 0 {
+		deps = t.GetDefaults().GetDependsOn()
+	}
+	var res []string
+	for _, d := range deps {
+		if d != name {
+			res = append(res, d)
+		}
+	}
+	return res
+}
+
+func (t *Topology) GetNodeStaticRoutes(name string) []*StaticRoute {
+	if ndef, ok := t.Nodes[name]; ok {
+		if len(ndef.GetStaticRoutes()) > 0 {
+			return ndef.GetStaticRoutes()
+		}
+		if len(t.GetKind(t.GetNodeKind(name)).GetStaticRoutes()) > 0 {
+			return t.GetKind(t.GetNodeKind(name)).GetStaticRoutes()
+		}
+		return t.GetDefaults().GetStaticRoutes()
+	}
+	return nil
+}
+
+func (t *Topology) GetNodeDefaultGateway(name string) string {
+	if ndef, ok := t.Nodes[name]; ok {
+		if ndef.GetDefaultGateway() != "" {
+			return ndef.GetDefaultGateway()
+		}
+		if t.GetKind(t.GetNodeKind(name)).GetDefaultGateway() != "" {
+			return t.GetKind(t.GetNodeKind(name)).GetDefaultGateway()
+		}
+		return t.GetDefaults().GetDefaultGateway()
+	}
+	return ""
+}
+
+func (t *Topology) GetNodeLabHost(name string) string {
+	if ndef, ok := t.Nodes[name]; ok {
+		if ndef.GetLabHost() != "" {
+			return ndef.GetLabHost()
+		}
+		if t.GetKind(t.GetNodeKind(name)).GetLabHost() != "" {
+			return t.GetKind(t.GetNodeKind(name)).GetLabHost()
+		}
+		return t.GetDefaults().GetLabHost()
+	}
+	return ""
+}
+
+func (t *Topology) GetNodeTLS(name string) bool {
+	if ndef, ok := t.Nodes[name]; ok {
+		if ndef.GetTLS() {
+			return true
+		}
+		if t.GetKind(t.GetNodeKind(name)).GetTLS() {
+			return true
+		}
+		return t.GetDefaults().GetTLS()
+	}
+	return false
+}
+
+// GetNodeCertificate returns the certificate settings of the node.
+// Fields not set for the node are taken from its kind and then from the defaults
+func (t *Topology) GetNodeCertificate(name string) *CertificateConfig {
+	if ndef, ok := t.Nodes[name]; ok {
+		return t.GetDefaults().GetCertificate().
+			Merge(t.GetKind(t.GetNodeKind(name)).GetCertificate()).
+			Merge(ndef.GetCertificate())
+	}
+	return nil
+}
+
 // Returns the 'extras' section for the given node
 func (t *Topology) GetNodeExtras(name string) *Extras {
 	if ndef, ok := t.Nodes[name]; ok {
@@ -425,6 +841,10 @@ func (t *Topology) ImportEnvs() {
 		k.ImportEnvs()
 	}
 
+	for _, g := range t.Groups {
+		g.ImportEnvs()
+	}
+
 	for _, n := range t.Nodes {
 		n.ImportEnvs()
 	}
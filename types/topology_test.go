@@ -438,3 +438,96 @@ func TestGetNodeLabels(t *testing.T) {
 		}
 	}
 }
+
+func TestGetNodeCertificate(t *testing.T) {
+	topo := &Topology{
+		Defaults: &NodeDefinition{
+			Certificate: &CertificateConfig{Organization: "org", Expiry: "8760h"},
+		},
+		Kinds: map[string]*NodeDefinition{
+			"srl": {
+				Certificate: &CertificateConfig{Organization: "lab", SANs: []string{"*.lab.example.com"}},
+			},
+		},
+		Nodes: map[string]*NodeDefinition{
+			"node1": {
+				Kind:        "srl",
+				Certificate: &CertificateConfig{CommonName: "node1.example.com", SANs: []string{"10.0.0.1"}},
+			},
+			"node2": {
+				Kind: "linux",
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		node string
+		want *CertificateConfig
+	}{
+		"node_kind_defaults": {
+			node: "node1",
+			want: &CertificateConfig{
+				CommonName:   "node1.example.com",
+				Organization: "lab",
+				Expiry:       "8760h",
+				SANs:         []string{"*.lab.example.com", "10.0.0.1"},
+			},
+		},
+		"defaults_only": {
+			node: "node2",
+			want: &CertificateConfig{Organization: "org", Expiry: "8760h"},
+		},
+		"missing_node": {
+			node: "node3",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := topo.GetNodeCertificate(tc.node)
+			if !cmp.Equal(got, tc.want) {
+				t.Errorf("diff (-want +got):\n%s", cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
+
+func TestGetNodeTLS(t *testing.T) {
+	topo := &Topology{
+		Kinds: map[string]*NodeDefinition{
+			"ceos": {TLS: true},
+		},
+		Nodes: map[string]*NodeDefinition{
+			"node1": {Kind: "ceos"},
+			"node2": {Kind: "crpd"},
+			"node3": {Kind: "crpd", TLS: true},
+		},
+	}
+	for node, want := range map[string]bool{"node1": true, "node2": false, "node3": true, "node4": false} {
+		if got := topo.GetNodeTLS(node); got != want {
+			t.Errorf("node %s: expected tls %v, got %v", node, want, got)
+		}
+	}
+}
+
+func TestGetNodeDNS(t *testing.T) {
+	def := &DNSConfig{Servers: []string{"10.0.0.53"}}
+	kind := &DNSConfig{Servers: []string{"10.0.1.53"}, Search: []string{"example.com"}}
+	node := &DNSConfig{Options: []string{"ndots:2"}}
+	topo := &Topology{
+		Defaults: &NodeDefinition{DNS: def},
+		Kinds: map[string]*NodeDefinition{
+			"srl": {DNS: kind},
+		},
+		Nodes: map[string]*NodeDefinition{
+			"node1": {Kind: "srl"},
+			"node2": {Kind: "linux"},
+			"node3": {Kind: "srl", DNS: node},
+		},
+	}
+	for name, want := range map[string]*DNSConfig{"node1": kind, "node2": def, "node3": node, "node4": nil} {
+		if d := cmp.Diff(want, topo.GetNodeDNS(name)); d != "" {
+			t.Errorf("node %s: dns mismatch (-want +got):\n%s", name, d)
+		}
+	}
+}
@@ -7,10 +7,12 @@ package types
 import (
 	"bytes"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/containernetworking/plugins/pkg/ns"
 	"github.com/docker/go-connections/nat"
@@ -25,6 +27,32 @@ type Link struct {
 	MTU    int
 	Labels map[string]string
 	Vars   map[string]interface{}
+	// VLANs of the bridge port the link is attached to
+	VLAN *LinkVLAN
+	// impairments of the traffic sent out of both endpoints of the link
+	Netem *Netem
+	// VxLAN tunnel to the lab host of the remote endpoint of the link
+	Stitch *VxlanStitch
+	// type and mode of the link, the macvlan and ipvlan links are
+	// the sub-interfaces of the host NIC of the host endpoint
+	Type string
+	Mode string
+}
+
+// VxlanStitch is the VxLAN tunnel replacing the endpoint of a link which node is deployed on another lab host.
+// The local endpoint is connected with a veth to the host netns, where the veth is stitched to the tunnel with tc
+type VxlanStitch struct {
+	// name of the remote lab host
+	Host string
+	// address of the remote lab host
+	Remote net.IP
+	VNI    int
+	// UDP port of the tunnel
+	Port int
+	// name of the VxLAN interface
+	Name string
+	// name of the veth interface in the host netns
+	HostIf string
 }
 
 func (link *Link) String() string {
@@ -44,11 +72,428 @@ type Endpoint struct {
 // mgmtNet struct defines the management network options
 // it is provided via docker network object
 type MgmtNet struct {
-	Network    string `yaml:"network,omitempty"` // docker network name
-	Bridge     string `yaml:"bridge,omitempty"`  // linux bridge backing the docker network (or containerd bridge net)
-	IPv4Subnet string `yaml:"ipv4_subnet,omitempty"`
-	IPv6Subnet string `yaml:"ipv6_subnet,omitempty"`
-	MTU        string `yaml:"mtu,omitempty"`
+	Network    string      `yaml:"network,omitempty" json:"network,omitempty"` // docker network name
+	Bridge     string      `yaml:"bridge,omitempty" json:"bridge,omitempty"`   // linux bridge backing the docker network (or containerd bridge net)
+	IPv4Subnet string      `yaml:"ipv4_subnet,omitempty" json:"ipv4_subnet,omitempty"`
+	IPv6Subnet string      `yaml:"ipv6_subnet,omitempty" json:"ipv6_subnet,omitempty"`
+	MTU        string      `yaml:"mtu,omitempty" json:"mtu,omitempty"`
+	IPAM       *IPAMConfig `yaml:"ipam,omitempty" json:"ipam,omitempty"`         // management addresses allocation
+	Firewall   string      `yaml:"firewall,omitempty" json:"firewall,omitempty"` // firewall backend managing the management network rules
+	// the network or the bridge exists already, it is not created nor deleted by containerlab
+	External bool `yaml:"external,omitempty" json:"external,omitempty"`
+}
+
+// IPAMConfig defines the IPAM used to allocate management addresses
+type IPAMConfig struct {
+	Type  string `yaml:"type,omitempty" json:"type,omitempty"`
+	File  string `yaml:"file,omitempty" json:"file,omitempty"` // addresses file of the static IPAM
+	URL   string `yaml:"url,omitempty" json:"url,omitempty"`   // endpoint of the external IPAM
+	Token string `yaml:"token,omitempty" json:"-"`             // bearer token for the external IPAM
+}
+
+// Credentials define the username and password containerlab logs in to the node with
+type Credentials struct {
+	Username string `yaml:"username,omitempty" json:"username,omitempty"`
+	Password string `yaml:"password,omitempty" json:"-"`
+}
+
+// WaitFor defines the readiness checks the deployment waits on before the node is used
+type WaitFor struct {
+	// TCP port probed on the management address of the node, e.g. 22 or 830
+	Port int `yaml:"port,omitempty" json:"port,omitempty"`
+	// time in seconds the node is given to become ready
+	Timeout int `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+// GetPort returns the TCP port probed on the node, 0 if no port is probed
+func (w *WaitFor) GetPort() int {
+	if w == nil {
+		return 0
+	}
+	return w.Port
+}
+
+// GetTimeout returns the time in seconds the node is given to become ready, 0 if unset
+func (w *WaitFor) GetTimeout() int {
+	if w == nil {
+		return 0
+	}
+	return w.Timeout
+}
+
+// CAConfig defines the certificate authority which signs the node certificates
+type CAConfig struct {
+	// external CA certificate, either a path to a PEM file or PEM encoded data
+	Cert string `yaml:"cert,omitempty" json:"cert,omitempty"`
+	// external CA private key, either a path to a PEM file or PEM encoded data
+	Key string `yaml:"key,omitempty" json:"key,omitempty"`
+	// sign the node certificates with an intermediate CA issued by the root CA
+	Intermediate bool `yaml:"intermediate,omitempty" json:"intermediate,omitempty"`
+}
+
+// IsExternal returns true if the certificate or key of an external CA is set
+func (c *CAConfig) IsExternal() bool {
+	return c != nil && (c.Cert != "" || c.Key != "")
+}
+
+// UseIntermediate returns true if the node certificates are signed by an intermediate CA
+func (c *CAConfig) UseIntermediate() bool {
+	return c != nil && c.Intermediate
+}
+
+// CertificateConfig defines the subject, validity and SANs of a node certificate
+type CertificateConfig struct {
+	CommonName       string `yaml:"common-name,omitempty"`
+	Country          string `yaml:"country,omitempty"`
+	Locality         string `yaml:"locality,omitempty"`
+	Organization     string `yaml:"organization,omitempty"`
+	OrganizationUnit string `yaml:"organization-unit,omitempty"`
+	// certificate validity period, e.g. 8760h
+	Expiry string `yaml:"expiry,omitempty"`
+	// additional DNS names and IP addresses
+	SANs []string `yaml:"sans,omitempty"`
+}
+
+// Merge returns a copy of c with the fields set in o overriding the ones of c,
+// SANs of both configs are combined
+func (c *CertificateConfig) Merge(o *CertificateConfig) *CertificateConfig {
+	if o == nil {
+		return c
+	}
+	if c == nil {
+		c = &CertificateConfig{}
+	}
+	r := *c
+	r.SANs = append(append([]string{}, c.SANs...), o.SANs...)
+	override := func(dst *string, src string) {
+		if src != "" {
+			*dst = src
+		}
+	}
+	override(&r.CommonName, o.CommonName)
+	override(&r.Country, o.Country)
+	override(&r.Locality, o.Locality)
+	override(&r.Organization, o.Organization)
+	override(&r.OrganizationUnit, o.OrganizationUnit)
+	override(&r.Expiry, o.Expiry)
+	return &r
+}
+
+// BuildConfig defines the Dockerfile a node image is built from
+type BuildConfig struct {
+	// build context directory, relative to the topology file
+	Context string `yaml:"context,omitempty"`
+	// path to the Dockerfile relative to the context directory, Dockerfile by default
+	Dockerfile string `yaml:"dockerfile,omitempty"`
+	// build-time variables of the Dockerfile
+	Args map[string]string `yaml:"args,omitempty"`
+}
+
+// GetDockerfile returns the path to the Dockerfile relative to the build context
+func (b *BuildConfig) GetDockerfile() string {
+	if b.Dockerfile == "" {
+		return "Dockerfile"
+	}
+	return b.Dockerfile
+}
+
+// ImportConfig defines the running container a node is imported from,
+// the container is matched by its name or by all of the labels
+type ImportConfig struct {
+	// name of the container
+	Container string `yaml:"container,omitempty"`
+	// labels the container is matched with
+	Labels map[string]string `yaml:"labels,omitempty"`
+}
+
+// Hooks defines the commands run at the lifecycle points of a node
+type Hooks struct {
+	// commands run before the node is created, the pre-deploy hooks are run on the container host
+	PreDeploy []*Hook `yaml:"pre-deploy,omitempty"`
+	// commands run once the node is deployed and ready
+	PostDeploy []*Hook `yaml:"post-deploy,omitempty"`
+	// commands run before the node is destroyed
+	PreDestroy []*Hook `yaml:"pre-destroy,omitempty"`
+}
+
+// Hook is a command run inside the node or on the container host
+type Hook struct {
+	Command string `yaml:"command,omitempty"`
+	// run the command with sh on the container host instead of inside the node
+	Host bool `yaml:"host,omitempty"`
+	// duration the command may run for, e.g. 30s
+	Timeout string `yaml:"timeout,omitempty"`
+	// failure policy of the command, warn (default) logs the failure, fail aborts the deployment or the destroy
+	OnFailure string `yaml:"on-failure,omitempty"`
+}
+
+// VMConfig defines the CPU and memory settings of the VM of the vrnetlab based nodes
+type VMConfig struct {
+	// host CPUs the vCPUs of the VM are pinned to, one vCPU per listed CPU, e.g. 4-7
+	CPUPinning string `yaml:"cpu-pinning,omitempty"`
+	// qemu CPU model of the VM, e.g. host to pass the host CPU through to the VM
+	CPUModel string `yaml:"cpu-model,omitempty"`
+	// memory of the VM backed by the host hugepages, e.g. 8GB
+	Hugepages string `yaml:"hugepages,omitempty"`
+}
+
+// DNSConfig defines the resolver settings of a node replacing the ones inherited from the container host
+type DNSConfig struct {
+	// addresses of the DNS servers
+	Servers []string `yaml:"servers,omitempty"`
+	// DNS search domains
+	Search []string `yaml:"search,omitempty"`
+	// resolver options, e.g. ndots:2
+	Options []string `yaml:"options,omitempty"`
+}
+
+// Validate checks that the DNS servers are IP addresses
+func (d *DNSConfig) Validate() error {
+	for _, s := range d.Servers {
+		if net.ParseIP(s) == nil {
+			return fmt.Errorf("invalid DNS server address %q", s)
+		}
+	}
+	return nil
+}
+
+// ResolvConf returns the resolv.conf contents with the DNS settings
+func (d *DNSConfig) ResolvConf() []byte {
+	b := new(bytes.Buffer)
+	for _, s := range d.Servers {
+		fmt.Fprintf(b, "nameserver %s\n", s)
+	}
+	if len(d.Search) != 0 {
+		fmt.Fprintf(b, "search %s\n", strings.Join(d.Search, " "))
+	}
+	if len(d.Options) != 0 {
+		fmt.Fprintf(b, "options %s\n", strings.Join(d.Options, " "))
+	}
+	return b.Bytes()
+}
+
+// Settings defines the lab-wide settings
+type Settings struct {
+	// backend issuing the lab certificates
+	CertificateAuthority *CABackendConfig `yaml:"certificate-authority,omitempty" json:"certificate-authority,omitempty"`
+	// verification of the node images before deploy
+	ImageVerification *ImageVerificationConfig `yaml:"image-verification,omitempty" json:"image-verification,omitempty"`
+	// registry mirror and credentials the node images are pulled with
+	ImagePull *ImagePullConfig `yaml:"image-pull,omitempty" json:"image-pull,omitempty"`
+	// maximum number of the nodes deployed at once per kind pattern, e.g. vr-*: 4
+	Concurrency map[string]uint `yaml:"concurrency,omitempty" json:"concurrency,omitempty"`
+	// MTU of the veth links which don't set their own MTU
+	LinkMTU int `yaml:"link-mtu,omitempty" json:"link-mtu,omitempty"`
+	// container hosts running the parts of a multi-host lab by their names
+	LabHosts map[string]*LabHost `yaml:"lab-hosts,omitempty" json:"lab-hosts,omitempty"`
+	// VxLAN tunnels of the links between the lab hosts
+	Vxlan *VxlanSettings `yaml:"vxlan,omitempty" json:"vxlan,omitempty"`
+	// directory of the kind plugins, loaded along with the plugins of the default plugins directory
+	PluginsDir string `yaml:"plugins-dir,omitempty" json:"plugins-dir,omitempty"`
+	// template of the container names of the nodes, e.g. {{ .Node }} names the containers after the nodes
+	ContainerName string `yaml:"container-name,omitempty" json:"container-name,omitempty"`
+}
+
+// LabHost is a container host running the nodes of a multi-host lab
+type LabHost struct {
+	// IP address of the host the VxLAN tunnels are terminated at
+	Address string `yaml:"address,omitempty" json:"address,omitempty"`
+}
+
+// VxlanSettings define the VxLAN tunnels of the links between the lab hosts
+type VxlanSettings struct {
+	// VNI of the first link, the links get the VNI of the base plus their index in the topology
+	VNIBase int `yaml:"vni-base,omitempty" json:"vni-base,omitempty"`
+	// UDP port of the tunnels
+	Port int `yaml:"port,omitempty" json:"port,omitempty"`
+}
+
+// GetLabHosts returns the lab hosts of a multi-host lab
+func (s *Settings) GetLabHosts() map[string]*LabHost {
+	if s == nil {
+		return nil
+	}
+	return s.LabHosts
+}
+
+// GetVxlan returns the VxLAN settings of the links between the lab hosts
+func (s *Settings) GetVxlan() *VxlanSettings {
+	if s == nil {
+		return nil
+	}
+	return s.Vxlan
+}
+
+// GetLinkMTU returns the lab-wide MTU of the veth links, 0 when not set
+func (s *Settings) GetLinkMTU() int {
+	if s == nil {
+		return 0
+	}
+	return s.LinkMTU
+}
+
+// GetCertificateAuthority returns the certificate authority backend settings
+func (s *Settings) GetCertificateAuthority() *CABackendConfig {
+	if s == nil {
+		return nil
+	}
+	return s.CertificateAuthority
+}
+
+// GetConcurrency returns the per kind deployment concurrency limits
+func (s *Settings) GetConcurrency() map[string]uint {
+	if s == nil {
+		return nil
+	}
+	return s.Concurrency
+}
+
+// GetImageVerification returns the image verification settings
+func (s *Settings) GetImageVerification() *ImageVerificationConfig {
+	if s == nil {
+		return nil
+	}
+	return s.ImageVerification
+}
+
+// GetImagePull returns the image pull settings
+func (s *Settings) GetImagePull() *ImagePullConfig {
+	if s == nil {
+		return nil
+	}
+	return s.ImagePull
+}
+
+// GetPluginsDir returns the directory of the kind plugins
+func (s *Settings) GetPluginsDir() string {
+	if s == nil {
+		return ""
+	}
+	return s.PluginsDir
+}
+
+// GetContainerName returns the template of the container names of the nodes
+func (s *Settings) GetContainerName() string {
+	if s == nil {
+		return ""
+	}
+	return s.ContainerName
+}
+
+// ImagePullConfig defines the registry mirror and the registry credentials the node images are pulled with
+type ImagePullConfig struct {
+	// registry the Docker Hub images are pulled from instead of docker.io, e.g. mirror.example.com:5000
+	Mirror string `yaml:"mirror,omitempty" json:"mirror,omitempty"`
+	// credentials of the registries by the registry host, e.g. registry.example.com
+	Registries map[string]*RegistryAuth `yaml:"registries,omitempty" json:"registries,omitempty"`
+}
+
+// RegistryAuth is the credentials of a container registry
+type RegistryAuth struct {
+	Username string `yaml:"username,omitempty" json:"username,omitempty"`
+	Password string `yaml:"password,omitempty" json:"password,omitempty"`
+}
+
+// PullOptions are the options of the image pull
+type PullOptions struct {
+	// reference the image is pulled from instead of the image name, e.g. the image in a registry mirror,
+	// the pulled image is stored with the image name
+	Source string
+	// credentials of the registry the image is pulled from
+	Auth *RegistryAuth
+	// Progress is called with the downloaded and the total bytes of the image layers during the pull
+	Progress func(current, total int64)
+}
+
+// ImageVerificationConfig defines the checks the node images have to pass before the lab is deployed
+type ImageVerificationConfig struct {
+	// images have to be pinned by digest
+	RequireDigest bool `yaml:"require-digest,omitempty" json:"require-digest,omitempty"`
+	// cosign public key the image signatures are verified with
+	CosignKey string `yaml:"cosign-key,omitempty" json:"cosign-key,omitempty"`
+}
+
+// CABackendConfig selects the backend issuing the lab certificates
+type CABackendConfig struct {
+	// backend type: local, vault or step-ca
+	Type   string        `yaml:"type,omitempty" json:"type,omitempty"`
+	Vault  *VaultConfig  `yaml:"vault,omitempty" json:"vault,omitempty"`
+	StepCA *StepCAConfig `yaml:"step-ca,omitempty" json:"step-ca,omitempty"`
+}
+
+// GetType returns the backend type, local backend is used by default
+func (c *CABackendConfig) GetType() string {
+	if c == nil || c.Type == "" {
+		return "local"
+	}
+	return c.Type
+}
+
+// VaultConfig defines the Vault PKI secrets engine issuing the lab certificates
+type VaultConfig struct {
+	// Vault server address, VAULT_ADDR env var by default
+	Address string `yaml:"address,omitempty" json:"address,omitempty"`
+	// Vault token, VAULT_TOKEN env var by default
+	Token     string `yaml:"token,omitempty" json:"token,omitempty"`
+	Namespace string `yaml:"namespace,omitempty" json:"namespace,omitempty"`
+	// path the PKI secrets engine is mounted at, pki by default
+	Mount string `yaml:"mount,omitempty" json:"mount,omitempty"`
+	// role the node certificates are issued against
+	Role string `yaml:"role,omitempty" json:"role,omitempty"`
+}
+
+// StepCAConfig defines the step-ca server issuing the lab certificates
+type StepCAConfig struct {
+	URL string `yaml:"url,omitempty" json:"url,omitempty"`
+	// fingerprint of the step-ca root certificate
+	Fingerprint string `yaml:"fingerprint,omitempty" json:"fingerprint,omitempty"`
+	Provisioner string `yaml:"provisioner,omitempty" json:"provisioner,omitempty"`
+	// path to the file with the provisioner password
+	PasswordFile string `yaml:"password-file,omitempty" json:"password-file,omitempty"`
+}
+
+// ControllerConfig defines the external controller the lab nodes are registered with after the deployment
+type ControllerConfig struct {
+	// controller API type: http or gnmic
+	Type string `yaml:"type,omitempty" json:"type,omitempty"`
+	// controller API URL
+	Address string `yaml:"address,omitempty" json:"address,omitempty"`
+	// credentials to authenticate with the controller API, either basic or bearer token
+	Username string `yaml:"username,omitempty" json:"username,omitempty"`
+	Password string `yaml:"password,omitempty" json:"password,omitempty"`
+	Token    string `yaml:"token,omitempty" json:"token,omitempty"`
+	// skip verification of the controller API certificate
+	SkipVerify bool `yaml:"skip-verify,omitempty" json:"skip-verify,omitempty"`
+	// groups of the nodes registered with the controller, all nodes by default
+	Groups []string `yaml:"groups,omitempty" json:"groups,omitempty"`
+}
+
+// NTPConfig defines the lab NTP server container
+type NTPConfig struct {
+	// container image with an NTP server
+	Image string `yaml:"image,omitempty" json:"image,omitempty"`
+	// upstream servers the lab NTP server synchronizes with
+	Servers []string `yaml:"servers,omitempty" json:"servers,omitempty"`
+}
+
+// JumpHostConfig defines the SSH jump host container providing access to the lab nodes
+type JumpHostConfig struct {
+	// container image with an openssh server
+	Image string `yaml:"image,omitempty" json:"image,omitempty"`
+	// host port the ssh server of the jump host is published on
+	Port int `yaml:"port,omitempty" json:"port,omitempty"`
+	// user name to log in to the jump host
+	User string `yaml:"user,omitempty" json:"user,omitempty"`
+	// paths to the public key files authorized to log in, ~/.ssh/*.pub by default
+	Keys []string `yaml:"keys,omitempty" json:"keys,omitempty"`
+}
+
+// QuotaConfig defines the limits of the resources a lab can take from the host
+type QuotaConfig struct {
+	// maximum number of CPUs, fractional values are allowed
+	CPU string `yaml:"cpu,omitempty" json:"cpu,omitempty"`
+	// maximum memory, e.g. 16GB
+	Memory string `yaml:"memory,omitempty" json:"memory,omitempty"`
 }
 
 // NodeConfig is a struct that contains the information of a container element
@@ -81,6 +526,7 @@ type NodeConfig struct {
 	PortSet              nat.PortSet // PortSet define the ports that should be exposed on a container
 	// container networking mode. if set to `host` the host networking will be used for this node, else bridged network
 	NetworkMode          string
+	PidMode              string // container pid namespace mode, if set to `host` the container shares the pid namespace with the host
 	MgmtNet              string // name of the docker network this node is connected to with its first interface
 	MgmtIPv4Address      string
 	MgmtIPv4PrefixLength int
@@ -94,6 +540,8 @@ type NodeConfig struct {
 	NSPath               string   // network namespace path for this node
 	Publish              []string //list of ports to publish with mysocketctl
 	ExtraHosts           []string // Extra /etc/hosts entries for all nodes
+	// names the node is resolved by with the embedded DNS of the management network
+	DNSAliases []string
 	// container labels
 	Labels map[string]string
 	// Slice of pointers to local endpoints
@@ -103,21 +551,75 @@ type NodeConfig struct {
 	// Configured container runtime
 	Runtime string
 	// Resource requirements
-	CPU, RAM         string
+	CPU, RAM string
+	// container memory limit and the CPUs the container is pinned to
+	Memory, CPUSet   string
 	DeploymentStatus string // status that is set by containerlab to indicate deployment stage
 
+	// Interfaces addressing, routes and state
+	Interfaces map[string]*InterfaceConfig
+	// settings applied to the link interfaces of the node
+	InterfaceProfile *InterfaceProfile
+	// add link peers with their interface addresses to /etc/hosts
+	PeerHosts bool
+
+	// node certificate settings
+	Certificate *CertificateConfig
+	// generate TLS certificate and key for the node
+	TLS bool
+	// IANA timezone name
+	Timezone string
+	// DNS servers, search domains and resolver options
+	DNS *DNSConfig
+	// addresses of the NTP servers the node synchronizes the clock with
+	NTPServers []string
+	// settings of the bridge and ovs-bridge nodes
+	Bridge *BridgeConfig
+	// credentials used to log in to the node, e.g. to save its config
+	Credentials *Credentials
+	// readiness checks the deployment waits on
+	WaitFor *WaitFor
+	// impairments of the traffic the node sends over the management interface
+	MgmtNetem *Netem
+	// names of the nodes deployed and ready before the node is deployed
+	DependsOn []string
+	// routes installed in the network namespace of the node
+	StaticRoutes []*StaticRoute
+	// gateway replacing the default gateway of the management network, none removes the default routes
+	DefaultGateway string
+	// commands run on the container host when the node becomes ready and when its container exits
+	OnReady, OnExit string
+	// Dockerfile the node image is built from, the Image is the name of the built image
+	Build *BuildConfig
+	// running container the node is imported from
+	Import *ImportConfig
+	// tmpfs mounts by their container paths with their mount options
+	Tmpfs map[string]string
+	// commands run at the lifecycle points of the node
+	Hooks *Hooks
+	// CPU and memory settings of the VM of the vrnetlab based nodes
+	VM *VMConfig
+	// capabilities added to and dropped from the container in the CAP_ prefixed form,
+	// the container runs unprivileged when any is set
+	CapAdd, CapDrop []string
+	// time the node is given to shut down gracefully on destroy, 0 if unset
+	StopTimeout time.Duration
 	// Extras
 	Extras *Extras // Extra node parameters
 }
 
+// ShouldGenerateConfig returns true if the config file by the dst path is to be (re)generated.
+// If the config file is already present in the node dir
+// we do not regenerate the config unless EnforceStartupConfig is explicitly set to true and startup-config points to a file
+// this will persist the changes that users make to a running config when booted from some startup config
+func (node *NodeConfig) ShouldGenerateConfig(dst string) bool {
+	return !utils.FileExists(dst) || (node.StartupConfig != "" && node.EnforceStartupConfig)
+}
+
 // GenerateConfig generates configuration for the nodes
 // out of the template based on the node configuration and saves the result to dst
 func (node *NodeConfig) GenerateConfig(dst, templ string) error {
-
-	// If the config file is already present in the node dir
-	// we do not regenerate the config unless EnforceStartupConfig is explicitly set to true and startup-config points to a file
-	// this will persist the changes that users make to a running config when booted from some startup config
-	if utils.FileExists(dst) && (node.StartupConfig == "" || !node.EnforceStartupConfig) {
+	if !node.ShouldGenerateConfig(dst) {
 		log.Infof("config file '%s' for node '%s' already exists and will not be generated/reset", dst, node.ShortName)
 		return nil
 	} else if node.EnforceStartupConfig {
@@ -170,11 +672,14 @@ type GenericContainer struct {
 	ID              string
 	ShortID         string // trimmed ID for display purposes
 	Image           string
+	ImageID         string // ID of the container image, empty for the runtimes without image IDs
 	State           string
 	Status          string
 	Labels          map[string]string
 	Pid             int
+	Created         time.Time // creation time of the container
 	NetworkSettings *GenericMgmtIPs
+	Ports           []*GenericPortBinding
 }
 
 type GenericMgmtIPs struct {
@@ -185,6 +690,24 @@ type GenericMgmtIPs struct {
 	IPv6pLen int
 }
 
+// GenericPortBinding represents a port published by a container on the host
+type GenericPortBinding struct {
+	HostIP        string `json:"host_ip,omitempty"`
+	HostPort      int    `json:"host_port,omitempty"`
+	ContainerPort int    `json:"container_port,omitempty"`
+	Protocol      string `json:"protocol,omitempty"`
+}
+
+// String returns the port binding in the docker notation, e.g. 0.0.0.0:8080->80/tcp
+// IPv6 host addresses are enclosed in brackets
+func (p *GenericPortBinding) String() string {
+	hostIP := p.HostIP
+	if strings.Contains(hostIP, ":") {
+		hostIP = "[" + hostIP + "]"
+	}
+	return fmt.Sprintf("%s:%d->%d/%s", hostIP, p.HostPort, p.ContainerPort, p.Protocol)
+}
+
 type GenericFilter struct {
 	// defined by now "label"
 	FilterType string
@@ -223,6 +746,12 @@ func FilterFromLabelStrings(labels []string) []*GenericFilter {
 // after they started
 type ConfigDispatcher struct {
 	Vars map[string]interface{} `yaml:"vars,omitempty"`
+	// transport the config is pushed to the node with, e.g. gnmi or netconf, the kind transport is used when unset
+	Transport string `yaml:"transport,omitempty"`
+	// config snippets pushed to the node once it is deployed
+	Snippets []string `yaml:"snippets,omitempty"`
+	// template files rendered with the vars and pushed to the node after the snippets
+	Templates []string `yaml:"templates,omitempty"`
 }
 
 func (cd *ConfigDispatcher) GetVars() map[string]interface{} {
@@ -232,7 +761,72 @@ func (cd *ConfigDispatcher) GetVars() map[string]interface{} {
 	return cd.Vars
 }
 
+func (cd *ConfigDispatcher) GetTransport() string {
+	if cd == nil {
+		return ""
+	}
+	return cd.Transport
+}
+
+func (cd *ConfigDispatcher) GetSnippets() []string {
+	if cd == nil {
+		return nil
+	}
+	return cd.Snippets
+}
+
+func (cd *ConfigDispatcher) GetTemplates() []string {
+	if cd == nil {
+		return nil
+	}
+	return cd.Templates
+}
+
+// HasPush returns true if the config dispatcher has the snippets or templates to push to the node
+func (cd *ConfigDispatcher) HasPush() bool {
+	return len(cd.GetSnippets()) != 0 || len(cd.GetTemplates()) != 0
+}
+
 // Extras contains extra node parameters which are not entitled to be part of a generic node config
 type Extras struct {
 	SRLAgents []string `yaml:"srl-agents,omitempty"` // Nokia SR Linux agents. As of now just the agents spec files can be provided here
+	// Nokia SR Linux config fragments merged into the generated startup config
+	SRLBootstrap *SRLBootstrap `yaml:"srl-bootstrap,omitempty"`
+	// settings passed to the VM launcher of the generic_vm nodes
+	GenericVM *GenericVM `yaml:"generic-vm,omitempty"`
+}
+
+// GetGenericVM returns the generic_vm settings
+func (e *Extras) GetGenericVM() *GenericVM {
+	if e == nil {
+		return nil
+	}
+	return e.GenericVM
+}
+
+// GenericVM holds the settings of the VM started by a vrnetlab-like launcher of the generic_vm nodes
+type GenericVM struct {
+	// extra arguments appended to the qemu command line
+	QemuArgs string `yaml:"qemu-args,omitempty"`
+	// number of the data NICs of the VM
+	NICs int `yaml:"nics,omitempty"`
+	// telnet port of the VM serial console
+	ConsolePort int `yaml:"console-port,omitempty"`
+}
+
+// SRLBootstrap holds the SR Linux banners, users and CLI aliases
+type SRLBootstrap struct {
+	// banner displayed before a user logs in
+	LoginBanner string `yaml:"login-banner,omitempty"`
+	// message of the day displayed after a user logs in
+	MOTD  string     `yaml:"motd,omitempty"`
+	Users []*SRLUser `yaml:"users,omitempty"`
+	// CLI aliases, alias name -> command
+	Aliases map[string]string `yaml:"aliases,omitempty"`
+}
+
+// SRLUser is a local user of SR Linux node
+type SRLUser struct {
+	Name     string `yaml:"name"`
+	Password string `yaml:"password"`
 }
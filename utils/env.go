@@ -5,10 +5,18 @@
 package utils
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
 )
 
+// envNameRe matches the valid env var names of the env files
+var envNameRe = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_.]*$`)
+
 // convertEnvs convert env variables passed as a map to a list of them
 func ConvertEnvs(m map[string]string) []string {
 	s := make([]string, 0, len(m))
@@ -85,3 +93,45 @@ func StringInSlice(slice []string, val string) (int, bool) {
 	}
 	return -1, false
 }
+
+// ParseEnvFile parses the env file in the dotenv format: KEY=VALUE lines with the optional export keyword.
+// The values are unquoted, the double quoted values support the escape sequences, the single quoted ones are literal.
+// The empty lines and the lines starting with # are skipped, as well as the comments after the unquoted values
+func ParseEnvFile(r io.Reader) (map[string]string, error) {
+	env := map[string]string{}
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		l := strings.TrimSpace(s.Text())
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		l = strings.TrimSpace(strings.TrimPrefix(l, "export "))
+		i := strings.Index(l, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", n)
+		}
+		k, v := strings.TrimSpace(l[:i]), strings.TrimSpace(l[i+1:])
+		if !envNameRe.MatchString(k) {
+			return nil, fmt.Errorf("line %d: invalid variable name %q", n, k)
+		}
+		switch {
+		case strings.HasPrefix(v, `"`):
+			uv, err := strconv.Unquote(v)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid double quoted value of %s", n, k)
+			}
+			v = uv
+		case strings.HasPrefix(v, "'"):
+			if len(v) < 2 || !strings.HasSuffix(v, "'") {
+				return nil, fmt.Errorf("line %d: invalid single quoted value of %s", n, k)
+			}
+			v = v[1 : len(v)-1]
+		default:
+			if j := strings.Index(v, " #"); j >= 0 {
+				v = strings.TrimSpace(v[:j])
+			}
+		}
+		env[k] = v
+	}
+	return env, s.Err()
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -185,3 +186,39 @@ func TestMergeMapsLists(t *testing.T) {
 	assert(t, MergeMaps(d1, nil), d1)
 	assert(t, MergeMaps(d1, d2), d2)
 }
+
+func TestParseEnvFile(t *testing.T) {
+	tests := map[string]struct {
+		in      string
+		want    map[string]string
+		wantErr bool
+	}{
+		"plain": {
+			in:   "# comment\n\nA=1\nexport B = two\nC=\n",
+			want: map[string]string{"A": "1", "B": "two", "C": ""},
+		},
+		"quoted": {
+			in:   "A=\"hello\\nworld\"\nB='p@ss#word'\nC=\"a=b\"\n",
+			want: map[string]string{"A": "hello\nworld", "B": "p@ss#word", "C": "a=b"},
+		},
+		"inline-comment": {
+			in:   "A=value # comment\nB=a#b\n",
+			want: map[string]string{"A": "value", "B": "a#b"},
+		},
+		"missing-equal":       {in: "A\n", wantErr: true},
+		"invalid-name":        {in: "1A=x\n", wantErr: true},
+		"unterminated-double": {in: "A=\"x\n", wantErr: true},
+		"unterminated-single": {in: "A='x\n", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseEnvFile(strings.NewReader(tc.in))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseEnvFile() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !tc.wantErr {
+				assert(t, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// hostRoot is the path where the root filesystem of the container host is mounted
+// when containerlab runs inside a container, empty when containerlab runs on the host
+var hostRoot string
+
+// SetHostRoot sets the path where the root filesystem of the container host is mounted,
+// the paths passed to the container runtime and read from the host are mapped using this path
+func SetHostRoot(p string) error {
+	if p == "" {
+		hostRoot = ""
+		return nil
+	}
+	p, err := filepath.Abs(p)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(p); err != nil {
+		return err
+	}
+	hostRoot = filepath.Clean(p)
+	if hostRoot == "/" {
+		hostRoot = ""
+	}
+	return nil
+}
+
+// HostRoot returns the path where the root filesystem of the container host is mounted
+func HostRoot() string {
+	return hostRoot
+}
+
+// HostPath maps the local path to the path on the container host.
+// The paths outside of the host root are returned as is
+func HostPath(p string) string {
+	if hostRoot == "" || !filepath.IsAbs(p) {
+		return p
+	}
+	p = filepath.Clean(p)
+	if p == hostRoot {
+		return "/"
+	}
+	if strings.HasPrefix(p, hostRoot+"/") {
+		return strings.TrimPrefix(p, hostRoot)
+	}
+	return p
+}
+
+// LocalPath maps the path on the container host to the local path
+func LocalPath(p string) string {
+	if hostRoot == "" || !filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(hostRoot, p)
+}
+
+// IsHostVisible returns false when the local path is outside of the host root
+// and thus is not visible to the container runtime
+func IsHostVisible(p string) bool {
+	if hostRoot == "" || !filepath.IsAbs(p) {
+		return true
+	}
+	p = filepath.Clean(p)
+	return p == hostRoot || strings.HasPrefix(p, hostRoot+"/")
+}
+
+// HostBind maps the host path of the bind in the /hostpath:/remotepath(:options) format
+// to the path on the container host
+func HostBind(bind string) string {
+	elems := strings.SplitN(bind, ":", 2)
+	elems[0] = HostPath(elems[0])
+	return strings.Join(elems, ":")
+}
+
+// containerCgroups are the markers of the container runtimes found in the cgroups of the processes
+var containerCgroups = []string{"docker", "kubepods", "containerd", "libpod", "lxc"}
+
+// InContainer returns true when containerlab runs inside a container
+func InContainer() bool {
+	for _, f := range []string{"/.dockerenv", "/run/.containerenv"} {
+		if _, err := os.Stat(f); err == nil {
+			return true
+		}
+	}
+	if os.Getenv("container") != "" {
+		return true
+	}
+	b, err := ioutil.ReadFile("/proc/1/cgroup")
+	if err != nil {
+		return false
+	}
+	for _, m := range containerCgroups {
+		if strings.Contains(string(b), m) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestHostRootPaths(t *testing.T) {
+	root, err := ioutil.TempDir("", "host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	if err := SetHostRoot(root); err != nil {
+		t.Fatal(err)
+	}
+	defer SetHostRoot("")
+
+	tests := map[string]struct {
+		f    func(string) string
+		in   string
+		want string
+	}{
+		"host_path":          {f: HostPath, in: root + "/home/user/lab", want: "/home/user/lab"},
+		"host_path_root":     {f: HostPath, in: root, want: "/"},
+		"host_path_outside":  {f: HostPath, in: "/tmp/lab", want: "/tmp/lab"},
+		"host_path_prefix":   {f: HostPath, in: root + "2/lab", want: root + "2/lab"},
+		"host_path_relative": {f: HostPath, in: "lab/dir", want: "lab/dir"},
+		"local_path":         {f: LocalPath, in: "/proc/100/ns/net", want: root + "/proc/100/ns/net"},
+		"host_bind":          {f: HostBind, in: root + "/lab/config:/etc/config:ro", want: "/lab/config:/etc/config:ro"},
+		"host_bind_outside":  {f: HostBind, in: "/lab/config:/etc/config", want: "/lab/config:/etc/config"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tc.f(tc.in); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+	if IsHostVisible("/tmp/lab") {
+		t.Error("expected path outside of the host root to be not visible")
+	}
+	if !IsHostVisible(root + "/tmp/lab") {
+		t.Error("expected path inside of the host root to be visible")
+	}
+}
+
+func TestNoHostRoot(t *testing.T) {
+	if err := SetHostRoot("/"); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{"/home/user/lab", "/proc/1/ns/net"} {
+		if HostPath(p) != p || LocalPath(p) != p || !IsHostVisible(p) {
+			t.Errorf("expected %q to be not mapped", p)
+		}
+	}
+	if err := SetHostRoot("/non/existing/host/root"); err == nil {
+		t.Error("expected error for non existing host root")
+	}
+}
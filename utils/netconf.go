@@ -5,7 +5,10 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/scrapli/scrapligo/driver/base"
 	"github.com/scrapli/scrapligo/netconf"
@@ -40,3 +43,94 @@ func SaveCfgViaNetconf(addr, username, password string) error {
 
 	return nil
 }
+
+// GetCfgViaNetconf retrieves the running config by means of invoking a netconf rpc <get-config>.
+// this method is used on the network elements that have no startup datastore to copy the running config to,
+// timeout bounds the netconf operations, as the config retrieval of the large VMs can take a while
+func GetCfgViaNetconf(addr, username, password string, timeout time.Duration) (string, error) {
+	d, err := netconf.NewNetconfDriver(
+		addr,
+		base.WithAuthStrictKey(false),
+		base.WithAuthUsername(username),
+		base.WithAuthPassword(password),
+		base.WithTransportType(transport.StandardTransportName),
+		base.WithTimeoutOps(timeout),
+		base.WithTimeoutSocket(timeout),
+	)
+	if err != nil {
+		return "", fmt.Errorf("could not create netconf driver for %s: %+v", addr, err)
+	}
+
+	err = d.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to open netconf driver for %s: %+v", addr, err)
+	}
+	defer d.Close()
+
+	resp, err := d.GetConfig("running")
+	if err != nil {
+		return "", fmt.Errorf("%s: Could not get config via Netconf: %+v", addr, err)
+	}
+	if resp.Failed != nil {
+		return "", fmt.Errorf("%s: get-config rpc failed: %+v", addr, resp.Failed)
+	}
+
+	return resp.Result, nil
+}
+
+// ErrNetconfRPC is wrapped into the errors reported by the netconf server in the rpc replies
+var ErrNetconfRPC = errors.New("netconf rpc error")
+
+// EditCfgViaNetconf edits the datastore with the configs by means of invoking the netconf rpc <edit-config> per config.
+// The candidate datastore is committed after the configs are applied, the running datastore is edited when datastore is empty.
+// The configs are the contents of the <config> element, the element is added unless the config starts with it
+func EditCfgViaNetconf(addr, username, password, datastore string, configs []string) error {
+	if datastore == "" {
+		datastore = "running"
+	}
+	d, err := netconf.NewNetconfDriver(
+		addr,
+		base.WithAuthStrictKey(false),
+		base.WithAuthUsername(username),
+		base.WithAuthPassword(password),
+		base.WithTransportType(transport.StandardTransportName),
+	)
+	if err != nil {
+		return fmt.Errorf("could not create netconf driver for %s: %+v", addr, err)
+	}
+
+	err = d.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open netconf driver for %s: %+v", addr, err)
+	}
+	defer d.Close()
+
+	for _, cfg := range configs {
+		cfg = strings.TrimSpace(cfg)
+		if !strings.HasPrefix(cfg, "<config") {
+			cfg = "<config>" + cfg + "</config>"
+		}
+		resp, err := d.EditConfig(datastore, cfg)
+		if err != nil {
+			return fmt.Errorf("%s: could not edit config via Netconf: %+v", addr, err)
+		}
+		if resp.Failed != nil {
+			if datastore == "candidate" {
+				_, _ = d.Discard()
+			}
+			return fmt.Errorf("%w: %s: edit-config rpc failed: %v", ErrNetconfRPC, addr, resp.Result)
+		}
+	}
+	if datastore != "candidate" {
+		return nil
+	}
+	resp, err := d.Commit()
+	if err != nil {
+		return fmt.Errorf("%s: could not commit config via Netconf: %+v", addr, err)
+	}
+	if resp.Failed != nil {
+		_, _ = d.Discard()
+		return fmt.Errorf("%w: %s: commit rpc failed: %v", ErrNetconfRPC, addr, resp.Result)
+	}
+	return nil
+}
@@ -7,6 +7,8 @@ package utils
 import (
 	"crypto/rand"
 	"fmt"
+	"math/big"
+	"net"
 	"os"
 
 	log "github.com/sirupsen/logrus"
@@ -28,12 +30,14 @@ func BridgeByName(name string) (*netlink.Bridge, error) {
 // linkContainerNS creates a symlink for containers network namespace
 // so that it can be managed by iproute2 utility
 func LinkContainerNS(nspath, containerName string) error {
-	CreateDirectory("/run/netns/", 0755)
-	dst := "/run/netns/" + containerName
+	// the symlink is created in the host netns dir and points to the host path
+	// so that it is usable by the host tools when containerlab runs inside a container
+	CreateDirectory(LocalPath("/run/netns/"), 0755)
+	dst := LocalPath("/run/netns/" + containerName)
 	if _, err := os.Lstat(dst); err == nil {
 		os.Remove(dst)
 	}
-	err := os.Symlink(nspath, dst)
+	err := os.Symlink(HostPath(nspath), dst)
 	if err != nil {
 		return err
 	}
@@ -88,10 +92,31 @@ func GenMac(oui string) string {
 // deleteNetnsSymlink deletes a network namespace and removes the symlink created by linkContainerNS func
 func DeleteNetnsSymlink(n string) error {
 	log.Debug("Deleting netns symlink: ", n)
-	sl := fmt.Sprintf("/run/netns/%s", n)
+	sl := LocalPath(fmt.Sprintf("/run/netns/%s", n))
 	err := os.Remove(sl)
 	if err != nil {
 		log.Debug("Failed to delete netns symlink by path:", sl)
 	}
 	return nil
 }
+
+// CIDRHost returns the n-th address of the IP prefix, e.g. the address 5 of 10.0.0.0/24 is 10.0.0.5
+func CIDRHost(prefix string, n int) (string, error) {
+	_, ipNet, err := net.ParseCIDR(prefix)
+	if err != nil {
+		return "", err
+	}
+	ip := ipNet.IP
+	v := new(big.Int).SetBytes(ip)
+	v.Add(v, big.NewInt(int64(n)))
+	b := v.Bytes()
+	if len(b) > len(ip) || n < 0 {
+		return "", fmt.Errorf("address %d is out of the prefix %s", n, prefix)
+	}
+	host := make(net.IP, len(ip))
+	copy(host[len(ip)-len(b):], b)
+	if !ipNet.Contains(host) {
+		return "", fmt.Errorf("address %d is out of the prefix %s", n, prefix)
+	}
+	return host.String(), nil
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/scrapli/scrapligo/driver/base"
@@ -68,3 +69,34 @@ func SpawnCLIviaExec(platform, contName string) (*network.Driver, error) {
 
 	return d, err
 }
+
+// SaveCfgViaSSHCLI saves the running config by sending the save command to the CLI over SSH.
+// The command fails if its output contains any of the failedWhenContains strings.
+// this method is used on the network elements that can't save the config via netconf
+func SaveCfgViaSSHCLI(addr, username, password, cmd string, failedWhenContains []string) error {
+	d, err := base.NewDriver(
+		addr,
+		base.WithAuthStrictKey(false),
+		base.WithAuthUsername(username),
+		base.WithAuthPassword(password),
+		base.WithTransportType(transport.StandardTransportName),
+		base.WithFailedWhenContains(failedWhenContains),
+	)
+	if err != nil {
+		return fmt.Errorf("could not create ssh driver for %s: %+v", addr, err)
+	}
+
+	if err := d.Open(); err != nil {
+		return fmt.Errorf("failed to open ssh session to %s: %+v", addr, err)
+	}
+	defer d.Close()
+
+	r, err := d.SendCommand(cmd)
+	if err != nil {
+		return fmt.Errorf("%s: could not send %q command: %+v", addr, cmd, err)
+	}
+	if r.Failed != nil {
+		return fmt.Errorf("%s: %q command failed: %s", addr, cmd, r.Result)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package utils
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MergeYAML updates the dst YAML document with the content of the src document
+// while keeping the comments and the keys order of dst.
+// The src document is authoritative: keys missing in src are removed from dst,
+// keys missing in dst are appended after the existing ones.
+func MergeYAML(dst, src []byte) ([]byte, error) {
+	var dstNode, srcNode yaml.Node
+	if err := yaml.Unmarshal(dst, &dstNode); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML document: %v", err)
+	}
+	if err := yaml.Unmarshal(src, &srcNode); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML document: %v", err)
+	}
+	// an empty dst document has no content to keep
+	if dstNode.Kind == 0 {
+		return src, nil
+	}
+
+	mergeYAMLNodes(&dstNode, &srcNode)
+
+	buf := new(bytes.Buffer)
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&dstNode); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// mergeYAMLNodes makes dst node hold the value of src node, reusing the dst nodes
+// where possible so that their comments are not lost
+func mergeYAMLNodes(dst, src *yaml.Node) {
+	if dst.Kind != src.Kind {
+		replaceYAMLNode(dst, src)
+		return
+	}
+
+	switch dst.Kind {
+	case yaml.DocumentNode:
+		if len(dst.Content) == 1 && len(src.Content) == 1 {
+			mergeYAMLNodes(dst.Content[0], src.Content[0])
+			return
+		}
+		dst.Content = src.Content
+	case yaml.MappingNode:
+		mergeYAMLMappings(dst, src)
+	case yaml.SequenceNode:
+		// sequence items are matched by their position
+		for i := range src.Content {
+			if i < len(dst.Content) {
+				mergeYAMLNodes(dst.Content[i], src.Content[i])
+				continue
+			}
+			dst.Content = append(dst.Content, src.Content[i])
+		}
+		if len(dst.Content) > len(src.Content) {
+			dst.Content = dst.Content[:len(src.Content)]
+		}
+	case yaml.ScalarNode:
+		if dst.Value != src.Value || dst.Tag != src.Tag {
+			dst.Value = src.Value
+			dst.Tag = src.Tag
+			dst.Style = src.Style
+		}
+	default:
+		replaceYAMLNode(dst, src)
+	}
+}
+
+// mergeYAMLMappings merges src mapping node into dst mapping node,
+// mapping nodes content is a list of key and value nodes pairs
+func mergeYAMLMappings(dst, src *yaml.Node) {
+	srcKeys := map[string]struct{}{}
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		k, v := src.Content[i], src.Content[i+1]
+		srcKeys[k.Value] = struct{}{}
+		if dv := yamlMappingValue(dst, k.Value); dv != nil {
+			mergeYAMLNodes(dv, v)
+			continue
+		}
+		dst.Content = append(dst.Content, k, v)
+	}
+
+	content := dst.Content[:0]
+	for i := 0; i+1 < len(dst.Content); i += 2 {
+		if _, ok := srcKeys[dst.Content[i].Value]; ok {
+			content = append(content, dst.Content[i], dst.Content[i+1])
+		}
+	}
+	dst.Content = content
+}
+
+// yamlMappingValue returns the value node of a key in a mapping node
+func yamlMappingValue(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// replaceYAMLNode replaces dst node with src keeping the comments of dst
+func replaceYAMLNode(dst, src *yaml.Node) {
+	head, line, foot := dst.HeadComment, dst.LineComment, dst.FootComment
+	*dst = *src
+	if dst.HeadComment == "" {
+		dst.HeadComment = head
+	}
+	if dst.LineComment == "" {
+		dst.LineComment = line
+	}
+	if dst.FootComment == "" {
+		dst.FootComment = foot
+	}
+}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestMergeYAML(t *testing.T) {
+	tests := map[string]struct {
+		dst  string
+		src  string
+		want string
+	}{
+		"comments-preserved": {
+			dst: `# lab comment
+name: lab1
+topology:
+  nodes:
+    # spine nodes
+    spine1:
+      kind: srl # kind comment
+`,
+			src: `name: lab1
+topology:
+  nodes:
+    spine1:
+      kind: ceos
+`,
+			want: `# lab comment
+name: lab1
+topology:
+  nodes:
+    # spine nodes
+    spine1:
+      kind: ceos # kind comment
+`,
+		},
+		"keys-added-and-removed": {
+			dst: `topology:
+  nodes:
+    leaf1:
+      kind: srl
+    leaf2:
+      kind: srl
+  links:
+    - endpoints: ["leaf1:e1-1", "leaf2:e1-1"]
+`,
+			src: `topology:
+  nodes:
+    leaf1:
+      kind: srl
+    leaf3:
+      kind: srl
+`,
+			want: `topology:
+  nodes:
+    leaf1:
+      kind: srl
+    leaf3:
+      kind: srl
+`,
+		},
+		"empty-dst": {
+			dst:  "",
+			src:  "name: lab1\n",
+			want: "name: lab1\n",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := MergeYAML([]byte(tc.dst), []byte(tc.src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert(t, string(got), tc.want)
+		})
+	}
+}